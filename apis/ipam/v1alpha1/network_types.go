@@ -18,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 )
 
@@ -45,6 +46,17 @@ type NetworkSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="PreAllocated field is immutable"
 	PreAllocated uint32 `json:"preAllocated"`
+	// AllocationPolicy is the policy used by the IPAM to choose the remapped CIDR, in case the desired one is not available.
+	// Defaults to the policy configured in the IPAM.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=FirstFit;BestFit;Deterministic
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="AllocationPolicy field is immutable"
+	AllocationPolicy string `json:"allocationPolicy,omitempty"`
+	// ReservedFor is the ID of the remote cluster the CIDR is reserved to. If set, the CIDR is not allocated,
+	// but the IPAM allocates the networks of the given cluster within it, and the ones of the other clusters outside of it.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ReservedFor field is immutable"
+	ReservedFor liqov1beta1.ClusterID `json:"reservedFor,omitempty"`
 }
 
 // NetworkStatus defines the observed state of Network.
@@ -0,0 +1,119 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+)
+
+var (
+	// PoolKind is the kind name used to register the Pool CRD.
+	PoolKind = "Pool"
+
+	// PoolResource is the resource name used to register the Pool CRD.
+	PoolResource = "pools"
+
+	// PoolGroupVersionResource is the group version resource used to register the Pool CRD.
+	PoolGroupVersionResource = SchemeGroupVersion.WithResource(PoolResource)
+
+	// PoolGroupResource is the group resource used to register the Pool CRD.
+	PoolGroupResource = schema.GroupResource{Group: SchemeGroupVersion.Group, Resource: PoolResource}
+)
+
+// PoolPhase is the phase of a Pool.
+type PoolPhase string
+
+const (
+	// PoolPhaseActive indicates that networks are allocated from the pool.
+	PoolPhaseActive PoolPhase = "Active"
+	// PoolPhaseDraining indicates that no new network is allocated from the pool, while the existing ones are preserved.
+	PoolPhaseDraining PoolPhase = "Draining"
+	// PoolPhaseTerminating indicates that the pool is being deleted, and it will be removed once all its networks are released.
+	PoolPhaseTerminating PoolPhase = "Terminating"
+	// PoolPhaseInvalid indicates that the pool cannot be used by the IPAM (e.g., it overlaps with another pool).
+	PoolPhaseInvalid PoolPhase = "Invalid"
+)
+
+// PoolSpec defines the desired state of Pool.
+type PoolSpec struct {
+	// CIDR is the address range networks are allocated from.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="CIDR field is immutable"
+	CIDR networkingv1beta1.CIDR `json:"cidr"`
+	// Draining prevents the allocation of new networks from the pool, while preserving the existing ones.
+	// +kubebuilder:validation:Optional
+	Draining bool `json:"draining,omitempty"`
+}
+
+// PoolConflict describes a cluster network overlapping with a pool.
+type PoolConflict struct {
+	// Name is the name of the conflicting Network.
+	Name string `json:"name"`
+	// Namespace is the namespace of the conflicting Network.
+	Namespace string `json:"namespace"`
+	// Type is the type of the conflicting Network (e.g., pod-cidr, service-cidr).
+	Type string `json:"type"`
+	// CIDR is the CIDR of the conflicting Network.
+	CIDR networkingv1beta1.CIDR `json:"cidr"`
+}
+
+// PoolStatus defines the observed state of Pool.
+type PoolStatus struct {
+	// Phase is the phase of the pool.
+	Phase PoolPhase `json:"phase,omitempty"`
+	// Message is a human-readable message describing the phase of the pool.
+	Message string `json:"message,omitempty"`
+	// Networks is the number of networks allocated from the pool.
+	Networks int32 `json:"networks,omitempty"`
+	// IPs is the number of IPs allocated from the networks of the pool.
+	IPs int32 `json:"ips,omitempty"`
+	// Utilization is the percentage of the pool address space covered by allocated networks.
+	Utilization string `json:"utilization,omitempty"`
+	// Conflicts lists the pod and service CIDRs partially overlapping with the pool, which cannot be reserved by the IPAM.
+	Conflicts []PoolConflict `json:"conflicts,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=liqo
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CIDR",type=string,JSONPath=`.spec.cidr`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Networks",type=integer,JSONPath=`.status.networks`
+// +kubebuilder:printcolumn:name="Utilization",type=string,JSONPath=`.status.utilization`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Pool is the Schema for the Pool API, representing an address range managed by the IPAM at runtime.
+type Pool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PoolSpec   `json:"spec"`
+	Status PoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PoolList contains a list of Pool.
+type PoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Pool{}, &PoolList{})
+}
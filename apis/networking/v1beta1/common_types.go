@@ -14,6 +14,8 @@
 
 package v1beta1
 
+import "k8s.io/apimachinery/pkg/api/resource"
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -25,10 +27,47 @@ func (c CIDR) String() string {
 	return string(c)
 }
 
-// IP defines a syntax validated IP.
-// +kubebuilder:validation:Format=ipv4
+// IP defines an IPv4 or IPv6 address.
+// The syntax is validated through a CEL rule, since the "ipv4" and "ipv6" OpenAPI formats are mutually exclusive.
+// +kubebuilder:validation:MaxLength=45
+// +kubebuilder:validation:XValidation:rule="isIP(self)",message="must be a valid IP address"
 type IP string
 
 func (i IP) String() string {
 	return string(i)
 }
+
+// BandwidthLimit defines the limits enforced on the traffic sent through the tunnel towards the remote cluster.
+type BandwidthLimit struct {
+	// Rate is the maximum rate of the traffic sent through the tunnel, in bits per second (e.g., 100M).
+	Rate resource.Quantity `json:"rate"`
+	// Burst is the amount of traffic, in bytes, which can be sent at a rate higher than the configured one (e.g., 64Ki).
+	// If not set, it is computed according to the rate.
+	// +optional
+	Burst *resource.Quantity `json:"burst,omitempty"`
+	// Classes are the traffic classes, identified by the DSCP values of the packets, which are guaranteed a share of the rate.
+	// The traffic not belonging to any class is served with the lowest priority, and with the rate not guaranteed to the classes.
+	// +optional
+	Classes []BandwidthClass `json:"classes,omitempty"`
+}
+
+// BandwidthClass defines a traffic class, which is guaranteed a share of the rate, and can borrow the unused one.
+type BandwidthClass struct {
+	// Name of the class, used to identify it in the metrics.
+	Name string `json:"name"`
+	// DSCP values of the packets belonging to the class.
+	// +kubebuilder:validation:MinItems=1
+	DSCP []DSCP `json:"dscp"`
+	// Rate is the rate guaranteed to the class, in bits per second (e.g., 10M).
+	Rate resource.Quantity `json:"rate"`
+	// Priority of the class, in borrowing the unused rate: lower values are served first.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +kubebuilder:default=0
+	Priority int `json:"priority,omitempty"`
+}
+
+// DSCP defines a Differentiated Services Code Point value.
+// +kubebuilder:validation:Minimum=0
+// +kubebuilder:validation:Maximum=63
+type DSCP uint8
@@ -17,6 +17,8 @@ package v1beta1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -48,12 +50,26 @@ type ClusterConfig struct {
 	CIDR ClusterConfigCIDR `json:"cidr,omitempty"`
 }
 
+// TransitConfig defines the transit routing configuration, to reach clusters without a direct gateway connection
+// through an intermediate peered cluster (hub-and-spoke topology).
+type TransitConfig struct {
+	// Via is the ID of the peered cluster the remote cluster is reached through, as no direct gateway connection exists.
+	// In this case, the remote CIDRs are the ones the transit cluster remapped the remote cluster to.
+	Via liqov1beta1.ClusterID `json:"via,omitempty"`
+	// Forward enables the forwarding of the traffic between the remote cluster and the other remote clusters
+	// with the forwarding enabled, making the local cluster act as a transit hub.
+	Forward bool `json:"forward,omitempty"`
+}
+
 // ConfigurationSpec defines the desired state of Configuration.
 type ConfigurationSpec struct {
 	// Local network configuration (the cluster where the resource is created).
 	Local *ClusterConfig `json:"local,omitempty"`
 	// Remote network configuration (the other cluster).
 	Remote ClusterConfig `json:"remote,omitempty"`
+	// Transit configures the transit routing towards the remote cluster.
+	// +kubebuilder:validation:XValidation:rule="!(has(self.via) && has(self.forward) && self.forward)",message="a cluster reached through a transit cluster cannot forward the traffic"
+	Transit *TransitConfig `json:"transit,omitempty"`
 }
 
 // ConfigurationStatus defines the observed state of Configuration.
@@ -71,6 +87,7 @@ type ConfigurationStatus struct {
 // +kubebuilder:printcolumn:name="Desired External CIDR",type=string,priority=1,JSONPath=`.spec.remote.cidr.external`
 // +kubebuilder:printcolumn:name="Remapped External CIDR",type=string,priority=1,JSONPath=`.status.remote.cidr.external`
 // +kubebuilder:printcolumn:name="ClusterID",type=string,priority=1,JSONPath=`.metadata.labels.liqo\.io/remote-cluster-id`
+// +kubebuilder:printcolumn:name="Via",type=string,priority=1,JSONPath=`.spec.transit.via`
 
 // Configuration contains the network configuration of a pair of clusters,
 // including the local and the remote pod and external CIDRs and how the where remapped.
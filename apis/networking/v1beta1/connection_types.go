@@ -72,12 +72,66 @@ type ConnectionLatency struct {
 	Timestamp metav1.Time `json:"timestamp,omitempty"`
 }
 
+// ConnectionQuality contains the link-quality measurements computed over the most recent pings.
+type ConnectionQuality struct {
+	// Samples is the number of pings the measurements refer to.
+	Samples int `json:"samples,omitempty"`
+	// PacketLoss is the percentage of lost pings.
+	PacketLoss string `json:"packetLoss,omitempty"`
+	// Jitter is the mean variation of the latency between consecutive pings.
+	Jitter string `json:"jitter,omitempty"`
+	// LatencyP50 is the 50th percentile of the latency.
+	LatencyP50 string `json:"latencyP50,omitempty"`
+	// LatencyP90 is the 90th percentile of the latency.
+	LatencyP90 string `json:"latencyP90,omitempty"`
+	// LatencyP99 is the 99th percentile of the latency.
+	LatencyP99 string `json:"latencyP99,omitempty"`
+	// PathMTU is the discovered path MTU towards the remote gateway.
+	PathMTU int `json:"pathMTU,omitempty"`
+}
+
+// ConnectionKeyRotation contains information about the rotation of the keys used by the connection.
+type ConnectionKeyRotation struct {
+	// LocalKeyVersion is the version of the key currently used by the local gateway.
+	LocalKeyVersion int64 `json:"localKeyVersion,omitempty"`
+	// LastLocalRotation is the time the local gateway switched to its current key.
+	LastLocalRotation *metav1.Time `json:"lastLocalRotation,omitempty"`
+	// RemoteKeyVersion is the version of the last key announced by the remote gateway.
+	RemoteKeyVersion int64 `json:"remoteKeyVersion,omitempty"`
+	// LastRemoteRotation is the time the last key announced by the remote gateway has been accepted.
+	LastRemoteRotation *metav1.Time `json:"lastRemoteRotation,omitempty"`
+}
+
+// ConnectionReplicaStatus defines the observed state of the tunnel terminated by a replica of an active-active gateway.
+type ConnectionReplicaStatus struct {
+	// Index of the gateway replica.
+	Index int `json:"index"`
+	// PodName is the name of the pod of the gateway replica.
+	PodName string `json:"podName"`
+	// Value of the connection of the gateway replica.
+	Value ConnectionStatusValue `json:"value,omitempty"`
+	// Latency of the connection of the gateway replica.
+	Latency ConnectionLatency `json:"latency,omitempty"`
+	// Quality of the connection of the gateway replica.
+	Quality *ConnectionQuality `json:"quality,omitempty"`
+}
+
 // ConnectionStatus defines the observed state of Connection.
 type ConnectionStatus struct {
 	// Value of the connection.
+	// In case of active-active gateways, the connection is considered established if at least one replica is connected.
 	Value ConnectionStatusValue `json:"value,omitempty"`
 	// Latency of the connection.
 	Latency ConnectionLatency `json:"latency,omitempty"`
+	// Quality contains the link-quality measurements of the connection.
+	Quality *ConnectionQuality `json:"quality,omitempty"`
+	// MTU is the MTU the tunnel has been tuned to, according to the discovered path MTU.
+	// It is set only if the automatic MTU tuning is enabled.
+	MTU int `json:"mtu,omitempty"`
+	// KeyRotation contains information about the rotation of the keys used by the connection.
+	KeyRotation *ConnectionKeyRotation `json:"keyRotation,omitempty"`
+	// Replicas contains the status of the tunnels terminated by each replica, in case of active-active gateways.
+	Replicas []ConnectionReplicaStatus `json:"replicas,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -87,6 +141,10 @@ type ConnectionStatus struct {
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.value`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:printcolumn:name="Latency",type=string,JSONPath=`.status.latency.value`,priority=1
+// +kubebuilder:printcolumn:name="Loss",type=string,JSONPath=`.status.quality.packetLoss`,priority=1
+// +kubebuilder:printcolumn:name="Jitter",type=string,JSONPath=`.status.quality.jitter`,priority=1
+// +kubebuilder:printcolumn:name="PMTU",type=integer,JSONPath=`.status.quality.pathMTU`,priority=1
+// +kubebuilder:printcolumn:name="Last Key Rotation",type=date,JSONPath=`.status.keyRotation.lastLocalRotation`,priority=1
 
 // Connection contains the status of a connection between two clusters (a client and a server).
 type Connection struct {
@@ -22,6 +22,8 @@ const (
 	IPValueTypeIP IPValueType = "ip"
 	// IPValueTypeSubnet is a string representing a subnet (eg. 10.0.0.0/24).
 	IPValueTypeSubnet IPValueType = "subnet"
+	// IPValueTypeSet is a string referencing a named set or map (eg. @pods).
+	IPValueTypeSet IPValueType = "set"
 	// IPValueTypeVoid is a void match value.
 	IPValueTypeVoid IPValueType = "void"
 )
@@ -34,6 +36,8 @@ const (
 	PortValueTypePort PortValueType = "port"
 	// PortValueTypeRange is a string representing a range of ports (eg. 3000-4000).
 	PortValueTypeRange PortValueType = "range"
+	// PortValueTypeSet is a string referencing a named set or map (eg. @ports).
+	PortValueTypeSet PortValueType = "set"
 	// PortValueTypeVoid is a void match value.
 	PortValueTypeVoid PortValueType = "void"
 )
@@ -24,6 +24,15 @@ const (
 	// ActionSetMetaMarkFromCtMark is the action to be applied to the rule.
 	// It is used to set the meta mark from the conntrack mark.
 	ActionSetMetaMarkFromCtMark FilterAction = "metamarkfromctmark"
+	// ActionAccept is the action to be applied to the rule.
+	// It is used to accept the packet.
+	ActionAccept FilterAction = "accept"
+	// ActionDrop is the action to be applied to the rule.
+	// It is used to silently drop the packet.
+	ActionDrop FilterAction = "drop"
+	// ActionReject is the action to be applied to the rule.
+	// It is used to drop the packet, notifying the sender with an ICMP port unreachable message.
+	ActionReject FilterAction = "reject"
 )
 
 // FilterRule is a rule to be applied to a filter chain.
@@ -34,8 +43,13 @@ type FilterRule struct {
 	// Match is the match to be applied to the rule.
 	// They can be multiple and they are applied with an AND operator.
 	Match []Match `json:"match"`
+	// Counter enables the counting of the packets and bytes matching the rule,
+	// which are reported in the status of the FirewallConfiguration.
+	Counter bool `json:"counter,omitempty"`
+	// Log enables the logging of the packets matching the rule, with a prefix derived from the rule name.
+	Log bool `json:"log,omitempty"`
 	// Action is the action to be applied to the rule.
-	// +kubebuilder:validation:Enum=ctmark;metamarkfromctmark
+	// +kubebuilder:validation:Enum=ctmark;metamarkfromctmark;accept;drop;reject
 	Action FilterAction `json:"action"`
 	// Value is the value to be used for the action.
 	Value *string `json:"value,omitempty"`
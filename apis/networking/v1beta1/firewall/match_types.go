@@ -54,10 +54,81 @@ const (
 	L4ProtoUDP L4Proto = "udp"
 )
 
+// CtState is a state of the connection tracked by conntrack.
+// +kubebuilder:validation:Enum=new;established;related;invalid;untracked
+type CtState string
+
+const (
+	// CtStateNew is the state of a connection which has not seen packets in both directions yet.
+	CtStateNew CtState = "new"
+	// CtStateEstablished is the state of a connection which has seen packets in both directions.
+	CtStateEstablished CtState = "established"
+	// CtStateRelated is the state of a connection related to an existing one (eg. ICMP errors).
+	CtStateRelated CtState = "related"
+	// CtStateInvalid is the state of a packet which cannot be associated to any connection.
+	CtStateInvalid CtState = "invalid"
+	// CtStateUntracked is the state of a packet which is not tracked by conntrack.
+	CtStateUntracked CtState = "untracked"
+)
+
+// MatchMarkType is the type of the mark to be matched.
+type MatchMarkType string
+
+const (
+	// MatchMarkTypeMeta is the mark of the packet.
+	MatchMarkTypeMeta MatchMarkType = "meta"
+	// MatchMarkTypeCt is the mark of the connection.
+	MatchMarkTypeCt MatchMarkType = "ct"
+)
+
+// ICMPType is the type of an ICMP message.
+type ICMPType string
+
+const (
+	// ICMPTypeEchoReply is the ICMP echo reply message.
+	ICMPTypeEchoReply ICMPType = "echo-reply"
+	// ICMPTypeDestinationUnreachable is the ICMP destination unreachable message.
+	ICMPTypeDestinationUnreachable ICMPType = "destination-unreachable"
+	// ICMPTypeRedirect is the ICMP redirect message.
+	ICMPTypeRedirect ICMPType = "redirect"
+	// ICMPTypeEchoRequest is the ICMP echo request message.
+	ICMPTypeEchoRequest ICMPType = "echo-request"
+	// ICMPTypeTimeExceeded is the ICMP time exceeded message.
+	ICMPTypeTimeExceeded ICMPType = "time-exceeded"
+	// ICMPTypeParameterProblem is the ICMP parameter problem message.
+	ICMPTypeParameterProblem ICMPType = "parameter-problem"
+	// ICMPTypePacketTooBig is the ICMPv6 packet too big message, which exists only in ICMPv6.
+	ICMPTypePacketTooBig ICMPType = "packet-too-big"
+)
+
+// ICMPProtocol is the protocol of an ICMP message.
+type ICMPProtocol string
+
+const (
+	// ICMPProtocolICMP is the ICMP protocol, carried by IPv4.
+	ICMPProtocolICMP ICMPProtocol = "icmp"
+	// ICMPProtocolICMPv6 is the ICMPv6 protocol, carried by IPv6.
+	ICMPProtocolICMPv6 ICMPProtocol = "icmpv6"
+)
+
+// LimitUnit is the time unit of a rate limit.
+type LimitUnit string
+
+const (
+	// LimitUnitSecond limits the rate per second.
+	LimitUnitSecond LimitUnit = "second"
+	// LimitUnitMinute limits the rate per minute.
+	LimitUnitMinute LimitUnit = "minute"
+	// LimitUnitHour limits the rate per hour.
+	LimitUnitHour LimitUnit = "hour"
+	// LimitUnitDay limits the rate per day.
+	LimitUnitDay LimitUnit = "day"
+)
+
 // MatchIP is an IP to be matched.
 // +kubebuilder:object:generate=true
 type MatchIP struct {
-	// Value is the IP or a Subnet to be matched.
+	// Value is the IP, a Subnet or a reference to a named set (eg. @pods) to be matched.
 	Value string `json:"value"`
 	// Position is the position of the IP in the packet.
 	// +kubebuilder:validation:Enum=src;dst
@@ -67,7 +138,7 @@ type MatchIP struct {
 // MatchPort is a port to be matched.
 // +kubebuilder:object:generate=true
 type MatchPort struct {
-	// Value is the port or a range (eg. 3000-4000) to be matched.
+	// Value is the port, a range (eg. 3000-4000) or a reference to a named set (eg. @ports) to be matched.
 	Value string `json:"value"`
 	// Position is the position of the port in the packet.
 	// +kubebuilder:validation:Enum=src;dst
@@ -77,7 +148,7 @@ type MatchPort struct {
 // MatchDev is a device to be matched.
 // +kubebuilder:object:generate=true
 type MatchDev struct {
-	// Value is the name of the device to be matched.
+	// Value is the name of the device, or a reference to a named set (eg. @devices), to be matched.
 	Value string `json:"value"`
 	// Position is the source device of the packet.
 	// +kubebuilder:validation:Enum=in;out
@@ -92,6 +163,51 @@ type MatchProto struct {
 	Value L4Proto `json:"value"`
 }
 
+// MatchCtState is a set of conntrack states to be matched.
+// +kubebuilder:object:generate=true
+type MatchCtState struct {
+	// Value is the list of states to be matched. The match succeeds if the connection is in any of them.
+	// +kubebuilder:validation:MinItems=1
+	Value []CtState `json:"value"`
+}
+
+// MatchMark is a mark to be matched.
+// +kubebuilder:object:generate=true
+type MatchMark struct {
+	// Value is the mark to be matched, optionally followed by the mask of the bits to be compared (eg. 0x10/0xf0).
+	Value string `json:"value"`
+	// Type is the type of the mark, either the mark of the packet (meta) or the one of the connection (ct).
+	// +kubebuilder:validation:Enum=meta;ct
+	Type MatchMarkType `json:"type"`
+}
+
+// MatchICMP is an ICMP message to be matched.
+// +kubebuilder:object:generate=true
+type MatchICMP struct {
+	// Type is the type of the ICMP message to be matched.
+	// +kubebuilder:validation:Enum=echo-reply;destination-unreachable;redirect;echo-request;time-exceeded;parameter-problem;packet-too-big
+	Type ICMPType `json:"type"`
+	// Protocol is the protocol of the ICMP message to be matched: icmp (the default) or icmpv6.
+	// In inet tables, matching both the IPv4 and the IPv6 messages requires a rule for each protocol.
+	// +kubebuilder:validation:Enum=icmp;icmpv6
+	Protocol ICMPProtocol `json:"protocol,omitempty"`
+}
+
+// MatchLimit is a rate limit to be matched.
+// The eq operation matches the packets within the rate, while the neq one matches the packets exceeding it.
+// +kubebuilder:object:generate=true
+type MatchLimit struct {
+	// Rate is the number of packets per time unit.
+	// +kubebuilder:validation:Minimum=1
+	Rate int64 `json:"rate"`
+	// Unit is the time unit of the rate.
+	// +kubebuilder:validation:Enum=second;minute;hour;day
+	Unit LimitUnit `json:"unit"`
+	// Burst is the number of packets exceeding the rate which are tolerated. Defaults to 5.
+	// +kubebuilder:validation:Minimum=0
+	Burst *int32 `json:"burst,omitempty"`
+}
+
 // Match is a match to be applied to a rule.
 // +kubebuilder:object:generate=true
 type Match struct {
@@ -106,4 +222,12 @@ type Match struct {
 	Proto *MatchProto `json:"proto,omitempty"`
 	// Dev contains the options to match a device.
 	Dev *MatchDev `json:"dev,omitempty"`
+	// CtState contains the options to match the conntrack state of the connection.
+	CtState *MatchCtState `json:"ctState,omitempty"`
+	// Mark contains the options to match the mark of the packet or of the connection.
+	Mark *MatchMark `json:"mark,omitempty"`
+	// ICMP contains the options to match the type of an ICMP message.
+	ICMP *MatchICMP `json:"icmp,omitempty"`
+	// Limit contains the options to match the packets depending on their rate.
+	Limit *MatchLimit `json:"limit,omitempty"`
 }
@@ -36,10 +36,16 @@ type NatRule struct {
 	// Match is the match to be applied to the rule.
 	// They can be multiple and they are applied with an AND operator.
 	Match []Match `json:"match"`
+	// Counter enables the counting of the packets and bytes matching the rule,
+	// which are reported in the status of the FirewallConfiguration.
+	Counter bool `json:"counter,omitempty"`
+	// Log enables the logging of the packets matching the rule, with a prefix derived from the rule name.
+	Log bool `json:"log,omitempty"`
 	// NatType is the type of the NAT rule.
 	// +kubebuilder:validation:Enum=dnat;snat;masquerade
 	NatType NatType `json:"natType"`
-	// To is the IP to be used for the NAT translation.
+	// To is the IP to be used for the NAT translation, or a reference to a named address map (eg. @remap),
+	// translating the destination address (dnat) or the source one (snat) to the corresponding value.
 	To *string `json:"to,omitempty"`
 	// TargetRef is the reference to the target object of the rule.
 	// It is optional and it can be used for custom purposes.
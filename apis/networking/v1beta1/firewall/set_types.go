@@ -0,0 +1,62 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+// SetReferencePrefix is the prefix of the values referencing a named set or map (e.g., @pods).
+const SetReferencePrefix = "@"
+
+// SetDataType is the type of the elements of a set, or of the keys and values of a map.
+type SetDataType string
+
+const (
+	// SetDataTypeIPAddr is an IPv4 address, or a subnet in interval sets.
+	SetDataTypeIPAddr SetDataType = "ipv4_addr"
+	// SetDataTypeIP6Addr is an IPv6 address, or a subnet in interval sets. It is allowed only as the key of a set.
+	SetDataTypeIP6Addr SetDataType = "ipv6_addr"
+	// SetDataTypeInetService is a transport port, or a port range (eg. 3000-4000) in interval sets.
+	SetDataTypeInetService SetDataType = "inet_service"
+	// SetDataTypeIfName is the name of a network interface.
+	SetDataTypeIfName SetDataType = "ifname"
+	// SetDataTypeVerdict is a verdict (accept, drop, return, jump <chain> or goto <chain>), allowed only as the values of a map.
+	SetDataTypeVerdict SetDataType = "verdict"
+)
+
+// SetElement is an element of a set, or an entry of a map.
+// +kubebuilder:object:generate=true
+type SetElement struct {
+	// Key is the element of the set, or the key of the map entry.
+	Key string `json:"key"`
+	// Value is the value of the map entry.
+	Value *string `json:"value,omitempty"`
+}
+
+// Set is a named set, or a map if the data type is defined, which can be referenced by the rules of the table.
+// Sets are referenced by the matches, while maps by the matches (verdict maps) and the NAT rules (address maps).
+// https://wiki.nftables.org/wiki-nftables/index.php/Sets
+// +kubebuilder:object:generate=true
+type Set struct {
+	// Name is the name of the set, referenced prefixed by "@" (eg. @pods).
+	Name string `json:"name"`
+	// KeyType is the type of the elements of the set, or of the keys of the map.
+	// +kubebuilder:validation:Enum=ipv4_addr;ipv6_addr;inet_service;ifname
+	KeyType SetDataType `json:"keyType"`
+	// DataType is the type of the values of the map. The set is not a map if not defined.
+	// +kubebuilder:validation:Enum=ipv4_addr;verdict
+	DataType *SetDataType `json:"dataType,omitempty"`
+	// Interval allows the elements to be subnets or port ranges.
+	Interval bool `json:"interval,omitempty"`
+	// Elements is the list of the elements of the set, or of the entries of the map.
+	Elements []SetElement `json:"elements,omitempty"`
+}
@@ -39,4 +39,6 @@ type Table struct {
 	// Family is the family of the table.
 	// +kubebuilder:validation:Enum="INET";"IPV4";"IPV6";"ARP";"NETDEV";"BRIDGE"
 	Family *TableFamily `json:"family"`
+	// Sets is a list of named sets and maps, which can be referenced by the rules of the table.
+	Sets []Set `json:"sets,omitempty"`
 }
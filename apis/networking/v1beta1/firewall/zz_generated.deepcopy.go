@@ -113,6 +113,26 @@ func (in *Match) DeepCopyInto(out *Match) {
 		*out = new(MatchDev)
 		**out = **in
 	}
+	if in.CtState != nil {
+		in, out := &in.CtState, &out.CtState
+		*out = new(MatchCtState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mark != nil {
+		in, out := &in.Mark, &out.Mark
+		*out = new(MatchMark)
+		**out = **in
+	}
+	if in.ICMP != nil {
+		in, out := &in.ICMP, &out.ICMP
+		*out = new(MatchICMP)
+		**out = **in
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(MatchLimit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Match.
@@ -125,6 +145,26 @@ func (in *Match) DeepCopy() *Match {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchCtState) DeepCopyInto(out *MatchCtState) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]CtState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchCtState.
+func (in *MatchCtState) DeepCopy() *MatchCtState {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchCtState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchDev) DeepCopyInto(out *MatchDev) {
 	*out = *in
@@ -140,6 +180,21 @@ func (in *MatchDev) DeepCopy() *MatchDev {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchICMP) DeepCopyInto(out *MatchICMP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchICMP.
+func (in *MatchICMP) DeepCopy() *MatchICMP {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchICMP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchIP) DeepCopyInto(out *MatchIP) {
 	*out = *in
@@ -155,6 +210,41 @@ func (in *MatchIP) DeepCopy() *MatchIP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchLimit) DeepCopyInto(out *MatchLimit) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchLimit.
+func (in *MatchLimit) DeepCopy() *MatchLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchMark) DeepCopyInto(out *MatchMark) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchMark.
+func (in *MatchMark) DeepCopy() *MatchMark {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchMark)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchPort) DeepCopyInto(out *MatchPort) {
 	*out = *in
@@ -278,6 +368,53 @@ func (in *RulesSet) DeepCopy() *RulesSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Set) DeepCopyInto(out *Set) {
+	*out = *in
+	if in.DataType != nil {
+		in, out := &in.DataType, &out.DataType
+		*out = new(SetDataType)
+		**out = **in
+	}
+	if in.Elements != nil {
+		in, out := &in.Elements, &out.Elements
+		*out = make([]SetElement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Set.
+func (in *Set) DeepCopy() *Set {
+	if in == nil {
+		return nil
+	}
+	out := new(Set)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetElement) DeepCopyInto(out *SetElement) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetElement.
+func (in *SetElement) DeepCopy() *SetElement {
+	if in == nil {
+		return nil
+	}
+	out := new(SetElement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Table) DeepCopyInto(out *Table) {
 	*out = *in
@@ -298,6 +435,13 @@ func (in *Table) DeepCopyInto(out *Table) {
 		*out = new(TableFamily)
 		**out = **in
 	}
+	if in.Sets != nil {
+		in, out := &in.Sets, &out.Sets
+		*out = make([]Set, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Table.
@@ -49,12 +49,34 @@ type FirewallConfigurationStatusCondition struct {
 	Status metav1.ConditionStatus `json:"status"`
 	// Last time the condition transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// LastCheckTime is the last time the configuration enforced on the host has been compared with the desired one.
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastDriftTime is the last time the configuration enforced on the host has been found diverging from the desired one.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+}
+
+// FirewallConfigurationStatusCounter reports the packets and bytes matched by a rule on a given host.
+type FirewallConfigurationStatusCounter struct {
+	// Host where the rule has been applied.
+	Host string `json:"host"`
+	// Chain is the name of the chain containing the rule.
+	Chain string `json:"chain"`
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Packets is the number of packets matched by the rule.
+	Packets int64 `json:"packets"`
+	// Bytes is the number of bytes matched by the rule.
+	Bytes int64 `json:"bytes"`
+	// Last time the counter has been read.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 // FirewallConfigurationStatus defines the observed state of FirewallConfiguration.
 type FirewallConfigurationStatus struct {
 	// Conditions is the list of conditions of the FirewallConfiguration.
 	Conditions []FirewallConfigurationStatusCondition `json:"conditions,omitempty"`
+	// Counters is the list of the counters of the rules with counting enabled, for each host.
+	Counters []FirewallConfigurationStatusCounter `json:"counters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -46,6 +46,9 @@ type GatewayClientSpec struct {
 	// SecretRef specifies the reference to the secret containing configurations.
 	// Leave it empty to let the operator create a new secret.
 	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// BandwidthLimit specifies the limits enforced on the traffic sent through the tunnel.
+	// +optional
+	BandwidthLimit *BandwidthLimit `json:"bandwidthLimit,omitempty"`
 }
 
 // GatewayClientStatus defines the observed state of GatewayClient.
@@ -56,6 +59,16 @@ type GatewayClientStatus struct {
 	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
 	// InternalEndpoint specifies the endpoint for the internal network.
 	InternalEndpoint *InternalGatewayEndpoint `json:"internalEndpoint,omitempty"`
+	// ActiveEndpoint specifies the address of the remote endpoint the client is currently connected to.
+	ActiveEndpoint *ActiveEndpoint `json:"activeEndpoint,omitempty"`
+}
+
+// ActiveEndpoint defines the address of the remote endpoint selected by the client, among the advertised ones.
+type ActiveEndpoint struct {
+	// Address is the advertised address in use, either an IP address or a DNS name.
+	Address string `json:"address,omitempty"`
+	// IP is the IP address in use, which the advertised address resolves to.
+	IP IP `json:"ip,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -67,6 +80,7 @@ type GatewayClientStatus struct {
 // +kubebuilder:printcolumn:name="IP",type=string,JSONPath=`.spec.endpoint.addresses[*]`
 // +kubebuilder:printcolumn:name="Port",type=string,JSONPath=`.spec.endpoint.port`
 // +kubebuilder:printcolumn:name="Protocol",type=string,JSONPath=`.spec.endpoint.protocol`, priority=1
+// +kubebuilder:printcolumn:name="Active IP",type=string,JSONPath=`.status.activeEndpoint.ip`, priority=1
 // +kubebuilder:printcolumn:name="MTU",type=integer,JSONPath=`.spec.mtu`, priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
@@ -50,6 +50,10 @@ type Endpoint struct {
 	// LoadBalancer provider must support this feature.
 	// +optional
 	LoadBalancerIP *string `json:"loadBalancerIP,omitempty"`
+	// AllowedClientCIDRs specifies the CIDRs the gateway clients are allowed to connect from.
+	// It is enforced by the gateway servers which learn the address of the client from the received traffic (e.g., the plain ones).
+	// +optional
+	AllowedClientCIDRs []CIDR `json:"allowedClientCIDRs,omitempty"`
 }
 
 // GatewayServerSpec defines the desired state of GatewayServer.
@@ -63,6 +67,9 @@ type GatewayServerSpec struct {
 	// SecretRef specifies the reference to the secret containing configurations.
 	// Leave it empty to let the operator create a new secret.
 	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// BandwidthLimit specifies the limits enforced on the traffic sent through the tunnel.
+	// +optional
+	BandwidthLimit *BandwidthLimit `json:"bandwidthLimit,omitempty"`
 }
 
 // EndpointStatus defines the observed state of the endpoint.
@@ -76,12 +83,25 @@ type EndpointStatus struct {
 	Protocol *corev1.Protocol `json:"protocol,omitempty"`
 }
 
+// InternalGatewayReplicaEndpoint defines the endpoint of a replica of an active-active gateway for the internal network.
+type InternalGatewayReplicaEndpoint struct {
+	// Index is the index of the replica.
+	Index int `json:"index"`
+	// IP is the IP address of the replica.
+	IP IP `json:"ip"`
+	// Node is the name of the node where the replica is running.
+	Node string `json:"node"`
+}
+
 // InternalGatewayEndpoint defines the endpoint for the internal network.
 type InternalGatewayEndpoint struct {
 	// IP is the IP address of the endpoint.
 	IP *IP `json:"ip,omitempty"`
 	// Node is the name of the node where the endpoint is running.
 	Node *string `json:"node,omitempty"`
+	// Replicas contains the endpoints of the ready replicas of an active-active gateway, sorted by index.
+	// The first one is also reported by the IP and Node fields.
+	Replicas []InternalGatewayReplicaEndpoint `json:"replicas,omitempty"`
 }
 
 // GatewayServerStatus defines the observed state of GatewayServer.
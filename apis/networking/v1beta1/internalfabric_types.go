@@ -55,6 +55,16 @@ type InternalFabricSpecInterface struct {
 	Gateway InternalFabricSpecInterfaceGateway `json:"gateway"`
 }
 
+// InternalFabricSpecReplica contains the information about an additional replica of an active-active gateway.
+type InternalFabricSpecReplica struct {
+	// Index is the index of the gateway replica.
+	Index int `json:"index"`
+	// InterfaceName is the name of the interface added to the nodes to connect them to the gateway replica.
+	InterfaceName string `json:"interfaceName"`
+	// GatewayIP is the IP of the gateway replica pod. It is empty if the replica is not ready.
+	GatewayIP IP `json:"gatewayIP,omitempty"`
+}
+
 // InternalFabricSpec defines the desired state of InternalFabric.
 type InternalFabricSpec struct {
 	// MTU is the MTU of the internal fabric.
@@ -65,6 +75,9 @@ type InternalFabricSpec struct {
 	Interface InternalFabricSpecInterface `json:"interface"`
 	// GatewayIP is the IP of the gateway pod.
 	GatewayIP IP `json:"gatewayIP"`
+	// Replicas contains the additional replicas of an active-active gateway, besides the one identified by GatewayIP.
+	// The traffic towards the remote CIDRs is balanced across all the ready replicas.
+	Replicas []InternalFabricSpecReplica `json:"replicas,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,83 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// IPsecGatewayClientResource the name of the ipsecgatewayclient resources.
+var IPsecGatewayClientResource = "ipsecgatewayclients"
+
+// IPsecGatewayClientKind is the kind name used to register the IPsecGatewayClient CRD.
+var IPsecGatewayClientKind = "IPsecGatewayClient"
+
+// IPsecGatewayClientGroupResource is group resource used to register these objects.
+var IPsecGatewayClientGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: IPsecGatewayClientResource}
+
+// IPsecGatewayClientGroupVersionResource is groupResourceVersion used to register these objects.
+var IPsecGatewayClientGroupVersionResource = GroupVersion.WithResource(IPsecGatewayClientResource)
+
+// IPsecGatewayClientSpec defines the desired state of IPsecGatewayClient.
+type IPsecGatewayClientSpec struct {
+	// Deployment specifies the deployment template for the client.
+	Deployment DeploymentTemplate `json:"deployment"`
+	// Metrics specifies the metrics configuration for the client.
+	Metrics *Metrics `json:"metrics,omitempty"`
+	// SecretRef specifies the reference to the secret containing the IPsec keys.
+	// Leave it empty to let the operator create a new secret.
+	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// IPsecGatewayClientStatus defines the observed state of IPsecGatewayClient.
+type IPsecGatewayClientStatus struct {
+	// SecretRef specifies the reference to the secret.
+	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
+	// InternalEndpoint specifies the endpoint for the internal network.
+	InternalEndpoint *InternalGatewayEndpoint `json:"internalEndpoint,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=ipsecc;ipsecgc
+// +kubebuilder:subresource:status
+
+// IPsecGatewayClient defines an IPsec gateway client that needs to point to a remote IPsec gateway server.
+// The security associations are negotiated by a Liqo-specific handshake rather than by IKEv2, hence its key management
+// is not FIPS-validated, even though it relies on FIPS-approved primitives.
+type IPsecGatewayClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPsecGatewayClientSpec   `json:"spec,omitempty"`
+	Status IPsecGatewayClientStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IPsecGatewayClientList contains a list of IPsecGatewayClient.
+type IPsecGatewayClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPsecGatewayClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPsecGatewayClient{}, &IPsecGatewayClientList{})
+}
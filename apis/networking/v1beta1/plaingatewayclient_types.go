@@ -0,0 +1,75 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PlainGatewayClientResource the name of the plaingatewayclient resources.
+var PlainGatewayClientResource = "plaingatewayclients"
+
+// PlainGatewayClientKind is the kind name used to register the PlainGatewayClient CRD.
+var PlainGatewayClientKind = "PlainGatewayClient"
+
+// PlainGatewayClientGroupResource is group resource used to register these objects.
+var PlainGatewayClientGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: PlainGatewayClientResource}
+
+// PlainGatewayClientGroupVersionResource is groupResourceVersion used to register these objects.
+var PlainGatewayClientGroupVersionResource = GroupVersion.WithResource(PlainGatewayClientResource)
+
+// PlainGatewayClientSpec defines the desired state of PlainGatewayClient.
+type PlainGatewayClientSpec struct {
+	// Deployment specifies the deployment template for the client.
+	Deployment DeploymentTemplate `json:"deployment"`
+	// Metrics specifies the metrics configuration for the client.
+	Metrics *Metrics `json:"metrics,omitempty"`
+}
+
+// PlainGatewayClientStatus defines the observed state of PlainGatewayClient.
+type PlainGatewayClientStatus struct {
+	// InternalEndpoint specifies the endpoint for the internal network.
+	InternalEndpoint *InternalGatewayEndpoint `json:"internalEndpoint,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=plainc;plaingc
+// +kubebuilder:subresource:status
+
+// PlainGatewayClient defines a gateway client establishing an unencrypted (GENEVE or VXLAN) tunnel with a remote plain gateway server.
+type PlainGatewayClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PlainGatewayClientSpec   `json:"spec,omitempty"`
+	Status PlainGatewayClientStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PlainGatewayClientList contains a list of PlainGatewayClient.
+type PlainGatewayClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlainGatewayClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PlainGatewayClient{}, &PlainGatewayClientList{})
+}
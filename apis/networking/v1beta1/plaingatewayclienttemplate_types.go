@@ -0,0 +1,69 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PlainGatewayClientTemplateResource the name of the plaingatewayclienttemplate resources.
+var PlainGatewayClientTemplateResource = "plaingatewayclienttemplates"
+
+// PlainGatewayClientTemplateKind is the kind name used to register the PlainGatewayClientTemplate CRD.
+var PlainGatewayClientTemplateKind = "PlainGatewayClientTemplate"
+
+// PlainGatewayClientTemplateGroupResource is group resource used to register these objects.
+var PlainGatewayClientTemplateGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: PlainGatewayClientTemplateResource}
+
+// PlainGatewayClientTemplateGroupVersionResource is groupResourceVersion used to register these objects.
+var PlainGatewayClientTemplateGroupVersionResource = GroupVersion.WithResource(PlainGatewayClientTemplateResource)
+
+// PlainGatewayClientTemplateSpec defines the desired state of PlainGatewayClientTemplate.
+type PlainGatewayClientTemplateSpec struct {
+	// ObjectKind specifies the kind of the object.
+	ObjectKind metav1.TypeMeta `json:"objectKind,omitempty"`
+	// Template specifies the template of the client.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template unstructured.Unstructured `json:"template,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=plaingct;plainct
+
+// PlainGatewayClientTemplate contains a template for a plain gateway client.
+type PlainGatewayClientTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PlainGatewayClientTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PlainGatewayClientTemplateList contains a list of PlainGatewayClientTemplate.
+type PlainGatewayClientTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlainGatewayClientTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PlainGatewayClientTemplate{}, &PlainGatewayClientTemplateList{})
+}
@@ -0,0 +1,79 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PlainGatewayServerResource the name of the plaingatewayserver resources.
+var PlainGatewayServerResource = "plaingatewayservers"
+
+// PlainGatewayServerKind specifies the kind of the plaingatewayserver resources.
+var PlainGatewayServerKind = "PlainGatewayServer"
+
+// PlainGatewayServerGroupResource specifies the group and the resource of the plaingatewayserver resources.
+var PlainGatewayServerGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: PlainGatewayServerResource}
+
+// PlainGatewayServerGroupVersionResource specifies the group, the version and the resource of the plaingatewayserver resources.
+var PlainGatewayServerGroupVersionResource = GroupVersion.WithResource(PlainGatewayServerResource)
+
+// PlainGatewayServerSpec defines the desired state of PlainGatewayServer.
+type PlainGatewayServerSpec struct {
+	// Service specifies the service template for the server.
+	Service ServiceTemplate `json:"service"`
+	// Deployment specifies the deployment template for the server.
+	Deployment DeploymentTemplate `json:"deployment"`
+	// Metrics specifies the metrics configuration for the server.
+	Metrics *Metrics `json:"metrics,omitempty"`
+}
+
+// PlainGatewayServerStatus defines the observed state of PlainGatewayServer.
+type PlainGatewayServerStatus struct {
+	// Endpoint specifies the endpoint of the server.
+	Endpoint *EndpointStatus `json:"endpoint,omitempty"`
+	// InternalEndpoint specifies the endpoint for the internal network.
+	InternalEndpoint *InternalGatewayEndpoint `json:"internalEndpoint,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=plains;plaings
+// +kubebuilder:subresource:status
+
+// PlainGatewayServer defines a gateway server establishing an unencrypted (GENEVE or VXLAN) tunnel with remote plain gateway clients.
+type PlainGatewayServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PlainGatewayServerSpec   `json:"spec,omitempty"`
+	Status PlainGatewayServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PlainGatewayServerList contains a list of PlainGatewayServer.
+type PlainGatewayServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlainGatewayServer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PlainGatewayServer{}, &PlainGatewayServerList{})
+}
@@ -38,6 +38,14 @@ var PublicKeyGroupVersionResource = GroupVersion.WithResource(PublicKeyResource)
 type PublicKeySpec struct {
 	// PublicKey contains the public key.
 	PublicKey []byte `json:"publicKey,omitempty"`
+	// Version is the version of the public key, incremented at every key rotation.
+	// +kubebuilder:validation:Minimum=0
+	Version int64 `json:"version,omitempty"`
+	// PreviousPublicKey contains the public key replaced by the last rotation,
+	// which is still accepted until PreviousPublicKeyExpiration.
+	PreviousPublicKey []byte `json:"previousPublicKey,omitempty"`
+	// PreviousPublicKeyExpiration is the time after which the previous public key is no longer accepted.
+	PreviousPublicKeyExpiration *metav1.Time `json:"previousPublicKeyExpiration,omitempty"`
 }
 
 // publickeies is used for resource name pluralization because k8s api do not manage false friends.
@@ -45,6 +53,8 @@ type PublicKeySpec struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories=liqo,path=publickeies,shortName=pk;pkies;pkey
+// +kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.spec.version`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PublicKey contains a public key data required by some interconnection technologies.
 type PublicKey struct {
@@ -51,6 +51,20 @@ const (
 	NowhereScope Scope = "nowhere"
 )
 
+// NextHop is a next hop of a multipath route.
+type NextHop struct {
+	// Gw is the gateway of the next hop.
+	Gw *IP `json:"gw,omitempty"`
+	// Dev is the device of the next hop.
+	Dev *string `json:"dev,omitempty"`
+	// Onlink enables the onlink flag for the next hop.
+	Onlink *bool `json:"onlink,omitempty"`
+	// Weight is the weight of the next hop.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=256
+	Weight *int `json:"weight,omitempty"`
+}
+
 // Route is the route of the RouteConfiguration.
 type Route struct {
 	// Dst is the destination of the RouteConfiguration.
@@ -66,6 +80,9 @@ type Route struct {
 	// Scope is the scope of the RouteConfiguration.
 	// +kubebuilder:validation:Enum=global;link;host;site;nowhere
 	Scope *Scope `json:"scope,omitempty"`
+	// NextHops is the list of next hops of a multipath route, alternative to Gw and Dev.
+	// The traffic is balanced across the next hops on a per-flow basis.
+	NextHops []NextHop `json:"nextHops,omitempty"`
 	// TargetRef is the reference to the target object of the route.
 	// It is optional and it can be used for custom purposes.
 	TargetRef *corev1.ObjectReference `json:"targetRef,omitempty"`
@@ -128,6 +145,10 @@ type RouteConfigurationStatusCondition struct {
 	Status metav1.ConditionStatus `json:"status"`
 	// Last time the condition transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// LastCheckTime is the last time the configuration enforced on the host has been compared with the desired one.
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastDriftTime is the last time the configuration enforced on the host has been found diverging from the desired one.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
 }
 
 // RouteConfigurationStatus defines the observed state of RouteConfiguration.
@@ -44,6 +44,9 @@ type WgGatewayClientSpec struct {
 	// SecretRef specifies the reference to the secret containing the wireguard configuration.
 	// Leave it empty to let the operator create a new secret.
 	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// KeyRotation configures the periodic rotation of the keys generated by the operator.
+	// It is ignored if the secret is provided through SecretRef. Leave it empty to disable the rotation.
+	KeyRotation *KeyRotation `json:"keyRotation,omitempty"`
 }
 
 // WgGatewayClientStatus defines the observed state of WgGatewayClient.
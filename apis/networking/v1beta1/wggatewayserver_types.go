@@ -71,6 +71,15 @@ type Metrics struct {
 	ServiceMonitor *ServiceMonitorTemplate `json:"serviceMonitor,omitempty"`
 }
 
+// KeyRotation defines the configuration of the periodic rotation of the gateway keys.
+type KeyRotation struct {
+	// Interval is the time between two consecutive key rotations.
+	Interval metav1.Duration `json:"interval"`
+	// TransitionWindow is the time, after a rotation, during which the previous key is still accepted by the remote gateway.
+	// +kubebuilder:default="10m"
+	TransitionWindow metav1.Duration `json:"transitionWindow,omitempty"`
+}
+
 // WgGatewayServerSpec defines the desired state of WgGatewayServer.
 type WgGatewayServerSpec struct {
 	// Service specifies the service template for the server.
@@ -82,6 +91,9 @@ type WgGatewayServerSpec struct {
 	// SecretRef specifies the reference to the secret containing the wireguard configuration.
 	// Leave it empty to let the operator create a new secret.
 	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// KeyRotation configures the periodic rotation of the keys generated by the operator.
+	// It is ignored if the secret is provided through SecretRef. Leave it empty to disable the rotation.
+	KeyRotation *KeyRotation `json:"keyRotation,omitempty"`
 }
 
 // WgGatewayServerStatus defines the observed state of WgGatewayServer.
@@ -23,6 +23,70 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveEndpoint) DeepCopyInto(out *ActiveEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveEndpoint.
+func (in *ActiveEndpoint) DeepCopy() *ActiveEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthClass) DeepCopyInto(out *BandwidthClass) {
+	*out = *in
+	if in.DSCP != nil {
+		in, out := &in.DSCP, &out.DSCP
+		*out = make([]DSCP, len(*in))
+		copy(*out, *in)
+	}
+	out.Rate = in.Rate.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthClass.
+func (in *BandwidthClass) DeepCopy() *BandwidthClass {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthLimit) DeepCopyInto(out *BandwidthLimit) {
+	*out = *in
+	out.Rate = in.Rate.DeepCopy()
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]BandwidthClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthLimit.
+func (in *BandwidthLimit) DeepCopy() *BandwidthLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 	*out = *in
@@ -132,6 +196,11 @@ func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Remote.DeepCopyInto(&out.Remote)
+	if in.Transit != nil {
+		in, out := &in.Transit, &out.Transit
+		*out = new(TransitConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -191,6 +260,29 @@ func (in *Connection) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionKeyRotation) DeepCopyInto(out *ConnectionKeyRotation) {
+	*out = *in
+	if in.LastLocalRotation != nil {
+		in, out := &in.LastLocalRotation, &out.LastLocalRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRemoteRotation != nil {
+		in, out := &in.LastRemoteRotation, &out.LastRemoteRotation
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionKeyRotation.
+func (in *ConnectionKeyRotation) DeepCopy() *ConnectionKeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionKeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionLatency) DeepCopyInto(out *ConnectionLatency) {
 	*out = *in
@@ -239,6 +331,42 @@ func (in *ConnectionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionQuality) DeepCopyInto(out *ConnectionQuality) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionQuality.
+func (in *ConnectionQuality) DeepCopy() *ConnectionQuality {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionQuality)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionReplicaStatus) DeepCopyInto(out *ConnectionReplicaStatus) {
+	*out = *in
+	in.Latency.DeepCopyInto(&out.Latency)
+	if in.Quality != nil {
+		in, out := &in.Quality, &out.Quality
+		*out = new(ConnectionQuality)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionReplicaStatus.
+func (in *ConnectionReplicaStatus) DeepCopy() *ConnectionReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionSpec) DeepCopyInto(out *ConnectionSpec) {
 	*out = *in
@@ -259,6 +387,23 @@ func (in *ConnectionSpec) DeepCopy() *ConnectionSpec {
 func (in *ConnectionStatus) DeepCopyInto(out *ConnectionStatus) {
 	*out = *in
 	in.Latency.DeepCopyInto(&out.Latency)
+	if in.Quality != nil {
+		in, out := &in.Quality, &out.Quality
+		*out = new(ConnectionQuality)
+		**out = **in
+	}
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(ConnectionKeyRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ConnectionReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionStatus.
@@ -301,6 +446,11 @@ func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AllowedClientCIDRs != nil {
+		in, out := &in.AllowedClientCIDRs, &out.AllowedClientCIDRs
+		*out = make([]CIDR, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
@@ -423,6 +573,13 @@ func (in *FirewallConfigurationStatus) DeepCopyInto(out *FirewallConfigurationSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Counters != nil {
+		in, out := &in.Counters, &out.Counters
+		*out = make([]FirewallConfigurationStatusCounter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallConfigurationStatus.
@@ -439,6 +596,14 @@ func (in *FirewallConfigurationStatus) DeepCopy() *FirewallConfigurationStatus {
 func (in *FirewallConfigurationStatusCondition) DeepCopyInto(out *FirewallConfigurationStatusCondition) {
 	*out = *in
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallConfigurationStatusCondition.
@@ -451,6 +616,22 @@ func (in *FirewallConfigurationStatusCondition) DeepCopy() *FirewallConfiguratio
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallConfigurationStatusCounter) DeepCopyInto(out *FirewallConfigurationStatusCounter) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallConfigurationStatusCounter.
+func (in *FirewallConfigurationStatusCounter) DeepCopy() *FirewallConfigurationStatusCounter {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallConfigurationStatusCounter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayClient) DeepCopyInto(out *GatewayClient) {
 	*out = *in
@@ -516,6 +697,11 @@ func (in *GatewayClientSpec) DeepCopyInto(out *GatewayClientSpec) {
 	out.ClientTemplateRef = in.ClientTemplateRef
 	in.Endpoint.DeepCopyInto(&out.Endpoint)
 	out.SecretRef = in.SecretRef
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		*out = new(BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayClientSpec.
@@ -546,6 +732,11 @@ func (in *GatewayClientStatus) DeepCopyInto(out *GatewayClientStatus) {
 		*out = new(InternalGatewayEndpoint)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ActiveEndpoint != nil {
+		in, out := &in.ActiveEndpoint, &out.ActiveEndpoint
+		*out = new(ActiveEndpoint)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayClientStatus.
@@ -623,6 +814,11 @@ func (in *GatewayServerSpec) DeepCopyInto(out *GatewayServerSpec) {
 	out.ServerTemplateRef = in.ServerTemplateRef
 	in.Endpoint.DeepCopyInto(&out.Endpoint)
 	out.SecretRef = in.SecretRef
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		*out = new(BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayServerSpec.
@@ -754,25 +950,26 @@ func (in *GeneveTunnelSpec) DeepCopy() *GeneveTunnelSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabric) DeepCopyInto(out *InternalFabric) {
+func (in *IPsecGatewayClient) DeepCopyInto(out *IPsecGatewayClient) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabric.
-func (in *InternalFabric) DeepCopy() *InternalFabric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClient.
+func (in *IPsecGatewayClient) DeepCopy() *IPsecGatewayClient {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabric)
+	out := new(IPsecGatewayClient)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalFabric) DeepCopyObject() runtime.Object {
+func (in *IPsecGatewayClient) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -780,31 +977,31 @@ func (in *InternalFabric) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabricList) DeepCopyInto(out *InternalFabricList) {
+func (in *IPsecGatewayClientList) DeepCopyInto(out *IPsecGatewayClientList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]InternalFabric, len(*in))
+		*out = make([]IPsecGatewayClient, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricList.
-func (in *InternalFabricList) DeepCopy() *InternalFabricList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientList.
+func (in *IPsecGatewayClientList) DeepCopy() *IPsecGatewayClientList {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabricList)
+	out := new(IPsecGatewayClientList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalFabricList) DeepCopyObject() runtime.Object {
+func (in *IPsecGatewayClientList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -812,119 +1009,148 @@ func (in *InternalFabricList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabricSpec) DeepCopyInto(out *InternalFabricSpec) {
+func (in *IPsecGatewayClientSpec) DeepCopyInto(out *IPsecGatewayClientSpec) {
 	*out = *in
-	if in.RemoteCIDRs != nil {
-		in, out := &in.RemoteCIDRs, &out.RemoteCIDRs
-		*out = make([]CIDR, len(*in))
-		copy(*out, *in)
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(Metrics)
+		(*in).DeepCopyInto(*out)
 	}
-	out.Interface = in.Interface
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpec.
-func (in *InternalFabricSpec) DeepCopy() *InternalFabricSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientSpec.
+func (in *IPsecGatewayClientSpec) DeepCopy() *IPsecGatewayClientSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabricSpec)
+	out := new(IPsecGatewayClientSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabricSpecInterface) DeepCopyInto(out *InternalFabricSpecInterface) {
+func (in *IPsecGatewayClientStatus) DeepCopyInto(out *IPsecGatewayClientStatus) {
 	*out = *in
-	out.Node = in.Node
-	out.Gateway = in.Gateway
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.InternalEndpoint != nil {
+		in, out := &in.InternalEndpoint, &out.InternalEndpoint
+		*out = new(InternalGatewayEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterface.
-func (in *InternalFabricSpecInterface) DeepCopy() *InternalFabricSpecInterface {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientStatus.
+func (in *IPsecGatewayClientStatus) DeepCopy() *IPsecGatewayClientStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabricSpecInterface)
+	out := new(IPsecGatewayClientStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabricSpecInterfaceGateway) DeepCopyInto(out *InternalFabricSpecInterfaceGateway) {
+func (in *IPsecGatewayClientTemplate) DeepCopyInto(out *IPsecGatewayClientTemplate) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterfaceGateway.
-func (in *InternalFabricSpecInterfaceGateway) DeepCopy() *InternalFabricSpecInterfaceGateway {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientTemplate.
+func (in *IPsecGatewayClientTemplate) DeepCopy() *IPsecGatewayClientTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabricSpecInterfaceGateway)
+	out := new(IPsecGatewayClientTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPsecGatewayClientTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalFabricSpecInterfaceNode) DeepCopyInto(out *InternalFabricSpecInterfaceNode) {
+func (in *IPsecGatewayClientTemplateList) DeepCopyInto(out *IPsecGatewayClientTemplateList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IPsecGatewayClientTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterfaceNode.
-func (in *InternalFabricSpecInterfaceNode) DeepCopy() *InternalFabricSpecInterfaceNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientTemplateList.
+func (in *IPsecGatewayClientTemplateList) DeepCopy() *IPsecGatewayClientTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalFabricSpecInterfaceNode)
+	out := new(IPsecGatewayClientTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPsecGatewayClientTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalGatewayEndpoint) DeepCopyInto(out *InternalGatewayEndpoint) {
+func (in *IPsecGatewayClientTemplateSpec) DeepCopyInto(out *IPsecGatewayClientTemplateSpec) {
 	*out = *in
-	if in.IP != nil {
-		in, out := &in.IP, &out.IP
-		*out = new(IP)
-		**out = **in
-	}
-	if in.Node != nil {
-		in, out := &in.Node, &out.Node
-		*out = new(string)
-		**out = **in
-	}
+	out.ObjectKind = in.ObjectKind
+	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalGatewayEndpoint.
-func (in *InternalGatewayEndpoint) DeepCopy() *InternalGatewayEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayClientTemplateSpec.
+func (in *IPsecGatewayClientTemplateSpec) DeepCopy() *IPsecGatewayClientTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalGatewayEndpoint)
+	out := new(IPsecGatewayClientTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNode) DeepCopyInto(out *InternalNode) {
+func (in *IPsecGatewayServer) DeepCopyInto(out *IPsecGatewayServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNode.
-func (in *InternalNode) DeepCopy() *InternalNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServer.
+func (in *IPsecGatewayServer) DeepCopy() *IPsecGatewayServer {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNode)
+	out := new(IPsecGatewayServer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalNode) DeepCopyObject() runtime.Object {
+func (in *IPsecGatewayServer) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -932,31 +1158,31 @@ func (in *InternalNode) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeList) DeepCopyInto(out *InternalNodeList) {
+func (in *IPsecGatewayServerList) DeepCopyInto(out *IPsecGatewayServerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]InternalNode, len(*in))
+		*out = make([]IPsecGatewayServer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeList.
-func (in *InternalNodeList) DeepCopy() *InternalNodeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerList.
+func (in *IPsecGatewayServerList) DeepCopy() *IPsecGatewayServerList {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeList)
+	out := new(IPsecGatewayServerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalNodeList) DeepCopyObject() runtime.Object {
+func (in *IPsecGatewayServerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -964,130 +1190,916 @@ func (in *InternalNodeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeSpec) DeepCopyInto(out *InternalNodeSpec) {
+func (in *IPsecGatewayServerSpec) DeepCopyInto(out *IPsecGatewayServerSpec) {
 	*out = *in
-	out.Interface = in.Interface
+	in.Service.DeepCopyInto(&out.Service)
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(Metrics)
+		(*in).DeepCopyInto(*out)
+	}
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpec.
-func (in *InternalNodeSpec) DeepCopy() *InternalNodeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerSpec.
+func (in *IPsecGatewayServerSpec) DeepCopy() *IPsecGatewayServerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeSpec)
+	out := new(IPsecGatewayServerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeSpecInterface) DeepCopyInto(out *InternalNodeSpecInterface) {
+func (in *IPsecGatewayServerStatus) DeepCopyInto(out *IPsecGatewayServerStatus) {
 	*out = *in
-	out.Gateway = in.Gateway
-	out.Node = in.Node
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(EndpointStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalEndpoint != nil {
+		in, out := &in.InternalEndpoint, &out.InternalEndpoint
+		*out = new(InternalGatewayEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpecInterface.
-func (in *InternalNodeSpecInterface) DeepCopy() *InternalNodeSpecInterface {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerStatus.
+func (in *IPsecGatewayServerStatus) DeepCopy() *IPsecGatewayServerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeSpecInterface)
+	out := new(IPsecGatewayServerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeSpecInterfaceGateway) DeepCopyInto(out *InternalNodeSpecInterfaceGateway) {
+func (in *IPsecGatewayServerTemplate) DeepCopyInto(out *IPsecGatewayServerTemplate) {
 	*out = *in
-}
-
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerTemplate.
+func (in *IPsecGatewayServerTemplate) DeepCopy() *IPsecGatewayServerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IPsecGatewayServerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPsecGatewayServerTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPsecGatewayServerTemplateList) DeepCopyInto(out *IPsecGatewayServerTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IPsecGatewayServerTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerTemplateList.
+func (in *IPsecGatewayServerTemplateList) DeepCopy() *IPsecGatewayServerTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(IPsecGatewayServerTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPsecGatewayServerTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPsecGatewayServerTemplateSpec) DeepCopyInto(out *IPsecGatewayServerTemplateSpec) {
+	*out = *in
+	out.ObjectKind = in.ObjectKind
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPsecGatewayServerTemplateSpec.
+func (in *IPsecGatewayServerTemplateSpec) DeepCopy() *IPsecGatewayServerTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPsecGatewayServerTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabric) DeepCopyInto(out *InternalFabric) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabric.
+func (in *InternalFabric) DeepCopy() *InternalFabric {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalFabric) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricList) DeepCopyInto(out *InternalFabricList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InternalFabric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricList.
+func (in *InternalFabricList) DeepCopy() *InternalFabricList {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalFabricList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricSpec) DeepCopyInto(out *InternalFabricSpec) {
+	*out = *in
+	if in.RemoteCIDRs != nil {
+		in, out := &in.RemoteCIDRs, &out.RemoteCIDRs
+		*out = make([]CIDR, len(*in))
+		copy(*out, *in)
+	}
+	out.Interface = in.Interface
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]InternalFabricSpecReplica, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpec.
+func (in *InternalFabricSpec) DeepCopy() *InternalFabricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricSpecInterface) DeepCopyInto(out *InternalFabricSpecInterface) {
+	*out = *in
+	out.Node = in.Node
+	out.Gateway = in.Gateway
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterface.
+func (in *InternalFabricSpecInterface) DeepCopy() *InternalFabricSpecInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricSpecInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricSpecInterfaceGateway) DeepCopyInto(out *InternalFabricSpecInterfaceGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterfaceGateway.
+func (in *InternalFabricSpecInterfaceGateway) DeepCopy() *InternalFabricSpecInterfaceGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricSpecInterfaceGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricSpecInterfaceNode) DeepCopyInto(out *InternalFabricSpecInterfaceNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecInterfaceNode.
+func (in *InternalFabricSpecInterfaceNode) DeepCopy() *InternalFabricSpecInterfaceNode {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricSpecInterfaceNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalFabricSpecReplica) DeepCopyInto(out *InternalFabricSpecReplica) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalFabricSpecReplica.
+func (in *InternalFabricSpecReplica) DeepCopy() *InternalFabricSpecReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalFabricSpecReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalGatewayEndpoint) DeepCopyInto(out *InternalGatewayEndpoint) {
+	*out = *in
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = new(IP)
+		**out = **in
+	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(string)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]InternalGatewayReplicaEndpoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalGatewayEndpoint.
+func (in *InternalGatewayEndpoint) DeepCopy() *InternalGatewayEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalGatewayEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalGatewayReplicaEndpoint) DeepCopyInto(out *InternalGatewayReplicaEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalGatewayReplicaEndpoint.
+func (in *InternalGatewayReplicaEndpoint) DeepCopy() *InternalGatewayReplicaEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalGatewayReplicaEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNode) DeepCopyInto(out *InternalNode) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNode.
+func (in *InternalNode) DeepCopy() *InternalNode {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalNode) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeList) DeepCopyInto(out *InternalNodeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InternalNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeList.
+func (in *InternalNodeList) DeepCopy() *InternalNodeList {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalNodeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeSpec) DeepCopyInto(out *InternalNodeSpec) {
+	*out = *in
+	out.Interface = in.Interface
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpec.
+func (in *InternalNodeSpec) DeepCopy() *InternalNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeSpecInterface) DeepCopyInto(out *InternalNodeSpecInterface) {
+	*out = *in
+	out.Gateway = in.Gateway
+	out.Node = in.Node
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpecInterface.
+func (in *InternalNodeSpecInterface) DeepCopy() *InternalNodeSpecInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeSpecInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeSpecInterfaceGateway) DeepCopyInto(out *InternalNodeSpecInterfaceGateway) {
+	*out = *in
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpecInterfaceGateway.
 func (in *InternalNodeSpecInterfaceGateway) DeepCopy() *InternalNodeSpecInterfaceGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeSpecInterfaceGateway)
+	out := new(InternalNodeSpecInterfaceGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeSpecInterfaceNode) DeepCopyInto(out *InternalNodeSpecInterfaceNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpecInterfaceNode.
+func (in *InternalNodeSpecInterfaceNode) DeepCopy() *InternalNodeSpecInterfaceNode {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeSpecInterfaceNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeStatus) DeepCopyInto(out *InternalNodeStatus) {
+	*out = *in
+	in.NodeIP.DeepCopyInto(&out.NodeIP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeStatus.
+func (in *InternalNodeStatus) DeepCopy() *InternalNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalNodeStatusNodeIP) DeepCopyInto(out *InternalNodeStatusNodeIP) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(IP)
+		**out = **in
+	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(IP)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeStatusNodeIP.
+func (in *InternalNodeStatusNodeIP) DeepCopy() *InternalNodeStatusNodeIP {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalNodeStatusNodeIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotation) DeepCopyInto(out *KeyRotation) {
+	*out = *in
+	out.Interval = in.Interval
+	out.TransitionWindow = in.TransitionWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotation.
+func (in *KeyRotation) DeepCopy() *KeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metrics) DeepCopyInto(out *Metrics) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metrics.
+func (in *Metrics) DeepCopy() *Metrics {
+	if in == nil {
+		return nil
+	}
+	out := new(Metrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NextHop) DeepCopyInto(out *NextHop) {
+	*out = *in
+	if in.Gw != nil {
+		in, out := &in.Gw, &out.Gw
+		*out = new(IP)
+		**out = **in
+	}
+	if in.Dev != nil {
+		in, out := &in.Dev, &out.Dev
+		*out = new(string)
+		**out = **in
+	}
+	if in.Onlink != nil {
+		in, out := &in.Onlink, &out.Onlink
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NextHop.
+func (in *NextHop) DeepCopy() *NextHop {
+	if in == nil {
+		return nil
+	}
+	out := new(NextHop)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayClient) DeepCopyInto(out *PlainGatewayClient) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClient.
+func (in *PlainGatewayClient) DeepCopy() *PlainGatewayClient {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayClientList) DeepCopyInto(out *PlainGatewayClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlainGatewayClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientList.
+func (in *PlainGatewayClientList) DeepCopy() *PlainGatewayClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayClientList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeSpecInterfaceNode) DeepCopyInto(out *InternalNodeSpecInterfaceNode) {
+func (in *PlainGatewayClientSpec) DeepCopyInto(out *PlainGatewayClientSpec) {
 	*out = *in
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(Metrics)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeSpecInterfaceNode.
-func (in *InternalNodeSpecInterfaceNode) DeepCopy() *InternalNodeSpecInterfaceNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientSpec.
+func (in *PlainGatewayClientSpec) DeepCopy() *PlainGatewayClientSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeSpecInterfaceNode)
+	out := new(PlainGatewayClientSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeStatus) DeepCopyInto(out *InternalNodeStatus) {
+func (in *PlainGatewayClientStatus) DeepCopyInto(out *PlainGatewayClientStatus) {
 	*out = *in
-	in.NodeIP.DeepCopyInto(&out.NodeIP)
+	if in.InternalEndpoint != nil {
+		in, out := &in.InternalEndpoint, &out.InternalEndpoint
+		*out = new(InternalGatewayEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeStatus.
-func (in *InternalNodeStatus) DeepCopy() *InternalNodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientStatus.
+func (in *PlainGatewayClientStatus) DeepCopy() *PlainGatewayClientStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeStatus)
+	out := new(PlainGatewayClientStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalNodeStatusNodeIP) DeepCopyInto(out *InternalNodeStatusNodeIP) {
+func (in *PlainGatewayClientTemplate) DeepCopyInto(out *PlainGatewayClientTemplate) {
 	*out = *in
-	if in.Local != nil {
-		in, out := &in.Local, &out.Local
-		*out = new(IP)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientTemplate.
+func (in *PlainGatewayClientTemplate) DeepCopy() *PlainGatewayClientTemplate {
+	if in == nil {
+		return nil
 	}
-	if in.Remote != nil {
-		in, out := &in.Remote, &out.Remote
-		*out = new(IP)
-		**out = **in
+	out := new(PlainGatewayClientTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayClientTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalNodeStatusNodeIP.
-func (in *InternalNodeStatusNodeIP) DeepCopy() *InternalNodeStatusNodeIP {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayClientTemplateList) DeepCopyInto(out *PlainGatewayClientTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlainGatewayClientTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientTemplateList.
+func (in *PlainGatewayClientTemplateList) DeepCopy() *PlainGatewayClientTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalNodeStatusNodeIP)
+	out := new(PlainGatewayClientTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayClientTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Metrics) DeepCopyInto(out *Metrics) {
+func (in *PlainGatewayClientTemplateSpec) DeepCopyInto(out *PlainGatewayClientTemplateSpec) {
 	*out = *in
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceTemplate)
+	out.ObjectKind = in.ObjectKind
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayClientTemplateSpec.
+func (in *PlainGatewayClientTemplateSpec) DeepCopy() *PlainGatewayClientTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayClientTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServer) DeepCopyInto(out *PlainGatewayServer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServer.
+func (in *PlainGatewayServer) DeepCopy() *PlainGatewayServer {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayServer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerList) DeepCopyInto(out *PlainGatewayServerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlainGatewayServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerList.
+func (in *PlainGatewayServerList) DeepCopy() *PlainGatewayServerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayServerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerSpec) DeepCopyInto(out *PlainGatewayServerSpec) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(Metrics)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ServiceMonitor != nil {
-		in, out := &in.ServiceMonitor, &out.ServiceMonitor
-		*out = new(ServiceMonitorTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerSpec.
+func (in *PlainGatewayServerSpec) DeepCopy() *PlainGatewayServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerStatus) DeepCopyInto(out *PlainGatewayServerStatus) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(EndpointStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalEndpoint != nil {
+		in, out := &in.InternalEndpoint, &out.InternalEndpoint
+		*out = new(InternalGatewayEndpoint)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metrics.
-func (in *Metrics) DeepCopy() *Metrics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerStatus.
+func (in *PlainGatewayServerStatus) DeepCopy() *PlainGatewayServerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Metrics)
+	out := new(PlainGatewayServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerTemplate) DeepCopyInto(out *PlainGatewayServerTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerTemplate.
+func (in *PlainGatewayServerTemplate) DeepCopy() *PlainGatewayServerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayServerTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerTemplateList) DeepCopyInto(out *PlainGatewayServerTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlainGatewayServerTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerTemplateList.
+func (in *PlainGatewayServerTemplateList) DeepCopy() *PlainGatewayServerTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServerTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlainGatewayServerTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainGatewayServerTemplateSpec) DeepCopyInto(out *PlainGatewayServerTemplateSpec) {
+	*out = *in
+	out.ObjectKind = in.ObjectKind
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainGatewayServerTemplateSpec.
+func (in *PlainGatewayServerTemplateSpec) DeepCopy() *PlainGatewayServerTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainGatewayServerTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1158,6 +2170,15 @@ func (in *PublicKeySpec) DeepCopyInto(out *PublicKeySpec) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.PreviousPublicKey != nil {
+		in, out := &in.PreviousPublicKey, &out.PreviousPublicKey
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreviousPublicKeyExpiration != nil {
+		in, out := &in.PreviousPublicKeyExpiration, &out.PreviousPublicKeyExpiration
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicKeySpec.
@@ -1203,6 +2224,13 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = new(Scope)
 		**out = **in
 	}
+	if in.NextHops != nil {
+		in, out := &in.NextHops, &out.NextHops
+		*out = make([]NextHop, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TargetRef != nil {
 		in, out := &in.TargetRef, &out.TargetRef
 		*out = new(v1.ObjectReference)
@@ -1321,6 +2349,14 @@ func (in *RouteConfigurationStatus) DeepCopy() *RouteConfigurationStatus {
 func (in *RouteConfigurationStatusCondition) DeepCopyInto(out *RouteConfigurationStatusCondition) {
 	*out = *in
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteConfigurationStatusCondition.
@@ -1446,6 +2482,21 @@ func (in *Table) DeepCopy() *Table {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitConfig) DeepCopyInto(out *TransitConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitConfig.
+func (in *TransitConfig) DeepCopy() *TransitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WgGatewayClient) DeepCopyInto(out *WgGatewayClient) {
 	*out = *in
@@ -1515,6 +2566,11 @@ func (in *WgGatewayClientSpec) DeepCopyInto(out *WgGatewayClientSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.SecretRef = in.SecretRef
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(KeyRotation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WgGatewayClientSpec.
@@ -1697,6 +2753,11 @@ func (in *WgGatewayServerSpec) DeepCopyInto(out *WgGatewayServerSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.SecretRef = in.SecretRef
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(KeyRotation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WgGatewayServerSpec.
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
@@ -184,6 +185,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	if err := fwcr.SetupWithManager(cmd.Context(), mgr, options.EnableNftMonitor); err != nil {
 		return fmt.Errorf("unable to setup firewall configuration reconciler: %w", err)
 	}
+	if err := metrics.Registry.Register(firewall.NewPrometheusCollector(fwcr)); err != nil {
+		return fmt.Errorf("unable to register the firewall metrics: %w", err)
+	}
 
 	// Setup the route configuration controller.
 	rcr, err := route.NewRouteConfigurationReconcilerWithFinalizer(
@@ -200,6 +204,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	if err := rcr.SetupWithManager(cmd.Context(), mgr); err != nil {
 		return fmt.Errorf("unable to setup route configuration reconciler: %w", err)
 	}
+	if err := route.RegisterMetrics(metrics.Registry); err != nil {
+		return fmt.Errorf("unable to register the route metrics: %w", err)
+	}
 
 	ifr, err := fabric.NewInternalFabricReconciler(
 		mgr.GetClient(),
@@ -0,0 +1,94 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/benchmark"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+)
+
+// newBenchmarkCommand returns the command running the traffic generator used to benchmark the links between gateways.
+func newBenchmarkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Run the traffic generator benchmarking the links between gateways",
+	}
+	cmd.AddCommand(newBenchmarkServerCommand(), newBenchmarkClientCommand())
+	return cmd
+}
+
+func newBenchmarkServerCommand() *cobra.Command {
+	var (
+		port    int
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Run the benchmark server, until the client completes or the timeout expires",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return benchmark.RunServer(cmd.Context(), port, timeout)
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", benchmark.DefaultPort, "The port the server listens on, for both TCP and UDP")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "The maximum time the server runs for")
+	return cmd
+}
+
+func newBenchmarkClientCommand() *cobra.Command {
+	var mode gateway.Mode
+	opts := benchmark.ClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "client",
+		Short: "Measure the throughput and the latency towards the benchmark server, printing the results as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Unless explicitly set, the server is the peer gateway, reachable through the tunnel.
+			if opts.Address == "" {
+				address, err := tunnel.GetRemoteInterfaceIP(mode)
+				if err != nil {
+					return fmt.Errorf("unable to get the address of the peer gateway (is --mode set?): %w", err)
+				}
+				opts.Address = address
+			}
+
+			result, err := benchmark.RunClient(cmd.Context(), &opts)
+			if err != nil {
+				return err
+			}
+			return json.NewEncoder(os.Stdout).Encode(result)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Address, "address", "",
+		"The address of the benchmark server. Defaults to the tunnel address of the peer gateway, according to --mode")
+	cmd.Flags().Var(&mode, "mode", "The mode of the local gateway (server or client), used to infer the address of the peer gateway")
+	cmd.Flags().IntVar(&opts.Port, "port", benchmark.DefaultPort, "The port of the benchmark server")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 10*time.Second, "The duration of the throughput measurement")
+	cmd.Flags().IntVar(&opts.Streams, "streams", 1, "The number of parallel TCP streams of the throughput measurement")
+	cmd.Flags().IntVar(&opts.Pings, "pings", 50, "The number of pings of the latency measurement")
+	return cmd
+}
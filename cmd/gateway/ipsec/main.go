@@ -0,0 +1,182 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipsec contains the logic to configure the IPsec tunnel.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/concurrent"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel/ipsec"
+	flagsutils "github.com/liqotech/liqo/pkg/utils/flags"
+	"github.com/liqotech/liqo/pkg/utils/mapper"
+	"github.com/liqotech/liqo/pkg/utils/restcfg"
+)
+
+var (
+	scheme  = runtime.NewScheme()
+	options = ipsec.NewOptions(gateway.NewOptions())
+)
+
+func init() {
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1beta1.AddToScheme(scheme))
+	utilruntime.Must(ipamv1alpha1.AddToScheme(scheme))
+}
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func main() {
+	var cmd = cobra.Command{
+		Use:  "liqo-ipsec",
+		RunE: run,
+	}
+
+	flagsutils.InitKlogFlags(cmd.Flags())
+	restcfg.InitFlags(cmd.Flags())
+
+	gateway.InitFlags(cmd.Flags(), options.GwOptions)
+	ipsec.InitFlags(cmd.Flags(), options)
+	if err := ipsec.MarkFlagsRequired(&cmd, options); err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	var err error
+
+	// Set controller-runtime logger.
+	log.SetLogger(klog.NewKlogr())
+
+	// Get the rest config.
+	cfg := config.GetConfigOrDie()
+
+	// Create the manager.
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		MapperProvider: mapper.LiqoMapperProvider(scheme),
+		Scheme:         scheme,
+		Cache: cache.Options{
+			DefaultNamespaces: map[string]cache.Config{
+				options.GwOptions.Namespace: {},
+			},
+		},
+		Metrics: server.Options{
+			BindAddress: options.GwOptions.MetricsAddress,
+		},
+		HealthProbeBindAddress: options.GwOptions.ProbeAddr,
+		LeaderElection:         false,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create manager: %w", err)
+	}
+
+	// Register the healthiness probes.
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up healthz probe: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up readyz probe: %w", err)
+	}
+
+	// Reject the IPv6 endpoints, as they are not supported by the IPsec tunnel.
+	if options.GwOptions.Mode == gateway.ModeClient {
+		if err := ipsec.CheckEndpointAddresses(options.EndpointAddresses); err != nil {
+			return err
+		}
+	}
+
+	// Load keys.
+	if err := ipsec.LoadKeys(options); err != nil {
+		return fmt.Errorf("unable to load keys: %w", err)
+	}
+
+	handle, err := netlink.NewHandle()
+	if err != nil {
+		return fmt.Errorf("unable to create netlink handle: %w", err)
+	}
+	defer handle.Close()
+
+	// Create the liqo-tunnel XFRM interface.
+	if err := ipsec.InitLink(handle, options); err != nil {
+		return fmt.Errorf("unable to init IPsec link: %w", err)
+	}
+
+	// Open the socket used for the handshake and the UDP encapsulation.
+	tunnel := ipsec.NewTunnel(options, handle)
+	if err := tunnel.Listen(); err != nil {
+		return fmt.Errorf("unable to open the IPsec socket: %w", err)
+	}
+	if err := mgr.Add(tunnel); err != nil {
+		return fmt.Errorf("unable to add the IPsec tunnel to the manager: %w", err)
+	}
+
+	// Setup the controller.
+	pkr := ipsec.NewPublicKeysReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("public-keys-controller"),
+		options,
+		tunnel,
+	)
+	if err = pkr.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to setup public keys reconciler: %w", err)
+	}
+
+	// Create the Prometheus collector and register it inside the controller-runtime metrics server.
+	promcollect := ipsec.NewPrometheusCollector(mgr.GetClient(), tunnel, &ipsec.MetricsOptions{
+		RemoteClusterID: options.GwOptions.RemoteClusterID,
+		Namespace:       options.GwOptions.Namespace,
+	})
+	if err := metrics.Registry.Register(promcollect); err != nil {
+		return fmt.Errorf("unable to register prometheus collector: %w", err)
+	}
+
+	runnable, err := concurrent.NewRunnableGuest(options.GwOptions.ContainerName)
+	if err != nil {
+		return fmt.Errorf("unable to create runnable guest: %w", err)
+	}
+	if err := runnable.Start(cmd.Context()); err != nil {
+		return fmt.Errorf("unable to start runnable guest: %w", err)
+	}
+	defer runnable.Close()
+
+	// Start the manager.
+	return mgr.Start(cmd.Context())
+}
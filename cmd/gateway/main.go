@@ -16,11 +16,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -29,8 +34,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
@@ -39,6 +46,8 @@ import (
 	"github.com/liqotech/liqo/pkg/gateway/concurrent"
 	"github.com/liqotech/liqo/pkg/gateway/connection"
 	"github.com/liqotech/liqo/pkg/gateway/connection/conncheck"
+	"github.com/liqotech/liqo/pkg/gateway/conntrack"
+	"github.com/liqotech/liqo/pkg/gateway/shaping"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/remapping"
 	"github.com/liqotech/liqo/pkg/route"
 	argsutils "github.com/liqotech/liqo/pkg/utils/args"
@@ -59,11 +68,13 @@ var (
 
 func init() {
 	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1.AddToScheme(scheme))
 	utilruntime.Must(networkingv1beta1.AddToScheme(scheme))
 }
 
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update;delete
 
 func main() {
 	var cmd = cobra.Command{
@@ -92,6 +103,8 @@ func main() {
 	cmd.Flags().Var(&globalLabels, "global-labels", "Global labels to be added to all created resources (key=value)")
 	cmd.Flags().Var(&globalAnnotations, "global-annotations", "Global annotations to be added to all created resources (key=value)")
 
+	cmd.AddCommand(newBenchmarkCommand())
+
 	if err := cmd.Execute(); err != nil {
 		klog.Error(err)
 		os.Exit(1)
@@ -137,6 +150,25 @@ func run(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("unable to create client: %w", err)
 	}
 
+	// In active-active mode, all the replicas are active, and each one claims its own replica index,
+	// used to pair it with the remote replica with the same index.
+	activeActive := connoptions.GwOptions.ConcurrencyMode == gateway.ConcurrencyModeActiveActive
+	if activeActive {
+		connoptions.GwOptions.ReplicaIndex, err = concurrent.ClaimReplicaIndex(cmd.Context(), cl,
+			connoptions.GwOptions.Namespace,
+			fmt.Sprintf("%s.%s", connoptions.GwOptions.Name, connoptions.GwOptions.Mode),
+			connoptions.GwOptions.PodName,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to claim the replica index: %w", err)
+		}
+		if connoptions.PMTUAutoTune {
+			// The path MTU is discovered by each replica independently, while the internal fabric is shared.
+			klog.Warning("Automatic MTU tuning is not supported in active-active mode, the path MTU is only reported")
+			connoptions.PMTUAutoTune = false
+		}
+	}
+
 	// Create the manager.
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		MapperProvider: mapper.LiqoMapperProvider(scheme),
@@ -145,7 +177,7 @@ func run(cmd *cobra.Command, _ []string) error {
 			BindAddress: connoptions.GwOptions.MetricsAddress,
 		},
 		HealthProbeBindAddress: connoptions.GwOptions.ProbeAddr,
-		LeaderElection:         connoptions.GwOptions.LeaderElection,
+		LeaderElection:         connoptions.GwOptions.LeaderElection && !activeActive,
 		LeaderElectionID: fmt.Sprintf(
 			"%s.%s.%s.connections.liqo.io",
 			connoptions.GwOptions.Name, connoptions.GwOptions.Namespace, connoptions.GwOptions.Mode,
@@ -165,9 +197,7 @@ func run(cmd *cobra.Command, _ []string) error {
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up healthz probe: %w", err)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		return fmt.Errorf("unable to set up readyz probe: %w", err)
-	}
+	readyzCheck := healthz.Ping
 
 	if connoptions.EnableConnectionController {
 		// Setup the connection controller.
@@ -185,6 +215,33 @@ func run(cmd *cobra.Command, _ []string) error {
 		if err = connr.SetupWithManager(mgr); err != nil {
 			return fmt.Errorf("unable to setup connections reconciler: %w", err)
 		}
+
+		// In active-active mode, a replica is ready only if its tunnel is established, so that nodes balance traffic only across working replicas.
+		if activeActive {
+			readyzCheck = connr.ReadyzCheck
+		}
+	}
+
+	// Setup the bandwidth limit controller.
+	blr := shaping.NewBandwidthLimitReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("bandwidthlimit-controller"),
+		connoptions.GwOptions,
+	)
+	if err := blr.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to setup bandwidth limit reconciler: %w", err)
+	}
+	if err := metrics.Registry.Register(shaping.NewPrometheusCollector(blr, connoptions.GwOptions.RemoteClusterID)); err != nil {
+		return fmt.Errorf("unable to register the bandwidth limit metrics: %w", err)
+	}
+
+	if err := conncheck.RegisterMetrics(metrics.Registry); err != nil {
+		return fmt.Errorf("unable to register the connection check metrics: %w", err)
+	}
+
+	if err := mgr.AddReadyzCheck("readyz", readyzCheck); err != nil {
+		return fmt.Errorf("unable to set up readyz probe: %w", err)
 	}
 
 	rcr, err := route.NewRouteConfigurationReconcilerWithoutFinalizer(
@@ -205,6 +262,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	if err := rcr.SetupWithManager(cmd.Context(), mgr); err != nil {
 		return fmt.Errorf("unable to setup routeconfiguration reconciler: %w", err)
 	}
+	if err := route.RegisterMetrics(metrics.Registry); err != nil {
+		return fmt.Errorf("unable to register the route metrics: %w", err)
+	}
 
 	// Setup the firewall configuration controller.
 	fwcr, err := firewall.NewFirewallConfigurationReconcilerWithoutFinalizer(
@@ -225,6 +285,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	if err := fwcr.SetupWithManager(cmd.Context(), mgr, true); err != nil {
 		return fmt.Errorf("unable to setup firewall configuration reconciler: %w", err)
 	}
+	if err := metrics.Registry.Register(firewall.NewPrometheusCollector(fwcr)); err != nil {
+		return fmt.Errorf("unable to register the firewall metrics: %w", err)
+	}
 
 	runnable, err := concurrent.NewRunnableGatewayStartup(
 		cl,
@@ -232,11 +295,18 @@ func run(cmd *cobra.Command, _ []string) error {
 		connoptions.GwOptions.Name,
 		connoptions.GwOptions.Namespace,
 		connoptions.GwOptions.ConcurrentContainersNames,
+		activeActive,
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create concurrent runnable: %w", err)
 	}
 
+	if connoptions.GwOptions.ConntrackSync {
+		if err := setupConntrackSync(cmd.Context(), mgr, cl, runnable, activeActive); err != nil {
+			return err
+		}
+	}
+
 	if err := mgr.Add(runnable); err != nil {
 		return fmt.Errorf("unable to add concurrent runnable: %w", err)
 	}
@@ -244,3 +314,52 @@ func run(cmd *cobra.Command, _ []string) error {
 	// Start the manager.
 	return mgr.Start(cmd.Context())
 }
+
+// setupConntrackSync configures the active replica to stream its conntrack entries to the standby ones,
+// which inject them when promoted. The replicas authenticate each other, and only the pod address is listened on,
+// optionally with a NetworkPolicy restricting the access to the other replicas.
+func setupConntrackSync(ctx context.Context, mgr ctrl.Manager, cl client.Client, runnable *concurrent.RunnableGateway, activeActive bool) error {
+	if activeActive {
+		klog.Warning("Conntrack synchronization is not supported in active-active mode, as each replica handles its own connections")
+		return nil
+	}
+
+	opts := connoptions.GwOptions
+	if opts.ConntrackSyncAddress == "" || opts.ConntrackSyncKeyFile == "" {
+		return fmt.Errorf("conntrack synchronization requires the --%s and --%s flags",
+			gateway.FlagNameConntrackSyncAddress, gateway.FlagNameConntrackSyncKeyFile)
+	}
+
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{
+		Name: conntrack.NetworkPolicyName(opts.Name), Namespace: opts.Namespace}}
+	if opts.ConntrackSyncNetworkPolicy {
+		if _, err := controllerutil.CreateOrUpdate(ctx, cl, policy, func() error {
+			conntrack.MutateNetworkPolicy(policy, opts.Name, opts.ConntrackSyncPort)
+			return gateway.SetOwnerReferenceWithMode(opts, policy, cl.Scheme())
+		}); err != nil {
+			return fmt.Errorf("unable to enforce the conntrack synchronization network policy: %w", err)
+		}
+	} else if err := cl.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("unable to delete the conntrack synchronization network policy: %w", err)
+	}
+
+	key := conntrack.KeyFromFile(opts.ConntrackSyncKeyFile)
+	port := strconv.Itoa(opts.ConntrackSyncPort)
+	if err := mgr.Add(conntrack.NewSender(net.JoinHostPort(opts.ConntrackSyncAddress, port), opts.ConntrackSyncInterval, key)); err != nil {
+		return fmt.Errorf("unable to add conntrack sender: %w", err)
+	}
+
+	receiver := conntrack.NewReceiver(func(ctx context.Context) (string, error) {
+		ip, err := concurrent.GetActiveGatewayPodIP(ctx, cl, opts.Namespace, opts.Name, opts.PodName)
+		if err != nil {
+			return "", err
+		}
+		return net.JoinHostPort(ip, port), nil
+	}, opts.ConntrackSyncInterval, key)
+	if err := mgr.Add(receiver); err != nil {
+		return fmt.Errorf("unable to add conntrack receiver: %w", err)
+	}
+
+	runnable.ConntrackReceiver = receiver
+	return nil
+}
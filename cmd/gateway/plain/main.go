@@ -0,0 +1,164 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plain contains the logic to configure the plain (unencrypted) tunnel.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/concurrent"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel/plain"
+	flagsutils "github.com/liqotech/liqo/pkg/utils/flags"
+	"github.com/liqotech/liqo/pkg/utils/mapper"
+	"github.com/liqotech/liqo/pkg/utils/restcfg"
+)
+
+var (
+	scheme  = runtime.NewScheme()
+	options = plain.NewOptions(gateway.NewOptions())
+)
+
+func init() {
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1beta1.AddToScheme(scheme))
+	utilruntime.Must(ipamv1alpha1.AddToScheme(scheme))
+}
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func main() {
+	var cmd = cobra.Command{
+		Use:  "liqo-plain",
+		RunE: run,
+	}
+
+	flagsutils.InitKlogFlags(cmd.Flags())
+	restcfg.InitFlags(cmd.Flags())
+
+	gateway.InitFlags(cmd.Flags(), options.GwOptions)
+	plain.InitFlags(cmd.Flags(), options)
+	if err := plain.MarkFlagsRequired(&cmd, options); err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	var err error
+
+	// Set controller-runtime logger.
+	log.SetLogger(klog.NewKlogr())
+
+	// Refuse to start unless the unencrypted traffic has been explicitly acknowledged.
+	if err := plain.CheckAcknowledged(options); err != nil {
+		return err
+	}
+	klog.Warningf("The traffic towards the remote cluster %q is not encrypted", options.GwOptions.RemoteClusterID)
+
+	// Refuse to start unless the server knows the sources the client is allowed to connect from.
+	if err := plain.CheckAllowedClients(options); err != nil {
+		return err
+	}
+
+	// Get the rest config.
+	cfg := config.GetConfigOrDie()
+
+	// Create the manager.
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		MapperProvider: mapper.LiqoMapperProvider(scheme),
+		Scheme:         scheme,
+		Cache: cache.Options{
+			DefaultNamespaces: map[string]cache.Config{
+				options.GwOptions.Namespace: {},
+			},
+		},
+		Metrics: server.Options{
+			BindAddress: options.GwOptions.MetricsAddress,
+		},
+		HealthProbeBindAddress: options.GwOptions.ProbeAddr,
+		LeaderElection:         false,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create manager: %w", err)
+	}
+
+	// Register the healthiness probes.
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up healthz probe: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up readyz probe: %w", err)
+	}
+
+	handle, err := netlink.NewHandle()
+	if err != nil {
+		return fmt.Errorf("unable to create netlink handle: %w", err)
+	}
+	defer handle.Close()
+
+	// Open the socket used by the server to learn the address of the client.
+	tunnel := plain.NewTunnel(mgr.GetClient(), mgr.GetScheme(), options, handle)
+	if err := tunnel.Listen(); err != nil {
+		return fmt.Errorf("unable to open the plain tunnel socket: %w", err)
+	}
+	if err := mgr.Add(tunnel); err != nil {
+		return fmt.Errorf("unable to add the plain tunnel to the manager: %w", err)
+	}
+
+	// Create the Prometheus collector and register it inside the controller-runtime metrics server.
+	promcollect := plain.NewPrometheusCollector(mgr.GetClient(), handle, &plain.MetricsOptions{
+		RemoteClusterID: options.GwOptions.RemoteClusterID,
+		Namespace:       options.GwOptions.Namespace,
+	})
+	if err := metrics.Registry.Register(promcollect); err != nil {
+		return fmt.Errorf("unable to register prometheus collector: %w", err)
+	}
+
+	runnable, err := concurrent.NewRunnableGuest(options.GwOptions.ContainerName)
+	if err != nil {
+		return fmt.Errorf("unable to create runnable guest: %w", err)
+	}
+	if err := runnable.Start(cmd.Context()); err != nil {
+		return fmt.Errorf("unable to start runnable guest: %w", err)
+	}
+	defer runnable.Close()
+
+	// Start the manager.
+	return mgr.Start(cmd.Context())
+}
@@ -17,7 +17,6 @@ package main
 
 import (
 	"fmt"
-	"net"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -27,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -57,6 +57,7 @@ func init() {
 
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get
 
 func main() {
 	var cmd = cobra.Command{
@@ -108,6 +109,23 @@ func run(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("unable to create manager: %w", err)
 	}
 
+	// In active-active mode, each client replica connects to the server replica with the same index,
+	// exposed on the endpoint port shifted by the replica index.
+	if options.GwOptions.Mode == gateway.ModeClient && options.GwOptions.ConcurrencyMode == gateway.ConcurrencyModeActiveActive {
+		cl, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("unable to create client: %w", err)
+		}
+		index, err := concurrent.WaitReplicaIndex(cmd.Context(), cl,
+			client.ObjectKey{Namespace: options.GwOptions.Namespace, Name: options.GwOptions.PodName})
+		if err != nil {
+			return fmt.Errorf("unable to get the replica index: %w", err)
+		}
+		options.GwOptions.ReplicaIndex = index
+		options.EndpointPort += index
+		klog.Infof("Replica %d: connecting to endpoint port %d", index, options.EndpointPort)
+	}
+
 	// Register the healthiness probes.
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up healthz probe: %w", err)
@@ -127,15 +145,19 @@ func run(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("unable to create public keys reconciler: %w", err)
 	}
 
+	// Carry the tunnel over a TCP or WebSocket stream, if configured.
+	streamed, err := wireguard.SetupTransport(mgr, options)
+	if err != nil {
+		return fmt.Errorf("unable to setup the stream transport: %w", err)
+	}
+
 	dnsChan := make(chan event.GenericEvent)
-	if options.GwOptions.Mode == gateway.ModeClient {
-		if wireguard.IsDNSRoutineRequired(options) {
-			go wireguard.StartDNSRoutine(cmd.Context(), dnsChan, options)
-			klog.Infof("Starting DNS routine: resolving the endpoint address every %s", options.DNSCheckInterval.String())
-		} else {
-			options.EndpointIP = net.ParseIP(options.EndpointAddress)
-			klog.Infof("Setting static endpoint IP: %s", options.EndpointIP.String())
+	if options.GwOptions.Mode == gateway.ModeClient && !streamed {
+		// Select the endpoint IP among the advertised addresses, failing over to another one if the connection is down.
+		if err := mgr.Add(wireguard.NewEndpointSelector(mgr.GetClient(), options, dnsChan)); err != nil {
+			return fmt.Errorf("unable to add the endpoint selector: %w", err)
 		}
+		klog.Infof("Selecting the endpoint among %v: resolving the addresses every %s", options.EndpointAddresses, options.DNSCheckInterval.String())
 	}
 
 	// Setup the controller.
@@ -153,6 +175,15 @@ func run(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("unable to init wireguard link: %w", err)
 	}
 
+	// Setup the key rotator, exchanging the rotated keys with the remote gateway through the tunnel.
+	rotator, err := wireguard.NewKeyRotator(mgr.GetClient(), options)
+	if err != nil {
+		return fmt.Errorf("unable to create key rotator: %w", err)
+	}
+	if err := mgr.Add(rotator); err != nil {
+		return fmt.Errorf("unable to add key rotator: %w", err)
+	}
+
 	// Create the Prometheus collector and register it inside the controller-runtime metrics server.
 	promcollect, err := wireguard.NewPrometheusCollector(mgr.GetClient(), &wireguard.MetricsOptions{
 		RemoteClusterID:  options.GwOptions.RemoteClusterID,
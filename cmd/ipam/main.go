@@ -16,6 +16,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -23,23 +24,29 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/ipam"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
 	"github.com/liqotech/liqo/pkg/leaderelection"
 	flagsutils "github.com/liqotech/liqo/pkg/utils/flags"
+	grpcutils "github.com/liqotech/liqo/pkg/utils/grpc"
 	"github.com/liqotech/liqo/pkg/utils/restcfg"
 )
 
@@ -77,9 +84,26 @@ func main() {
 	cmd.Flags().DurationVar(&options.ServerOpts.SyncGracePeriod, "sync-graceperiod", consts.SyncGracePeriod,
 		"The grace period the sync routine wait before releasing an ip or a network.")
 	cmd.Flags().BoolVar(&options.ServerOpts.GraphvizEnabled, "enable-graphviz", false, "Enable the graphviz output for the IPAM.")
+	cmd.Flags().StringVar(&options.ServerOpts.AllocationPolicy, "allocation-policy", string(ipamcore.AllocationPolicyFirstFit),
+		fmt.Sprintf("The default policy used to remap the networks which cannot be allocated as requested (%v).", ipamcore.AllocationPolicies))
 	cmd.Flags().StringSliceVar(&options.ServerOpts.Pools, "pools", consts.PrivateAddressSpace,
 		"The pools used by the IPAM to acquire Networks and IPs from. Default: private addesses space.",
 	)
+	cmd.Flags().StringVar(&options.ServerOpts.SnapshotConfigMapName, "snapshot-configmap-name", "",
+		"The name of the ConfigMap used to checkpoint the IPAM state. If empty, the state is not checkpointed to a ConfigMap.")
+	cmd.Flags().StringVar(&options.ServerOpts.SnapshotConfigMapNamespace, "snapshot-configmap-namespace", consts.DefaultLiqoNamespace,
+		"The namespace of the ConfigMap used to checkpoint the IPAM state.")
+	cmd.Flags().StringVar(&options.ServerOpts.SnapshotFilePath, "snapshot-file", "",
+		"The path of the local file used to checkpoint the IPAM state. It takes precedence over the ConfigMap.")
+
+	// Leak collector options.
+	cmd.Flags().DurationVar(&options.LeakCollectorOpts.Interval, "leak-collector-interval", 5*time.Minute,
+		"The interval at which the IPAM looks for the allocations whose owner no longer exists. Set to 0 to disable the collector.")
+	cmd.Flags().DurationVar(&options.LeakCollectorOpts.GracePeriod, "leak-collector-graceperiod", consts.SyncGracePeriod,
+		"The minimum age of an allocation to be considered leaked.")
+	cmd.Flags().BoolVar(&options.LeakCollectorOpts.Free, "leak-collector-free", false,
+		"Free the leaked allocations, rather than only reporting them through events and metrics.")
+	cmd.Flags().StringVar(&options.MetricsAddress, "metrics-address", ":8082", "The address the metric endpoint binds to.")
 
 	// Leader election flags.
 	cmd.Flags().BoolVar(&options.EnableLeaderElection, "leader-election", false, "Enable leader election for IPAM. "+
@@ -96,7 +120,12 @@ func main() {
 		"The duration the LeaderElector clients should wait between tries of actions.")
 	cmd.Flags().StringVar(&options.PodName, "pod-name", "",
 		"The name of the pod running the IPAM service.")
+	cmd.Flags().StringVar(&options.PodNamespace, "pod-namespace", consts.DefaultLiqoNamespace,
+		"The namespace of the pod running the IPAM service.")
 	cmd.Flags().StringVar(&options.DeploymentName, "deployment-name", "", "The name of the deployment running the IPAM service.")
+	cmd.Flags().StringVar(&options.LeaderAddress, "leader-address", "",
+		"The address of the IPAM leader, used by the other replicas to follow its state. "+
+			"If empty, the replicas initialize the state from scratch when elected.")
 
 	utilruntime.Must(cmd.MarkFlagRequired("pod-name"))
 
@@ -123,38 +152,55 @@ func run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	var liqoIPAM *ipam.LiqoIPAM
 	if options.EnableLeaderElection {
-		if leader, err := leaderelection.Blocking(ctx, cfg, record.NewBroadcaster(), &leaderelection.Opts{
-			PodInfo: leaderelection.PodInfo{
-				PodName:        options.PodName,
-				Namespace:      options.LeaderElectionNamespace,
-				DeploymentName: &options.DeploymentName,
-			},
-			Client:            cl,
-			LeaderElectorName: options.LeaderElectionName,
-			LeaseDuration:     options.LeaseDuration,
-			RenewDeadline:     options.RenewDeadline,
-			RetryPeriod:       options.RetryPeriod,
-			LabelLeader:       true,
-		}); err != nil {
+		if liqoIPAM, err = runReplica(ctx, cfg, cl); err != nil {
 			return err
-		} else if !leader {
-			klog.Error("IPAM is not the leader, exiting")
-			os.Exit(1)
 		}
+	} else if liqoIPAM, err = ipam.New(ctx, cl, &options.ServerOpts); err != nil {
+		return err
 	}
 
-	liqoIPAM, err := ipam.New(ctx, cl, &options.ServerOpts)
+	// Start reconciling the pools managed at runtime and collecting the leaked allocations, now that the IPAM has been initialized.
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: options.MetricsAddress},
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to create the manager: %w", err)
+	}
+	if err := ipam.NewPoolReconciler(mgr.GetClient(), liqoIPAM).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to setup the pool reconciler: %w", err)
+	}
+
+	leakCollector := ipam.NewLeakCollector(cl, liqoIPAM, mgr.GetEventRecorderFor("liqo-ipam"), &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  options.PodNamespace,
+		Name:       options.PodName,
+	}, options.LeakCollectorOpts)
+	if err := mgr.Add(leakCollector); err != nil {
+		return fmt.Errorf("unable to setup the leak collector: %w", err)
+	}
+	if err := metrics.Registry.Register(leakCollector); err != nil {
+		return fmt.Errorf("unable to register the leak collector metrics: %w", err)
 	}
+	if err := ipam.RegisterSnapshotMetrics(metrics.Registry); err != nil {
+		return fmt.Errorf("unable to register the snapshot metrics: %w", err)
+	}
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			klog.Errorf("unable to start the manager: %v", err)
+			os.Exit(1)
+		}
+	}()
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", options.ServerOpts.Port))
 	if err != nil {
 		return err
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpcutils.LeaderFollowingServerOptions()...)
 
 	// Register health service
 	grpc_health_v1.RegisterHealthServer(server, liqoIPAM.HealthServer)
@@ -169,3 +215,60 @@ func run(cmd *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+// runReplica runs the IPAM as a replica, following the state of the leader until it gets elected.
+// Once elected, it starts the IPAM from the replicated state, which is reconciled with the cluster.
+func runReplica(ctx context.Context, cfg *rest.Config, cl client.Client) (*ipam.LiqoIPAM, error) {
+	liqoIPAM, err := ipam.NewReplica(cl, &options.ServerOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	elected := make(chan struct{})
+	elector, err := leaderelection.Init(&leaderelection.Opts{
+		PodInfo: leaderelection.PodInfo{
+			PodName:        options.PodName,
+			Namespace:      options.LeaderElectionNamespace,
+			DeploymentName: &options.DeploymentName,
+		},
+		Client:            cl,
+		LeaderElectorName: options.LeaderElectionName,
+		LeaseDuration:     options.LeaseDuration,
+		RenewDeadline:     options.RenewDeadline,
+		RetryPeriod:       options.RetryPeriod,
+		InitCallback:      func() { close(elected) },
+		StopCallback: func() {
+			// The state of a former leader cannot be trusted anymore: restart as a follower.
+			klog.Error("IPAM is not the leader anymore, exiting")
+			os.Exit(1)
+		},
+		LabelLeader: true,
+	}, cfg, record.NewBroadcaster())
+	if err != nil {
+		return nil, err
+	}
+	go leaderelection.Run(ctx, elector)
+
+	if options.LeaderAddress != "" {
+		conn, err := grpc.NewClient(options.LeaderAddress, append(grpcutils.LeaderFollowingDialOptions(ipam.IsRetryable),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a connection to the IPAM leader %q: %w", options.LeaderAddress, err)
+		}
+		defer conn.Close()
+
+		klog.Infof("Following the IPAM leader %q", options.LeaderAddress)
+		liqoIPAM.FollowUntilElected(ctx, ipam.NewIPAMClient(conn), options.PodName, elected)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-elected:
+	}
+
+	if err := liqoIPAM.Start(ctx); err != nil {
+		return nil, err
+	}
+	return liqoIPAM, nil
+}
@@ -173,7 +173,8 @@ func run(cmd *cobra.Command, _ []string) error {
 		var ipamClient ipam.IPAMClient
 		if opts.IPAMServer != "" {
 			klog.Infof("connecting to the IPAM server %q", opts.IPAMServer)
-			conn, err := grpc.NewClient(opts.IPAMServer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			conn, err := grpc.NewClient(opts.IPAMServer, append(grpcutils.LeaderFollowingDialOptions(ipam.IsRetryable),
+				grpc.WithTransportCredentials(insecure.NewCredentials()))...)
 			if err != nil {
 				return fmt.Errorf("failed to establish a connection to the IPAM %q: %w", opts.IPAMServer, err)
 			}
@@ -29,6 +29,8 @@ import (
 	liqocontrollermanager "github.com/liqotech/liqo/pkg/liqo-controller-manager"
 	clientoperator "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/client-operator"
 	configuration "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/configuration"
+	ipsecgatewaycontrollers "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/ipsec"
+	plaingatewaycontrollers "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/plain"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/remapping"
 	externalnetworkroute "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/route"
 	serveroperator "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/server-operator"
@@ -54,14 +56,18 @@ type NetworkingOption struct {
 	LiqoNamespace string
 	IpamClient    ipam.IPAMClient
 
-	GatewayServerResources         []string
-	GatewayClientResources         []string
-	WgGatewayServerClusterRoleName string
-	WgGatewayClientClusterRoleName string
-	NetworkWorkers                 int
-	IPWorkers                      int
-	FabricFullMasquerade           bool
-	GwmasqbypassEnabled            bool
+	GatewayServerResources            []string
+	GatewayClientResources            []string
+	WgGatewayServerClusterRoleName    string
+	WgGatewayClientClusterRoleName    string
+	IPsecGatewayServerClusterRoleName string
+	IPsecGatewayClientClusterRoleName string
+	PlainGatewayServerClusterRoleName string
+	PlainGatewayClientClusterRoleName string
+	NetworkWorkers                    int
+	IPWorkers                         int
+	FabricFullMasquerade              bool
+	GwmasqbypassEnabled               bool
 
 	GenevePort uint16
 }
@@ -76,14 +82,18 @@ func NewNetworkingOption(factory *dynamicutils.RunnableFactory, dynClient dynami
 		LiqoNamespace: opts.LiqoNamespace,
 		IpamClient:    ipamClient,
 
-		GatewayServerResources:         opts.GatewayServerResources.StringList,
-		GatewayClientResources:         opts.GatewayClientResources.StringList,
-		WgGatewayServerClusterRoleName: opts.WgGatewayServerClusterRoleName,
-		WgGatewayClientClusterRoleName: opts.WgGatewayClientClusterRoleName,
-		NetworkWorkers:                 opts.NetworkWorkers,
-		IPWorkers:                      opts.IPWorkers,
-		FabricFullMasquerade:           opts.FabricFullMasqueradeEnabled,
-		GwmasqbypassEnabled:            opts.GwmasqbypassEnabled,
+		GatewayServerResources:            opts.GatewayServerResources.StringList,
+		GatewayClientResources:            opts.GatewayClientResources.StringList,
+		WgGatewayServerClusterRoleName:    opts.WgGatewayServerClusterRoleName,
+		WgGatewayClientClusterRoleName:    opts.WgGatewayClientClusterRoleName,
+		IPsecGatewayServerClusterRoleName: opts.IPsecGatewayServerClusterRoleName,
+		IPsecGatewayClientClusterRoleName: opts.IPsecGatewayClientClusterRoleName,
+		PlainGatewayServerClusterRoleName: opts.PlainGatewayServerClusterRoleName,
+		PlainGatewayClientClusterRoleName: opts.PlainGatewayClientClusterRoleName,
+		NetworkWorkers:                    opts.NetworkWorkers,
+		IPWorkers:                         opts.IPWorkers,
+		FabricFullMasquerade:              opts.FabricFullMasqueradeEnabled,
+		GwmasqbypassEnabled:               opts.GwmasqbypassEnabled,
 
 		GenevePort: opts.GenevePort,
 	}
@@ -146,6 +156,38 @@ func SetupNetworkingModule(ctx context.Context, mgr manager.Manager, uncachedCli
 		return err
 	}
 
+	ipsecServerRec := ipsecgatewaycontrollers.NewIPsecGatewayServerReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("ipsec-gateway-server-controller"),
+		opts.IPsecGatewayServerClusterRoleName)
+	if err := ipsecServerRec.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to start the ipsecGatewayServerReconciler: %v", err)
+		return err
+	}
+
+	ipsecClientRec := ipsecgatewaycontrollers.NewIPsecGatewayClientReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("ipsec-gateway-client-controller"),
+		opts.IPsecGatewayClientClusterRoleName)
+	if err := ipsecClientRec.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to start the ipsecGatewayClientReconciler: %v", err)
+		return err
+	}
+
+	plainServerRec := plaingatewaycontrollers.NewPlainGatewayServerReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("plain-gateway-server-controller"),
+		opts.PlainGatewayServerClusterRoleName)
+	if err := plainServerRec.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to start the plainGatewayServerReconciler: %v", err)
+		return err
+	}
+
+	plainClientRec := plaingatewaycontrollers.NewPlainGatewayClientReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("plain-gateway-client-controller"),
+		opts.PlainGatewayClientClusterRoleName)
+	if err := plainClientRec.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to start the plainGatewayClientReconciler: %v", err)
+		return err
+	}
+
 	serverReconciler := serveroperator.NewServerReconciler(mgr.GetClient(),
 		opts.DynClient, opts.Factory, mgr.GetScheme(),
 		mgr.GetEventRecorderFor("server-controller"),
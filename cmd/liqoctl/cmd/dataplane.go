@@ -0,0 +1,87 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	liqodataplane "github.com/liqotech/liqo/pkg/dataplane"
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
+	"github.com/liqotech/liqo/pkg/liqoctl/dataplane"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	"github.com/liqotech/liqo/pkg/utils/args"
+)
+
+const liqoctlDataplaneLongHelp = `Render the dataplane configuration enforced by a fabric node or a gateway.
+
+This command retrieves the FirewallConfigurations and RouteConfigurations selected
+by the given host, and renders the nftables ruleset and the ip rules and routes
+they translate to.
+
+When the state captured from the host is provided (i.e., the output of
+"nft list ruleset", "ip rule show" and "ip route show table all"), it is compared
+with the rendered one, and the divergences are printed. In this case, the command
+fails if any divergence is detected.
+
+Examples:
+  $ {{ .Executable }} dataplane --host-type fabric --node worker-1
+or
+  $ {{ .Executable }} dataplane --host-type gateway --node worker-1 --remote-cluster-id cluster-2
+or
+  $ {{ .Executable }} dataplane --host-type fabric --node worker-1 \
+      --nft-ruleset ruleset.txt --ip-rules rules.txt --ip-routes routes.txt
+`
+
+func newDataplaneCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	hostType := args.NewEnum([]string{string(liqodataplane.HostTypeFabric), string(liqodataplane.HostTypeGateway)},
+		string(liqodataplane.HostTypeFabric))
+
+	options := &dataplane.Options{Factory: f}
+	cmd := &cobra.Command{
+		Use:   "dataplane",
+		Short: "Render the dataplane configuration enforced by a host",
+		Long:  liqoctlDataplaneLongHelp,
+		Args:  cobra.NoArgs,
+
+		PreRun: func(_ *cobra.Command, _ []string) {
+			options.HostType = liqodataplane.HostType(hostType.Value)
+		},
+
+		Run: func(_ *cobra.Command, _ []string) {
+			output.ExitOnErr(options.Run(ctx))
+		},
+	}
+
+	cmd.Flags().Var(hostType, "host-type", "The type of host enforcing the configuration. Supported types: fabric, gateway")
+	cmd.Flags().StringVar(&options.NodeName, "node", "", "The node hosting the fabric or the gateway")
+	cmd.Flags().StringVar(&options.RemoteClusterID, "remote-cluster-id", "",
+		"The cluster ID of the remote cluster the gateway is connected to (required for gateways)")
+	cmd.Flags().StringVar(&options.NftRulesetFile, "nft-ruleset", "",
+		"The file containing the captured nftables ruleset (i.e., the output of \"nft list ruleset\")")
+	cmd.Flags().StringVar(&options.IPRulesFile, "ip-rules", "",
+		"The file containing the captured ip rules (i.e., the output of \"ip rule show\")")
+	cmd.Flags().StringVar(&options.IPRoutesFile, "ip-routes", "",
+		"The file containing the captured ip routes (i.e., the output of \"ip route show table all\")")
+
+	f.Printer.CheckErr(cmd.MarkFlagRequired("node"))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("host-type", completion.Enumeration(hostType.Allowed)))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("node", completion.Nodes(ctx, f, completion.NoLimit)))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("remote-cluster-id", completion.ClusterIDs(ctx, f, completion.NoLimit)))
+
+	return cmd
+}
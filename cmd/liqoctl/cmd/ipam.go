@@ -0,0 +1,78 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/ipam"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	"github.com/liqotech/liqo/pkg/utils/args"
+)
+
+const liqoctlIpamLongHelp = `Inspect the allocations of the Liqo IPAM.
+
+This command retrieves the state of the IPAM server (through a port-forward to
+the IPAM pod, or to the current leader in case of multiple replicas), and shows
+the pools, the allocated networks and IPs, together with the objects they have
+been acquired for and whether they are still within the sync grace period.
+
+Alternatively, the IPAM trees can be exported in JSON, Graphviz DOT or Mermaid
+format, to be rendered with the corresponding tools.
+
+Examples:
+  $ {{ .Executable }} ipam
+or
+  $ {{ .Executable }} ipam --pool 10.80.0.0/12
+export the trees as a Graphviz image
+  $ {{ .Executable }} ipam --output dot | dot -Tsvg > ipam.svg
+`
+
+func newIpamCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	outputFormat := args.NewEnum([]string{
+		string(ipam.OutputTable), string(ipam.OutputJSON), string(ipam.OutputDOT), string(ipam.OutputMermaid),
+	}, string(ipam.OutputTable))
+
+	options := &ipam.Options{Factory: f}
+	cmd := &cobra.Command{
+		Use:   "ipam",
+		Short: "Inspect the allocations of the Liqo IPAM",
+		Long:  liqoctlIpamLongHelp,
+		Args:  cobra.NoArgs,
+
+		PreRun: func(_ *cobra.Command, _ []string) {
+			options.Format = ipam.OutputFormat(outputFormat.Value)
+		},
+
+		Run: func(_ *cobra.Command, _ []string) {
+			output.ExitOnErr(options.Run(ctx))
+		},
+	}
+
+	f.AddLiqoNamespaceFlag(cmd.Flags())
+	cmd.Flags().StringVar(&options.Pool, "pool", "", "Show only the allocations of the given pool")
+	cmd.Flags().VarP(outputFormat, "output", "o", "Output format. Supported formats: table, json, dot, mermaid")
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 30*time.Second, "Timeout for the retrieval of the IPAM state")
+
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc(factory.FlagNamespace, completion.Namespaces(ctx, f, completion.NoLimit)))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Enumeration(outputFormat.Allowed)))
+
+	return cmd
+}
@@ -138,6 +138,9 @@ func newNetworkConnectCommand(ctx context.Context, options *network.Options) *co
 		"Force the NodePort of the Gateway Server service. Leave empty to let Kubernetes allocate a random NodePort")
 	cmd.Flags().StringVar(&options.ServerServiceLoadBalancerIP, "gw-server-service-loadbalancerip", "",
 		"Force LoadBalancer IP of the Gateway Server service. Leave empty to use the one provided by the LoadBalancer provider")
+	cmd.Flags().Var(&options.ServerAllowedClientCIDRs, "gw-server-allowed-client-cidrs",
+		"CIDRs the Gateway Client is allowed to connect from (e.g., the public address of the client cluster). "+
+			"Required by the plain Gateway Server, which otherwise refuses all the clients")
 
 	// Client flags
 	cmd.Flags().StringVar(&options.ClientGatewayType, "gw-client-type", forge.DefaultGwClientType,
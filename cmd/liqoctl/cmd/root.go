@@ -138,6 +138,8 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 	utils.AddCommand(cmd, get.NewGetCommand(ctx, liqoResources, f))
 	utils.AddCommand(cmd, delete.NewDeleteCommand(ctx, liqoResources, f))
 	utils.AddCommand(cmd, newInfoCommand(ctx, f))
+	utils.AddCommand(cmd, newIpamCommand(ctx, f))
+	utils.AddCommand(cmd, newDataplaneCommand(ctx, f))
 	utils.AddCommand(cmd, newTestCommand(ctx, f))
 
 	return cmd
@@ -38,6 +38,11 @@ The command needs to be run on the cluster that will act as the consumer,
 and it requires the kubeconfig of the remote cluster that will act as the providers.
 The consumer cluster must be peered with the providers, previously using "{{ .Executable }} peer".
 
+With the --benchmark flag, the command measures instead the throughput, the TCP retransmits and the latency
+distribution between the gateways of the consumer and of each provider (and, with --benchmark-pods, between pods
+running in short-lived pods in different clusters), through the traffic generator built into the gateway image,
+which is executed in the gateway pods themselves and in pods deleted once the benchmark completes.
+
 
 Examples:
   $ {{ .Executable }} test network --remote-kubeconfigs $HOME/.kube/config2,$HOME/.kube/config3
@@ -49,6 +54,8 @@ or
   $ {{ .Executable }} test network --remote-kubeconfigs $HOME/.kube/config2,$HOME/.kube/config3 --ip
 or
   $ {{ .Executable }} test network --remote-kubeconfigs $HOME/.kube/config2,$HOME/.kube/config3 --lb
+or
+  $ {{ .Executable }} test network --remote-kubeconfigs $HOME/.kube/config2 --benchmark --benchmark-pods --benchmark-streams 4
 `
 
 func newTestCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
@@ -29,6 +29,7 @@ const (
 	CtrlSecretWebhook       = "secret_webhook"
 
 	// Networking.
+	CtrlBandwidthLimit         = "bandwidthlimit"
 	CtrlConfigurationExternal  = "configuration_external"
 	CtrlConfigurationInternal  = "configuration_internal"
 	CtrlConfigurationRemapping = "configuration_remapping"
@@ -45,11 +46,16 @@ const (
 	CtrlInternalNodeRoute      = "internalnode_route"
 	CtrlIP                     = "ip"
 	CtrlIPRemapping            = "ip_remapping"
+	CtrlIPsecGatewayClient     = "ipsecgatewayclient"
+	CtrlIPsecGatewayServer     = "ipsecgatewayserver"
 	CtrlNetwork                = "network"
 	CtrlNode                   = "node"
+	CtrlPlainGatewayClient     = "plaingatewayclient"
+	CtrlPlainGatewayServer     = "plaingatewayserver"
 	CtrlPodGateway             = "pod_gateway"
 	CtrlPodGwMasq              = "pod_gw_masq"
 	CtrlPodInternalNet         = "pod_internalnet"
+	CtrlPool                   = "pool"
 	CtrlPublicKey              = "publickey"
 	CtrlRouteConfiguration     = "routeconfiguration"
 	CtrlWGGatewayClient        = "wggatewayclient"
@@ -36,6 +36,19 @@ const (
 	PrivateKeyField = "privateKey"
 	// PublicKeyField is the data field of the secrets containing public keys.
 	PublicKeyField = "publicKey"
+	// PresharedKeyField is the optional data field of the secrets containing the key shared with the remote gateway.
+	PresharedKeyField = "presharedKey"
+	// PreviousPrivateKeyField is the data field of the secrets containing the private key replaced by the last rotation.
+	PreviousPrivateKeyField = "previousPrivateKey"
+	// PreviousPublicKeyField is the data field of the secrets containing the public key replaced by the last rotation.
+	PreviousPublicKeyField = "previousPublicKey"
+	// PreviousKeyExpirationField is the data field of the secrets containing the time (RFC 3339) until which
+	// the keys replaced by the last rotation are still accepted.
+	PreviousKeyExpirationField = "previousKeyExpiration"
+	// KeyVersionField is the data field of the secrets containing the version of the keys, incremented at every rotation.
+	KeyVersionField = "keyVersion"
+	// KeyRotationTimestampAnnotation is the annotation of the keys secrets containing the time of the last rotation.
+	KeyRotationTimestampAnnotation = "networking.liqo.io/key-rotation-timestamp"
 
 	// ClusterRoleBindingFinalizer is the finalizer added ti the owner when a ClusterRoleBinding is created.
 	ClusterRoleBindingFinalizer = "networking.liqo.io/clusterrolebinding"
@@ -43,4 +56,10 @@ const (
 	GatewayNameLabel = "networking.liqo.io/gateway-name"
 	// GatewayNamespaceLabel is the label added to a resource to identify the namespace of the Gateway it belongs to.
 	GatewayNamespaceLabel = "networking.liqo.io/gateway-namespace"
+	// GatewayActiveLabel is the label added to the active gateway pods.
+	GatewayActiveLabel = "networking.liqo.io/active"
+	// GatewayActiveLabelValue is the value of the label added to the active gateway pods.
+	GatewayActiveLabelValue = "true"
+	// GatewayReplicaIndexLabel is the label added to the pods of active-active gateways with their replica index.
+	GatewayReplicaIndexLabel = "networking.liqo.io/replica-index"
 )
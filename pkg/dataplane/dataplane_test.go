@@ -0,0 +1,218 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	"github.com/liqotech/liqo/pkg/dataplane"
+	routeutils "github.com/liqotech/liqo/pkg/utils/route"
+)
+
+var _ = Describe("Firewall rendering", func() {
+	var fwcfg networkingv1beta1.FirewallConfiguration
+
+	BeforeEach(func() {
+		fwcfg = networkingv1beta1.FirewallConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "fwcfg", Namespace: "liqo"},
+			Spec: networkingv1beta1.FirewallConfigurationSpec{
+				Table: firewallapi.Table{
+					Name:   ptr.To("liqo-test"),
+					Family: ptr.To(firewallapi.TableFamilyIPv4),
+					Sets: []firewallapi.Set{{
+						Name:     "allowed",
+						KeyType:  firewallapi.SetDataTypeIPAddr,
+						Interval: true,
+						Elements: []firewallapi.SetElement{{Key: "10.0.0.0/8"}, {Key: "192.168.0.0/16"}},
+					}},
+					Chains: []firewallapi.Chain{{
+						Name:     ptr.To("forward"),
+						Type:     firewallapi.ChainTypeFilter,
+						Hook:     ptr.To(firewallapi.ChainHookForward),
+						Policy:   ptr.To(firewallapi.ChainPolicyAccept),
+						Priority: ptr.To(firewallapi.ChainPriorityFilter),
+						Rules: firewallapi.RulesSet{FilterRules: []firewallapi.FilterRule{{
+							Name: ptr.To("allow-web"),
+							Match: []firewallapi.Match{{
+								Op:    firewallapi.MatchOperationEq,
+								IP:    &firewallapi.MatchIP{Value: "@allowed", Position: firewallapi.MatchPositionSrc},
+								Proto: &firewallapi.MatchProto{Value: firewallapi.L4ProtoTCP},
+								Port:  &firewallapi.MatchPort{Value: "80", Position: firewallapi.MatchPositionDst},
+							}},
+							Counter: true,
+							Action:  firewallapi.ActionAccept,
+						}}},
+					}},
+				},
+			},
+		}
+	})
+
+	It("should render the ruleset in the nft syntax", func() {
+		ruleset, err := dataplane.RenderFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ruleset).To(Equal(`table ip liqo-test {
+	set allowed {
+		type ipv4_addr
+		flags interval
+		elements = { 10.0.0.0/8, 192.168.0.0/16 }
+	}
+	chain forward {
+		type filter hook forward priority filter; policy accept;
+
+		ip saddr @allowed tcp dport 80 counter accept comment "allow-web"
+	}
+}
+`))
+	})
+
+	It("should fail if the table family is unknown", func() {
+		fwcfg.Spec.Table.Family = ptr.To(firewallapi.TableFamily("unknown"))
+		_, err := dataplane.RenderFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not report any difference if the captured ruleset matches", func() {
+		captured := `table ip other {
+}
+table ip liqo-test {
+	set allowed {
+		type ipv4_addr
+		flags interval
+		elements = { 10.0.0.0/8, 192.168.0.0/16 }
+	}
+
+	chain forward {
+		type filter hook forward priority filter; policy accept;
+		ip saddr @allowed tcp dport 80 counter packets 12 bytes 3456 accept comment "allow-web" # handle 4
+	}
+}
+`
+		diff, err := dataplane.DiffFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg}, captured)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff).To(BeEmpty())
+	})
+
+	It("should report the missing rules", func() {
+		captured := `table ip liqo-test {
+	set allowed {
+		type ipv4_addr
+		flags interval
+		elements = { 10.0.0.0/8, 192.168.0.0/16 }
+	}
+	chain forward {
+		type filter hook forward priority filter; policy accept;
+	}
+}
+`
+		diff, err := dataplane.DiffFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg}, captured)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff).To(ContainSubstring(`+ip saddr @allowed tcp dport 80 counter accept comment "allow-web"`))
+	})
+
+	It("should report the whole table if missing", func() {
+		diff, err := dataplane.DiffFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff).To(ContainSubstring("+table ip liqo-test {"))
+	})
+})
+
+var _ = Describe("Route rendering", func() {
+	var rcfg networkingv1beta1.RouteConfiguration
+
+	BeforeEach(func() {
+		rcfg = networkingv1beta1.RouteConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "rcfg", Namespace: "liqo"},
+			Spec: networkingv1beta1.RouteConfigurationSpec{
+				Table: networkingv1beta1.Table{
+					Name: "liqo-test",
+					Rules: []networkingv1beta1.Rule{{
+						Dst:    ptr.To(networkingv1beta1.CIDR("10.70.0.0/16")),
+						FwMark: ptr.To(2),
+						Routes: []networkingv1beta1.Route{
+							{
+								Dst: ptr.To(networkingv1beta1.CIDR("10.70.0.0/16")),
+								Gw:  ptr.To(networkingv1beta1.IP("10.80.0.1")),
+								Dev: ptr.To("liqo-tunnel"),
+							},
+							{
+								Dst: ptr.To(networkingv1beta1.CIDR("10.80.0.1/32")),
+								NextHops: []networkingv1beta1.NextHop{
+									{Gw: ptr.To(networkingv1beta1.IP("10.0.0.1")), Weight: ptr.To(2)},
+									{Gw: ptr.To(networkingv1beta1.IP("10.0.0.2"))},
+								},
+							},
+						},
+					}},
+				},
+			},
+		}
+	})
+
+	It("should render the ip commands", func() {
+		commands, err := dataplane.RenderRoutes([]networkingv1beta1.RouteConfiguration{rcfg})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(commands).To(Equal(`ip rule add to 10.70.0.0/16 fwmark 0x2 lookup liqo-test
+ip route add 10.70.0.0/16 via 10.80.0.1 dev liqo-tunnel table liqo-test
+ip route add 10.80.0.1 table liqo-test nexthop via 10.0.0.1 weight 2 nexthop via 10.0.0.2 weight 1
+`))
+	})
+
+	It("should not report any difference if the captured state matches", func() {
+		rules := `0:	from all lookup local
+32765:	from all to 10.70.0.0/16 fwmark 0x2 lookup liqo-test
+32766:	from all lookup main
+`
+		routes := `10.70.0.0/16 via 10.80.0.1 dev liqo-tunnel table liqo-test proto boot
+10.80.0.1 table liqo-test proto boot
+	nexthop via 10.0.0.1 dev eth0 weight 2
+	nexthop via 10.0.0.2 dev eth0 weight 1
+default via 172.18.0.1 dev eth0
+local 127.0.0.1 dev lo table local proto kernel scope host src 127.0.0.1
+`
+		diff, err := dataplane.DiffRoutes([]networkingv1beta1.RouteConfiguration{rcfg}, rules, routes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Empty()).To(BeTrue(), diff.String())
+	})
+
+	It("should identify the tables by ID as well", func() {
+		id := routeutils.TableID("liqo-test")
+		rules := "32765:	from all to 10.70.0.0/16 fwmark 0x2 lookup " + itoa(id) + "\n"
+		routes := "10.70.0.0/16 via 10.80.0.9 dev liqo-tunnel table " + itoa(id) + "\n"
+		diff, err := dataplane.DiffRoutes([]networkingv1beta1.RouteConfiguration{rcfg}, rules, routes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.MissingRules).To(BeEmpty())
+		Expect(diff.MissingRoutes).To(ConsistOf(
+			"ip route add 10.70.0.0/16 via 10.80.0.1 dev liqo-tunnel table liqo-test",
+			"ip route add 10.80.0.1 table liqo-test nexthop via 10.0.0.1 weight 2 nexthop via 10.0.0.2 weight 1",
+		))
+		Expect(diff.UnexpectedRoutes).To(ConsistOf("ip route add 10.70.0.0/16 via 10.80.0.9 dev liqo-tunnel table liqo-test"))
+	})
+
+	It("should report the unexpected rules", func() {
+		rules := `32764:	from 10.0.0.0/8 lookup liqo-test
+32765:	from all to 10.70.0.0/16 fwmark 0x2 lookup liqo-test
+`
+		diff, err := dataplane.DiffRoutes([]networkingv1beta1.RouteConfiguration{rcfg}, rules, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.UnexpectedRules).To(ConsistOf("ip rule add from 10.0.0.0/8 lookup liqo-test"))
+		Expect(diff.MissingRules).To(BeEmpty())
+	})
+})
@@ -0,0 +1,202 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	routeutils "github.com/liqotech/liqo/pkg/utils/route"
+)
+
+var (
+	counterRegex = regexp.MustCompile(`counter packets \d+ bytes \d+`)
+	handleRegex  = regexp.MustCompile(`\s*# handle \d+$`)
+)
+
+// DiffFirewall compares the nftables ruleset programmed by the given FirewallConfigurations with the captured one
+// (i.e., the output of "nft list ruleset"), and returns a unified diff for each table which diverges.
+// The tables not managed by the given FirewallConfigurations are ignored. An empty string means no divergence.
+func DiffFirewall(fwcfgs []networkingv1beta1.FirewallConfiguration, captured string) (string, error) {
+	capturedTables := splitTables(captured)
+
+	var sb strings.Builder
+	for _, fwcfg := range sortFirewallConfigurations(fwcfgs) {
+		header, err := tableHeader(&fwcfg.Spec.Table)
+		if err != nil {
+			return "", fmt.Errorf("firewallconfiguration %s/%s: %w", fwcfg.Namespace, fwcfg.Name, err)
+		}
+		desired, err := renderTable(&fwcfg.Spec.Table)
+		if err != nil {
+			return "", fmt.Errorf("firewallconfiguration %s/%s: %w", fwcfg.Namespace, fwcfg.Name, err)
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        normalizeRuleset(capturedTables[header]),
+			B:        normalizeRuleset(desired),
+			FromFile: "kernel",
+			ToFile:   fmt.Sprintf("%s/%s", fwcfg.Namespace, fwcfg.Name),
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("firewallconfiguration %s/%s: %w", fwcfg.Namespace, fwcfg.Name, err)
+		}
+		sb.WriteString(diff)
+	}
+	return sb.String(), nil
+}
+
+// splitTables splits a captured nftables ruleset into the contained tables, indexed by their header.
+func splitTables(ruleset string) map[string]string {
+	tables := map[string]string{}
+	var header string
+	var sb strings.Builder
+	depth := 0
+	for _, line := range strings.Split(ruleset, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if depth == 0 {
+			if !strings.HasPrefix(trimmed, "table ") || !strings.HasSuffix(trimmed, "{") {
+				continue
+			}
+			header = strings.Join(strings.Fields(trimmed), " ")
+			sb.Reset()
+		}
+		sb.WriteString(line + "\n")
+		depth += strings.Count(trimmed, "{") - strings.Count(trimmed, "}")
+		if depth <= 0 {
+			tables[header] = sb.String()
+			depth = 0
+		}
+	}
+	return tables
+}
+
+// normalizeRuleset splits a ruleset in lines, removing the indentation, the blank lines, the rule handles
+// and the values of the counters, which are not relevant for the comparison.
+func normalizeRuleset(ruleset string) []string {
+	var lines []string
+	for _, line := range strings.Split(ruleset, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = counterRegex.ReplaceAllString(line, "counter")
+		line = handleRegex.ReplaceAllString(line, "")
+		lines = append(lines, line+"\n")
+	}
+	return lines
+}
+
+// RoutesDiff contains the divergences between the desired and the captured ip rules and routes.
+type RoutesDiff struct {
+	// MissingRules are the desired rules which are not present in the captured state.
+	MissingRules []string
+	// UnexpectedRules are the captured rules pointing to a Liqo table which are not desired.
+	UnexpectedRules []string
+	// MissingRoutes are the desired routes which are not present in the captured state.
+	MissingRoutes []string
+	// UnexpectedRoutes are the captured routes in a Liqo table which are not desired.
+	UnexpectedRoutes []string
+}
+
+// Empty returns whether no divergence has been detected.
+func (d *RoutesDiff) Empty() bool {
+	return len(d.MissingRules) == 0 && len(d.UnexpectedRules) == 0 &&
+		len(d.MissingRoutes) == 0 && len(d.UnexpectedRoutes) == 0
+}
+
+// String renders the divergences, prefixing the missing entries with "+" and the unexpected ones with "-".
+func (d *RoutesDiff) String() string {
+	var sb strings.Builder
+	for _, entries := range []struct {
+		prefix string
+		lines  []string
+	}{{"-", d.UnexpectedRules}, {"+", d.MissingRules}, {"-", d.UnexpectedRoutes}, {"+", d.MissingRoutes}} {
+		for _, line := range entries.lines {
+			fmt.Fprintf(&sb, "%s %s\n", entries.prefix, line)
+		}
+	}
+	return sb.String()
+}
+
+// DiffRoutes compares the ip rules and routes programmed by the given RouteConfigurations with the captured ones
+// (i.e., the output of "ip rule show" and "ip route show table all"). Only the entries referring to the tables
+// of the given RouteConfigurations are taken into account.
+func DiffRoutes(rcfgs []networkingv1beta1.RouteConfiguration, capturedRules, capturedRoutes string) (*RoutesDiff, error) {
+	desiredRules, desiredRoutes, err := desiredRoutes(rcfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := map[string]string{}
+	for i := range rcfgs {
+		name := rcfgs[i].Spec.Table.Name
+		tables[name] = name
+		tables[strconv.FormatUint(uint64(routeutils.TableID(name)), 10)] = name
+	}
+	currentRules := parseIPRules(capturedRules, tables)
+	currentRoutes := parseIPRoutes(capturedRoutes, tables)
+
+	diff := &RoutesDiff{}
+	found := make([]bool, len(currentRules))
+	for i := range desiredRules {
+		if !matchRule(&desiredRules[i], currentRules, found) {
+			diff.MissingRules = append(diff.MissingRules, desiredRules[i].String())
+		}
+	}
+	for i := range currentRules {
+		if !found[i] {
+			diff.UnexpectedRules = append(diff.UnexpectedRules, currentRules[i].String())
+		}
+	}
+
+	found = make([]bool, len(currentRoutes))
+	for i := range desiredRoutes {
+		if !matchRoute(&desiredRoutes[i], currentRoutes, found) {
+			diff.MissingRoutes = append(diff.MissingRoutes, desiredRoutes[i].String())
+		}
+	}
+	for i := range currentRoutes {
+		if !found[i] {
+			diff.UnexpectedRoutes = append(diff.UnexpectedRoutes, currentRoutes[i].String())
+		}
+	}
+	return diff, nil
+}
+
+func matchRule(rule *ipRule, current []ipRule, found []bool) bool {
+	for i := range current {
+		if !found[i] && *rule == current[i] {
+			found[i] = true
+			return true
+		}
+	}
+	return false
+}
+
+func matchRoute(route *ipRoute, current []ipRoute, found []bool) bool {
+	for i := range current {
+		if !found[i] && route.matches(&current[i]) {
+			found[i] = true
+			return true
+		}
+	}
+	return false
+}
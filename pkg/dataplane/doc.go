@@ -0,0 +1,18 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dataplane renders the nftables ruleset and the ip rules and routes programmed by the firewall and route controllers
+// for a given set of FirewallConfigurations and RouteConfigurations, and compares them with the state captured from a host.
+// It does not depend on the kernel interfaces, hence it can be used also by liqoctl on any platform.
+package dataplane
@@ -0,0 +1,257 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/google/nftables"
+	"k8s.io/utils/ptr"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+// RenderFirewall renders the nftables ruleset programmed by the given FirewallConfigurations, using the nft syntax.
+func RenderFirewall(fwcfgs []networkingv1beta1.FirewallConfiguration) (string, error) {
+	var sb strings.Builder
+	for _, fwcfg := range sortFirewallConfigurations(fwcfgs) {
+		table, err := renderTable(&fwcfg.Spec.Table)
+		if err != nil {
+			return "", fmt.Errorf("firewallconfiguration %s/%s: %w", fwcfg.Namespace, fwcfg.Name, err)
+		}
+		sb.WriteString(table)
+	}
+	return sb.String(), nil
+}
+
+func sortFirewallConfigurations(fwcfgs []networkingv1beta1.FirewallConfiguration) []networkingv1beta1.FirewallConfiguration {
+	sorted := make([]networkingv1beta1.FirewallConfiguration, len(fwcfgs))
+	copy(sorted, fwcfgs)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// tableHeader returns the first line of the given table, as printed by nft.
+func tableHeader(table *firewallapi.Table) (string, error) {
+	if table.Name == nil || table.Family == nil {
+		return "", fmt.Errorf("table name or family not defined")
+	}
+	family, err := renderFamily(*table.Family)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("table %s %s {", family, *table.Name), nil
+}
+
+func renderTable(table *firewallapi.Table) (string, error) {
+	header, err := tableHeader(table)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	nftTable := &nftables.Table{Name: *table.Name, Family: firewallutils.GetTableFamily(*table.Family)}
+	for i := range table.Sets {
+		// The set is forged only to reject the ones which the firewall controller would fail to program.
+		if _, _, err := firewallutils.ForgeSet(&table.Sets[i], nftTable); err != nil {
+			return "", err
+		}
+		sb.WriteString(renderSet(&table.Sets[i]))
+	}
+	for i := range table.Chains {
+		chain, err := renderChain(&table.Chains[i], table.Sets, nftTable)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(chain)
+	}
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+func renderFamily(family firewallapi.TableFamily) (string, error) {
+	switch family {
+	case firewallapi.TableFamilyIPv4:
+		return "ip", nil
+	case firewallapi.TableFamilyIPv6:
+		return "ip6", nil
+	case firewallapi.TableFamilyINet:
+		return "inet", nil
+	case firewallapi.TableFamilyARP:
+		return "arp", nil
+	case firewallapi.TableFamilyBridge:
+		return "bridge", nil
+	case firewallapi.TableFamilyNetdev:
+		return "netdev", nil
+	default:
+		return "", fmt.Errorf("unknown table family %s", family)
+	}
+}
+
+func renderSet(set *firewallapi.Set) string {
+	var sb strings.Builder
+	if set.DataType == nil {
+		fmt.Fprintf(&sb, "\tset %s {\n\t\ttype %s\n", set.Name, set.KeyType)
+	} else {
+		fmt.Fprintf(&sb, "\tmap %s {\n\t\ttype %s : %s\n", set.Name, set.KeyType, *set.DataType)
+	}
+	if set.Interval {
+		sb.WriteString("\t\tflags interval\n")
+	}
+	if len(set.Elements) > 0 {
+		elements := make([]string, len(set.Elements))
+		for i := range set.Elements {
+			elements[i] = renderElement(set.KeyType, set.Elements[i].Key)
+			if set.Elements[i].Value != nil {
+				elements[i] += " : " + renderElement(ptr.Deref(set.DataType, ""), *set.Elements[i].Value)
+			}
+		}
+		fmt.Fprintf(&sb, "\t\telements = { %s }\n", strings.Join(elements, ", "))
+	}
+	sb.WriteString("\t}\n")
+	return sb.String()
+}
+
+// renderElement renders a set element of the given type, quoting the interface names as nft does.
+func renderElement(dataType firewallapi.SetDataType, value string) string {
+	if dataType == firewallapi.SetDataTypeIfName {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+func renderChain(chain *firewallapi.Chain, sets []firewallapi.Set, table *nftables.Table) (string, error) {
+	if chain.Name == nil || chain.Hook == nil {
+		return "", fmt.Errorf("chain name or hook not defined")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\tchain %s {\n", *chain.Name)
+	priority := firewallapi.ChainPriorityFilter
+	if chain.Priority != nil {
+		priority = *chain.Priority
+	}
+	fmt.Fprintf(&sb, "\t\ttype %s hook %s priority %s;", chain.Type, *chain.Hook, renderPriority(priority))
+	if chain.Policy != nil {
+		fmt.Fprintf(&sb, " policy %s;", *chain.Policy)
+	}
+	sb.WriteString("\n")
+
+	nftChain := &nftables.Chain{Name: *chain.Name, Table: table}
+	var rules []string
+	switch chain.Type {
+	case firewallapi.ChainTypeFilter:
+		for i := range chain.Rules.FilterRules {
+			rule, err := renderRule(&firewallutils.FilterRuleWrapper{FilterRule: &chain.Rules.FilterRules[i], Sets: sets}, nftChain)
+			if err != nil {
+				return "", fmt.Errorf("chain %s: %w", *chain.Name, err)
+			}
+			rules = append(rules, rule)
+		}
+	case firewallapi.ChainTypeNAT:
+		for i := range chain.Rules.NatRules {
+			rule, err := renderRule(&firewallutils.NatRuleWrapper{NatRule: &chain.Rules.NatRules[i], Sets: sets}, nftChain)
+			if err != nil {
+				return "", fmt.Errorf("chain %s: %w", *chain.Name, err)
+			}
+			rules = append(rules, rule)
+		}
+	default:
+		// Route rules are not programmed in nftables.
+	}
+	if len(rules) > 0 {
+		sb.WriteString("\n")
+	}
+	for i := range rules {
+		fmt.Fprintf(&sb, "\t\t%s\n", rules[i])
+	}
+	sb.WriteString("\t}\n")
+	return sb.String(), nil
+}
+
+// renderPriority renders the priority of a chain, using the standard names when possible.
+func renderPriority(priority firewallapi.ChainPriority) string {
+	switch priority {
+	case firewallapi.ChainPriorityRaw:
+		return "raw"
+	case firewallapi.ChainPriorityMangle:
+		return "mangle"
+	case firewallapi.ChainPriorityNATDest:
+		return "dstnat"
+	case firewallapi.ChainPriorityFilter:
+		return "filter"
+	case firewallapi.ChainPrioritySecurity:
+		return "security"
+	case firewallapi.ChainPriorityNATSource:
+		return "srcnat"
+	default:
+		return fmt.Sprintf("%d", priority)
+	}
+}
+
+// renderRule forges the given rule, as programmed by the firewall controller, and renders it in the nft syntax.
+func renderRule(rule firewallutils.Rule, chain *nftables.Chain) (string, error) {
+	if rule.GetName() == nil {
+		return "", fmt.Errorf("rule name not defined")
+	}
+
+	var nftRule *nftables.Rule
+	var err error
+	switch r := rule.(type) {
+	case *firewallutils.FilterRuleWrapper:
+		nftRule, err = r.Forge(chain)
+	case *firewallutils.NatRuleWrapper:
+		nftRule, err = r.Forge(chain)
+	default:
+		return "", fmt.Errorf("rule %s: unsupported rule type %T", *rule.GetName(), rule)
+	}
+	if err != nil {
+		return "", fmt.Errorf("rule %s: %w", *rule.GetName(), err)
+	}
+
+	rendered, err := renderExprs(nftRule)
+	if err != nil {
+		return "", fmt.Errorf("rule %s: %w", *rule.GetName(), err)
+	}
+	return rendered, nil
+}
+
+// renderDst renders a destination as printed by iproute2, omitting the prefix length of host addresses.
+func renderDst(cidr string) (string, error) {
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	ones, bits := ipnet.Mask.Size()
+	switch {
+	case ones == 0:
+		return "default", nil
+	case ones == bits:
+		return ip.String(), nil
+	default:
+		return ipnet.String(), nil
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	"github.com/liqotech/liqo/pkg/dataplane"
+)
+
+// The golden files contain the rulesets printed by "nft list ruleset" once the corresponding rules are programmed.
+
+func goldenFirewallConfiguration(sets []firewallapi.Set, chain *firewallapi.Chain) networkingv1beta1.FirewallConfiguration {
+	return networkingv1beta1.FirewallConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "golden", Namespace: "liqo"},
+		Spec: networkingv1beta1.FirewallConfigurationSpec{
+			Table: firewallapi.Table{
+				Name:   ptr.To("liqo-golden"),
+				Family: ptr.To(firewallapi.TableFamilyIPv4),
+				Sets:   sets,
+				Chains: []firewallapi.Chain{*chain},
+			},
+		},
+	}
+}
+
+func filterChain(rules ...firewallapi.FilterRule) *firewallapi.Chain {
+	return &firewallapi.Chain{
+		Name:     ptr.To("forward"),
+		Type:     firewallapi.ChainTypeFilter,
+		Hook:     ptr.To(firewallapi.ChainHookForward),
+		Policy:   ptr.To(firewallapi.ChainPolicyAccept),
+		Priority: ptr.To(firewallapi.ChainPriorityFilter),
+		Rules:    firewallapi.RulesSet{FilterRules: rules},
+	}
+}
+
+func natChain(rules ...firewallapi.NatRule) *firewallapi.Chain {
+	return &firewallapi.Chain{
+		Name:     ptr.To("postrouting"),
+		Type:     firewallapi.ChainTypeNAT,
+		Hook:     ptr.To(firewallapi.ChainHookPostrouting),
+		Policy:   ptr.To(firewallapi.ChainPolicyAccept),
+		Priority: ptr.To(firewallapi.ChainPriorityNATSource),
+		Rules:    firewallapi.RulesSet{NatRules: rules},
+	}
+}
+
+func filterRule(name string, action firewallapi.FilterAction, match ...firewallapi.Match) firewallapi.FilterRule {
+	return firewallapi.FilterRule{Name: ptr.To(name), Match: match, Counter: true, Action: action}
+}
+
+func natRule(name string, natType firewallapi.NatType, to *string, match ...firewallapi.Match) firewallapi.NatRule {
+	return firewallapi.NatRule{Name: ptr.To(name), Match: match, Counter: true, NatType: natType, To: to}
+}
+
+func matchIP(op firewallapi.MatchOperation, position firewallapi.MatchPosition, value string) firewallapi.Match {
+	return firewallapi.Match{Op: op, IP: &firewallapi.MatchIP{Value: value, Position: position}}
+}
+
+func matchPort(op firewallapi.MatchOperation, proto *firewallapi.MatchProto,
+	position firewallapi.MatchPosition, value string) firewallapi.Match {
+	return firewallapi.Match{Op: op, Proto: proto, Port: &firewallapi.MatchPort{Value: value, Position: position}}
+}
+
+func matchCtState(op firewallapi.MatchOperation, states ...firewallapi.CtState) firewallapi.Match {
+	return firewallapi.Match{Op: op, CtState: &firewallapi.MatchCtState{Value: states}}
+}
+
+func matchMark(op firewallapi.MatchOperation, markType firewallapi.MatchMarkType, value string) firewallapi.Match {
+	return firewallapi.Match{Op: op, Mark: &firewallapi.MatchMark{Value: value, Type: markType}}
+}
+
+func matchLimit(op firewallapi.MatchOperation, rate int64, unit firewallapi.LimitUnit, burst *int32) firewallapi.Match {
+	return firewallapi.Match{Op: op, Limit: &firewallapi.MatchLimit{Rate: rate, Unit: unit, Burst: burst}}
+}
+
+var _ = Describe("Firewall rendering against the nft output", func() {
+	const eq, neq = firewallapi.MatchOperationEq, firewallapi.MatchOperationNeq
+	var (
+		src, dst = firewallapi.MatchPositionSrc, firewallapi.MatchPositionDst
+		tcp      = &firewallapi.MatchProto{Value: firewallapi.L4ProtoTCP}
+		udp      = &firewallapi.MatchProto{Value: firewallapi.L4ProtoUDP}
+	)
+
+	DescribeTable("should not report any difference with the golden ruleset",
+		func(golden string, sets []firewallapi.Set, chain *firewallapi.Chain) {
+			captured, err := os.ReadFile(filepath.Join("testdata", "firewall", golden))
+			Expect(err).ToNot(HaveOccurred())
+
+			fwcfg := goldenFirewallConfiguration(sets, chain)
+			diff, err := dataplane.DiffFirewall([]networkingv1beta1.FirewallConfiguration{fwcfg}, string(captured))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		},
+		Entry("ip matches", "ip.nft",
+			[]firewallapi.Set{{Name: "addrs", KeyType: firewallapi.SetDataTypeIPAddr, Interval: true,
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.0/8"}, {Key: "192.168.0.0/16"}}}},
+			filterChain(
+				filterRule("single", firewallapi.ActionAccept, matchIP(eq, src, "10.0.0.1")),
+				filterRule("subnet", firewallapi.ActionAccept, matchIP(eq, dst, "10.0.0.0/8")),
+				filterRule("subnet-neq", firewallapi.ActionDrop, matchIP(neq, dst, "10.0.0.0/8")),
+				filterRule("set", firewallapi.ActionAccept, matchIP(eq, src, "@addrs")),
+				filterRule("set-neq", firewallapi.ActionDrop, matchIP(neq, src, "@addrs")),
+			)),
+		Entry("port matches", "port.nft",
+			[]firewallapi.Set{{Name: "ports", KeyType: firewallapi.SetDataTypeInetService,
+				Elements: []firewallapi.SetElement{{Key: "80"}, {Key: "443"}}}},
+			filterChain(
+				filterRule("single", firewallapi.ActionAccept, matchPort(eq, tcp, dst, "80")),
+				filterRule("range", firewallapi.ActionAccept, matchPort(eq, udp, src, "1000-2000")),
+				filterRule("single-neq", firewallapi.ActionDrop, matchPort(neq, tcp, dst, "22")),
+				filterRule("set", firewallapi.ActionAccept, matchPort(eq, tcp, dst, "@ports")),
+				filterRule("no-proto", firewallapi.ActionAccept, matchPort(eq, nil, dst, "53")),
+			)),
+		Entry("proto matches", "proto.nft", nil,
+			filterChain(
+				filterRule("tcp", firewallapi.ActionAccept, firewallapi.Match{Op: eq, Proto: tcp}),
+				filterRule("udp", firewallapi.ActionAccept, firewallapi.Match{Op: eq, Proto: udp}),
+			)),
+		Entry("dev matches", "dev.nft",
+			[]firewallapi.Set{{Name: "devs", KeyType: firewallapi.SetDataTypeIfName,
+				Elements: []firewallapi.SetElement{{Key: "liqo-tunnel"}, {Key: "liqo.vxlan"}}}},
+			filterChain(
+				filterRule("in", firewallapi.ActionAccept,
+					firewallapi.Match{Op: eq, Dev: &firewallapi.MatchDev{Value: "eth0", Position: firewallapi.MatchDevPositionIn}}),
+				filterRule("out-neq", firewallapi.ActionDrop,
+					firewallapi.Match{Op: neq, Dev: &firewallapi.MatchDev{Value: "eth0", Position: firewallapi.MatchDevPositionOut}}),
+				filterRule("set", firewallapi.ActionAccept,
+					firewallapi.Match{Op: eq, Dev: &firewallapi.MatchDev{Value: "@devs", Position: firewallapi.MatchDevPositionIn}}),
+			)),
+		Entry("icmp matches", "icmp.nft", nil,
+			filterChain(
+				filterRule("echo-request", firewallapi.ActionAccept,
+					firewallapi.Match{Op: eq, ICMP: &firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest}}),
+				filterRule("destination-unreachable", firewallapi.ActionAccept,
+					firewallapi.Match{Op: eq, ICMP: &firewallapi.MatchICMP{Type: firewallapi.ICMPTypeDestinationUnreachable}}),
+			)),
+		Entry("ct state matches", "ctstate.nft", nil,
+			filterChain(
+				filterRule("eq", firewallapi.ActionAccept,
+					matchCtState(eq, firewallapi.CtStateRelated, firewallapi.CtStateEstablished)),
+				filterRule("neq", firewallapi.ActionAccept,
+					matchCtState(neq, firewallapi.CtStateNew, firewallapi.CtStateInvalid)),
+				filterRule("neq-single", firewallapi.ActionAccept, matchCtState(neq, firewallapi.CtStateInvalid)),
+			)),
+		Entry("mark matches", "mark.nft", nil,
+			filterChain(
+				filterRule("meta", firewallapi.ActionAccept, matchMark(eq, firewallapi.MatchMarkTypeMeta, "0x10")),
+				filterRule("ct", firewallapi.ActionAccept, matchMark(eq, firewallapi.MatchMarkTypeCt, "32")),
+				filterRule("masked", firewallapi.ActionAccept, matchMark(eq, firewallapi.MatchMarkTypeCt, "0x10/0xff")),
+				filterRule("neq", firewallapi.ActionDrop, matchMark(neq, firewallapi.MatchMarkTypeMeta, "0x10")),
+			)),
+		Entry("limit matches", "limit.nft", nil,
+			filterChain(
+				filterRule("default-burst", firewallapi.ActionAccept, matchLimit(eq, 10, firewallapi.LimitUnitSecond, nil)),
+				filterRule("burst", firewallapi.ActionAccept, matchLimit(eq, 100, firewallapi.LimitUnitMinute, ptr.To[int32](20))),
+				filterRule("over", firewallapi.ActionDrop, matchLimit(neq, 10, firewallapi.LimitUnitHour, nil)),
+			)),
+		Entry("verdict map matches", "vmap.nft",
+			[]firewallapi.Set{{Name: "verdicts", KeyType: firewallapi.SetDataTypeIPAddr, DataType: ptr.To(firewallapi.SetDataTypeVerdict),
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.1", Value: ptr.To("drop")}, {Key: "10.0.0.2", Value: ptr.To("accept")}}}},
+			filterChain(
+				filterRule("vmap", firewallapi.ActionAccept, matchIP(eq, src, "@verdicts")),
+			)),
+		Entry("nat rules", "nat.nft",
+			[]firewallapi.Set{{Name: "translations", KeyType: firewallapi.SetDataTypeIPAddr, DataType: ptr.To(firewallapi.SetDataTypeIPAddr),
+				Elements: []firewallapi.SetElement{{Key: "10.70.0.1", Value: ptr.To("10.0.0.1")}}}},
+			natChain(
+				natRule("snat", firewallapi.NatTypeSource, ptr.To("192.168.0.1"), matchIP(eq, src, "10.0.0.0/8")),
+				natRule("snat-prefix", firewallapi.NatTypeSource, ptr.To("192.168.0.0/16"), matchIP(eq, src, "10.0.0.0/8")),
+				natRule("masquerade", firewallapi.NatTypeMasquerade, nil,
+					firewallapi.Match{Op: eq, Dev: &firewallapi.MatchDev{Value: "eth0", Position: firewallapi.MatchDevPositionOut}}),
+				natRule("dnat-map", firewallapi.NatTypeDestination, ptr.To("@translations")),
+			)),
+		Entry("actions", "actions.nft", nil,
+			filterChain(
+				firewallapi.FilterRule{Name: ptr.To("ctmark"), Match: []firewallapi.Match{matchIP(eq, src, "10.0.0.1")},
+					Action: firewallapi.ActionCtMark, Value: ptr.To("2")},
+				firewallapi.FilterRule{Name: ptr.To("metamarkfromctmark"), Action: firewallapi.ActionSetMetaMarkFromCtMark},
+				filterRule("reject", firewallapi.ActionReject),
+				filterRule("drop", firewallapi.ActionDrop, matchIP(eq, src, "10.0.0.2")),
+				firewallapi.FilterRule{Name: ptr.To("log"), Counter: true, Log: true, Action: firewallapi.ActionAccept},
+			)),
+	)
+})
@@ -0,0 +1,466 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/google/nftables/userdata"
+	"golang.org/x/sys/unix"
+)
+
+// ctStates are the names of the conntrack states, in the order of their bits, as printed by nft.
+var ctStates = []struct {
+	bit  uint32
+	name string
+}{
+	{expr.CtStateBitINVALID, "invalid"},
+	{expr.CtStateBitESTABLISHED, "established"},
+	{expr.CtStateBitRELATED, "related"},
+	{expr.CtStateBitNEW, "new"},
+	{expr.CtStateBitUNTRACKED, "untracked"},
+}
+
+// icmpTypes are the names of the ICMP types, as printed by nft.
+var icmpTypes = map[byte]string{
+	0:  "echo-reply",
+	3:  "destination-unreachable",
+	5:  "redirect",
+	8:  "echo-request",
+	11: "time-exceeded",
+	12: "parameter-problem",
+}
+
+// icmpv6Types are the names of the ICMPv6 types, as printed by nft.
+var icmpv6Types = map[byte]string{
+	1:   "destination-unreachable",
+	2:   "packet-too-big",
+	3:   "time-exceeded",
+	4:   "parameter-problem",
+	128: "echo-request",
+	129: "echo-reply",
+	137: "nd-redirect",
+}
+
+// l4Protos are the names of the transport protocols, as printed by nft.
+var l4Protos = map[byte]string{
+	unix.IPPROTO_ICMP:   "icmp",
+	unix.IPPROTO_ICMPV6: "icmpv6",
+	unix.IPPROTO_TCP:    "tcp",
+	unix.IPPROTO_UDP:    "udp",
+}
+
+// operand is the kind of value loaded in a register.
+type operand int
+
+const (
+	operandNone operand = iota
+	operandL4Proto
+	operandIfName
+	operandAddr
+	operandPort
+	operandICMPType
+	operandICMPv6Type
+	operandCtState
+	operandMark
+	operandNFProto
+)
+
+// register describes the value loaded in a register, and how it is printed by nft.
+type register struct {
+	kind operand
+	// name is the selector of the value (e.g., ip saddr, tcp dport).
+	name string
+	// mask is the mask applied to the value, if any.
+	mask []byte
+	// data is the constant loaded by an immediate expression.
+	data []byte
+	// mapName is the name of the map the value has been looked up into.
+	mapName string
+}
+
+// ruleRenderer converts the expressions of a nftables rule to the nft syntax, as printed by "nft list ruleset".
+type ruleRenderer struct {
+	family nftables.TableFamily
+	tokens []string
+	regs   map[uint32]*register
+	// l4proto is the index of the token matching the transport protocol, which nft omits
+	// when implied by a following match on the transport header.
+	l4proto     int
+	l4protoName string
+	// nfproto is the index of the token matching the IPv6 family, which nft omits when implied by a following match on the IPv6 header.
+	nfproto int
+	// rangeStart is the lower bound of a range being matched.
+	rangeStart []byte
+}
+
+// renderExprs renders the expressions of the given nftables rule in the nft syntax.
+func renderExprs(rule *nftables.Rule) (string, error) {
+	r := &ruleRenderer{family: rule.Table.Family, regs: map[uint32]*register{}, l4proto: -1, nfproto: -1}
+	for _, e := range rule.Exprs {
+		if err := r.render(e); err != nil {
+			return "", err
+		}
+	}
+	if comment, ok := userdata.GetString(rule.UserData, userdata.TypeComment); ok {
+		r.tokens = append(r.tokens, fmt.Sprintf("comment %q", comment))
+	}
+	return strings.Join(r.tokens, " "), nil
+}
+
+func (r *ruleRenderer) reg(n uint32) *register {
+	if r.regs[n] == nil {
+		r.regs[n] = &register{}
+	}
+	return r.regs[n]
+}
+
+func (r *ruleRenderer) load(n uint32, kind operand, name string) {
+	r.regs[n] = &register{kind: kind, name: name}
+}
+
+//nolint:gocyclo // The function dispatches the supported expressions.
+func (r *ruleRenderer) render(e expr.Any) error {
+	switch e := e.(type) {
+	case *expr.Meta:
+		return r.renderMeta(e)
+	case *expr.Ct:
+		return r.renderCt(e)
+	case *expr.Payload:
+		return r.renderPayload(e)
+	case *expr.Bitwise:
+		r.reg(e.DestRegister).mask = e.Mask
+	case *expr.Cmp:
+		return r.renderCmp(e)
+	case *expr.Lookup:
+		reg := r.reg(e.SourceRegister)
+		switch {
+		case e.IsDestRegSet && e.DestRegister == 0:
+			r.tokens = append(r.tokens, fmt.Sprintf("%s vmap @%s", reg.name, e.SetName))
+		case e.IsDestRegSet:
+			r.regs[e.DestRegister] = &register{name: reg.name, mapName: e.SetName}
+		case e.Invert:
+			r.tokens = append(r.tokens, fmt.Sprintf("%s != @%s", reg.name, e.SetName))
+		default:
+			r.tokens = append(r.tokens, fmt.Sprintf("%s @%s", reg.name, e.SetName))
+		}
+	case *expr.Immediate:
+		r.regs[e.Register] = &register{data: e.Data}
+	case *expr.Limit:
+		r.tokens = append(r.tokens, renderLimit(e))
+	case *expr.Counter:
+		r.tokens = append(r.tokens, "counter")
+	case *expr.Log:
+		r.tokens = append(r.tokens, fmt.Sprintf("log prefix %q", string(e.Data)))
+	case *expr.Verdict:
+		r.tokens = append(r.tokens, renderVerdict(e))
+	case *expr.Reject:
+		// The rules are rejected with a port unreachable message, which is the default one.
+		r.tokens = append(r.tokens, "reject")
+	case *expr.NAT:
+		return r.renderNAT(e)
+	case *expr.Masq:
+		r.tokens = append(r.tokens, "masquerade")
+	default:
+		return fmt.Errorf("unsupported expression %T", e)
+	}
+	return nil
+}
+
+func (r *ruleRenderer) renderMeta(e *expr.Meta) error {
+	if e.SourceRegister {
+		if e.Key != expr.MetaKeyMARK {
+			return fmt.Errorf("unsupported meta statement %d", e.Key)
+		}
+		r.tokens = append(r.tokens, "meta mark set "+r.reg(e.Register).name)
+		return nil
+	}
+
+	switch e.Key {
+	case expr.MetaKeyL4PROTO:
+		r.load(e.Register, operandL4Proto, "meta l4proto")
+	case expr.MetaKeyNFPROTO:
+		r.load(e.Register, operandNFProto, "meta nfproto")
+	case expr.MetaKeyIIFNAME:
+		r.load(e.Register, operandIfName, "iifname")
+	case expr.MetaKeyOIFNAME:
+		r.load(e.Register, operandIfName, "oifname")
+	case expr.MetaKeyMARK:
+		r.load(e.Register, operandMark, "meta mark")
+	default:
+		return fmt.Errorf("unsupported meta key %d", e.Key)
+	}
+	return nil
+}
+
+func (r *ruleRenderer) renderCt(e *expr.Ct) error {
+	// The source register of the statements read from the kernel is not decoded by the nftables library,
+	// hence they are recognized by the missing destination register, and the value is loaded in the first one.
+	if e.SourceRegister || e.Register == 0 {
+		if e.Key != expr.CtKeyMARK {
+			return fmt.Errorf("unsupported ct statement %d", e.Key)
+		}
+		r.tokens = append(r.tokens, "ct mark set "+renderMark(r.reg(max(e.Register, 1)).data))
+		return nil
+	}
+
+	switch e.Key {
+	case expr.CtKeySTATE:
+		r.load(e.Register, operandCtState, "ct state")
+	case expr.CtKeyMARK:
+		r.load(e.Register, operandMark, "ct mark")
+	default:
+		return fmt.Errorf("unsupported ct key %d", e.Key)
+	}
+	return nil
+}
+
+func (r *ruleRenderer) renderPayload(e *expr.Payload) error {
+	switch {
+	case e.Base == expr.PayloadBaseNetworkHeader && e.Len == 4 && e.Offset == 12:
+		r.load(e.DestRegister, operandAddr, "ip saddr")
+	case e.Base == expr.PayloadBaseNetworkHeader && e.Len == 4 && e.Offset == 16:
+		r.load(e.DestRegister, operandAddr, "ip daddr")
+	case e.Base == expr.PayloadBaseNetworkHeader && e.Len == 16 && (e.Offset == 8 || e.Offset == 24):
+		if r.nfproto >= 0 {
+			r.tokens = append(r.tokens[:r.nfproto], r.tokens[r.nfproto+1:]...)
+			r.nfproto = -1
+		}
+		position := "daddr"
+		if e.Offset == 8 {
+			position = "saddr"
+		}
+		r.load(e.DestRegister, operandAddr, "ip6 "+position)
+	case e.Base == expr.PayloadBaseTransportHeader && e.Len == 1 && e.Offset == 0 && r.l4protoName == "icmp":
+		r.consumeL4Proto()
+		r.load(e.DestRegister, operandICMPType, "icmp type")
+	case e.Base == expr.PayloadBaseTransportHeader && e.Len == 1 && e.Offset == 0 && r.l4protoName == "icmpv6":
+		r.consumeL4Proto()
+		r.load(e.DestRegister, operandICMPv6Type, "icmpv6 type")
+	case e.Base == expr.PayloadBaseTransportHeader && e.Len == 2 && (e.Offset == 0 || e.Offset == 2):
+		// Without a protocol dependency, the ports are printed through the generic transport header.
+		proto := "th"
+		if r.l4protoName == "tcp" || r.l4protoName == "udp" {
+			proto = r.l4protoName
+			r.consumeL4Proto()
+		}
+		position := "dport"
+		if e.Offset == 0 {
+			position = "sport"
+		}
+		r.load(e.DestRegister, operandPort, proto+" "+position)
+	default:
+		return fmt.Errorf("unsupported payload at offset %d of base %d", e.Offset, e.Base)
+	}
+	return nil
+}
+
+// consumeL4Proto removes the match on the transport protocol, which is implied by the following match on the transport header.
+func (r *ruleRenderer) consumeL4Proto() {
+	if r.l4proto >= 0 {
+		r.tokens = append(r.tokens[:r.l4proto], r.tokens[r.l4proto+1:]...)
+	}
+	r.l4proto, r.l4protoName = -1, ""
+}
+
+//nolint:gocyclo // The function renders the comparisons of the supported operands.
+func (r *ruleRenderer) renderCmp(e *expr.Cmp) error {
+	reg := r.reg(e.Register)
+
+	op := ""
+	switch e.Op {
+	case expr.CmpOpEq:
+	case expr.CmpOpNeq:
+		op = "!= "
+	case expr.CmpOpGte:
+		// The lower bound of a range, which is printed together with the upper bound.
+		r.rangeStart = e.Data
+		return nil
+	case expr.CmpOpLte:
+		if r.rangeStart == nil {
+			return fmt.Errorf("unsupported comparison without lower bound")
+		}
+		r.tokens = append(r.tokens, fmt.Sprintf("%s %s-%s", reg.name, renderValue(reg.kind, r.rangeStart), renderValue(reg.kind, e.Data)))
+		r.rangeStart = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported comparison operation %d", e.Op)
+	}
+
+	switch reg.kind {
+	case operandL4Proto:
+		name, ok := l4Protos[e.Data[0]]
+		if !ok {
+			return fmt.Errorf("unsupported transport protocol %d", e.Data[0])
+		}
+		if e.Op == expr.CmpOpEq {
+			r.l4proto, r.l4protoName = len(r.tokens), name
+		}
+		r.tokens = append(r.tokens, fmt.Sprintf("%s %s%s", reg.name, op, name))
+	case operandNFProto:
+		if len(e.Data) != 1 || e.Data[0] != unix.NFPROTO_IPV6 {
+			return fmt.Errorf("unsupported family %v", e.Data)
+		}
+		if e.Op == expr.CmpOpEq {
+			r.nfproto = len(r.tokens)
+		}
+		r.tokens = append(r.tokens, fmt.Sprintf("%s %sipv6", reg.name, op))
+	case operandCtState:
+		// The states are matched through a mask, and the rule matches if any of them is set (i.e., the result is not zero).
+		states := renderCtStates(binaryutil.NativeEndian.Uint32(reg.mask))
+		if e.Op == expr.CmpOpNeq {
+			r.tokens = append(r.tokens, fmt.Sprintf("%s %s", reg.name, strings.Join(states, ",")))
+		} else {
+			r.tokens = append(r.tokens, fmt.Sprintf("%s & %s == 0x0", reg.name, renderFlags(states)))
+		}
+	case operandMark:
+		if reg.mask != nil {
+			if op == "" {
+				op = "== "
+			}
+			r.tokens = append(r.tokens, fmt.Sprintf("%s & %s %s%s", reg.name, renderMark(reg.mask), op, renderMark(e.Data)))
+		} else {
+			r.tokens = append(r.tokens, fmt.Sprintf("%s %s%s", reg.name, op, renderMark(e.Data)))
+		}
+	case operandAddr:
+		value := net.IP(e.Data).String()
+		if reg.mask != nil {
+			ones, _ := net.IPMask(reg.mask).Size()
+			value = fmt.Sprintf("%s/%d", value, ones)
+		}
+		r.tokens = append(r.tokens, fmt.Sprintf("%s %s%s", reg.name, op, value))
+	case operandPort, operandICMPType, operandICMPv6Type, operandIfName:
+		r.tokens = append(r.tokens, fmt.Sprintf("%s %s%s", reg.name, op, renderValue(reg.kind, e.Data)))
+	default:
+		return fmt.Errorf("unsupported comparison of %q", reg.name)
+	}
+	return nil
+}
+
+func (r *ruleRenderer) renderNAT(e *expr.NAT) error {
+	natType := "snat"
+	if e.Type == expr.NATTypeDestNAT {
+		natType = "dnat"
+	}
+	// In the inet tables, the family of the translated addresses must be specified.
+	if r.family == nftables.TableFamilyINet {
+		natType += " ip"
+	}
+
+	start := r.reg(e.RegAddrMin)
+	switch {
+	case start.mapName != "":
+		r.tokens = append(r.tokens, fmt.Sprintf("%s to %s map @%s", natType, start.name, start.mapName))
+	case e.Prefix:
+		end := r.reg(e.RegAddrMax)
+		if len(start.data) != 4 || len(end.data) != 4 {
+			return fmt.Errorf("unsupported nat prefix")
+		}
+		size := bits.LeadingZeros32(binary.BigEndian.Uint32(start.data) ^ binary.BigEndian.Uint32(end.data))
+		r.tokens = append(r.tokens, fmt.Sprintf("%s prefix to %s/%d", natType, net.IP(start.data), size))
+	default:
+		if len(start.data) == 0 {
+			return fmt.Errorf("unsupported nat without address")
+		}
+		r.tokens = append(r.tokens, fmt.Sprintf("%s to %s", natType, net.IP(start.data)))
+	}
+	return nil
+}
+
+// renderValue renders a constant compared with an operand of the given kind.
+func renderValue(kind operand, data []byte) string {
+	switch kind {
+	case operandPort:
+		return fmt.Sprintf("%d", binary.BigEndian.Uint16(data))
+	case operandICMPType:
+		if name, ok := icmpTypes[data[0]]; ok {
+			return name
+		}
+		return fmt.Sprintf("%d", data[0])
+	case operandICMPv6Type:
+		if name, ok := icmpv6Types[data[0]]; ok {
+			return name
+		}
+		return fmt.Sprintf("%d", data[0])
+	case operandIfName:
+		return fmt.Sprintf("%q", strings.TrimRight(string(data), "\x00"))
+	default:
+		return fmt.Sprintf("0x%x", data)
+	}
+}
+
+// renderMark renders a mark (or a mask), which nft prints as a zero-padded hexadecimal value.
+func renderMark(data []byte) string {
+	return fmt.Sprintf("0x%08x", binaryutil.NativeEndian.Uint32(data))
+}
+
+// renderCtStates returns the names of the conntrack states set in the given mask, in the order of their bits.
+func renderCtStates(mask uint32) []string {
+	var states []string
+	for _, state := range ctStates {
+		if mask&state.bit != 0 {
+			states = append(states, state.name)
+		}
+	}
+	return states
+}
+
+// renderFlags renders a list of flags combined in a mask.
+func renderFlags(flags []string) string {
+	if len(flags) == 1 {
+		return flags[0]
+	}
+	return "(" + strings.Join(flags, " | ") + ")"
+}
+
+func renderLimit(e *expr.Limit) string {
+	over := ""
+	if e.Over {
+		over = "over "
+	}
+	units := map[expr.LimitTime]string{
+		expr.LimitTimeSecond: "second", expr.LimitTimeMinute: "minute", expr.LimitTimeHour: "hour", expr.LimitTimeDay: "day",
+	}
+	limit := fmt.Sprintf("limit rate %s%d/%s", over, e.Rate, units[e.Unit])
+	// The default burst is omitted.
+	if e.Burst != 0 && e.Burst != 5 {
+		limit += fmt.Sprintf(" burst %d packets", e.Burst)
+	}
+	return limit
+}
+
+func renderVerdict(e *expr.Verdict) string {
+	switch e.Kind {
+	case expr.VerdictAccept:
+		return "accept"
+	case expr.VerdictDrop:
+		return "drop"
+	case expr.VerdictReturn:
+		return "return"
+	case expr.VerdictJump:
+		return "jump " + e.Chain
+	case expr.VerdictGoto:
+		return "goto " + e.Chain
+	default:
+		return fmt.Sprintf("verdict %d", e.Kind)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/google/nftables"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netns"
+	"k8s.io/utils/ptr"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+var _ = Describe("Rendering of the rules read back from the kernel", func() {
+	const eq, neq = firewallapi.MatchOperationEq, firewallapi.MatchOperationNeq
+	var (
+		nftconn *nftables.Conn
+		ns      netns.NsHandle
+		table   *nftables.Table
+		sets    = []firewallapi.Set{
+			{Name: "addrs", KeyType: firewallapi.SetDataTypeIPAddr, Interval: true,
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.0/8"}}},
+			{Name: "devs", KeyType: firewallapi.SetDataTypeIfName,
+				Elements: []firewallapi.SetElement{{Key: "eth0"}}},
+			{Name: "verdicts", KeyType: firewallapi.SetDataTypeIPAddr, DataType: ptr.To(firewallapi.SetDataTypeVerdict),
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.1", Value: ptr.To("drop")}}},
+			{Name: "translations", KeyType: firewallapi.SetDataTypeIPAddr, DataType: ptr.To(firewallapi.SetDataTypeIPAddr),
+				Elements: []firewallapi.SetElement{{Key: "10.70.0.1", Value: ptr.To("10.0.0.1")}}},
+		}
+		tcp = &firewallapi.MatchProto{Value: firewallapi.L4ProtoTCP}
+	)
+
+	// program adds the forged rule to a new chain, and returns the rule as forged and as read back from the kernel.
+	program := func(forge func(*nftables.Chain) (*nftables.Rule, error), chainType nftables.ChainType,
+		hook *nftables.ChainHook) (forged, current *nftables.Rule) {
+		chain := nftconn.AddChain(&nftables.Chain{Name: "test", Table: table, Type: chainType,
+			Hooknum: hook, Priority: nftables.ChainPriorityFilter})
+		forged, err := forge(chain)
+		Expect(err).ToNot(HaveOccurred())
+		nftconn.AddRule(forged)
+		Expect(nftconn.Flush()).To(Succeed())
+
+		rules, err := nftconn.GetRules(table, chain)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(HaveLen(1))
+		return forged, rules[0]
+	}
+
+	BeforeEach(func() {
+		if os.Geteuid() != 0 {
+			Skip("the creation of the network namespaces requires root privileges")
+		}
+
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		origin, err := netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+		defer origin.Close()
+		ns, err = netns.New()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(netns.Set(origin)).To(Succeed())
+
+		nftconn, err = nftables.New(nftables.WithNetNSFd(int(ns)))
+		Expect(err).ToNot(HaveOccurred())
+		table = nftconn.AddTable(&nftables.Table{Name: "liqo-test", Family: nftables.TableFamilyIPv4})
+		for i := range sets {
+			set, elements, err := firewallutils.ForgeSet(&sets[i], table)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nftconn.AddSet(set, elements)).To(Succeed())
+		}
+		if err := nftconn.Flush(); err != nil {
+			Skip("nf_tables not supported: " + err.Error())
+		}
+	})
+
+	AfterEach(func() {
+		if nftconn != nil {
+			Expect(nftconn.CloseLasting()).To(Succeed())
+		}
+		Expect(ns.Close()).To(Succeed())
+	})
+
+	DescribeTable("should render the same rule as the forged one",
+		func(match ...firewallapi.Match) {
+			rule := &firewallutils.FilterRuleWrapper{
+				FilterRule: &firewallapi.FilterRule{Name: ptr.To("test"), Match: match, Counter: true, Action: firewallapi.ActionAccept},
+				Sets:       sets,
+			}
+			forged, current := program(rule.Forge, nftables.ChainTypeFilter, nftables.ChainHookForward)
+
+			expected, err := renderExprs(forged)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renderExprs(current)).To(Equal(expected))
+		},
+		Entry("ip", firewallapi.Match{Op: eq, IP: &firewallapi.MatchIP{Value: "10.0.0.1", Position: firewallapi.MatchPositionSrc}}),
+		Entry("ip subnet", firewallapi.Match{Op: neq, IP: &firewallapi.MatchIP{Value: "10.0.0.0/8", Position: firewallapi.MatchPositionDst}}),
+		Entry("ip set", firewallapi.Match{Op: neq, IP: &firewallapi.MatchIP{Value: "@addrs", Position: firewallapi.MatchPositionSrc}}),
+		Entry("ip verdict map", firewallapi.Match{Op: eq, IP: &firewallapi.MatchIP{Value: "@verdicts", Position: firewallapi.MatchPositionSrc}}),
+		Entry("port", firewallapi.Match{Op: neq, Proto: tcp, Port: &firewallapi.MatchPort{Value: "80", Position: firewallapi.MatchPositionDst}}),
+		Entry("port range", firewallapi.Match{Op: eq, Proto: tcp, Port: &firewallapi.MatchPort{Value: "1000-2000", Position: firewallapi.MatchPositionSrc}}),
+		Entry("proto", firewallapi.Match{Op: eq, Proto: tcp}),
+		Entry("dev", firewallapi.Match{Op: neq, Dev: &firewallapi.MatchDev{Value: "eth0", Position: firewallapi.MatchDevPositionOut}}),
+		Entry("dev set", firewallapi.Match{Op: eq, Dev: &firewallapi.MatchDev{Value: "@devs", Position: firewallapi.MatchDevPositionIn}}),
+		Entry("icmp", firewallapi.Match{Op: eq, ICMP: &firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest}}),
+		Entry("ct state", firewallapi.Match{Op: eq, CtState: &firewallapi.MatchCtState{
+			Value: []firewallapi.CtState{firewallapi.CtStateRelated, firewallapi.CtStateEstablished}}}),
+		Entry("ct state neq", firewallapi.Match{Op: neq, CtState: &firewallapi.MatchCtState{
+			Value: []firewallapi.CtState{firewallapi.CtStateNew, firewallapi.CtStateInvalid}}}),
+		Entry("mark", firewallapi.Match{Op: neq, Mark: &firewallapi.MatchMark{Value: "0x10/0xff", Type: firewallapi.MatchMarkTypeCt}}),
+		Entry("limit", firewallapi.Match{Op: neq, Limit: &firewallapi.MatchLimit{Rate: 10, Unit: firewallapi.LimitUnitMinute, Burst: ptr.To[int32](20)}}),
+	)
+
+	DescribeTable("should render the same action as the forged one",
+		func(rule *firewallapi.FilterRule) {
+			rule.Name = ptr.To("test")
+			wrapper := &firewallutils.FilterRuleWrapper{FilterRule: rule, Sets: sets}
+			forged, current := program(wrapper.Forge, nftables.ChainTypeFilter, nftables.ChainHookForward)
+
+			expected, err := renderExprs(forged)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renderExprs(current)).To(Equal(expected))
+		},
+		Entry("ctmark", &firewallapi.FilterRule{Action: firewallapi.ActionCtMark, Value: ptr.To("2")}),
+		Entry("metamarkfromctmark", &firewallapi.FilterRule{Action: firewallapi.ActionSetMetaMarkFromCtMark}),
+		Entry("reject", &firewallapi.FilterRule{Action: firewallapi.ActionReject}),
+		Entry("log", &firewallapi.FilterRule{Action: firewallapi.ActionDrop, Log: true}),
+	)
+
+	DescribeTable("should render the same nat rule as the forged one",
+		func(hook *nftables.ChainHook, rule *firewallapi.NatRule) {
+			rule.Name = ptr.To("test")
+			wrapper := &firewallutils.NatRuleWrapper{NatRule: rule, Sets: sets}
+			forged, current := program(wrapper.Forge, nftables.ChainTypeNAT, hook)
+
+			expected, err := renderExprs(forged)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renderExprs(current)).To(Equal(expected))
+		},
+		Entry("snat", nftables.ChainHookPostrouting,
+			&firewallapi.NatRule{NatType: firewallapi.NatTypeSource, To: ptr.To("192.168.0.1")}),
+		Entry("snat prefix", nftables.ChainHookPostrouting,
+			&firewallapi.NatRule{NatType: firewallapi.NatTypeSource, To: ptr.To("192.168.0.0/16")}),
+		Entry("masquerade", nftables.ChainHookPostrouting,
+			&firewallapi.NatRule{NatType: firewallapi.NatTypeMasquerade, Counter: true}),
+		Entry("dnat map", nftables.ChainHookPrerouting,
+			&firewallapi.NatRule{NatType: firewallapi.NatTypeDestination, To: ptr.To("@translations")}),
+	)
+
+	It("should render the IPv6 matches", func() {
+		sets6 := []firewallapi.Set{{Name: "addrs6", KeyType: firewallapi.SetDataTypeIP6Addr, Interval: true,
+			Elements: []firewallapi.SetElement{{Key: "2001:db8::/32"}}}}
+		table = nftconn.AddTable(&nftables.Table{Name: "liqo-test6", Family: nftables.TableFamilyINet})
+		set, elements, err := firewallutils.ForgeSet(&sets6[0], table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nftconn.AddSet(set, elements)).To(Succeed())
+
+		rule := &firewallutils.FilterRuleWrapper{
+			FilterRule: &firewallapi.FilterRule{Name: ptr.To("test"), Action: firewallapi.ActionAccept, Match: []firewallapi.Match{
+				{Op: eq, IP: &firewallapi.MatchIP{Value: "@addrs6", Position: firewallapi.MatchPositionSrc}},
+				{Op: eq, ICMP: &firewallapi.MatchICMP{Type: firewallapi.ICMPTypePacketTooBig, Protocol: firewallapi.ICMPProtocolICMPv6}},
+			}},
+			Sets: sets6,
+		}
+		forged, current := program(rule.Forge, nftables.ChainTypeFilter, nftables.ChainHookForward)
+
+		expected, err := renderExprs(forged)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expected).To(Equal(`ip6 saddr @addrs6 icmpv6 type packet-too-big accept comment "test"`))
+		Expect(renderExprs(current)).To(Equal(expected))
+	})
+})
@@ -0,0 +1,409 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+)
+
+// ipRule is the normalized representation of an ip rule.
+type ipRule struct {
+	table  string
+	src    string
+	dst    string
+	iif    string
+	oif    string
+	fwmark string
+}
+
+// String renders the rule as an ip rule command.
+func (r *ipRule) String() string {
+	tokens := []string{"ip", "rule", "add"}
+	tokens = appendOption(tokens, "from", r.src)
+	tokens = appendOption(tokens, "to", r.dst)
+	tokens = appendOption(tokens, "iif", r.iif)
+	tokens = appendOption(tokens, "oif", r.oif)
+	tokens = appendOption(tokens, "fwmark", r.fwmark)
+	return strings.Join(append(tokens, "lookup", r.table), " ")
+}
+
+// ipNextHop is the normalized representation of a next hop of a multipath route.
+type ipNextHop struct {
+	gw     string
+	dev    string
+	onlink bool
+	weight int
+}
+
+// ipRoute is the normalized representation of an ip route.
+type ipRoute struct {
+	table    string
+	dst      string
+	gw       string
+	dev      string
+	src      string
+	scope    string
+	onlink   bool
+	nexthops []ipNextHop
+}
+
+// String renders the route as an ip route command.
+func (r *ipRoute) String() string {
+	tokens := []string{"ip", "route", "add", r.dst}
+	tokens = appendOption(tokens, "via", r.gw)
+	tokens = appendOption(tokens, "dev", r.dev)
+	tokens = appendOption(tokens, "src", r.src)
+	tokens = appendOption(tokens, "scope", r.scope)
+	if r.onlink {
+		tokens = append(tokens, "onlink")
+	}
+	tokens = append(tokens, "table", r.table)
+	for i := range r.nexthops {
+		nh := &r.nexthops[i]
+		tokens = append(tokens, "nexthop")
+		tokens = appendOption(tokens, "via", nh.gw)
+		tokens = appendOption(tokens, "dev", nh.dev)
+		tokens = append(tokens, "weight", strconv.Itoa(nh.weight))
+		if nh.onlink {
+			tokens = append(tokens, "onlink")
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// matches returns whether the captured route satisfies the desired one. Unset devices and sources are not checked,
+// since the kernel may fill them in.
+func (r *ipRoute) matches(captured *ipRoute) bool {
+	if r.table != captured.table || r.dst != captured.dst || r.gw != captured.gw ||
+		r.scope != captured.scope || r.onlink != captured.onlink || len(r.nexthops) != len(captured.nexthops) {
+		return false
+	}
+	if (r.dev != "" && r.dev != captured.dev) || (r.src != "" && r.src != captured.src) {
+		return false
+	}
+	for i := range r.nexthops {
+		nh, cnh := &r.nexthops[i], &captured.nexthops[i]
+		if nh.gw != cnh.gw || nh.onlink != cnh.onlink || nh.weight != cnh.weight || (nh.dev != "" && nh.dev != cnh.dev) {
+			return false
+		}
+	}
+	return true
+}
+
+func appendOption(tokens []string, key, value string) []string {
+	if value == "" {
+		return tokens
+	}
+	return append(tokens, key, value)
+}
+
+// RenderRoutes renders the ip rules and routes programmed by the given RouteConfigurations, as ip commands.
+func RenderRoutes(rcfgs []networkingv1beta1.RouteConfiguration) (string, error) {
+	rules, routes, err := desiredRoutes(rcfgs)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i := range rules {
+		sb.WriteString(rules[i].String() + "\n")
+	}
+	for i := range routes {
+		sb.WriteString(routes[i].String() + "\n")
+	}
+	return sb.String(), nil
+}
+
+func desiredRoutes(rcfgs []networkingv1beta1.RouteConfiguration) (rules []ipRule, routes []ipRoute, err error) {
+	sorted := make([]networkingv1beta1.RouteConfiguration, len(rcfgs))
+	copy(sorted, rcfgs)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for i := range sorted {
+		table := &sorted[i].Spec.Table
+		if table.Name == "" {
+			return nil, nil, fmt.Errorf("routeconfiguration %s/%s: table name is empty", sorted[i].Namespace, sorted[i].Name)
+		}
+		for j := range table.Rules {
+			rule, err := forgeIPRule(&table.Rules[j], table.Name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("routeconfiguration %s/%s: %w", sorted[i].Namespace, sorted[i].Name, err)
+			}
+			rules = append(rules, *rule)
+			for k := range table.Rules[j].Routes {
+				route, err := forgeIPRoute(&table.Rules[j].Routes[k], table.Name)
+				if err != nil {
+					return nil, nil, fmt.Errorf("routeconfiguration %s/%s: %w", sorted[i].Namespace, sorted[i].Name, err)
+				}
+				routes = append(routes, *route)
+			}
+		}
+	}
+	return rules, routes, nil
+}
+
+func forgeIPRule(rule *networkingv1beta1.Rule, table string) (*ipRule, error) {
+	r := &ipRule{table: table}
+	if rule.Src != nil {
+		src, err := renderDst(rule.Src.String())
+		if err != nil {
+			return nil, err
+		}
+		r.src = src
+	}
+	if rule.Dst != nil {
+		dst, err := renderDst(rule.Dst.String())
+		if err != nil {
+			return nil, err
+		}
+		r.dst = dst
+	}
+	// Rules matching all the addresses are shown without the corresponding selector.
+	if r.src == "default" {
+		r.src = ""
+	}
+	if r.dst == "default" {
+		r.dst = ""
+	}
+	r.iif = ptrToString(rule.Iif)
+	r.oif = ptrToString(rule.Oif)
+	if rule.FwMark != nil {
+		r.fwmark = fmt.Sprintf("0x%x", *rule.FwMark)
+	}
+	return r, nil
+}
+
+func forgeIPRoute(route *networkingv1beta1.Route, table string) (*ipRoute, error) {
+	if route.Dst == nil {
+		return nil, fmt.Errorf("route without destination")
+	}
+	dst, err := renderDst(route.Dst.String())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ipRoute{table: table, dst: dst, dev: ptrToString(route.Dev)}
+	if route.Gw != nil {
+		r.gw = renderIP(route.Gw.String())
+	}
+	if route.Src != nil {
+		r.src = renderIP(route.Src.String())
+	}
+	if route.Scope != nil && *route.Scope != networkingv1beta1.GlobalScope {
+		r.scope = string(*route.Scope)
+	}
+	r.onlink = route.Onlink != nil && *route.Onlink
+	for i := range route.NextHops {
+		nh := ipNextHop{dev: ptrToString(route.NextHops[i].Dev), weight: 1}
+		if route.NextHops[i].Gw != nil {
+			nh.gw = renderIP(route.NextHops[i].Gw.String())
+		}
+		nh.onlink = route.NextHops[i].Onlink != nil && *route.NextHops[i].Onlink
+		if route.NextHops[i].Weight != nil {
+			nh.weight = *route.NextHops[i].Weight
+		}
+		r.nexthops = append(r.nexthops, nh)
+	}
+	return r, nil
+}
+
+// renderIP normalizes an IP address, leaving it untouched if it cannot be parsed.
+func renderIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
+// parseIPRules parses the output of "ip rule show", keeping only the rules pointing to the given tables.
+// The tables are identified either by name or by ID, and are normalized to the name.
+func parseIPRules(output string, tables map[string]string) []ipRule {
+	var rules []ipRule
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// Skip the priority, and the rules with an unsupported syntax.
+		if len(fields) < 2 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+		var r ipRule
+		fields = fields[1:]
+		for i := 0; i+1 < len(fields); i++ {
+			value := fields[i+1]
+			switch fields[i] {
+			case "from":
+				if value != "all" {
+					r.src = normalizeDst(value)
+				}
+			case "to":
+				if value != "all" {
+					r.dst = normalizeDst(value)
+				}
+			case "iif":
+				r.iif = value
+			case "oif":
+				r.oif = value
+			case "fwmark":
+				r.fwmark = normalizeMark(value)
+			case "lookup", "table":
+				r.table = value
+			default:
+				continue
+			}
+			i++
+		}
+		if name, ok := tables[r.table]; ok {
+			r.table = name
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// parseIPRoutes parses the output of "ip route show table all", keeping only the routes belonging to the given tables.
+// The tables are identified either by name or by ID, and are normalized to the name.
+func parseIPRoutes(output string, tables map[string]string) []ipRoute {
+	var routes []ipRoute
+	var last *ipRoute
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "nexthop" {
+			if last != nil {
+				last.nexthops = append(last.nexthops, parseNextHop(fields[1:]))
+			}
+			continue
+		}
+
+		last = nil
+		if fields[0] != "default" && !strings.ContainsAny(fields[0], ".:") {
+			// Skip the routes of types other than unicast (e.g., local, broadcast).
+			continue
+		}
+
+		r := ipRoute{dst: normalizeDst(fields[0])}
+		for i := 1; i < len(fields); i++ {
+			if fields[i] == "onlink" {
+				r.onlink = true
+				continue
+			}
+			if i+1 >= len(fields) {
+				break
+			}
+			value := fields[i+1]
+			switch fields[i] {
+			case "via":
+				if value == "inet" || value == "inet6" {
+					i++
+					if i+1 >= len(fields) {
+						continue
+					}
+					value = fields[i+1]
+				}
+				r.gw = renderIP(value)
+			case "dev":
+				r.dev = value
+			case "src":
+				r.src = renderIP(value)
+			case "scope":
+				if value != "global" && value != "universe" {
+					r.scope = value
+				}
+			case "table":
+				r.table = value
+			case "proto", "metric", "pref", "expires", "mtu", "advmss", "hoplimit", "realms":
+			default:
+				continue
+			}
+			i++
+		}
+		if name, ok := tables[r.table]; ok {
+			r.table = name
+			routes = append(routes, r)
+			last = &routes[len(routes)-1]
+		}
+	}
+	return routes
+}
+
+func parseNextHop(fields []string) ipNextHop {
+	nh := ipNextHop{weight: 1}
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "onlink" {
+			nh.onlink = true
+			continue
+		}
+		if i+1 >= len(fields) {
+			break
+		}
+		switch fields[i] {
+		case "via":
+			nh.gw = renderIP(fields[i+1])
+		case "dev":
+			nh.dev = fields[i+1]
+		case "weight":
+			if weight, err := strconv.Atoi(fields[i+1]); err == nil {
+				nh.weight = weight
+			}
+		default:
+			continue
+		}
+		i++
+	}
+	return nh
+}
+
+// normalizeDst normalizes a destination as printed by iproute2, leaving it untouched if it cannot be parsed.
+func normalizeDst(dst string) string {
+	if dst == "default" || dst == "all" {
+		return "default"
+	}
+	if !strings.Contains(dst, "/") {
+		return renderIP(dst)
+	}
+	if normalized, err := renderDst(dst); err == nil {
+		return normalized
+	}
+	return dst
+}
+
+// normalizeMark normalizes a firewall mark, discarding the mask if it matches all the bits.
+func normalizeMark(mark string) string {
+	mark = strings.TrimSuffix(mark, "/0xffffffff")
+	if value, err := strconv.ParseUint(mark, 0, 32); err == nil {
+		return fmt.Sprintf("0x%x", value)
+	}
+	return mark
+}
+
+func ptrToString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,87 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// The following keys and values mirror the ones used by the fabric and gateway components to select the
+// FirewallConfigurations and RouteConfigurations they enforce. They are duplicated here, since the packages
+// defining them depend on the kernel interfaces.
+const (
+	firewallCategoryKey    = "networking.liqo.io/firewall-category"
+	firewallSubCategoryKey = "networking.liqo.io/firewall-subcategory"
+	firewallUniqueKey      = "networking.liqo.io/firewall-unique"
+	routeCategoryKey       = "networking.liqo.io/route-category"
+	routeSubCategoryKey    = "networking.liqo.io/route-subcategory"
+	routeUniqueKey         = "networking.liqo.io/route-unique"
+
+	categoryFabric        = "fabric"
+	categoryGateway       = "gateway"
+	subCategoryAllNodes   = "all-nodes"
+	subCategorySingle     = "single-node"
+	subCategoryIPMapping  = "ip-mapping"
+	subCategoryFabric     = "fabric"
+	subCategoryFabricNode = "fabric-node"
+)
+
+// HostType is the type of host enforcing the FirewallConfigurations and RouteConfigurations.
+type HostType string
+
+const (
+	// HostTypeFabric identifies a node running the fabric component.
+	HostTypeFabric HostType = "fabric"
+	// HostTypeGateway identifies a gateway pod.
+	HostTypeGateway HostType = "gateway"
+)
+
+// Selectors returns the label sets selecting the FirewallConfigurations and RouteConfigurations enforced by the given host.
+// The node name identifies the node hosting the fabric or the gateway, while the remote cluster ID is required for gateways only.
+func Selectors(hostType HostType, nodeName, remoteClusterID string) (firewall, route []labels.Set, err error) {
+	switch hostType {
+	case HostTypeFabric:
+		if nodeName == "" {
+			return nil, nil, fmt.Errorf("node name is required for %s hosts", hostType)
+		}
+		firewall = []labels.Set{
+			{firewallCategoryKey: categoryFabric, firewallSubCategoryKey: subCategoryAllNodes},
+			{firewallCategoryKey: categoryFabric, firewallSubCategoryKey: subCategoryIPMapping},
+			{firewallCategoryKey: categoryFabric, firewallSubCategoryKey: subCategorySingle, firewallUniqueKey: nodeName},
+		}
+		route = []labels.Set{
+			{routeCategoryKey: categoryFabric},
+		}
+	case HostTypeGateway:
+		if nodeName == "" || remoteClusterID == "" {
+			return nil, nil, fmt.Errorf("node name and remote cluster ID are required for %s hosts", hostType)
+		}
+		firewall = []labels.Set{
+			{firewallCategoryKey: categoryGateway, firewallSubCategoryKey: subCategoryFabric},
+			{firewallCategoryKey: categoryGateway, firewallUniqueKey: remoteClusterID},
+			{firewallCategoryKey: categoryGateway, firewallSubCategoryKey: subCategoryIPMapping},
+		}
+		route = []labels.Set{
+			{routeCategoryKey: categoryGateway, routeUniqueKey: remoteClusterID},
+			{routeCategoryKey: categoryGateway, routeSubCategoryKey: subCategoryFabric},
+			{routeCategoryKey: categoryGateway, routeSubCategoryKey: subCategoryFabricNode, routeUniqueKey: nodeName},
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown host type %q", hostType)
+	}
+	return firewall, route, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane_test
+
+import (
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/liqotech/liqo/pkg/dataplane"
+	"github.com/liqotech/liqo/pkg/fabric"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/remapping"
+	"github.com/liqotech/liqo/pkg/route"
+	routeutils "github.com/liqotech/liqo/pkg/utils/route"
+)
+
+func itoa(id uint32) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+var _ = Describe("Selectors", func() {
+	It("should match the ones of the fabric", func() {
+		firewall, routes, err := dataplane.Selectors(dataplane.HostTypeFabric, "node", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(firewall).To(ConsistOf(
+			labels.Set(fabric.ForgeFirewallTargetLabels()),
+			labels.Set(remapping.ForgeFirewallTargetLabelsIPMappingFabric()),
+			labels.Set(fabric.ForgeFirewallTargetLabelsSingleNode("node")),
+		))
+		Expect(routes).To(ConsistOf(labels.Set(fabric.ForgeRouteTargetLabels())))
+	})
+
+	It("should match the ones of the gateway", func() {
+		firewall, routes, err := dataplane.Selectors(dataplane.HostTypeGateway, "node", "remote")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(firewall).To(ConsistOf(
+			labels.Set(gateway.ForgeFirewallInternalTargetLabels()),
+			labels.Set(remapping.ForgeFirewallTargetLabels("remote")),
+			labels.Set(remapping.ForgeFirewallTargetLabelsIPMappingGw()),
+		))
+		Expect(routes).To(ConsistOf(
+			labels.Set(gateway.ForgeRouteExternalTargetLabels("remote")),
+			labels.Set(gateway.ForgeRouteInternalTargetLabels()),
+			labels.Set(gateway.ForgeRouteInternalTargetLabelsByNode("node")),
+		))
+	})
+
+	It("should fail if the remote cluster ID is missing for gateways", func() {
+		_, _, err := dataplane.Selectors(dataplane.HostTypeGateway, "node", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Route table IDs", func() {
+	It("should match the ones generated by the route controller", func() {
+		id, err := route.GetTableID("liqo-test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(routeutils.TableID("liqo-test")))
+		Expect(id).To(BeNumerically(">", 255))
+		Expect(id >> 31).To(BeZero())
+	})
+})
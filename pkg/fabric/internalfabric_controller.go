@@ -90,6 +90,11 @@ func (r *InternalFabricReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if err := geneve.EnsureGeneveInterfaceAbsence(internalfabric.Spec.Interface.Node.Name); err != nil {
 			return ctrl.Result{}, fmt.Errorf("unable to ensure the geneve interface absence: %w", err)
 		}
+		for i := range internalfabric.Spec.Replicas {
+			if err := geneve.EnsureGeneveInterfaceAbsence(internalfabric.Spec.Replicas[i].InterfaceName); err != nil {
+				return ctrl.Result{}, fmt.Errorf("unable to ensure the geneve interface absence: %w", err)
+			}
+		}
 
 		if err = r.ensureinternalfabricFinalizerAbsence(ctx, internalfabric); err != nil {
 			return ctrl.Result{}, err
@@ -122,6 +127,31 @@ func (r *InternalFabricReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	klog.Infof("Enforced interface %s for internalfabric %s", internalfabric.Spec.Interface.Node.Name, internalfabric.Name)
 
+	// Enforce an interface towards each additional replica of active-active gateways, sharing the same tunnel ID.
+	for i := range internalfabric.Spec.Replicas {
+		replica := &internalfabric.Spec.Replicas[i]
+		if replica.GatewayIP == "" {
+			if err := geneve.EnsureGeneveInterfaceAbsence(replica.InterfaceName); err != nil {
+				return ctrl.Result{}, fmt.Errorf("unable to ensure the geneve interface absence: %w", err)
+			}
+			continue
+		}
+
+		if err := geneve.EnsureGeneveInterfacePresence(
+			replica.InterfaceName,
+			internalnode.Spec.Interface.Node.IP.String(),
+			replica.GatewayIP.String(),
+			id,
+			r.Options.DisableARP,
+			internalfabric.Spec.MTU,
+			r.Options.GenevePort,
+		); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to ensure the geneve interface presence for replica %d: %w", replica.Index, err)
+		}
+
+		klog.Infof("Enforced interface %s for replica %d of internalfabric %s", replica.InterfaceName, replica.Index, internalfabric.Name)
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -22,14 +22,14 @@ import (
 	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
 )
 
-func addChains(nftConn *nftables.Conn, chains []firewallapi.Chain, table *nftables.Table) error {
+func addChains(nftConn *nftables.Conn, chains []firewallapi.Chain, sets []firewallapi.Set, table *nftables.Table) error {
 	var err error
 	for i := range chains {
 		var nftchain *nftables.Chain
 		if nftchain, err = addChain(nftConn, &chains[i], table); err != nil {
 			return err
 		}
-		if err = addRules(nftConn, &chains[i], nftchain); err != nil {
+		if err = addRules(nftConn, &chains[i], sets, nftchain); err != nil {
 			return err
 		}
 	}
@@ -214,18 +214,19 @@ func isChainModified(nftChain *nftables.Chain, chain *firewallapi.Chain) bool {
 }
 
 // FromChainToRulesArray converts a chain to an array of rules.
-func FromChainToRulesArray(chain *firewallapi.Chain) (rules []firewallutils.Rule) {
+// The named sets of the table are attached to the rules, since they can be referenced by their matches.
+func FromChainToRulesArray(chain *firewallapi.Chain, sets []firewallapi.Set) (rules []firewallutils.Rule) {
 	switch chain.Type {
 	case firewallapi.ChainTypeFilter:
 		rules = make([]firewallutils.Rule, len(chain.Rules.FilterRules))
 		for i := range chain.Rules.FilterRules {
-			rules[i] = &firewallutils.FilterRuleWrapper{FilterRule: &chain.Rules.FilterRules[i]}
+			rules[i] = &firewallutils.FilterRuleWrapper{FilterRule: &chain.Rules.FilterRules[i], Sets: sets}
 		}
 		return rules
 	case firewallapi.ChainTypeNAT:
 		rules = make([]firewallutils.Rule, len(chain.Rules.NatRules))
 		for i := range chain.Rules.NatRules {
-			rules[i] = &firewallutils.NatRuleWrapper{NatRule: &chain.Rules.NatRules[i]}
+			rules[i] = &firewallutils.NatRuleWrapper{NatRule: &chain.Rules.NatRules[i], Sets: sets}
 		}
 	case firewallapi.ChainTypeRoute:
 		rules = make([]firewallutils.Rule, len(chain.Rules.RouteRules))
@@ -241,16 +242,18 @@ func FromChainToRulesArray(chain *firewallapi.Chain) (rules []firewallutils.Rule
 }
 
 // cleanChain removes all the rules that are not present in the firewall configuration or that have been modified.
-func cleanChain(nftconn *nftables.Conn, chain *firewallapi.Chain, nftChain *nftables.Chain) error {
+// It also removes the rules referencing the outdated sets, since a set cannot be deleted while in use.
+func cleanChain(nftconn *nftables.Conn, chain *firewallapi.Chain, nftChain *nftables.Chain,
+	sets []firewallapi.Set, outdatedSets []*nftables.Set) error {
 	nftRules, err := nftconn.GetRules(nftChain.Table, nftChain)
 	if err != nil {
 		return err
 	}
-	rules := FromChainToRulesArray(chain)
+	rules := FromChainToRulesArray(chain, sets)
 	for i := range nftRules {
 		// If the rule is outdated, delete it.
 		outdated, ruleName := isRuleOutdated(nftRules[i], rules)
-		if outdated {
+		if outdated || isRuleUsingSets(nftRules[i], outdatedSets) {
 			klog.V(2).Infof("deleting rule %s from chain %s", ruleName, nftChain.Name)
 			if err := nftconn.DelRule(nftRules[i]); err != nil {
 				return err
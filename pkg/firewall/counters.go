@@ -0,0 +1,222 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/userdata"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+// countersRefreshPeriod is the period after which the counters reported in the status are refreshed.
+// It is longer than the scraping interval of the metrics, which expose the up-to-date values,
+// since the status is shared by all the hosts applying the resource.
+const countersRefreshPeriod = 5 * time.Minute
+
+var metricsLabels = []string{"namespace", "name", "chain", "rule"}
+
+var (
+	// MetricsRulePackets is the metric that counts the number of packets matched by a firewall rule.
+	MetricsRulePackets = prometheus.NewDesc(
+		"liqo_firewall_rule_packets_total",
+		"Number of packets matched by a rule of a FirewallConfiguration.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsRuleBytes is the metric that counts the number of bytes matched by a firewall rule.
+	MetricsRuleBytes = prometheus.NewDesc(
+		"liqo_firewall_rule_bytes_total",
+		"Number of bytes matched by a rule of a FirewallConfiguration.",
+		metricsLabels,
+		nil,
+	)
+)
+
+// ruleCounter contains the values of the counter of a rule.
+type ruleCounter struct {
+	chain   string
+	rule    string
+	packets uint64
+	bytes   uint64
+}
+
+// countedTables keeps track of the applied tables containing rules with counting enabled.
+type countedTables struct {
+	mutex  sync.RWMutex
+	tables map[types.NamespacedName]*firewallapi.Table
+}
+
+func (ct *countedTables) track(nsName types.NamespacedName, table *firewallapi.Table) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+	ct.tables[nsName] = table.DeepCopy()
+}
+
+func (ct *countedTables) untrack(nsName types.NamespacedName) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+	delete(ct.tables, nsName)
+}
+
+func (ct *countedTables) list() map[types.NamespacedName]*firewallapi.Table {
+	ct.mutex.RLock()
+	defer ct.mutex.RUnlock()
+	tables := make(map[types.NamespacedName]*firewallapi.Table, len(ct.tables))
+	for k, v := range ct.tables {
+		tables[k] = v
+	}
+	return tables
+}
+
+// hasCounters returns whether the table contains at least a rule with counting enabled.
+func hasCounters(table *firewallapi.Table) bool {
+	for i := range table.Chains {
+		for j := range table.Chains[i].Rules.FilterRules {
+			if table.Chains[i].Rules.FilterRules[j].Counter {
+				return true
+			}
+		}
+		for j := range table.Chains[i].Rules.NatRules {
+			if table.Chains[i].Rules.NatRules[j].Counter {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readCounters reads the counters of the rules of the given table.
+func readCounters(nftconn *nftables.Conn, table *firewallapi.Table) ([]ruleCounter, error) {
+	exist, err := existTable(nftconn, table)
+	if err != nil || !exist {
+		return nil, err
+	}
+	nftChains, err := nftconn.ListChainsOfTableFamily(firewallutils.GetTableFamily(*table.Family))
+	if err != nil {
+		return nil, err
+	}
+
+	var counters []ruleCounter
+	for i := range nftChains {
+		if nftChains[i].Table.Name != *table.Name {
+			continue
+		}
+		nftRules, err := nftconn.GetRules(nftChains[i].Table, nftChains[i])
+		if err != nil {
+			return nil, err
+		}
+		for j := range nftRules {
+			name, ok := userdata.GetString(nftRules[j].UserData, userdata.TypeComment)
+			if !ok {
+				continue
+			}
+			if counter := firewallutils.GetCounter(nftRules[j]); counter != nil {
+				counters = append(counters, ruleCounter{
+					chain: nftChains[i].Name, rule: name, packets: counter.Packets, bytes: counter.Bytes,
+				})
+			}
+		}
+	}
+	return counters, nil
+}
+
+// areStatusCountersExpired returns whether the counters reported in the status for the given host need to be refreshed,
+// together with the time remaining before the next refresh.
+func areStatusCountersExpired(fwcfg *networkingv1beta1.FirewallConfiguration, host string) (expired bool, remaining time.Duration) {
+	for i := range fwcfg.Status.Counters {
+		if fwcfg.Status.Counters[i].Host != host {
+			continue
+		}
+		remaining = countersRefreshPeriod - time.Since(fwcfg.Status.Counters[i].LastUpdateTime.Time)
+		return remaining <= 0, remaining
+	}
+	return true, 0
+}
+
+// setStatusCounters replaces the counters reported in the status for the given host.
+func setStatusCounters(fwcfg *networkingv1beta1.FirewallConfiguration, host string, counters []ruleCounter) {
+	statusCounters := make([]networkingv1beta1.FirewallConfigurationStatusCounter, 0, len(fwcfg.Status.Counters)+len(counters))
+	for i := range fwcfg.Status.Counters {
+		if fwcfg.Status.Counters[i].Host != host {
+			statusCounters = append(statusCounters, fwcfg.Status.Counters[i])
+		}
+	}
+	now := metav1.Now()
+	for i := range counters {
+		statusCounters = append(statusCounters, networkingv1beta1.FirewallConfigurationStatusCounter{
+			Host:           host,
+			Chain:          counters[i].chain,
+			Rule:           counters[i].rule,
+			Packets:        int64(counters[i].packets), //nolint:gosec // Counters do not overflow in practice.
+			Bytes:          int64(counters[i].bytes),   //nolint:gosec // Counters do not overflow in practice.
+			LastUpdateTime: now,
+		})
+	}
+	if len(statusCounters) == 0 {
+		statusCounters = nil
+	}
+	fwcfg.Status.Counters = statusCounters
+}
+
+var _ prometheus.Collector = &PrometheusCollector{}
+
+// PrometheusCollector is a prometheus.Collector that collects the counters of the firewall rules,
+// together with the divergences of the enforced configurations.
+type PrometheusCollector struct {
+	reconciler *FirewallConfigurationReconciler
+}
+
+// NewPrometheusCollector creates a new PrometheusCollector.
+func NewPrometheusCollector(reconciler *FirewallConfigurationReconciler) *PrometheusCollector {
+	return &PrometheusCollector{reconciler: reconciler}
+}
+
+// Describe implements prometheus.Collector.
+func (pc *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- MetricsRulePackets
+	ch <- MetricsRuleBytes
+	MetricsDrifts.Describe(ch)
+	MetricsLastDriftCheck.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	MetricsDrifts.Collect(ch)
+	MetricsLastDriftCheck.Collect(ch)
+	for nsName, table := range pc.reconciler.countedTables.list() {
+		counters, err := readCounters(pc.reconciler.NftConnection, table)
+		if err != nil {
+			err = fmt.Errorf("error collecting counters of firewallconfiguration %s: %w", nsName, err)
+			ch <- prometheus.NewInvalidMetric(MetricsRulePackets, err)
+			ch <- prometheus.NewInvalidMetric(MetricsRuleBytes, err)
+			continue
+		}
+		for i := range counters {
+			labels := []string{nsName.Namespace, nsName.Name, counters[i].chain, counters[i].rule}
+			ch <- prometheus.MustNewConstMetric(MetricsRulePackets, prometheus.CounterValue, float64(counters[i].packets), labels...)
+			ch <- prometheus.MustNewConstMetric(MetricsRuleBytes, prometheus.CounterValue, float64(counters[i].bytes), labels...)
+		}
+	}
+}
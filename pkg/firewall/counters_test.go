@@ -0,0 +1,150 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/google/nftables"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netns"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+var _ = Describe("Counters", func() {
+	statusCounter := func(host, rule string, packets, bytes int64, lastUpdate time.Time) networkingv1beta1.FirewallConfigurationStatusCounter {
+		return networkingv1beta1.FirewallConfigurationStatusCounter{
+			Host: host, Chain: "forward", Rule: rule, Packets: packets, Bytes: bytes, LastUpdateTime: metav1.NewTime(lastUpdate),
+		}
+	}
+
+	DescribeTable("should detect the tables with counting enabled",
+		func(chain firewallapi.Chain, expected bool) {
+			Expect(hasCounters(&firewallapi.Table{Chains: []firewallapi.Chain{chain}})).To(Equal(expected))
+		},
+		Entry("no rules", firewallapi.Chain{}, false),
+		Entry("rules without counters", firewallapi.Chain{Rules: firewallapi.RulesSet{
+			FilterRules: []firewallapi.FilterRule{{Name: ptr.To("a"), Log: true}},
+			NatRules:    []firewallapi.NatRule{{Name: ptr.To("b")}},
+		}}, false),
+		Entry("a filter rule with counter", firewallapi.Chain{Rules: firewallapi.RulesSet{
+			FilterRules: []firewallapi.FilterRule{{Name: ptr.To("a")}, {Name: ptr.To("b"), Counter: true}},
+		}}, true),
+		Entry("a nat rule with counter", firewallapi.Chain{Rules: firewallapi.RulesSet{
+			NatRules: []firewallapi.NatRule{{Name: ptr.To("a"), Counter: true}},
+		}}, true),
+	)
+
+	It("should read the counters of the rules with counting enabled from the kernel", func() {
+		if os.Geteuid() != 0 {
+			Skip("the creation of the network namespaces requires root privileges")
+		}
+
+		runtime.LockOSThread()
+		origin, err := netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+		ns, err := netns.New()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(netns.Set(origin)).To(Succeed())
+		runtime.UnlockOSThread()
+		defer func() {
+			Expect(origin.Close()).To(Succeed())
+			Expect(ns.Close()).To(Succeed())
+		}()
+
+		nftconn, err := nftables.New(nftables.WithNetNSFd(int(ns)))
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { Expect(nftconn.CloseLasting()).To(Succeed()) }()
+
+		table := &firewallapi.Table{Name: ptr.To("liqo-test"), Family: ptr.To(firewallapi.TableFamilyIPv4)}
+		nftTable := nftconn.AddTable(&nftables.Table{Name: *table.Name, Family: nftables.TableFamilyIPv4})
+		chain := nftconn.AddChain(&nftables.Chain{Name: "forward", Table: nftTable, Type: nftables.ChainTypeFilter,
+			Hooknum: nftables.ChainHookForward, Priority: nftables.ChainPriorityFilter})
+		for _, rule := range []*firewallapi.FilterRule{
+			{Name: ptr.To("counted"), Action: firewallapi.ActionAccept, Counter: true},
+			{Name: ptr.To("uncounted"), Action: firewallapi.ActionAccept},
+		} {
+			nftRule, err := (&firewallutils.FilterRuleWrapper{FilterRule: rule}).Forge(chain)
+			Expect(err).ToNot(HaveOccurred())
+			nftconn.AddRule(nftRule)
+		}
+		if err := nftconn.Flush(); err != nil {
+			Skip("nf_tables not supported: " + err.Error())
+		}
+
+		Expect(readCounters(nftconn, table)).To(ConsistOf(ruleCounter{chain: "forward", rule: "counted"}))
+	})
+
+	Describe("the counters reported in the status", func() {
+		var fwcfg *networkingv1beta1.FirewallConfiguration
+
+		BeforeEach(func() {
+			fwcfg = &networkingv1beta1.FirewallConfiguration{Status: networkingv1beta1.FirewallConfigurationStatus{
+				Counters: []networkingv1beta1.FirewallConfigurationStatusCounter{
+					statusCounter("node-a", "allow-web", 10, 1000, time.Now().Add(-time.Minute)),
+					statusCounter("node-a", "allow-dns", 20, 2000, time.Now().Add(-time.Minute)),
+					statusCounter("node-b", "allow-web", 30, 3000, time.Now().Add(-time.Hour)),
+				},
+			}}
+		})
+
+		It("should replace the counters of the given host only", func() {
+			setStatusCounters(fwcfg, "node-a", []ruleCounter{{chain: "forward", rule: "allow-web", packets: 15, bytes: 1500}})
+			Expect(fwcfg.Status.Counters).To(HaveLen(2))
+			Expect(fwcfg.Status.Counters[0]).To(Equal(statusCounter("node-b", "allow-web", 30, 3000, fwcfg.Status.Counters[0].LastUpdateTime.Time)))
+			Expect(fwcfg.Status.Counters[1]).To(And(
+				HaveField("Host", "node-a"), HaveField("Rule", "allow-web"),
+				HaveField("Packets", int64(15)), HaveField("Bytes", int64(1500)),
+				HaveField("LastUpdateTime.Time", BeTemporally("~", time.Now(), time.Second)),
+			))
+		})
+
+		It("should add the counters of a new host", func() {
+			setStatusCounters(fwcfg, "node-c", []ruleCounter{
+				{chain: "forward", rule: "allow-web", packets: 1, bytes: 100},
+				{chain: "forward", rule: "allow-dns", packets: 2, bytes: 200},
+			})
+			Expect(fwcfg.Status.Counters).To(HaveLen(5))
+			Expect(fwcfg.Status.Counters[3:]).To(HaveEach(HaveField("Host", "node-c")))
+		})
+
+		It("should remove the counters of a host without counted rules", func() {
+			setStatusCounters(fwcfg, "node-a", nil)
+			setStatusCounters(fwcfg, "node-b", nil)
+			Expect(fwcfg.Status.Counters).To(BeNil())
+		})
+
+		DescribeTable("should detect whether the counters of the host are expired",
+			func(host string, expectedExpired bool) {
+				expired, remaining := areStatusCountersExpired(fwcfg, host)
+				Expect(expired).To(Equal(expectedExpired))
+				if !expectedExpired {
+					Expect(remaining).To(BeNumerically("~", countersRefreshPeriod-time.Minute, time.Second))
+				}
+			},
+			Entry("recently updated counters", "node-a", false),
+			Entry("outdated counters", "node-b", true),
+			Entry("a host without counters", "node-c", true),
+		)
+	})
+})
@@ -0,0 +1,168 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"github.com/google/nftables"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+	"github.com/liqotech/liqo/pkg/utils/drift"
+)
+
+var (
+	// MetricsDrifts is the metric that counts the divergences of the enforced nftables configuration from the desired one.
+	MetricsDrifts = drift.NewMetricsDrifts("firewall",
+		"Number of divergences of the nftables configuration enforced by a FirewallConfiguration from the desired one.")
+	// MetricsLastDriftCheck is the metric that reports the last time the enforced nftables configuration has been checked.
+	MetricsLastDriftCheck = drift.NewMetricsLastCheck("firewall",
+		"Last time the nftables configuration enforced by a FirewallConfiguration has been compared with the desired one.")
+)
+
+// detectDrifts compares the nftables configuration currently enforced with the desired table.
+func detectDrifts(nftconn *nftables.Conn, table *firewallapi.Table) ([]drift.Drift, error) {
+	exist, err := existTable(nftconn, table)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return []drift.Drift{drift.NewDrift(drift.KindTable, "table %s is missing", *table.Name)}, nil
+	}
+
+	nftTable := &nftables.Table{Name: *table.Name, Family: firewallutils.GetTableFamily(*table.Family)}
+	drifts, err := detectSetsDrifts(nftconn, table, nftTable)
+	if err != nil {
+		return nil, err
+	}
+	chainsDrifts, err := detectChainsDrifts(nftconn, table)
+	if err != nil {
+		return nil, err
+	}
+	return append(drifts, chainsDrifts...), nil
+}
+
+func detectSetsDrifts(nftconn *nftables.Conn, table *firewallapi.Table, nftTable *nftables.Table) ([]drift.Drift, error) {
+	nftSets, err := nftconn.GetSets(nftTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []drift.Drift
+	for i := range nftSets {
+		if !nftSets[i].Anonymous && isSetOutdated(nftSets[i], table) {
+			drifts = append(drifts, drift.NewDrift(drift.KindSet, "set %s is unexpected or modified", nftSets[i].Name))
+		}
+	}
+
+	for i := range table.Sets {
+		var current *nftables.Set
+		for j := range nftSets {
+			if nftSets[j].Name == table.Sets[i].Name {
+				current = nftSets[j]
+				break
+			}
+		}
+		if current == nil {
+			drifts = append(drifts, drift.NewDrift(drift.KindSet, "set %s is missing", table.Sets[i].Name))
+			continue
+		}
+		if isSetOutdated(current, table) {
+			continue
+		}
+
+		desired, elements, err := firewallutils.ForgeSet(&table.Sets[i], nftTable)
+		if err != nil {
+			return nil, err
+		}
+		stale, missing, err := diffSetElements(nftconn, &table.Sets[i], current, desired, elements)
+		if err != nil {
+			return nil, err
+		}
+		if len(stale) > 0 || len(missing) > 0 {
+			drifts = append(drifts, drift.NewDrift(drift.KindSet, "set %s has %d unexpected and %d missing elements",
+				table.Sets[i].Name, len(stale), len(missing)))
+		}
+	}
+	return drifts, nil
+}
+
+func detectChainsDrifts(nftconn *nftables.Conn, table *firewallapi.Table) ([]drift.Drift, error) {
+	nftChains, err := nftconn.ListChainsOfTableFamily(firewallutils.GetTableFamily(*table.Family))
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []drift.Drift
+	found := make([]bool, len(table.Chains))
+	for i := range nftChains {
+		if nftChains[i].Table.Name != *table.Name {
+			continue
+		}
+		outdated, chainIndex := isChainOutdated(nftChains[i], table.Chains)
+		if chainIndex >= 0 {
+			found[chainIndex] = true
+		}
+		if outdated {
+			drifts = append(drifts, drift.NewDrift(drift.KindChain, "chain %s is unexpected or modified", nftChains[i].Name))
+			continue
+		}
+
+		nftRules, err := nftconn.GetRules(nftChains[i].Table, nftChains[i])
+		if err != nil {
+			return nil, err
+		}
+		rules := FromChainToRulesArray(&table.Chains[chainIndex], table.Sets)
+		for j := range nftRules {
+			if outdated, ruleName := isRuleOutdated(nftRules[j], rules); outdated {
+				drifts = append(drifts, drift.NewDrift(drift.KindRule,
+					"rule %s in chain %s is unexpected or modified", ruleName, nftChains[i].Name))
+			}
+		}
+		for j := range rules {
+			if !existRule(nftRules, rules[j]) {
+				drifts = append(drifts, drift.NewDrift(drift.KindRule,
+					"rule %s in chain %s is missing", ptrToString(rules[j].GetName()), nftChains[i].Name))
+			}
+		}
+	}
+
+	for i := range table.Chains {
+		if !found[i] {
+			drifts = append(drifts, drift.NewDrift(drift.KindChain, "chain %s is missing", ptrToString(table.Chains[i].Name)))
+		}
+	}
+	return drifts, nil
+}
+
+func ptrToString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// checkDrifts compares the enforced configuration with the desired one, recording the outcome in the condition of the host.
+func (r *FirewallConfigurationReconciler) checkDrifts(fwcfg *networkingv1beta1.FirewallConfiguration) error {
+	checked, drifted, err := r.driftChecker.Check(fwcfg, func() ([]drift.Drift, error) {
+		return detectDrifts(r.NftConnection, &fwcfg.Spec.Table)
+	})
+	if err != nil || !checked {
+		return err
+	}
+	conditionRef := getConditionRef(fwcfg, r.PodName)
+	drift.SetStatusTimes(&conditionRef.LastCheckTime, &conditionRef.LastDriftTime, drifted)
+	return nil
+}
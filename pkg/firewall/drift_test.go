@@ -0,0 +1,149 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/google/nftables"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netns"
+	"k8s.io/utils/ptr"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	"github.com/liqotech/liqo/pkg/utils/drift"
+)
+
+var _ = Describe("Drifts", func() {
+	const (
+		eq  = firewallapi.MatchOperationEq
+		neq = firewallapi.MatchOperationNeq
+	)
+
+	var (
+		ns      netns.NsHandle
+		nftconn *nftables.Conn
+
+		tcp  = &firewallapi.MatchProto{Value: firewallapi.L4ProtoTCP}
+		sets = []firewallapi.Set{
+			{Name: "addrs", KeyType: firewallapi.SetDataTypeIPAddr, Interval: true,
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.0/24"}, {Key: "10.1.0.1"}}},
+			{Name: "devs", KeyType: firewallapi.SetDataTypeIfName,
+				Elements: []firewallapi.SetElement{{Key: "eth0"}}},
+			{Name: "verdicts", KeyType: firewallapi.SetDataTypeIPAddr, DataType: ptr.To(firewallapi.SetDataTypeVerdict),
+				Elements: []firewallapi.SetElement{{Key: "10.0.0.1", Value: ptr.To("drop")}}},
+		}
+	)
+
+	// forgeTable returns a table with a forward chain containing a single rule with the given matches.
+	forgeTable := func(match ...firewallapi.Match) *firewallapi.Table {
+		return &firewallapi.Table{
+			Name:   ptr.To("liqo-test"),
+			Family: ptr.To(firewallapi.TableFamilyIPv4),
+			Sets:   sets,
+			Chains: []firewallapi.Chain{{
+				Name:     ptr.To("forward"),
+				Type:     firewallapi.ChainTypeFilter,
+				Hook:     ptr.To(firewallapi.ChainHookForward),
+				Policy:   ptr.To(firewallapi.ChainPolicyAccept),
+				Priority: ptr.To(firewallapi.ChainPriority(0)),
+				Rules: firewallapi.RulesSet{FilterRules: []firewallapi.FilterRule{
+					{Name: ptr.To("test"), Match: match, Action: firewallapi.ActionAccept},
+				}},
+			}},
+		}
+	}
+
+	// enforce applies the given table, as the reconciler does.
+	enforce := func(table *firewallapi.Table) {
+		Expect(cleanTable(nftconn, table)).To(Succeed())
+		Expect(nftconn.Flush()).To(Succeed())
+		nftTable := addTable(nftconn, table)
+		Expect(addSets(nftconn, table, nftTable)).To(Succeed())
+		Expect(addChains(nftconn, table.Chains, table.Sets, nftTable)).To(Succeed())
+		Expect(nftconn.Flush()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		if os.Geteuid() != 0 {
+			Skip("the creation of the network namespaces requires root privileges")
+		}
+
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		origin, err := netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+		defer origin.Close()
+		ns, err = netns.New()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(netns.Set(origin)).To(Succeed())
+
+		nftconn, err = nftables.New(nftables.WithNetNSFd(int(ns)))
+		Expect(err).ToNot(HaveOccurred())
+		nftconn.AddTable(&nftables.Table{Name: "liqo-probe", Family: nftables.TableFamilyIPv4})
+		if err := nftconn.Flush(); err != nil {
+			Skip("nf_tables not supported: " + err.Error())
+		}
+	})
+
+	AfterEach(func() {
+		if nftconn != nil {
+			Expect(nftconn.CloseLasting()).To(Succeed())
+		}
+		Expect(ns.Close()).To(Succeed())
+	})
+
+	DescribeTable("should not detect drifts in the enforced configuration",
+		func(match ...firewallapi.Match) {
+			table := forgeTable(match...)
+			enforce(table)
+
+			Expect(detectDrifts(nftconn, table)).To(BeEmpty())
+			Expect(detectChainsDrifts(nftconn, table)).To(BeEmpty())
+		},
+		Entry("ip", firewallapi.Match{Op: eq, IP: &firewallapi.MatchIP{Value: "10.0.0.1", Position: firewallapi.MatchPositionSrc}}),
+		Entry("ip subnet", firewallapi.Match{Op: neq, IP: &firewallapi.MatchIP{Value: "10.0.0.0/8", Position: firewallapi.MatchPositionDst}}),
+		Entry("ip set", firewallapi.Match{Op: neq, IP: &firewallapi.MatchIP{Value: "@addrs", Position: firewallapi.MatchPositionSrc}}),
+		Entry("ip verdict map", firewallapi.Match{Op: eq, IP: &firewallapi.MatchIP{Value: "@verdicts", Position: firewallapi.MatchPositionSrc}}),
+		Entry("port", firewallapi.Match{Op: neq, Proto: tcp, Port: &firewallapi.MatchPort{Value: "80", Position: firewallapi.MatchPositionDst}}),
+		Entry("port range", firewallapi.Match{Op: eq, Proto: tcp, Port: &firewallapi.MatchPort{Value: "1000-2000", Position: firewallapi.MatchPositionSrc}}),
+		Entry("proto", firewallapi.Match{Op: eq, Proto: tcp}),
+		Entry("dev", firewallapi.Match{Op: neq, Dev: &firewallapi.MatchDev{Value: "eth0", Position: firewallapi.MatchDevPositionOut}}),
+		Entry("dev set", firewallapi.Match{Op: eq, Dev: &firewallapi.MatchDev{Value: "@devs", Position: firewallapi.MatchDevPositionIn}}),
+		Entry("icmp", firewallapi.Match{Op: eq, ICMP: &firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest}}),
+		Entry("ct state", firewallapi.Match{Op: eq, CtState: &firewallapi.MatchCtState{
+			Value: []firewallapi.CtState{firewallapi.CtStateRelated, firewallapi.CtStateEstablished}}}),
+		Entry("meta mark", firewallapi.Match{Op: eq, Mark: &firewallapi.MatchMark{Value: "0x10", Type: firewallapi.MatchMarkTypeMeta}}),
+		Entry("ct mark", firewallapi.Match{Op: neq, Mark: &firewallapi.MatchMark{Value: "0x10/0xff", Type: firewallapi.MatchMarkTypeCt}}),
+		Entry("limit", firewallapi.Match{Op: neq, Limit: &firewallapi.MatchLimit{Rate: 10, Unit: firewallapi.LimitUnitMinute, Burst: ptr.To[int32](20)}}),
+	)
+
+	It("should detect the rules removed from the enforced configuration", func() {
+		table := forgeTable(firewallapi.Match{Op: eq, Proto: tcp})
+		enforce(table)
+
+		chain, err := getChain(nftconn, &nftables.Table{Name: *table.Name, Family: nftables.TableFamilyIPv4}, &table.Chains[0])
+		Expect(err).ToNot(HaveOccurred())
+		nftconn.FlushChain(chain)
+		Expect(nftconn.Flush()).To(Succeed())
+
+		drifts, err := detectChainsDrifts(nftconn, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drifts).To(ConsistOf(HaveField("Kind", drift.KindRule)))
+	})
+})
@@ -20,10 +20,12 @@ import (
 	"fmt"
 
 	"github.com/google/nftables"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -35,7 +37,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/drift"
 	"github.com/liqotech/liqo/pkg/utils/network/netmonitor"
 )
 
@@ -52,6 +56,9 @@ type FirewallConfigurationReconciler struct {
 	LabelsSets []labels.Set
 	// EnableFinalizer is used to enable the finalizer on the reconciled resources.
 	EnableFinalizer bool
+
+	countedTables countedTables
+	driftChecker  *drift.Checker
 }
 
 // newFirewallConfigurationReconciler returns a new FirewallConfigurationReconciler.
@@ -69,6 +76,8 @@ func newFirewallConfigurationReconciler(cl client.Client, s *runtime.Scheme, pod
 		EventsRecorder:  er,
 		LabelsSets:      labelsSets,
 		EnableFinalizer: enableFinalizer,
+		countedTables:   countedTables{tables: map[types.NamespacedName]*firewallapi.Table{}},
+		driftChecker:    drift.NewChecker("FirewallConfiguration", podname, er, MetricsDrifts, MetricsLastDriftCheck),
 	}, nil
 }
 
@@ -97,6 +106,8 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 	if err = r.Get(ctx, req.NamespacedName, fwcfg); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.Infof("There is no firewallconfiguration %s", req.String())
+			r.countedTables.untrack(req.NamespacedName)
+			r.driftChecker.Forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("unable to get the firewallconfiguration %q: %w", req.NamespacedName, err)
@@ -104,8 +115,9 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 
 	klog.V(4).Infof("Reconciling firewallconfiguration %s", req.String())
 
+	originalStatus := fwcfg.Status.DeepCopy()
 	defer func() {
-		err = r.UpdateStatus(ctx, r.EventsRecorder, fwcfg, r.PodName, err)
+		err = r.UpdateStatus(ctx, r.EventsRecorder, fwcfg, originalStatus, r.PodName, err)
 	}()
 
 	// Manage Finalizers and Table deletion.
@@ -113,13 +125,15 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 
 	if fwcfg.DeletionTimestamp.IsZero() && r.EnableFinalizer {
 		if !ctrlutil.ContainsFinalizer(fwcfg, firewallConfigurationsControllerFinalizer) {
+			// The update of the finalizer does not trigger a new reconciliation, hence the configuration is applied straight away.
 			if err = r.ensureFirewallConfigurationFinalizerPresence(ctx, fwcfg); err != nil {
 				return ctrl.Result{}, err
 			}
-			return ctrl.Result{}, nil
 		}
 	} else if r.EnableFinalizer {
 		if ctrlutil.ContainsFinalizer(fwcfg, firewallConfigurationsControllerFinalizer) {
+			r.countedTables.untrack(req.NamespacedName)
+			r.driftChecker.Forget(req.NamespacedName)
 			delTable(r.NftConnection, &fwcfg.Spec.Table)
 			if err = r.NftConnection.Flush(); err != nil {
 				return ctrl.Result{}, err
@@ -132,6 +146,11 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, nil
 	}
 
+	// Detect whether the enforced configuration has been modified by other actors, before restoring it.
+	if err = r.checkDrifts(fwcfg); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// If table exists, it delete chains and rules which are not contained anymore in firewallconfiguration resource.
 	// It also deletes chains and rules which has been updated and need to be recreated.
 	if err = cleanTable(r.NftConnection, &fwcfg.Spec.Table); err != nil {
@@ -148,7 +167,12 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 	// Enforce table existence.
 	table := addTable(r.NftConnection, &fwcfg.Spec.Table)
 
-	if err = addChains(r.NftConnection, fwcfg.Spec.Table.Chains, table); err != nil {
+	// Sets are added before the chains, since rules can reference them.
+	if err = addSets(r.NftConnection, &fwcfg.Spec.Table, table); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = addChains(r.NftConnection, fwcfg.Spec.Table.Chains, fwcfg.Spec.Table.Sets, table); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -157,8 +181,29 @@ func (r *FirewallConfigurationReconciler) Reconcile(ctx context.Context, req ctr
 	}
 
 	klog.Infof("Applied firewallconfiguration %s", req.String())
+	r.driftChecker.Applied(fwcfg)
+
+	// The resource is periodically reconciled, to detect and restore the divergences of the enforced configuration.
+	if !hasCounters(&fwcfg.Spec.Table) {
+		r.countedTables.untrack(req.NamespacedName)
+		setStatusCounters(fwcfg, r.PodName, nil)
+		return ctrl.Result{RequeueAfter: drift.CheckPeriod}, nil
+	}
+
+	// The counters in the status are refreshed periodically, and not at every reconciliation,
+	// since the status is shared by all the hosts. Up-to-date values are exposed through the metrics.
+	r.countedTables.track(req.NamespacedName, &fwcfg.Spec.Table)
+	expired, remaining := areStatusCountersExpired(fwcfg, r.PodName)
+	if !expired {
+		return ctrl.Result{RequeueAfter: min(remaining, drift.CheckPeriod)}, nil
+	}
+	var counters []ruleCounter
+	if counters, err = readCounters(r.NftConnection, &fwcfg.Spec.Table); err != nil {
+		return ctrl.Result{}, err
+	}
+	setStatusCounters(fwcfg, r.PodName, counters)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: min(countersRefreshPeriod, drift.CheckPeriod)}, nil
 }
 
 // SetupWithManager register the FirewallConfigurationReconciler to the manager.
@@ -176,7 +221,7 @@ func (r *FirewallConfigurationReconciler) SetupWithManager(ctx context.Context,
 		}()
 	}
 	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlFirewallConfiguration).
-		For(&networkingv1beta1.FirewallConfiguration{}, builder.WithPredicates(filterByLabelsPredicate)).
+		For(&networkingv1beta1.FirewallConfiguration{}, builder.WithPredicates(filterByLabelsPredicate, ignoreStatusUpdatesPredicate())).
 		WatchesRawSource(NewFirewallWatchSource(src, NewFirewallWatchEventHandler(r.Client, r.LabelsSets))).
 		Complete(r)
 }
@@ -193,6 +238,12 @@ func forgeLabelsPredicate(labelsSets []labels.Set) (predicate.Predicate, error)
 	return predicate.Or(labelPredicates...), nil
 }
 
+// ignoreStatusUpdatesPredicate returns a predicate that filters out the updates involving only the status,
+// which are performed by all the hosts applying the resource, and would otherwise trigger a reconciliation on each of them.
+func ignoreStatusUpdatesPredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
+
 func getConditionRef(fwcfg *networkingv1beta1.FirewallConfiguration, podname string) *networkingv1beta1.FirewallConfigurationStatusCondition {
 	var conditionRef *networkingv1beta1.FirewallConfigurationStatusCondition
 	for i := range fwcfg.Status.Conditions {
@@ -206,13 +257,16 @@ func getConditionRef(fwcfg *networkingv1beta1.FirewallConfiguration, podname str
 			Host: podname,
 		}
 		fwcfg.Status.Conditions = append(fwcfg.Status.Conditions, *conditionRef)
+		// The condition is appended by value, hence the reference must point to the element of the slice.
+		conditionRef = &fwcfg.Status.Conditions[len(fwcfg.Status.Conditions)-1]
 	}
 	return conditionRef
 }
 
-// UpdateStatus updates the status of the given FirewallConfiguration.
+// UpdateStatus updates the status of the given FirewallConfiguration, patching it only if it differs from the original one.
 func (r *FirewallConfigurationReconciler) UpdateStatus(ctx context.Context, er record.EventRecorder,
-	fwcfg *networkingv1beta1.FirewallConfiguration, podname string, err error) error {
+	fwcfg *networkingv1beta1.FirewallConfiguration, originalStatus *networkingv1beta1.FirewallConfigurationStatus,
+	podname string, err error) error {
 	conditionRef := getConditionRef(fwcfg, podname)
 	conditionRef.Host = podname
 	conditionRef.Type = networkingv1beta1.FirewallConfigurationStatusConditionTypeApplied
@@ -225,10 +279,15 @@ func (r *FirewallConfigurationReconciler) UpdateStatus(ctx context.Context, er r
 	}
 	if oldStatus != conditionRef.Status {
 		conditionRef.LastTransitionTime = metav1.Now()
+		er.Eventf(fwcfg, "Normal", "FirewallConfigurationUpdate", "FirewallConfiguration %s: %s", conditionRef.Type, conditionRef.Status)
 	}
 
-	er.Eventf(fwcfg, "Normal", "FirewallConfigurationUpdate", "FirewallConfiguration %s: %s", conditionRef.Type, conditionRef.Status)
-	if clerr := r.Client.Status().Update(ctx, fwcfg); clerr != nil {
+	if equality.Semantic.DeepEqual(originalStatus, &fwcfg.Status) {
+		return err
+	}
+	original := fwcfg.DeepCopy()
+	original.Status = *originalStatus
+	if clerr := r.Client.Status().Patch(ctx, fwcfg, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); clerr != nil {
 		err = errors.Join(err, clerr)
 	}
 	return err
@@ -22,8 +22,8 @@ import (
 	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
 )
 
-func addRules(nftconn *nftables.Conn, chain *firewallapi.Chain, nftchain *nftables.Chain) error {
-	apirules := FromChainToRulesArray(chain)
+func addRules(nftconn *nftables.Conn, chain *firewallapi.Chain, sets []firewallapi.Set, nftchain *nftables.Chain) error {
+	apirules := FromChainToRulesArray(chain, sets)
 	nftrules, err := nftconn.GetRules(nftchain.Table, nftchain)
 	if err != nil {
 		return err
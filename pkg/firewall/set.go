@@ -0,0 +1,261 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+// existTable checks whether the given table is already present.
+func existTable(nftconn *nftables.Conn, table *firewallapi.Table) (bool, error) {
+	nftTables, err := nftconn.ListTablesOfFamily(firewallutils.GetTableFamily(*table.Family))
+	if err != nil {
+		return false, err
+	}
+	for i := range nftTables {
+		if nftTables[i].Name == *table.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getOutdatedSets returns the sets of the table which are not present in the firewall configuration or whose type has been modified.
+func getOutdatedSets(nftconn *nftables.Conn, table *firewallapi.Table) ([]*nftables.Set, error) {
+	exist, err := existTable(nftconn, table)
+	if err != nil || !exist {
+		return nil, err
+	}
+	nftSets, err := nftconn.GetSets(&nftables.Table{Name: *table.Name, Family: firewallutils.GetTableFamily(*table.Family)})
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []*nftables.Set
+	for i := range nftSets {
+		// Anonymous sets are owned by the rules using them.
+		if nftSets[i].Anonymous {
+			continue
+		}
+		if isSetOutdated(nftSets[i], table) {
+			outdated = append(outdated, nftSets[i])
+		}
+	}
+	return outdated, nil
+}
+
+// isSetOutdated checks if the set has to be deleted.
+// A set must be deleted when its type or flags change
+// or when it is not contained in the FirewallConfiguration CRD.
+func isSetOutdated(nftSet *nftables.Set, table *firewallapi.Table) bool {
+	set, err := firewallutils.FindSet(table.Sets, nftSet.Name)
+	if err != nil {
+		return true
+	}
+	desired, _, err := firewallutils.ForgeSet(set, nftSet.Table)
+	if err != nil {
+		return true
+	}
+	if desired.Interval != nftSet.Interval || desired.IsMap != nftSet.IsMap {
+		return true
+	}
+	// The type of the key of verdict maps is not reported back by the kernel, hence only the data type can be compared.
+	if firewallutils.IsVerdictMap(set) {
+		return nftSet.KeyType.GetNFTMagic() != nftables.TypeVerdict.GetNFTMagic()
+	}
+	if desired.KeyType.GetNFTMagic() != nftSet.KeyType.GetNFTMagic() {
+		return true
+	}
+	return desired.IsMap && desired.DataType.GetNFTMagic() != nftSet.DataType.GetNFTMagic()
+}
+
+// isRuleUsingSets checks whether the rule looks up one of the given sets.
+func isRuleUsingSets(nftRule *nftables.Rule, nftSets []*nftables.Set) bool {
+	for i := range nftRule.Exprs {
+		lookup, ok := nftRule.Exprs[i].(*expr.Lookup)
+		if !ok {
+			continue
+		}
+		for j := range nftSets {
+			if lookup.SetName == nftSets[j].Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// delSets deletes the given sets.
+func delSets(nftconn *nftables.Conn, nftSets []*nftables.Set) {
+	for i := range nftSets {
+		klog.V(2).Infof("deleting set %s", nftSets[i].Name)
+		nftconn.DelSet(nftSets[i])
+	}
+}
+
+// addSets enforces the presence of the sets of the table, updating incrementally the elements of the existing ones.
+func addSets(nftconn *nftables.Conn, table *firewallapi.Table, nftTable *nftables.Table) error {
+	var nftSets []*nftables.Set
+	exist, err := existTable(nftconn, table)
+	if err != nil {
+		return err
+	}
+	if exist {
+		if nftSets, err = nftconn.GetSets(nftTable); err != nil {
+			return err
+		}
+	}
+
+	for i := range table.Sets {
+		var current *nftables.Set
+		for j := range nftSets {
+			if nftSets[j].Name == table.Sets[i].Name {
+				current = nftSets[j]
+				break
+			}
+		}
+		if err := addSet(nftconn, &table.Sets[i], current, nftTable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addSet(nftconn *nftables.Conn, set *firewallapi.Set, current *nftables.Set, table *nftables.Table) error {
+	nftSet, elements, err := firewallutils.ForgeSet(set, table)
+	if err != nil {
+		return err
+	}
+
+	// If the set is not present (or it has been deleted since outdated), it is created with all its elements.
+	if current == nil {
+		return nftconn.AddSet(nftSet, elements)
+	}
+
+	stale, missing, err := diffSetElements(nftconn, set, current, nftSet, elements)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 && len(missing) == 0 {
+		return nil
+	}
+
+	klog.V(2).Infof("updating set %s: %d elements to remove, %d to add", set.Name, len(stale), len(missing))
+	if set.Interval {
+		// The boundaries of adjacent intervals may be shared, hence interval sets are entirely refilled.
+		nftconn.FlushSet(current)
+		return nftconn.SetAddElements(current, elements)
+	}
+	if len(stale) > 0 {
+		if err := nftconn.SetDeleteElements(current, stale); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return nftconn.SetAddElements(current, missing)
+	}
+	return nil
+}
+
+// diffSetElements compares the elements of the current set with the desired ones,
+// returning the elements to be removed and the ones to be added.
+func diffSetElements(nftconn *nftables.Conn, set *firewallapi.Set, current, desired *nftables.Set,
+	elements []nftables.SetElement) (stale, missing []nftables.SetElement, err error) {
+	// The kernel does not report the data type of verdict maps, which is required to encode the elements.
+	current.Table, current.KeyType, current.DataType = desired.Table, desired.KeyType, desired.DataType
+
+	currentElements, err := nftconn.GetSetElements(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	stale, missing = diffElements(elements, currentElements, firewallutils.IsVerdictMap(set))
+	return stale, missing, nil
+}
+
+// diffElements returns the current elements which are not desired, and the desired elements which are not present.
+// The verdicts of the current elements are decoded if encodedVerdict is true, as read back from the kernel.
+func diffElements(desired, current []nftables.SetElement, encodedVerdict bool) (stale, missing []nftables.SetElement) {
+	desiredKeys := make(map[string]nftables.SetElement, len(desired))
+	for i := range desired {
+		desiredKeys[setElementKey(&desired[i], false)] = desired[i]
+	}
+	currentKeys := make(map[string]nftables.SetElement, len(current))
+	for i := range current {
+		currentKeys[setElementKey(&current[i], encodedVerdict)] = current[i]
+	}
+
+	for k := range currentKeys {
+		if _, ok := desiredKeys[k]; !ok {
+			stale = append(stale, currentKeys[k])
+		}
+	}
+	for k := range desiredKeys {
+		if _, ok := currentKeys[k]; !ok {
+			missing = append(missing, desiredKeys[k])
+		}
+	}
+	return stale, missing
+}
+
+// setElementKey returns a string uniquely identifying the given element, including its value.
+// The verdicts read back from the kernel are reported as raw netlink attributes, and they are decoded if encodedVerdict is true.
+func setElementKey(element *nftables.SetElement, encodedVerdict bool) string {
+	key := fmt.Sprintf("%s/%t", hex.EncodeToString(element.Key), element.IntervalEnd)
+	verdict := element.VerdictData
+	if encodedVerdict && len(element.Val) > 0 {
+		var err error
+		if verdict, err = decodeVerdict(element.Val); err != nil {
+			klog.Warningf("unable to decode verdict of set element %s: %v", key, err)
+		}
+	}
+	switch {
+	case verdict != nil:
+		return fmt.Sprintf("%s/%d/%s", key, verdict.Kind, verdict.Chain)
+	case len(element.Val) > 0:
+		return fmt.Sprintf("%s/%s", key, hex.EncodeToString(element.Val))
+	default:
+		return key
+	}
+}
+
+func decodeVerdict(data []byte) (*expr.Verdict, error) {
+	ad, err := netlink.NewAttributeDecoder(data)
+	if err != nil {
+		return nil, err
+	}
+	ad.ByteOrder = binary.BigEndian
+
+	verdict := &expr.Verdict{}
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.NFTA_VERDICT_CODE:
+			verdict.Kind = expr.VerdictKind(int32(ad.Uint32()))
+		case unix.NFTA_VERDICT_CHAIN:
+			verdict.Chain = ad.String()
+		}
+	}
+	return verdict, ad.Err()
+}
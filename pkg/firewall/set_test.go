@@ -0,0 +1,92 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/mdlayher/netlink"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("Set elements", func() {
+	ip := func(address string) nftables.SetElement {
+		return nftables.SetElement{Key: net.ParseIP(address).To4()}
+	}
+	port := func(p uint16, end bool) nftables.SetElement {
+		return nftables.SetElement{Key: binaryutil.BigEndian.PutUint16(p), IntervalEnd: end}
+	}
+	mapped := func(address, value string) nftables.SetElement {
+		return nftables.SetElement{Key: net.ParseIP(address).To4(), Val: net.ParseIP(value).To4()}
+	}
+	verdict := func(address string, kind expr.VerdictKind, chain string) nftables.SetElement {
+		return nftables.SetElement{Key: net.ParseIP(address).To4(), VerdictData: &expr.Verdict{Kind: kind, Chain: chain}}
+	}
+	// encodedVerdict returns the element as read back from the kernel, which reports the verdict as raw netlink attributes.
+	encodedVerdict := func(address string, kind expr.VerdictKind, chain string) nftables.SetElement {
+		ae := netlink.NewAttributeEncoder()
+		ae.ByteOrder = binary.BigEndian
+		ae.Uint32(unix.NFTA_VERDICT_CODE, uint32(int32(kind)))
+		if chain != "" {
+			ae.String(unix.NFTA_VERDICT_CHAIN, chain)
+		}
+		data, err := ae.Encode()
+		Expect(err).ToNot(HaveOccurred())
+		return nftables.SetElement{Key: net.ParseIP(address).To4(), Val: data}
+	}
+
+	DescribeTable("should compute the stale and the missing elements",
+		func(desired, current []nftables.SetElement, encoded bool, expectedStale, expectedMissing []nftables.SetElement) {
+			stale, missing := diffElements(desired, current, encoded)
+			Expect(stale).To(ConsistOf(expectedStale))
+			Expect(missing).To(ConsistOf(expectedMissing))
+		},
+		Entry("no changes",
+			[]nftables.SetElement{ip("10.0.0.1"), ip("10.0.0.2")}, []nftables.SetElement{ip("10.0.0.2"), ip("10.0.0.1")}, false,
+			nil, nil),
+		Entry("an element added and one removed",
+			[]nftables.SetElement{ip("10.0.0.1"), ip("10.0.0.3")}, []nftables.SetElement{ip("10.0.0.1"), ip("10.0.0.2")}, false,
+			[]nftables.SetElement{ip("10.0.0.2")}, []nftables.SetElement{ip("10.0.0.3")}),
+		Entry("an empty set",
+			[]nftables.SetElement{ip("10.0.0.1")}, nil, false,
+			nil, []nftables.SetElement{ip("10.0.0.1")}),
+		Entry("the end of an interval, distinct from the start of the next one",
+			[]nftables.SetElement{port(1000, false), port(2001, true)}, []nftables.SetElement{port(1000, false), port(2001, false)}, false,
+			[]nftables.SetElement{port(2001, false)}, []nftables.SetElement{port(2001, true)}),
+		Entry("a map entry whose value changed",
+			[]nftables.SetElement{mapped("10.70.0.1", "10.0.0.2")}, []nftables.SetElement{mapped("10.70.0.1", "10.0.0.1")}, false,
+			[]nftables.SetElement{mapped("10.70.0.1", "10.0.0.1")}, []nftables.SetElement{mapped("10.70.0.1", "10.0.0.2")}),
+		Entry("the verdicts read back from the kernel",
+			[]nftables.SetElement{verdict("10.0.0.1", expr.VerdictDrop, ""), verdict("10.0.0.2", expr.VerdictJump, "pods")},
+			[]nftables.SetElement{encodedVerdict("10.0.0.1", expr.VerdictDrop, ""), encodedVerdict("10.0.0.2", expr.VerdictJump, "pods")}, true,
+			nil, nil),
+		Entry("a verdict whose chain changed",
+			[]nftables.SetElement{verdict("10.0.0.1", expr.VerdictJump, "pods")},
+			[]nftables.SetElement{encodedVerdict("10.0.0.1", expr.VerdictJump, "nodes")}, true,
+			[]nftables.SetElement{encodedVerdict("10.0.0.1", expr.VerdictJump, "nodes")},
+			[]nftables.SetElement{verdict("10.0.0.1", expr.VerdictJump, "pods")}),
+	)
+
+	It("should decode the verdicts read back from the kernel", func() {
+		element := encodedVerdict("10.0.0.1", expr.VerdictGoto, "pods")
+		Expect(decodeVerdict(element.Val)).To(Equal(&expr.Verdict{Kind: expr.VerdictGoto, Chain: "pods"}))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"k8s.io/klog/v2"
 
 	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
 )
 
 func addTable(nftconn *nftables.Conn, table *firewallapi.Table) *nftables.Table {
@@ -35,28 +36,13 @@ func delTable(nftconn *nftables.Conn, table *firewallapi.Table) {
 	nftconn.DelTable(nftTable)
 }
 
-func getTableFamily(family firewallapi.TableFamily) nftables.TableFamily {
-	switch family {
-	case firewallapi.TableFamilyIPv4:
-		return nftables.TableFamilyIPv4
-	case firewallapi.TableFamilyIPv6:
-		return nftables.TableFamilyIPv6
-	case firewallapi.TableFamilyINet:
-		return nftables.TableFamilyINet
-	case firewallapi.TableFamilyARP:
-		return nftables.TableFamilyARP
-	case firewallapi.TableFamilyBridge:
-		return nftables.TableFamilyBridge
-	case firewallapi.TableFamilyNetdev:
-		return nftables.TableFamilyNetdev
-	default:
-		return nftables.TableFamily(0)
-	}
-}
-
-// cleanTable removes all the chains and rules that are not present in the firewall configuration or that have been modified.
+// cleanTable removes all the chains, rules and sets that are not present in the firewall configuration or that have been modified.
 func cleanTable(nftconn *nftables.Conn, table *firewallapi.Table) error {
-	nftChains, err := nftconn.ListChainsOfTableFamily(getTableFamily(*table.Family))
+	outdatedSets, err := getOutdatedSets(nftconn, table)
+	if err != nil {
+		return err
+	}
+	nftChains, err := nftconn.ListChainsOfTableFamily(firewallutils.GetTableFamily(*table.Family))
 	if err != nil {
 		return err
 	}
@@ -74,10 +60,12 @@ func cleanTable(nftconn *nftables.Conn, table *firewallapi.Table) error {
 			continue
 		}
 		// If the chain is not outdated we need to check the rules inside it.
-		if err := cleanChain(nftconn, &table.Chains[chainIndex], nftChains[i]); err != nil {
+		if err := cleanChain(nftconn, &table.Chains[chainIndex], nftChains[i], table.Sets, outdatedSets); err != nil {
 			return err
 		}
 	}
+	// Sets are deleted after the rules referencing them.
+	delSets(nftconn, outdatedSets)
 	return nil
 }
 
@@ -19,6 +19,8 @@ import (
 	"net"
 	"strconv"
 
+	"github.com/google/nftables"
+
 	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
 	"github.com/liqotech/liqo/pkg/utils/network/port"
 )
@@ -29,6 +31,11 @@ func GetIPValueType(value *string) (firewallv1beta1.IPValueType, error) {
 		return firewallv1beta1.IPValueTypeVoid, nil
 	}
 
+	// Check if the value references a named set.
+	if _, ok := GetSetReference(*value); ok {
+		return firewallv1beta1.IPValueTypeSet, nil
+	}
+
 	// Check if the value is a pool subnet.
 	if _, _, err := net.ParseCIDR(*value); err == nil {
 		return firewallv1beta1.IPValueTypeSubnet, nil
@@ -48,6 +55,11 @@ func GetPortValueType(value *string) (firewallv1beta1.PortValueType, error) {
 		return firewallv1beta1.PortValueTypeVoid, nil
 	}
 
+	// Check if the value references a named set.
+	if _, ok := GetSetReference(*value); ok {
+		return firewallv1beta1.PortValueTypeSet, nil
+	}
+
 	// Check if the value is a port range.
 	if _, _, err := port.ParsePortRange(*value); err == nil {
 		return firewallv1beta1.PortValueTypeRange, nil
@@ -60,3 +72,23 @@ func GetPortValueType(value *string) (firewallv1beta1.PortValueType, error) {
 
 	return firewallv1beta1.PortValueTypeVoid, fmt.Errorf("invalid match value %s", *value)
 }
+
+// GetTableFamily returns the nftables family corresponding to the given one.
+func GetTableFamily(family firewallv1beta1.TableFamily) nftables.TableFamily {
+	switch family {
+	case firewallv1beta1.TableFamilyIPv4:
+		return nftables.TableFamilyIPv4
+	case firewallv1beta1.TableFamilyIPv6:
+		return nftables.TableFamilyIPv6
+	case firewallv1beta1.TableFamilyINet:
+		return nftables.TableFamilyINet
+	case firewallv1beta1.TableFamilyARP:
+		return nftables.TableFamilyARP
+	case firewallv1beta1.TableFamilyBridge:
+		return nftables.TableFamilyBridge
+	case firewallv1beta1.TableFamilyNetdev:
+		return nftables.TableFamilyNetdev
+	default:
+		return nftables.TableFamily(0)
+	}
+}
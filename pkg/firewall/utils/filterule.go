@@ -23,6 +23,7 @@ import (
 	"github.com/google/nftables/binaryutil"
 	"github.com/google/nftables/expr"
 	"github.com/google/nftables/userdata"
+	"golang.org/x/sys/unix"
 	"k8s.io/klog/v2"
 
 	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
@@ -33,6 +34,8 @@ var _ Rule = &FilterRuleWrapper{}
 // FilterRuleWrapper is a wrapper for a FilterRule.
 type FilterRuleWrapper struct {
 	*firewallv1beta1.FilterRule
+	// Sets are the sets of the table, which can be referenced by the rule.
+	Sets []firewallv1beta1.Set
 }
 
 // GetName returns the name of the rule.
@@ -45,9 +48,14 @@ func (fr *FilterRuleWrapper) SetName(name string) {
 	fr.Name = &name
 }
 
+// Forge forges the nftables rule programmed in the given chain, without adding it.
+func (fr *FilterRuleWrapper) Forge(chain *nftables.Chain) (*nftables.Rule, error) {
+	return forgeFilterRule(fr.FilterRule, chain, fr.Sets)
+}
+
 // Add adds the rule to the chain.
 func (fr *FilterRuleWrapper) Add(nftconn *nftables.Conn, chain *nftables.Chain) error {
-	rule, err := forgeFilterRule(fr.FilterRule, chain)
+	rule, err := forgeFilterRule(fr.FilterRule, chain, fr.Sets)
 	if err != nil {
 		return err
 	}
@@ -59,7 +67,7 @@ func (fr *FilterRuleWrapper) Add(nftconn *nftables.Conn, chain *nftables.Chain)
 // Equal checks if the rule is equal to the given one.
 func (fr *FilterRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	currentrule.Chain.Table = currentrule.Table
-	newrule, err := forgeFilterRule(fr.FilterRule, currentrule.Chain)
+	newrule, err := forgeFilterRule(fr.FilterRule, currentrule.Chain, fr.Sets)
 	// TODO: this ugly exception is caused by an error in the expr retrieved by nftables library.
 	// In particular, the expr retrieved by the library when the action is ctmark
 	// Retrieved expr: &{0 false 3}
@@ -67,7 +75,7 @@ func (fr *FilterRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	// We think that this error should be caused by a library bug.
 	// We are going to investigate it further.
 	if fr.FilterRule.Action == firewallv1beta1.ActionCtMark {
-		return true
+		return (GetCounter(currentrule) != nil) == fr.Counter && hasLog(currentrule) == fr.Log
 	}
 	if err != nil {
 		return false
@@ -77,7 +85,7 @@ func (fr *FilterRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	}
 	for i := range currentrule.Exprs {
 		foundEqual := false
-		currentbytes, err := expr.Marshal(byte(currentrule.Table.Family), currentrule.Exprs[i])
+		currentbytes, err := marshalExpr(currentrule.Table.Family, currentrule.Exprs[i])
 		if err != nil {
 			klog.Errorf("Error while marshaling current rule %s", err.Error())
 			return false
@@ -101,7 +109,7 @@ func (fr *FilterRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 }
 
 // forgeFilterRule forges a nftables rule from a FilterRule.
-func forgeFilterRule(fr *firewallv1beta1.FilterRule, chain *nftables.Chain) (*nftables.Rule, error) {
+func forgeFilterRule(fr *firewallv1beta1.FilterRule, chain *nftables.Chain, sets []firewallv1beta1.Set) (*nftables.Rule, error) {
 	rule := &nftables.Rule{
 		Table:    chain.Table,
 		Chain:    chain,
@@ -109,11 +117,13 @@ func forgeFilterRule(fr *firewallv1beta1.FilterRule, chain *nftables.Chain) (*nf
 	}
 
 	for i := range fr.Match {
-		if err := applyMatch(&fr.Match[i], rule); err != nil {
+		if err := applyMatch(&fr.Match[i], rule, sets); err != nil {
 			return nil, err
 		}
 	}
 
+	applyStatements(*fr.Name, fr.Counter, fr.Log, rule)
+
 	switch fr.Action {
 	case firewallv1beta1.ActionCtMark:
 		err := applyCtMarkAction(fr.Value, rule)
@@ -122,6 +132,12 @@ func forgeFilterRule(fr *firewallv1beta1.FilterRule, chain *nftables.Chain) (*nf
 		}
 	case firewallv1beta1.ActionSetMetaMarkFromCtMark:
 		applySetMetaMarkFromCtMarkAction(rule)
+	case firewallv1beta1.ActionAccept:
+		rule.Exprs = append(rule.Exprs, &expr.Verdict{Kind: expr.VerdictAccept})
+	case firewallv1beta1.ActionDrop:
+		rule.Exprs = append(rule.Exprs, &expr.Verdict{Kind: expr.VerdictDrop})
+	case firewallv1beta1.ActionReject:
+		rule.Exprs = append(rule.Exprs, forgeRejectExpr(chain.Table.Family))
 	default:
 	}
 	return rule, nil
@@ -159,3 +175,15 @@ func applySetMetaMarkFromCtMarkAction(rule *nftables.Rule) {
 		},
 	)
 }
+
+// forgeRejectExpr forges the expression rejecting a packet with a port unreachable message, depending on the table family.
+func forgeRejectExpr(family nftables.TableFamily) *expr.Reject {
+	switch family {
+	case nftables.TableFamilyIPv4:
+		return &expr.Reject{Type: unix.NFT_REJECT_ICMP_UNREACH, Code: 3 /* ICMP port unreachable */}
+	case nftables.TableFamilyIPv6:
+		return &expr.Reject{Type: unix.NFT_REJECT_ICMP_UNREACH, Code: 4 /* ICMPv6 port unreachable */}
+	default:
+		return &expr.Reject{Type: unix.NFT_REJECT_ICMPX_UNREACH, Code: unix.NFT_REJECT_ICMPX_PORT_UNREACH}
+	}
+}
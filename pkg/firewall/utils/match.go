@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 
 	"github.com/google/nftables"
 	"github.com/google/nftables/binaryutil"
@@ -28,7 +29,10 @@ import (
 	"github.com/liqotech/liqo/pkg/utils/network/port"
 )
 
-func applyMatch(m *firewallv1beta1.Match, rule *nftables.Rule) error {
+// defaultLimitBurst is the burst applied by the kernel to the limits when not specified.
+const defaultLimitBurst = 5
+
+func applyMatch(m *firewallv1beta1.Match, rule *nftables.Rule, sets []firewallv1beta1.Set) error {
 	op, err := getMatchCmpOp(m)
 	if err != nil {
 		return err
@@ -41,19 +45,44 @@ func applyMatch(m *firewallv1beta1.Match, rule *nftables.Rule) error {
 		}
 	}
 	if m.Dev != nil {
-		err = applyMatchDev(m, rule, op)
+		err = applyMatchDev(m, rule, op, sets)
 		if err != nil {
 			return err
 		}
 	}
 	if m.IP != nil {
-		err = applyMatchIP(m, rule, op)
+		err = applyMatchIP(m, rule, op, sets)
 		if err != nil {
 			return err
 		}
 	}
 	if m.Port != nil {
-		err = applyMatchPort(m, rule, op)
+		err = applyMatchPort(m, rule, op, sets)
+		if err != nil {
+			return err
+		}
+	}
+	if m.ICMP != nil {
+		err = applyMatchICMP(m, rule, op)
+		if err != nil {
+			return err
+		}
+	}
+	if m.CtState != nil {
+		err = applyMatchCtState(m, rule, op)
+		if err != nil {
+			return err
+		}
+	}
+	if m.Mark != nil {
+		err = applyMatchMark(m, rule, op)
+		if err != nil {
+			return err
+		}
+	}
+	// The limit is evaluated last, so that only the packets satisfying the other matches consume the rate.
+	if m.Limit != nil {
+		err = applyMatchLimit(m, rule, op)
 		if err != nil {
 			return err
 		}
@@ -61,7 +90,7 @@ func applyMatch(m *firewallv1beta1.Match, rule *nftables.Rule) error {
 	return nil
 }
 
-func applyMatchIP(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+func applyMatchIP(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp, sets []firewallv1beta1.Set) error {
 	matchIPValueType, err := GetIPValueType(&m.IP.Value)
 	if err != nil {
 		return err
@@ -72,6 +101,8 @@ func applyMatchIP(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp)
 		return applyMatchIPSingleIP(m, rule, op)
 	case firewallv1beta1.IPValueTypeSubnet:
 		return applyMatchIPPoolSubnet(m, rule, op)
+	case firewallv1beta1.IPValueTypeSet:
+		return applyMatchIPSet(m, rule, sets)
 	default:
 		return fmt.Errorf("invalid match value type %s", matchIPValueType)
 	}
@@ -99,6 +130,43 @@ func applyMatchIPSingleIP(m *firewallv1beta1.Match, rule *nftables.Rule, op expr
 	return nil
 }
 
+func applyMatchIPSet(m *firewallv1beta1.Match, rule *nftables.Rule, sets []firewallv1beta1.Set) error {
+	posOffset, err := getMatchIPPositionOffset(m)
+	if err != nil {
+		return err
+	}
+	name, _ := GetSetReference(m.IP.Value)
+
+	keyType := GetIPSetKeyType(sets, name)
+	if keyType == firewallv1beta1.SetDataTypeIP6Addr {
+		if posOffset, err = getMatchIP6PositionOffset(m); err != nil {
+			return err
+		}
+		// The packets of the other families are skipped, as the IPv6 addresses are located at different offsets.
+		rule.Exprs = append(rule.Exprs,
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV6}},
+			&expr.Payload{
+				DestRegister: 1,
+				Base:         expr.PayloadBaseNetworkHeader,
+				Offset:       posOffset,
+				Len:          16,
+			},
+		)
+		return applySetLookup(m, name, keyType, sets, rule)
+	}
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseNetworkHeader,
+			Offset:       posOffset,
+			Len:          4,
+		},
+	)
+	return applySetLookup(m, name, keyType, sets, rule)
+}
+
 func applyMatchIPPoolSubnet(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
 	posOffset, err := getMatchIPPositionOffset(m)
 	if err != nil {
@@ -159,6 +227,24 @@ func applyMatchPortSinglePort(m *firewallv1beta1.Match, rule *nftables.Rule, op
 	return nil
 }
 
+func applyMatchPortSet(m *firewallv1beta1.Match, rule *nftables.Rule, sets []firewallv1beta1.Set) error {
+	posOffset, err := getMatchPortPositionOffset(m)
+	if err != nil {
+		return err
+	}
+	name, _ := GetSetReference(m.Port.Value)
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseTransportHeader,
+			Offset:       posOffset,
+			Len:          2,
+		},
+	)
+	return applySetLookup(m, name, firewallv1beta1.SetDataTypeInetService, sets, rule)
+}
+
 func applyMatchPortRange(m *firewallv1beta1.Match, rule *nftables.Rule) error {
 	posOffset, err := getMatchPortPositionOffset(m)
 	if err != nil {
@@ -211,12 +297,22 @@ func applyMatchProto(m *firewallv1beta1.Match, rule *nftables.Rule) error {
 	return nil
 }
 
-func applyMatchDev(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+func applyMatchDev(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp, sets []firewallv1beta1.Set) error {
 	metakey, err := getMatchDevMetaKey(m)
 	if err != nil {
 		return err
 	}
 
+	if name, ok := GetSetReference(m.Dev.Value); ok {
+		rule.Exprs = append(rule.Exprs,
+			&expr.Meta{
+				Register: 1,
+				Key:      metakey,
+			},
+		)
+		return applySetLookup(m, name, firewallv1beta1.SetDataTypeIfName, sets, rule)
+	}
+
 	rule.Exprs = append(rule.Exprs,
 		&expr.Meta{
 			Register: 1,
@@ -232,8 +328,8 @@ func applyMatchDev(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp)
 	return nil
 }
 
-func applyMatchPort(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
-	matchPortValueType, err := GetPortValueType(&m.IP.Value)
+func applyMatchPort(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp, sets []firewallv1beta1.Set) error {
+	matchPortValueType, err := GetPortValueType(&m.Port.Value)
 	if err != nil {
 		return err
 	}
@@ -243,11 +339,157 @@ func applyMatchPort(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp
 		return applyMatchPortSinglePort(m, rule, op)
 	case firewallv1beta1.PortValueTypeRange:
 		return applyMatchPortRange(m, rule)
+	case firewallv1beta1.PortValueTypeSet:
+		return applyMatchPortSet(m, rule, sets)
 	default:
 		return fmt.Errorf("invalid match value type %s", matchPortValueType)
 	}
 }
 
+func applyMatchICMP(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+	icmpType, err := getMatchICMPTypeValue(m)
+	if err != nil {
+		return err
+	}
+	var proto byte = unix.IPPROTO_ICMP
+	if IsICMPv6(m.ICMP) {
+		proto = unix.IPPROTO_ICMPV6
+	}
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     []byte{proto},
+		},
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseTransportHeader,
+			Offset:       0,
+			Len:          1,
+		},
+		&expr.Cmp{
+			Op:       op,
+			Register: 1,
+			Data:     []byte{icmpType},
+		},
+	)
+	return nil
+}
+
+func applyMatchCtState(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+	states, err := getMatchCtStateValue(m)
+	if err != nil {
+		return err
+	}
+
+	// The match succeeds if the state is any of the given ones, hence the cmp operation is inverted.
+	cmpOp := expr.CmpOpNeq
+	if op == expr.CmpOpNeq {
+		cmpOp = expr.CmpOpEq
+	}
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+		&expr.Bitwise{
+			SourceRegister: 1,
+			DestRegister:   1,
+			Len:            4,
+			Mask:           binaryutil.NativeEndian.PutUint32(states),
+			Xor:            binaryutil.NativeEndian.PutUint32(0),
+		},
+		&expr.Cmp{
+			Op:       cmpOp,
+			Register: 1,
+			Data:     binaryutil.NativeEndian.PutUint32(0),
+		},
+	)
+	return nil
+}
+
+func applyMatchMark(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+	mark, mask, err := ParseMark(m.Mark.Value)
+	if err != nil {
+		return err
+	}
+
+	switch m.Mark.Type {
+	case firewallv1beta1.MatchMarkTypeMeta:
+		rule.Exprs = append(rule.Exprs, &expr.Meta{Key: expr.MetaKeyMARK, Register: 1})
+	case firewallv1beta1.MatchMarkTypeCt:
+		rule.Exprs = append(rule.Exprs, &expr.Ct{Key: expr.CtKeyMARK, Register: 1})
+	default:
+		return fmt.Errorf("invalid match mark type %s", m.Mark.Type)
+	}
+
+	if mask != ^uint32(0) {
+		rule.Exprs = append(rule.Exprs,
+			&expr.Bitwise{
+				SourceRegister: 1,
+				DestRegister:   1,
+				Len:            4,
+				Mask:           binaryutil.NativeEndian.PutUint32(mask),
+				Xor:            binaryutil.NativeEndian.PutUint32(0),
+			},
+		)
+	}
+	rule.Exprs = append(rule.Exprs,
+		&expr.Cmp{
+			Op:       op,
+			Register: 1,
+			Data:     binaryutil.NativeEndian.PutUint32(mark & mask),
+		},
+	)
+	return nil
+}
+
+func applyMatchLimit(m *firewallv1beta1.Match, rule *nftables.Rule, op expr.CmpOp) error {
+	unit, err := getMatchLimitUnit(m)
+	if err != nil {
+		return err
+	}
+	if m.Limit.Rate <= 0 {
+		return fmt.Errorf("invalid match limit rate %d", m.Limit.Rate)
+	}
+	// The kernel defaults the burst to 5 packets, hence it is set explicitly to compare the rule with the applied one.
+	burst := uint32(defaultLimitBurst)
+	if m.Limit.Burst != nil {
+		if *m.Limit.Burst < 0 {
+			return fmt.Errorf("invalid match limit burst %d", *m.Limit.Burst)
+		}
+		burst = uint32(*m.Limit.Burst) //nolint:gosec // The burst is not negative.
+	}
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Limit{
+			Type:  expr.LimitTypePkts,
+			Rate:  uint64(m.Limit.Rate), //nolint:gosec // The rate is positive.
+			Over:  op == expr.CmpOpNeq,
+			Unit:  unit,
+			Burst: burst,
+		},
+	)
+	return nil
+}
+
+// ParseMark parses a mark, optionally followed by the mask of the bits to be compared (eg. 0x10/0xf0).
+func ParseMark(value string) (mark, mask uint32, err error) {
+	markStr, maskStr, hasMask := strings.Cut(value, "/")
+	parsed, err := strconv.ParseUint(markStr, 0, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid mark %s: %w", value, err)
+	}
+	mark, mask = uint32(parsed), ^uint32(0)
+	if hasMask {
+		if parsed, err = strconv.ParseUint(maskStr, 0, 32); err != nil {
+			return 0, 0, fmt.Errorf("invalid mask %s: %w", value, err)
+		}
+		mask = uint32(parsed)
+	}
+	return mark, mask, nil
+}
+
 func getMatchCmpOp(m *firewallv1beta1.Match) (expr.CmpOp, error) {
 	switch m.Op {
 	case firewallv1beta1.MatchOperationEq:
@@ -268,6 +510,16 @@ func getMatchIPPositionOffset(m *firewallv1beta1.Match) (uint32, error) {
 	return 0, fmt.Errorf("invalid match IP position %s", m.Dev.Position)
 }
 
+func getMatchIP6PositionOffset(m *firewallv1beta1.Match) (uint32, error) {
+	switch m.IP.Position {
+	case firewallv1beta1.MatchPositionSrc:
+		return 8, nil
+	case firewallv1beta1.MatchPositionDst:
+		return 24, nil
+	}
+	return 0, fmt.Errorf("invalid match IP position %s", m.IP.Position)
+}
+
 func getMatchPortPositionOffset(m *firewallv1beta1.Match) (uint32, error) {
 	switch m.Port.Position {
 	case firewallv1beta1.MatchPositionSrc:
@@ -288,6 +540,93 @@ func getMatchProtoValue(m *firewallv1beta1.Match) (uint8, error) {
 	return 0, fmt.Errorf("invalid match IP position %s", m.Dev.Position)
 }
 
+// IsICMPv6 returns whether the given match refers to ICMPv6 messages.
+func IsICMPv6(m *firewallv1beta1.MatchICMP) bool {
+	return m.Protocol == firewallv1beta1.ICMPProtocolICMPv6
+}
+
+func getMatchICMPTypeValue(m *firewallv1beta1.Match) (uint8, error) {
+	if IsICMPv6(m.ICMP) {
+		return getMatchICMPv6TypeValue(m)
+	}
+	switch m.ICMP.Type {
+	case firewallv1beta1.ICMPTypeEchoReply:
+		return 0, nil
+	case firewallv1beta1.ICMPTypeDestinationUnreachable:
+		return 3, nil
+	case firewallv1beta1.ICMPTypeRedirect:
+		return 5, nil
+	case firewallv1beta1.ICMPTypeEchoRequest:
+		return 8, nil
+	case firewallv1beta1.ICMPTypeTimeExceeded:
+		return 11, nil
+	case firewallv1beta1.ICMPTypeParameterProblem:
+		return 12, nil
+	default:
+		return 0, fmt.Errorf("invalid match icmp type %s", m.ICMP.Type)
+	}
+}
+
+func getMatchICMPv6TypeValue(m *firewallv1beta1.Match) (uint8, error) {
+	switch m.ICMP.Type {
+	case firewallv1beta1.ICMPTypeDestinationUnreachable:
+		return 1, nil
+	case firewallv1beta1.ICMPTypePacketTooBig:
+		return 2, nil
+	case firewallv1beta1.ICMPTypeTimeExceeded:
+		return 3, nil
+	case firewallv1beta1.ICMPTypeParameterProblem:
+		return 4, nil
+	case firewallv1beta1.ICMPTypeEchoRequest:
+		return 128, nil
+	case firewallv1beta1.ICMPTypeEchoReply:
+		return 129, nil
+	case firewallv1beta1.ICMPTypeRedirect:
+		return 137, nil
+	default:
+		return 0, fmt.Errorf("invalid match icmpv6 type %s", m.ICMP.Type)
+	}
+}
+
+func getMatchCtStateValue(m *firewallv1beta1.Match) (uint32, error) {
+	if len(m.CtState.Value) == 0 {
+		return 0, fmt.Errorf("match ct state has no states")
+	}
+	var states uint32
+	for _, state := range m.CtState.Value {
+		switch state {
+		case firewallv1beta1.CtStateNew:
+			states |= expr.CtStateBitNEW
+		case firewallv1beta1.CtStateEstablished:
+			states |= expr.CtStateBitESTABLISHED
+		case firewallv1beta1.CtStateRelated:
+			states |= expr.CtStateBitRELATED
+		case firewallv1beta1.CtStateInvalid:
+			states |= expr.CtStateBitINVALID
+		case firewallv1beta1.CtStateUntracked:
+			states |= expr.CtStateBitUNTRACKED
+		default:
+			return 0, fmt.Errorf("invalid match ct state %s", state)
+		}
+	}
+	return states, nil
+}
+
+func getMatchLimitUnit(m *firewallv1beta1.Match) (expr.LimitTime, error) {
+	switch m.Limit.Unit {
+	case firewallv1beta1.LimitUnitSecond:
+		return expr.LimitTimeSecond, nil
+	case firewallv1beta1.LimitUnitMinute:
+		return expr.LimitTimeMinute, nil
+	case firewallv1beta1.LimitUnitHour:
+		return expr.LimitTimeHour, nil
+	case firewallv1beta1.LimitUnitDay:
+		return expr.LimitTimeDay, nil
+	default:
+		return 0, fmt.Errorf("invalid match limit unit %s", m.Limit.Unit)
+	}
+}
+
 func getMatchDevMetaKey(m *firewallv1beta1.Match) (expr.MetaKey, error) {
 	switch m.Dev.Position {
 	case firewallv1beta1.MatchDevPositionIn:
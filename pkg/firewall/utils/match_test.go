@@ -0,0 +1,58 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+)
+
+var _ = Describe("Port matches", func() {
+	// dport is the payload expression loading the destination port in the first register.
+	dport := &expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2}
+
+	DescribeTable("should forge the expressions matching the port",
+		func(op firewallv1beta1.MatchOperation, value string, expected ...expr.Any) {
+			rule := &nftables.Rule{}
+			match := &firewallv1beta1.Match{
+				Op:   op,
+				Port: &firewallv1beta1.MatchPort{Value: value, Position: firewallv1beta1.MatchPositionDst},
+			}
+			Expect(applyMatch(match, rule, nil)).To(Succeed())
+			Expect(rule.Exprs).To(Equal(expected))
+		},
+		Entry("a single port", firewallv1beta1.MatchOperationEq, "80",
+			dport, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(80)}),
+		Entry("a negated single port", firewallv1beta1.MatchOperationNeq, "22",
+			dport, &expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: binaryutil.BigEndian.PutUint16(22)}),
+		Entry("a port range", firewallv1beta1.MatchOperationEq, "1000-2000",
+			dport,
+			&expr.Cmp{Op: expr.CmpOpGte, Register: 1, Data: binaryutil.BigEndian.PutUint16(1000)},
+			&expr.Cmp{Op: expr.CmpOpLte, Register: 1, Data: binaryutil.BigEndian.PutUint16(2000)}),
+	)
+
+	It("should fail if the port range is reversed", func() {
+		match := &firewallv1beta1.Match{
+			Op:   firewallv1beta1.MatchOperationEq,
+			Port: &firewallv1beta1.MatchPort{Value: "2000-1000", Position: firewallv1beta1.MatchPositionDst},
+		}
+		Expect(applyMatch(match, &nftables.Rule{}, nil)).ToNot(Succeed())
+	})
+})
@@ -33,6 +33,8 @@ var _ Rule = &NatRuleWrapper{}
 // NatRuleWrapper wraps a NatRule.
 type NatRuleWrapper struct {
 	*firewallv1beta1.NatRule
+	// Sets are the sets of the table, which can be referenced by the rule.
+	Sets []firewallv1beta1.Set
 }
 
 // GetName returns the name of the rule.
@@ -45,9 +47,14 @@ func (nr *NatRuleWrapper) SetName(name string) {
 	nr.Name = &name
 }
 
+// Forge forges the nftables rule programmed in the given chain, without adding it.
+func (nr *NatRuleWrapper) Forge(chain *nftables.Chain) (*nftables.Rule, error) {
+	return forgeNatRule(nr.NatRule, chain, nr.Sets)
+}
+
 // Add adds the rule to the chain.
 func (nr *NatRuleWrapper) Add(nftconn *nftables.Conn, chain *nftables.Chain) error {
-	rule, err := forgeNatRule(nr.NatRule, chain)
+	rule, err := forgeNatRule(nr.NatRule, chain, nr.Sets)
 	if err != nil {
 		return err
 	}
@@ -59,7 +66,7 @@ func (nr *NatRuleWrapper) Add(nftconn *nftables.Conn, chain *nftables.Chain) err
 // Equal checks if the rule is equal to the given one.
 func (nr *NatRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	currentrule.Chain.Table = currentrule.Table
-	newrule, err := forgeNatRule(nr.NatRule, currentrule.Chain)
+	newrule, err := forgeNatRule(nr.NatRule, currentrule.Chain, nr.Sets)
 	if err != nil {
 		return false
 	}
@@ -68,7 +75,7 @@ func (nr *NatRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	}
 	for i := range currentrule.Exprs {
 		foundEqual := false
-		currentbytes, err := expr.Marshal(byte(currentrule.Table.Family), currentrule.Exprs[i])
+		currentbytes, err := marshalExpr(currentrule.Table.Family, currentrule.Exprs[i])
 		if err != nil {
 			klog.Errorf("Error while marshaling current rule %s", err.Error())
 			return false
@@ -91,7 +98,7 @@ func (nr *NatRuleWrapper) Equal(currentrule *nftables.Rule) bool {
 	return true
 }
 
-func forgeNatRule(nr *firewallv1beta1.NatRule, chain *nftables.Chain) (*nftables.Rule, error) {
+func forgeNatRule(nr *firewallv1beta1.NatRule, chain *nftables.Chain, sets []firewallv1beta1.Set) (*nftables.Rule, error) {
 	rule := &nftables.Rule{
 		Table:    chain.Table,
 		Chain:    chain,
@@ -99,19 +106,21 @@ func forgeNatRule(nr *firewallv1beta1.NatRule, chain *nftables.Chain) (*nftables
 	}
 
 	for i := range nr.Match {
-		if err := applyMatch(&nr.Match[i], rule); err != nil {
+		if err := applyMatch(&nr.Match[i], rule, sets); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := applyNatRule(nr, rule); err != nil {
+	applyStatements(*nr.Name, nr.Counter, nr.Log, rule)
+
+	if err := applyNatRule(nr, rule, sets); err != nil {
 		return nil, err
 	}
 
 	return rule, nil
 }
 
-func applyNatRule(nr *firewallv1beta1.NatRule, rule *nftables.Rule) error {
+func applyNatRule(nr *firewallv1beta1.NatRule, rule *nftables.Rule, sets []firewallv1beta1.Set) error {
 	ipType, err := GetIPValueType(nr.To)
 	if err != nil {
 		return err
@@ -127,6 +136,8 @@ func applyNatRule(nr *firewallv1beta1.NatRule, rule *nftables.Rule) error {
 		return applyNatIP(nr.To, natType, rule)
 	case firewallv1beta1.IPValueTypeSubnet:
 		return applyNatSubnet(nr.To, natType, rule)
+	case firewallv1beta1.IPValueTypeSet:
+		return applyNatMap(nr.To, natType, rule, sets)
 	case firewallv1beta1.IPValueTypeVoid:
 		return applyNatVoid(rule)
 	}
@@ -156,6 +167,40 @@ func applyNatIP(ip *string, natType expr.NATType, rule *nftables.Rule) error {
 	return nil
 }
 
+// applyNatMap translates the destination address (dnat) or the source one (snat) to the value associated in the referenced map.
+func applyNatMap(to *string, natType expr.NATType, rule *nftables.Rule, sets []firewallv1beta1.Set) error {
+	name, _ := GetSetReference(*to)
+	if _, err := findAddressMap(sets, name); err != nil {
+		return err
+	}
+
+	var offset uint32 = 12
+	if natType == expr.NATTypeDestNAT {
+		offset = 16
+	}
+
+	rule.Exprs = append(rule.Exprs,
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseNetworkHeader,
+			Offset:       offset,
+			Len:          4,
+		},
+		&expr.Lookup{
+			SourceRegister: 1,
+			DestRegister:   1,
+			IsDestRegSet:   true,
+			SetName:        name,
+		},
+		&expr.NAT{
+			Type:       natType,
+			RegAddrMin: 1,
+			RegAddrMax: 1,
+			Family:     uint32(rule.Table.Family),
+		})
+	return nil
+}
+
 func applyNatVoid(rule *nftables.Rule) error {
 	rule.Exprs = append(rule.Exprs, &expr.Masq{})
 	return nil
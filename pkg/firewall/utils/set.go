@@ -0,0 +1,379 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+
+	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	"github.com/liqotech/liqo/pkg/utils/network/port"
+)
+
+// GetSetReference returns the name of the set referenced by the given value, if any.
+func GetSetReference(value string) (name string, isReference bool) {
+	return strings.CutPrefix(value, firewallv1beta1.SetReferencePrefix)
+}
+
+// IsVerdictMap returns whether the given set is a verdict map.
+func IsVerdictMap(set *firewallv1beta1.Set) bool {
+	return set.DataType != nil && *set.DataType == firewallv1beta1.SetDataTypeVerdict
+}
+
+// IsAddressMap returns whether the given set is an address map, usable as NAT target.
+func IsAddressMap(set *firewallv1beta1.Set) bool {
+	return set.DataType != nil && *set.DataType == firewallv1beta1.SetDataTypeIPAddr
+}
+
+// FindSet returns the set with the given name.
+func FindSet(sets []firewallv1beta1.Set, name string) (*firewallv1beta1.Set, error) {
+	for i := range sets {
+		if sets[i].Name == name {
+			return &sets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("set %s not found", name)
+}
+
+// ForgeSet forges the nftables set corresponding to the given one, together with its elements.
+func ForgeSet(set *firewallv1beta1.Set, table *nftables.Table) (*nftables.Set, []nftables.SetElement, error) {
+	keyType, err := getSetDatatype(set.KeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if set.Interval && set.KeyType == firewallv1beta1.SetDataTypeIfName {
+		return nil, nil, fmt.Errorf("set %s: interval sets of type %s are not supported", set.Name, set.KeyType)
+	}
+	if !isSetKeyTypeAllowed(set.KeyType, table.Family) {
+		return nil, nil, fmt.Errorf("set %s: sets of type %s are not supported in tables of this family", set.Name, set.KeyType)
+	}
+
+	nftSet := &nftables.Set{
+		Table:    table,
+		Name:     set.Name,
+		KeyType:  keyType,
+		Interval: set.Interval,
+	}
+	if set.DataType != nil {
+		nftSet.IsMap = true
+		if nftSet.DataType, err = getSetDatatype(*set.DataType); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	elements, err := forgeSetElements(set)
+	if err != nil {
+		return nil, nil, fmt.Errorf("set %s: %w", set.Name, err)
+	}
+	return nftSet, elements, nil
+}
+
+func forgeSetElements(set *firewallv1beta1.Set) ([]nftables.SetElement, error) {
+	elements := make([]nftables.SetElement, 0, len(set.Elements))
+	for i := range set.Elements {
+		start, end, err := encodeSetKey(set, set.Elements[i].Key)
+		if err != nil {
+			return nil, err
+		}
+
+		element := nftables.SetElement{Key: start}
+		switch {
+		case set.DataType == nil && set.Elements[i].Value != nil:
+			return nil, fmt.Errorf("element %s has a value, but the set is not a map", set.Elements[i].Key)
+		case set.DataType != nil && set.Elements[i].Value == nil:
+			return nil, fmt.Errorf("entry %s has no value", set.Elements[i].Key)
+		case IsVerdictMap(set):
+			if element.VerdictData, err = parseVerdict(*set.Elements[i].Value); err != nil {
+				return nil, err
+			}
+		case IsAddressMap(set):
+			ip := net.ParseIP(*set.Elements[i].Value).To4()
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IPv4 address %s", *set.Elements[i].Value)
+			}
+			element.Val = ip
+		}
+		elements = append(elements, element)
+
+		// The end of the interval is the first value not included, and it is omitted if the interval reaches the maximum value.
+		if set.Interval && end != nil {
+			elements = append(elements, nftables.SetElement{Key: end, IntervalEnd: true})
+		}
+	}
+	return elements, nil
+}
+
+// encodeSetKey encodes the given key of a set element, returning also the end of the interval for interval sets.
+func encodeSetKey(set *firewallv1beta1.Set, key string) (start, end []byte, err error) {
+	switch set.KeyType {
+	case firewallv1beta1.SetDataTypeIPAddr:
+		first, last, err := parseIPv4Interval(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !set.Interval && first != last {
+			return nil, nil, fmt.Errorf("subnet %s is allowed only in interval sets", key)
+		}
+		start = binaryutil.BigEndian.PutUint32(first)
+		if last != ^uint32(0) {
+			end = binaryutil.BigEndian.PutUint32(last + 1)
+		}
+	case firewallv1beta1.SetDataTypeIP6Addr:
+		first, last, err := parseIPv6Interval(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !set.Interval && !first.Equal(last) {
+			return nil, nil, fmt.Errorf("subnet %s is allowed only in interval sets", key)
+		}
+		start = first
+		end = nextIP(last)
+	case firewallv1beta1.SetDataTypeInetService:
+		first, last, err := parsePortInterval(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !set.Interval && first != last {
+			return nil, nil, fmt.Errorf("port range %s is allowed only in interval sets", key)
+		}
+		start = binaryutil.BigEndian.PutUint16(first)
+		if last != ^uint16(0) {
+			end = binaryutil.BigEndian.PutUint16(last + 1)
+		}
+	case firewallv1beta1.SetDataTypeIfName:
+		if key == "" || len(key) >= 16 {
+			return nil, nil, fmt.Errorf("invalid interface name %q", key)
+		}
+		start = ifname(key)
+	default:
+		return nil, nil, fmt.Errorf("invalid key type %s", set.KeyType)
+	}
+	return start, end, nil
+}
+
+func parseIPv4Interval(value string) (first, last uint32, err error) {
+	if _, subnet, err := net.ParseCIDR(value); err == nil && subnet.IP.To4() != nil {
+		first = binary.BigEndian.Uint32(subnet.IP.To4())
+		mask := binary.BigEndian.Uint32(subnet.Mask)
+		return first, first | ^mask, nil
+	}
+	ip := net.ParseIP(value).To4()
+	if ip == nil {
+		return 0, 0, fmt.Errorf("invalid IPv4 address or subnet %s", value)
+	}
+	return binary.BigEndian.Uint32(ip), binary.BigEndian.Uint32(ip), nil
+}
+
+func parseIPv6Interval(value string) (first, last net.IP, err error) {
+	if _, subnet, err := net.ParseCIDR(value); err == nil && subnet.IP.To4() == nil {
+		first, last = subnet.IP.To16(), make(net.IP, net.IPv6len)
+		for i := range last {
+			last[i] = first[i] | ^subnet.Mask[i]
+		}
+		return first, last, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() != nil {
+		return nil, nil, fmt.Errorf("invalid IPv6 address or subnet %s", value)
+	}
+	return ip.To16(), ip.To16(), nil
+}
+
+// nextIP returns the address following the given one, or nil if it is the maximum address.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
+
+func parsePortInterval(value string) (first, last uint16, err error) {
+	if strings.Contains(value, "-") {
+		return port.ParsePortRange(value)
+	}
+	p, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port %s", value)
+	}
+	return uint16(p), uint16(p), nil
+}
+
+// parseVerdict parses a verdict, in the nft syntax (eg. accept, jump <chain>).
+func parseVerdict(value string) (*expr.Verdict, error) {
+	fields := strings.Fields(value)
+	switch {
+	case len(fields) == 1 && fields[0] == "accept":
+		return &expr.Verdict{Kind: expr.VerdictAccept}, nil
+	case len(fields) == 1 && fields[0] == "drop":
+		return &expr.Verdict{Kind: expr.VerdictDrop}, nil
+	case len(fields) == 1 && fields[0] == "return":
+		return &expr.Verdict{Kind: expr.VerdictReturn}, nil
+	case len(fields) == 2 && fields[0] == "jump":
+		return &expr.Verdict{Kind: expr.VerdictJump, Chain: fields[1]}, nil
+	case len(fields) == 2 && fields[0] == "goto":
+		return &expr.Verdict{Kind: expr.VerdictGoto, Chain: fields[1]}, nil
+	default:
+		return nil, fmt.Errorf("invalid verdict %q (allowed: accept, drop, return, jump <chain>, goto <chain>)", value)
+	}
+}
+
+func getSetDatatype(dataType firewallv1beta1.SetDataType) (nftables.SetDatatype, error) {
+	switch dataType {
+	case firewallv1beta1.SetDataTypeIPAddr:
+		return nftables.TypeIPAddr, nil
+	case firewallv1beta1.SetDataTypeIP6Addr:
+		return nftables.TypeIP6Addr, nil
+	case firewallv1beta1.SetDataTypeInetService:
+		return nftables.TypeInetService, nil
+	case firewallv1beta1.SetDataTypeIfName:
+		return nftables.TypeIFName, nil
+	case firewallv1beta1.SetDataTypeVerdict:
+		return nftables.TypeVerdict, nil
+	default:
+		return nftables.TypeInvalid, fmt.Errorf("invalid set data type %s", dataType)
+	}
+}
+
+// isSetKeyTypeAllowed returns whether sets of the given key type can be defined in tables of the given family.
+func isSetKeyTypeAllowed(keyType firewallv1beta1.SetDataType, family nftables.TableFamily) bool {
+	switch keyType {
+	case firewallv1beta1.SetDataTypeIPAddr:
+		return family != nftables.TableFamilyIPv6
+	case firewallv1beta1.SetDataTypeIP6Addr:
+		return family != nftables.TableFamilyIPv4
+	default:
+		return true
+	}
+}
+
+// GetIPSetKeyType returns the key type of the given set of addresses, defaulting to IPv4 if the set is not found.
+func GetIPSetKeyType(sets []firewallv1beta1.Set, name string) firewallv1beta1.SetDataType {
+	if set, err := FindSet(sets, name); err == nil && set.KeyType == firewallv1beta1.SetDataTypeIP6Addr {
+		return firewallv1beta1.SetDataTypeIP6Addr
+	}
+	return firewallv1beta1.SetDataTypeIPAddr
+}
+
+// applySetLookup appends the lookup of the value loaded in the first register in the referenced set,
+// checking that it is of the given key type. Verdict maps apply the verdict associated with the value.
+func applySetLookup(m *firewallv1beta1.Match, name string, keyType firewallv1beta1.SetDataType,
+	sets []firewallv1beta1.Set, rule *nftables.Rule) error {
+	set, err := findSetOfKeyType(sets, name, keyType)
+	if err != nil {
+		return err
+	}
+
+	lookup := &expr.Lookup{SourceRegister: 1, SetName: set.Name}
+	switch {
+	case IsVerdictMap(set):
+		if m.Op != firewallv1beta1.MatchOperationEq {
+			return fmt.Errorf("verdict map %s can be matched only with the %s operation", name, firewallv1beta1.MatchOperationEq)
+		}
+		lookup.IsDestRegSet = true
+		lookup.DestRegister = 0
+	case set.DataType != nil:
+		return fmt.Errorf("map %s of type %s cannot be matched", name, *set.DataType)
+	default:
+		lookup.Invert = m.Op == firewallv1beta1.MatchOperationNeq
+	}
+
+	rule.Exprs = append(rule.Exprs, lookup)
+	return nil
+}
+
+func findSetOfKeyType(sets []firewallv1beta1.Set, name string, keyType firewallv1beta1.SetDataType) (*firewallv1beta1.Set, error) {
+	set, err := FindSet(sets, name)
+	if err != nil {
+		return nil, err
+	}
+	if set.KeyType != keyType {
+		return nil, fmt.Errorf("set %s has key type %s, while %s is required", name, set.KeyType, keyType)
+	}
+	return set, nil
+}
+
+func findAddressMap(sets []firewallv1beta1.Set, name string) (*firewallv1beta1.Set, error) {
+	set, err := findSetOfKeyType(sets, name, firewallv1beta1.SetDataTypeIPAddr)
+	if err != nil {
+		return nil, err
+	}
+	if !IsAddressMap(set) {
+		return nil, fmt.Errorf("set %s is not an address map", name)
+	}
+	return set, nil
+}
+
+// CheckSetReferences checks that the sets referenced by the given rule exist, and that they are suitable for their usage.
+func CheckSetReferences(rule Rule) error {
+	switch r := rule.(type) {
+	case *FilterRuleWrapper:
+		return checkMatchesSetReferences(r.Match, r.Sets)
+	case *NatRuleWrapper:
+		if err := checkMatchesSetReferences(r.Match, r.Sets); err != nil {
+			return err
+		}
+		if r.To == nil {
+			return nil
+		}
+		if name, ok := GetSetReference(*r.To); ok {
+			if _, err := findAddressMap(r.Sets, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkMatchesSetReferences(matches []firewallv1beta1.Match, sets []firewallv1beta1.Set) error {
+	// The lookups are forged on a scratch rule, only to check the references.
+	scratch := &nftables.Rule{}
+	for i := range matches {
+		m := &matches[i]
+		if m.IP != nil {
+			if name, ok := GetSetReference(m.IP.Value); ok {
+				if err := applySetLookup(m, name, GetIPSetKeyType(sets, name), sets, scratch); err != nil {
+					return err
+				}
+			}
+		}
+		if m.Port != nil {
+			if name, ok := GetSetReference(m.Port.Value); ok {
+				if err := applySetLookup(m, name, firewallv1beta1.SetDataTypeInetService, sets, scratch); err != nil {
+					return err
+				}
+			}
+		}
+		if m.Dev != nil {
+			if name, ok := GetSetReference(m.Dev.Value); ok {
+				if err := applySetLookup(m, name, firewallv1beta1.SetDataTypeIfName, sets, scratch); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+)
+
+var _ = Describe("Sets", func() {
+	var (
+		ipv4  = &nftables.Table{Name: "test", Family: nftables.TableFamilyIPv4}
+		ipv6  = &nftables.Table{Name: "test", Family: nftables.TableFamilyIPv6}
+		inet  = &nftables.Table{Name: "test", Family: nftables.TableFamilyINet}
+		start = func(key []byte) nftables.SetElement { return nftables.SetElement{Key: key} }
+		end   = func(key []byte) nftables.SetElement { return nftables.SetElement{Key: key, IntervalEnd: true} }
+		ip    = func(address string) []byte {
+			if parsed := net.ParseIP(address); parsed.To4() != nil {
+				return parsed.To4()
+			}
+			return net.ParseIP(address).To16()
+		}
+		elements = func(keys ...string) []firewallv1beta1.SetElement {
+			res := make([]firewallv1beta1.SetElement, len(keys))
+			for i := range keys {
+				res[i] = firewallv1beta1.SetElement{Key: keys[i]}
+			}
+			return res
+		}
+	)
+
+	DescribeTable("should forge the set and its elements",
+		func(set *firewallv1beta1.Set, table *nftables.Table, keyType, dataType nftables.SetDatatype, expected []nftables.SetElement) {
+			nftSet, nftElements, err := ForgeSet(set, table)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nftSet.Name).To(Equal(set.Name))
+			Expect(nftSet.Table).To(Equal(table))
+			Expect(nftSet.Interval).To(Equal(set.Interval))
+			Expect(nftSet.IsMap).To(Equal(set.DataType != nil))
+			Expect(nftSet.KeyType).To(Equal(keyType))
+			Expect(nftSet.DataType).To(Equal(dataType))
+			Expect(nftElements).To(Equal(expected))
+		},
+		Entry("a set of IPv4 addresses",
+			&firewallv1beta1.Set{Name: "addrs", KeyType: firewallv1beta1.SetDataTypeIPAddr, Elements: elements("10.0.0.1", "10.0.0.2")},
+			ipv4, nftables.TypeIPAddr, nftables.SetDatatype{},
+			[]nftables.SetElement{start(ip("10.0.0.1")), start(ip("10.0.0.2"))}),
+		Entry("an interval set of IPv4 subnets",
+			&firewallv1beta1.Set{Name: "subnets", KeyType: firewallv1beta1.SetDataTypeIPAddr, Interval: true,
+				Elements: elements("10.0.0.0/8", "192.168.1.1", "255.255.255.0/24")},
+			inet, nftables.TypeIPAddr, nftables.SetDatatype{},
+			[]nftables.SetElement{
+				start(ip("10.0.0.0")), end(ip("11.0.0.0")),
+				start(ip("192.168.1.1")), end(ip("192.168.1.2")),
+				// The end of the interval is omitted if it reaches the maximum address.
+				start(ip("255.255.255.0")),
+			}),
+		Entry("a set of IPv6 addresses",
+			&firewallv1beta1.Set{Name: "addrs6", KeyType: firewallv1beta1.SetDataTypeIP6Addr, Elements: elements("2001:db8::1")},
+			ipv6, nftables.TypeIP6Addr, nftables.SetDatatype{},
+			[]nftables.SetElement{start(ip("2001:db8::1"))}),
+		Entry("an interval set of IPv6 subnets",
+			&firewallv1beta1.Set{Name: "subnets6", KeyType: firewallv1beta1.SetDataTypeIP6Addr, Interval: true,
+				Elements: elements("2001:db8::/32", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "ffff::/16")},
+			inet, nftables.TypeIP6Addr, nftables.SetDatatype{},
+			[]nftables.SetElement{
+				start(ip("2001:db8::")), end(ip("2001:db9::")),
+				start(ip("2001:db8:ffff:ffff:ffff:ffff:ffff:ffff")), end(ip("2001:db9::")),
+				start(ip("ffff::")),
+			}),
+		Entry("an interval set of ports",
+			&firewallv1beta1.Set{Name: "ports", KeyType: firewallv1beta1.SetDataTypeInetService, Interval: true,
+				Elements: elements("80", "1000-2000", "65000-65535")},
+			ipv4, nftables.TypeInetService, nftables.SetDatatype{},
+			[]nftables.SetElement{
+				start(binaryutil.BigEndian.PutUint16(80)), end(binaryutil.BigEndian.PutUint16(81)),
+				start(binaryutil.BigEndian.PutUint16(1000)), end(binaryutil.BigEndian.PutUint16(2001)),
+				start(binaryutil.BigEndian.PutUint16(65000)),
+			}),
+		Entry("a set of interfaces",
+			&firewallv1beta1.Set{Name: "devs", KeyType: firewallv1beta1.SetDataTypeIfName, Elements: elements("eth0")},
+			ipv4, nftables.TypeIFName, nftables.SetDatatype{},
+			[]nftables.SetElement{start(ifname("eth0"))}),
+		Entry("a verdict map",
+			&firewallv1beta1.Set{Name: "verdicts", KeyType: firewallv1beta1.SetDataTypeIPAddr,
+				DataType: ptr.To(firewallv1beta1.SetDataTypeVerdict), Elements: []firewallv1beta1.SetElement{
+					{Key: "10.0.0.1", Value: ptr.To("drop")},
+					{Key: "10.0.0.2", Value: ptr.To("jump pods")},
+				}},
+			ipv4, nftables.TypeIPAddr, nftables.TypeVerdict,
+			[]nftables.SetElement{
+				{Key: ip("10.0.0.1"), VerdictData: &expr.Verdict{Kind: expr.VerdictDrop}},
+				{Key: ip("10.0.0.2"), VerdictData: &expr.Verdict{Kind: expr.VerdictJump, Chain: "pods"}},
+			}),
+		Entry("an interval verdict map",
+			&firewallv1beta1.Set{Name: "verdicts", KeyType: firewallv1beta1.SetDataTypeIPAddr, Interval: true,
+				DataType: ptr.To(firewallv1beta1.SetDataTypeVerdict), Elements: []firewallv1beta1.SetElement{
+					{Key: "10.0.0.0/24", Value: ptr.To("accept")},
+				}},
+			ipv4, nftables.TypeIPAddr, nftables.TypeVerdict,
+			[]nftables.SetElement{
+				{Key: ip("10.0.0.0"), VerdictData: &expr.Verdict{Kind: expr.VerdictAccept}},
+				end(ip("10.0.1.0")),
+			}),
+		Entry("an address map",
+			&firewallv1beta1.Set{Name: "translations", KeyType: firewallv1beta1.SetDataTypeIPAddr,
+				DataType: ptr.To(firewallv1beta1.SetDataTypeIPAddr), Elements: []firewallv1beta1.SetElement{
+					{Key: "10.70.0.1", Value: ptr.To("10.0.0.1")},
+				}},
+			ipv4, nftables.TypeIPAddr, nftables.TypeIPAddr,
+			[]nftables.SetElement{{Key: ip("10.70.0.1"), Val: ip("10.0.0.1")}}),
+	)
+
+	DescribeTable("should fail to forge an invalid set",
+		func(set *firewallv1beta1.Set, table *nftables.Table) {
+			_, _, err := ForgeSet(set, table)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("a subnet in a non-interval set",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr, Elements: elements("10.0.0.0/8")}, ipv4),
+		Entry("an IPv6 subnet in a non-interval set",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIP6Addr, Elements: elements("2001:db8::/32")}, ipv6),
+		Entry("a port range in a non-interval set",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeInetService, Elements: elements("1000-2000")}, ipv4),
+		Entry("an IPv6 address in an IPv4 set",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr, Elements: elements("2001:db8::1")}, ipv4),
+		Entry("an IPv4 address in an IPv6 set",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIP6Addr, Elements: elements("10.0.0.1")}, ipv6),
+		Entry("an IPv4 set in an IPv6 table",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr}, ipv6),
+		Entry("an IPv6 set in an IPv4 table",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIP6Addr}, ipv4),
+		Entry("an interval set of interfaces",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIfName, Interval: true}, ipv4),
+		Entry("a value in a set which is not a map",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr,
+				Elements: []firewallv1beta1.SetElement{{Key: "10.0.0.1", Value: ptr.To("accept")}}}, ipv4),
+		Entry("a map entry without value",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr,
+				DataType: ptr.To(firewallv1beta1.SetDataTypeVerdict), Elements: elements("10.0.0.1")}, ipv4),
+		Entry("an invalid verdict",
+			&firewallv1beta1.Set{Name: "s", KeyType: firewallv1beta1.SetDataTypeIPAddr,
+				DataType: ptr.To(firewallv1beta1.SetDataTypeVerdict),
+				Elements: []firewallv1beta1.SetElement{{Key: "10.0.0.1", Value: ptr.To("jump")}}}, ipv4),
+	)
+
+	It("should match the IPv6 addresses only in the IPv6 packets", func() {
+		sets := []firewallv1beta1.Set{{Name: "addrs6", KeyType: firewallv1beta1.SetDataTypeIP6Addr}}
+		match := &firewallv1beta1.Match{
+			Op: firewallv1beta1.MatchOperationNeq,
+			IP: &firewallv1beta1.MatchIP{Value: "@addrs6", Position: firewallv1beta1.MatchPositionDst},
+		}
+		rule := &nftables.Rule{}
+		Expect(applyMatch(match, rule, sets)).To(Succeed())
+		Expect(rule.Exprs).To(Equal([]expr.Any{
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{10}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 24, Len: 16},
+			&expr.Lookup{SourceRegister: 1, SetName: "addrs6", Invert: true},
+		}))
+	})
+})
@@ -0,0 +1,78 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// logPrefixPrefix is prepended to the name of the rule to derive the log prefix.
+	logPrefixPrefix = "liqo:"
+	// maxLogPrefixLength is the maximum length of a log prefix accepted by the kernel, excluding the terminator.
+	maxLogPrefixLength = 127
+)
+
+// LogPrefix returns the prefix of the packets logged by the rule with the given name.
+func LogPrefix(name string) string {
+	prefix := logPrefixPrefix + name
+	// Leave room for the trailing space separating the prefix from the packet details.
+	if len(prefix) > maxLogPrefixLength-1 {
+		prefix = prefix[:maxLogPrefixLength-1]
+	}
+	return prefix + " "
+}
+
+// GetCounter returns the counter statement of the given rule, if any.
+func GetCounter(rule *nftables.Rule) *expr.Counter {
+	for i := range rule.Exprs {
+		if counter, ok := rule.Exprs[i].(*expr.Counter); ok {
+			return counter
+		}
+	}
+	return nil
+}
+
+func hasLog(rule *nftables.Rule) bool {
+	for i := range rule.Exprs {
+		if _, ok := rule.Exprs[i].(*expr.Log); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyStatements appends the counter and log statements to the rule, before its action.
+func applyStatements(name string, counter, log bool, rule *nftables.Rule) {
+	if counter {
+		rule.Exprs = append(rule.Exprs, &expr.Counter{})
+	}
+	if log {
+		rule.Exprs = append(rule.Exprs, &expr.Log{
+			Key:  1 << unix.NFTA_LOG_PREFIX,
+			Data: []byte(LogPrefix(name)),
+		})
+	}
+}
+
+// marshalExpr marshals the given expression, ignoring the values of the counters so that rules can be compared.
+func marshalExpr(family nftables.TableFamily, e expr.Any) ([]byte, error) {
+	if _, ok := e.(*expr.Counter); ok {
+		e = &expr.Counter{}
+	}
+	return expr.Marshal(byte(family), e)
+}
@@ -0,0 +1,115 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+	"k8s.io/utils/ptr"
+
+	firewallv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+)
+
+var _ = Describe("Statements", func() {
+	DescribeTable("should derive the log prefix from the rule name",
+		func(name, expected string) {
+			prefix := LogPrefix(name)
+			Expect(prefix).To(Equal(expected))
+			Expect(len(prefix)).To(BeNumerically("<=", maxLogPrefixLength))
+		},
+		Entry("a short name", "allow-web", "liqo:allow-web "),
+		Entry("an empty name", "", "liqo: "),
+		Entry("a name filling the prefix", strings.Repeat("a", 121), "liqo:"+strings.Repeat("a", 121)+" "),
+		Entry("a name exceeding the prefix", strings.Repeat("b", 200), "liqo:"+strings.Repeat("b", 121)+" "),
+	)
+
+	DescribeTable("should append the statements before the action",
+		func(counter, log bool, expected ...expr.Any) {
+			rule := &nftables.Rule{}
+			applyStatements("test", counter, log, rule)
+			if len(expected) == 0 {
+				Expect(rule.Exprs).To(BeEmpty())
+				return
+			}
+			Expect(rule.Exprs).To(Equal(expected))
+		},
+		Entry("no statements", false, false),
+		Entry("the counter", true, false, &expr.Counter{}),
+		Entry("the log", false, true, &expr.Log{Key: 1 << unix.NFTA_LOG_PREFIX, Data: []byte("liqo:test ")}),
+		Entry("the counter and the log", true, true,
+			&expr.Counter{}, &expr.Log{Key: 1 << unix.NFTA_LOG_PREFIX, Data: []byte("liqo:test ")}),
+	)
+
+	Describe("the equality of the rules with statements", func() {
+		var chain *nftables.Chain
+
+		// current returns the given rule as forged, with the counter incremented as if it had matched some packets.
+		current := func(forge func(*nftables.Chain) (*nftables.Rule, error)) *nftables.Rule {
+			rule, err := forge(chain)
+			Expect(err).ToNot(HaveOccurred())
+			rule.Table = chain.Table
+			if counter := GetCounter(rule); counter != nil {
+				counter.Packets, counter.Bytes = 12, 3456
+			}
+			return rule
+		}
+
+		BeforeEach(func() {
+			chain = &nftables.Chain{Name: "test", Table: &nftables.Table{Name: "test", Family: nftables.TableFamilyIPv4}}
+		})
+
+		DescribeTable("filter rules",
+			func(action firewallv1beta1.FilterAction, counter, log bool) {
+				desired := &FilterRuleWrapper{FilterRule: &firewallv1beta1.FilterRule{
+					Name: ptr.To("test"), Action: action, Counter: counter, Log: log,
+					Value: ptr.To("1"),
+					Match: []firewallv1beta1.Match{{
+						Op: firewallv1beta1.MatchOperationEq,
+						IP: &firewallv1beta1.MatchIP{Value: "10.0.0.1", Position: firewallv1beta1.MatchPositionSrc},
+					}},
+				}}
+				Expect(desired.Equal(current(desired.Forge))).To(BeTrue())
+
+				// The rules differing only in the statements are not equal.
+				toggled := &FilterRuleWrapper{FilterRule: desired.FilterRule.DeepCopy()}
+				toggled.Counter = !counter
+				Expect(desired.Equal(current(toggled.Forge))).To(BeFalse())
+				toggled = &FilterRuleWrapper{FilterRule: desired.FilterRule.DeepCopy()}
+				toggled.Log = !log
+				Expect(desired.Equal(current(toggled.Forge))).To(BeFalse())
+			},
+			Entry("with counter", firewallv1beta1.ActionAccept, true, false),
+			Entry("with log", firewallv1beta1.ActionDrop, false, true),
+			Entry("with counter and log", firewallv1beta1.ActionAccept, true, true),
+			Entry("with counter and log, setting the ct mark", firewallv1beta1.ActionCtMark, true, true),
+		)
+
+		It("nat rules", func() {
+			desired := &NatRuleWrapper{NatRule: &firewallv1beta1.NatRule{
+				Name: ptr.To("test"), NatType: firewallv1beta1.NatTypeMasquerade, Counter: true,
+			}}
+			Expect(desired.Equal(current(desired.Forge))).To(BeTrue())
+
+			toggled := &NatRuleWrapper{NatRule: desired.NatRule.DeepCopy()}
+			toggled.Counter = false
+			Expect(desired.Equal(current(toggled.Forge))).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,96 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultPort is the port the benchmark server listens on, for both the TCP and the UDP traffic.
+	DefaultPort = 5201
+
+	// byeMsg is the UDP message sent by the client to terminate the server.
+	byeMsg = "bye"
+	// pingSize is the size of the UDP pings, carrying their sequence number and timestamp.
+	pingSize = 16
+	// pingInterval is the interval between two consecutive pings.
+	pingInterval = 200 * time.Millisecond
+	// pingTimeout is the time waited for the answers of the pings after sending the last one.
+	pingTimeout = time.Second
+	// bufferSize is the size of the buffers written and read by the throughput streams.
+	bufferSize = 128 * 1024
+)
+
+// Result contains the results of a benchmark.
+type Result struct {
+	Throughput *Throughput `json:"throughput,omitempty"`
+	Latency    *Latency    `json:"latency,omitempty"`
+}
+
+// Throughput contains the results of a throughput measurement.
+type Throughput struct {
+	// Sent is the throughput measured by the sender, in bits per second.
+	Sent float64 `json:"sent"`
+	// Received is the throughput measured by the receiver, in bits per second.
+	Received float64 `json:"received"`
+	// Retransmits is the number of TCP segments retransmitted by the sender.
+	Retransmits int `json:"retransmits"`
+}
+
+// Latency contains the results of a latency measurement.
+type Latency struct {
+	// Samples is the number of pings answered.
+	Samples int `json:"samples"`
+	// PacketLoss is the percentage of lost pings.
+	PacketLoss float64 `json:"packetLoss"`
+	// Min is the minimum round-trip time.
+	Min time.Duration `json:"min"`
+	// P50 is the 50th percentile of the round-trip time.
+	P50 time.Duration `json:"p50"`
+	// P90 is the 90th percentile of the round-trip time.
+	P90 time.Duration `json:"p90"`
+	// P99 is the 99th percentile of the round-trip time.
+	P99 time.Duration `json:"p99"`
+	// Max is the maximum round-trip time.
+	Max time.Duration `json:"max"`
+}
+
+// newLatency computes the distribution of the given round-trip times, measured over the given number of pings.
+func newLatency(sent int, rtts []time.Duration) *Latency {
+	latency := &Latency{Samples: len(rtts)}
+	if sent == 0 {
+		return latency
+	}
+	latency.PacketLoss = float64(sent-len(rtts)) * 100 / float64(sent)
+	if len(rtts) == 0 {
+		return latency
+	}
+
+	sorted := slices.Sorted(slices.Values(rtts))
+	latency.Min = sorted[0]
+	latency.P50 = percentile(sorted, 50)
+	latency.P90 = percentile(sorted, 90)
+	latency.P99 = percentile(sorted, 99)
+	latency.Max = sorted[len(sorted)-1]
+	return latency
+}
+
+// percentile returns the given percentile of the sorted samples, according to the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Benchmark", func() {
+	Describe("The newLatency function", func() {
+		It("should compute the distribution of the round-trip times", func() {
+			rtts := make([]time.Duration, 0, 100)
+			for i := 100; i > 0; i-- {
+				rtts = append(rtts, time.Duration(i)*time.Millisecond)
+			}
+			latency := newLatency(200, rtts)
+			Expect(latency.Samples).To(Equal(100))
+			Expect(latency.PacketLoss).To(BeNumerically("~", 50))
+			Expect(latency.Min).To(Equal(time.Millisecond))
+			Expect(latency.P50).To(Equal(50 * time.Millisecond))
+			Expect(latency.P90).To(Equal(90 * time.Millisecond))
+			Expect(latency.P99).To(Equal(99 * time.Millisecond))
+			Expect(latency.Max).To(Equal(100 * time.Millisecond))
+		})
+
+		It("should report a full loss if no ping is answered", func() {
+			latency := newLatency(10, nil)
+			Expect(latency.Samples).To(BeZero())
+			Expect(latency.PacketLoss).To(BeNumerically("~", 100))
+			Expect(latency.Max).To(BeZero())
+		})
+	})
+
+	Describe("The client and the server", func() {
+		var port int
+
+		BeforeEach(func() {
+			// Reserve a free port, which is available for both TCP and UDP in the test environment.
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			port = listener.Addr().(*net.TCPAddr).Port
+			Expect(listener.Close()).To(Succeed())
+		})
+
+		It("should measure the throughput and the latency, and terminate the server", func(ctx SpecContext) {
+			serverErr := make(chan error, 1)
+			go func() { serverErr <- RunServer(ctx, port, time.Minute) }()
+
+			var result *Result
+			Eventually(func() (err error) {
+				result, err = RunClient(ctx, &ClientOptions{
+					Address: "127.0.0.1", Port: port, Duration: 500 * time.Millisecond, Streams: 2, Pings: 5,
+				})
+				return err
+			}).Should(Succeed())
+
+			Expect(result.Throughput).ToNot(BeNil())
+			Expect(result.Throughput.Sent).To(BeNumerically(">", 0))
+			Expect(result.Throughput.Received).To(BeNumerically(">", 0))
+			Expect(result.Throughput.Received).To(BeNumerically("<=", result.Throughput.Sent))
+			Expect(result.Latency).ToNot(BeNil())
+			Expect(result.Latency.Samples).To(Equal(5))
+			Expect(result.Latency.PacketLoss).To(BeZero())
+			Expect(result.Latency.Min).To(BeNumerically("<=", result.Latency.Max))
+
+			Eventually(serverErr).Should(Receive(BeNil()))
+		}, SpecTimeout(30*time.Second))
+
+		It("should terminate the server once the timeout expires", func(ctx SpecContext) {
+			Expect(RunServer(ctx, port, 100*time.Millisecond)).To(Succeed())
+		}, SpecTimeout(5*time.Second))
+
+		It("should fail if the context is canceled", func(ctx SpecContext) {
+			go func() { _ = RunServer(ctx, port, time.Minute) }()
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err := RunClient(cctx, &ClientOptions{Address: "127.0.0.1", Port: port, Duration: time.Second, Pings: 1})
+			Expect(err).To(HaveOccurred())
+		}, SpecTimeout(5*time.Second))
+	})
+})
@@ -0,0 +1,254 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sys/unix"
+)
+
+// ClientOptions contains the options of the benchmark client.
+type ClientOptions struct {
+	// Address is the address of the benchmark server.
+	Address string
+	// Port is the port of the benchmark server.
+	Port int
+	// Duration is the duration of the throughput measurement.
+	Duration time.Duration
+	// Streams is the number of parallel TCP streams of the throughput measurement.
+	Streams int
+	// Pings is the number of pings of the latency measurement.
+	Pings int
+}
+
+// RunClient measures the throughput and the latency towards the benchmark server, and terminates it.
+func RunClient(ctx context.Context, opts *ClientOptions) (*Result, error) {
+	address := net.JoinHostPort(opts.Address, strconv.Itoa(opts.Port))
+
+	var dialer net.Dialer
+	pc, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s/udp: %w", address, err)
+	}
+	defer pc.Close()
+
+	// The server is not terminated if the throughput measurement fails, as it might not be ready yet and the
+	// measurement can be retried. In any case, the server terminates once its timeout expires.
+	throughput, err := measureThroughput(ctx, address, opts.Duration, max(opts.Streams, 1))
+	if err != nil {
+		return nil, err
+	}
+	defer pc.Write([]byte(byeMsg)) //nolint:errcheck // Best effort, as the server terminates anyway once its timeout expires.
+
+	latency, err := measureLatency(ctx, pc, max(opts.Pings, 1))
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Throughput: throughput, Latency: latency}, nil
+}
+
+// measureThroughput sends data to the server through the given number of parallel TCP streams, for the given duration.
+// The rates are computed up to the last byte written, excluding the time spent waiting for the reports of the server.
+func measureThroughput(ctx context.Context, address string, duration time.Duration, streams int) (*Throughput, error) {
+	var (
+		m     sync.Mutex
+		total streamStats
+	)
+
+	conns := make([]*net.TCPConn, streams)
+	defer func() {
+		for _, conn := range conns {
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}()
+
+	var dialer net.Dialer
+	for i := range conns {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial %s/tcp: %w", address, err)
+		}
+		conns[i] = conn.(*net.TCPConn)
+	}
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	g, ctx := errgroup.WithContext(ctx)
+	for _, conn := range conns {
+		g.Go(func() error {
+			stats, err := runStream(ctx, conn, deadline)
+			m.Lock()
+			defer m.Unlock()
+			total.sent += stats.sent
+			total.received += stats.received
+			total.retransmits += stats.retransmits
+			if stats.end.After(total.end) {
+				total.end = stats.end
+			}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	elapsed := total.end.Sub(start).Seconds()
+	return &Throughput{
+		Sent:        float64(total.sent*8) / elapsed,
+		Received:    float64(total.received*8) / elapsed,
+		Retransmits: int(total.retransmits),
+	}, nil
+}
+
+// streamStats contains the outcome of a throughput stream.
+type streamStats struct {
+	// sent is the number of bytes sent.
+	sent uint64
+	// received is the number of bytes received by the server.
+	received uint64
+	// retransmits is the number of TCP segments retransmitted.
+	retransmits uint64
+	// end is the time the last byte has been written.
+	end time.Time
+}
+
+// runStream writes to the given connection until the deadline, and then waits for the report of the server.
+func runStream(ctx context.Context, conn *net.TCPConn, deadline time.Time) (stats streamStats, err error) {
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return stats, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetWriteDeadline(time.Now()) }) //nolint:errcheck // Best effort.
+	defer stop()
+
+	buff := make([]byte, bufferSize)
+	for {
+		n, err := conn.Write(buff)
+		stats.sent += uint64(n)
+		stats.end = time.Now()
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to send to %s: %w", conn.RemoteAddr(), err)
+		}
+	}
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+
+	if stats.retransmits, err = tcpRetransmits(conn); err != nil {
+		return stats, err
+	}
+
+	if err := conn.CloseWrite(); err != nil {
+		return stats, fmt.Errorf("failed to close the stream towards %s: %w", conn.RemoteAddr(), err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(pingTimeout * 10)); err != nil {
+		return stats, err
+	}
+	if err := binary.Read(conn, binary.BigEndian, &stats.received); err != nil {
+		return stats, fmt.Errorf("failed to receive the report from %s: %w", conn.RemoteAddr(), err)
+	}
+	return stats, nil
+}
+
+// tcpRetransmits returns the number of TCP segments retransmitted through the given connection.
+func tcpRetransmits(conn *net.TCPConn) (uint64, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var info *unix.TCPInfo
+	var sockErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		info, sockErr = unix.GetsockoptTCPInfo(int(fd), unix.IPPROTO_TCP, unix.TCP_INFO)
+	}); err != nil {
+		return 0, err
+	}
+	if sockErr != nil {
+		return 0, fmt.Errorf("failed to get the TCP info: %w", sockErr)
+	}
+	return uint64(info.Total_retrans), nil
+}
+
+// measureLatency sends the given number of pings through the given connection, and measures their round-trip time.
+func measureLatency(ctx context.Context, conn net.Conn, pings int) (*Latency, error) {
+	var (
+		m    sync.Mutex
+		rtts = make(map[uint64]time.Duration, pings)
+		done = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		buff := make([]byte, pingSize)
+		for {
+			n, err := conn.Read(buff)
+			if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil || n != pingSize {
+				// E.g., the server is not reachable, hence the ping is lost.
+				continue
+			}
+			seq := binary.BigEndian.Uint64(buff[:8])
+			sentAt := time.Unix(0, int64(binary.BigEndian.Uint64(buff[8:])))
+			m.Lock()
+			rtts[seq] = time.Since(sentAt)
+			m.Unlock()
+		}
+	}()
+
+	buff := make([]byte, pingSize)
+	for seq := range pings {
+		binary.BigEndian.PutUint64(buff[:8], uint64(seq))
+		binary.BigEndian.PutUint64(buff[8:], uint64(time.Now().UnixNano()))
+		if _, err := conn.Write(buff); err != nil {
+			return nil, fmt.Errorf("failed to send ping to %s: %w", conn.RemoteAddr(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pingInterval):
+		}
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(pingTimeout)); err != nil {
+		return nil, err
+	}
+	<-done
+
+	m.Lock()
+	defer m.Unlock()
+	values := make([]time.Duration, 0, len(rtts))
+	for _, rtt := range rtts {
+		values = append(values, rtt)
+	}
+	return newLatency(pings, values), nil
+}
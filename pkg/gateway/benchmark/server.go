@@ -0,0 +1,103 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RunServer runs the benchmark server on the given port, until the client terminates it or the given timeout expires.
+// The TCP streams are discarded, replying with the number of received bytes once closed by the client,
+// while the UDP pings are echoed back.
+func RunServer(ctx context.Context, port int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := net.JoinHostPort("", strconv.Itoa(port))
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s/tcp: %w", address, err)
+	}
+	defer listener.Close()
+
+	pc, err := lc.ListenPacket(ctx, "udp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s/udp: %w", address, err)
+	}
+	defer pc.Close()
+
+	// Unblock the listeners once the server terminates.
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+		pc.Close()
+	}()
+
+	go acceptStreams(listener)
+	if err := echoPings(pc); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// acceptStreams serves the throughput streams, until the listener is closed.
+func acceptStreams(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			received, err := io.CopyBuffer(io.Discard, conn, make([]byte, bufferSize))
+			if err != nil {
+				klog.Warningf("failed to receive from %s: %v", conn.RemoteAddr(), err)
+				return
+			}
+			if err := binary.Write(conn, binary.BigEndian, uint64(received)); err != nil {
+				klog.Warningf("failed to reply to %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// echoPings echoes back the UDP pings, until the client sends the termination message or the connection is closed.
+func echoPings(pc net.PacketConn) error {
+	buff := make([]byte, pingSize)
+	for {
+		n, addr, err := pc.ReadFrom(buff)
+		switch {
+		case errors.Is(err, net.ErrClosed):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to receive ping: %w", err)
+		case string(buff[:n]) == byeMsg:
+			return nil
+		}
+		if _, err := pc.WriteTo(buff[:n], addr); err != nil {
+			klog.Warningf("failed to echo ping to %s: %v", addr, err)
+		}
+	}
+}
@@ -18,4 +18,8 @@
 // Then, the active gateway is labeled with the ActiveGatewayKey and ActiveGatewayValue, and the passive gateways are unlabeled.
 // The gateway service target the active gateway using the ActiveGatewayKey and ActiveGatewayValue labels.
 // In order to cohordinate the sidecar containers, the gateway uses a unix socket to manage the IPC, and to start the sidecars when it becomes leader.
+// Alternatively, replicas can be managed using an active/active approach, where each replica claims a replica index through a lease,
+// and terminates its own tunnel, paired with the remote replica with the same index.
+// In active/passive mode, the standby replicas can optionally receive the conntrack entries of the active one,
+// restoring them when promoted before being labeled as active.
 package concurrent
@@ -20,9 +20,11 @@ import (
 	"net"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/liqotech/liqo/pkg/gateway/conntrack"
 	"github.com/liqotech/liqo/pkg/utils/ipc"
 )
 
@@ -35,13 +37,19 @@ type RunnableGateway struct {
 	PodName     string
 	GatewayName string
 	Namespace   string
+	// ActiveActive is true if all the replicas are active, hence the other replicas must not be unlabeled.
+	ActiveActive bool
+	// ConntrackReceiver, if set, injects the conntrack entries received from the previously active replica
+	// before the pod is labeled as active, so that established connections survive the failover.
+	ConntrackReceiver *conntrack.Receiver
 
 	Socket           net.Listener
 	GuestConnections ipc.GuestConnections
 }
 
 // NewRunnableGatewayStartup creates a new Runnable.
-func NewRunnableGatewayStartup(cl client.Client, podName, gatewayName, namespace string, containerNames []string) (*RunnableGateway, error) {
+func NewRunnableGatewayStartup(cl client.Client, podName, gatewayName, namespace string, containerNames []string,
+	activeActive bool) (*RunnableGateway, error) {
 	guestConnections := ipc.NewGuestConnections(containerNames)
 
 	socket, err := ipc.CreateListenSocket(unixSocketPath)
@@ -59,6 +67,7 @@ func NewRunnableGatewayStartup(cl client.Client, podName, gatewayName, namespace
 		PodName:          podName,
 		GatewayName:      gatewayName,
 		Namespace:        namespace,
+		ActiveActive:     activeActive,
 		Socket:           socket,
 		GuestConnections: guestConnections,
 	}, nil
@@ -78,7 +87,7 @@ func (rg *RunnableGateway) Start(ctx context.Context) error {
 	for i := range pods {
 		if pods[i].GetName() == rg.PodName {
 			activePod = &pods[i]
-		} else {
+		} else if !rg.ActiveActive {
 			if err := RemoveActiveGatewayLabel(ctx, rg.Client, client.ObjectKeyFromObject(&pods[i])); err != nil {
 				return err
 			}
@@ -89,6 +98,12 @@ func (rg *RunnableGateway) Start(ctx context.Context) error {
 		return fmt.Errorf("active gateway pod not found")
 	}
 
+	if rg.ConntrackReceiver != nil {
+		if err := rg.ConntrackReceiver.Promote(); err != nil {
+			klog.Warningf("Unable to restore the conntrack entries of the previously active replica: %v", err)
+		}
+	}
+
 	if err := AddActiveGatewayLabel(ctx, rg.Client, client.ObjectKeyFromObject(activePod)); err != nil {
 		return err
 	}
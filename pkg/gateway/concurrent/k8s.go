@@ -16,6 +16,7 @@ package concurrent
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -87,3 +88,21 @@ func ListAllGatewaysReplicas(ctx context.Context, cl client.Client, namespace, g
 	}
 	return podList.Items, nil
 }
+
+// GetActiveGatewayPodIP returns the IP of the active replica of the given gateway, ignoring the given pod.
+func GetActiveGatewayPodIP(ctx context.Context, cl client.Client, namespace, gatewayName, excludedPodName string) (string, error) {
+	pods, err := ListAllGatewaysReplicas(ctx, cl, namespace, gatewayName)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.GetName() == excludedPodName || pod.GetLabels()[ActiveGatewayKey] != ActiveGatewayValue ||
+			!pod.DeletionTimestamp.IsZero() || pod.Status.PodIP == "" {
+			continue
+		}
+		return pod.Status.PodIP, nil
+	}
+	return "", fmt.Errorf("no active replica found for gateway %s/%s", namespace, gatewayName)
+}
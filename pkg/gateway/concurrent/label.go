@@ -14,9 +14,13 @@
 
 package concurrent
 
+import "github.com/liqotech/liqo/pkg/consts"
+
 const (
 	// ActiveGatewayKey is the key used to label the active pod gateway.
-	ActiveGatewayKey = "networking.liqo.io/active"
+	ActiveGatewayKey = consts.GatewayActiveLabel
 	// ActiveGatewayValue is the value used to label the active pod gateway.
-	ActiveGatewayValue = "true"
+	ActiveGatewayValue = consts.GatewayActiveLabelValue
+	// ReplicaIndexKey is the key used to label the pods of active-active gateways with their replica index.
+	ReplicaIndexKey = consts.GatewayReplicaIndexLabel
 )
@@ -0,0 +1,182 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MaxReplicas is the maximum number of replicas of the same gateway in active-active mode.
+const MaxReplicas = 16
+
+// ReplicaLeaseName returns the name of the lease used to claim the given replica index.
+func ReplicaLeaseName(id string, index int) string {
+	return fmt.Sprintf("%s.replica-%d", id, index)
+}
+
+// ClaimReplicaIndex claims the lowest replica index not held by another existing pod, and labels the pod with it.
+// Each index is claimed through a lease held by the pod, and owned by it to be garbage collected once the pod is deleted.
+// The id identifies the gateway, and must be unique in the namespace.
+func ClaimReplicaIndex(ctx context.Context, cl client.Client, namespace, id, podName string) (int, error) {
+	pod := &corev1.Pod{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil {
+		return 0, fmt.Errorf("unable to get pod %s/%s: %w", namespace, podName, err)
+	}
+
+	for index := range MaxReplicas {
+		claimed, err := claimReplicaLease(ctx, cl, pod, ReplicaLeaseName(id, index))
+		if err != nil {
+			return 0, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := addReplicaIndexLabel(ctx, cl, pod, index); err != nil {
+			return 0, err
+		}
+		klog.Infof("Pod %s/%s claimed the gateway replica index %d", pod.Namespace, pod.Name, index)
+		return index, nil
+	}
+
+	return 0, fmt.Errorf("unable to claim a replica index for pod %s/%s: all the %d indexes are in use", namespace, podName, MaxReplicas)
+}
+
+// GetReplicaIndex returns the replica index the given pod is labeled with.
+func GetReplicaIndex(ctx context.Context, cl client.Client, key client.ObjectKey) (int, error) {
+	pod := &corev1.Pod{}
+	if err := cl.Get(ctx, key, pod); err != nil {
+		return 0, err
+	}
+	return ReplicaIndexFromPod(pod)
+}
+
+// WaitReplicaIndex waits until the given pod is labeled with its replica index, and returns it.
+func WaitReplicaIndex(ctx context.Context, cl client.Client, key client.ObjectKey) (int, error) {
+	var index int
+	err := wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		var err error
+		if index, err = GetReplicaIndex(ctx, cl, key); err != nil {
+			klog.V(4).Infof("Waiting for the replica index of pod %s: %v", key, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	return index, err
+}
+
+// ReplicaIndexFromPod returns the replica index the given pod is labeled with.
+func ReplicaIndexFromPod(pod *corev1.Pod) (int, error) {
+	value, ok := pod.GetLabels()[ReplicaIndexKey]
+	if !ok {
+		return 0, fmt.Errorf("pod %s/%s has no replica index", pod.Namespace, pod.Name)
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("pod %s/%s has an invalid replica index %q: %w", pod.Namespace, pod.Name, value, err)
+	}
+	return index, nil
+}
+
+// claimReplicaLease tries to acquire the given lease for the pod.
+// The lease is acquired if it does not exist, if it is already held by the pod, or if its holder no longer exists.
+func claimReplicaLease(ctx context.Context, cl client.Client, pod *corev1.Pod, name string) (bool, error) {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       pod.Namespace,
+			OwnerReferences: forgePodOwnerReferences(pod),
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: ptr.To(pod.Name),
+			AcquireTime:    ptr.To(metav1.NewMicroTime(time.Now())),
+		},
+	}
+
+	err := cl.Create(ctx, lease)
+	switch {
+	case err == nil:
+		return true, nil
+	case !apierrors.IsAlreadyExists(err):
+		return false, fmt.Errorf("unable to create lease %s/%s: %w", lease.Namespace, lease.Name, err)
+	}
+
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		return false, fmt.Errorf("unable to get lease %s/%s: %w", lease.Namespace, lease.Name, err)
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder == pod.Name {
+		return true, nil
+	}
+
+	if holder != "" {
+		err := cl.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: holder}, &corev1.Pod{})
+		switch {
+		case err == nil:
+			return false, nil
+		case !apierrors.IsNotFound(err):
+			return false, fmt.Errorf("unable to get pod %s/%s: %w", pod.Namespace, holder, err)
+		}
+	}
+
+	// The holder no longer exists: take over the lease.
+	lease.OwnerReferences = forgePodOwnerReferences(pod)
+	lease.Spec.HolderIdentity = ptr.To(pod.Name)
+	lease.Spec.AcquireTime = ptr.To(metav1.NewMicroTime(time.Now()))
+	if err := cl.Update(ctx, lease); err != nil {
+		if apierrors.IsConflict(err) {
+			// Another replica took over the lease in the meanwhile.
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to update lease %s/%s: %w", lease.Namespace, lease.Name, err)
+	}
+	return true, nil
+}
+
+func addReplicaIndexLabel(ctx context.Context, cl client.Client, pod *corev1.Pod, index int) error {
+	labels := pod.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ReplicaIndexKey] = strconv.Itoa(index)
+	pod.SetLabels(labels)
+
+	if err := cl.Update(ctx, pod); err != nil {
+		return fmt.Errorf("unable to label pod %s/%s with the replica index: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+func forgePodOwnerReferences(pod *corev1.Pod) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}}
+}
@@ -24,6 +24,10 @@ type Msg struct {
 	ClusterID string    `json:"clusterID"`
 	MsgType   MsgTypes  `json:"msgType"`
 	TimeStamp time.Time `json:"timeStamp"`
+	// Size is the size of the IP packet carrying a PMTUPROBE, which is echoed back in the PMTUACK.
+	Size int `json:"size,omitempty"`
+	// Padding pads a PMTUPROBE to the size being probed.
+	Padding string `json:"padding,omitempty"`
 }
 
 func (msg Msg) String() string {
@@ -41,7 +45,12 @@ const (
 	PING MsgTypes = "PING"
 	// PONG is the type of a pong message.
 	PONG MsgTypes = "PONG"
+	// PMTUPROBE is the type of a message probing the path MTU.
+	PMTUPROBE MsgTypes = "PMTU_PROBE"
+	// PMTUACK is the type of a message acknowledging a PMTUPROBE.
+	PMTUACK MsgTypes = "PMTU_ACK"
 )
 
 // UpdateFunc is a function called when a Receiver gets a PONG or when a connection is declared failed.
-type UpdateFunc func(connected bool, latency time.Duration, time time.Time) error
+// The quality contains the link-quality measurements computed over the most recent pings.
+type UpdateFunc func(connected bool, latency time.Duration, quality *LinkQuality, time time.Time) error
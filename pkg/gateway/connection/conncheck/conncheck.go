@@ -108,9 +108,12 @@ func (c *ConnChecker) RunSender(clusterID string) {
 	klog.Infof("conncheck sender %q starting against %q", clusterID, sender.raddr.IP.String())
 
 	if err := wait.PollUntilContextCancel(sender.Ctx, c.opts.PingInterval, false, func(_ context.Context) (done bool, err error) {
-		err = c.senders[clusterID].SendPing()
-		if err != nil {
+		// The PING is recorded before being sent, so that a fast PONG always finds it in the window.
+		timestamp := time.Now()
+		c.receiver.RecordPing(clusterID, timestamp)
+		if err := sender.SendPing(timestamp); err != nil {
 			klog.Warningf("failed to send ping: %s", err)
+			c.receiver.ForgetPing(clusterID, timestamp)
 		}
 		return false, nil
 	}); err != nil {
@@ -120,6 +123,62 @@ func (c *ConnChecker) RunSender(clusterID string) {
 	klog.Infof("conncheck sender %s stopped", clusterID)
 }
 
+// RunPathMTUDiscovery periodically discovers the path MTU towards the peer of the given sender, until the sender is stopped.
+// The onDiscovery function, if not nil, is called every time the path MTU is discovered.
+func (c *ConnChecker) RunPathMTUDiscovery(clusterID string, onDiscovery func(mtu int)) {
+	c.sm.RLock()
+	sender, ok := c.senders[clusterID]
+	c.sm.RUnlock()
+	if !ok {
+		klog.Errorf("conncheck path MTU discovery %s doesn't start: sender not found", clusterID)
+		return
+	}
+
+	klog.Infof("conncheck path MTU discovery %q starting against %q", clusterID, sender.raddr.IP.String())
+
+	for {
+		interval := c.opts.PMTUDiscoveryInterval
+		mtu, err := discoverPathMTU(clusterID, &sender.raddr, c.opts.PingInterval, c.opts.TunnelMTU)
+		switch {
+		case err != nil:
+			// The peer may be not reachable yet, hence retry as soon as the connection is expected to be established.
+			klog.V(4).Infof("conncheck path MTU discovery %s failed: %s", clusterID, err)
+			interval = c.opts.PingInterval * time.Duration(c.opts.PingLossThreshold)
+		default:
+			klog.V(4).Infof("conncheck path MTU discovery %s: discovered a path MTU of %d bytes", clusterID, mtu)
+			c.receiver.setPathMTU(clusterID, mtu)
+			if onDiscovery != nil {
+				onDiscovery(mtu)
+			}
+		}
+
+		select {
+		case <-sender.Ctx.Done():
+			klog.Infof("conncheck path MTU discovery %s stopped", clusterID)
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// DiscoverPathMTU synchronously discovers the path MTU towards the peer of the given sender.
+// It must not be called concurrently with the periodic discovery, e.g., only from the RunPathMTUDiscovery callback.
+func (c *ConnChecker) DiscoverPathMTU(clusterID string) (int, error) {
+	c.sm.RLock()
+	sender, ok := c.senders[clusterID]
+	c.sm.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("sender %s not found", clusterID)
+	}
+
+	mtu, err := discoverPathMTU(clusterID, &sender.raddr, c.opts.PingInterval, c.opts.TunnelMTU)
+	if err != nil {
+		return 0, err
+	}
+	c.receiver.setPathMTU(clusterID, mtu)
+	return mtu, nil
+}
+
 // DelAndStopSender stops and deletes a sender. If sender has been already stoped and deleted is a no-op function.
 func (c *ConnChecker) DelAndStopSender(clusterID string) {
 	c.sm.Lock()
@@ -135,6 +194,7 @@ func (c *ConnChecker) DelAndStopSender(clusterID string) {
 
 	delete(c.runningSenders, clusterID)
 	delete(c.receiver.peers, clusterID)
+	deleteMetrics(clusterID)
 }
 
 // GetLatency returns the latency with clusterID.
@@ -147,6 +207,17 @@ func (c *ConnChecker) GetLatency(clusterID string) (time.Duration, error) {
 	return 0, fmt.Errorf("sender %s not found", clusterID)
 }
 
+// GetLinkQuality returns the link-quality measurements with clusterID.
+func (c *ConnChecker) GetLinkQuality(clusterID string) (*LinkQuality, error) {
+	c.receiver.m.RLock()
+	defer c.receiver.m.RUnlock()
+	if peer, ok := c.receiver.peers[clusterID]; ok {
+		quality := peer.linkQuality(time.Now(), c.opts.LossTimeout())
+		return &quality, nil
+	}
+	return nil, fmt.Errorf("sender %s not found", clusterID)
+}
+
 // GetConnected returns the connection status with clusterID.
 func (c *ConnChecker) GetConnected(clusterID string) (bool, error) {
 	c.receiver.m.RLock()
@@ -0,0 +1,69 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricsLabels = []string{"cluster_id"}
+
+	// MetricsPeerLatencyHistogram is the metric that tracks the distribution of the latency towards a given peer.
+	MetricsPeerLatencyHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "liqo_peer_latency_seconds",
+		Help:    "Distribution of the round-trip latency of a given peer in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 13),
+	}, metricsLabels)
+
+	// MetricsPeerJitter is the metric that exposes the jitter towards a given peer.
+	MetricsPeerJitter = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "liqo_peer_jitter_us",
+		Help: "Mean variation of the round-trip latency of a given peer in microseconds, over the most recent pings.",
+	}, metricsLabels)
+
+	// MetricsPeerPacketLoss is the metric that exposes the packet loss towards a given peer.
+	MetricsPeerPacketLoss = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "liqo_peer_packet_loss_percentage",
+		Help: "Percentage of pings lost towards a given peer, over the most recent pings.",
+	}, metricsLabels)
+)
+
+// RegisterMetrics registers the link-quality metrics to the given registerer.
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{MetricsPeerLatencyHistogram, MetricsPeerJitter, MetricsPeerPacketLoss} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func observeLatency(clusterID string, latency time.Duration) {
+	MetricsPeerLatencyHistogram.WithLabelValues(clusterID).Observe(latency.Seconds())
+}
+
+func observeQuality(clusterID string, quality *LinkQuality) {
+	MetricsPeerJitter.WithLabelValues(clusterID).Set(float64(quality.Jitter.Microseconds()))
+	MetricsPeerPacketLoss.WithLabelValues(clusterID).Set(quality.PacketLoss)
+}
+
+func deleteMetrics(clusterID string) {
+	MetricsPeerLatencyHistogram.DeleteLabelValues(clusterID)
+	MetricsPeerJitter.DeleteLabelValues(clusterID)
+	MetricsPeerPacketLoss.DeleteLabelValues(clusterID)
+}
@@ -16,6 +16,10 @@ package conncheck
 
 import "time"
 
+// DefaultPingLossTimeoutIntervals is the default number of ping intervals after which an unanswered ping is considered as lost,
+// so that the answers delayed by a latency spike or by queuing are not accounted as losses.
+const DefaultPingLossTimeoutIntervals = 3
+
 // Options contains the options for the wireguard interface.
 type Options struct {
 	// PingPort is the port used for the ping check.
@@ -26,9 +30,27 @@ type Options struct {
 	PingLossThreshold uint
 	// PingInterval is the interval at which the ping is sent.
 	PingInterval time.Duration
+	// PingLossTimeout is the time after which an unanswered ping is considered as lost by the link-quality measurements.
+	// If zero, it defaults to DefaultPingLossTimeoutIntervals ping intervals.
+	PingLossTimeout time.Duration
+	// PingStatsWindow is the number of most recent pings the link-quality measurements are computed on.
+	PingStatsWindow uint
+	// PMTUDiscoveryInterval is the interval at which the path MTU is discovered. Zero disables the discovery.
+	PMTUDiscoveryInterval time.Duration
+	// TunnelMTU is the MTU configured for the tunnel, which bounds the path MTU discovery.
+	// If zero, the discovery is bounded by the MTU of the route towards the peer.
+	TunnelMTU int
 }
 
 // NewOptions returns a new Options struct.
 func NewOptions() *Options {
 	return &Options{}
 }
+
+// LossTimeout returns the time after which an unanswered ping is considered as lost.
+func (o *Options) LossTimeout() time.Duration {
+	if o.PingLossTimeout > 0 {
+		return o.PingLossTimeout
+	}
+	return DefaultPingLossTimeoutIntervals * o.PingInterval
+}
@@ -0,0 +1,249 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// pmtuMinSizeIPv4 is the minimum MTU every IPv4 host is required to support.
+	pmtuMinSizeIPv4 = 576
+	// pmtuMinSizeIPv6 is the minimum MTU every IPv6 link is required to support.
+	pmtuMinSizeIPv6 = 1280
+	// pmtuOverheadIPv4 is the size of the IPv4 and UDP headers carrying a probe.
+	pmtuOverheadIPv4 = 20 + 8
+	// pmtuOverheadIPv6 is the size of the IPv6 and UDP headers carrying a probe.
+	pmtuOverheadIPv6 = 40 + 8
+	// pmtuProbeAttempts is the number of probes sent for each size before considering it too large.
+	pmtuProbeAttempts = 2
+	// maxMsgSize is the maximum size of a message, which is bounded by the maximum UDP payload.
+	maxMsgSize = 65535
+)
+
+// pmtuProber probes the path MTU towards a peer, sending PMTUPROBE messages of the given size with the DF bit set.
+type pmtuProber struct {
+	clusterID string
+	conn      *net.UDPConn
+	overhead  int
+	timeout   time.Duration
+	buff      []byte
+}
+
+// newPMTUProber creates a new pmtuProber, waiting for each PMTUACK at most for the given timeout.
+func newPMTUProber(clusterID string, raddr *net.UDPAddr, timeout time.Duration) (*pmtuProber, error) {
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", raddr, err)
+	}
+
+	overhead, level, opt, value := pmtuOverheadIPv6, unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_PROBE
+	if raddr.IP.To4() != nil {
+		overhead, level, opt, value = pmtuOverheadIPv4, unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE
+	}
+
+	// Set the DF bit, ignoring the path MTU cached by the kernel, so that probes larger than the path MTU are dropped.
+	if err := setSockOpt(conn, level, opt, value); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set the DF bit: %w", err)
+	}
+
+	return &pmtuProber{
+		clusterID: clusterID,
+		conn:      conn,
+		overhead:  overhead,
+		timeout:   timeout,
+		buff:      make([]byte, maxMsgSize),
+	}, nil
+}
+
+// minSize returns the minimum path MTU, which is assumed to be always supported.
+func (p *pmtuProber) minSize() int {
+	if p.overhead == pmtuOverheadIPv4 {
+		return pmtuMinSizeIPv4
+	}
+	return pmtuMinSizeIPv6
+}
+
+// probe returns whether an IP packet of the given size reaches the peer.
+func (p *pmtuProber) probe(size int) (bool, error) {
+	b, err := forgeProbe(p.clusterID, size, p.overhead)
+	if err != nil {
+		return false, err
+	}
+
+	for range pmtuProbeAttempts {
+		if _, err := p.conn.Write(b); err != nil {
+			if errors.Is(err, syscall.EMSGSIZE) {
+				// The probe exceeds the MTU of the local interface.
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to write to %s: %w", p.conn.RemoteAddr(), err)
+		}
+
+		acked, err := p.waitAck(size)
+		if err != nil || acked {
+			return acked, err
+		}
+	}
+	return false, nil
+}
+
+// waitAck waits for the PMTUACK of the probe of the given size.
+func (p *pmtuProber) waitAck(size int) (bool, error) {
+	if err := p.conn.SetReadDeadline(time.Now().Add(p.timeout)); err != nil {
+		return false, fmt.Errorf("failed to set the read deadline: %w", err)
+	}
+
+	for {
+		n, err := p.conn.Read(p.buff)
+		var netErr net.Error
+		switch {
+		case errors.As(err, &netErr) && netErr.Timeout(), errors.Is(err, syscall.ECONNREFUSED):
+			return false, nil
+		case err != nil:
+			return false, fmt.Errorf("failed to read from %s: %w", p.conn.RemoteAddr(), err)
+		}
+
+		msg := Msg{}
+		if err := json.Unmarshal(p.buff[:n], &msg); err != nil {
+			continue
+		}
+		// Acknowledgments of previous sizes, which arrived late, are discarded.
+		if msg.MsgType == PMTUACK && msg.Size == size {
+			return true, nil
+		}
+	}
+}
+
+// Close closes the prober socket.
+func (p *pmtuProber) Close() error {
+	return p.conn.Close()
+}
+
+// forgeProbe forges a PMTUPROBE message, padded so that the IP packet carrying it is exactly of the given size.
+func forgeProbe(clusterID string, size, overhead int) ([]byte, error) {
+	msg := Msg{ClusterID: clusterID, MsgType: PMTUPROBE, TimeStamp: time.Now(), Size: size}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal msg: %w", err)
+	}
+
+	// The padding field is marshaled last, adding its key and its value to the message.
+	padding := size - overhead - len(b) - len(`,"padding":""`)
+	if padding < 0 {
+		return nil, fmt.Errorf("probe size %d is too small", size)
+	}
+	msg.Padding = strings.Repeat("0", padding)
+	return json.Marshal(msg)
+}
+
+// searchPathMTU returns the largest size between minSize and maxSize for which the probe succeeds,
+// by means of a binary search. It returns an error if not even the probe of minSize succeeds.
+func searchPathMTU(minSize, maxSize int, probe func(size int) (bool, error)) (int, error) {
+	minSize = min(minSize, maxSize)
+	ok, err := probe(minSize)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("no answer to the probes of %d bytes", minSize)
+	}
+
+	low, high := minSize, maxSize
+	for low < high {
+		mid := (low + high + 1) / 2
+		ok, err := probe(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
+
+// discoverPathMTU discovers the path MTU towards the given address, which is bounded by the given size, if positive,
+// or by the MTU of the route towards the peer otherwise. In both cases, probes exceeding the MTU of the outgoing
+// interface are considered too large.
+// As the peer is reached through the tunnel, the DF bit applies only to the inner packets, while the encapsulating ones
+// may be fragmented along the path: the probes detect the paths dropping the fragments (e.g., cloud load balancers),
+// but not the ones fragmenting the tunnel packets, which are delivered (at the cost of the reassembly) anyway.
+func discoverPathMTU(clusterID string, raddr *net.UDPAddr, timeout time.Duration, maxSize int) (int, error) {
+	if maxSize <= 0 {
+		var err error
+		if maxSize, err = routeMTU(raddr.IP); err != nil {
+			return 0, err
+		}
+	}
+
+	prober, err := newPMTUProber(clusterID, raddr, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer prober.Close()
+
+	return searchPathMTU(prober.minSize(), maxSize, prober.probe)
+}
+
+// routeMTU returns the MTU of the route towards the given IP address.
+func routeMTU(ip net.IP) (int, error) {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the route towards %s: %w", ip, err)
+	}
+	if len(routes) == 0 {
+		return 0, fmt.Errorf("no route towards %s", ip)
+	}
+
+	link, err := netlink.LinkByIndex(routes[0].LinkIndex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the interface towards %s: %w", ip, err)
+	}
+
+	mtu := link.Attrs().MTU
+	if routes[0].MTU > 0 {
+		mtu = min(mtu, routes[0].MTU)
+	}
+	return min(mtu, maxMsgSize), nil
+}
+
+// setSockOpt sets an integer option on the socket underlying the given connection.
+func setSockOpt(conn *net.UDPConn, level, opt, value int) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), level, opt, value)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
@@ -0,0 +1,110 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Path MTU discovery", func() {
+	Context("forging the probes", func() {
+		It("should pad the probes to the given size", func() {
+			for _, size := range []int{576, 1280, 1420} {
+				b, err := forgeProbe("cluster", size, pmtuOverheadIPv4)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(b).To(HaveLen(size - pmtuOverheadIPv4))
+
+				msg := Msg{}
+				Expect(json.Unmarshal(b, &msg)).To(Succeed())
+				Expect(msg.MsgType).To(Equal(PMTUPROBE))
+				Expect(msg.Size).To(Equal(size))
+			}
+		})
+
+		It("should fail if the size is too small", func() {
+			_, err := forgeProbe("cluster", 64, pmtuOverheadIPv6)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("searching the path MTU", func() {
+		var probed []int
+
+		probeUpTo := func(pmtu int) func(size int) (bool, error) {
+			return func(size int) (bool, error) {
+				probed = append(probed, size)
+				return size <= pmtu, nil
+			}
+		}
+
+		BeforeEach(func() { probed = nil })
+
+		It("should find the largest size reaching the peer", func() {
+			Expect(searchPathMTU(576, 1340, probeUpTo(1280))).To(Equal(1280))
+			Expect(len(probed)).To(BeNumerically("<=", 12))
+		})
+
+		It("should return the maximum size if all probes reach the peer", func() {
+			Expect(searchPathMTU(576, 1340, probeUpTo(9000))).To(Equal(1340))
+		})
+
+		It("should return the minimum size if larger probes do not reach the peer", func() {
+			Expect(searchPathMTU(576, 1340, probeUpTo(576))).To(Equal(576))
+		})
+
+		It("should fail if not even the minimum size reaches the peer", func() {
+			_, err := searchPathMTU(576, 1340, probeUpTo(0))
+			Expect(err).To(HaveOccurred())
+			Expect(probed).To(Equal([]int{576}))
+		})
+	})
+
+	Context("probing a receiver", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			raddr  *net.UDPAddr
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(conn.Close)
+			raddr = conn.LocalAddr().(*net.UDPAddr)
+
+			receiver := NewReceiver(conn, &Options{PingBufferSize: 1024})
+			go receiver.Run(ctx)
+		})
+
+		AfterEach(func() { cancel() })
+
+		It("should acknowledge the probes larger than the ping buffer", func() {
+			prober, err := newPMTUProber("cluster", raddr, time.Second)
+			Expect(err).ToNot(HaveOccurred())
+			defer prober.Close()
+
+			Expect(prober.minSize()).To(Equal(pmtuMinSizeIPv4))
+			Expect(prober.probe(1400)).To(BeTrue())
+			Expect(prober.probe(9000)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,123 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"slices"
+	"time"
+)
+
+// LinkQuality contains the link-quality measurements computed over the most recent pings.
+type LinkQuality struct {
+	// Samples is the number of pings the measurements refer to, i.e., the ones either answered or lost.
+	Samples int
+	// PacketLoss is the percentage of lost pings.
+	PacketLoss float64
+	// Jitter is the mean variation of the latency between consecutive pings.
+	Jitter time.Duration
+	// LatencyP50 is the 50th percentile of the latency.
+	LatencyP50 time.Duration
+	// LatencyP90 is the 90th percentile of the latency.
+	LatencyP90 time.Duration
+	// LatencyP99 is the 99th percentile of the latency.
+	LatencyP99 time.Duration
+	// PathMTU is the discovered path MTU towards the peer, or zero if not yet discovered.
+	PathMTU int
+}
+
+// ping is a PING sent to a peer, identified by its timestamp.
+type ping struct {
+	timestamp time.Time
+	latency   time.Duration
+	answered  bool
+}
+
+// pingWindow is a sliding window over the most recent pings sent to a peer.
+type pingWindow struct {
+	pings []ping
+	size  int
+}
+
+func newPingWindow(size uint) *pingWindow {
+	return &pingWindow{size: max(int(size), 1)}
+}
+
+// sent records a PING sent at the given time, discarding the oldest one if the window is full.
+func (w *pingWindow) sent(timestamp time.Time) {
+	if len(w.pings) == w.size {
+		w.pings = slices.Delete(w.pings, 0, 1)
+	}
+	w.pings = append(w.pings, ping{timestamp: timestamp})
+}
+
+// discard removes the PING sent at the given time, e.g., because it could not be actually sent.
+func (w *pingWindow) discard(timestamp time.Time) {
+	w.pings = slices.DeleteFunc(w.pings, func(p ping) bool { return p.timestamp.Equal(timestamp) })
+}
+
+// answered records the PONG of the PING sent at the given time. PONGs of pings not in the window are ignored.
+func (w *pingWindow) answered(timestamp time.Time, latency time.Duration) {
+	for i := range w.pings {
+		if w.pings[i].timestamp.Equal(timestamp) {
+			w.pings[i].answered, w.pings[i].latency = true, latency
+			return
+		}
+	}
+}
+
+// quality computes the link quality at the given time. Pings not answered within the given timeout are considered lost,
+// while more recent ones are not considered yet.
+func (w *pingWindow) quality(now time.Time, lossTimeout time.Duration) LinkQuality {
+	var quality LinkQuality
+	var lost int
+	var latencies []time.Duration
+	var deviations time.Duration
+
+	for i := range w.pings {
+		switch {
+		case w.pings[i].answered:
+			if len(latencies) > 0 {
+				deviations += (w.pings[i].latency - latencies[len(latencies)-1]).Abs()
+			}
+			latencies = append(latencies, w.pings[i].latency)
+		case now.Sub(w.pings[i].timestamp) > lossTimeout:
+			lost++
+		}
+	}
+
+	quality.Samples = len(latencies) + lost
+	if quality.Samples == 0 {
+		return quality
+	}
+	quality.PacketLoss = float64(lost) * 100 / float64(quality.Samples)
+	if len(latencies) > 1 {
+		quality.Jitter = deviations / time.Duration(len(latencies)-1)
+	}
+
+	slices.Sort(latencies)
+	quality.LatencyP50 = percentile(latencies, 50)
+	quality.LatencyP90 = percentile(latencies, 90)
+	quality.LatencyP99 = percentile(latencies, 99)
+	return quality
+}
+
+// percentile returns the given percentile of the sorted values, according to the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
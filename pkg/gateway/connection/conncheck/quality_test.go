@@ -0,0 +1,120 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link quality", func() {
+	const (
+		interval    = time.Second
+		lossTimeout = time.Second
+	)
+
+	var (
+		window *pingWindow
+		start  time.Time
+	)
+
+	BeforeEach(func() {
+		window = newPingWindow(10)
+		start = time.Now()
+	})
+
+	// send records the given number of pings, answered with the given latencies (zero means lost).
+	send := func(latencies ...time.Duration) {
+		for i, latency := range latencies {
+			timestamp := start.Add(time.Duration(i) * interval)
+			window.sent(timestamp)
+			if latency != 0 {
+				window.answered(timestamp, latency)
+			}
+		}
+	}
+
+	It("should report no samples if no ping has been sent", func() {
+		Expect(window.quality(start, lossTimeout)).To(Equal(LinkQuality{}))
+	})
+
+	It("should compute the packet loss", func() {
+		send(10*time.Millisecond, 0, 10*time.Millisecond, 0)
+		quality := window.quality(start.Add(10*interval), lossTimeout)
+		Expect(quality.Samples).To(Equal(4))
+		Expect(quality.PacketLoss).To(BeNumerically("==", 50))
+	})
+
+	It("should not consider as lost the pings not yet expired", func() {
+		send(10*time.Millisecond, 0)
+		quality := window.quality(start.Add(interval), lossTimeout)
+		Expect(quality.Samples).To(Equal(1))
+		Expect(quality.PacketLoss).To(BeZero())
+	})
+
+	It("should not consider the discarded pings", func() {
+		send(10*time.Millisecond, 0)
+		window.discard(start.Add(interval))
+		quality := window.quality(start.Add(10*interval), lossTimeout)
+		Expect(quality.Samples).To(Equal(1))
+		Expect(quality.PacketLoss).To(BeZero())
+	})
+
+	It("should compute the jitter", func() {
+		send(10*time.Millisecond, 20*time.Millisecond, 10*time.Millisecond, 40*time.Millisecond)
+		quality := window.quality(start.Add(10*interval), lossTimeout)
+		Expect(quality.Jitter).To(Equal(50 * time.Millisecond / 3))
+	})
+
+	It("should compute the latency percentiles", func() {
+		var latencies []time.Duration
+		for i := 10; i > 0; i-- {
+			latencies = append(latencies, time.Duration(i)*time.Millisecond)
+		}
+		send(latencies...)
+		quality := window.quality(start.Add(20*interval), lossTimeout)
+		Expect(quality.LatencyP50).To(Equal(5 * time.Millisecond))
+		Expect(quality.LatencyP90).To(Equal(9 * time.Millisecond))
+		Expect(quality.LatencyP99).To(Equal(10 * time.Millisecond))
+	})
+
+	It("should consider only the most recent pings", func() {
+		send(0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+		quality := window.quality(start.Add(20*interval), lossTimeout)
+		Expect(quality.Samples).To(Equal(10))
+		Expect(quality.PacketLoss).To(BeZero())
+	})
+
+	It("should ignore the answers to unknown pings", func() {
+		send(0)
+		window.answered(start.Add(-interval), time.Millisecond)
+		quality := window.quality(start.Add(10*interval), lossTimeout)
+		Expect(quality.PacketLoss).To(BeNumerically("==", 100))
+	})
+})
+
+var _ = Describe("Loss timeout", func() {
+	It("should default to a multiple of the ping interval", func() {
+		opts := &Options{PingInterval: 2 * time.Second}
+		Expect(opts.LossTimeout()).To(Equal(DefaultPingLossTimeoutIntervals * 2 * time.Second))
+	})
+
+	It("should use the configured timeout, if any", func() {
+		opts := &Options{PingInterval: 2 * time.Second, PingLossTimeout: 10 * time.Second}
+		Expect(opts.LossTimeout()).To(Equal(10 * time.Second))
+	})
+})
@@ -33,6 +33,17 @@ type Peer struct {
 	// lastReceivedTimestamp is the timestamp when the last received PING has been sent.
 	lastReceivedTimestamp time.Time
 	updateCallback        UpdateFunc
+	// window contains the most recent pings, used to compute the link quality.
+	window *pingWindow
+	// pathMTU is the discovered path MTU towards the peer, or zero if not yet discovered.
+	pathMTU int
+}
+
+// linkQuality returns the link-quality measurements towards the peer.
+func (p *Peer) linkQuality(now time.Time, lossTimeout time.Duration) LinkQuality {
+	quality := p.window.quality(now, lossTimeout)
+	quality.PathMTU = p.pathMTU
+	return quality
 }
 
 // Receiver is a receiver for conncheck messages.
@@ -48,9 +59,10 @@ type Receiver struct {
 func NewReceiver(conn *net.UDPConn, opts *Options) *Receiver {
 	return &Receiver{
 		peers: make(map[string]*Peer),
-		buff:  make([]byte, opts.PingBufferSize),
-		conn:  conn,
-		opts:  opts,
+		// The buffer must fit the largest PMTUPROBE, regardless of the configured size.
+		buff: make([]byte, max(opts.PingBufferSize, maxMsgSize)),
+		conn: conn,
+		opts: opts,
 	}
 }
 
@@ -69,21 +81,43 @@ func (r *Receiver) SendPong(raddr *net.UDPAddr, msg *Msg) error {
 	return nil
 }
 
+// SendPMTUAck sends a PMTUACK message to the given address, acknowledging the given PMTUPROBE.
+func (r *Receiver) SendPMTUAck(raddr *net.UDPAddr, msg *Msg) error {
+	msg.MsgType = PMTUACK
+	msg.Padding = ""
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal msg: %w", err)
+	}
+	_, err = r.conn.WriteToUDP(b, raddr)
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", raddr.String(), err)
+	}
+	klog.V(8).Infof("conncheck receiver: sent a PMTU_ACK -> %s", msg)
+	return nil
+}
+
 // ReceivePong receives a PONG message.
 func (r *Receiver) ReceivePong(msg *Msg) error {
 	r.m.Lock()
 	defer r.m.Unlock()
 	if peer, ok := r.peers[msg.ClusterID]; ok {
+		now := time.Now()
+		// Out-of-order PONGs still contribute to the link quality, as the corresponding pings are not lost.
+		peer.window.answered(msg.TimeStamp, now.Sub(msg.TimeStamp))
 		if msg.TimeStamp.Before(peer.lastReceivedTimestamp) {
 			klog.V(8).Infof("dropped a PONG message from %s because out-of-order", msg.ClusterID)
 			return nil
 		}
-		now := time.Now()
 		peer.lastReceivedTimestamp = msg.TimeStamp
 		peer.latency = now.Sub(msg.TimeStamp)
 		peer.connected = true
 
-		err := peer.updateCallback(true, peer.latency, now)
+		quality := peer.linkQuality(now, r.opts.LossTimeout())
+		observeLatency(msg.ClusterID, peer.latency)
+		observeQuality(msg.ClusterID, &quality)
+
+		err := peer.updateCallback(true, peer.latency, &quality, now)
 		if err != nil {
 			return fmt.Errorf("failed to update peer %s: %w", msg.ClusterID, err)
 		}
@@ -101,10 +135,38 @@ func (r *Receiver) InitPeer(clusterID string, updateCallback UpdateFunc) error {
 		latency:               0,
 		lastReceivedTimestamp: time.Now(),
 		updateCallback:        updateCallback,
+		window:                newPingWindow(r.opts.PingStatsWindow),
 	}
 	return nil
 }
 
+// RecordPing records a PING sent to the given peer at the given time, to compute the link quality.
+func (r *Receiver) RecordPing(clusterID string, timestamp time.Time) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if peer, ok := r.peers[clusterID]; ok {
+		peer.window.sent(timestamp)
+	}
+}
+
+// ForgetPing forgets a PING previously recorded for the given peer, as it could not be sent.
+func (r *Receiver) ForgetPing(clusterID string, timestamp time.Time) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if peer, ok := r.peers[clusterID]; ok {
+		peer.window.discard(timestamp)
+	}
+}
+
+// setPathMTU sets the discovered path MTU towards the given peer.
+func (r *Receiver) setPathMTU(clusterID string, mtu int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if peer, ok := r.peers[clusterID]; ok {
+		peer.pathMTU = mtu
+	}
+}
+
 // Run starts the receiver.
 func (r *Receiver) Run(ctx context.Context) {
 	klog.Infof("conncheck receiver: started")
@@ -128,6 +190,9 @@ func (r *Receiver) Run(ctx context.Context) {
 		case PONG:
 			klog.V(8).Infof("conncheck receiver: received a PONG from %s  -> %s", raddr, msgr)
 			err = r.ReceivePong(msgr)
+		case PMTUPROBE:
+			klog.V(8).Infof("conncheck receiver: received a PMTU_PROBE of %d bytes from %s", msgr.Size, raddr)
+			err = r.SendPMTUAck(raddr, msgr)
 		}
 		if err != nil {
 			klog.Errorf("conncheck receiver: %v", err)
@@ -154,7 +219,9 @@ func (r *Receiver) RunDisconnectObserver(ctx context.Context) {
 				klog.V(8).Infof("conncheck receiver: %s unreachable", id)
 				peer.connected = false
 				peer.latency = 0
-				err := peer.updateCallback(false, 0, time.Time{})
+				quality := peer.linkQuality(time.Now(), r.opts.LossTimeout())
+				observeQuality(id, &quality)
+				err := peer.updateCallback(false, 0, &quality, time.Time{})
 				if err != nil {
 					klog.Errorf("conncheck receiver: failed to update peer %s: %s", peer.lastReceivedTimestamp, err)
 				}
@@ -48,9 +48,9 @@ func NewSender(ctx context.Context, opts *Options, clusterID string, cancel func
 	}, nil
 }
 
-// SendPing sends a PING message to the given address.
-func (s *Sender) SendPing() error {
-	msgOut := Msg{ClusterID: s.clusterID, MsgType: PING, TimeStamp: time.Now()}
+// SendPing sends a PING message to the given address, carrying the given timestamp, which identifies the corresponding PONG.
+func (s *Sender) SendPing(timestamp time.Time) error {
+	msgOut := Msg{ClusterID: s.clusterID, MsgType: PING, TimeStamp: timestamp}
 	b, err := json.Marshal(msgOut)
 	if err != nil {
 		return fmt.Errorf("conncheck sender: failed to marshal msg: %w", err)
@@ -17,12 +17,15 @@ package connection
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -46,6 +49,9 @@ type ConnectionsReconciler struct {
 	Scheme         *runtime.Scheme
 	EventsRecorder record.EventRecorder
 	Options        *Options
+
+	// connected tracks whether the last check reported the connection as established.
+	connected atomic.Bool
 }
 
 // NewConnectionsReconciler returns a new PublicKeysReconciler.
@@ -78,7 +84,11 @@ func (r *ConnectionsReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 	klog.V(4).Infof("Reconciling connection %q", req.NamespacedName)
 
-	updateConnection := ForgeUpdateConnectionCallback(ctx, r.Client, r.Options, req)
+	forgedUpdateConnection := ForgeUpdateConnectionCallback(ctx, r.Client, r.Options, req)
+	updateConnection := func(connected bool, latency time.Duration, quality *conncheck.LinkQuality, timestamp time.Time) error {
+		r.connected.Store(connected)
+		return forgedUpdateConnection(connected, latency, quality, timestamp)
+	}
 
 	switch r.Options.PingEnabled {
 	case true:
@@ -98,8 +108,18 @@ func (r *ConnectionsReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 
 		go r.ConnChecker.RunSender(r.Options.GwOptions.RemoteClusterID)
+		if r.Options.ConnCheckOptions.PMTUDiscoveryInterval > 0 {
+			var onDiscovery func(mtu int)
+			if r.Options.PMTUAutoTune {
+				clusterID := r.Options.GwOptions.RemoteClusterID
+				onDiscovery = newMTUTuner(r.Options.ConnCheckOptions.TunnelMTU, func() (int, error) {
+					return r.ConnChecker.DiscoverPathMTU(clusterID)
+				}).onDiscovery
+			}
+			go r.ConnChecker.RunPathMTUDiscovery(r.Options.GwOptions.RemoteClusterID, onDiscovery)
+		}
 	case false:
-		if err := updateConnection(true, 0, time.Time{}); err != nil {
+		if err := updateConnection(true, 0, nil, time.Time{}); err != nil {
 			return ctrl.Result{}, fmt.Errorf("unable to update the connection status: %w", err)
 		}
 	}
@@ -107,6 +127,15 @@ func (r *ConnectionsReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// ReadyzCheck is a healthz.Checker reporting the gateway as ready only if the connection is established.
+// It is used in active-active mode, so that only the replicas with an established tunnel receive traffic.
+func (r *ConnectionsReconciler) ReadyzCheck(_ *http.Request) error {
+	if !r.connected.Load() {
+		return fmt.Errorf("connection not established")
+	}
+	return nil
+}
+
 // SetupWithManager register the ConnectionReconciler to the manager.
 func (r *ConnectionsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	filterByLabelsPredicate, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
@@ -124,11 +153,7 @@ func (r *ConnectionsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // ForgeUpdateConnectionCallback forges the UpdateConnectionStatus function.
 func ForgeUpdateConnectionCallback(ctx context.Context, cl client.Client, opts *Options, req ctrl.Request) conncheck.UpdateFunc {
-	return func(connected bool, latency time.Duration, timestamp time.Time) error {
-		connection := &networkingv1beta1.Connection{}
-		if err := cl.Get(ctx, req.NamespacedName, connection); err != nil {
-			return err
-		}
+	return func(connected bool, latency time.Duration, quality *conncheck.LinkQuality, timestamp time.Time) error {
 		var connStatusValue networkingv1beta1.ConnectionStatusValue
 		switch connected {
 		case true:
@@ -136,6 +161,13 @@ func ForgeUpdateConnectionCallback(ctx context.Context, cl client.Client, opts *
 		case false:
 			connStatusValue = networkingv1beta1.ConnectionError
 		}
-		return UpdateConnectionStatus(ctx, cl, opts, connection, connStatusValue, latency, timestamp)
+		// Retry on conflicts, as the replicas of an active-active gateway concurrently update the same connection.
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			connection := &networkingv1beta1.Connection{}
+			if err := cl.Get(ctx, req.NamespacedName, connection); err != nil {
+				return err
+			}
+			return UpdateConnectionStatus(ctx, cl, opts, connection, connStatusValue, latency, quality, timestamp)
+		})
 	}
 }
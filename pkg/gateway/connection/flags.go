@@ -15,9 +15,12 @@
 package connection
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"github.com/liqotech/liqo/pkg/gateway/connection/conncheck"
 )
 
 // FlagName is the type for the name of the flags.
@@ -40,6 +43,16 @@ const (
 	PingLossThresholdFlag FlagName = "ping-loss-threshold"
 	// PingIntervalFlag is the name of the flag used to set the ping interval.
 	PingIntervalFlag FlagName = "ping-interval"
+	// PingLossTimeoutFlag is the name of the flag used to set the time after which an unanswered ping is considered as lost.
+	PingLossTimeoutFlag FlagName = "ping-loss-timeout"
+	// PingStatsWindowFlag is the name of the flag used to set the number of pings the link-quality measurements are computed on.
+	PingStatsWindowFlag FlagName = "ping-stats-window"
+	// PMTUDiscoveryIntervalFlag is the name of the flag used to set the path MTU discovery interval.
+	PMTUDiscoveryIntervalFlag FlagName = "pmtu-discovery-interval"
+	// PMTUAutoTuneFlag is the name of the flag used to enable the tuning of the tunnel MTU according to the discovered path MTU.
+	PMTUAutoTuneFlag FlagName = "pmtu-auto-tune"
+	// TunnelMTUFlag is the name of the flag used to set the MTU configured for the tunnel interface.
+	TunnelMTUFlag FlagName = "tunnel-mtu"
 	// PingUpdateStatusIntervalFlag is the name of the flag used to set the ping update status interval.
 	PingUpdateStatusIntervalFlag FlagName = "ping-update-status-interval"
 )
@@ -58,6 +71,17 @@ func InitFlags(flagset *pflag.FlagSet, options *Options) {
 		"ping-loss-threshold is the number of lost packets after which the connection check is considered as failed.")
 	flagset.DurationVar(&options.ConnCheckOptions.PingInterval, PingIntervalFlag.String(), 2*time.Second,
 		"ping-interval is the interval between two connection checks")
+	flagset.DurationVar(&options.ConnCheckOptions.PingLossTimeout, PingLossTimeoutFlag.String(), 0,
+		fmt.Sprintf("ping-loss-timeout is the time after which an unanswered ping is considered as lost by the packet loss measurement "+
+			"(0 to use %d ping intervals)", conncheck.DefaultPingLossTimeoutIntervals))
+	flagset.UintVar(&options.ConnCheckOptions.PingStatsWindow, PingStatsWindowFlag.String(), 30,
+		"ping-stats-window is the number of most recent pings the packet loss, jitter and latency percentiles are computed on")
+	flagset.DurationVar(&options.ConnCheckOptions.PMTUDiscoveryInterval, PMTUDiscoveryIntervalFlag.String(), 10*time.Minute,
+		"pmtu-discovery-interval is the interval at which the path MTU towards the remote gateway is discovered (0 to disable)")
+	flagset.BoolVar(&options.PMTUAutoTune, PMTUAutoTuneFlag.String(), false,
+		"pmtu-auto-tune tunes the MTU of the tunnel interface according to the discovered path MTU, and reports it in the connection status")
+	flagset.IntVar(&options.ConnCheckOptions.TunnelMTU, TunnelMTUFlag.String(), 0,
+		"tunnel-mtu is the MTU configured for the tunnel interface, which bounds the path MTU discovery and tuning (0 to use the current one)")
 	flagset.DurationVar(&options.PingUpdateStatusInterval, PingUpdateStatusIntervalFlag.String(), 10*time.Second,
 		"ping-update-status-interval is the interval at which the status is updated")
 }
@@ -17,6 +17,7 @@ package connection
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,12 +25,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/connection/conncheck"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
 	timeutils "github.com/liqotech/liqo/pkg/utils/time"
 )
 
 // UpdateConnectionStatus updates the status of a connection.
 func UpdateConnectionStatus(ctx context.Context, cl client.Client, opts *Options, connection *networkingv1beta1.Connection,
-	value networkingv1beta1.ConnectionStatusValue, latency time.Duration, timestamp time.Time) error {
+	value networkingv1beta1.ConnectionStatusValue, latency time.Duration, quality *conncheck.LinkQuality, timestamp time.Time) error {
+	if opts.GwOptions.ConcurrencyMode == gateway.ConcurrencyModeActiveActive {
+		return updateConnectionReplicaStatus(ctx, cl, opts, connection, value, latency, quality, timestamp)
+	}
+
 	if connection.Status.Value != value ||
 		timestamp.Sub(connection.Status.Latency.Timestamp.Time) > opts.PingUpdateStatusInterval {
 		if connection.Status.Value != value {
@@ -40,6 +48,16 @@ func UpdateConnectionStatus(ctx context.Context, cl client.Client, opts *Options
 			Value:     timeutils.FormatLatency(latency),
 			Timestamp: metav1.NewTime(timestamp),
 		}
+		connection.Status.Quality = forgeConnectionQuality(quality)
+		if opts.PMTUAutoTune && quality != nil && quality.PathMTU > 0 {
+			// The MTU of the tunnel interface is reported, rather than the last discovered path MTU, as it is tuned only
+			// once the discoveries are consistent.
+			if mtu, err := tunnelMTU(); err != nil {
+				klog.Warningf("unable to get the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+			} else {
+				connection.Status.MTU = mtu
+			}
+		}
 		connection.Status.Value = value
 		if err := cl.Status().Update(ctx, connection); err != nil {
 			return fmt.Errorf("unable to update connection %q: %w",
@@ -48,3 +66,75 @@ func UpdateConnectionStatus(ctx context.Context, cl client.Client, opts *Options
 	}
 	return nil
 }
+
+// updateConnectionReplicaStatus updates the status of the tunnel terminated by the current replica of an active-active gateway,
+// and the overall status of the connection, which is established if at least one replica is connected.
+func updateConnectionReplicaStatus(ctx context.Context, cl client.Client, opts *Options, connection *networkingv1beta1.Connection,
+	value networkingv1beta1.ConnectionStatusValue, latency time.Duration, quality *conncheck.LinkQuality, timestamp time.Time) error {
+	index := slices.IndexFunc(connection.Status.Replicas, func(r networkingv1beta1.ConnectionReplicaStatus) bool {
+		return r.Index == opts.GwOptions.ReplicaIndex
+	})
+	if index < 0 {
+		connection.Status.Replicas = append(connection.Status.Replicas,
+			networkingv1beta1.ConnectionReplicaStatus{Index: opts.GwOptions.ReplicaIndex})
+		slices.SortFunc(connection.Status.Replicas, func(a, b networkingv1beta1.ConnectionReplicaStatus) int {
+			return a.Index - b.Index
+		})
+		index = slices.IndexFunc(connection.Status.Replicas, func(r networkingv1beta1.ConnectionReplicaStatus) bool {
+			return r.Index == opts.GwOptions.ReplicaIndex
+		})
+	}
+
+	replica := &connection.Status.Replicas[index]
+	if replica.Value == value && replica.PodName == opts.GwOptions.PodName &&
+		timestamp.Sub(replica.Latency.Timestamp.Time) <= opts.PingUpdateStatusInterval {
+		return nil
+	}
+
+	if replica.Value != value {
+		klog.Infof("changing connection %q status of replica %d to %q",
+			client.ObjectKeyFromObject(connection).String(), replica.Index, value)
+	}
+	replica.PodName = opts.GwOptions.PodName
+	replica.Value = value
+	replica.Latency = networkingv1beta1.ConnectionLatency{
+		Value:     timeutils.FormatLatency(latency),
+		Timestamp: metav1.NewTime(timestamp),
+	}
+	replica.Quality = forgeConnectionQuality(quality)
+
+	connection.Status.Value = networkingv1beta1.ConnectionError
+	for i := range connection.Status.Replicas {
+		if connection.Status.Replicas[i].Value == networkingv1beta1.Connected {
+			connection.Status.Value = networkingv1beta1.Connected
+			break
+		}
+	}
+	if value == networkingv1beta1.Connected || connection.Status.Value != networkingv1beta1.Connected {
+		connection.Status.Latency = replica.Latency
+		connection.Status.Quality = replica.Quality
+	}
+
+	if err := cl.Status().Update(ctx, connection); err != nil {
+		return fmt.Errorf("unable to update connection %q: %w",
+			client.ObjectKeyFromObject(connection).String(), err)
+	}
+	return nil
+}
+
+// forgeConnectionQuality forges the link-quality measurements to be included in the connection status.
+// It returns nil if no measurement is available (e.g., because the ping check is disabled).
+func forgeConnectionQuality(quality *conncheck.LinkQuality) *networkingv1beta1.ConnectionQuality {
+	if quality == nil || (quality.Samples == 0 && quality.PathMTU == 0) {
+		return nil
+	}
+	return &networkingv1beta1.ConnectionQuality{
+		Samples:    quality.Samples,
+		PacketLoss: fmt.Sprintf("%.1f%%", quality.PacketLoss),
+		Jitter:     timeutils.FormatLatency(quality.Jitter),
+		LatencyP50: timeutils.FormatLatency(quality.LatencyP50),
+		LatencyP90: timeutils.FormatLatency(quality.LatencyP90),
+		LatencyP99: timeutils.FormatLatency(quality.LatencyP99),
+		PathMTU:    quality.PathMTU,
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog/v2"
+
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+)
+
+// mtuLowerThreshold is the number of consecutive discoveries which must report a path MTU smaller than the MTU
+// of the tunnel interface before lowering it, so that transient losses of the probes are not mistaken for a smaller path MTU.
+const mtuLowerThreshold = 3
+
+// mtuTuner tunes the MTU of the tunnel interface according to the path MTU periodically discovered towards the peer.
+// The MTU is lowered only if the discoveries consistently report a smaller path MTU, and it is raised back up to the
+// configured one as soon as larger probes succeed again.
+type mtuTuner struct {
+	// configuredMTU is the MTU configured for the tunnel interface, which is never exceeded.
+	configuredMTU int
+
+	getMTU   func() (int, error)
+	setMTU   func(mtu int) error
+	discover func() (int, error)
+
+	// lowerStreak is the number of consecutive discoveries reporting a path MTU smaller than the current MTU.
+	lowerStreak int
+	// lowerCandidate is the largest path MTU reported by the current streak.
+	lowerCandidate int
+}
+
+// newMTUTuner returns a mtuTuner acting on the tunnel interface, re-discovering the path MTU through the given function.
+// If the configured MTU is not positive, the MTU of the tunnel interface at the first discovery is considered as configured.
+func newMTUTuner(configuredMTU int, discover func() (int, error)) *mtuTuner {
+	return &mtuTuner{
+		configuredMTU: configuredMTU,
+		getMTU:        tunnelMTU,
+		setMTU: func(mtu int) error {
+			link, err := netlink.LinkByName(tunnel.TunnelInterfaceName)
+			if err != nil {
+				return err
+			}
+			return netlink.LinkSetMTU(link, mtu)
+		},
+		discover: discover,
+	}
+}
+
+// onDiscovery tunes the MTU of the tunnel interface according to the discovered path MTU.
+func (t *mtuTuner) onDiscovery(pmtu int) {
+	current, err := t.getMTU()
+	if err != nil {
+		klog.Errorf("unable to get the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+		return
+	}
+	if t.configuredMTU <= 0 {
+		t.configuredMTU = current
+	}
+
+	if pmtu >= current {
+		t.lowerStreak, t.lowerCandidate = 0, 0
+		if current < t.configuredMTU {
+			// The probes are bounded by the current MTU: check whether larger packets get through again.
+			t.raise(current)
+		}
+		return
+	}
+
+	t.lowerStreak++
+	t.lowerCandidate = max(t.lowerCandidate, pmtu)
+	if t.lowerStreak < mtuLowerThreshold {
+		klog.V(4).Infof("Discovered a path MTU of %d bytes, smaller than the MTU %d of the tunnel interface %q (%d/%d)",
+			pmtu, current, tunnel.TunnelInterfaceName, t.lowerStreak, mtuLowerThreshold)
+		return
+	}
+
+	klog.Infof("Lowering the MTU of the tunnel interface %q from %d to the discovered path MTU %d",
+		tunnel.TunnelInterfaceName, current, t.lowerCandidate)
+	if err := t.setMTU(t.lowerCandidate); err != nil {
+		klog.Errorf("unable to set the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+		return
+	}
+	t.lowerStreak, t.lowerCandidate = 0, 0
+}
+
+// raise temporarily restores the configured MTU of the tunnel interface and re-discovers the path MTU, raising the MTU
+// up to the discovered one if larger than the current one, or restoring the current one otherwise.
+func (t *mtuTuner) raise(current int) {
+	if err := t.setMTU(t.configuredMTU); err != nil {
+		klog.Errorf("unable to set the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+		return
+	}
+
+	pmtu, err := t.discover()
+	if err != nil || pmtu <= current {
+		if err := t.setMTU(current); err != nil {
+			klog.Errorf("unable to set the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+		}
+		return
+	}
+
+	klog.Infof("Raising the MTU of the tunnel interface %q from %d to the discovered path MTU %d",
+		tunnel.TunnelInterfaceName, current, pmtu)
+	if pmtu < t.configuredMTU {
+		if err := t.setMTU(pmtu); err != nil {
+			klog.Errorf("unable to set the MTU of the tunnel interface %q: %v", tunnel.TunnelInterfaceName, err)
+		}
+	}
+}
+
+// tunnelMTU returns the current MTU of the tunnel interface.
+func tunnelMTU() (int, error) {
+	link, err := netlink.LinkByName(tunnel.TunnelInterfaceName)
+	if err != nil {
+		return 0, err
+	}
+	return link.Attrs().MTU, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MTU tuning", func() {
+	const configuredMTU = 1400
+
+	var (
+		tuner      *mtuTuner
+		currentMTU int
+		discovered []int
+	)
+
+	BeforeEach(func() {
+		currentMTU, discovered = configuredMTU, nil
+		tuner = &mtuTuner{
+			configuredMTU: configuredMTU,
+			getMTU:        func() (int, error) { return currentMTU, nil },
+			setMTU:        func(mtu int) error { currentMTU = mtu; return nil },
+			discover: func() (int, error) {
+				if len(discovered) == 0 {
+					return 0, errors.New("no answer")
+				}
+				// The probes are bounded by the MTU of the interface.
+				pmtu := min(discovered[0], currentMTU)
+				discovered = discovered[1:]
+				return pmtu, nil
+			},
+		}
+	})
+
+	It("should not lower the MTU after a single smaller discovery", func() {
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(configuredMTU))
+	})
+
+	It("should lower the MTU after consistent smaller discoveries", func() {
+		for range mtuLowerThreshold - 1 {
+			tuner.onDiscovery(1300)
+		}
+		tuner.onDiscovery(1200)
+		Expect(currentMTU).To(Equal(1300))
+	})
+
+	It("should reset the streak if a discovery matches the current MTU", func() {
+		for range mtuLowerThreshold - 1 {
+			tuner.onDiscovery(1300)
+		}
+		tuner.onDiscovery(configuredMTU)
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(configuredMTU))
+	})
+
+	It("should raise the MTU back if larger probes succeed", func() {
+		currentMTU = 1300
+		discovered = []int{configuredMTU}
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(configuredMTU))
+	})
+
+	It("should raise the MTU up to the discovered path MTU", func() {
+		currentMTU = 1300
+		discovered = []int{1350}
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(1350))
+	})
+
+	It("should restore the current MTU if larger probes still fail", func() {
+		currentMTU = 1300
+		discovered = []int{1300}
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(1300))
+	})
+
+	It("should restore the current MTU if the discovery fails", func() {
+		currentMTU = 1300
+		tuner.onDiscovery(1300)
+		Expect(currentMTU).To(Equal(1300))
+	})
+})
@@ -33,6 +33,8 @@ type Options struct {
 	PingEnabled bool
 	// PingUpdateStatusInterval is the interval at which the status is updated.
 	PingUpdateStatusInterval time.Duration
+	// PMTUAutoTune enables the tuning of the tunnel MTU according to the discovered path MTU.
+	PMTUAutoTune bool
 }
 
 // NewOptions returns a new Options struct.
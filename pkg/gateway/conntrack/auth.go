@@ -0,0 +1,130 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// authNonceSize is the size of the nonces exchanged to authenticate the replicas.
+	authNonceSize = 32
+	// authTimeout is the maximum duration of the authentication of the replicas.
+	authTimeout = 5 * time.Second
+
+	authKeyLabel      = "liqo-conntrack-sync"
+	authSenderLabel   = "sender"
+	authReceiverLabel = "receiver"
+)
+
+// KeyFromFile returns a function reading the secret shared by the replicas from the given file, and deriving the key
+// used to authenticate them. The file is read at every connection, so that the changes of the secret (e.g., due to a
+// key rotation) are eventually applied.
+func KeyFromFile(path string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		secret, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the conntrack synchronization secret: %w", err)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("the conntrack synchronization secret %q is empty", path)
+		}
+		return authMAC(secret, authKeyLabel), nil
+	}
+}
+
+// authMAC returns the HMAC-SHA256 of the given label and values.
+func authMAC(key []byte, label string, values ...[]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	for _, value := range values {
+		mac.Write(value)
+	}
+	return mac.Sum(nil)
+}
+
+// authenticateReceiver authenticates the standby replica connected to the active one, proving the knowledge of the key as well.
+// Both replicas contribute with a fresh nonce, hence the exchanged messages cannot be replayed.
+func authenticateReceiver(conn net.Conn, key []byte) error {
+	if err := conn.SetDeadline(time.Now().Add(authTimeout)); err != nil {
+		return err
+	}
+
+	receiverNonce := make([]byte, authNonceSize)
+	if _, err := io.ReadFull(conn, receiverNonce); err != nil {
+		return fmt.Errorf("unable to read the nonce: %w", err)
+	}
+	senderNonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(senderNonce, authMAC(key, authSenderLabel, receiverNonce, senderNonce)...)); err != nil {
+		return fmt.Errorf("unable to write the nonce: %w", err)
+	}
+
+	received := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(conn, received); err != nil {
+		return fmt.Errorf("unable to read the authentication code: %w", err)
+	}
+	if !hmac.Equal(received, authMAC(key, authReceiverLabel, receiverNonce, senderNonce)) {
+		return fmt.Errorf("invalid authentication code")
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
+// authenticateSender authenticates the active replica the standby one is connected to, proving the knowledge of the key as well.
+func authenticateSender(conn net.Conn, key []byte) error {
+	if err := conn.SetDeadline(time.Now().Add(authTimeout)); err != nil {
+		return err
+	}
+
+	receiverNonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(receiverNonce); err != nil {
+		return fmt.Errorf("unable to write the nonce: %w", err)
+	}
+
+	received := make([]byte, authNonceSize+sha256.Size)
+	if _, err := io.ReadFull(conn, received); err != nil {
+		return fmt.Errorf("unable to read the authentication code: %w", err)
+	}
+	senderNonce := received[:authNonceSize]
+	if !hmac.Equal(received[authNonceSize:], authMAC(key, authSenderLabel, receiverNonce, senderNonce)) {
+		return fmt.Errorf("invalid authentication code")
+	}
+
+	if _, err := conn.Write(authMAC(key, authReceiverLabel, receiverNonce, senderNonce)); err != nil {
+		return fmt.Errorf("unable to write the authentication code: %w", err)
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
+func newNonce() ([]byte, error) {
+	nonce := make([]byte, authNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate the nonce: %w", err)
+	}
+	return nonce, nil
+}
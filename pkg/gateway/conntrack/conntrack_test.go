@@ -0,0 +1,233 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Conntrack synchronization", func() {
+	var entry Flow
+
+	BeforeEach(func() {
+		// A connection from a remote pod towards a remapped IP, translated to the real IP of the local pod.
+		entry = Flow{
+			Family:   unix.AF_INET,
+			Protocol: unix.IPPROTO_TCP,
+			Orig: Tuple{
+				Src: netip.MustParseAddr("10.71.0.10"), Dst: netip.MustParseAddr("10.70.0.20"),
+				SrcPort: 43512, DstPort: 8080,
+			},
+			Reply: Tuple{
+				Src: netip.MustParseAddr("10.0.0.20"), Dst: netip.MustParseAddr("10.71.0.10"),
+				SrcPort: 8080, DstPort: 43512,
+			},
+			Status:   ipsSeenReply | ipsAssured,
+			Mark:     0x42,
+			Timeout:  431999,
+			TCPState: 3,
+		}
+	})
+
+	Describe("the netlink encoding", func() {
+		forgeMessage := func(entry *Flow) []byte {
+			msg := (&nl.Nfgenmsg{NfgenFamily: entry.Family, Version: nl.NFNETLINK_V0}).Serialize()
+			for _, attr := range forgeEntryAttributes(entry) {
+				msg = append(msg, attr.Serialize()...)
+			}
+			return msg
+		}
+
+		It("should preserve the entries", func() {
+			parsed, err := parseEntry(forgeMessage(&entry))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*parsed).To(Equal(entry))
+		})
+
+		It("should preserve the IPv6 entries", func() {
+			entry.Family = unix.AF_INET6
+			entry.Orig.Src, entry.Orig.Dst = netip.MustParseAddr("fd00::10"), netip.MustParseAddr("fd00::20")
+			entry.Reply.Src, entry.Reply.Dst = netip.MustParseAddr("fd00::20"), netip.MustParseAddr("fd00::10")
+
+			parsed, err := parseEntry(forgeMessage(&entry))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*parsed).To(Equal(entry))
+		})
+
+		It("should configure the address translations", func() {
+			Expect(entry.IsSrcNAT()).To(BeFalse())
+			Expect(entry.IsDstNAT()).To(BeTrue())
+
+			var types []uint16
+			for _, attr := range forgeEntryAttributes(&entry) {
+				types = append(types, attr.Type&nl.NLA_TYPE_MASK)
+			}
+			Expect(types).To(ContainElement(uint16(ctaNatDst)))
+			Expect(types).ToNot(ContainElement(uint16(ctaNatSrc)))
+		})
+
+		It("should reject truncated messages", func() {
+			_, err := parseEntry([]byte{unix.AF_INET})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the selection of the entries", func() {
+		It("should select the assured connections", func() {
+			Expect(isSynchronizable(&entry, nil)).To(BeTrue())
+		})
+
+		It("should skip the connections not yet assured", func() {
+			entry.Status = ipsSeenReply
+			Expect(isSynchronizable(&entry, nil)).To(BeFalse())
+		})
+
+		It("should skip the connections involving the local addresses", func() {
+			local := map[netip.Addr]struct{}{entry.Reply.Src: {}}
+			Expect(isSynchronizable(&entry, local)).To(BeFalse())
+		})
+
+		It("should skip the unsupported protocols", func() {
+			entry.Protocol = unix.IPPROTO_ICMP
+			Expect(isSynchronizable(&entry, nil)).To(BeFalse())
+		})
+	})
+
+	Describe("the synchronization between replicas", func() {
+		var (
+			ctx      context.Context
+			cancel   context.CancelFunc
+			sender   *Sender
+			receiver *Receiver
+			injected []Flow
+			key      func() ([]byte, error)
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			injected = nil
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			address := listener.Addr().String()
+			Expect(listener.Close()).To(Succeed())
+
+			keyFile := filepath.Join(GinkgoT().TempDir(), "privateKey")
+			Expect(os.WriteFile(keyFile, []byte("secret"), 0o600)).To(Succeed())
+			key = KeyFromFile(keyFile)
+
+			sender = NewSender(address, 50*time.Millisecond, key)
+			sender.list = func() ([]Flow, error) { return []Flow{entry}, nil }
+
+			receiver = NewReceiver(func(context.Context) (string, error) { return address, nil }, 50*time.Millisecond, key)
+			receiver.inject = func(entries []Flow) (int, error) {
+				injected = append(injected, entries...)
+				return len(entries), nil
+			}
+		})
+
+		AfterEach(func() { cancel() })
+
+		It("should inject the entries received from the active replica when promoted", func() {
+			go func() { defer GinkgoRecover(); Expect(sender.Start(ctx)).To(Succeed()) }()
+			done := make(chan struct{})
+			go func() { defer close(done); Expect(receiver.Start(ctx)).To(Succeed()) }()
+
+			Eventually(func() bool {
+				receiver.mutex.Lock()
+				defer receiver.mutex.Unlock()
+				return receiver.snapshot != nil
+			}).Should(BeTrue())
+
+			Expect(receiver.Promote()).To(Succeed())
+			Expect(injected).To(ConsistOf(entry))
+			Eventually(done).Should(BeClosed())
+
+			// Subsequent promotions have no effect.
+			Expect(receiver.Promote()).To(Succeed())
+			Expect(injected).To(HaveLen(1))
+		})
+
+		It("should not stream the entries to the replicas holding a different key", func() {
+			receiver.key = func() ([]byte, error) { return authMAC([]byte("other"), authKeyLabel), nil }
+			go func() { defer GinkgoRecover(); Expect(sender.Start(ctx)).To(Succeed()) }()
+			go func() { defer GinkgoRecover(); Expect(receiver.Start(ctx)).To(Succeed()) }()
+
+			Consistently(func() bool {
+				receiver.mutex.Lock()
+				defer receiver.mutex.Unlock()
+				return receiver.snapshot != nil
+			}, 500*time.Millisecond).Should(BeFalse())
+		})
+
+		It("should not inject anything if nothing has been received", func() {
+			Expect(receiver.Promote()).To(Succeed())
+			Expect(injected).To(BeEmpty())
+		})
+
+		It("should skip the expired entries", func() {
+			entry.Timeout = 0
+			receiver.snapshot, receiver.received = &Snapshot{Entries: []Flow{entry}}, time.Now()
+			Expect(receiver.Promote()).To(Succeed())
+			Expect(injected).To(BeEmpty())
+		})
+	})
+
+	Describe("the network policy", func() {
+		const port = 5872
+
+		var policy networkingv1.NetworkPolicy
+
+		BeforeEach(func() { MutateNetworkPolicy(&policy, "gateway", port) })
+
+		It("should select the replicas of the gateway", func() {
+			Expect(policy.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue(consts.K8sAppNameKey, "gateway"))
+			Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+		})
+
+		It("should allow the synchronization port only from the replicas", func() {
+			Expect(policy.Spec.Ingress).To(HaveLen(2))
+			Expect(policy.Spec.Ingress[0].From).To(ConsistOf(networkingv1.NetworkPolicyPeer{PodSelector: &policy.Spec.PodSelector}))
+			Expect(policy.Spec.Ingress[0].Ports).To(ConsistOf(networkingv1.NetworkPolicyPort{
+				Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(port))}))
+		})
+
+		It("should allow the other ports from any source", func() {
+			Expect(policy.Spec.Ingress[1].From).To(BeEmpty())
+			Expect(policy.Spec.Ingress[1].Ports).To(ConsistOf(
+				networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(1)), EndPort: ptr.To[int32](port - 1)},
+				networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(port + 1)), EndPort: ptr.To[int32](65535)},
+				networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolUDP)},
+				networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolSCTP)},
+			))
+		})
+	})
+})
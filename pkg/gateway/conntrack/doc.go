@@ -0,0 +1,19 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conntrack contains the logic to synchronize the connection tracking (and NAT) state
+// from the active gateway replica to the standby ones, so that established connections survive failovers.
+// The active replica periodically streams its conntrack entries to the standby replicas, which inject
+// the most recent ones into their own conntrack table when promoted, before starting to receive the traffic.
+package conntrack
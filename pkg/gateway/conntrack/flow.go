@@ -0,0 +1,346 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// nfnlSubsysCTNetlink is the netfilter netlink subsystem of the conntrack tables.
+	nfnlSubsysCTNetlink = 1
+	// ipctnlMsgCTNew is the message to create a conntrack entry.
+	ipctnlMsgCTNew = 0
+
+	ctaNatSrc          = 6
+	ctaNatDst          = 13
+	ctaNatV4MinIP      = 1
+	ctaNatV4MaxIP      = 2
+	ctaNatProto        = 3
+	ctaNatV6MinIP      = 4
+	ctaNatV6MaxIP      = 5
+	ctaProtoNatPortMin = 1
+	ctaProtoNatPortMax = 2
+
+	// Status bits of the conntrack entries (include/uapi/linux/netfilter/nf_conntrack_common.h).
+	ipsSeenReply = 1 << 1
+	ipsAssured   = 1 << 2
+	ipsDying     = 1 << 9
+)
+
+// Tuple is one direction of a connection.
+type Tuple struct {
+	Src     netip.Addr
+	Dst     netip.Addr
+	SrcPort uint16
+	DstPort uint16
+}
+
+// Flow is a conntrack entry, tracking a connection.
+type Flow struct {
+	Family   uint8
+	Protocol uint8
+	Orig     Tuple
+	Reply    Tuple
+	Status   uint32
+	Mark     uint32
+	// Timeout is the number of seconds before the entry expires.
+	Timeout uint32
+	// TCPState is the state of the TCP connections.
+	TCPState uint8
+}
+
+// String returns a human-readable representation of the entry.
+func (e *Flow) String() string {
+	return fmt.Sprintf("proto=%d src=%s dst=%s sport=%d dport=%d reply-src=%s reply-dst=%s reply-sport=%d reply-dport=%d",
+		e.Protocol, e.Orig.Src, e.Orig.Dst, e.Orig.SrcPort, e.Orig.DstPort, e.Reply.Src, e.Reply.Dst, e.Reply.SrcPort, e.Reply.DstPort)
+}
+
+// IsSrcNAT returns whether the source of the connection is translated.
+func (e *Flow) IsSrcNAT() bool {
+	return e.Orig.Src != e.Reply.Dst || e.Orig.SrcPort != e.Reply.DstPort
+}
+
+// IsDstNAT returns whether the destination of the connection is translated.
+func (e *Flow) IsDstNAT() bool {
+	return e.Orig.Dst != e.Reply.Src || e.Orig.DstPort != e.Reply.SrcPort
+}
+
+// List returns the conntrack entries worth synchronizing, i.e., the assured TCP and UDP connections,
+// excluding the ones involving the given local addresses, which are not valid on other replicas.
+func List(localAddrs map[netip.Addr]struct{}) ([]Flow, error) {
+	var entries []Flow
+	for _, family := range []uint8{unix.AF_INET, unix.AF_INET6} {
+		req := nl.NewNetlinkRequest((nfnlSubsysCTNetlink<<8)|nl.IPCTNL_MSG_CT_GET, unix.NLM_F_DUMP)
+		req.AddData(&nl.Nfgenmsg{NfgenFamily: family, Version: nl.NFNETLINK_V0})
+		msgs, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list conntrack entries: %w", err)
+		}
+
+		for _, msg := range msgs {
+			entry, err := parseEntry(msg)
+			if err != nil {
+				klog.V(4).Infof("Skipping conntrack entry: %v", err)
+				continue
+			}
+			if isSynchronizable(entry, localAddrs) {
+				entries = append(entries, *entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// Inject creates the given entries in the conntrack table. Entries already present are left untouched,
+// as they have been created by the traffic received in the meanwhile. It returns the number of created entries.
+func Inject(entries []Flow) (int, error) {
+	var created int
+	var errs []error
+	for i := range entries {
+		req := nl.NewNetlinkRequest((nfnlSubsysCTNetlink<<8)|ipctnlMsgCTNew, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
+		req.AddData(&nl.Nfgenmsg{NfgenFamily: entries[i].Family, Version: nl.NFNETLINK_V0})
+		for _, attr := range forgeEntryAttributes(&entries[i]) {
+			req.AddData(attr)
+		}
+
+		_, err := req.Execute(unix.NETLINK_NETFILTER, 0)
+		switch {
+		case errors.Is(err, syscall.EEXIST):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("unable to create conntrack entry %s: %w", &entries[i], err))
+		default:
+			created++
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+func isSynchronizable(entry *Flow, localAddrs map[netip.Addr]struct{}) bool {
+	if entry.Protocol != unix.IPPROTO_TCP && entry.Protocol != unix.IPPROTO_UDP {
+		return false
+	}
+	if entry.Status&ipsAssured == 0 || entry.Status&ipsDying != 0 {
+		return false
+	}
+	for _, addr := range []netip.Addr{entry.Orig.Src, entry.Orig.Dst, entry.Reply.Src, entry.Reply.Dst} {
+		if _, ok := localAddrs[addr]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseEntry parses a conntrack entry from a netlink message, including the netfilter header.
+func parseEntry(msg []byte) (*Flow, error) {
+	if len(msg) < nl.SizeofNfgenmsg {
+		return nil, fmt.Errorf("message too short")
+	}
+	attrs, err := nl.ParseRouteAttr(msg[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Flow{Family: msg[0]}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_ORIG:
+			err = parseTuple(attr.Value, &entry.Orig, &entry.Protocol)
+		case nl.CTA_TUPLE_REPLY:
+			err = parseTuple(attr.Value, &entry.Reply, &entry.Protocol)
+		case nl.CTA_STATUS:
+			entry.Status, err = parseUint32(attr.Value)
+		case nl.CTA_MARK:
+			entry.Mark, err = parseUint32(attr.Value)
+		case nl.CTA_TIMEOUT:
+			entry.Timeout, err = parseUint32(attr.Value)
+		case nl.CTA_PROTOINFO:
+			entry.TCPState, err = parseTCPState(attr.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !entry.Orig.Src.IsValid() || !entry.Reply.Src.IsValid() {
+		return nil, fmt.Errorf("missing tuple")
+	}
+	return entry, nil
+}
+
+func parseTuple(data []byte, tuple *Tuple, protocol *uint8) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
+	}
+
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_IP:
+			ips, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, ip := range ips {
+				addr, ok := netip.AddrFromSlice(ip.Value)
+				if !ok {
+					return fmt.Errorf("invalid address %v", ip.Value)
+				}
+				switch ip.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					tuple.Src = addr
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					tuple.Dst = addr
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			protos, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, proto := range protos {
+				switch proto.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.CTA_PROTO_NUM:
+					if len(proto.Value) < 1 {
+						return fmt.Errorf("invalid protocol")
+					}
+					*protocol = proto.Value[0]
+				case nl.CTA_PROTO_SRC_PORT:
+					tuple.SrcPort, err = parseUint16(proto.Value)
+				case nl.CTA_PROTO_DST_PORT:
+					tuple.DstPort, err = parseUint16(proto.Value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func parseTCPState(data []byte) (uint8, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return 0, err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.CTA_PROTOINFO_TCP {
+			continue
+		}
+		tcp, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return 0, err
+		}
+		for _, info := range tcp {
+			if info.Attr.Type&nl.NLA_TYPE_MASK == nl.CTA_PROTOINFO_TCP_STATE && len(info.Value) > 0 {
+				return info.Value[0], nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+func parseUint16(data []byte) (uint16, error) {
+	if len(data) < 2 {
+		return 0, fmt.Errorf("invalid attribute length %d", len(data))
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
+func parseUint32(data []byte) (uint32, error) {
+	if len(data) < 4 {
+		return 0, fmt.Errorf("invalid attribute length %d", len(data))
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+// forgeEntryAttributes forges the netlink attributes to create the given entry.
+// The address translations are configured explicitly, as the kernel does not infer them from the reply tuple.
+func forgeEntryAttributes(entry *Flow) []*nl.RtAttr {
+	attrs := []*nl.RtAttr{
+		forgeTupleAttribute(nl.CTA_TUPLE_ORIG, entry.Protocol, &entry.Orig),
+		forgeTupleAttribute(nl.CTA_TUPLE_REPLY, entry.Protocol, &entry.Reply),
+		// Only the flags concerning the observed traffic can be set, the others are managed by the kernel.
+		nl.NewRtAttr(nl.CTA_STATUS, bigEndian32(entry.Status&(ipsSeenReply|ipsAssured))),
+		nl.NewRtAttr(nl.CTA_TIMEOUT, bigEndian32(entry.Timeout)),
+		nl.NewRtAttr(nl.CTA_MARK, bigEndian32(entry.Mark)),
+	}
+
+	if entry.IsSrcNAT() {
+		attrs = append(attrs, forgeNatAttribute(ctaNatSrc, entry.Reply.Dst, entry.Reply.DstPort))
+	}
+	if entry.IsDstNAT() {
+		attrs = append(attrs, forgeNatAttribute(ctaNatDst, entry.Reply.Src, entry.Reply.SrcPort))
+	}
+
+	if entry.Protocol == unix.IPPROTO_TCP {
+		protoinfo := nl.NewRtAttr(nl.CTA_PROTOINFO|int(nl.NLA_F_NESTED), nil)
+		tcp := protoinfo.AddRtAttr(nl.CTA_PROTOINFO_TCP|int(nl.NLA_F_NESTED), nil)
+		tcp.AddRtAttr(nl.CTA_PROTOINFO_TCP_STATE, []byte{entry.TCPState})
+		attrs = append(attrs, protoinfo)
+	}
+	return attrs
+}
+
+func forgeTupleAttribute(attrType int, protocol uint8, tuple *Tuple) *nl.RtAttr {
+	attr := nl.NewRtAttr(attrType|int(nl.NLA_F_NESTED), nil)
+
+	ip := attr.AddRtAttr(nl.CTA_TUPLE_IP|int(nl.NLA_F_NESTED), nil)
+	if tuple.Src.Is4() {
+		ip.AddRtAttr(nl.CTA_IP_V4_SRC, tuple.Src.AsSlice())
+		ip.AddRtAttr(nl.CTA_IP_V4_DST, tuple.Dst.AsSlice())
+	} else {
+		ip.AddRtAttr(nl.CTA_IP_V6_SRC, tuple.Src.AsSlice())
+		ip.AddRtAttr(nl.CTA_IP_V6_DST, tuple.Dst.AsSlice())
+	}
+
+	proto := attr.AddRtAttr(nl.CTA_TUPLE_PROTO|int(nl.NLA_F_NESTED), nil)
+	proto.AddRtAttr(nl.CTA_PROTO_NUM, []byte{protocol})
+	proto.AddRtAttr(nl.CTA_PROTO_SRC_PORT, bigEndian16(tuple.SrcPort))
+	proto.AddRtAttr(nl.CTA_PROTO_DST_PORT, bigEndian16(tuple.DstPort))
+	return attr
+}
+
+func forgeNatAttribute(attrType int, addr netip.Addr, port uint16) *nl.RtAttr {
+	attr := nl.NewRtAttr(attrType|int(nl.NLA_F_NESTED), nil)
+	if addr.Is4() {
+		attr.AddRtAttr(ctaNatV4MinIP, addr.AsSlice())
+		attr.AddRtAttr(ctaNatV4MaxIP, addr.AsSlice())
+	} else {
+		attr.AddRtAttr(ctaNatV6MinIP, addr.AsSlice())
+		attr.AddRtAttr(ctaNatV6MaxIP, addr.AsSlice())
+	}
+	proto := attr.AddRtAttr(ctaNatProto|int(nl.NLA_F_NESTED), nil)
+	proto.AddRtAttr(ctaProtoNatPortMin, bigEndian16(port))
+	proto.AddRtAttr(ctaProtoNatPortMax, bigEndian16(port))
+	return attr
+}
+
+func bigEndian16(value uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, value)
+}
+
+func bigEndian32(value uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, value)
+}
@@ -0,0 +1,74 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// NetworkPolicyName returns the name of the NetworkPolicy protecting the conntrack synchronization of the given gateway.
+func NetworkPolicyName(gatewayName string) string {
+	return gatewayName + "-conntrack-sync"
+}
+
+// MutateNetworkPolicy configures the given NetworkPolicy to restrict the access to the conntrack synchronization port
+// of the replicas of the given gateway to the other replicas. As the replicas are isolated for ingress traffic once selected,
+// every other TCP, UDP and SCTP port is explicitly allowed from any source. Conversely, NetworkPolicies cannot express ICMP,
+// hence the ICMP messages towards the replicas (e.g., the ones required by the path MTU discovery) may be dropped,
+// depending on the network plugin. For this reason, the NetworkPolicy is created only if explicitly requested.
+func MutateNetworkPolicy(policy *networkingv1.NetworkPolicy, gatewayName string, port int) {
+	replicas := metav1.LabelSelector{MatchLabels: map[string]string{consts.K8sAppNameKey: gatewayName}}
+
+	var others []networkingv1.NetworkPolicyPort
+	if port > 1 {
+		others = append(others, forgeNetworkPolicyPort(corev1.ProtocolTCP, 1, port-1))
+	}
+	if port < math.MaxUint16 {
+		others = append(others, forgeNetworkPolicyPort(corev1.ProtocolTCP, port+1, math.MaxUint16))
+	}
+	others = append(others,
+		networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolUDP)},
+		networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolSCTP)})
+
+	policy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: replicas,
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &replicas}},
+			Ports: []networkingv1.NetworkPolicyPort{forgeNetworkPolicyPort(corev1.ProtocolTCP, port, port)},
+		}, {
+			Ports: others,
+		}},
+	}
+}
+
+func forgeNetworkPolicyPort(protocol corev1.Protocol, start, end int) networkingv1.NetworkPolicyPort {
+	policyPort := networkingv1.NetworkPolicyPort{
+		Protocol: ptr.To(protocol),
+		Port:     ptr.To(intstr.FromInt32(int32(start))), //nolint:gosec // The port is always in range.
+	}
+	if end > start {
+		policyPort.EndPort = ptr.To(int32(end)) //nolint:gosec // The port is always in range.
+	}
+	return policyPort
+}
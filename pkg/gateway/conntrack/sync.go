@@ -0,0 +1,257 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	_ manager.Runnable               = &Sender{}
+	_ manager.Runnable               = &Receiver{}
+	_ manager.LeaderElectionRunnable = &Receiver{}
+)
+
+// Snapshot is the message streamed by the active replica, containing its current conntrack entries.
+type Snapshot struct {
+	Entries []Flow
+}
+
+// Sender streams the conntrack entries of the active replica to the standby ones, once authenticated.
+// It requires the leader election, hence it runs only on the active replica.
+type Sender struct {
+	address  string
+	interval time.Duration
+	key      func() ([]byte, error)
+	list     func() ([]Flow, error)
+}
+
+// NewSender returns a new Sender, listening on the given address and sending a snapshot at every interval
+// to the standby replicas authenticated with the key returned by the given function.
+func NewSender(address string, interval time.Duration, key func() ([]byte, error)) *Sender {
+	return &Sender{
+		address:  address,
+		interval: interval,
+		key:      key,
+		list:     listSynchronizable,
+	}
+}
+
+// Start listens for the standby replicas until the given context is canceled.
+func (s *Sender) Start(ctx context.Context) error {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("unable to listen for conntrack synchronization on %s: %w", s.address, err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+
+	klog.Infof("Streaming the conntrack entries to the standby replicas on %s", listener.Addr())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to accept conntrack synchronization connections: %w", err)
+		}
+		go s.serve(ctx, conn)
+	}
+}
+
+func (s *Sender) serve(ctx context.Context, conn net.Conn) {
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	key, err := s.key()
+	if err != nil {
+		klog.Errorf("Unable to authenticate the standby replica %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+	if err := authenticateReceiver(conn, key); err != nil {
+		klog.Warningf("Rejected conntrack synchronization from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	klog.Infof("Standby replica %s connected for conntrack synchronization", conn.RemoteAddr())
+	encoder := gob.NewEncoder(conn)
+	err = wait.PollUntilContextCancel(ctx, s.interval, true, func(context.Context) (bool, error) {
+		entries, err := s.list()
+		if err != nil {
+			klog.Errorf("Unable to list the conntrack entries: %v", err)
+			return false, nil
+		}
+		if err := conn.SetWriteDeadline(time.Now().Add(s.interval)); err != nil {
+			return false, err
+		}
+		return false, encoder.Encode(&Snapshot{Entries: entries})
+	})
+	if err != nil && ctx.Err() == nil {
+		klog.Warningf("Conntrack synchronization with standby replica %s interrupted: %v", conn.RemoteAddr(), err)
+	}
+}
+
+// Receiver receives the conntrack entries from the active replica, and injects the most recent ones when promoted.
+// It does not require the leader election, hence it runs on the standby replicas as well.
+type Receiver struct {
+	resolve  func(ctx context.Context) (string, error)
+	interval time.Duration
+	key      func() ([]byte, error)
+	inject   func([]Flow) (int, error)
+
+	mutex    sync.Mutex
+	snapshot *Snapshot
+	received time.Time
+	promoted chan struct{}
+	once     sync.Once
+}
+
+// NewReceiver returns a new Receiver, connecting to the address of the active replica returned by the given function,
+// and retrying at every interval in case of errors. The active replica is authenticated with the key returned by the given function.
+func NewReceiver(resolve func(ctx context.Context) (string, error), interval time.Duration, key func() ([]byte, error)) *Receiver {
+	return &Receiver{
+		resolve:  resolve,
+		interval: interval,
+		key:      key,
+		inject:   Inject,
+		promoted: make(chan struct{}),
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start receives the conntrack entries from the active replica until the given context is canceled, or the replica is promoted.
+func (r *Receiver) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.promoted:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.receive(ctx); err != nil && ctx.Err() == nil {
+			klog.V(2).Infof("Unable to receive the conntrack entries from the active replica: %v", err)
+		}
+	}, r.interval)
+	return nil
+}
+
+func (r *Receiver) receive(ctx context.Context) error {
+	address, err := r.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := r.key()
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	if err := authenticateSender(conn, key); err != nil {
+		return fmt.Errorf("unable to authenticate the active replica %s: %w", address, err)
+	}
+
+	klog.Infof("Receiving the conntrack entries from the active replica %s", address)
+	decoder := gob.NewDecoder(conn)
+	for {
+		// The active replica sends a snapshot at every interval, hence a longer silence means it is no longer working.
+		if err := conn.SetReadDeadline(time.Now().Add(3 * r.interval)); err != nil {
+			return err
+		}
+		var snapshot Snapshot
+		if err := decoder.Decode(&snapshot); err != nil {
+			return err
+		}
+
+		r.mutex.Lock()
+		r.snapshot, r.received = &snapshot, time.Now()
+		r.mutex.Unlock()
+	}
+}
+
+// Promote stops receiving the conntrack entries, and injects the most recent ones, with the timeouts
+// decreased by the time elapsed since their reception. Only the first invocation has effect.
+func (r *Receiver) Promote() error {
+	var err error
+	r.once.Do(func() {
+		close(r.promoted)
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		if r.snapshot == nil {
+			klog.Info("No conntrack entries received from the previously active replica")
+			return
+		}
+
+		elapsed := uint32(time.Since(r.received).Seconds())
+		entries := make([]Flow, 0, len(r.snapshot.Entries))
+		for i := range r.snapshot.Entries {
+			if r.snapshot.Entries[i].Timeout > elapsed {
+				entry := r.snapshot.Entries[i]
+				entry.Timeout -= elapsed
+				entries = append(entries, entry)
+			}
+		}
+
+		var created int
+		created, err = r.inject(entries)
+		klog.Infof("Injected %d conntrack entries received from the previously active replica", created)
+	})
+	return err
+}
+
+// listSynchronizable lists the conntrack entries to be synchronized, excluding the ones involving the addresses of the replica.
+func listSynchronizable() ([]Flow, error) {
+	addrs, err := netlink.AddrList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the local addresses: %w", err)
+	}
+
+	localAddrs := make(map[netip.Addr]struct{}, len(addrs))
+	for i := range addrs {
+		if addr, ok := netip.AddrFromSlice(addrs[i].IP); ok {
+			localAddrs[addr.Unmap()] = struct{}{}
+		}
+	}
+	return List(localAddrs)
+}
@@ -51,6 +51,22 @@ const (
 	// FlagConcurrentContainersNames is the names of the containers that the gateway container must wait for.
 	FlagConcurrentContainersNames FlagName = "concurrent-containers-names"
 
+	// FlagNameConcurrencyMode is the mode in which the replicas of the same gateway are managed.
+	FlagNameConcurrencyMode FlagName = "concurrency-mode"
+
+	// FlagNameConntrackSync is the flag to enable the synchronization of the conntrack entries across replicas.
+	FlagNameConntrackSync FlagName = "conntrack-sync"
+	// FlagNameConntrackSyncAddress is the address used to synchronize the conntrack entries.
+	FlagNameConntrackSyncAddress FlagName = "conntrack-sync-address"
+	// FlagNameConntrackSyncPort is the port used to synchronize the conntrack entries.
+	FlagNameConntrackSyncPort FlagName = "conntrack-sync-port"
+	// FlagNameConntrackSyncInterval is the interval between two synchronizations of the conntrack entries.
+	FlagNameConntrackSyncInterval FlagName = "conntrack-sync-interval"
+	// FlagNameConntrackSyncKeyFile is the file containing the secret used to authenticate the replicas synchronizing the conntrack entries.
+	FlagNameConntrackSyncKeyFile FlagName = "conntrack-sync-key-file"
+	// FlagNameConntrackSyncNetworkPolicy is the flag to restrict the access to the synchronization port through a NetworkPolicy.
+	FlagNameConntrackSyncNetworkPolicy FlagName = "conntrack-sync-network-policy"
+
 	// FlagNameLeaderElection is the flag to enable leader election.
 	FlagNameLeaderElection FlagName = "leader-election"
 	// FlagNameLeaderElectionLeaseDuration is the lease duration for the leader election.
@@ -99,6 +115,22 @@ func InitFlags(flagset *pflag.FlagSet, opts *Options) {
 	flagset.StringSliceVar(&opts.ConcurrentContainersNames, FlagConcurrentContainersNames.String(),
 		[]string{}, "the container list that gateway container must wait for")
 
+	flagset.Var(&opts.ConcurrencyMode, FlagNameConcurrencyMode.String(),
+		"Concurrency mode of the gateway replicas (active-passive or active-active)")
+
+	flagset.BoolVar(&opts.ConntrackSync, FlagNameConntrackSync.String(), false,
+		"Synchronize the conntrack entries from the active replica to the standby ones (active-passive mode only)")
+	flagset.StringVar(&opts.ConntrackSyncAddress, FlagNameConntrackSyncAddress.String(), "",
+		"Address of the pod the active replica streams the conntrack entries from (required if the synchronization is enabled)")
+	flagset.IntVar(&opts.ConntrackSyncPort, FlagNameConntrackSyncPort.String(), 5872,
+		"Port used by the active replica to stream the conntrack entries to the standby ones")
+	flagset.DurationVar(&opts.ConntrackSyncInterval, FlagNameConntrackSyncInterval.String(), 2*time.Second,
+		"Interval between two synchronizations of the conntrack entries")
+	flagset.StringVar(&opts.ConntrackSyncKeyFile, FlagNameConntrackSyncKeyFile.String(), "",
+		"File containing the secret shared by the replicas to authenticate each other (required if the synchronization is enabled)")
+	flagset.BoolVar(&opts.ConntrackSyncNetworkPolicy, FlagNameConntrackSyncNetworkPolicy.String(), false,
+		"Create a NetworkPolicy allowing only the other replicas to reach the synchronization port (it may drop the ICMP messages towards the replicas)")
+
 	flagset.BoolVar(&opts.LeaderElection, FlagNameLeaderElection.String(), false, "Enable leader election")
 	flagset.DurationVar(&opts.LeaderElectionLeaseDuration, FlagNameLeaderElectionLeaseDuration.String(), 15*time.Second,
 		"LeaseDuration for the leader election")
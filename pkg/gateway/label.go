@@ -42,6 +42,9 @@ const (
 
 	// FirewallSubCategoryFabricTargetValue is the value used by the firewallconfiguration controller to reconcile only resources related to a gateway.
 	FirewallSubCategoryFabricTargetValue = "fabric"
+
+	// ConcurrencyModeAnnotationKey is the annotation used to mark the services exposing active-active gateways.
+	ConcurrencyModeAnnotationKey = "networking.liqo.io/concurrency-mode"
 )
 
 // ForgeActiveGatewayPodLabels returns the labels for the gateway pod.
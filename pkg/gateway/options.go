@@ -36,6 +36,20 @@ type Options struct {
 
 	ConcurrentContainersNames []string
 
+	ConcurrencyMode ConcurrencyMode
+	// ReplicaIndex is the index claimed by the gateway replica, in case of active-active concurrency mode.
+	ReplicaIndex int
+
+	// ConntrackSync enables the synchronization of the conntrack entries from the active replica to the standby ones.
+	ConntrackSync         bool
+	ConntrackSyncAddress  string
+	ConntrackSyncPort     int
+	ConntrackSyncInterval time.Duration
+	// ConntrackSyncKeyFile is the file containing the secret shared by the replicas to authenticate each other.
+	ConntrackSyncKeyFile string
+	// ConntrackSyncNetworkPolicy restricts the access to the synchronization port to the other replicas through a NetworkPolicy.
+	ConntrackSyncNetworkPolicy bool
+
 	LeaderElection              bool
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
@@ -51,6 +65,7 @@ type Options struct {
 // NewOptions returns a new Options struct.
 func NewOptions() *Options {
 	return &Options{
+		ConcurrencyMode:      ConcurrencyModeActivePassive,
 		MinimumKernelVersion: kernelversion.MinimumKernelVersion,
 	}
 }
@@ -86,3 +101,35 @@ func (m *Mode) Set(value string) error {
 func (m *Mode) Type() string {
 	return "string"
 }
+
+// ConcurrencyMode is the mode in which the replicas of the same gateway are managed.
+type ConcurrencyMode string
+
+const (
+	// ConcurrencyModeActivePassive is the mode when a single replica is active, and the others are on standby.
+	ConcurrencyModeActivePassive ConcurrencyMode = "active-passive"
+	// ConcurrencyModeActiveActive is the mode when all replicas are active, each one terminating its own tunnel.
+	ConcurrencyModeActiveActive ConcurrencyMode = "active-active"
+)
+
+// String returns the string representation of the concurrency mode.
+func (cm ConcurrencyMode) String() string {
+	return string(cm)
+}
+
+// Set sets the value of the concurrency mode.
+func (cm *ConcurrencyMode) Set(value string) error {
+	if value == "" {
+		return fmt.Errorf("concurrency mode cannot be empty")
+	}
+	if value != ConcurrencyModeActivePassive.String() && value != ConcurrencyModeActiveActive.String() {
+		return fmt.Errorf("invalid concurrency mode %q", value)
+	}
+	*cm = ConcurrencyMode(value)
+	return nil
+}
+
+// Type returns the type of the concurrency mode.
+func (cm *ConcurrencyMode) Type() string {
+	return "string"
+}
@@ -0,0 +1,194 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+)
+
+// resyncPeriod is the period after which the queueing discipline is checked again, to restore it
+// in case the tunnel interface has been recreated.
+const resyncPeriod = 30 * time.Second
+
+// cluster-role
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=gatewayservers;gatewayclients,verbs=get;list;watch
+
+// BandwidthLimitReconciler enforces the bandwidth limit configured on the GatewayServer (or GatewayClient)
+// of the gateway on the tunnel interface.
+type BandwidthLimitReconciler struct {
+	Client         client.Client
+	Scheme         *runtime.Scheme
+	EventsRecorder record.EventRecorder
+	Options        *gateway.Options
+
+	mutex sync.RWMutex
+	// limit is the currently enforced limit, nil if none.
+	limit *networkingv1beta1.BandwidthLimit
+	// classes are the classes forged according to the currently enforced limit.
+	classes []Class
+	// linkIndex is the index of the tunnel interface the limit has been enforced on.
+	linkIndex int
+}
+
+// NewBandwidthLimitReconciler returns a new BandwidthLimitReconciler.
+func NewBandwidthLimitReconciler(cl client.Client, s *runtime.Scheme,
+	er record.EventRecorder, options *gateway.Options) *BandwidthLimitReconciler {
+	return &BandwidthLimitReconciler{
+		Client:         cl,
+		Scheme:         s,
+		EventsRecorder: er,
+		Options:        options,
+	}
+}
+
+// Reconcile enforces the bandwidth limit of the gateway on the tunnel interface.
+func (r *BandwidthLimitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	gw := r.newGatewayObject()
+	var limit *networkingv1beta1.BandwidthLimit
+	switch err := r.Client.Get(ctx, req.NamespacedName, gw); {
+	case apierrors.IsNotFound(err):
+		klog.V(4).Infof("The gateway %q does not exist", req.NamespacedName)
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("unable to get the gateway %q: %w", req.NamespacedName, err)
+	default:
+		limit = getBandwidthLimit(gw)
+	}
+
+	link, err := netlink.LinkByName(tunnel.TunnelInterfaceName)
+	var notFound netlink.LinkNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		// The queueing discipline is removed together with the interface.
+		r.setApplied(nil, nil, 0)
+		if limit != nil {
+			klog.V(4).Infof("The tunnel interface %q does not exist yet", tunnel.TunnelInterfaceName)
+			return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+		}
+		return ctrl.Result{}, nil
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("unable to get the tunnel interface %q: %w", tunnel.TunnelInterfaceName, err)
+	}
+
+	if limit == nil {
+		if err := Clear(link); err != nil {
+			return ctrl.Result{}, err
+		}
+		if r.isApplied(nil, 0) {
+			return ctrl.Result{}, nil
+		}
+		klog.Infof("Removed the bandwidth limit from the tunnel interface %q", tunnel.TunnelInterfaceName)
+		r.setApplied(nil, nil, 0)
+		return ctrl.Result{}, nil
+	}
+
+	applied, err := IsApplied(link)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if applied && r.isApplied(limit, link.Attrs().Index) {
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	classes, err := ForgeClasses(limit)
+	if err != nil {
+		r.EventsRecorder.Eventf(gw, corev1.EventTypeWarning, "BandwidthLimitInvalid", "Invalid bandwidth limit: %v", err)
+		klog.Errorf("Invalid bandwidth limit of gateway %q: %v", req.NamespacedName, err)
+		// The limit is not valid, hence there is no point in retrying until it is modified.
+		return ctrl.Result{}, nil
+	}
+
+	if err := Apply(link, classes); err != nil {
+		r.EventsRecorder.Eventf(gw, corev1.EventTypeWarning, "BandwidthLimitFailed", "Unable to enforce the bandwidth limit: %v", err)
+		return ctrl.Result{}, fmt.Errorf("unable to enforce the bandwidth limit on the tunnel interface %q: %w", tunnel.TunnelInterfaceName, err)
+	}
+	r.setApplied(limit, classes, link.Attrs().Index)
+
+	klog.Infof("Enforced a bandwidth limit of %s bps (%d classes) on the tunnel interface %q",
+		limit.Rate.String(), len(limit.Classes), tunnel.TunnelInterfaceName)
+	r.EventsRecorder.Eventf(gw, corev1.EventTypeNormal, "BandwidthLimitEnforced",
+		"Enforced a bandwidth limit of %s bps on the gateway %s", limit.Rate.String(), r.Options.PodName)
+	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+}
+
+// SetupWithManager registers the BandwidthLimitReconciler to the manager.
+func (r *BandwidthLimitReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	filterByNamePredicate := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.Options.Name && obj.GetNamespace() == r.Options.Namespace
+	})
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlBandwidthLimit).
+		For(r.newGatewayObject(), builder.WithPredicates(filterByNamePredicate)).
+		Complete(r)
+}
+
+// AppliedClasses returns the classes forged according to the currently enforced limit, and the index of the link they are attached to.
+func (r *BandwidthLimitReconciler) AppliedClasses() (classes []Class, linkIndex int) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.classes, r.linkIndex
+}
+
+func (r *BandwidthLimitReconciler) isApplied(limit *networkingv1beta1.BandwidthLimit, linkIndex int) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.linkIndex == linkIndex && equality.Semantic.DeepEqual(r.limit, limit)
+}
+
+func (r *BandwidthLimitReconciler) setApplied(limit *networkingv1beta1.BandwidthLimit, classes []Class, linkIndex int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.limit = limit.DeepCopy()
+	r.classes = classes
+	r.linkIndex = linkIndex
+}
+
+// newGatewayObject returns an empty GatewayServer or GatewayClient, depending on the gateway mode.
+func (r *BandwidthLimitReconciler) newGatewayObject() client.Object {
+	if r.Options.Mode == gateway.ModeClient {
+		return &networkingv1beta1.GatewayClient{}
+	}
+	return &networkingv1beta1.GatewayServer{}
+}
+
+func getBandwidthLimit(obj client.Object) *networkingv1beta1.BandwidthLimit {
+	switch gw := obj.(type) {
+	case *networkingv1beta1.GatewayServer:
+		return gw.Spec.BandwidthLimit
+	case *networkingv1beta1.GatewayClient:
+		return gw.Spec.BandwidthLimit
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaping
+
+import (
+	"fmt"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+)
+
+const (
+	// DefaultClassName is the name of the class serving the traffic not belonging to any configured class.
+	DefaultClassName = "default"
+	// RootClassName is the name of the class enforcing the overall rate.
+	RootClassName = "root"
+
+	rootMajor         uint16 = 1
+	rootClassMinor    uint16 = 1
+	firstClassMinor   uint16 = 0x10
+	defaultClassMinor uint16 = 0xff
+
+	// defaultClassPriority is the lowest priority supported by HTB, lower than the ones allowed for the configured classes.
+	defaultClassPriority = 7
+	// minDefaultClassRate is the rate (bits per second) guaranteed to the default class when the configured classes
+	// reserve the whole rate, as HTB does not support classes with a null rate.
+	minDefaultClassRate = 8000
+)
+
+// Class describes an HTB class forged according to a BandwidthLimit.
+type Class struct {
+	Name string
+	// Minor is the minor number of the class handle, whose major number is always the one of the root qdisc.
+	Minor uint16
+	// Rate is the guaranteed rate, in bits per second.
+	Rate uint64
+	// Ceil is the maximum rate, in bits per second, reachable borrowing from the parent class.
+	Ceil uint64
+	// Burst is the amount of bytes which can be sent at ceil speed. Zero means computed according to the rate.
+	Burst    uint32
+	Priority uint32
+	// DSCP are the DSCP values of the packets to be enqueued into the class.
+	DSCP []uint8
+}
+
+// ForgeClasses returns the HTB classes enforcing the given limit: the root class comes first, followed by the
+// configured classes and by the default one. The configured classes borrow the unused rate from the root class.
+func ForgeClasses(limit *networkingv1beta1.BandwidthLimit) ([]Class, error) {
+	rate := limit.Rate.Value()
+	if rate <= 0 {
+		return nil, fmt.Errorf("invalid rate %q: it must be positive", limit.Rate.String())
+	}
+
+	var burst uint32
+	if limit.Burst != nil {
+		if limit.Burst.Value() < 0 || limit.Burst.Value() > int64(^uint32(0)) {
+			return nil, fmt.Errorf("invalid burst %q", limit.Burst.String())
+		}
+		burst = uint32(limit.Burst.Value())
+	}
+
+	classes := []Class{{Name: RootClassName, Minor: rootClassMinor, Rate: uint64(rate), Ceil: uint64(rate), Burst: burst}}
+
+	names := map[string]struct{}{RootClassName: {}, DefaultClassName: {}}
+	dscps := map[networkingv1beta1.DSCP]string{}
+	var reserved int64
+	for i := range limit.Classes {
+		class := &limit.Classes[i]
+		if _, found := names[class.Name]; found {
+			return nil, fmt.Errorf("invalid class name %q: it is duplicated or reserved", class.Name)
+		}
+		names[class.Name] = struct{}{}
+
+		classRate := class.Rate.Value()
+		if classRate <= 0 {
+			return nil, fmt.Errorf("invalid rate %q of class %q: it must be positive", class.Rate.String(), class.Name)
+		}
+		reserved += classRate
+
+		values := make([]uint8, 0, len(class.DSCP))
+		for _, dscp := range class.DSCP {
+			if dscp > 63 {
+				return nil, fmt.Errorf("invalid DSCP value %d of class %q", dscp, class.Name)
+			}
+			if other, found := dscps[dscp]; found {
+				return nil, fmt.Errorf("DSCP value %d is assigned to both classes %q and %q", dscp, other, class.Name)
+			}
+			dscps[dscp] = class.Name
+			values = append(values, uint8(dscp))
+		}
+
+		classes = append(classes, Class{
+			Name:     class.Name,
+			Minor:    firstClassMinor + uint16(i),
+			Rate:     uint64(classRate),
+			Ceil:     uint64(rate),
+			Burst:    burst,
+			Priority: uint32(class.Priority), //nolint:gosec // bounded by the API validation.
+			DSCP:     values,
+		})
+	}
+
+	if reserved > rate {
+		return nil, fmt.Errorf("the rates of the classes (%d bps) exceed the overall rate (%d bps)", reserved, rate)
+	}
+
+	return append(classes, Class{
+		Name:     DefaultClassName,
+		Minor:    defaultClassMinor,
+		Rate:     uint64(max(rate-reserved, minDefaultClassRate)),
+		Ceil:     uint64(rate),
+		Burst:    burst,
+		Priority: defaultClassPriority,
+	}), nil
+}
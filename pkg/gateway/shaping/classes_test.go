@@ -0,0 +1,144 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaping
+
+import (
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+)
+
+var _ = Describe("Traffic classes", func() {
+	var limit *networkingv1beta1.BandwidthLimit
+
+	BeforeEach(func() {
+		limit = &networkingv1beta1.BandwidthLimit{Rate: resource.MustParse("100M")}
+	})
+
+	Describe("Forging the classes", func() {
+		When("no class is configured", func() {
+			It("should forge the root and the default class, sharing the whole rate", func() {
+				classes, err := ForgeClasses(limit)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(classes).To(Equal([]Class{
+					{Name: RootClassName, Minor: rootClassMinor, Rate: 100e6, Ceil: 100e6},
+					{Name: DefaultClassName, Minor: defaultClassMinor, Rate: 100e6, Ceil: 100e6, Priority: defaultClassPriority},
+				}))
+			})
+		})
+
+		When("the burst is configured", func() {
+			It("should propagate it to all the classes", func() {
+				limit.Burst = ptr.To(resource.MustParse("64Ki"))
+				classes, err := ForgeClasses(limit)
+				Expect(err).ToNot(HaveOccurred())
+				for i := range classes {
+					Expect(classes[i].Burst).To(BeEquivalentTo(64 * 1024))
+				}
+			})
+		})
+
+		When("some classes are configured", func() {
+			BeforeEach(func() {
+				limit.Classes = []networkingv1beta1.BandwidthClass{
+					{Name: "realtime", DSCP: []networkingv1beta1.DSCP{46}, Rate: resource.MustParse("20M")},
+					{Name: "bulk", DSCP: []networkingv1beta1.DSCP{8, 10}, Rate: resource.MustParse("30M"), Priority: 3},
+				}
+			})
+
+			It("should forge a class for each of them, borrowing up to the overall rate", func() {
+				classes, err := ForgeClasses(limit)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(classes).To(HaveLen(4))
+				Expect(classes[1]).To(Equal(Class{Name: "realtime", Minor: firstClassMinor, Rate: 20e6, Ceil: 100e6, DSCP: []uint8{46}}))
+				Expect(classes[2]).To(Equal(Class{Name: "bulk", Minor: firstClassMinor + 1, Rate: 30e6, Ceil: 100e6, Priority: 3, DSCP: []uint8{8, 10}}))
+			})
+
+			It("should guarantee the residual rate to the default class", func() {
+				classes, err := ForgeClasses(limit)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(classes[3].Name).To(Equal(DefaultClassName))
+				Expect(classes[3].Rate).To(BeEquivalentTo(50e6))
+				Expect(classes[3].Priority).To(BeEquivalentTo(defaultClassPriority))
+			})
+
+			It("should guarantee a minimum rate to the default class, if the configured classes reserve the whole rate", func() {
+				limit.Classes[1].Rate = resource.MustParse("80M")
+				classes, err := ForgeClasses(limit)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(classes[3].Rate).To(BeEquivalentTo(minDefaultClassRate))
+			})
+
+			It("should fail if the classes reserve more than the overall rate", func() {
+				limit.Classes[1].Rate = resource.MustParse("90M")
+				_, err := ForgeClasses(limit)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if a class name is duplicated", func() {
+				limit.Classes[1].Name = "realtime"
+				_, err := ForgeClasses(limit)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if a class name is reserved", func() {
+				limit.Classes[1].Name = DefaultClassName
+				_, err := ForgeClasses(limit)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if a DSCP value is assigned to multiple classes", func() {
+				limit.Classes[1].DSCP = append(limit.Classes[1].DSCP, 46)
+				_, err := ForgeClasses(limit)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the rate is not positive", func() {
+			It("should fail", func() {
+				limit.Rate = resource.MustParse("0")
+				_, err := ForgeClasses(limit)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Forging the filters", func() {
+		It("should match the DSCP bits of both IPv4 and IPv6 packets", func() {
+			// DSCP 46 (EF) corresponds to TOS 0xb8, and to the 0x0b8 traffic class.
+			filters := forgeFilters(3, netlink.MakeHandle(1, 0), netlink.MakeHandle(1, 0x10), []uint8{46})
+			Expect(filters).To(HaveLen(2))
+
+			ipv4, ok := filters[0].(*netlink.U32)
+			Expect(ok).To(BeTrue())
+			Expect(ipv4.Protocol).To(BeEquivalentTo(unix.ETH_P_IP))
+			Expect(ipv4.ClassId).To(Equal(netlink.MakeHandle(1, 0x10)))
+			Expect(ipv4.Sel.Keys).To(Equal([]nl.TcU32Key{{Mask: 0x00fc0000, Val: 0x00b80000}}))
+
+			ipv6, ok := filters[1].(*netlink.U32)
+			Expect(ok).To(BeTrue())
+			Expect(ipv6.Protocol).To(BeEquivalentTo(unix.ETH_P_IPV6))
+			Expect(ipv6.Priority).ToNot(Equal(ipv4.Priority))
+			Expect(ipv6.Sel.Keys).To(Equal([]nl.TcU32Key{{Mask: 0x0fc00000, Val: 0x0b800000}}))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shaping contains the logic to enforce the bandwidth limits configured on the GatewayServer and GatewayClient
+// resources, through an HTB queueing discipline attached to the tunnel interface. The optional traffic classes are
+// identified by the DSCP values of the packets, and are guaranteed a share of the rate, while the traffic not belonging
+// to any class is served by the default class, with the lowest priority.
+package shaping
@@ -0,0 +1,142 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaping
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vishvananda/netlink"
+)
+
+var metricsLabels = []string{"cluster_id", "class"}
+
+var (
+	// MetricsShapedRate is the metric that exposes the rate guaranteed to a traffic class.
+	MetricsShapedRate = prometheus.NewDesc(
+		"liqo_peer_shaped_rate_bits",
+		"Rate guaranteed to a traffic class towards a given peer, in bits per second.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsShapedCeil is the metric that exposes the maximum rate of a traffic class.
+	MetricsShapedCeil = prometheus.NewDesc(
+		"liqo_peer_shaped_ceil_bits",
+		"Maximum rate of a traffic class towards a given peer, in bits per second.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsShapedTransmittedBytes is the metric that counts the number of bytes of a traffic class transmitted to a given peer.
+	MetricsShapedTransmittedBytes = prometheus.NewDesc(
+		"liqo_peer_shaped_transmit_bytes_total",
+		"Number of bytes of a traffic class transmitted to a given peer.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsShapedTransmittedPackets is the metric that counts the number of packets of a traffic class transmitted to a given peer.
+	MetricsShapedTransmittedPackets = prometheus.NewDesc(
+		"liqo_peer_shaped_transmit_packets_total",
+		"Number of packets of a traffic class transmitted to a given peer.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsShapedDroppedPackets is the metric that counts the number of packets of a traffic class dropped by the shaper.
+	MetricsShapedDroppedPackets = prometheus.NewDesc(
+		"liqo_peer_shaped_dropped_packets_total",
+		"Number of packets of a traffic class towards a given peer dropped because exceeding the limits.",
+		metricsLabels,
+		nil,
+	)
+	// MetricsShapedOverlimits is the metric that counts the number of times a traffic class exceeded its rate.
+	MetricsShapedOverlimits = prometheus.NewDesc(
+		"liqo_peer_shaped_overlimits_total",
+		"Number of times a traffic class towards a given peer exceeded its rate, and packets got delayed.",
+		metricsLabels,
+		nil,
+	)
+)
+
+var _ prometheus.Collector = &PrometheusCollector{}
+
+// PrometheusCollector is a prometheus.Collector that collects the statistics of the traffic classes enforcing the bandwidth limit.
+type PrometheusCollector struct {
+	reconciler      *BandwidthLimitReconciler
+	remoteClusterID string
+}
+
+// NewPrometheusCollector creates a new PrometheusCollector.
+func NewPrometheusCollector(reconciler *BandwidthLimitReconciler, remoteClusterID string) *PrometheusCollector {
+	return &PrometheusCollector{
+		reconciler:      reconciler,
+		remoteClusterID: remoteClusterID,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (pc *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- MetricsShapedRate
+	ch <- MetricsShapedCeil
+	ch <- MetricsShapedTransmittedBytes
+	ch <- MetricsShapedTransmittedPackets
+	ch <- MetricsShapedDroppedPackets
+	ch <- MetricsShapedOverlimits
+}
+
+// Collect implements prometheus.Collector.
+func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	classes, linkIndex := pc.reconciler.AppliedClasses()
+	if len(classes) == 0 {
+		// No bandwidth limit is enforced.
+		return
+	}
+
+	link, err := netlink.LinkByIndex(linkIndex)
+	if err != nil {
+		pc.errorHandler(fmt.Errorf("error collecting shaping metrics: %w", err), ch)
+		return
+	}
+	stats, err := ClassStatistics(link)
+	if err != nil {
+		pc.errorHandler(fmt.Errorf("error collecting shaping metrics: %w", err), ch)
+		return
+	}
+
+	for i := range classes {
+		class := &classes[i]
+		labels := []string{pc.remoteClusterID, class.Name}
+		ch <- prometheus.MustNewConstMetric(MetricsShapedRate, prometheus.GaugeValue, float64(class.Rate), labels...)
+		ch <- prometheus.MustNewConstMetric(MetricsShapedCeil, prometheus.GaugeValue, float64(class.Ceil), labels...)
+
+		stat, found := stats[class.Minor]
+		if !found {
+			continue
+		}
+		if stat.Basic != nil {
+			ch <- prometheus.MustNewConstMetric(MetricsShapedTransmittedBytes, prometheus.CounterValue, float64(stat.Basic.Bytes), labels...)
+			ch <- prometheus.MustNewConstMetric(MetricsShapedTransmittedPackets, prometheus.CounterValue, float64(stat.Basic.Packets), labels...)
+		}
+		if stat.Queue != nil {
+			ch <- prometheus.MustNewConstMetric(MetricsShapedDroppedPackets, prometheus.CounterValue, float64(stat.Queue.Drops), labels...)
+			ch <- prometheus.MustNewConstMetric(MetricsShapedOverlimits, prometheus.CounterValue, float64(stat.Queue.Overlimits), labels...)
+		}
+	}
+}
+
+func (pc *PrometheusCollector) errorHandler(err error, ch chan<- prometheus.Metric) {
+	ch <- prometheus.NewInvalidMetric(MetricsShapedTransmittedBytes, err)
+	ch <- prometheus.NewInvalidMetric(MetricsShapedTransmittedPackets, err)
+	ch <- prometheus.NewInvalidMetric(MetricsShapedDroppedPackets, err)
+	ch <- prometheus.NewInvalidMetric(MetricsShapedOverlimits, err)
+}
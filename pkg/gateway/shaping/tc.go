@@ -0,0 +1,167 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaping
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ipv4FilterPriority uint16 = 1
+	ipv6FilterPriority uint16 = 2
+
+	// ipv4DSCPMask matches the DSCP bits of the TOS field, in the first 32-bit word of the IPv4 header.
+	ipv4DSCPMask  uint32 = 0x00fc0000
+	ipv4DSCPShift        = 18
+	// ipv6DSCPMask matches the DSCP bits of the traffic class field, in the first 32-bit word of the IPv6 header.
+	ipv6DSCPMask  uint32 = 0x0fc00000
+	ipv6DSCPShift        = 22
+)
+
+// Apply replaces the queueing discipline of the given link with the HTB hierarchy described by the given classes,
+// as returned by ForgeClasses.
+func Apply(link netlink.Link, classes []Class) error {
+	if err := Clear(link); err != nil {
+		return err
+	}
+
+	index := link.Attrs().Index
+	qdisc := netlink.NewHtb(netlink.QdiscAttrs{
+		LinkIndex: index,
+		Handle:    netlink.MakeHandle(rootMajor, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	})
+	qdisc.Defcls = uint32(defaultClassMinor)
+	if err := netlink.QdiscAdd(qdisc); err != nil {
+		return fmt.Errorf("unable to add the htb qdisc: %w", err)
+	}
+
+	for i := range classes {
+		class := &classes[i]
+		parent := netlink.MakeHandle(rootMajor, rootClassMinor)
+		if class.Minor == rootClassMinor {
+			parent = qdisc.Handle
+		}
+
+		htb := netlink.NewHtbClass(netlink.ClassAttrs{
+			LinkIndex: index,
+			Handle:    netlink.MakeHandle(rootMajor, class.Minor),
+			Parent:    parent,
+		}, netlink.HtbClassAttrs{
+			Rate:    class.Rate,
+			Ceil:    class.Ceil,
+			Buffer:  class.Burst,
+			Cbuffer: class.Burst,
+			Prio:    class.Priority,
+		})
+		if err := netlink.ClassAdd(htb); err != nil {
+			return fmt.Errorf("unable to add the htb class %q: %w", class.Name, err)
+		}
+
+		for _, filter := range forgeFilters(index, qdisc.Handle, htb.Handle, class.DSCP) {
+			if err := netlink.FilterAdd(filter); err != nil {
+				return fmt.Errorf("unable to add the filter of class %q: %w", class.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Clear removes the HTB queueing discipline from the given link, if present.
+func Clear(link netlink.Link) error {
+	qdisc, err := getRootQdisc(link)
+	if err != nil || qdisc == nil {
+		return err
+	}
+	if err := netlink.QdiscDel(qdisc); err != nil && !errors.Is(err, unix.ENOENT) {
+		return fmt.Errorf("unable to delete the htb qdisc: %w", err)
+	}
+	return nil
+}
+
+// IsApplied returns whether the HTB queueing discipline is attached to the given link.
+func IsApplied(link netlink.Link) (bool, error) {
+	qdisc, err := getRootQdisc(link)
+	return qdisc != nil, err
+}
+
+// ClassStatistics returns the statistics of the HTB classes attached to the given link, indexed by minor number.
+func ClassStatistics(link netlink.Link) (map[uint16]*netlink.ClassStatistics, error) {
+	classes, err := netlink.ClassList(link, netlink.MakeHandle(rootMajor, 0))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the classes: %w", err)
+	}
+
+	stats := make(map[uint16]*netlink.ClassStatistics, len(classes))
+	for _, class := range classes {
+		attrs := class.Attrs()
+		major, minor := netlink.MajorMinor(attrs.Handle)
+		if class.Type() != "htb" || major != rootMajor || attrs.Statistics == nil {
+			continue
+		}
+		stats[minor] = attrs.Statistics
+	}
+	return stats, nil
+}
+
+func getRootQdisc(link netlink.Link) (netlink.Qdisc, error) {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the qdiscs: %w", err)
+	}
+	for _, qdisc := range qdiscs {
+		attrs := qdisc.Attrs()
+		if qdisc.Type() == "htb" && attrs.Parent == netlink.HANDLE_ROOT && attrs.Handle == netlink.MakeHandle(rootMajor, 0) {
+			return qdisc, nil
+		}
+	}
+	return nil, nil
+}
+
+// forgeFilters returns the u32 filters enqueuing both the IPv4 and IPv6 packets with the given DSCP values into the given class.
+func forgeFilters(linkIndex int, parent, classID uint32, dscps []uint8) []netlink.Filter {
+	filters := make([]netlink.Filter, 0, 2*len(dscps))
+	for _, dscp := range dscps {
+		filters = append(filters,
+			forgeFilter(linkIndex, parent, classID, unix.ETH_P_IP, ipv4FilterPriority,
+				ipv4DSCPMask, uint32(dscp)<<ipv4DSCPShift),
+			forgeFilter(linkIndex, parent, classID, unix.ETH_P_IPV6, ipv6FilterPriority,
+				ipv6DSCPMask, uint32(dscp)<<ipv6DSCPShift),
+		)
+	}
+	return filters
+}
+
+func forgeFilter(linkIndex int, parent, classID uint32, protocol, priority uint16, mask, value uint32) *netlink.U32 {
+	return &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: linkIndex,
+			Parent:    parent,
+			Priority:  priority,
+			Protocol:  protocol,
+		},
+		ClassId: classID,
+		Sel: &nl.TcU32Sel{
+			Flags: nl.TC_U32_TERMINAL,
+			Keys:  []nl.TcU32Key{{Mask: mask, Val: value}},
+		},
+	}
+}
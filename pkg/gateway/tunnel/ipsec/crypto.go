@@ -0,0 +1,250 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+const (
+	// nonceLength is the length of the nonces exchanged during the handshake.
+	nonceLength = 32
+	// ephemeralKeyLength is the length of the ephemeral public keys exchanged during the handshake (uncompressed P-256 points).
+	ephemeralKeyLength = 65
+	// addressLength is the length of the encoded addresses, including the port.
+	addressLength = 16 + 2
+	// aeadKeyLength is the length of the keys of the security associations: 32 bytes for AES-256, plus 4 bytes of salt (RFC 4106).
+	aeadKeyLength = 36
+	// aeadICVLength is the length, in bits, of the integrity check value of the ESP packets.
+	aeadICVLength = 128
+	// aeadAlgorithm is the name of the AEAD algorithm used by the security associations.
+	aeadAlgorithm = "rfc4106(gcm(aes))"
+
+	hmacLength    = sha256.Size
+	messageLength = 4 + len(messageMagic) + 2 + 8 + 2*nonceLength + ephemeralKeyLength + addressLength + hmacLength
+
+	messageMagic   = "LQIS"
+	messageVersion = 2
+
+	hkdfInfoHello   = "liqo ipsec v2 hello"
+	hkdfInfoSession = "liqo ipsec v2 session"
+	hkdfInfoConfirm = "liqo ipsec v2 confirm"
+)
+
+// messageType is the type of a handshake message.
+type messageType uint8
+
+const (
+	// messageHello is sent by the client to start (or keep alive) a session.
+	messageHello messageType = 1
+	// messageHelloAck is sent by the server in response to a valid hello message.
+	messageHelloAck messageType = 2
+	// messageConfirm is sent by the client to confirm a new session, once its keys are derived.
+	messageConfirm messageType = 3
+	// messageConfirmAck is sent by the server in response to a valid confirmation.
+	messageConfirmAck messageType = 4
+)
+
+// nonce is a random value identifying one side of a session.
+type nonce [nonceLength]byte
+
+// ephemeralKey is the ephemeral public key of one side of a session.
+type ephemeralKey [ephemeralKeyLength]byte
+
+// message is a handshake message. Handshake messages are sent on the same UDP socket used for the
+// encapsulation of the ESP packets, prefixed by the non-ESP marker (RFC 3948), so that they are delivered
+// to userspace instead of being processed by the kernel.
+//
+// The hello messages and their acknowledgments are authenticated with a key derived from the static keys of
+// the two gateways, and carry the nonces and the ephemeral keys the session keys are derived from, hence
+// guaranteeing perfect forward secrecy. The confirmations and their acknowledgments are authenticated with a key
+// derived from the session keys, hence they cannot be forged or replayed by anybody not taking part in the session.
+type message struct {
+	Type      messageType
+	Timestamp time.Time
+
+	ClientNonce nonce
+	ServerNonce nonce
+	// EphemeralKey is the ephemeral public key of the sender, included in the hello messages and in their acknowledgments.
+	EphemeralKey ephemeralKey
+	// Address is the address of the client as observed by the server, included in the acknowledgments
+	// and echoed by the confirmations, so that the security associations are bound to it.
+	Address netip.AddrPort
+}
+
+// marshal encodes and authenticates the message.
+func (m *message) marshal(key []byte) []byte {
+	buf := make([]byte, 4, messageLength)
+	buf = append(buf, messageMagic...)
+	buf = append(buf, messageVersion, byte(m.Type))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(m.Timestamp.UnixNano())) //nolint:gosec // the timestamp is always positive
+	buf = append(buf, m.ClientNonce[:]...)
+	buf = append(buf, m.ServerNonce[:]...)
+	buf = append(buf, m.EphemeralKey[:]...)
+	addr := m.Address.Addr().As16()
+	buf = append(buf, addr[:]...)
+	buf = binary.BigEndian.AppendUint16(buf, m.Address.Port())
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(buf[4:])
+	return mac.Sum(buf)
+}
+
+// parseMessage decodes a message, checking its authenticity with the key returned by the given function,
+// depending on the content of the message. A nil key causes the message to be rejected.
+func parseMessage(buf []byte, key func(m *message) []byte) (*message, error) {
+	if len(buf) != messageLength || !bytes.Equal(buf[:4], make([]byte, 4)) || string(buf[4:4+len(messageMagic)]) != messageMagic {
+		return nil, errors.New("not a handshake message")
+	}
+	body := buf[4+len(messageMagic) : messageLength-hmacLength]
+	if body[0] != messageVersion {
+		return nil, fmt.Errorf("unsupported handshake version %d", body[0])
+	}
+
+	m := &message{
+		Type:      messageType(body[1]),
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(body[2:10]))), //nolint:gosec // overflows are rejected by the timestamp checks
+	}
+	body = body[10:]
+	body = body[copy(m.ClientNonce[:], body):]
+	body = body[copy(m.ServerNonce[:], body):]
+	body = body[copy(m.EphemeralKey[:], body):]
+	m.Address = netip.AddrPortFrom(netip.AddrFrom16([16]byte(body[:16])).Unmap(), binary.BigEndian.Uint16(body[16:]))
+
+	k := key(m)
+	if k == nil {
+		return nil, fmt.Errorf("unexpected handshake message of type %d", m.Type)
+	}
+	mac := hmac.New(sha256.New, k)
+	mac.Write(buf[4 : messageLength-hmacLength])
+	if !hmac.Equal(mac.Sum(nil), buf[messageLength-hmacLength:]) {
+		return nil, errors.New("invalid handshake message authentication code")
+	}
+	return m, nil
+}
+
+// sessionKeys contains the parameters of the security associations of a session.
+type sessionKeys struct {
+	OutSPI uint32
+	InSPI  uint32
+	OutKey []byte
+	InKey  []byte
+	// ConfirmKey is the key authenticating the confirmations of the session.
+	ConfirmKey []byte
+}
+
+// secrets contains the secrets derived from the static keys of the two gateways.
+type secrets struct {
+	// prk is the pseudorandom key mixed with the ephemeral secret of each session.
+	prk []byte
+	// helloKey is the key authenticating the hello messages and their acknowledgments.
+	helloKey []byte
+}
+
+// deriveSecrets derives the static secrets shared with the peer from the result of the ECDH key agreement,
+// mixed with the optional preshared key.
+func deriveSecrets(priv *ecdh.PrivateKey, peer *ecdh.PublicKey, psk []byte) (*secrets, error) {
+	shared, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+
+	prk, err := hkdf.Extract(sha256.New, shared, psk)
+	if err != nil {
+		return nil, err
+	}
+	helloKey, err := hkdf.Expand(sha256.New, prk, hkdfInfoHello, sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &secrets{prk: prk, helloKey: helloKey}, nil
+}
+
+// generateEphemeralKey generates the ephemeral key of one side of a session.
+func generateEphemeralKey() (*ecdh.PrivateKey, ephemeralKey, error) {
+	priv, err := GeneratePrivateKey()
+	if err != nil {
+		return nil, ephemeralKey{}, fmt.Errorf("cannot generate the ephemeral key: %w", err)
+	}
+	var pub ephemeralKey
+	copy(pub[:], priv.PublicKey().Bytes())
+	return priv, pub, nil
+}
+
+// deriveSessionKeys derives the keys and the SPIs of the security associations of the session identified by
+// the given nonces, from the ephemeral keys of the two sides mixed with the static secrets. Different keys
+// are used for the two directions, which are swapped depending on the mode.
+func (s *secrets) deriveSessionKeys(mode gateway.Mode, ephemeral *ecdh.PrivateKey, peerEphemeral *ephemeralKey,
+	clientNonce, serverNonce *nonce) (*sessionKeys, error) {
+	peer, err := ParsePublicKey(peerEphemeral[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("ephemeral key agreement failed: %w", err)
+	}
+	prk, err := hkdf.Extract(sha256.New, shared, s.prk)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 0, 2*nonceLength)
+	salt = append(salt, clientNonce[:]...)
+	salt = append(salt, serverNonce[:]...)
+
+	material, err := hkdf.Expand(sha256.New, prk, hkdfInfoSession+string(salt), 2*aeadKeyLength+8)
+	if err != nil {
+		return nil, err
+	}
+	confirmKey, err := hkdf.Expand(sha256.New, prk, hkdfInfoConfirm+string(salt), sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	toServer := &sessionKeys{
+		OutKey:     material[:aeadKeyLength],
+		InKey:      material[aeadKeyLength : 2*aeadKeyLength],
+		OutSPI:     spiFromBytes(material[2*aeadKeyLength:]),
+		InSPI:      spiFromBytes(material[2*aeadKeyLength+4:]),
+		ConfirmKey: confirmKey,
+	}
+	switch mode {
+	case gateway.ModeClient:
+		return toServer, nil
+	case gateway.ModeServer:
+		return &sessionKeys{OutKey: toServer.InKey, InKey: toServer.OutKey, OutSPI: toServer.InSPI, InSPI: toServer.OutSPI,
+			ConfirmKey: confirmKey}, nil
+	default:
+		return nil, fmt.Errorf("invalid mode %q", mode)
+	}
+}
+
+// spiFromBytes returns an SPI from the given bytes, skipping the range reserved by RFC 4303.
+func spiFromBytes(b []byte) uint32 {
+	const reserved = 256
+	return binary.BigEndian.Uint32(b)%(^uint32(0)-reserved+1) + reserved
+}
@@ -0,0 +1,221 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+var _ = Describe("Handshake cryptography", func() {
+	var (
+		clientKey, serverKey *ecdh.PrivateKey
+		clientNonce          nonce
+		serverNonce          nonce
+	)
+
+	BeforeEach(func() {
+		var err error
+		clientKey, err = GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		serverKey, err = GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = rand.Read(clientNonce[:])
+		Expect(err).ToNot(HaveOccurred())
+		_, err = rand.Read(serverNonce[:])
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("the handshake messages", func() {
+		var (
+			key []byte
+			msg *message
+		)
+
+		keyFor := func(key []byte) func(*message) []byte {
+			return func(*message) []byte { return key }
+		}
+
+		BeforeEach(func() {
+			s, err := deriveSecrets(clientKey, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			key = s.helloKey
+
+			msg = &message{
+				Type:        messageHelloAck,
+				Timestamp:   time.Unix(0, time.Now().UnixNano()),
+				ClientNonce: clientNonce,
+				ServerNonce: serverNonce,
+				Address:     netip.MustParseAddrPort("192.0.2.1:4500"),
+			}
+			_, msg.EphemeralKey, err = generateEphemeralKey()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should be correctly encoded and decoded", func() {
+			buf := msg.marshal(key)
+			Expect(buf).To(HaveLen(messageLength))
+			Expect(buf[:4]).To(Equal([]byte{0, 0, 0, 0}))
+
+			parsed, err := parseMessage(buf, keyFor(key))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Type).To(Equal(msg.Type))
+			Expect(parsed.Timestamp.Equal(msg.Timestamp)).To(BeTrue())
+			Expect(parsed.ClientNonce).To(Equal(msg.ClientNonce))
+			Expect(parsed.ServerNonce).To(Equal(msg.ServerNonce))
+			Expect(parsed.EphemeralKey).To(Equal(msg.EphemeralKey))
+			Expect(parsed.Address).To(Equal(msg.Address))
+		})
+
+		It("should reject tampered messages", func() {
+			buf := msg.marshal(key)
+			buf[12] ^= 0xff
+			_, err := parseMessage(buf, keyFor(key))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject messages authenticated with a different key", func() {
+			other, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			s, err := deriveSecrets(other, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = parseMessage(msg.marshal(s.helloKey), keyFor(key))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject the messages whose address was tampered", func() {
+			buf := msg.marshal(key)
+			buf[messageLength-hmacLength-1] ^= 0xff
+			_, err := parseMessage(buf, keyFor(key))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject messages without a key", func() {
+			_, err := parseMessage(msg.marshal(key), keyFor(nil))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject packets which are not handshake messages", func() {
+			_, err := parseMessage([]byte{0, 0, 0, 1, 0xde, 0xad}, keyFor(key))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the key derivation", func() {
+		var (
+			clientEphemeral, serverEphemeral       *ecdh.PrivateKey
+			clientEphemeralKey, serverEphemeralKey ephemeralKey
+		)
+
+		BeforeEach(func() {
+			var err error
+			clientEphemeral, clientEphemeralKey, err = generateEphemeralKey()
+			Expect(err).ToNot(HaveOccurred())
+			serverEphemeral, serverEphemeralKey, err = generateEphemeralKey()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should derive matching keys on the two sides", func() {
+			cs, err := deriveSecrets(clientKey, serverKey.PublicKey(), []byte("psk"))
+			Expect(err).ToNot(HaveOccurred())
+			ss, err := deriveSecrets(serverKey, clientKey.PublicKey(), []byte("psk"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cs.helloKey).To(Equal(ss.helloKey))
+
+			ck, err := cs.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &serverEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+			sk, err := ss.deriveSessionKeys(gateway.ModeServer, serverEphemeral, &clientEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ck.ConfirmKey).To(Equal(sk.ConfirmKey))
+			Expect(ck.ConfirmKey).ToNot(Equal(cs.helloKey))
+
+			Expect(ck.OutKey).To(HaveLen(aeadKeyLength))
+			Expect(ck.OutKey).To(Equal(sk.InKey))
+			Expect(ck.InKey).To(Equal(sk.OutKey))
+			Expect(ck.OutKey).ToNot(Equal(ck.InKey))
+			Expect(ck.OutSPI).To(Equal(sk.InSPI))
+			Expect(ck.InSPI).To(Equal(sk.OutSPI))
+			Expect(ck.OutSPI).To(BeNumerically(">=", 256))
+			Expect(ck.InSPI).To(BeNumerically(">=", 256))
+		})
+
+		It("should derive different keys for different sessions", func() {
+			s, err := deriveSecrets(clientKey, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			first, err := s.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &serverEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+			serverNonce[0] ^= 0xff
+			second, err := s.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &serverEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(first.OutKey).ToNot(Equal(second.OutKey))
+			Expect(first.InKey).ToNot(Equal(second.InKey))
+		})
+
+		It("should derive different keys for different ephemeral keys", func() {
+			s, err := deriveSecrets(clientKey, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			first, err := s.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &serverEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+			_, otherEphemeralKey, err := generateEphemeralKey()
+			Expect(err).ToNot(HaveOccurred())
+			second, err := s.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &otherEphemeralKey, &clientNonce, &serverNonce)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(first.OutKey).ToNot(Equal(second.OutKey))
+			Expect(first.ConfirmKey).ToNot(Equal(second.ConfirmKey))
+		})
+
+		It("should reject invalid ephemeral keys", func() {
+			s, err := deriveSecrets(clientKey, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = s.deriveSessionKeys(gateway.ModeClient, clientEphemeral, &ephemeralKey{}, &clientNonce, &serverNonce)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should derive different secrets if the preshared keys do not match", func() {
+			cs, err := deriveSecrets(clientKey, serverKey.PublicKey(), []byte("psk"))
+			Expect(err).ToNot(HaveOccurred())
+			ss, err := deriveSecrets(serverKey, clientKey.PublicKey(), []byte("other"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cs.helloKey).ToNot(Equal(ss.helloKey))
+		})
+	})
+
+	Describe("the public keys", func() {
+		It("should be parsed correctly", func() {
+			pub, err := ParsePublicKey(clientKey.PublicKey().Bytes())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pub.Equal(clientKey.PublicKey())).To(BeTrue())
+		})
+
+		It("should reject invalid keys", func() {
+			_, err := ParsePublicKey([]byte("invalid"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,19 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipsec contains the implementation of the IPsec tunnel, based on the kernel XFRM framework.
+// The handshake messages are authenticated with the ECDH keys exchanged through the PublicKey resources,
+// while the security associations are derived from ephemeral keys, and periodically renegotiated by the client.
+// The handshake is Liqo-specific, and not IKEv2, hence the key management is not covered by any FIPS validation.
+package ipsec
@@ -0,0 +1,144 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// endpointCandidate is a candidate IP address of the server, together with the advertised address it originates from.
+type endpointCandidate struct {
+	address string
+	ip      net.IP
+}
+
+// endpointResolver resolves the advertised addresses of the server, and selects the one the client connects to.
+// The IPsec tunnel supports IPv4 endpoints only, hence the IPv6 addresses are discarded. The DNS names are periodically
+// re-resolved, and the client fails over to the next address whenever the server does not acknowledge the hello messages
+// for longer than the failover timeout.
+type endpointResolver struct {
+	options *Options
+
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+	now      func() time.Time
+
+	candidates []endpointCandidate
+	active     int
+	// since is the last time the active candidate has been selected, or acknowledged the hello messages.
+	since      time.Time
+	resolvedAt time.Time
+}
+
+func newEndpointResolver(options *Options) *endpointResolver {
+	return &endpointResolver{
+		options: options,
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+		now: time.Now,
+	}
+}
+
+// CheckEndpointAddresses checks that at least one of the given endpoint addresses may resolve to an IPv4 address,
+// since the IPsec tunnel does not support IPv6 endpoints.
+func CheckEndpointAddresses(addresses []string) error {
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip == nil || ip.To4() != nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("the IPsec tunnel does not support IPv6 endpoints, while all the endpoint addresses %v are IPv6", addresses)
+}
+
+// resolve returns the address of the server the client connects to, given the last time the server acknowledged
+// the hello messages, failing over to the next candidate if needed.
+func (r *endpointResolver) resolve(ctx context.Context, acknowledged time.Time) (*net.UDPAddr, error) {
+	if len(r.candidates) == 0 || r.now().Sub(r.resolvedAt) >= r.options.DNSCheckInterval {
+		r.refresh(ctx)
+	}
+	if len(r.candidates) == 0 {
+		return nil, fmt.Errorf("no IPv4 address found for the endpoint addresses %v (IPv6 endpoints are not supported)",
+			r.options.EndpointAddresses)
+	}
+
+	if acknowledged.After(r.since) {
+		r.since = acknowledged
+	}
+	if len(r.candidates) > 1 && r.now().Sub(r.since) >= r.options.EndpointFailoverTimeout {
+		next := (r.active + 1) % len(r.candidates)
+		klog.Warningf("Endpoint %s (%s) not acknowledging the hello messages for %s: failing over to %s (%s)",
+			r.candidates[r.active].ip, r.candidates[r.active].address, r.options.EndpointFailoverTimeout,
+			r.candidates[next].ip, r.candidates[next].address)
+		r.activate(next)
+	}
+
+	return &net.UDPAddr{IP: r.candidates[r.active].ip, Port: r.options.EndpointPort}, nil
+}
+
+// refresh resolves the advertised addresses, updating the candidates, and preserving the active one if still present.
+// The current candidates are kept if none is found, so that a transient resolution error does not interrupt the tunnel.
+func (r *endpointResolver) refresh(ctx context.Context) {
+	var candidates []endpointCandidate
+	seen := map[string]struct{}{}
+	for _, address := range r.options.EndpointAddresses {
+		ips := []net.IP{net.ParseIP(address)}
+		if ips[0] == nil {
+			var err error
+			if ips, err = r.lookupIP(ctx, address); err != nil {
+				klog.Warningf("Unable to resolve the endpoint address %q: %v", address, err)
+				continue
+			}
+		}
+
+		for _, ip := range ips {
+			if ip.To4() == nil {
+				klog.Warningf("Skipping the endpoint IP %s (%s), as IPv6 endpoints are not supported by the IPsec tunnel", ip, address)
+				continue
+			}
+			if _, ok := seen[ip.String()]; ok {
+				continue
+			}
+			seen[ip.String()] = struct{}{}
+			candidates = append(candidates, endpointCandidate{address: address, ip: ip.To4()})
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	r.resolvedAt = r.now()
+
+	if len(r.candidates) > 0 {
+		current := r.candidates[r.active]
+		for i := range candidates {
+			if candidates[i].ip.Equal(current.ip) {
+				r.candidates, r.active = candidates, i
+				return
+			}
+		}
+	}
+	r.candidates = candidates
+	r.activate(0)
+}
+
+// activate selects the given candidate.
+func (r *endpointResolver) activate(index int) {
+	r.active, r.since = index, r.now()
+	klog.Infof("Endpoint address %q: using IP %s", r.candidates[index].address, r.candidates[index].ip)
+}
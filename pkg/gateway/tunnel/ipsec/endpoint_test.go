@@ -0,0 +1,147 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoint resolution", func() {
+	var (
+		ctx      context.Context
+		options  *Options
+		resolver *endpointResolver
+		records  map[string][]net.IP
+		now      time.Time
+	)
+
+	ips := func(addresses ...string) []net.IP {
+		res := make([]net.IP, len(addresses))
+		for i := range addresses {
+			res[i] = net.ParseIP(addresses[i])
+		}
+		return res
+	}
+
+	resolve := func(acknowledged time.Time) string {
+		endpoint, err := resolver.resolve(ctx, acknowledged)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoint.Port).To(Equal(4500))
+		return endpoint.IP.String()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Unix(1700000000, 0)
+		records = map[string][]net.IP{
+			"gw.example.com":  ips("2001:db8::1", "192.0.2.1", "192.0.2.2"),
+			"gw6.example.com": ips("2001:db8::3"),
+		}
+		options = &Options{
+			EndpointPort:            4500,
+			DNSCheckInterval:        5 * time.Minute,
+			EndpointFailoverTimeout: time.Minute,
+		}
+		resolver = newEndpointResolver(options)
+		resolver.now = func() time.Time { return now }
+		resolver.lookupIP = func(_ context.Context, host string) ([]net.IP, error) {
+			if res, ok := records[host]; ok {
+				return res, nil
+			}
+			return nil, fmt.Errorf("no such host %q", host)
+		}
+	})
+
+	DescribeTable("the candidate addresses",
+		func(addresses, expected []string) {
+			options.EndpointAddresses = addresses
+			Expect(resolver.resolve(ctx, time.Time{})).ToNot(BeNil())
+			var res []string
+			for i := range resolver.candidates {
+				res = append(res, resolver.candidates[i].ip.String())
+			}
+			Expect(res).To(Equal(expected))
+		},
+		Entry("an IPv4 address", []string{"198.51.100.1"}, []string{"198.51.100.1"}),
+		Entry("a DNS name, skipping the IPv6 addresses", []string{"gw.example.com"}, []string{"192.0.2.1", "192.0.2.2"}),
+		Entry("multiple addresses, skipping the IPv6 and unresolvable ones",
+			[]string{"2001:db8::4", "unknown.example.com", "gw6.example.com", "198.51.100.1", "gw.example.com"},
+			[]string{"198.51.100.1", "192.0.2.1", "192.0.2.2"}),
+		Entry("duplicate addresses", []string{"192.0.2.2", "gw.example.com"}, []string{"192.0.2.2", "192.0.2.1"}),
+	)
+
+	It("should fail if no IPv4 address is found", func() {
+		options.EndpointAddresses = []string{"2001:db8::4", "gw6.example.com"}
+		_, err := resolver.resolve(ctx, time.Time{})
+		Expect(err).To(MatchError(ContainSubstring("IPv6 endpoints are not supported")))
+	})
+
+	It("should fail over to the next address when the server does not acknowledge the hello messages", func() {
+		options.EndpointAddresses = []string{"gw.example.com", "198.51.100.1"}
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.1"))
+
+		now = now.Add(59 * time.Second)
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.1"))
+		now = now.Add(time.Second)
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.2"))
+		now = now.Add(time.Minute)
+		Expect(resolve(time.Time{})).To(Equal("198.51.100.1"))
+		now = now.Add(time.Minute)
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.1"))
+	})
+
+	It("should not fail over while the server acknowledges the hello messages", func() {
+		options.EndpointAddresses = []string{"gw.example.com"}
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.1"))
+
+		for range 10 {
+			now = now.Add(30 * time.Second)
+			Expect(resolve(now.Add(-time.Second))).To(Equal("192.0.2.1"))
+		}
+	})
+
+	It("should preserve the active address across the DNS checks", func() {
+		options.EndpointAddresses = []string{"gw.example.com"}
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.1"))
+		now = now.Add(time.Minute)
+		Expect(resolve(time.Time{})).To(Equal("192.0.2.2"))
+
+		records["gw.example.com"] = ips("192.0.2.3", "192.0.2.2")
+		now = now.Add(5 * time.Minute)
+		Expect(resolve(now)).To(Equal("192.0.2.2"))
+		Expect(resolver.candidates).To(HaveLen(2))
+	})
+
+	DescribeTable("CheckEndpointAddresses",
+		func(addresses []string, expectErr bool) {
+			err := CheckEndpointAddresses(addresses)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("an IPv4 address", []string{"192.0.2.1"}, false),
+		Entry("a DNS name", []string{"gw.example.com"}, false),
+		Entry("mixed addresses", []string{"2001:db8::1", "192.0.2.1"}, false),
+		Entry("only IPv6 addresses", []string{"2001:db8::1", "2001:db8::2"}, true),
+	)
+})
@@ -0,0 +1,89 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/forge"
+)
+
+// FlagName is the type for the name of the flags.
+type FlagName string
+
+func (fn FlagName) String() string {
+	return string(fn)
+}
+
+const (
+	// FlagNameMTU is the MTU for the IPsec interface.
+	FlagNameMTU FlagName = "mtu"
+	// FlagNameListenPort is the local port used for the UDP encapsulation of the ESP packets.
+	FlagNameListenPort FlagName = "listen-port"
+	// FlagNameEndpointAddress is the list of the addresses of the remote IPsec server.
+	FlagNameEndpointAddress FlagName = "endpoint-address"
+	// FlagNameEndpointPort is the port of the remote IPsec server.
+	FlagNameEndpointPort FlagName = "endpoint-port"
+	// FlagNameKeysDir is the directory where the keys are stored.
+	FlagNameKeysDir FlagName = "keys-dir"
+	// FlagNameKeepaliveInterval is the interval between two handshake messages sent by the client.
+	FlagNameKeepaliveInterval FlagName = "keepalive-interval"
+	// FlagNameRekeyInterval is the lifetime of the keys of the security associations.
+	FlagNameRekeyInterval FlagName = "rekey-interval"
+
+	// FlagNameDNSCheckInterval is the interval between two DNS checks.
+	FlagNameDNSCheckInterval FlagName = "dns-check-interval"
+	// FlagNameEndpointFailoverTimeout is the time after which the client fails over to another endpoint address.
+	FlagNameEndpointFailoverTimeout FlagName = "endpoint-failover-timeout"
+)
+
+// ClientRequiredFlags contains the list of the mandatory flags for the client mode.
+var ClientRequiredFlags = []FlagName{
+	FlagNameEndpointAddress,
+}
+
+// InitFlags initializes the flags for the IPsec tunnel.
+func InitFlags(flagset *pflag.FlagSet, opts *Options) {
+	flagset.IntVar(&opts.MTU, FlagNameMTU.String(), forge.DefaultMTU, "MTU for the interface")
+	flagset.IntVar(&opts.ListenPort, FlagNameListenPort.String(), forge.DefaultGwServerPort, "Local port for the UDP encapsulation")
+	flagset.StringSliceVar(&opts.EndpointAddresses, FlagNameEndpointAddress.String(), nil,
+		"Endpoint addresses, tried in order until the server acknowledges the handshake. IPv6 addresses are not supported (client only)")
+	flagset.IntVar(&opts.EndpointPort, FlagNameEndpointPort.String(), forge.DefaultGwServerPort, "Endpoint port (client only)")
+	flagset.StringVar(&opts.KeysDir, FlagNameKeysDir.String(), forge.DefaultKeysDir, "Directory where the keys are stored")
+	flagset.DurationVar(&opts.KeepaliveInterval, FlagNameKeepaliveInterval.String(), 10*time.Second,
+		"Interval between two handshake messages, which also keep the NAT mappings alive (client only)")
+	flagset.DurationVar(&opts.RekeyInterval, FlagNameRekeyInterval.String(), time.Hour,
+		"Lifetime of the keys of the security associations, which are renegotiated by the client when it elapses")
+
+	flagset.DurationVar(&opts.DNSCheckInterval, FlagNameDNSCheckInterval.String(), 5*time.Minute, "Interval between two DNS checks (client only)")
+	flagset.DurationVar(&opts.EndpointFailoverTimeout, FlagNameEndpointFailoverTimeout.String(), time.Minute,
+		"Time after which the client fails over to the next endpoint address, if the server does not acknowledge the handshake (client only)")
+}
+
+// MarkFlagsRequired marks the flags as required.
+func MarkFlagsRequired(cmd *cobra.Command, opts *Options) error {
+	if opts.GwOptions.Mode == gateway.ModeClient {
+		for _, flag := range ClientRequiredFlags {
+			if err := cmd.MarkFlagRequired(flag.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
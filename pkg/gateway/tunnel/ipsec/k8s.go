@@ -0,0 +1,64 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"crypto/ecdh"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/forge"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// CreateKeysSecret saves the given private key, and the corresponding public key, inside a Secret resource.
+func CreateKeysSecret(ctx context.Context, cl client.Client, opts *gateway.Options, pri *ecdh.PrivateKey) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      forge.GatewayResourceName(opts.Name),
+			Namespace: opts.Namespace,
+		},
+	}
+
+	_, err := resource.CreateOrUpdate(ctx, cl, secret, func() error {
+		secret.SetLabels(map[string]string{
+			string(consts.RemoteClusterID):      opts.RemoteClusterID,
+			string(consts.GatewayResourceLabel): string(consts.GatewayResourceLabelValue),
+		})
+		if err := gateway.SetOwnerReferenceWithMode(opts, secret, cl.Scheme()); err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			consts.PrivateKeyField: pri.Bytes(),
+			consts.PublicKeyField:  pri.PublicKey().Bytes(),
+		}
+		return nil
+	})
+	return err
+}
+
+// EnsureConnection creates or updates the connection resource.
+func EnsureConnection(ctx context.Context, cl client.Client, scheme *runtime.Scheme, opts *Options) error {
+	return tunnel.EnsureConnection(ctx, cl, scheme, opts.GwOptions,
+		networkingv1beta1.IPsecGatewayServerKind, networkingv1beta1.IPsecGatewayClientKind)
+}
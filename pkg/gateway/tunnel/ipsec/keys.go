@@ -0,0 +1,69 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// curve is the elliptic curve used for the ECDH key agreement.
+// P-256 is used, rather than X25519, since it is a FIPS-approved curve. Still, the handshake built on top of it
+// is Liqo-specific, hence the resulting key management is not FIPS-validated.
+var curve = ecdh.P256()
+
+// GeneratePrivateKey generates a new private key.
+func GeneratePrivateKey() (*ecdh.PrivateKey, error) {
+	return curve.GenerateKey(rand.Reader)
+}
+
+// ParsePublicKey parses a public key, encoded in the uncompressed form.
+func ParsePublicKey(key []byte) (*ecdh.PublicKey, error) {
+	pub, err := curve.NewPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return pub, nil
+}
+
+// LoadKeys loads the private key, and the optional preshared key, from the specified directory.
+func LoadKeys(options *Options) error {
+	priv, err := os.ReadFile(filepath.Clean(filepath.Join(options.KeysDir, consts.PrivateKeyField)))
+	if err != nil {
+		return err
+	}
+
+	options.PrivateKey, err = curve.NewPrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+
+	psk, err := os.ReadFile(filepath.Clean(filepath.Join(options.KeysDir, consts.PresharedKeyField)))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		options.PresharedKey = nil
+	case err != nil:
+		return err
+	default:
+		options.PresharedKey = psk
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+const (
+	driverLabelValue = "ipsec"
+)
+
+var _ prometheus.Collector = &PrometheusCollector{}
+
+// PrometheusCollector is a prometheus.Collector that collects IPsec metrics.
+type PrometheusCollector struct {
+	tunnelMetrics tunnel.PrometheusMetrics
+	tunnel        *Tunnel
+	clientctrl    client.Client
+
+	metricsOptions *MetricsOptions
+}
+
+// MetricsOptions contains the options for the PrometheusCollector.
+type MetricsOptions struct {
+	RemoteClusterID string
+	Namespace       string
+}
+
+// NewPrometheusCollector creates a new PrometheusCollector.
+func NewPrometheusCollector(clctrl client.Client, t *Tunnel, metricsOpts *MetricsOptions) *PrometheusCollector {
+	return &PrometheusCollector{
+		tunnelMetrics:  tunnel.PrometheusMetrics{},
+		tunnel:         t,
+		clientctrl:     clctrl,
+		metricsOptions: metricsOpts,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (pc *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	pc.tunnelMetrics.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	outSPI, inSPI, ok := pc.tunnel.securityParameterIndexes()
+	if !ok {
+		pc.tunnelMetrics.MetricsErrorHandler(fmt.Errorf("error collecting IPsec metrics: session not established"), ch)
+		return
+	}
+
+	states, err := listSecurityAssociations(pc.tunnel.handle)
+	if err != nil {
+		pc.tunnelMetrics.MetricsErrorHandler(fmt.Errorf("error collecting IPsec metrics: %w", err), ch)
+		return
+	}
+
+	var rx, tx uint64
+	for i := range states {
+		switch uint32(states[i].Spi) { //nolint:gosec // SPIs are unsigned 32-bit values
+		case outSPI:
+			tx = states[i].Statistics.Bytes
+		case inSPI:
+			rx = states[i].Statistics.Bytes
+		}
+	}
+
+	labels := []string{driverLabelValue, pc.metricsOptions.RemoteClusterID}
+
+	ctx := context.WithoutCancel(context.Background())
+	conn, err := getters.GetConnectionByClusterIDInNamespace(ctx, pc.clientctrl,
+		pc.metricsOptions.RemoteClusterID, pc.metricsOptions.Namespace)
+	if err != nil {
+		pc.tunnelMetrics.MetricsErrorHandler(fmt.Errorf("error collecting IPsec metrics: %w", err), ch)
+		return
+	}
+
+	connected := conn.Status.Value == networkingv1beta1.Connected
+	var result float64
+	if connected {
+		result = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		tunnel.MetricsPeerIsConnected,
+		prometheus.GaugeValue,
+		result,
+		labels...,
+	)
+
+	if connected {
+		ch <- prometheus.MustNewConstMetric(
+			tunnel.MetricsPeerReceivedBytes,
+			prometheus.CounterValue,
+			float64(rx),
+			labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			tunnel.MetricsPeerTransmittedBytes,
+			prometheus.CounterValue,
+			float64(tx),
+			labels...,
+		)
+
+		latency, err := time.ParseDuration(conn.Status.Latency.Value)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(tunnel.MetricsPeerLatency, err)
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(
+			tunnel.MetricsPeerLatency,
+			prometheus.GaugeValue,
+			float64(latency.Microseconds()),
+			labels...,
+		)
+	}
+}
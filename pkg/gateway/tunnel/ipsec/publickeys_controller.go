@@ -0,0 +1,92 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=publickeies,verbs=get;list;create;delete;update;watch
+
+// PublicKeysReconciler configures the tunnel with the PublicKey of the remote gateway.
+type PublicKeysReconciler struct {
+	Client         client.Client
+	Scheme         *runtime.Scheme
+	EventsRecorder record.EventRecorder
+	Options        *Options
+	Tunnel         *Tunnel
+}
+
+// NewPublicKeysReconciler returns a new PublicKeysReconciler.
+func NewPublicKeysReconciler(cl client.Client, s *runtime.Scheme, er record.EventRecorder, options *Options, tunnel *Tunnel) *PublicKeysReconciler {
+	return &PublicKeysReconciler{
+		Client:         cl,
+		Scheme:         s,
+		EventsRecorder: er,
+		Options:        options,
+		Tunnel:         tunnel,
+	}
+}
+
+// Reconcile manage PublicKey resources.
+func (r *PublicKeysReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	publicKey := &networkingv1beta1.PublicKey{}
+	if err := r.Client.Get(ctx, req.NamespacedName, publicKey); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("There is no publicKey %s", req.String())
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to get the publicKey %q: %w", req.NamespacedName, err)
+	}
+
+	if err := r.Tunnel.SetPeerPublicKey(publicKey.Spec.PublicKey); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to configure the publicKey %q: %w", req.NamespacedName, err)
+	}
+
+	return ctrl.Result{}, EnsureConnection(ctx, r.Client, r.Scheme, r.Options)
+}
+
+// SetupWithManager register the PublicKeysReconciler to the manager.
+func (r *PublicKeysReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPublicKey).
+		For(&networkingv1beta1.PublicKey{}, r.Predicates()).
+		Complete(r)
+}
+
+// Predicates returns the predicates required for the PublicKey controller.
+func (r *PublicKeysReconciler) Predicates() builder.Predicates {
+	return builder.WithPredicates(
+		predicate.NewPredicateFuncs(func(object client.Object) bool {
+			id, ok := object.GetLabels()[string(consts.RemoteClusterID)]
+			if !ok {
+				return false
+			}
+			return id == r.Options.GwOptions.RemoteClusterID
+		}))
+}
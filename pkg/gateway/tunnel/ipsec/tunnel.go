@@ -0,0 +1,611 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+const (
+	// maxClockSkew is the maximum difference between the timestamp of a hello message and the local time.
+	maxClockSkew = 5 * time.Minute
+	// maxConfirmDelay is the maximum time between an acknowledgment sent by the server and the corresponding confirmation.
+	maxConfirmDelay = time.Minute
+	// retryInterval is the interval between two hello messages while the session is not established.
+	retryInterval = time.Second
+	// retireDelay is the time the inbound security association of a replaced session is kept,
+	// so that the packets sent by the peer before switching to the new session are still accepted.
+	retireDelay = 30 * time.Second
+)
+
+var _ manager.Runnable = &Tunnel{}
+
+// session contains the state of a session with the peer.
+type session struct {
+	clientNonce nonce
+	// serverNonce is the zero value on the client, until the hello message is acknowledged by the server.
+	serverNonce nonce
+	// ephemeral is the ephemeral private key, which is discarded as soon as the session keys are derived.
+	ephemeral *ecdh.PrivateKey
+	// ephemeralKey is the ephemeral public key sent to the peer.
+	ephemeralKey ephemeralKey
+	// keys is nil on the client, until the hello message is acknowledged by the server.
+	keys *sessionKeys
+	// endpoints is nil until the security associations are configured.
+	endpoints *endpoints
+	// established is the time the session replaced the previous one.
+	established time.Time
+
+	// helloTimestamp is the timestamp of the hello message which started the session (server only).
+	helloTimestamp time.Time
+	// ackTimestamp is the timestamp of the last acknowledgment received from the server (client only).
+	ackTimestamp time.Time
+	// address is the address of the client as observed by the server in the last acknowledgment (client only).
+	address netip.AddrPort
+}
+
+// matches returns whether the session is identified by the nonces of the given message.
+func (s *session) matches(msg *message) bool {
+	return s != nil && s.keys != nil && s.clientNonce == msg.ClientNonce && s.serverNonce == msg.ServerNonce
+}
+
+// Tunnel manages the IPsec tunnel with the remote gateway. The client periodically sends a hello message to the server,
+// which replies with an acknowledgment. Both messages carry a nonce and an ephemeral key, which the keys of a new session
+// are derived from. The client then configures the inbound security association, and confirms the session, echoing the
+// address observed by the server. The server configures the security associations towards the confirmed address only,
+// so that the tunnel cannot be redirected by replaying the messages, and acknowledges the confirmation. Finally, the client
+// configures the outbound security association. The client periodically renegotiates the session, which replaces the
+// previous one without interrupting the traffic, and the security associations expire if not renegotiated in time.
+// The hello messages also allow the server to learn the address of the client, possibly behind a NAT,
+// and keep the NAT mappings alive.
+type Tunnel struct {
+	options *Options
+	handle  *netlink.Handle
+	conn    *net.UDPConn
+	wakeup  chan struct{}
+
+	mutex   sync.Mutex
+	peerKey []byte
+	secrets *secrets
+	// current is the established session, if any.
+	current *session
+	// pending is the session being negotiated, if any, which replaces the current one once confirmed.
+	pending *session
+	// endpoint is the address of the server, as resolved by the client.
+	endpoint *net.UDPAddr
+	// acknowledged is the last time the server acknowledged a hello message (client only).
+	acknowledged time.Time
+}
+
+// NewTunnel returns a new Tunnel, configuring the security associations through the given netlink handle.
+func NewTunnel(options *Options, handle *netlink.Handle) *Tunnel {
+	return &Tunnel{
+		options: options,
+		handle:  handle,
+		wakeup:  make(chan struct{}, 1),
+	}
+}
+
+// Listen opens the UDP socket used to exchange the handshake messages and to receive the encapsulated ESP packets.
+// The socket is created in the network namespace of the calling thread. The IPsec tunnel supports IPv4 endpoints only,
+// hence the socket is bound to the IPv4 addresses.
+func (t *Tunnel) Listen() error {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: t.options.ListenPort})
+	if err != nil {
+		return fmt.Errorf("cannot listen on UDP port %d: %w", t.options.ListenPort, err)
+	}
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_UDP, unix.UDP_ENCAP, unix.UDP_ENCAP_ESPINUDP)
+	}); err != nil || sockErr != nil {
+		conn.Close()
+		return fmt.Errorf("cannot enable the ESP-in-UDP encapsulation: %w", errors.Join(err, sockErr))
+	}
+
+	t.conn = conn
+	return nil
+}
+
+// SetPeerPublicKey configures the public key of the peer. If the key changes, the current session is torn down.
+func (t *Tunnel) SetPeerPublicKey(key []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.secrets != nil && bytes.Equal(t.peerKey, key) {
+		return nil
+	}
+
+	peer, err := ParsePublicKey(key)
+	if err != nil {
+		return err
+	}
+	secrets, err := deriveSecrets(t.options.PrivateKey, peer, t.options.PresharedKey)
+	if err != nil {
+		return err
+	}
+
+	klog.Info("Peer public key configured")
+	t.peerKey = bytes.Clone(key)
+	t.secrets = secrets
+	t.resetSessions()
+	t.wake()
+	return nil
+}
+
+// Established returns whether the security associations of a session are configured.
+func (t *Tunnel) Established() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.current != nil
+}
+
+// Start runs the tunnel until the given context is canceled. Listen must be called beforehand.
+func (t *Tunnel) Start(ctx context.Context) error {
+	if t.conn == nil {
+		return errors.New("the tunnel socket is not open")
+	}
+
+	go func() {
+		<-ctx.Done()
+		t.conn.Close()
+	}()
+
+	if t.options.GwOptions.Mode == gateway.ModeClient {
+		go t.runClient(ctx)
+	}
+
+	buf := make([]byte, messageLength+1)
+	for {
+		n, from, err := t.conn.ReadFromUDP(buf)
+		switch {
+		case errors.Is(err, net.ErrClosed):
+			return nil
+		case err != nil:
+			klog.Errorf("Unable to read from the tunnel socket: %v", err)
+			continue
+		}
+		t.handleMessage(buf[:n], from)
+	}
+}
+
+// runClient periodically sends the hello messages to the server.
+func (t *Tunnel) runClient(ctx context.Context) {
+	resolver := newEndpointResolver(t.options)
+	for {
+		endpoint, err := resolver.resolve(ctx, t.lastAcknowledged())
+		if err != nil {
+			klog.Errorf("Unable to resolve the endpoint addresses: %v", err)
+		}
+
+		interval := t.options.KeepaliveInterval
+		if negotiating := t.sendHello(endpoint); negotiating {
+			interval = min(interval, retryInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.wakeup:
+		case <-time.After(interval):
+		}
+	}
+}
+
+// lastAcknowledged returns the last time the server acknowledged a hello message.
+func (t *Tunnel) lastAcknowledged() time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.acknowledged
+}
+
+// sendHello sends a hello message to the given endpoint, starting a new session if none is established, or the current one
+// needs to be renegotiated. It returns whether a session is being negotiated.
+func (t *Tunnel) sendHello(endpoint *net.UDPAddr) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if endpoint == nil {
+		return true
+	}
+	if t.endpoint != nil && !t.endpoint.IP.Equal(endpoint.IP) {
+		klog.Infof("Endpoint address changed from %s to %s", t.endpoint, endpoint)
+		t.resetSessions()
+	}
+	t.endpoint = endpoint
+
+	if t.secrets == nil {
+		klog.V(4).Info("Peer public key not yet available, waiting")
+		return true
+	}
+
+	if t.pending == nil && (t.current == nil || time.Since(t.current.established) >= t.options.RekeyInterval) {
+		if t.current != nil {
+			klog.Infof("Renegotiating the IPsec session with %s", endpoint)
+		}
+		s, err := newClientSession()
+		if err != nil {
+			klog.Errorf("Unable to start a new IPsec session: %v", err)
+			return true
+		}
+		t.pending = s
+	}
+
+	s := t.current
+	if t.pending != nil {
+		s = t.pending
+	}
+	hello := message{Type: messageHello, Timestamp: time.Now(), ClientNonce: s.clientNonce, EphemeralKey: s.ephemeralKey}
+	t.send(&hello, endpoint, t.secrets.helloKey)
+	return t.pending != nil
+}
+
+// handleMessage processes a message received on the tunnel socket.
+func (t *Tunnel) handleMessage(buf []byte, from *net.UDPAddr) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.secrets == nil {
+		klog.V(4).Infof("Ignoring message from %s: peer public key not yet available", from)
+		return
+	}
+
+	msg, err := parseMessage(buf, t.messageKey)
+	if err != nil {
+		klog.V(4).Infof("Ignoring message from %s: %v", from, err)
+		return
+	}
+
+	switch mode := t.options.GwOptions.Mode; {
+	case mode == gateway.ModeServer && msg.Type == messageHello:
+		t.handleHello(msg, from)
+	case mode == gateway.ModeServer && msg.Type == messageConfirm:
+		t.handleConfirm(msg, from)
+	case mode == gateway.ModeClient && msg.Type == messageHelloAck:
+		t.handleHelloAck(msg)
+	case mode == gateway.ModeClient && msg.Type == messageConfirmAck:
+		t.handleConfirmAck(msg)
+	default:
+		klog.V(4).Infof("Ignoring unexpected message of type %d from %s", msg.Type, from)
+	}
+}
+
+// messageKey returns the key authenticating the given message: the static one for the hello messages
+// and their acknowledgments, and the one of the corresponding session for the confirmations.
+func (t *Tunnel) messageKey(msg *message) []byte {
+	switch msg.Type {
+	case messageHello, messageHelloAck:
+		return t.secrets.helloKey
+	case messageConfirm, messageConfirmAck:
+		for _, s := range []*session{t.pending, t.current} {
+			if s.matches(msg) {
+				return s.keys.ConfirmKey
+			}
+		}
+	}
+	return nil
+}
+
+// handleHello processes a hello message on the server, starting a new session if the client did, and replies with
+// an acknowledgment including the observed address of the client. No security association is configured at this stage.
+func (t *Tunnel) handleHello(msg *message, from *net.UDPAddr) {
+	now := time.Now()
+	if skew := msg.Timestamp.Sub(now); skew > maxClockSkew || skew < -maxClockSkew {
+		klog.Warningf("Ignoring hello message from %s: timestamp %s is too far from the local time", from, msg.Timestamp)
+		return
+	}
+
+	var s *session
+	switch {
+	case t.current != nil && t.current.clientNonce == msg.ClientNonce:
+		s = t.current
+	case t.pending != nil && t.pending.clientNonce == msg.ClientNonce:
+		s = t.pending
+	default:
+		// Only the hello messages more recent than the one which started the last session can start a new one,
+		// so that replayed messages do not replace it.
+		for _, last := range []*session{t.pending, t.current} {
+			if last != nil && !msg.Timestamp.After(last.helloTimestamp) {
+				klog.V(4).Infof("Ignoring replayed hello message from %s", from)
+				return
+			}
+		}
+
+		var err error
+		if s, err = t.newServerSession(msg); err != nil {
+			klog.Errorf("Unable to start a new IPsec session with %s: %v", from, err)
+			return
+		}
+		t.pending = s
+		klog.Infof("Negotiating a new IPsec session with %s", from)
+	}
+
+	ack := message{Type: messageHelloAck, Timestamp: now, ClientNonce: s.clientNonce, ServerNonce: s.serverNonce,
+		EphemeralKey: s.ephemeralKey, Address: addrPort(from)}
+	t.send(&ack, from, t.secrets.helloKey)
+}
+
+// handleConfirm processes a confirmation on the server, configuring the security associations of the confirmed session
+// towards the address of the client, and replies with an acknowledgment.
+func (t *Tunnel) handleConfirm(msg *message, from *net.UDPAddr) {
+	if addr := addrPort(from); msg.Address != addr {
+		klog.Warningf("Ignoring confirmation from %s: bound to address %s", from, msg.Address)
+		return
+	}
+	// The timestamp is the one of the acknowledgment generated by the server itself, hence no clock skew is involved.
+	if age := time.Since(msg.Timestamp); age < 0 || age > maxConfirmDelay {
+		klog.V(4).Infof("Ignoring expired confirmation from %s", from)
+		return
+	}
+
+	key := t.messageKey(msg)
+	switch {
+	case t.pending.matches(msg):
+		ep, err := t.forgeEndpoints(from)
+		if err != nil {
+			klog.Errorf("Unable to configure the IPsec security associations towards %s: %v", from, err)
+			return
+		}
+		if err := t.configure(t.pending, ep); err != nil {
+			klog.Errorf("Unable to configure the IPsec security associations towards %s: %v", from, err)
+			return
+		}
+		t.promote()
+	case t.current.matches(msg):
+		// The acknowledgment of the confirmation has been lost, and the client retransmitted it.
+		if ep := t.current.endpoints; !ep.Peer.Equal(from.IP) || ep.PeerPort != from.Port {
+			klog.V(4).Infof("Ignoring confirmation of the current session from %s", from)
+			return
+		}
+	}
+
+	ack := message{Type: messageConfirmAck, Timestamp: time.Now(), ClientNonce: msg.ClientNonce, ServerNonce: msg.ServerNonce,
+		Address: msg.Address}
+	t.send(&ack, from, key)
+}
+
+// handleHelloAck processes an acknowledgment on the client. In case of a new session, it derives the session keys,
+// configures the inbound security association and sends the confirmation. In case of the current session, it triggers
+// a renegotiation if the server no longer knows it, or the address of the client changed (e.g., due to a NAT).
+func (t *Tunnel) handleHelloAck(msg *message) {
+	var s *session
+	switch {
+	case t.endpoint == nil:
+	case t.pending != nil && t.pending.clientNonce == msg.ClientNonce:
+		s = t.pending
+	case t.current != nil && t.current.clientNonce == msg.ClientNonce:
+		s = t.current
+	}
+	if s == nil {
+		klog.V(4).Info("Ignoring acknowledgment of a stale session")
+		return
+	}
+	if !msg.Timestamp.After(s.ackTimestamp) {
+		klog.V(4).Info("Ignoring replayed acknowledgment")
+		return
+	}
+	s.ackTimestamp = msg.Timestamp
+	t.acknowledged = time.Now()
+
+	if s.keys != nil && s.serverNonce != msg.ServerNonce {
+		klog.Infof("IPsec session with %s no longer known by the server, renegotiating", t.endpoint)
+		t.renegotiate(s)
+		return
+	}
+
+	if s == t.current {
+		if s.address != msg.Address {
+			klog.Infof("Address observed by the server changed from %s to %s, renegotiating", s.address, msg.Address)
+			t.renegotiate(s)
+		}
+		return
+	}
+
+	if s.keys == nil {
+		if err := t.acceptSession(s, msg); err != nil {
+			klog.Errorf("Unable to configure the IPsec security associations towards %s: %v", t.endpoint, err)
+			return
+		}
+	}
+	s.address = msg.Address
+
+	confirm := message{Type: messageConfirm, Timestamp: msg.Timestamp, ClientNonce: s.clientNonce, ServerNonce: s.serverNonce,
+		Address: msg.Address}
+	t.send(&confirm, t.endpoint, s.keys.ConfirmKey)
+}
+
+// handleConfirmAck processes the acknowledgment of a confirmation on the client, configuring the outbound
+// security association of the new session, which replaces the current one.
+func (t *Tunnel) handleConfirmAck(msg *message) {
+	if !t.pending.matches(msg) || t.pending.endpoints == nil {
+		return
+	}
+
+	if err := addOutboundSecurityAssociation(t.handle, t.pending.endpoints, t.pending.keys, t.options.RekeyInterval); err != nil {
+		klog.Errorf("Unable to configure the IPsec security associations towards %s: %v", t.endpoint, err)
+		return
+	}
+	t.promote()
+}
+
+// acceptSession derives the keys of the given session from the acknowledgment of the server,
+// and configures the inbound security association.
+func (t *Tunnel) acceptSession(s *session, msg *message) error {
+	keys, err := t.secrets.deriveSessionKeys(gateway.ModeClient, s.ephemeral, &msg.EphemeralKey, &s.clientNonce, &msg.ServerNonce)
+	if err != nil {
+		return err
+	}
+	ep, err := t.forgeEndpoints(t.endpoint)
+	if err != nil {
+		return err
+	}
+	if err := addInboundSecurityAssociation(t.handle, ep, keys, t.options.RekeyInterval); err != nil {
+		return err
+	}
+
+	s.serverNonce, s.keys, s.endpoints, s.ephemeral = msg.ServerNonce, keys, ep, nil
+	klog.Infof("IPsec session with %s acknowledged", t.endpoint)
+	return nil
+}
+
+// renegotiate forces the renegotiation of the given session.
+func (t *Tunnel) renegotiate(s *session) {
+	if s == t.pending {
+		t.pending = nil
+	} else {
+		s.established = time.Time{}
+	}
+	t.wake()
+}
+
+// newClientSession returns a new session, with a fresh nonce and ephemeral key.
+func newClientSession() (*session, error) {
+	s := &session{}
+	if _, err := rand.Read(s.clientNonce[:]); err != nil {
+		return nil, fmt.Errorf("unable to generate the session nonce: %w", err)
+	}
+	var err error
+	if s.ephemeral, s.ephemeralKey, err = generateEphemeralKey(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newServerSession returns a new session started by the given hello message, deriving its keys.
+func (t *Tunnel) newServerSession(msg *message) (*session, error) {
+	s := &session{clientNonce: msg.ClientNonce, helloTimestamp: msg.Timestamp}
+	if _, err := rand.Read(s.serverNonce[:]); err != nil {
+		return nil, fmt.Errorf("unable to generate the session nonce: %w", err)
+	}
+	ephemeral, ephemeralKey, err := generateEphemeralKey()
+	if err != nil {
+		return nil, err
+	}
+	if s.keys, err = t.secrets.deriveSessionKeys(gateway.ModeServer, ephemeral, &msg.EphemeralKey, &s.clientNonce, &s.serverNonce); err != nil {
+		return nil, err
+	}
+	s.ephemeralKey = ephemeralKey
+	return s, nil
+}
+
+// forgeEndpoints returns the outer addresses of the ESP-in-UDP packets exchanged with the given peer.
+func (t *Tunnel) forgeEndpoints(peer *net.UDPAddr) (*endpoints, error) {
+	local, err := localAddress(t.handle, peer.IP)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoints{Local: local, Peer: peer.IP, LocalPort: t.options.ListenPort, PeerPort: peer.Port}, nil
+}
+
+// configure configures both the security associations of the given session towards the given endpoints.
+func (t *Tunnel) configure(s *session, ep *endpoints) error {
+	if err := addInboundSecurityAssociation(t.handle, ep, s.keys, t.options.RekeyInterval); err != nil {
+		return err
+	}
+	if err := addOutboundSecurityAssociation(t.handle, ep, s.keys, t.options.RekeyInterval); err != nil {
+		if rmErr := removeSecurityAssociation(t.handle, ep.Peer, ep.Local, s.keys.InSPI); rmErr != nil {
+			klog.Errorf("Unable to remove the IPsec security associations: %v", rmErr)
+		}
+		return err
+	}
+	s.endpoints = ep
+	return nil
+}
+
+// promote replaces the current session with the pending one, whose security associations are configured.
+// The outbound security association of the replaced session is removed immediately, so that the new one is used,
+// while the inbound one is kept for a while, to accept the packets sent in the meanwhile.
+func (t *Tunnel) promote() {
+	old := t.current
+	t.current, t.pending = t.pending, nil
+	t.current.established = time.Now()
+
+	ep := t.current.endpoints
+	klog.Infof("IPsec session established (local %s:%d, peer %s:%d)", ep.Local, ep.LocalPort, ep.Peer, ep.PeerPort)
+
+	if old == nil || old.endpoints == nil {
+		return
+	}
+	if err := removeSecurityAssociation(t.handle, old.endpoints.Local, old.endpoints.Peer, old.keys.OutSPI); err != nil {
+		klog.Errorf("Unable to remove the IPsec security associations of the previous session: %v", err)
+	}
+	time.AfterFunc(retireDelay, func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		if err := removeSecurityAssociation(t.handle, old.endpoints.Peer, old.endpoints.Local, old.keys.InSPI); err != nil {
+			klog.Errorf("Unable to remove the IPsec security associations of the previous session: %v", err)
+		}
+	})
+}
+
+// resetSessions tears down the current and the pending sessions, if any.
+func (t *Tunnel) resetSessions() {
+	if t.current != nil || (t.pending != nil && t.pending.endpoints != nil) {
+		if err := flushSecurityAssociations(t.handle); err != nil {
+			klog.Errorf("Unable to remove the IPsec security associations: %v", err)
+		}
+	}
+	t.current, t.pending = nil, nil
+}
+
+func (t *Tunnel) wake() {
+	select {
+	case t.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (t *Tunnel) send(msg *message, to *net.UDPAddr, key []byte) {
+	if _, err := t.conn.WriteToUDP(msg.marshal(key), to); err != nil && !errors.Is(err, syscall.ECONNREFUSED) {
+		klog.Warningf("Unable to send the handshake message to %s: %v", to, err)
+	}
+}
+
+// securityParameterIndexes returns the SPIs of the current session, if established.
+func (t *Tunnel) securityParameterIndexes() (out, in uint32, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.current == nil {
+		return 0, 0, false
+	}
+	return t.current.keys.OutSPI, t.current.keys.InSPI, true
+}
+
+// addrPort returns the given address as a netip.AddrPort, with IPv4 addresses unmapped.
+func addrPort(addr *net.UDPAddr) netip.AddrPort {
+	ap := addr.AddrPort()
+	return netip.AddrPortFrom(ap.Addr().Unmap(), ap.Port())
+}
@@ -0,0 +1,370 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+)
+
+var _ = Describe("IPsec tunnel", func() {
+	var clientKey, serverKey *ecdh.PrivateKey
+
+	BeforeEach(func() {
+		var err error
+		clientKey, err = GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		serverKey, err = GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	forgeOptions := func(mode gateway.Mode, key *ecdh.PrivateKey) *Options {
+		return &Options{
+			GwOptions:         &gateway.Options{Mode: mode},
+			MTU:               1400,
+			ListenPort:        4500,
+			EndpointPort:      4500,
+			KeepaliveInterval: 200 * time.Millisecond,
+			RekeyInterval:     time.Hour,
+			DNSCheckInterval:  time.Minute,
+			PrivateKey:        key,
+
+			EndpointFailoverTimeout: time.Minute,
+		}
+	}
+
+	Describe("the handshake on the server", func() {
+		var (
+			server  *Tunnel
+			client  *net.UDPConn
+			from    *net.UDPAddr
+			secrets *secrets
+			hello   message
+
+			ephemeral *ecdh.PrivateKey
+		)
+
+		receive := func(key func(*message) []byte) *message {
+			buf := make([]byte, messageLength+1)
+			Expect(client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))).To(Succeed())
+			n, _, err := client.ReadFromUDP(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil
+			}
+			Expect(err).ToNot(HaveOccurred())
+			msg, err := parseMessage(buf[:n], key)
+			Expect(err).ToNot(HaveOccurred())
+			return msg
+		}
+
+		// confirm returns the confirmation of the session acknowledged by the given message, bound to the given address.
+		confirm := func(ack *message, address *net.UDPAddr, timestamp time.Time) ([]byte, *sessionKeys) {
+			keys, err := secrets.deriveSessionKeys(gateway.ModeClient, ephemeral, &ack.EphemeralKey, &ack.ClientNonce, &ack.ServerNonce)
+			Expect(err).ToNot(HaveOccurred())
+			msg := message{Type: messageConfirm, Timestamp: timestamp, ClientNonce: ack.ClientNonce, ServerNonce: ack.ServerNonce,
+				Address: addrPort(address)}
+			return msg.marshal(keys.ConfirmKey), keys
+		}
+
+		BeforeEach(func() {
+			var err error
+			server = NewTunnel(forgeOptions(gateway.ModeServer, serverKey), nil)
+			server.conn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.SetPeerPublicKey(clientKey.PublicKey().Bytes())).To(Succeed())
+
+			client, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+			Expect(err).ToNot(HaveOccurred())
+			from = client.LocalAddr().(*net.UDPAddr)
+
+			secrets, err = deriveSecrets(clientKey, serverKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			hello = message{Type: messageHello, Timestamp: time.Now()}
+			_, err = rand.Read(hello.ClientNonce[:])
+			Expect(err).ToNot(HaveOccurred())
+			ephemeral, hello.EphemeralKey, err = generateEphemeralKey()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(server.conn.Close()).To(Succeed())
+			Expect(client.Close()).To(Succeed())
+		})
+
+		It("should acknowledge the hello messages with the observed address", func() {
+			server.handleMessage(hello.marshal(secrets.helloKey), from)
+
+			ack := receive(func(*message) []byte { return secrets.helloKey })
+			Expect(ack).ToNot(BeNil())
+			Expect(ack.Type).To(Equal(messageHelloAck))
+			Expect(ack.ClientNonce).To(Equal(hello.ClientNonce))
+			Expect(ack.ServerNonce).ToNot(Equal(nonce{}))
+			Expect(ack.EphemeralKey).ToNot(Equal(ephemeralKey{}))
+			Expect(ack.Address).To(Equal(addrPort(from)))
+
+			Expect(server.pending).ToNot(BeNil())
+			Expect(server.current).To(BeNil())
+		})
+
+		It("should not start a new session for replayed hello messages", func() {
+			server.handleMessage(hello.marshal(secrets.helloKey), from)
+			Expect(receive(func(*message) []byte { return secrets.helloKey })).ToNot(BeNil())
+			pending := server.pending
+
+			replayed := hello
+			replayed.ClientNonce[0] ^= 0xff
+			replayed.Timestamp = hello.Timestamp.Add(-time.Second)
+			server.handleMessage(replayed.marshal(secrets.helloKey), from)
+			Expect(receive(func(*message) []byte { return secrets.helloKey })).To(BeNil())
+			Expect(server.pending).To(BeIdenticalTo(pending))
+		})
+
+		It("should ignore the hello messages authenticated with a different key", func() {
+			other, err := deriveSecrets(clientKey, clientKey.PublicKey(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			server.handleMessage(hello.marshal(other.helloKey), from)
+			Expect(server.pending).To(BeNil())
+		})
+
+		It("should ignore the confirmations bound to a different address", func() {
+			server.handleMessage(hello.marshal(secrets.helloKey), from)
+			ack := receive(func(*message) []byte { return secrets.helloKey })
+			Expect(ack).ToNot(BeNil())
+
+			other := &net.UDPAddr{IP: from.IP, Port: from.Port + 1}
+			buf, keys := confirm(ack, other, ack.Timestamp)
+			server.handleMessage(buf, from)
+
+			Expect(receive(func(*message) []byte { return keys.ConfirmKey })).To(BeNil())
+			Expect(server.pending).ToNot(BeNil())
+			Expect(server.current).To(BeNil())
+		})
+
+		It("should ignore the expired confirmations", func() {
+			server.handleMessage(hello.marshal(secrets.helloKey), from)
+			ack := receive(func(*message) []byte { return secrets.helloKey })
+			Expect(ack).ToNot(BeNil())
+
+			buf, keys := confirm(ack, from, ack.Timestamp.Add(-2*maxConfirmDelay))
+			server.handleMessage(buf, from)
+
+			Expect(receive(func(*message) []byte { return keys.ConfirmKey })).To(BeNil())
+			Expect(server.current).To(BeNil())
+		})
+	})
+
+	// The following tests establish a tunnel between two network namespaces, hence they require the
+	// privileges to create them, and a kernel supporting the XFRM interfaces and the AES-GCM algorithm.
+	Describe("the tunnel between network namespaces", func() {
+		const (
+			serverAddress = "192.0.2.1"
+			clientAddress = "192.0.2.2"
+			probePort     = 9000
+		)
+
+		var (
+			origin, serverNs, clientNs     netns.NsHandle
+			serverHandle, clientHandle     *netlink.Handle
+			server, client                 *Tunnel
+			serverOptions, clientOptions   *Options
+			ctx                            context.Context
+			cancel                         context.CancelFunc
+			serverTunnelIP, clientTunnelIP net.IP
+		)
+
+		// inNamespace runs the given function in the given network namespace.
+		inNamespace := func(ns netns.NsHandle, f func()) {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			Expect(netns.Set(ns)).To(Succeed())
+			defer func() { Expect(netns.Set(origin)).To(Succeed()) }()
+			f()
+		}
+
+		setupNamespace := func(handle *netlink.Handle, name, address string) {
+			link, err := handle.LinkByName(name)
+			Expect(err).ToNot(HaveOccurred())
+			addr, err := netlink.ParseAddr(address + "/24")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handle.AddrAdd(link, addr)).To(Succeed())
+			Expect(handle.LinkSetUp(link)).To(Succeed())
+			lo, err := handle.LinkByName("lo")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handle.LinkSetUp(lo)).To(Succeed())
+		}
+
+		// probe sends a datagram through the tunnel, from the client to the server, returning whether it is received.
+		probe := func() bool {
+			var listener, sender *net.UDPConn
+			inNamespace(serverNs, func() {
+				var err error
+				listener, err = net.ListenUDP("udp4", &net.UDPAddr{IP: serverTunnelIP, Port: probePort})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			defer listener.Close()
+			inNamespace(clientNs, func() {
+				var err error
+				sender, err = net.DialUDP("udp4", nil, &net.UDPAddr{IP: serverTunnelIP, Port: probePort})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			defer sender.Close()
+
+			if _, err := sender.Write([]byte("probe")); err != nil {
+				return false
+			}
+			Expect(listener.SetReadDeadline(time.Now().Add(200 * time.Millisecond))).To(Succeed())
+			buf := make([]byte, 16)
+			n, from, err := listener.ReadFromUDP(buf)
+			return err == nil && string(buf[:n]) == "probe" && from.IP.Equal(clientTunnelIP)
+		}
+
+		BeforeEach(func() {
+			if os.Geteuid() != 0 {
+				Skip("the creation of the network namespaces requires root privileges")
+			}
+
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+
+			var err error
+			origin, err = netns.Get()
+			Expect(err).ToNot(HaveOccurred())
+			serverNs, err = netns.New()
+			Expect(err).ToNot(HaveOccurred())
+			clientNs, err = netns.New()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(netns.Set(origin)).To(Succeed())
+
+			serverHandle, err = netlink.NewHandleAt(serverNs)
+			Expect(err).ToNot(HaveOccurred())
+			clientHandle, err = netlink.NewHandleAt(clientNs)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(serverHandle.LinkAdd(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "server"}, PeerName: "client"})).To(Succeed())
+			peer, err := serverHandle.LinkByName("client")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serverHandle.LinkSetNsFd(peer, int(clientNs))).To(Succeed())
+			setupNamespace(serverHandle, "server", serverAddress)
+			setupNamespace(clientHandle, "client", clientAddress)
+
+			serverOptions = forgeOptions(gateway.ModeServer, serverKey)
+			clientOptions = forgeOptions(gateway.ModeClient, clientKey)
+			clientOptions.EndpointAddresses = []string{serverAddress}
+
+			if err := InitLink(serverHandle, serverOptions); err != nil {
+				Skip("XFRM interfaces not supported: " + err.Error())
+			}
+			Expect(InitLink(clientHandle, clientOptions)).To(Succeed())
+
+			probeState := forgeState(net.ParseIP(serverAddress), net.ParseIP(clientAddress), 4500, 4500, 0x100,
+				make([]byte, aeadKeyLength), time.Minute)
+			if err := serverHandle.XfrmStateAdd(probeState); err != nil {
+				Skip("IPsec security associations not supported: " + err.Error())
+			}
+			Expect(serverHandle.XfrmStateDel(probeState)).To(Succeed())
+
+			for mode, ip := range map[gateway.Mode]*net.IP{gateway.ModeServer: &serverTunnelIP, gateway.ModeClient: &clientTunnelIP} {
+				addr, err := netlink.ParseIPNet(tunnel.GetInterfaceIP(mode))
+				Expect(err).ToNot(HaveOccurred())
+				*ip = addr.IP
+			}
+
+			server, client = NewTunnel(serverOptions, serverHandle), NewTunnel(clientOptions, clientHandle)
+			inNamespace(serverNs, func() { Expect(server.Listen()).To(Succeed()) })
+			inNamespace(clientNs, func() { Expect(client.Listen()).To(Succeed()) })
+			Expect(server.SetPeerPublicKey(clientKey.PublicKey().Bytes())).To(Succeed())
+			Expect(client.SetPeerPublicKey(serverKey.PublicKey().Bytes())).To(Succeed())
+
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() { defer GinkgoRecover(); Expect(server.Start(ctx)).To(Succeed()) }()
+			go func() { defer GinkgoRecover(); Expect(client.Start(ctx)).To(Succeed()) }()
+		})
+
+		AfterEach(func() {
+			if cancel != nil {
+				cancel()
+			}
+			for _, h := range []*netlink.Handle{serverHandle, clientHandle} {
+				if h != nil {
+					h.Close()
+				}
+			}
+			for _, ns := range []netns.NsHandle{serverNs, clientNs, origin} {
+				if ns.IsOpen() {
+					Expect(ns.Close()).To(Succeed())
+				}
+			}
+		})
+
+		It("should establish the tunnel and carry the traffic", func() {
+			Eventually(client.Established, 10*time.Second).Should(BeTrue())
+			Eventually(server.Established, 10*time.Second).Should(BeTrue())
+			Eventually(probe, 10*time.Second).Should(BeTrue())
+		})
+
+		It("should configure the lifetimes of the security associations", func() {
+			Eventually(client.Established, 10*time.Second).Should(BeTrue())
+			states, err := listSecurityAssociations(clientHandle)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(states).To(HaveLen(2))
+			for i := range states {
+				Expect(states[i].Limits.TimeSoft).To(BeEquivalentTo(clientOptions.RekeyInterval.Seconds()))
+				Expect(states[i].Limits.TimeHard).To(BeEquivalentTo((clientOptions.RekeyInterval + lifetimeMargin).Seconds()))
+			}
+		})
+
+		It("should renegotiate the keys without interrupting the traffic", func() {
+			Eventually(client.Established, 10*time.Second).Should(BeTrue())
+			Eventually(probe, 10*time.Second).Should(BeTrue())
+			out, in, ok := client.securityParameterIndexes()
+			Expect(ok).To(BeTrue())
+
+			client.mutex.Lock()
+			client.current.established = time.Time{}
+			client.mutex.Unlock()
+			client.wake()
+
+			Eventually(func() bool {
+				newOut, newIn, ok := client.securityParameterIndexes()
+				return ok && newOut != out && newIn != in
+			}, 10*time.Second).Should(BeTrue())
+			Expect(probe()).To(BeTrue())
+		})
+
+		It("should not establish the tunnel if the keys do not match", func() {
+			Eventually(client.Established, 10*time.Second).Should(BeTrue())
+			other, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.SetPeerPublicKey(other.PublicKey().Bytes())).To(Succeed())
+			Consistently(client.Established, time.Second).Should(BeFalse())
+		})
+	})
+})
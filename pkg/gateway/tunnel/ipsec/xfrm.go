@@ -0,0 +1,233 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog/v2"
+
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+)
+
+const (
+	// InterfaceID is the XFRM interface ID binding the security policies to the tunnel interface.
+	InterfaceID = 0x4c51
+	// reqID is the request ID associating the security policies to the security associations.
+	reqID = InterfaceID
+	// replayWindow is the size of the anti-replay window of the inbound security associations.
+	replayWindow = 128
+	// lifetimeMargin is the time the security associations survive after their lifetime, waiting for a delayed rekey.
+	lifetimeMargin = 5 * time.Minute
+)
+
+// InitLink creates the XFRM interface, and assigns it the tunnel IP address.
+func InitLink(handle *netlink.Handle, options *Options) error {
+	_, err := handle.LinkByName(tunnel.TunnelInterfaceName)
+	switch {
+	case err == nil:
+		klog.Infof("IPsec interface %q already exists", tunnel.TunnelInterfaceName)
+		return nil
+	case !errors.As(err, &netlink.LinkNotFoundError{}):
+		return fmt.Errorf("cannot check if IPsec interface exists: %w", err)
+	}
+
+	link := &netlink.Xfrmi{
+		LinkAttrs: netlink.LinkAttrs{
+			MTU:  options.MTU,
+			Name: tunnel.TunnelInterfaceName,
+		},
+		Ifid: InterfaceID,
+	}
+	if err := handle.LinkAdd(link); err != nil {
+		return fmt.Errorf("cannot add IPsec interface: %w", err)
+	}
+
+	klog.Infof("Setting up IPsec interface %q with IP %q", tunnel.TunnelInterfaceName, tunnel.GetInterfaceIP(options.GwOptions.Mode))
+	addr, err := netlink.ParseAddr(tunnel.GetInterfaceIP(options.GwOptions.Mode))
+	if err != nil {
+		return err
+	}
+	if err := handle.AddrAdd(link, addr); err != nil {
+		return fmt.Errorf("cannot set the address of the IPsec interface: %w", err)
+	}
+
+	return handle.LinkSetUp(link)
+}
+
+// endpoints contains the outer addresses of the ESP-in-UDP packets of a session.
+type endpoints struct {
+	Local     net.IP
+	Peer      net.IP
+	LocalPort int
+	PeerPort  int
+}
+
+// addInboundSecurityAssociation adds the inbound security association of a session, which expires after the given lifetime.
+// It coexists with the ones of the previous sessions, so that the packets they protect are still accepted during a rekey.
+func addInboundSecurityAssociation(handle *netlink.Handle, ep *endpoints, keys *sessionKeys, lifetime time.Duration) error {
+	state := forgeState(ep.Peer, ep.Local, ep.PeerPort, ep.LocalPort, keys.InSPI, keys.InKey, lifetime)
+	if err := handle.XfrmStateAdd(state); err != nil {
+		return fmt.Errorf("cannot add the security association with SPI %#x: %w", state.Spi, err)
+	}
+	return nil
+}
+
+// addOutboundSecurityAssociation adds the outbound security association of a session, which expires after the given lifetime,
+// and points the security policies to the given endpoints. The outbound security associations of the previous sessions
+// must be removed afterwards, so that the new one is used.
+func addOutboundSecurityAssociation(handle *netlink.Handle, ep *endpoints, keys *sessionKeys, lifetime time.Duration) error {
+	state := forgeState(ep.Local, ep.Peer, ep.LocalPort, ep.PeerPort, keys.OutSPI, keys.OutKey, lifetime)
+	if err := handle.XfrmStateAdd(state); err != nil {
+		return fmt.Errorf("cannot add the security association with SPI %#x: %w", state.Spi, err)
+	}
+
+	for _, policy := range []*netlink.XfrmPolicy{
+		forgePolicy(netlink.XFRM_DIR_OUT, ep.Local, ep.Peer),
+		forgePolicy(netlink.XFRM_DIR_IN, ep.Peer, ep.Local),
+		forgePolicy(netlink.XFRM_DIR_FWD, ep.Peer, ep.Local),
+	} {
+		if err := handle.XfrmPolicyUpdate(policy); err != nil {
+			return fmt.Errorf("cannot configure the %s security policy: %w", policy.Dir, err)
+		}
+	}
+	return nil
+}
+
+// removeSecurityAssociation removes the security association with the given addresses and SPI, if still present.
+func removeSecurityAssociation(handle *netlink.Handle, src, dst net.IP, spi uint32) error {
+	state := &netlink.XfrmState{Src: src, Dst: dst, Proto: netlink.XFRM_PROTO_ESP, Spi: int(spi), Ifid: InterfaceID}
+	if err := handle.XfrmStateDel(state); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("cannot remove the security association with SPI %#x: %w", spi, err)
+	}
+	return nil
+}
+
+// flushSecurityAssociations removes the security associations and policies bound to the tunnel interface.
+func flushSecurityAssociations(handle *netlink.Handle) error {
+	states, err := listSecurityAssociations(handle)
+	if err != nil {
+		return err
+	}
+	for i := range states {
+		if err := handle.XfrmStateDel(&states[i]); err != nil {
+			return fmt.Errorf("cannot remove the security association with SPI %#x: %w", states[i].Spi, err)
+		}
+	}
+
+	policies, err := handle.XfrmPolicyList(netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("cannot list the security policies: %w", err)
+	}
+	for i := range policies {
+		if policies[i].Ifid != InterfaceID {
+			continue
+		}
+		if err := handle.XfrmPolicyDel(&policies[i]); err != nil {
+			return fmt.Errorf("cannot remove the %s security policy: %w", policies[i].Dir, err)
+		}
+	}
+	return nil
+}
+
+// listSecurityAssociations returns the security associations bound to the tunnel interface.
+func listSecurityAssociations(handle *netlink.Handle) ([]netlink.XfrmState, error) {
+	states, err := handle.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the security associations: %w", err)
+	}
+
+	var filtered []netlink.XfrmState
+	for i := range states {
+		if states[i].Ifid == InterfaceID {
+			filtered = append(filtered, states[i])
+		}
+	}
+	return filtered, nil
+}
+
+// forgeState forges a security association, which is marked as dying once the given lifetime elapses,
+// and removed after a further margin, unless replaced by a rekey in the meanwhile.
+func forgeState(src, dst net.IP, srcPort, dstPort int, spi uint32, key []byte, lifetime time.Duration) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src:   src,
+		Dst:   dst,
+		Proto: netlink.XFRM_PROTO_ESP,
+		Mode:  netlink.XFRM_MODE_TUNNEL,
+		Spi:   int(spi),
+		Reqid: reqID,
+		Aead: &netlink.XfrmStateAlgo{
+			Name:   aeadAlgorithm,
+			Key:    key,
+			ICVLen: aeadICVLength,
+		},
+		Encap: &netlink.XfrmStateEncap{
+			Type:    netlink.XFRM_ENCAP_ESPINUDP,
+			SrcPort: srcPort,
+			DstPort: dstPort,
+		},
+		// Extended sequence numbers prevent the counters from wrapping on long-lived sessions.
+		ESN:          true,
+		ReplayWindow: replayWindow,
+		Ifid:         InterfaceID,
+		Limits: netlink.XfrmStateLimits{
+			TimeSoft: uint64(lifetime.Seconds()),
+			TimeHard: uint64((lifetime + lifetimeMargin).Seconds()),
+		},
+	}
+}
+
+func forgePolicy(dir netlink.Dir, src, dst net.IP) *netlink.XfrmPolicy {
+	return &netlink.XfrmPolicy{
+		Src:  anyNetwork(src),
+		Dst:  anyNetwork(dst),
+		Dir:  dir,
+		Ifid: InterfaceID,
+		Tmpls: []netlink.XfrmPolicyTmpl{{
+			Src:   src,
+			Dst:   dst,
+			Proto: netlink.XFRM_PROTO_ESP,
+			Mode:  netlink.XFRM_MODE_TUNNEL,
+			Reqid: reqID,
+		}},
+	}
+}
+
+// anyNetwork returns the network matching any address of the same family of the given one.
+func anyNetwork(ip net.IP) *net.IPNet {
+	if ip.To4() != nil {
+		return &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+	}
+	return &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+}
+
+// localAddress returns the address used to reach the given peer.
+func localAddress(handle *netlink.Handle, peer net.IP) (net.IP, error) {
+	routes, err := handle.RouteGet(peer)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the route towards %s: %w", peer, err)
+	}
+	for i := range routes {
+		if routes[i].Src != nil {
+			return routes[i].Src, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find the source address to reach %s", peer)
+}
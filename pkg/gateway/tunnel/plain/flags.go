@@ -0,0 +1,111 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/forge"
+)
+
+// FlagName is the type for the name of the flags.
+type FlagName string
+
+func (fn FlagName) String() string {
+	return string(fn)
+}
+
+const (
+	// FlagNameMTU is the MTU for the tunnel interface.
+	FlagNameMTU FlagName = "mtu"
+	// FlagNameListenPort is the local port of the encapsulated packets.
+	FlagNameListenPort FlagName = "listen-port"
+	// FlagNameEndpointAddress is the address of the remote gateway server.
+	FlagNameEndpointAddress FlagName = "endpoint-address"
+	// FlagNameEndpointPort is the port of the remote gateway server.
+	FlagNameEndpointPort FlagName = "endpoint-port"
+	// FlagNameEncapsulation is the encapsulation protocol of the tunnel.
+	FlagNameEncapsulation FlagName = "encapsulation"
+	// FlagNameVNI is the virtual network identifier of the tunnel.
+	FlagNameVNI FlagName = "vni"
+	// FlagNameAcknowledgeUnencrypted is the explicit acknowledgement that the traffic is not encrypted.
+	FlagNameAcknowledgeUnencrypted FlagName = "acknowledge-unencrypted"
+
+	// FlagNameAllowedClientCIDRs is the list of CIDRs the server learns the address of the client from.
+	FlagNameAllowedClientCIDRs FlagName = "allowed-client-cidrs"
+	// FlagNameRemoteChangeInterval is the minimum interval between two changes of the learned address of the client.
+	FlagNameRemoteChangeInterval FlagName = "remote-change-interval"
+
+	// FlagNameDNSCheckInterval is the interval between two DNS checks.
+	FlagNameDNSCheckInterval FlagName = "dns-check-interval"
+)
+
+// ClientRequiredFlags contains the list of the mandatory flags for the client mode.
+var ClientRequiredFlags = []FlagName{
+	FlagNameEndpointAddress,
+}
+
+// InitFlags initializes the flags for the plain tunnel.
+func InitFlags(flagset *pflag.FlagSet, opts *Options) {
+	flagset.IntVar(&opts.MTU, FlagNameMTU.String(), forge.DefaultMTU, "MTU for the interface")
+	flagset.IntVar(&opts.ListenPort, FlagNameListenPort.String(), forge.DefaultGwServerPort, "Local port of the encapsulated packets (server only)")
+	flagset.StringVar(&opts.EndpointAddress, FlagNameEndpointAddress.String(), "", "Endpoint address (client only)")
+	flagset.IntVar(&opts.EndpointPort, FlagNameEndpointPort.String(), forge.DefaultGwServerPort, "Endpoint port (client only)")
+	flagset.Var(&opts.Encapsulation, FlagNameEncapsulation.String(), "Encapsulation protocol of the tunnel (geneve or vxlan)")
+	flagset.Uint32Var(&opts.VNI, FlagNameVNI.String(), DefaultVNI, "Virtual network identifier of the tunnel, which must match the remote one")
+	flagset.BoolVar(&opts.AcknowledgeUnencrypted, FlagNameAcknowledgeUnencrypted.String(), false,
+		"Acknowledge that the traffic towards the remote cluster is not encrypted. The gateway refuses to start otherwise")
+	flagset.Var(&opts.AllowedClientCIDRs, FlagNameAllowedClientCIDRs.String(),
+		"CIDRs the address of the client is learned from, ignoring the traffic from other sources (server only)")
+	flagset.DurationVar(&opts.RemoteChangeInterval, FlagNameRemoteChangeInterval.String(), 10*time.Second,
+		"Minimum interval between two changes of the learned address of the client (server only)")
+
+	flagset.DurationVar(&opts.DNSCheckInterval, FlagNameDNSCheckInterval.String(), 5*time.Minute, "Interval between two DNS checks (client only)")
+}
+
+// MarkFlagsRequired marks the flags as required.
+func MarkFlagsRequired(cmd *cobra.Command, opts *Options) error {
+	if opts.GwOptions.Mode == gateway.ModeClient {
+		for _, flag := range ClientRequiredFlags {
+			if err := cmd.MarkFlagRequired(flag.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckAllowedClients returns an error if the server has not been configured with the CIDRs the clients are allowed to connect from.
+func CheckAllowedClients(opts *Options) error {
+	if opts.GwOptions.Mode == gateway.ModeServer && len(opts.AllowedClientCIDRs.CIDRList) == 0 {
+		return errors.New("the plain gateway server learns the address of the client from the received traffic: " +
+			"set the --" + FlagNameAllowedClientCIDRs.String() + " flag to restrict the allowed sources")
+	}
+	return nil
+}
+
+// CheckAcknowledged returns an error if the unencrypted traffic has not been explicitly acknowledged.
+func CheckAcknowledged(opts *Options) error {
+	if !opts.AcknowledgeUnencrypted {
+		return errors.New("the plain gateway does not encrypt the traffic towards the remote cluster: " +
+			"set the --" + FlagNameAcknowledgeUnencrypted.String() + " flag to acknowledge it")
+	}
+	return nil
+}
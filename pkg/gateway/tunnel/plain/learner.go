@@ -0,0 +1,184 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// learnerSnapLen is the number of bytes of the packets read by the learner, enough to include the IPv4 header.
+	learnerSnapLen = 64
+	// maxAllowedCIDRs is the maximum number of allowed CIDRs, bounded by the length of the jumps of the filter.
+	maxAllowedCIDRs = 64
+)
+
+// remoteLearner learns the address of the remote gateway from the encapsulated packets received on the local port,
+// as the server does not know in advance the address of the client. A BPF filter accepts only the packets carrying
+// the expected virtual network identifier and coming from the allowed CIDRs, and discards the ones coming from
+// the address already known, so that only the packets from a new allowed address reach the userspace.
+type remoteLearner struct {
+	port    int
+	vni     uint32
+	allowed []net.IPNet
+	file    *os.File
+}
+
+// newRemoteLearner opens the raw socket receiving a copy of the UDP packets directed to the given port, carrying the given
+// virtual network identifier and coming from the given CIDRs. The socket is created in the network namespace of the calling thread.
+func newRemoteLearner(port int, vni uint32, allowed []net.IPNet) (*remoteLearner, error) {
+	if len(allowed) > maxAllowedCIDRs {
+		return nil, fmt.Errorf("too many allowed CIDRs (%d, maximum %d)", len(allowed), maxAllowedCIDRs)
+	}
+	for i := range allowed {
+		if allowed[i].IP.To4() == nil {
+			return nil, fmt.Errorf("unsupported IPv6 CIDR %s", allowed[i].String())
+		}
+	}
+
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_RAW|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, unix.IPPROTO_UDP)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open raw socket: %w", err)
+	}
+
+	learner := &remoteLearner{port: port, vni: vni, allowed: allowed, file: os.NewFile(uintptr(fd), "plain-learner")}
+	if err := learner.setKnownRemote(nil); err != nil {
+		learner.file.Close()
+		return nil, err
+	}
+	return learner, nil
+}
+
+// setKnownRemote configures the filter to discard the packets coming from the given address.
+func (l *remoteLearner) setKnownRemote(known net.IP) error {
+	instructions, err := bpf.Assemble(learnerFilter(l.port, l.vni, known, l.allowed))
+	if err != nil {
+		return fmt.Errorf("cannot assemble the learner filter: %w", err)
+	}
+
+	filter := make([]unix.SockFilter, len(instructions))
+	for i := range instructions {
+		filter[i] = unix.SockFilter{Code: instructions[i].Op, Jt: instructions[i].Jt, Jf: instructions[i].Jf, K: instructions[i].K}
+	}
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]} //nolint:gosec // the filter is short
+
+	raw, err := l.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptSockFprog(int(fd), unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, &prog)
+	}); err != nil || sockErr != nil {
+		return fmt.Errorf("cannot attach the learner filter: %w", errors.Join(err, sockErr))
+	}
+	return nil
+}
+
+// run reads the packets accepted by the filter, and invokes the handler with their source address,
+// until the given context is canceled.
+func (l *remoteLearner) run(ctx context.Context, handler func(net.IP)) error {
+	go func() {
+		<-ctx.Done()
+		l.file.Close()
+	}()
+
+	buf := make([]byte, learnerSnapLen)
+	for {
+		n, err := l.file.Read(buf)
+		switch {
+		case errors.Is(err, os.ErrClosed):
+			return nil
+		case err != nil:
+			klog.Errorf("Unable to read from the learner socket: %v", err)
+			continue
+		}
+		if src := parseSource(buf[:n]); src != nil {
+			handler(src)
+		}
+	}
+}
+
+// learnerFilter returns the BPF program accepting the UDP packets directed to the given port, carrying the given virtual
+// network identifier and coming from one of the allowed CIDRs, unless they come from the known address. Packets read from
+// raw sockets start with the IPv4 header, while both the GENEVE and the VXLAN headers carry the identifier in the 24 bits
+// following their fourth byte.
+func learnerFilter(port int, vni uint32, known net.IP, allowed []net.IPNet) []bpf.Instruction {
+	var knownValue uint32
+	if known4 := known.To4(); known4 != nil {
+		knownValue = binary.BigEndian.Uint32(known4)
+	}
+
+	// The jumps to the final instructions are resolved once the number of the allowed CIDRs is known.
+	const drop, accept uint8 = 0xff, 0xfe
+	instructions := []bpf.Instruction{
+		// Discard the non-first fragments, which do not carry the UDP header.
+		bpf.LoadAbsolute{Off: 6, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpBitsSet, Val: 0x1fff, SkipTrue: drop},
+		// Load the length of the IPv4 header, and check the UDP destination port.
+		bpf.LoadMemShift{Off: 0},
+		bpf.LoadIndirect{Off: 2, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: uint32(port), SkipTrue: drop}, //nolint:gosec // ports are 16-bit values
+		// Check the virtual network identifier, following the 8 bytes of the UDP header.
+		bpf.LoadIndirect{Off: 8 + 4, Size: 4},
+		bpf.ALUOpConstant{Op: bpf.ALUOpShiftRight, Val: 8},
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: vni, SkipTrue: drop},
+		// Check the source address.
+		bpf.LoadAbsolute{Off: 12, Size: 4},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: knownValue, SkipTrue: drop},
+	}
+	for i := range allowed {
+		instructions = append(instructions,
+			bpf.LoadAbsolute{Off: 12, Size: 4},
+			bpf.ALUOpConstant{Op: bpf.ALUOpAnd, Val: binary.BigEndian.Uint32(allowed[i].Mask)},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: binary.BigEndian.Uint32(allowed[i].IP.To4()), SkipTrue: accept},
+		)
+	}
+	instructions = append(instructions, bpf.RetConstant{Val: 0}, bpf.RetConstant{Val: learnerSnapLen})
+
+	// Resolve the jumps, relative to the instruction following the current one.
+	dropIndex, acceptIndex := len(instructions)-2, len(instructions)-1
+	for i := range instructions {
+		jump, ok := instructions[i].(bpf.JumpIf)
+		if !ok {
+			continue
+		}
+		switch jump.SkipTrue {
+		case drop:
+			jump.SkipTrue = uint8(dropIndex - i - 1) //nolint:gosec // the filter is short
+		case accept:
+			jump.SkipTrue = uint8(acceptIndex - i - 1) //nolint:gosec // the filter is short
+		}
+		instructions[i] = jump
+	}
+	return instructions
+}
+
+// parseSource returns the source address of the given IPv4 packet, or nil if it is malformed.
+func parseSource(packet []byte) net.IP {
+	if len(packet) < 20 || packet[0]>>4 != 4 {
+		return nil
+	}
+	return net.IPv4(packet[12], packet[13], packet[14], packet[15])
+}
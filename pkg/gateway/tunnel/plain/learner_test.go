@@ -0,0 +1,120 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"encoding/binary"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/bpf"
+)
+
+// forgePacket returns an IPv4 packet with the given header length, source address, fragment offset and UDP destination port,
+// carrying an encapsulation header with the given virtual network identifier.
+func forgePacket(headerLength int, src net.IP, fragmentOffset uint16, dport uint16, vni uint32) []byte {
+	packet := make([]byte, headerLength+8+8)
+	packet[0] = 0x40 | byte(headerLength/4)
+	binary.BigEndian.PutUint16(packet[6:8], fragmentOffset)
+	packet[9] = 17
+	copy(packet[12:16], src.To4())
+	binary.BigEndian.PutUint16(packet[headerLength+2:headerLength+4], dport)
+	binary.BigEndian.PutUint32(packet[headerLength+8+4:], vni<<8)
+	return packet
+}
+
+var _ = Describe("Remote learner", func() {
+	const (
+		port = 51840
+		vni  = 42
+	)
+
+	var (
+		known, other, outside net.IP
+		allowed               []net.IPNet
+	)
+
+	BeforeEach(func() {
+		known = net.ParseIP("10.0.0.1")
+		other = net.ParseIP("10.0.0.2")
+		outside = net.ParseIP("10.0.1.1")
+		_, cidr1, _ := net.ParseCIDR("10.0.0.0/24")
+		_, cidr2, _ := net.ParseCIDR("192.168.0.1/32")
+		allowed = []net.IPNet{*cidr1, *cidr2}
+	})
+
+	run := func(known net.IP, packet []byte) int {
+		vm, err := bpf.NewVM(learnerFilter(port, vni, known, allowed))
+		Expect(err).ToNot(HaveOccurred())
+		accepted, err := vm.Run(packet)
+		Expect(err).ToNot(HaveOccurred())
+		return accepted
+	}
+
+	DescribeTable("the learner filter",
+		func(packet func() []byte, expected bool) {
+			accepted := run(known, packet())
+			if expected {
+				Expect(accepted).To(BeNumerically(">", 0))
+			} else {
+				Expect(accepted).To(BeZero())
+			}
+		},
+		Entry("accepts the packets from a new address", func() []byte { return forgePacket(20, other, 0, port, vni) }, true),
+		Entry("accepts the packets from any allowed CIDR", func() []byte {
+			return forgePacket(20, net.ParseIP("192.168.0.1"), 0, port, vni)
+		}, true),
+		Entry("accepts the packets with IPv4 options", func() []byte { return forgePacket(24, other, 0, port, vni) }, true),
+		Entry("discards the packets from the known address", func() []byte { return forgePacket(20, known, 0, port, vni) }, false),
+		Entry("discards the packets from outside the allowed CIDRs", func() []byte { return forgePacket(20, outside, 0, port, vni) }, false),
+		Entry("discards the packets directed to other ports", func() []byte { return forgePacket(20, other, 0, port+1, vni) }, false),
+		Entry("discards the packets with another identifier", func() []byte { return forgePacket(20, other, 0, port, vni+1) }, false),
+		Entry("discards the non-first fragments", func() []byte { return forgePacket(20, other, 0x20, port, vni) }, false),
+	)
+
+	It("accepts the packets from any allowed address when no address is known", func() {
+		Expect(run(nil, forgePacket(20, known, 0, port, vni))).To(BeNumerically(">", 0))
+	})
+
+	It("discards all the packets when no CIDR is allowed", func() {
+		allowed = nil
+		Expect(run(nil, forgePacket(20, other, 0, port, vni))).To(BeZero())
+	})
+
+	It("parses the source address of the packets", func() {
+		Expect(parseSource(forgePacket(20, other, 0, port, vni)).Equal(other)).To(BeTrue())
+		Expect(parseSource([]byte{0x60, 0, 0})).To(BeNil())
+	})
+})
+
+var _ = Describe("Encapsulation", func() {
+	It("defaults to geneve", func() {
+		var encap Encapsulation
+		Expect(encap.Set("")).To(Succeed())
+		Expect(encap).To(Equal(EncapsulationGeneve))
+	})
+
+	It("accepts vxlan", func() {
+		var encap Encapsulation
+		Expect(encap.Set("vxlan")).To(Succeed())
+		Expect(encap).To(Equal(EncapsulationVXLAN))
+	})
+
+	It("rejects unknown values", func() {
+		var encap Encapsulation
+		Expect(encap.Set("gre")).ToNot(Succeed())
+	})
+})
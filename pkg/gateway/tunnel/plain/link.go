@@ -0,0 +1,219 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	"github.com/liqotech/liqo/pkg/utils/network/geneve"
+)
+
+const (
+	// DefaultVNI is the default virtual network identifier of the tunnel.
+	DefaultVNI = 1
+	// txQueueLen is the transmit queue length of the tunnel interface.
+	txQueueLen = 1000
+)
+
+// defaultFdbMAC is the MAC address of the default forwarding entry of VXLAN interfaces.
+var defaultFdbMAC = net.HardwareAddr{0, 0, 0, 0, 0, 0}
+
+// EnsureLink ensures that the tunnel interface points to the given remote address, and that it is assigned the tunnel IP address.
+// The remote address is updated in place, so that the tunnel interface is not flapped, while the interface is recreated only if its
+// encapsulation, port or identifier changed.
+func EnsureLink(handle *netlink.Handle, options *Options, remote net.IP) error {
+	desired := forgeLink(options, remote)
+
+	link, err := handle.LinkByName(tunnel.TunnelInterfaceName)
+	switch {
+	case errors.As(err, &netlink.LinkNotFoundError{}):
+		klog.Infof("Creating %s interface %q towards %s", options.Encapsulation, tunnel.TunnelInterfaceName, remote)
+		if link, err = addLink(handle, desired); err != nil {
+			return fmt.Errorf("cannot add %s interface: %w", options.Encapsulation, err)
+		}
+	case err != nil:
+		return fmt.Errorf("cannot check if %s interface exists: %w", options.Encapsulation, err)
+	case !linkMatches(link, desired):
+		klog.Infof("Recreating %s interface %q towards %s", options.Encapsulation, tunnel.TunnelInterfaceName, remote)
+		// The MTU is preserved, as it may have been tuned after the path MTU discovery.
+		desired.Attrs().MTU = link.Attrs().MTU
+		if err := handle.LinkDel(link); err != nil {
+			return fmt.Errorf("cannot delete %s interface: %w", options.Encapsulation, err)
+		}
+		if link, err = addLink(handle, desired); err != nil {
+			return fmt.Errorf("cannot add %s interface: %w", options.Encapsulation, err)
+		}
+	}
+
+	if err := ensureRemote(handle, link, remote); err != nil {
+		return fmt.Errorf("cannot point the %s interface to %s: %w", options.Encapsulation, remote, err)
+	}
+
+	addr, err := netlink.ParseAddr(tunnel.GetInterfaceIP(options.GwOptions.Mode))
+	if err != nil {
+		return err
+	}
+	addrs, err := handle.AddrList(link, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("cannot list the addresses of the %s interface: %w", options.Encapsulation, err)
+	}
+	if !containsAddr(addrs, addr) {
+		if err := handle.AddrAdd(link, addr); err != nil {
+			return fmt.Errorf("cannot set the address of the %s interface: %w", options.Encapsulation, err)
+		}
+	}
+
+	return handle.LinkSetUp(link)
+}
+
+// addLink adds the given link, and returns it as configured by the kernel.
+func addLink(handle *netlink.Handle, link netlink.Link) (netlink.Link, error) {
+	if err := handle.LinkAdd(link); err != nil {
+		return nil, err
+	}
+	return handle.LinkByName(link.Attrs().Name)
+}
+
+// ensureRemote points the given tunnel interface to the given remote address, without recreating it.
+func ensureRemote(handle *netlink.Handle, link netlink.Link, remote net.IP) error {
+	switch l := link.(type) {
+	case *netlink.Geneve:
+		if l.Remote.Equal(remote) {
+			return nil
+		}
+		klog.Infof("Pointing interface %q to %s", l.Name, remote)
+		// The port is omitted, as the kernel refuses to change it, even if unmodified.
+		return handle.LinkModify(&netlink.Geneve{
+			LinkAttrs: netlink.LinkAttrs{Index: l.Index, Name: l.Name, MTU: l.MTU, TxQLen: l.TxQLen},
+			ID:        l.ID,
+			Remote:    remote,
+		})
+	case *netlink.Vxlan:
+		return ensureVxlanRemote(handle, l, remote)
+	default:
+		return fmt.Errorf("unexpected interface type %q", link.Type())
+	}
+}
+
+// ensureVxlanRemote points the default forwarding entry of the given VXLAN interface to the given remote address.
+// The kernel does not allow to replace the default entry, hence the new destination is appended before removing
+// the previous ones.
+func ensureVxlanRemote(handle *netlink.Handle, link *netlink.Vxlan, remote net.IP) error {
+	entries, err := handle.NeighList(link.Index, unix.AF_BRIDGE)
+	if err != nil {
+		return fmt.Errorf("cannot list the forwarding entries: %w", err)
+	}
+
+	var stale []netlink.Neigh
+	var found bool
+	for i := range entries {
+		if !bytes.Equal(entries[i].HardwareAddr, defaultFdbMAC) {
+			continue
+		}
+		if entries[i].IP.Equal(remote) {
+			found = true
+			continue
+		}
+		stale = append(stale, entries[i])
+	}
+
+	if !found {
+		klog.Infof("Pointing interface %q to %s", link.Name, remote)
+		if err := handle.NeighAppend(forgeDefaultFdbEntry(link.Index, remote)); err != nil {
+			return fmt.Errorf("cannot add the forwarding entry towards %s: %w", remote, err)
+		}
+	}
+	for i := range stale {
+		if err := handle.NeighDel(forgeDefaultFdbEntry(link.Index, stale[i].IP)); err != nil {
+			return fmt.Errorf("cannot delete the forwarding entry towards %s: %w", stale[i].IP, err)
+		}
+	}
+	return nil
+}
+
+// forgeDefaultFdbEntry forges the default forwarding entry of a VXLAN interface, directing all the traffic to the given address.
+func forgeDefaultFdbEntry(index int, remote net.IP) *netlink.Neigh {
+	return &netlink.Neigh{
+		LinkIndex:    index,
+		Family:       unix.AF_BRIDGE,
+		State:        netlink.NUD_NOARP | netlink.NUD_PERMANENT,
+		Flags:        netlink.NTF_SELF,
+		IP:           remote,
+		HardwareAddr: defaultFdbMAC,
+	}
+}
+
+// localPort returns the port the encapsulated packets are sent to and received from, which must be the same on both sides.
+func localPort(options *Options) int {
+	if options.GwOptions.Mode == gateway.ModeClient {
+		return options.EndpointPort
+	}
+	return options.ListenPort
+}
+
+// forgeLink returns the tunnel interface pointing to the given remote address.
+// The VXLAN interface is created without a remote address, which is configured through its default forwarding entry
+// to be updated in place.
+func forgeLink(options *Options, remote net.IP) netlink.Link {
+	port := localPort(options)
+	switch options.Encapsulation {
+	case EncapsulationVXLAN:
+		return &netlink.Vxlan{
+			LinkAttrs: netlink.LinkAttrs{
+				Name:   tunnel.TunnelInterfaceName,
+				MTU:    options.MTU,
+				TxQLen: txQueueLen,
+			},
+			VxlanId: int(options.VNI),
+			Port:    port,
+		}
+	default:
+		return geneve.ForgeGeneveInterface(tunnel.TunnelInterfaceName, remote, options.VNI, options.MTU,
+			uint16(port)) //nolint:gosec // ports are 16-bit values
+	}
+}
+
+// linkMatches returns whether the existing link has the same type, port and identifier of the desired one,
+// regardless of the remote address, which can be changed in place.
+func linkMatches(existing, desired netlink.Link) bool {
+	switch d := desired.(type) {
+	case *netlink.Vxlan:
+		e, ok := existing.(*netlink.Vxlan)
+		return ok && e.Port == d.Port && e.VxlanId == d.VxlanId
+	case *netlink.Geneve:
+		e, ok := existing.(*netlink.Geneve)
+		return ok && e.Dport == d.Dport && e.ID == d.ID
+	default:
+		return false
+	}
+}
+
+func containsAddr(addrs []netlink.Addr, addr *netlink.Addr) bool {
+	for i := range addrs {
+		if addrs[i].Equal(*addr) {
+			return true
+		}
+	}
+	return false
+}
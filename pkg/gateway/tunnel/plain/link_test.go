@@ -0,0 +1,79 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+var _ = Describe("Tunnel interface", func() {
+	var (
+		options *Options
+		remote  net.IP
+	)
+
+	BeforeEach(func() {
+		options = NewOptions(&gateway.Options{Mode: gateway.ModeClient})
+		options.MTU = 1400
+		options.ListenPort = 51840
+		options.EndpointPort = 30000
+		options.VNI = DefaultVNI
+		remote = net.ParseIP("10.0.0.1")
+	})
+
+	It("forges a geneve interface towards the endpoint port in client mode", func() {
+		link, ok := forgeLink(options, remote).(*netlink.Geneve)
+		Expect(ok).To(BeTrue())
+		Expect(link.Remote.Equal(remote)).To(BeTrue())
+		Expect(link.Dport).To(BeEquivalentTo(30000))
+		Expect(link.ID).To(BeEquivalentTo(DefaultVNI))
+		Expect(link.MTU).To(Equal(1400))
+	})
+
+	It("forges a vxlan interface towards the listen port in server mode", func() {
+		options.GwOptions.Mode = gateway.ModeServer
+		options.Encapsulation = EncapsulationVXLAN
+		link, ok := forgeLink(options, remote).(*netlink.Vxlan)
+		Expect(ok).To(BeTrue())
+		// The remote address is configured through the default forwarding entry.
+		Expect(link.Group).To(BeNil())
+		Expect(link.Port).To(Equal(51840))
+	})
+
+	It("detects the interfaces to be recreated", func() {
+		desired := forgeLink(options, remote)
+		Expect(linkMatches(forgeLink(options, remote), desired)).To(BeTrue())
+
+		options.VNI = DefaultVNI + 1
+		Expect(linkMatches(forgeLink(options, remote), desired)).To(BeFalse())
+
+		options.VNI = DefaultVNI
+		options.Encapsulation = EncapsulationVXLAN
+		Expect(linkMatches(forgeLink(options, remote), desired)).To(BeFalse())
+	})
+
+	It("does not recreate the interfaces when only the remote address changes", func() {
+		for _, encap := range []Encapsulation{EncapsulationGeneve, EncapsulationVXLAN} {
+			options.Encapsulation = encap
+			Expect(linkMatches(forgeLink(options, net.ParseIP("10.0.0.2")), forgeLink(options, remote))).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+)
+
+// Encapsulation represents the encapsulation protocol of the plain tunnel.
+type Encapsulation string
+
+const (
+	// EncapsulationGeneve represents the GENEVE encapsulation.
+	EncapsulationGeneve Encapsulation = "geneve"
+	// EncapsulationVXLAN represents the VXLAN encapsulation.
+	EncapsulationVXLAN Encapsulation = "vxlan"
+)
+
+// String returns the string representation of the encapsulation.
+func (e Encapsulation) String() string {
+	return string(e)
+}
+
+// Set parses the provided string into the encapsulation.
+func (e *Encapsulation) Set(s string) error {
+	if s == "" {
+		s = EncapsulationGeneve.String()
+	}
+	if s != EncapsulationGeneve.String() && s != EncapsulationVXLAN.String() {
+		return fmt.Errorf("invalid encapsulation: %s (allowed values are: %s,%s)", s, EncapsulationGeneve, EncapsulationVXLAN)
+	}
+	*e = Encapsulation(s)
+	return nil
+}
+
+// Type returns the type of the encapsulation.
+func (e Encapsulation) Type() string {
+	return "string"
+}
+
+// Options contains the options for the plain tunnel.
+type Options struct {
+	GwOptions *gateway.Options
+
+	MTU              int
+	ListenPort       int
+	EndpointAddress  string
+	EndpointPort     int
+	Encapsulation    Encapsulation
+	VNI              uint32
+	DNSCheckInterval time.Duration
+
+	// AllowedClientCIDRs are the CIDRs the server learns the address of the client from.
+	AllowedClientCIDRs argsutils.CIDRList
+	// RemoteChangeInterval is the minimum interval between two changes of the learned address of the client.
+	RemoteChangeInterval time.Duration
+
+	// AcknowledgeUnencrypted is the explicit acknowledgement that the inter-cluster traffic is not encrypted.
+	AcknowledgeUnencrypted bool
+}
+
+// NewOptions returns a new Options struct.
+func NewOptions(options *gateway.Options) *Options {
+	return &Options{
+		GwOptions:     options,
+		Encapsulation: EncapsulationGeneve,
+	}
+}
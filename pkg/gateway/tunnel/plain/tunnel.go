@@ -0,0 +1,191 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+const (
+	// retryInterval is the interval between two attempts, while the connection resource is not created
+	// or the endpoint address is not resolved.
+	retryInterval = time.Second
+)
+
+var _ manager.Runnable = &Tunnel{}
+
+// Tunnel manages the plain tunnel with the remote gateway. The client points the tunnel interface to the address
+// of the server, periodically resolving it if it is a hostname. The server, instead, learns the address of the client
+// from the encapsulated packets it receives, and points the tunnel interface to it.
+type Tunnel struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	options *Options
+	handle  *netlink.Handle
+	learner *remoteLearner
+
+	mutex  sync.Mutex
+	remote net.IP
+	// remoteChanged is the time the remote address last changed.
+	remoteChanged time.Time
+}
+
+// NewTunnel returns a new Tunnel, configuring the tunnel interface through the given netlink handle.
+func NewTunnel(cl client.Client, s *runtime.Scheme, options *Options, handle *netlink.Handle) *Tunnel {
+	return &Tunnel{
+		client:  cl,
+		scheme:  s,
+		options: options,
+		handle:  handle,
+	}
+}
+
+// Listen opens the socket used by the server to learn the address of the client. It is a no-op in client mode.
+// The socket is created in the network namespace of the calling thread.
+func (t *Tunnel) Listen() error {
+	if t.options.GwOptions.Mode != gateway.ModeServer {
+		return nil
+	}
+
+	learner, err := newRemoteLearner(t.options.ListenPort, t.options.VNI, t.options.AllowedClientCIDRs.CIDRList)
+	if err != nil {
+		return fmt.Errorf("cannot listen on UDP port %d: %w", t.options.ListenPort, err)
+	}
+	t.learner = learner
+	return nil
+}
+
+// Remote returns the address of the remote gateway the tunnel interface points to, or nil if not yet known.
+func (t *Tunnel) Remote() net.IP {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.remote
+}
+
+// Start runs the tunnel until the given context is canceled. Listen must be called beforehand.
+func (t *Tunnel) Start(ctx context.Context) error {
+	if t.options.GwOptions.Mode == gateway.ModeServer && t.learner == nil {
+		return errors.New("the learner socket is not open")
+	}
+
+	// The connection resource is created upfront, as there are no keys to wait for.
+	if err := wait.PollUntilContextCancel(ctx, retryInterval, true, func(ctx context.Context) (bool, error) {
+		if err := EnsureConnection(ctx, t.client, t.scheme, t.options); err != nil {
+			klog.Errorf("Unable to ensure the connection: %v", err)
+			return false, nil
+		}
+		return true, nil
+	}); err != nil {
+		return nil
+	}
+
+	if t.options.GwOptions.Mode == gateway.ModeServer {
+		return t.learner.run(ctx, t.learnRemote)
+	}
+	t.runClient(ctx)
+	return nil
+}
+
+// runClient points the tunnel interface to the address of the server, refreshing it if it is a hostname.
+func (t *Tunnel) runClient(ctx context.Context) {
+	for {
+		interval := t.options.DNSCheckInterval
+		remote, err := resolveEndpoint(ctx, t.options.EndpointAddress)
+		switch {
+		case err != nil:
+			klog.Errorf("Unable to resolve the endpoint address %q: %v", t.options.EndpointAddress, err)
+			interval = retryInterval
+		case !t.setRemote(remote):
+			interval = retryInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// learnRemote points the tunnel interface to the address learned from the received traffic, unless the remote address
+// changed too recently, to prevent a misbehaving client from continuously re-pointing the tunnel. The packets from the
+// new address keep reaching the learner, hence the change is applied once the interval expires.
+func (t *Tunnel) learnRemote(remote net.IP) {
+	t.mutex.Lock()
+	wait := t.options.RemoteChangeInterval - time.Since(t.remoteChanged)
+	known := t.remote != nil
+	t.mutex.Unlock()
+
+	if known && wait > 0 {
+		klog.V(4).Infof("Ignoring the traffic from %s, as the remote address changed less than %v ago", remote, t.options.RemoteChangeInterval)
+		return
+	}
+	t.setRemote(remote)
+}
+
+// setRemote points the tunnel interface to the given remote address, returning whether it succeeded.
+func (t *Tunnel) setRemote(remote net.IP) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.remote.Equal(remote) {
+		return true
+	}
+
+	if err := EnsureLink(t.handle, t.options, remote); err != nil {
+		klog.Errorf("Unable to point the tunnel interface to %s: %v", remote, err)
+		return false
+	}
+	klog.Infof("Tunnel interface pointed to the remote gateway %s", remote)
+	t.remote = remote
+	t.remoteChanged = time.Now()
+
+	if t.learner != nil {
+		if err := t.learner.setKnownRemote(remote); err != nil {
+			klog.Errorf("Unable to update the learner filter: %v", err)
+		}
+	}
+	return true
+}
+
+// resolveEndpoint resolves the given endpoint address to an IPv4 address.
+func resolveEndpoint(ctx context.Context, address string) (net.IP, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return ip, nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", address)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found")
+	}
+	return ips[0], nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// dialTimeout is the maximum time allowed to establish a stream with the server.
+	dialTimeout = 10 * time.Second
+	// retryInterval is the interval between the attempts to establish a stream with the server.
+	retryInterval = 2 * time.Second
+)
+
+// Client exposes a local UDP socket, and relays the datagrams received on it to the server through a stream,
+// re-establishing it (and re-resolving the server addresses) whenever it breaks.
+type Client struct {
+	transport Transport
+	addresses []string
+	localPort int
+	tlsConfig *tls.Config
+
+	conn *net.UDPConn
+
+	mutex  sync.Mutex
+	stream datagramConn
+	peer   *net.UDPAddr
+}
+
+var _ manager.Runnable = &Client{}
+
+// NewClient returns a new Client, relaying the datagrams received on the given local port
+// to the server at the given addresses and port, through the given transport.
+// The addresses are tried in order, until a stream is established with one of them.
+// If the TLS configuration is not nil, the WebSocket streams are established over TLS, and its server name,
+// if any, is used as the HTTP host as well.
+func NewClient(transport Transport, addresses []string, port, localPort int, tlsConfig *tls.Config) *Client {
+	hostPorts := make([]string, len(addresses))
+	for i := range addresses {
+		hostPorts[i] = net.JoinHostPort(addresses[i], strconv.Itoa(port))
+	}
+	return &Client{
+		transport: transport,
+		addresses: hostPorts,
+		localPort: localPort,
+		tlsConfig: tlsConfig,
+	}
+}
+
+// Listen opens the local UDP socket, bound to the loopback address.
+func (c *Client) Listen() error {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: c.localPort})
+	if err != nil {
+		return fmt.Errorf("unable to listen on local port %d: %w", c.localPort, err)
+	}
+	c.conn = conn
+	return nil
+}
+
+// LocalAddr returns the address of the local UDP socket, the tunnel shall be pointed to.
+func (c *Client) LocalAddr() *net.UDPAddr {
+	return c.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Start starts the client, until the context is canceled.
+func (c *Client) Start(ctx context.Context) error {
+	if c.conn == nil {
+		return fmt.Errorf("the local socket is not open")
+	}
+
+	go func() {
+		<-ctx.Done()
+		c.conn.Close()
+		c.setStream(nil)
+	}()
+	go c.forwardToStream()
+
+	for {
+		stream, address, err := c.dialAny(ctx)
+		if err != nil {
+			klog.Errorf("Failed to establish the %s stream with %v: %v", c.transport, c.addresses, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryInterval):
+				continue
+			}
+		}
+
+		klog.Infof("Tunnel stream with %s established through the %s transport", address, c.transport)
+		c.setStream(stream)
+		c.forwardFromStream(stream)
+		c.setStream(nil)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		klog.Warningf("Tunnel stream with %s closed, reconnecting", address)
+	}
+}
+
+// dialAny establishes a new stream with the first reachable server address, returning it.
+func (c *Client) dialAny(ctx context.Context) (datagramConn, string, error) {
+	var errs []error
+	for _, address := range c.addresses {
+		stream, err := c.dial(ctx, address)
+		if err == nil {
+			return stream, address, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", address, err))
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// dial establishes a new stream with the server at the given address.
+func (c *Client) dial(ctx context.Context, address string) (datagramConn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	switch c.transport {
+	case TransportTCP:
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write([]byte(tcpPreamble)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return &tcpConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+	case TransportWebSocket:
+		webSocketDialer := &websocket.Dialer{
+			// Go through the HTTP proxy, if configured, as typical of restrictive networks.
+			Proxy:            http.ProxyFromEnvironment,
+			NetDialContext:   dialer.DialContext,
+			HandshakeTimeout: dialTimeout,
+			TLSClientConfig:  c.tlsConfig,
+		}
+		target := url.URL{Scheme: "ws", Host: address, Path: webSocketPath}
+		var header http.Header
+		if c.tlsConfig != nil {
+			target.Scheme = "wss"
+			if c.tlsConfig.ServerName != "" {
+				header = http.Header{"Host": []string{c.tlsConfig.ServerName}}
+			}
+		}
+		conn, resp, err := webSocketDialer.DialContext(ctx, target.String(), header)
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &webSocketConn{conn: conn}, nil
+	default:
+		return nil, fmt.Errorf("unsupported transport %q", c.transport)
+	}
+}
+
+// setStream sets the current stream, closing the previous one.
+func (c *Client) setStream(stream datagramConn) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stream != nil {
+		c.stream.Close()
+	}
+	c.stream = stream
+}
+
+// forwardToStream forwards the datagrams received on the local socket to the current stream,
+// dropping them while the stream is not established.
+func (c *Client) forwardToStream() {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		size, peer, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				klog.Errorf("Failed to receive the datagrams on the local socket: %v", err)
+			}
+			return
+		}
+
+		c.mutex.Lock()
+		c.peer = peer
+		stream := c.stream
+		c.mutex.Unlock()
+
+		if stream == nil {
+			continue
+		}
+		if err := stream.WriteDatagram(buf[:size]); err != nil {
+			// Closing the stream triggers the reconnection.
+			stream.Close()
+		}
+	}
+}
+
+// forwardFromStream forwards the datagrams received from the stream to the local peer, until the stream breaks.
+func (c *Client) forwardFromStream(stream datagramConn) {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		size, err := stream.ReadDatagram(buf)
+		if err != nil {
+			return
+		}
+
+		c.mutex.Lock()
+		peer := c.peer
+		c.mutex.Unlock()
+
+		if peer == nil {
+			continue
+		}
+		if _, err := c.conn.WriteToUDP(buf[:size], peer); errors.Is(err, net.ErrClosed) {
+			return
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// tcpPreamble is sent by the clients at the beginning of the TCP streams, to distinguish them from the WebSocket ones.
+	tcpPreamble = "LQTN"
+	// webSocketPath is the HTTP path of the WebSocket endpoint.
+	webSocketPath = "/liqo/tunnel"
+	// maxDatagramSize is the maximum size of the datagrams carried over the streams.
+	maxDatagramSize = 65535
+	// idleTimeout is the maximum interval without datagrams received from a stream, before it is considered broken.
+	idleTimeout = time.Minute
+)
+
+// datagramConn is a stream carrying datagrams.
+type datagramConn interface {
+	// ReadDatagram reads the next datagram into the given buffer, returning its size.
+	ReadDatagram(buf []byte) (int, error)
+	// WriteDatagram writes the given datagram.
+	WriteDatagram(datagram []byte) error
+	// Close closes the stream.
+	Close() error
+}
+
+// tcpConn carries the datagrams over a TCP stream, each one prefixed by its length.
+type tcpConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+var _ datagramConn = &tcpConn{}
+
+// ReadDatagram implements datagramConn.
+func (c *tcpConn) ReadDatagram(buf []byte) (int, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+		return 0, err
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, err
+	}
+	size := int(binary.BigEndian.Uint16(header[:]))
+	if size > len(buf) {
+		return 0, fmt.Errorf("datagram of %d bytes exceeds the buffer size", size)
+	}
+	return io.ReadFull(c.reader, buf[:size])
+}
+
+// WriteDatagram implements datagramConn.
+func (c *tcpConn) WriteDatagram(datagram []byte) error {
+	if len(datagram) > maxDatagramSize {
+		return fmt.Errorf("datagram of %d bytes exceeds the maximum size", len(datagram))
+	}
+	frame := make([]byte, 2+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram))) //nolint:gosec // the size has been checked above
+	copy(frame[2:], datagram)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close implements datagramConn.
+func (c *tcpConn) Close() error {
+	return c.conn.Close()
+}
+
+// webSocketConn carries the datagrams over a WebSocket stream, each one as a binary message.
+type webSocketConn struct {
+	conn *websocket.Conn
+}
+
+var _ datagramConn = &webSocketConn{}
+
+// ReadDatagram implements datagramConn.
+func (c *webSocketConn) ReadDatagram(buf []byte) (int, error) {
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+			return 0, err
+		}
+
+		messageType, reader, err := c.conn.NextReader()
+		if err != nil {
+			return 0, err
+		}
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+
+		size, err := io.ReadFull(reader, buf)
+		switch {
+		case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+			return size, nil
+		case err != nil:
+			return 0, err
+		}
+		// The buffer is full: make sure the message is not larger.
+		if n, _ := reader.Read(make([]byte, 1)); n > 0 {
+			return 0, fmt.Errorf("datagram exceeds the buffer size")
+		}
+		return size, nil
+	}
+}
+
+// WriteDatagram implements datagramConn.
+func (c *webSocketConn) WriteDatagram(datagram []byte) error {
+	return c.conn.WriteMessage(websocket.BinaryMessage, datagram)
+}
+
+// Close implements datagramConn.
+func (c *webSocketConn) Close() error {
+	return c.conn.Close()
+}
+
+// bufferedConn is a net.Conn whose initial bytes have already been read into a buffered reader.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read implements net.Conn.
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// releasingConn is a net.Conn releasing the associated resources once closed.
+type releasingConn struct {
+	net.Conn
+	release func()
+	once    sync.Once
+}
+
+// Close implements net.Conn.
+func (c *releasingConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
@@ -0,0 +1,20 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stream carries the UDP datagrams of the inter-cluster tunnel over TCP or WebSocket streams,
+// to establish the tunnel through networks blocking the UDP traffic.
+// The server accepts both the transports on the same port, relaying the datagrams to the local tunnel socket,
+// while the client exposes a local UDP socket the tunnel is pointed to, and relays the datagrams to the server.
+// The WebSocket streams can be secured with TLS, to traverse the proxies only allowing the HTTPS traffic.
+package stream
@@ -0,0 +1,255 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// handshakeTimeout is the maximum time allowed to the clients to select the transport.
+	handshakeTimeout = 10 * time.Second
+	// tlsRecordTypeHandshake is the first byte of the TLS handshakes, which distinguishes the secure WebSocket streams.
+	tlsRecordTypeHandshake = 0x16
+)
+
+// Server accepts the TCP and WebSocket streams on the same port, relaying the carried datagrams
+// to the local tunnel socket through a dedicated UDP socket for each stream.
+type Server struct {
+	listenPort int
+	target     *net.UDPAddr
+	upgrader   websocket.Upgrader
+	tlsConfig  *tls.Config
+	// streams bounds the number of concurrent connections, as they are accepted before the peer is authenticated by WireGuard.
+	streams chan struct{}
+}
+
+var _ manager.Runnable = &Server{}
+
+// NewServer returns a new Server listening on the given port, and relaying the datagrams to the given target.
+// If the TLS configuration is not nil, the WebSocket streams are accepted over TLS as well.
+// At most maxStreams connections are served concurrently, while the exceeding ones are closed.
+func NewServer(listenPort int, target *net.UDPAddr, tlsConfig *tls.Config, maxStreams int) *Server {
+	return &Server{
+		listenPort: listenPort,
+		target:     target,
+		tlsConfig:  tlsConfig,
+		streams:    make(chan struct{}, maxStreams),
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: handshakeTimeout,
+			// The tunnel is not accessed by browsers, hence there is no need to check the origin.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+// Start starts the server, until the context is canceled.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.listenPort))
+	if err != nil {
+		return fmt.Errorf("unable to listen on port %d: %w", s.listenPort, err)
+	}
+	klog.Infof("Accepting the tunnel streams on port %d", s.listenPort)
+
+	// The WebSocket handshakes are handled by an HTTP server, fed with the connections not selecting the TCP transport.
+	webSocketListener := newConnListener(listener.Addr())
+	httpServer := &http.Server{
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { s.serveWebSocket(ctx, w, r) }),
+		ReadHeaderTimeout: handshakeTimeout,
+		// Release the connections not upgraded to a WebSocket, which would otherwise hold a stream slot.
+		IdleTimeout: handshakeTimeout,
+	}
+	go func() {
+		if err := httpServer.Serve(webSocketListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("Failed to serve the WebSocket streams: %v", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+		httpServer.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to accept the tunnel streams: %w", err)
+		}
+
+		select {
+		case s.streams <- struct{}{}:
+		default:
+			klog.Warningf("Rejecting the stream from %s, as the maximum number of streams (%d) is reached", conn.RemoteAddr(), cap(s.streams))
+			conn.Close()
+			continue
+		}
+		go s.handle(ctx, &releasingConn{Conn: conn, release: func() { <-s.streams }}, webSocketListener)
+	}
+}
+
+// handle selects the transport of a new connection, based on its initial bytes.
+func (s *Server) handle(ctx context.Context, conn net.Conn, webSocketListener *connListener) {
+	reader := bufio.NewReader(conn)
+	if err := conn.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		conn.Close()
+		return
+	}
+	preamble, err := reader.Peek(len(tcpPreamble))
+	if err != nil {
+		klog.Warningf("Failed to read the preamble of the stream from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if string(preamble) != tcpPreamble {
+		var webSocketConn net.Conn = &bufferedConn{Conn: conn, reader: reader}
+		if s.tlsConfig != nil && preamble[0] == tlsRecordTypeHandshake {
+			webSocketConn = tls.Server(webSocketConn, s.tlsConfig)
+		}
+		webSocketListener.push(webSocketConn)
+		return
+	}
+
+	if _, err := reader.Discard(len(tcpPreamble)); err != nil {
+		conn.Close()
+		return
+	}
+	s.relay(ctx, &tcpConn{conn: conn, reader: reader}, conn.RemoteAddr())
+}
+
+// serveWebSocket upgrades the HTTP connection to a WebSocket, and relays the carried datagrams.
+func (s *Server) serveWebSocket(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != webSocketPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with the error.
+		klog.Warningf("Failed to upgrade the stream from %s: %v", r.RemoteAddr, err)
+		return
+	}
+	s.relay(ctx, &webSocketConn{conn: conn}, conn.RemoteAddr())
+}
+
+// relay forwards the datagrams received from the stream to the target, and vice versa, until either side fails.
+func (s *Server) relay(ctx context.Context, stream datagramConn, remote net.Addr) {
+	udpConn, err := net.DialUDP("udp", nil, s.target)
+	if err != nil {
+		klog.Errorf("Failed to relay the stream from %s: %v", remote, err)
+		stream.Close()
+		return
+	}
+
+	klog.Infof("Tunnel stream from %s established", remote)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+		udpConn.Close()
+	}()
+
+	go func() {
+		defer cancel()
+		buf := make([]byte, maxDatagramSize)
+		for {
+			size, err := udpConn.Read(buf)
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					klog.Warningf("Failed to receive the datagrams for the stream from %s: %v", remote, err)
+				}
+				return
+			}
+			if err := stream.WriteDatagram(buf[:size]); err != nil {
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		size, err := stream.ReadDatagram(buf)
+		if err != nil {
+			break
+		}
+		// Sending errors are transient (e.g., the tunnel socket is being recreated), and the datagram is simply lost.
+		if _, err := udpConn.Write(buf[:size]); errors.Is(err, net.ErrClosed) {
+			break
+		}
+	}
+	klog.Infof("Tunnel stream from %s closed", remote)
+}
+
+// connListener is a net.Listener returning the connections pushed to it.
+type connListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+var _ net.Listener = &connListener{}
+
+func newConnListener(addr net.Addr) *connListener {
+	return &connListener{addr: addr, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+// push hands the given connection to the listener, closing it if the listener is closed.
+func (l *connListener) push(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+// Accept implements net.Listener.
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener.
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+// Addr implements net.Listener.
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
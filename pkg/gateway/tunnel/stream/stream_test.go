@@ -0,0 +1,237 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// freePort returns a currently free TCP port on the loopback address.
+func freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// startEcho starts a UDP server replying to each datagram with its uppercase version.
+func startEcho(ctx context.Context) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	Expect(err).ToNot(HaveOccurred())
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+		for {
+			size, peer, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			reply := make([]byte, size)
+			for i := range buf[:size] {
+				reply[i] = buf[i] &^ 0x20
+			}
+			_, _ = conn.WriteToUDP(reply, peer)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+// writeCertificate writes a self-signed certificate for the given server name, and its key, to the given directory,
+// returning the paths of the certificate (which is its own certificate authority) and of the key.
+func writeCertificate(dir, serverName string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: serverName},
+		DNSNames:              []string{serverName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)).To(Succeed())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0o600)).To(Succeed())
+	return certFile, keyFile
+}
+
+var _ = Describe("Stream transport", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		port   int
+		caFile string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		port = freePort()
+
+		var keyFile string
+		caFile, keyFile = writeCertificate(GinkgoT().TempDir(), "gateway.example.com")
+		tlsConfig, err := ServerTLSConfig(caFile, keyFile)
+		Expect(err).ToNot(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(NewServer(port, startEcho(ctx), tlsConfig, 4).Start(ctx)).To(Succeed())
+		}()
+	})
+
+	AfterEach(func() { cancel() })
+
+	DescribeTable("relaying the datagrams through the server",
+		func(transport Transport, secure bool) {
+			var tlsConfig *tls.Config
+			if secure {
+				var err error
+				// The server is reached through its address, hence the server name must be overridden.
+				tlsConfig, err = ClientTLSConfig(caFile, "gateway.example.com")
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			client := NewClient(transport, []string{"127.0.0.1"}, port, 0, tlsConfig)
+			Expect(client.Listen()).To(Succeed())
+			go func() {
+				defer GinkgoRecover()
+				Expect(client.Start(ctx)).To(Succeed())
+			}()
+
+			conn, err := net.DialUDP("udp", nil, client.LocalAddr())
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			// Datagrams are dropped until the stream is established, hence keep sending until a reply is received.
+			buf := make([]byte, maxDatagramSize)
+			Eventually(func() string {
+				_, err := conn.Write([]byte("ping"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))).To(Succeed())
+				size, err := conn.Read(buf)
+				if err != nil {
+					return ""
+				}
+				return string(buf[:size])
+			}).WithTimeout(10 * time.Second).Should(Equal("PING"))
+
+			// Larger datagrams are carried as well.
+			datagram := make([]byte, 1420)
+			for i := range datagram {
+				datagram[i] = 'a'
+			}
+			_, err = conn.Write(datagram)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+			size, err := conn.Read(buf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(len(datagram)))
+			Expect(buf[0]).To(Equal(byte('A')))
+		},
+		Entry("over TCP", TransportTCP, false),
+		Entry("over WebSocket", TransportWebSocket, false),
+		Entry("over WebSocket with TLS", TransportWebSocket, true),
+	)
+
+	It("should reject the streams exceeding the maximum number", func() {
+		// The connections not selecting the transport hold a slot until the handshake timeout.
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for range 4 {
+			var conn net.Conn
+			// The server may not be listening yet.
+			Eventually(func() (err error) {
+				conn, err = net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+				return err
+			}).WithTimeout(5 * time.Second).Should(Succeed())
+			conns = append(conns, conn)
+		}
+
+		// The server closes the exceeding connection straight away.
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+			Expect(conn.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+			_, err = conn.Read(make([]byte, 1))
+			return err
+		}).WithTimeout(5 * time.Second).Should(MatchError(io.EOF))
+
+		// Once a slot is released, the connections are accepted again.
+		conns[0].Close()
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+			Expect(conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))).To(Succeed())
+			_, err = conn.Read(make([]byte, 1))
+			return err
+		}).WithTimeout(5 * time.Second).Should(MatchError(os.ErrDeadlineExceeded))
+	})
+})
+
+var _ = Describe("Transport", func() {
+	It("defaults to udp", func() {
+		var transport Transport
+		Expect(transport.Set("")).To(Succeed())
+		Expect(transport).To(Equal(TransportUDP))
+		Expect(transport.IsStream()).To(BeFalse())
+	})
+
+	It("rejects unknown values", func() {
+		var transport Transport
+		Expect(transport.Set("quic")).ToNot(Succeed())
+	})
+
+	DescribeTable("selecting the transport given the endpoint protocol",
+		func(preferred Transport, protocol string, expected Transport) {
+			Expect(ForEndpointProtocol(preferred, protocol)).To(Equal(expected))
+		},
+		Entry("uses udp with an UDP endpoint", TransportWebSocket, "UDP", TransportUDP),
+		Entry("uses udp with an unknown protocol", TransportTCP, "<nil>", TransportUDP),
+		Entry("uses the preferred transport with a TCP endpoint", TransportWebSocket, "TCP", TransportWebSocket),
+		Entry("falls back to tcp with a TCP endpoint", TransportUDP, "TCP", TransportTCP),
+	)
+})
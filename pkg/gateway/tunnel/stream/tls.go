@@ -0,0 +1,61 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ServerTLSConfig returns the TLS configuration of the server, loading the certificate and the key from the given files.
+// They are loaded at every handshake, so that the renewed certificates are used without restarting the server.
+func ServerTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	// Load the certificate upfront, to catch the configuration errors immediately.
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return nil, fmt.Errorf("unable to load the TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the TLS certificate: %w", err)
+			}
+			return &cert, nil
+		},
+	}, nil
+}
+
+// ClientTLSConfig returns the TLS configuration of the client, trusting the certificate authorities in the given file,
+// or the system ones if empty. The server name, if not empty, overrides the one derived from the server address,
+// both in the TLS handshake (SNI) and in the certificate verification.
+func ClientTLSConfig(caFile, serverName string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: serverName}
+	if caFile == "" {
+		return config, nil
+	}
+
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the certificate authorities: %w", err)
+	}
+	config.RootCAs = x509.NewCertPool()
+	if !config.RootCAs.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no valid certificate authority found in %s", caFile)
+	}
+	return config, nil
+}
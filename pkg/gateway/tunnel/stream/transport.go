@@ -0,0 +1,76 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Transport represents the transport carrying the datagrams of the tunnel.
+type Transport string
+
+const (
+	// TransportUDP represents the native UDP transport, which does not involve any stream.
+	TransportUDP Transport = "udp"
+	// TransportTCP represents the transport over a TCP stream.
+	TransportTCP Transport = "tcp"
+	// TransportWebSocket represents the transport over a WebSocket stream.
+	TransportWebSocket Transport = "websocket"
+)
+
+// String returns the string representation of the transport.
+func (t Transport) String() string {
+	return string(t)
+}
+
+// Set parses the provided string into the transport.
+func (t *Transport) Set(s string) error {
+	if s == "" {
+		s = TransportUDP.String()
+	}
+	switch Transport(s) {
+	case TransportUDP, TransportTCP, TransportWebSocket:
+		*t = Transport(s)
+		return nil
+	default:
+		return fmt.Errorf("invalid transport: %s (allowed values are: %s,%s,%s)", s, TransportUDP, TransportTCP, TransportWebSocket)
+	}
+}
+
+// Type returns the type of the transport.
+func (t Transport) Type() string {
+	return "string"
+}
+
+// IsStream returns whether the transport carries the datagrams over a stream.
+func (t Transport) IsStream() bool {
+	return t == TransportTCP || t == TransportWebSocket
+}
+
+// ForEndpointProtocol returns the transport to be used by a client, given the preferred one and the protocol
+// advertised by the server endpoint. The advertised protocol prevails: a server exposed over TCP is reached
+// through the preferred stream transport (TCP, if none), while a server exposed over UDP through the native transport.
+func ForEndpointProtocol(preferred Transport, protocol string) Transport {
+	if !strings.EqualFold(protocol, string(corev1.ProtocolTCP)) {
+		return TransportUDP
+	}
+	if preferred.IsStream() {
+		return preferred
+	}
+	return TransportTCP
+}
@@ -17,6 +17,8 @@ package wireguard
 import (
 	"fmt"
 	"net"
+	"slices"
+	"time"
 
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -26,29 +28,49 @@ import (
 	"github.com/liqotech/liqo/pkg/gateway/tunnel"
 )
 
-func configureDevice(wgcl *wgctrl.Client, options *Options, peerPubKey wgtypes.Key) error {
+var allowedIPs = []net.IPNet{{IP: net.IP{0, 0, 0, 0}, Mask: net.CIDRMask(0, 32)}}
+
+// configureDevice configures the interface with the local private key and the given public keys of the remote gateway.
+// Multiple keys are accepted during a key rotation: all of them are configured as peers, while the allowed IPs
+// (hence, the traffic) are assigned to the one which most recently completed a handshake.
+func configureDevice(wgcl *wgctrl.Client, options *Options, peerKeys []wgtypes.Key) error {
+	options.KeysMutex.Lock()
+	defer options.KeysMutex.Unlock()
+
+	device, err := wgcl.Device(tunnel.TunnelInterfaceName)
+	if err != nil {
+		return fmt.Errorf("unable to get the device %s: %w", tunnel.TunnelInterfaceName, err)
+	}
+	active := activePeer(device, peerKeys)
+
 	confdev := wgtypes.Config{
 		PrivateKey: &options.PrivateKey,
 		ListenPort: nil,
-		Peers: []wgtypes.PeerConfig{
-			{
-				PublicKey:  peerPubKey,
-				AllowedIPs: []net.IPNet{{IP: net.IP{0, 0, 0, 0}, Mask: net.CIDRMask(0, 32)}},
-			},
-		},
-		ReplacePeers: true,
 	}
-
-	switch options.GwOptions.Mode {
-	case gateway.ModeServer:
+	if options.GwOptions.Mode == gateway.ModeServer {
 		confdev.ListenPort = &options.ListenPort
-	case gateway.ModeClient:
-		confdev.Peers[0].Endpoint = &net.UDPAddr{
-			IP:   options.EndpointIP,
-			Port: options.EndpointPort,
+	}
+
+	for i := range device.Peers {
+		if !slices.Contains(peerKeys, device.Peers[i].PublicKey) {
+			confdev.Peers = append(confdev.Peers, wgtypes.PeerConfig{PublicKey: device.Peers[i].PublicKey, Remove: true})
 		}
 	}
 
+	for _, key := range peerKeys {
+		peer := wgtypes.PeerConfig{PublicKey: key, ReplaceAllowedIPs: true}
+		if key == active {
+			peer.AllowedIPs = allowedIPs
+		}
+		if options.GwOptions.Mode == gateway.ModeClient {
+			peer.Endpoint = &net.UDPAddr{
+				IP:   options.GetEndpointIP(),
+				Port: options.EndpointPort,
+			}
+		}
+		confdev.Peers = append(confdev.Peers, peer)
+	}
+
 	klog.Infof("Configuring device %s", tunnel.TunnelInterfaceName)
 
 	if err := wgcl.ConfigureDevice(tunnel.TunnelInterfaceName, confdev); err != nil {
@@ -56,3 +78,57 @@ func configureDevice(wgcl *wgctrl.Client, options *Options, peerPubKey wgtypes.K
 	}
 	return nil
 }
+
+// syncActivePeer assigns the allowed IPs to the peer which most recently completed a handshake, if multiple peers
+// are configured. This way, the traffic is moved to the new key of the remote gateway as soon as it switches to it.
+func syncActivePeer(wgcl *wgctrl.Client, options *Options) error {
+	options.KeysMutex.Lock()
+	defer options.KeysMutex.Unlock()
+
+	device, err := wgcl.Device(tunnel.TunnelInterfaceName)
+	if err != nil {
+		return fmt.Errorf("unable to get the device %s: %w", tunnel.TunnelInterfaceName, err)
+	}
+	if len(device.Peers) < 2 {
+		return nil
+	}
+
+	keys := make([]wgtypes.Key, len(device.Peers))
+	for i := range device.Peers {
+		keys[i] = device.Peers[i].PublicKey
+	}
+	active := activePeer(device, keys)
+
+	var confdev wgtypes.Config
+	for i := range device.Peers {
+		peer := &device.Peers[i]
+		switch {
+		case peer.PublicKey == active && len(peer.AllowedIPs) == 0:
+			confdev.Peers = append(confdev.Peers, wgtypes.PeerConfig{
+				PublicKey: peer.PublicKey, UpdateOnly: true, ReplaceAllowedIPs: true, AllowedIPs: allowedIPs})
+		case peer.PublicKey != active && len(peer.AllowedIPs) > 0:
+			confdev.Peers = append(confdev.Peers, wgtypes.PeerConfig{
+				PublicKey: peer.PublicKey, UpdateOnly: true, ReplaceAllowedIPs: true})
+		}
+	}
+	if len(confdev.Peers) == 0 {
+		return nil
+	}
+
+	klog.Infof("Moving the traffic of device %s to the peer with public key %s", tunnel.TunnelInterfaceName, active)
+	return wgcl.ConfigureDevice(tunnel.TunnelInterfaceName, confdev)
+}
+
+// activePeer returns the key, among the given ones, of the peer which most recently completed a handshake.
+// It defaults to the first key, if none of them completed a handshake yet.
+func activePeer(device *wgtypes.Device, keys []wgtypes.Key) wgtypes.Key {
+	active := keys[0]
+	var latest time.Time
+	for i := range device.Peers {
+		peer := &device.Peers[i]
+		if slices.Contains(keys, peer.PublicKey) && peer.LastHandshakeTime.After(latest) {
+			active, latest = peer.PublicKey, peer.LastHandshakeTime
+		}
+	}
+	return active
+}
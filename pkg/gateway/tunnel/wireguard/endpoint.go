@@ -0,0 +1,280 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=gatewayclients/status,verbs=get;patch
+
+const (
+	// resolveRetryInterval is the interval between two resolutions of the endpoint addresses, until one of them is resolved.
+	// In some cases (like AWS LoadBalancer) the DNS is not immediately populated.
+	resolveRetryInterval = 5 * time.Second
+	// connectionCheckInterval is the interval between two checks of the status of the connection.
+	connectionCheckInterval = 5 * time.Second
+)
+
+// endpointCandidate is a candidate IP address of the remote endpoint, together with the advertised address it originates from.
+type endpointCandidate struct {
+	address string
+	ip      net.IP
+}
+
+// EndpointSelector selects the IP address of the remote endpoint among the ones the advertised addresses resolve to.
+// The candidates are ordered interleaving the address families, as in the Happy Eyeballs algorithm (RFC 8305),
+// and the DNS names are periodically re-resolved. Whenever the connection is not established for longer than
+// the failover timeout, the selector moves to the next candidate.
+type EndpointSelector struct {
+	client  client.Client
+	options *Options
+	ch      chan<- event.GenericEvent
+
+	// lookupIP resolves a DNS name, and routable checks whether an IP address can be reached.
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+	routable func(ip net.IP) bool
+	now      func() time.Time
+
+	candidates    []endpointCandidate
+	active        int
+	since         time.Time
+	statusUpdated bool
+}
+
+var _ manager.Runnable = &EndpointSelector{}
+
+// NewEndpointSelector returns a new EndpointSelector, triggering the reconfiguration of the tunnel through the given channel.
+func NewEndpointSelector(cl client.Client, options *Options, ch chan<- event.GenericEvent) *EndpointSelector {
+	return &EndpointSelector{
+		client:  cl,
+		options: options,
+		ch:      ch,
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+		routable: func(ip net.IP) bool {
+			// Connecting a UDP socket sends no packets, but fails if there is no route towards the address.
+			conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: ip, Port: options.EndpointPort})
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		},
+		now: time.Now,
+	}
+}
+
+// Start starts the endpoint selector, until the context is canceled.
+func (s *EndpointSelector) Start(ctx context.Context) error {
+	if err := wait.PollUntilContextCancel(ctx, resolveRetryInterval, true, func(ctx context.Context) (bool, error) {
+		return s.resolve(ctx), nil
+	}); err != nil {
+		// The context has been canceled.
+		return nil
+	}
+
+	resolveTicker := time.NewTicker(s.options.DNSCheckInterval)
+	defer resolveTicker.Stop()
+	checkTicker := time.NewTicker(connectionCheckInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-resolveTicker.C:
+			s.resolve(ctx)
+		case <-checkTicker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+// resolve resolves the advertised addresses, updating the candidates, and returns whether at least one has been found.
+// The active candidate is preserved, if still present.
+func (s *EndpointSelector) resolve(ctx context.Context) bool {
+	candidates := s.resolveCandidates(ctx)
+	if len(candidates) == 0 {
+		klog.Warningf("No reachable IP address found for the endpoint addresses %v", s.options.EndpointAddresses)
+		return false
+	}
+
+	if len(s.candidates) > 0 {
+		current := s.candidates[s.active]
+		for i := range candidates {
+			if candidates[i].ip.Equal(current.ip) {
+				s.candidates, s.active = candidates, i
+				return true
+			}
+		}
+	}
+
+	s.candidates = candidates
+	s.activate(ctx, 0)
+	return true
+}
+
+// resolveCandidates returns the reachable IP addresses the advertised addresses resolve to, without duplicates,
+// interleaving the IPv6 and the IPv4 ones, starting from the family of the first address.
+func (s *EndpointSelector) resolveCandidates(ctx context.Context) []endpointCandidate {
+	var candidates []endpointCandidate
+	for _, address := range s.options.EndpointAddresses {
+		ips := []net.IP{net.ParseIP(address)}
+		if ips[0] == nil {
+			var err error
+			if ips, err = s.lookupIP(ctx, address); err != nil {
+				klog.Warningf("Unable to resolve the endpoint address %q: %v", address, err)
+				continue
+			}
+		}
+
+		for _, ip := range ips {
+			if !s.routable(ip) {
+				klog.V(4).Infof("Skipping the endpoint IP %s (%s), as not reachable", ip, address)
+				continue
+			}
+			candidates = append(candidates, endpointCandidate{address: address, ip: ip})
+		}
+	}
+
+	return interleaveCandidates(dedupCandidates(candidates))
+}
+
+// check checks the status of the connection, failing over to the next candidate
+// if the connection has not been established for longer than the failover timeout.
+func (s *EndpointSelector) check(ctx context.Context) {
+	if len(s.candidates) == 0 {
+		return
+	}
+	if !s.statusUpdated {
+		s.updateStatus(ctx)
+	}
+
+	connection, err := getters.GetConnectionByClusterIDInNamespace(ctx, s.client,
+		s.options.GwOptions.RemoteClusterID, s.options.GwOptions.Namespace)
+	if err == nil && connection.Status.Value == networkingv1beta1.Connected {
+		s.since = s.now()
+		return
+	}
+
+	if len(s.candidates) < 2 || s.now().Sub(s.since) < s.options.EndpointFailoverTimeout {
+		return
+	}
+
+	next := (s.active + 1) % len(s.candidates)
+	klog.Warningf("Connection with endpoint %s (%s) not established for %s: failing over to %s (%s)",
+		s.candidates[s.active].ip, s.candidates[s.active].address, s.options.EndpointFailoverTimeout,
+		s.candidates[next].ip, s.candidates[next].address)
+	s.activate(ctx, next)
+}
+
+// activate selects the given candidate, triggering the reconfiguration of the tunnel.
+func (s *EndpointSelector) activate(ctx context.Context, index int) {
+	s.active, s.since = index, s.now()
+	candidate := s.candidates[index]
+
+	klog.Infof("Endpoint address %q: using IP %s", candidate.address, candidate.ip)
+	s.options.SetEndpointIP(candidate.ip)
+
+	// Triggers a new reconcile of the public keys, which configures the peer endpoint.
+	select {
+	case s.ch <- event.GenericEvent{}:
+	case <-ctx.Done():
+		return
+	}
+
+	s.updateStatus(ctx)
+}
+
+// updateStatus records the active candidate in the status of the GatewayClient.
+func (s *EndpointSelector) updateStatus(ctx context.Context) {
+	candidate := s.candidates[s.active]
+
+	gwClient := &networkingv1beta1.GatewayClient{}
+	key := client.ObjectKey{Name: s.options.GwOptions.Name, Namespace: s.options.GwOptions.Namespace}
+	if err := s.client.Get(ctx, key, gwClient); err != nil {
+		klog.Errorf("Unable to get the GatewayClient %q: %v", key, err)
+		s.statusUpdated = false
+		return
+	}
+
+	original := gwClient.DeepCopy()
+	gwClient.Status.ActiveEndpoint = &networkingv1beta1.ActiveEndpoint{
+		Address: candidate.address,
+		IP:      networkingv1beta1.IP(candidate.ip.String()),
+	}
+	if err := s.client.Status().Patch(ctx, gwClient, client.MergeFrom(original)); err != nil {
+		klog.Errorf("Unable to update the active endpoint of the GatewayClient %q: %v", key, err)
+		s.statusUpdated = false
+		return
+	}
+	s.statusUpdated = true
+}
+
+// dedupCandidates removes the candidates with the same IP address, preserving the first occurrence.
+func dedupCandidates(candidates []endpointCandidate) []endpointCandidate {
+	seen := make(map[string]struct{}, len(candidates))
+	deduped := make([]endpointCandidate, 0, len(candidates))
+	for i := range candidates {
+		if _, ok := seen[candidates[i].ip.String()]; ok {
+			continue
+		}
+		seen[candidates[i].ip.String()] = struct{}{}
+		deduped = append(deduped, candidates[i])
+	}
+	return deduped
+}
+
+// interleaveCandidates alternates the candidates of the two address families, starting from the family of the first one,
+// and preserving the order within each family, so that an unreachable family does not prevent the failover to the other.
+func interleaveCandidates(candidates []endpointCandidate) []endpointCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	isV4 := func(ip net.IP) bool { return ip.To4() != nil }
+	var first, second []endpointCandidate
+	for i := range candidates {
+		if isV4(candidates[i].ip) == isV4(candidates[0].ip) {
+			first = append(first, candidates[i])
+		} else {
+			second = append(second, candidates[i])
+		}
+	}
+
+	interleaved := make([]endpointCandidate, 0, len(candidates))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			interleaved = append(interleaved, first[i])
+		}
+		if i < len(second) {
+			interleaved = append(interleaved, second[i])
+		}
+	}
+	return interleaved
+}
@@ -0,0 +1,192 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+var _ = Describe("Endpoint selection", func() {
+	const (
+		namespace = "liqo-tenant-remote"
+		name      = "gw-client"
+		clusterID = "remote"
+	)
+
+	var (
+		ctx        context.Context
+		cl         client.Client
+		connection *networkingv1beta1.Connection
+		events     chan event.GenericEvent
+		options    *Options
+		selector   *EndpointSelector
+		records    map[string][]net.IP
+		now        time.Time
+	)
+
+	ips := func(addresses ...string) []net.IP {
+		res := make([]net.IP, len(addresses))
+		for i := range addresses {
+			res[i] = net.ParseIP(addresses[i])
+		}
+		return res
+	}
+
+	candidateIPs := func() []string {
+		var res []string
+		for i := range selector.candidates {
+			res = append(res, selector.candidates[i].ip.String())
+		}
+		return res
+	}
+
+	activeEndpoint := func() *networkingv1beta1.ActiveEndpoint {
+		gwClient := &networkingv1beta1.GatewayClient{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, gwClient)).To(Succeed())
+		return gwClient.Status.ActiveEndpoint
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Unix(1700000000, 0)
+		records = map[string][]net.IP{
+			"gw.example.com":  ips("2001:db8::1", "2001:db8::2", "192.0.2.1"),
+			"alt.example.com": ips("192.0.2.2", "192.0.2.1"),
+		}
+
+		sch := runtime.NewScheme()
+		Expect(networkingv1beta1.AddToScheme(sch)).To(Succeed())
+		connection = &networkingv1beta1.Connection{
+			ObjectMeta: metav1.ObjectMeta{Name: "connection", Namespace: namespace,
+				Labels: map[string]string{consts.RemoteClusterID: clusterID}},
+		}
+		gwClient := &networkingv1beta1.GatewayClient{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		cl = fake.NewClientBuilder().WithScheme(sch).WithObjects(connection, gwClient).
+			WithStatusSubresource(connection, gwClient).Build()
+
+		events = make(chan event.GenericEvent, 10)
+		options = NewOptions(&gateway.Options{Name: name, Namespace: namespace, RemoteClusterID: clusterID, Mode: gateway.ModeClient})
+		options.EndpointFailoverTimeout = time.Minute
+
+		selector = NewEndpointSelector(cl, options, events)
+		selector.lookupIP = func(_ context.Context, host string) ([]net.IP, error) {
+			if ips, ok := records[host]; ok {
+				return ips, nil
+			}
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		selector.routable = func(ip net.IP) bool { return !ip.Equal(net.ParseIP("2001:db8::2")) }
+		selector.now = func() time.Time { return now }
+	})
+
+	setConnectionStatus := func(value networkingv1beta1.ConnectionStatusValue) {
+		connection.Status.Value = value
+		Expect(cl.Status().Update(ctx, connection)).To(Succeed())
+	}
+
+	When("resolving the advertised addresses", func() {
+		BeforeEach(func() {
+			options.EndpointAddresses = []string{"gw.example.com", "missing.example.com", "alt.example.com", "192.0.2.3"}
+			Expect(selector.resolve(ctx)).To(BeTrue())
+		})
+
+		It("should interleave the address families, skipping the duplicated and unreachable addresses", func() {
+			Expect(candidateIPs()).To(Equal([]string{"2001:db8::1", "192.0.2.1", "192.0.2.2", "192.0.2.3"}))
+		})
+
+		It("should select the first candidate", func() {
+			Expect(options.GetEndpointIP().String()).To(Equal("2001:db8::1"))
+			Expect(events).To(HaveLen(1))
+			Expect(activeEndpoint()).To(Equal(&networkingv1beta1.ActiveEndpoint{Address: "gw.example.com", IP: "2001:db8::1"}))
+		})
+
+		It("should preserve the active candidate when still resolved", func() {
+			selector.activate(ctx, 2)
+			records["gw.example.com"] = ips("192.0.2.4")
+			Expect(selector.resolve(ctx)).To(BeTrue())
+			Expect(candidateIPs()).To(Equal([]string{"192.0.2.4", "192.0.2.2", "192.0.2.1", "192.0.2.3"}))
+			Expect(options.GetEndpointIP().String()).To(Equal("192.0.2.2"))
+			Expect(selector.active).To(Equal(1))
+		})
+
+		It("should move to the first candidate when the active one is no longer resolved", func() {
+			records["gw.example.com"] = ips("192.0.2.4")
+			Expect(selector.resolve(ctx)).To(BeTrue())
+			Expect(options.GetEndpointIP().String()).To(Equal("192.0.2.4"))
+			Expect(activeEndpoint().IP).To(BeEquivalentTo("192.0.2.4"))
+		})
+	})
+
+	When("no address can be resolved", func() {
+		BeforeEach(func() {
+			options.EndpointAddresses = []string{"missing.example.com"}
+		})
+
+		It("should not select any endpoint", func() {
+			Expect(selector.resolve(ctx)).To(BeFalse())
+			Expect(options.GetEndpointIP()).To(BeNil())
+			Expect(events).To(BeEmpty())
+		})
+	})
+
+	When("checking the connection", func() {
+		BeforeEach(func() {
+			options.EndpointAddresses = []string{"192.0.2.1", "gw.example.com"}
+			Expect(selector.resolve(ctx)).To(BeTrue())
+		})
+
+		It("should keep the active endpoint while connected", func() {
+			setConnectionStatus(networkingv1beta1.Connected)
+			for range 5 {
+				now = now.Add(30 * time.Second)
+				selector.check(ctx)
+			}
+			Expect(options.GetEndpointIP().String()).To(Equal("192.0.2.1"))
+		})
+
+		It("should keep the active endpoint until the failover timeout expires", func() {
+			setConnectionStatus(networkingv1beta1.ConnectionError)
+			now = now.Add(30 * time.Second)
+			selector.check(ctx)
+			Expect(options.GetEndpointIP().String()).To(Equal("192.0.2.1"))
+		})
+
+		It("should fail over to the next endpoint, wrapping around, when the connection is down", func() {
+			setConnectionStatus(networkingv1beta1.ConnectionError)
+			for _, expected := range []string{"2001:db8::1", "192.0.2.1"} {
+				now = now.Add(time.Minute)
+				selector.check(ctx)
+				Expect(options.GetEndpointIP().String()).To(Equal(expected))
+				Expect(activeEndpoint().IP).To(BeEquivalentTo(expected), fmt.Sprintf("status of %s", expected))
+			}
+			Expect(events).To(HaveLen(3))
+		})
+	})
+})
@@ -19,8 +19,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel/stream"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/forge"
 )
 
@@ -38,18 +40,44 @@ const (
 	FlagNameListenPort FlagName = "listen-port"
 	// FlagNameInterfaceIP is the IP of the wireguard interface.
 	FlagNameInterfaceIP FlagName = "interface-ip"
-	// FlagNameEndpointAddress is the address of the endpoint for the wireguard interface.
+	// FlagNameEndpointAddress is the address of the endpoint for the wireguard interface, possibly repeated.
 	FlagNameEndpointAddress FlagName = "endpoint-address"
 	// FlagNameEndpointPort is the port of the endpoint for the wireguard interface.
 	FlagNameEndpointPort FlagName = "endpoint-port"
 	// FlagNameKeysDir is the directory where the keys are stored.
 	FlagNameKeysDir FlagName = "keys-dir"
 
+	// FlagNameKeyExchangePort is the port used to announce the rotated keys to the remote gateway through the tunnel.
+	FlagNameKeyExchangePort FlagName = "key-exchange-port"
+	// FlagNameKeysCheckInterval is the interval between two checks for rotated keys.
+	FlagNameKeysCheckInterval FlagName = "keys-check-interval"
+
 	// FlagNameDNSCheckInterval is the interval between two DNS checks.
 	FlagNameDNSCheckInterval FlagName = "dns-check-interval"
+	// FlagNameEndpointFailoverTimeout is the time after which the client fails over to another endpoint address.
+	FlagNameEndpointFailoverTimeout FlagName = "endpoint-failover-timeout"
 
 	// FlagNameImplementation is the implementation of the wireguard interface.
 	FlagNameImplementation FlagName = "implementation"
+
+	// FlagNameTransport is the transport carrying the tunnel.
+	FlagNameTransport FlagName = "transport"
+	// FlagNameEndpointProtocol is the protocol advertised by the server endpoint.
+	FlagNameEndpointProtocol FlagName = "endpoint-protocol"
+	// FlagNameTransportLocalPort is the local port relaying the tunnel to the stream transport.
+	FlagNameTransportLocalPort FlagName = "transport-local-port"
+	// FlagNameTransportMaxStreams is the maximum number of concurrent streams accepted by the server.
+	FlagNameTransportMaxStreams FlagName = "transport-max-streams"
+	// FlagNameTransportTLSCertFile is the file containing the certificate securing the WebSocket streams.
+	FlagNameTransportTLSCertFile FlagName = "transport-tls-cert-file"
+	// FlagNameTransportTLSKeyFile is the file containing the key of the certificate securing the WebSocket streams.
+	FlagNameTransportTLSKeyFile FlagName = "transport-tls-key-file"
+	// FlagNameTransportTLS is the flag to secure the WebSocket streams with TLS.
+	FlagNameTransportTLS FlagName = "transport-tls"
+	// FlagNameTransportTLSCAFile is the file containing the certificate authorities trusted to verify the server.
+	FlagNameTransportTLSCAFile FlagName = "transport-tls-ca-file"
+	// FlagNameTransportTLSServerName is the name of the server, overriding the one derived from the endpoint address.
+	FlagNameTransportTLSServerName FlagName = "transport-tls-server-name"
 )
 
 // ClientRequiredFlags contains the list of the mandatory flags for the client mode.
@@ -61,13 +89,42 @@ var ClientRequiredFlags = []FlagName{
 func InitFlags(flagset *pflag.FlagSet, opts *Options) {
 	flagset.IntVar(&opts.MTU, FlagNameMTU.String(), forge.DefaultMTU, "MTU for the interface")
 	flagset.IntVar(&opts.ListenPort, FlagNameListenPort.String(), forge.DefaultGwServerPort, "Listen port (server only)")
-	flagset.StringVar(&opts.EndpointAddress, FlagNameEndpointAddress.String(), "", "Endpoint address (client only)")
+	flagset.StringSliceVar(&opts.EndpointAddresses, FlagNameEndpointAddress.String(), nil,
+		"Endpoint addresses, either IP addresses or DNS names, in order of preference (client only)")
 	flagset.IntVar(&opts.EndpointPort, FlagNameEndpointPort.String(), forge.DefaultGwServerPort, "Endpoint port (client only)")
 	flagset.StringVar(&opts.KeysDir, FlagNameKeysDir.String(), forge.DefaultKeysDir, "Directory where the keys are stored")
 
+	flagset.IntVar(&opts.KeyExchangePort, FlagNameKeyExchangePort.String(), DefaultKeyExchangePort,
+		"Port used to announce the rotated keys to the remote gateway through the tunnel")
+	flagset.DurationVar(&opts.KeysCheckInterval, FlagNameKeysCheckInterval.String(), 30*time.Second,
+		"Interval between two checks for rotated keys")
+
 	flagset.DurationVar(&opts.DNSCheckInterval, FlagNameDNSCheckInterval.String(), 5*time.Minute, "Interval between two DNS checks")
+	flagset.DurationVar(&opts.EndpointFailoverTimeout, FlagNameEndpointFailoverTimeout.String(), time.Minute,
+		"Time after which the client fails over to the next endpoint address, if the connection is not established (client only)")
 
 	flagset.Var(&opts.Implementation, "implementation", "Implementation of the wireguard interface (kernel or userspace)")
+
+	opts.Transport = stream.TransportUDP
+	flagset.Var(&opts.Transport, FlagNameTransport.String(),
+		"Transport carrying the tunnel (udp, tcp or websocket): the server accepts both the stream transports when not udp, "+
+			"while the client uses it only if the endpoint protocol is TCP")
+	flagset.StringVar(&opts.EndpointProtocol, FlagNameEndpointProtocol.String(), string(corev1.ProtocolUDP),
+		"Protocol advertised by the server endpoint (client only)")
+	flagset.IntVar(&opts.TransportLocalPort, FlagNameTransportLocalPort.String(), DefaultTransportLocalPort,
+		"Local port relaying the tunnel to the stream transport (client only)")
+	flagset.IntVar(&opts.TransportMaxStreams, FlagNameTransportMaxStreams.String(), DefaultTransportMaxStreams,
+		"Maximum number of concurrent streams, which are accepted before the peer is authenticated (server only)")
+	flagset.StringVar(&opts.TransportTLSCertFile, FlagNameTransportTLSCertFile.String(), "",
+		"File containing the certificate securing the WebSocket streams, which are then accepted over TLS as well (server only)")
+	flagset.StringVar(&opts.TransportTLSKeyFile, FlagNameTransportTLSKeyFile.String(), "",
+		"File containing the key of the certificate securing the WebSocket streams (server only)")
+	flagset.BoolVar(&opts.TransportTLS, FlagNameTransportTLS.String(), false,
+		"Secure the WebSocket streams with TLS (client only)")
+	flagset.StringVar(&opts.TransportTLSCAFile, FlagNameTransportTLSCAFile.String(), "",
+		"File containing the certificate authorities trusted to verify the server, instead of the system ones (client only)")
+	flagset.StringVar(&opts.TransportTLSServerName, FlagNameTransportTLSServerName.String(), "",
+		"Name of the server, used for the TLS handshake and as HTTP host, instead of the endpoint address (client only)")
 }
 
 // MarkFlagsRequired marks the flags as required.
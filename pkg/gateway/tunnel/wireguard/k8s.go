@@ -16,19 +16,24 @@ package wireguard
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
 
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/klog/v2"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/gateway"
 	"github.com/liqotech/liqo/pkg/gateway/forge"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	"github.com/liqotech/liqo/pkg/utils/getters"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
@@ -82,42 +87,72 @@ func CreateKeysSecret(ctx context.Context, cl client.Client, opts *gateway.Optio
 	return nil
 }
 
-// EnsureConnection creates or updates the connection resource.
-func EnsureConnection(ctx context.Context, cl client.Client, scheme *runtime.Scheme, opts *Options) error {
-	conn := &networkingv1beta1.Connection{ObjectMeta: metav1.ObjectMeta{
-		Name: forge.GatewayResourceName(opts.GwOptions.Name), Namespace: opts.GwOptions.Namespace,
-		Labels: map[string]string{
-			string(consts.RemoteClusterID): opts.GwOptions.RemoteClusterID,
-		},
-	}}
+// RotateKeysSecret replaces the keys stored in the given Secret with a newly generated pair. The current keys are
+// preserved as previous keys until the end of the transition window, to let the remote gateway switch to the new ones.
+func RotateKeysSecret(ctx context.Context, cl client.Client, secret *corev1.Secret, transitionWindow time.Duration) error {
+	keys, err := ParseKeys(secret.Data)
+	if err != nil {
+		return fmt.Errorf("unable to parse the keys of secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	pri, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return err
+	}
+	pub := pri.PublicKey()
+	prevPub := keys.PrivateKey.PublicKey()
 
-	klog.Infof("Creating connection %q", conn.Name)
+	now := time.Now()
+	secret.Data[consts.PreviousPrivateKeyField] = keys.PrivateKey[:]
+	secret.Data[consts.PreviousPublicKeyField] = prevPub[:]
+	secret.Data[consts.PreviousKeyExpirationField] = []byte(now.Add(transitionWindow).UTC().Format(time.RFC3339))
+	secret.Data[consts.KeyVersionField] = []byte(strconv.FormatInt(keys.Version+1, 10))
+	secret.Data[consts.PrivateKeyField] = pri[:]
+	secret.Data[consts.PublicKeyField] = pub[:]
 
-	_, err := resource.CreateOrUpdate(ctx, cl, conn, func() error {
-		if err := gateway.SetOwnerReferenceWithMode(opts.GwOptions, conn, scheme); err != nil {
-			return err
-		}
-		conn.Spec.GatewayRef.APIVersion = networkingv1beta1.GroupVersion.String()
-		conn.Spec.GatewayRef.Name = opts.GwOptions.Name
-		conn.Spec.GatewayRef.Namespace = opts.GwOptions.Namespace
-		conn.Spec.GatewayRef.UID = types.UID(opts.GwOptions.GatewayUID)
-		switch opts.GwOptions.Mode {
-		case gateway.ModeServer:
-			conn.Spec.Type = networkingv1beta1.ConnectionTypeServer
-			conn.Spec.GatewayRef.Kind = networkingv1beta1.WgGatewayServerKind
-		case gateway.ModeClient:
-			conn.Spec.Type = networkingv1beta1.ConnectionTypeClient
-			conn.Spec.GatewayRef.Kind = networkingv1beta1.WgGatewayClientKind
-		}
-		return nil
-	})
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[consts.KeyRotationTimestampAnnotation] = now.UTC().Format(time.RFC3339)
+	secret.SetAnnotations(annotations)
 
+	return cl.Update(ctx, secret)
+}
+
+// PurgePreviousKeys removes the previous keys from the given Secret, once the transition window is over.
+func PurgePreviousKeys(ctx context.Context, cl client.Client, secret *corev1.Secret) error {
+	keys, err := ParseKeys(secret.Data)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to parse the keys of secret %s/%s: %w", secret.Namespace, secret.Name, err)
 	}
+	if keys.PreviousPrivateKey == nil || keys.PreviousValid(time.Now()) {
+		return nil
+	}
+
+	delete(secret.Data, consts.PreviousPrivateKeyField)
+	delete(secret.Data, consts.PreviousPublicKeyField)
+	delete(secret.Data, consts.PreviousKeyExpirationField)
+	return cl.Update(ctx, secret)
+}
 
-	klog.Infof("Connection %q created", conn.Name)
+// UpdateConnectionKeyRotation updates the key rotation status of the connection resource.
+func UpdateConnectionKeyRotation(ctx context.Context, cl client.Client, opts *Options,
+	mutate func(status *networkingv1beta1.ConnectionKeyRotation)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		conn, err := getters.GetConnectionByClusterIDInNamespace(ctx, cl, opts.GwOptions.RemoteClusterID, opts.GwOptions.Namespace)
+		if err != nil {
+			return err
+		}
+		if conn.Status.KeyRotation == nil {
+			conn.Status.KeyRotation = &networkingv1beta1.ConnectionKeyRotation{}
+		}
+		mutate(conn.Status.KeyRotation)
+		return cl.Status().Update(ctx, conn)
+	})
+}
 
-	conn.Status.Value = networkingv1beta1.Connecting
-	return cl.Status().Update(ctx, conn)
+// EnsureConnection creates or updates the connection resource.
+func EnsureConnection(ctx context.Context, cl client.Client, scheme *runtime.Scheme, opts *Options) error {
+	return tunnel.EnsureConnection(ctx, cl, scheme, opts.GwOptions,
+		networkingv1beta1.WgGatewayServerKind, networkingv1beta1.WgGatewayClientKind)
 }
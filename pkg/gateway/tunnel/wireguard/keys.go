@@ -15,39 +15,112 @@
 package wireguard
 
 import (
-	"encoding/base64"
-	"io"
+	"errors"
+	"fmt"
 	"os"
-	"path"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"k8s.io/klog/v2"
+
+	"github.com/liqotech/liqo/pkg/consts"
 )
 
-// LoadKeys loads the keys from the specified directory.
-func LoadKeys(options *Options) error {
-	// Load the keys
-	privKeyPath := path.Join(options.KeysDir, "privateKey")
+// Keys contains the keys of the gateway, as stored in the keys secret.
+type Keys struct {
+	// PrivateKey is the current private key.
+	PrivateKey wgtypes.Key
+	// Version is the version of the current private key, incremented at every rotation.
+	Version int64
+	// PreviousPrivateKey is the private key replaced by the last rotation, if any.
+	PreviousPrivateKey *wgtypes.Key
+	// PreviousExpiration is the time until which the previous key is still accepted by the remote gateway.
+	PreviousExpiration time.Time
+}
 
-	// read the private key from the file
-	privKeyFile, err := os.Open(filepath.Clean(privKeyPath))
-	if err != nil {
-		return err
+// PreviousValid returns whether the previous key is still accepted by the remote gateway at the given time.
+func (k *Keys) PreviousValid(now time.Time) bool {
+	return k.PreviousPrivateKey != nil && k.Version > 0 && now.Before(k.PreviousExpiration)
+}
+
+// ParseKeys parses the keys from the data of the keys secret.
+// The fields related to the key rotation are optional, since they are not present before the first rotation.
+func ParseKeys(data map[string][]byte) (*Keys, error) {
+	pri, ok := data[consts.PrivateKeyField]
+	if !ok {
+		return nil, fmt.Errorf("missing %q field", consts.PrivateKeyField)
 	}
-	defer privKeyFile.Close()
 
-	// base64 encoded private key
-	privKey, err := io.ReadAll(privKeyFile)
-	if err != nil {
-		return err
+	var keys Keys
+	var err error
+	if keys.PrivateKey, err = wgtypes.NewKey(pri); err != nil {
+		return nil, fmt.Errorf("invalid %q field: %w", consts.PrivateKeyField, err)
 	}
 
-	base64PrivKey := base64.StdEncoding.EncodeToString(privKey)
-	wgtypesKey, err := wgtypes.ParseKey(base64PrivKey)
+	if version, ok := data[consts.KeyVersionField]; ok {
+		if keys.Version, err = strconv.ParseInt(string(version), 10, 64); err != nil || keys.Version < 0 {
+			return nil, fmt.Errorf("invalid %q field %q", consts.KeyVersionField, version)
+		}
+	}
+
+	if prev, ok := data[consts.PreviousPrivateKeyField]; ok {
+		key, err := wgtypes.NewKey(prev)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q field: %w", consts.PreviousPrivateKeyField, err)
+		}
+		keys.PreviousPrivateKey = &key
+
+		expiration, ok := data[consts.PreviousKeyExpirationField]
+		if !ok {
+			return nil, fmt.Errorf("missing %q field", consts.PreviousKeyExpirationField)
+		}
+		if keys.PreviousExpiration, err = time.Parse(time.RFC3339, string(expiration)); err != nil {
+			return nil, fmt.Errorf("invalid %q field: %w", consts.PreviousKeyExpirationField, err)
+		}
+	}
+
+	return &keys, nil
+}
+
+// ReadKeys reads the keys from the specified directory, where the keys secret is mounted.
+func ReadKeys(dir string) (*Keys, error) {
+	data := map[string][]byte{}
+	for _, field := range []string{consts.PrivateKeyField, consts.KeyVersionField,
+		consts.PreviousPrivateKeyField, consts.PreviousKeyExpirationField} {
+		content, err := os.ReadFile(filepath.Clean(filepath.Join(dir, field)))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		data[field] = content
+	}
+	return ParseKeys(data)
+}
+
+// LoadKeys loads the keys from the specified directory.
+// If a rotation is still in progress, the previous key is loaded, and the switch to the new one is
+// performed by the KeyRotator once the remote gateway acknowledges it.
+func LoadKeys(options *Options) error {
+	keys, err := ReadKeys(options.KeysDir)
 	if err != nil {
 		return err
 	}
 
-	options.PrivateKey = wgtypesKey
+	options.KeysMutex.Lock()
+	defer options.KeysMutex.Unlock()
+
+	if keys.PreviousValid(time.Now()) {
+		klog.Infof("Key rotation to version %d in progress: loading the previous key", keys.Version)
+		options.PrivateKey = *keys.PreviousPrivateKey
+		options.KeyVersion = keys.Version - 1
+		return nil
+	}
+
+	options.PrivateKey = keys.PrivateKey
+	options.KeyVersion = keys.Version
 	return nil
 }
@@ -96,13 +96,17 @@ func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
 		[]string{driverLabelValue, string(pc.metricsOptions.WgImplementation)}...,
 	)
 
-	if len(device.Peers) != 1 {
-		pc.tunnelMetrics.MetricsErrorHandler(
-			fmt.Errorf("error collecting wireguard metrics: gateway must have exactly 1 peer, it has %d", len(device.Peers)), ch)
+	if len(device.Peers) == 0 {
+		pc.tunnelMetrics.MetricsErrorHandler(fmt.Errorf("error collecting wireguard metrics: gateway has no peers"), ch)
 		return
 	}
 
-	peer := device.Peers[0]
+	// During a key rotation, the remote gateway is configured as two peers (with the new and the previous key).
+	var receivedBytes, transmittedBytes int64
+	for i := range device.Peers {
+		receivedBytes += device.Peers[i].ReceiveBytes
+		transmittedBytes += device.Peers[i].TransmitBytes
+	}
 
 	labels := []string{driverLabelValue, pc.metricsOptions.RemoteClusterID}
 
@@ -130,14 +134,14 @@ func (pc *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(
 			tunnel.MetricsPeerReceivedBytes,
 			prometheus.CounterValue,
-			float64(peer.ReceiveBytes),
+			float64(receivedBytes),
 			labels...,
 		)
 
 		ch <- prometheus.MustNewConstMetric(
 			tunnel.MetricsPeerTransmittedBytes,
 			prometheus.CounterValue,
-			float64(peer.TransmitBytes),
+			float64(transmittedBytes),
 			labels...,
 		)
 
@@ -23,6 +23,7 @@ import (
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
 	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel/stream"
 )
 
 // WgImplementation represents the implementation of the wireguard interface.
@@ -61,20 +62,45 @@ func (wgi WgImplementation) Type() string {
 type Options struct {
 	GwOptions *gateway.Options
 
-	MTU             int
-	PrivateKey      wgtypes.Key
-	InterfaceIP     string
-	ListenPort      int
-	EndpointAddress string
-	EndpointPort    int
-	KeysDir         string
+	MTU               int
+	InterfaceIP       string
+	ListenPort        int
+	EndpointAddresses []string
+	EndpointPort      int
+	KeysDir           string
+
+	// PrivateKey is the private key configured on the interface, and KeyVersion its version.
+	// They change when the keys are rotated, hence they must be accessed holding KeysMutex.
+	PrivateKey wgtypes.Key
+	KeyVersion int64
+	KeysMutex  *sync.Mutex
+
+	KeyExchangePort   int
+	KeysCheckInterval time.Duration
 
 	EndpointIP      net.IP
 	EndpointIPMutex *sync.Mutex
 
-	DNSCheckInterval time.Duration
+	DNSCheckInterval        time.Duration
+	EndpointFailoverTimeout time.Duration
 
 	Implementation WgImplementation
+
+	// Transport is the transport carrying the tunnel, and EndpointProtocol the protocol advertised by the server endpoint,
+	// which determines the transport used by the client.
+	Transport          stream.Transport
+	EndpointProtocol   string
+	TransportLocalPort int
+	// TransportMaxStreams bounds the concurrent streams accepted by the server.
+	TransportMaxStreams int
+
+	// TransportTLSCertFile and TransportTLSKeyFile secure the WebSocket streams accepted by the server,
+	// while TransportTLS, TransportTLSCAFile and TransportTLSServerName the ones established by the client.
+	TransportTLSCertFile   string
+	TransportTLSKeyFile    string
+	TransportTLS           bool
+	TransportTLSCAFile     string
+	TransportTLSServerName string
 }
 
 // NewOptions returns a new Options struct.
@@ -82,5 +108,20 @@ func NewOptions(options *gateway.Options) *Options {
 	return &Options{
 		GwOptions:       options,
 		EndpointIPMutex: &sync.Mutex{},
+		KeysMutex:       &sync.Mutex{},
 	}
 }
+
+// GetEndpointIP returns the IP address of the remote endpoint currently selected.
+func (o *Options) GetEndpointIP() net.IP {
+	o.EndpointIPMutex.Lock()
+	defer o.EndpointIPMutex.Unlock()
+	return o.EndpointIP
+}
+
+// SetEndpointIP sets the IP address of the remote endpoint.
+func (o *Options) SetEndpointIP(ip net.IP) {
+	o.EndpointIPMutex.Lock()
+	defer o.EndpointIPMutex.Unlock()
+	o.EndpointIP = ip
+}
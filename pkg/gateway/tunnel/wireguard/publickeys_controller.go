@@ -17,6 +17,7 @@ package wireguard
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -73,17 +74,46 @@ func (r *PublicKeysReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("unable to get the publicKey %q: %w", req.NamespacedName, err)
 	}
 
-	if r.Options.GwOptions.Mode == gateway.ModeClient && r.Options.EndpointIP == nil {
-		// We don't need to retry because the DNS resolution routine will wakeup this controller.
+	if r.Options.GwOptions.Mode == gateway.ModeClient && r.Options.GetEndpointIP() == nil {
+		// We don't need to retry because the endpoint selector will wakeup this controller.
 		klog.Warning("EndpointIP is not set yet. Maybe the DNS resolution is still in progress")
 		return ctrl.Result{}, nil
 	}
 
-	if err := configureDevice(r.Wgcl, r.Options, wgtypes.Key(publicKey.Spec.PublicKey)); err != nil {
+	keys, requeue, err := peerKeys(publicKey, time.Now())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid publicKey %q: %w", req.NamespacedName, err)
+	}
+
+	if err := configureDevice(r.Wgcl, r.Options, keys); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, EnsureConnection(ctx, r.Client, r.Scheme, r.Options)
+	// Requeue when the previous key expires, to remove it from the peers.
+	return ctrl.Result{RequeueAfter: requeue}, EnsureConnection(ctx, r.Client, r.Scheme, r.Options)
+}
+
+// peerKeys returns the public keys of the remote gateway currently accepted, starting from the current one,
+// and the time after which the previous one (if any) expires.
+func peerKeys(publicKey *networkingv1beta1.PublicKey, now time.Time) ([]wgtypes.Key, time.Duration, error) {
+	current, err := wgtypes.NewKey(publicKey.Spec.PublicKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	keys := []wgtypes.Key{current}
+
+	expiration := publicKey.Spec.PreviousPublicKeyExpiration
+	if len(publicKey.Spec.PreviousPublicKey) == 0 || expiration == nil || !now.Before(expiration.Time) {
+		return keys, 0, nil
+	}
+	previous, err := wgtypes.NewKey(publicKey.Spec.PreviousPublicKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	if previous != current {
+		keys = append(keys, previous)
+	}
+	return keys, expiration.Sub(now) + time.Second, nil
 }
 
 // SetupWithManager register the ConfigurationReconciler to the manager.
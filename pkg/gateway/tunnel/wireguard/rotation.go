@@ -0,0 +1,468 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+const (
+	// DefaultKeyExchangePort is the default port used to announce the rotated keys to the remote gateway.
+	DefaultKeyExchangePort = 51841
+
+	// announceInterval is the interval between two announcements of a new key, until it is acknowledged.
+	announceInterval = time.Second
+	// activePeerSyncInterval is the interval between two checks of the peer the traffic is assigned to.
+	activePeerSyncInterval = 500 * time.Millisecond
+
+	keyExchangeMagic         = "LQKR"
+	keyExchangeVersion       = 1
+	keyExchangeMACLabel      = "liqo wireguard key rotation v1"
+	keyExchangeMessageLength = len(keyExchangeMagic) + 2 + 8 + wgtypes.KeyLen + 8 + sha256.Size
+)
+
+// keyExchangeMessageType is the type of a key exchange message.
+type keyExchangeMessageType uint8
+
+const (
+	// keyAnnouncement is sent by a gateway to announce its new public key.
+	keyAnnouncement keyExchangeMessageType = 1
+	// keyAcknowledgement is sent in response to an announcement, once the new public key has been configured.
+	keyAcknowledgement keyExchangeMessageType = 2
+)
+
+// keyExchangeMessage announces (or acknowledges) a new public key of a gateway. Messages are exchanged through
+// the tunnel, and they are authenticated with a key derived from the static keys currently used by the two gateways.
+type keyExchangeMessage struct {
+	Type keyExchangeMessageType
+	// Version is the version of the new key.
+	Version int64
+	// PublicKey is the new public key.
+	PublicKey wgtypes.Key
+	// PreviousExpiration is the time until which the previous key has to be accepted.
+	PreviousExpiration time.Time
+}
+
+// marshal encodes and authenticates the message.
+func (m *keyExchangeMessage) marshal(macKey []byte) []byte {
+	buf := make([]byte, 0, keyExchangeMessageLength)
+	buf = append(buf, keyExchangeMagic...)
+	buf = append(buf, keyExchangeVersion, byte(m.Type))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(m.Version)) //nolint:gosec // the version is never negative
+	buf = append(buf, m.PublicKey[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(m.PreviousExpiration.Unix())) //nolint:gosec // the time is after the epoch
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(buf)
+	return mac.Sum(buf)
+}
+
+// parseKeyExchangeMessage decodes a message, checking its authenticity against the given keys.
+// It returns the key the message has been authenticated with.
+func parseKeyExchangeMessage(buf []byte, macKeys [][]byte) (msg *keyExchangeMessage, macKey []byte, err error) {
+	if len(buf) != keyExchangeMessageLength || string(buf[:len(keyExchangeMagic)]) != keyExchangeMagic {
+		return nil, nil, errors.New("not a key exchange message")
+	}
+	body := buf[len(keyExchangeMagic) : keyExchangeMessageLength-sha256.Size]
+	if body[0] != keyExchangeVersion {
+		return nil, nil, fmt.Errorf("unsupported key exchange version %d", body[0])
+	}
+
+	idx := slices.IndexFunc(macKeys, func(key []byte) bool {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(buf[:keyExchangeMessageLength-sha256.Size])
+		return hmac.Equal(mac.Sum(nil), buf[keyExchangeMessageLength-sha256.Size:])
+	})
+	if idx < 0 {
+		return nil, nil, errors.New("invalid key exchange message authentication code")
+	}
+
+	msg = &keyExchangeMessage{
+		Type:               keyExchangeMessageType(body[1]),
+		Version:            int64(binary.BigEndian.Uint64(body[2:10])),                             //nolint:gosec // negative versions are never accepted
+		PreviousExpiration: time.Unix(int64(binary.BigEndian.Uint64(body[10+wgtypes.KeyLen:])), 0), //nolint:gosec // see above
+	}
+	copy(msg.PublicKey[:], body[10:10+wgtypes.KeyLen])
+	if msg.Version < 0 {
+		return nil, nil, fmt.Errorf("invalid key version %d", msg.Version)
+	}
+	return msg, macKeys[idx], nil
+}
+
+// keyExchangeMACKey derives the key authenticating the key exchange messages from a private key of the local gateway
+// and a public key of the remote one.
+func keyExchangeMACKey(private, peer wgtypes.Key) ([]byte, error) {
+	priv, err := ecdh.X25519().NewPrivateKey(private[:])
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ecdh.X25519().NewPublicKey(peer[:])
+	if err != nil {
+		return nil, err
+	}
+	shared, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, shared)
+	mac.Write([]byte(keyExchangeMACLabel))
+	return mac.Sum(nil), nil
+}
+
+var _ manager.Runnable = &KeyRotator{}
+
+// KeyRotator rotates the keys of the gateway without interrupting the tunnel.
+// When the keys stored in the keys secret are rotated, it announces the new public key to the remote gateway through
+// the tunnel itself, and it switches to the new private key once the remote gateway acknowledges it. Conversely, it
+// accepts the keys announced by the remote gateway, updating the corresponding PublicKey resource.
+type KeyRotator struct {
+	client  client.Client
+	wgcl    *wgctrl.Client
+	options *Options
+
+	conn *net.UDPConn
+	peer *net.UDPAddr
+
+	mutex sync.Mutex
+	// acknowledged is the last local key acknowledged by the remote gateway.
+	acknowledged *keyExchangeMessage
+}
+
+// NewKeyRotator returns a new KeyRotator.
+func NewKeyRotator(cl client.Client, options *Options) (*KeyRotator, error) {
+	wgcl, err := wgctrl.New()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create wireguard client: %w", err)
+	}
+	return &KeyRotator{
+		client:  cl,
+		wgcl:    wgcl,
+		options: options,
+	}, nil
+}
+
+// Start runs the KeyRotator until the given context is canceled. The tunnel interface must already exist.
+func (kr *KeyRotator) Start(ctx context.Context) error {
+	if err := kr.listen(ctx); err != nil {
+		return fmt.Errorf("unable to listen for key exchange messages: %w", err)
+	}
+	klog.Infof("Listening for key exchange messages on %s", kr.conn.LocalAddr())
+
+	go func() {
+		<-ctx.Done()
+		kr.conn.Close()
+	}()
+	go kr.receive(ctx)
+	go wait.UntilWithContext(ctx, func(context.Context) {
+		if err := syncActivePeer(kr.wgcl, kr.options); err != nil {
+			klog.Warningf("Unable to sync the active peer: %v", err)
+		}
+	}, activePeerSyncInterval)
+
+	for {
+		interval := kr.options.KeysCheckInterval
+		pending, err := kr.rotate(ctx)
+		if err != nil {
+			klog.Errorf("Key rotation failed: %v", err)
+		}
+		if pending {
+			interval = announceInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// listen opens the socket used to exchange the keys, bound to the tunnel interface, so that only the messages
+// received through the tunnel are accepted.
+func (kr *KeyRotator) listen(ctx context.Context) error {
+	local, _, err := net.ParseCIDR(tunnel.GetInterfaceIP(kr.options.GwOptions.Mode))
+	if err != nil {
+		return err
+	}
+	remote, err := tunnel.GetRemoteInterfaceIP(kr.options.GwOptions.Mode)
+	if err != nil {
+		return err
+	}
+	kr.peer = &net.UDPAddr{IP: net.ParseIP(remote), Port: kr.options.KeyExchangePort}
+
+	lc := net.ListenConfig{Control: func(_, _ string, c syscall.RawConn) error {
+		var serr error
+		if err := c.Control(func(fd uintptr) {
+			serr = unix.BindToDevice(int(fd), tunnel.TunnelInterfaceName)
+		}); err != nil {
+			return err
+		}
+		return serr
+	}}
+	pc, err := lc.ListenPacket(ctx, "udp4", net.JoinHostPort(local.String(), strconv.Itoa(kr.options.KeyExchangePort)))
+	if err != nil {
+		return err
+	}
+	kr.conn = pc.(*net.UDPConn)
+	return nil
+}
+
+// rotate checks whether the keys have been rotated, and switches to the new key once the remote gateway acknowledges it.
+// It returns whether a rotation is pending.
+func (kr *KeyRotator) rotate(ctx context.Context) (bool, error) {
+	keys, err := ReadKeys(kr.options.KeysDir)
+	if err != nil {
+		return false, fmt.Errorf("unable to read keys: %w", err)
+	}
+
+	kr.options.KeysMutex.Lock()
+	current, version := kr.options.PrivateKey, kr.options.KeyVersion
+	if keys.PrivateKey == current {
+		kr.options.KeyVersion = keys.Version
+	}
+	kr.options.KeysMutex.Unlock()
+	if keys.Version <= version || keys.PrivateKey == current {
+		return false, nil
+	}
+
+	switch {
+	case kr.isAcknowledged(keys):
+		klog.Infof("The remote gateway acknowledged the key version %d", keys.Version)
+	case !keys.PreviousValid(time.Now()):
+		klog.Warningf("The remote gateway did not acknowledge the key version %d within the transition window: "+
+			"switching anyway, the new public key has to be provided to the remote cluster", keys.Version)
+	default:
+		return true, kr.announce(ctx, keys, current)
+	}
+	return false, kr.switchKey(ctx, keys)
+}
+
+// announce sends the new public key to the remote gateway, authenticated with the key currently in use.
+func (kr *KeyRotator) announce(ctx context.Context, keys *Keys, private wgtypes.Key) error {
+	publicKey, err := getters.GetPublicKeyByClusterID(ctx, kr.client,
+		liqov1beta1.ClusterID(kr.options.GwOptions.RemoteClusterID), kr.options.GwOptions.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to get the public key of the remote gateway: %w", err)
+	}
+	peer, err := wgtypes.NewKey(publicKey.Spec.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key of the remote gateway: %w", err)
+	}
+	macKey, err := keyExchangeMACKey(private, peer)
+	if err != nil {
+		return err
+	}
+
+	msg := &keyExchangeMessage{
+		Type:               keyAnnouncement,
+		Version:            keys.Version,
+		PublicKey:          keys.PrivateKey.PublicKey(),
+		PreviousExpiration: keys.PreviousExpiration,
+	}
+	klog.V(4).Infof("Announcing the key version %d to the remote gateway", keys.Version)
+	_, err = kr.conn.WriteToUDP(msg.marshal(macKey), kr.peer)
+	return err
+}
+
+// switchKey configures the interface with the new private key.
+func (kr *KeyRotator) switchKey(ctx context.Context, keys *Keys) error {
+	kr.options.KeysMutex.Lock()
+	err := kr.wgcl.ConfigureDevice(tunnel.TunnelInterfaceName, wgtypes.Config{PrivateKey: &keys.PrivateKey})
+	if err == nil {
+		kr.options.PrivateKey = keys.PrivateKey
+		kr.options.KeyVersion = keys.Version
+	}
+	kr.options.KeysMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to configure the new private key: %w", err)
+	}
+
+	klog.Infof("Switched to the key version %d", keys.Version)
+	return UpdateConnectionKeyRotation(ctx, kr.client, kr.options, func(status *networkingv1beta1.ConnectionKeyRotation) {
+		status.LocalKeyVersion = keys.Version
+		status.LastLocalRotation = ptrNow()
+	})
+}
+
+func (kr *KeyRotator) isAcknowledged(keys *Keys) bool {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	return kr.acknowledged != nil && kr.acknowledged.Version == keys.Version &&
+		kr.acknowledged.PublicKey == keys.PrivateKey.PublicKey()
+}
+
+// receive handles the key exchange messages received from the remote gateway.
+func (kr *KeyRotator) receive(ctx context.Context) {
+	buf := make([]byte, 2*keyExchangeMessageLength)
+	for {
+		n, from, err := kr.conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			klog.Warningf("Unable to receive key exchange messages: %v", err)
+			continue
+		}
+		if !from.IP.Equal(kr.peer.IP) {
+			klog.V(4).Infof("Discarding key exchange message from unexpected address %s", from)
+			continue
+		}
+		kr.handleMessage(ctx, buf[:n])
+	}
+}
+
+func (kr *KeyRotator) handleMessage(ctx context.Context, buf []byte) {
+	publicKey, err := getters.GetPublicKeyByClusterID(ctx, kr.client,
+		liqov1beta1.ClusterID(kr.options.GwOptions.RemoteClusterID), kr.options.GwOptions.Namespace)
+	if err != nil {
+		klog.Warningf("Unable to get the public key of the remote gateway: %v", err)
+		return
+	}
+
+	msg, macKey, err := parseKeyExchangeMessage(buf, kr.macKeys(publicKey))
+	if err != nil {
+		klog.Warningf("Discarding key exchange message: %v", err)
+		return
+	}
+
+	switch msg.Type {
+	case keyAnnouncement:
+		kr.handleAnnouncement(ctx, publicKey, msg, macKey)
+	case keyAcknowledgement:
+		kr.mutex.Lock()
+		kr.acknowledged = msg
+		kr.mutex.Unlock()
+	default:
+		klog.Warningf("Discarding key exchange message of unknown type %d", msg.Type)
+	}
+}
+
+// handleAnnouncement accepts the new public key announced by the remote gateway, and acknowledges it once configured.
+func (kr *KeyRotator) handleAnnouncement(ctx context.Context, publicKey *networkingv1beta1.PublicKey,
+	msg *keyExchangeMessage, macKey []byte) {
+	switch {
+	case msg.Version > publicKey.Spec.Version:
+		// The PublicKey update triggers the configuration of the new peer. The acknowledgement
+		// is sent in response to the following announcements, once the peer is configured.
+		if err := kr.acceptKey(ctx, publicKey, msg); err != nil {
+			klog.Errorf("Unable to accept the key version %d of the remote gateway: %v", msg.Version, err)
+		}
+	case msg.Version == publicKey.Spec.Version && bytes.Equal(publicKey.Spec.PublicKey, msg.PublicKey[:]):
+		configured, err := kr.isPeerConfigured(msg.PublicKey)
+		if err != nil {
+			klog.Warningf("Unable to check the peers of the device: %v", err)
+			return
+		}
+		if !configured {
+			return
+		}
+		ack := &keyExchangeMessage{Type: keyAcknowledgement, Version: msg.Version, PublicKey: msg.PublicKey}
+		if _, err := kr.conn.WriteToUDP(ack.marshal(macKey), kr.peer); err != nil {
+			klog.Warningf("Unable to acknowledge the key version %d of the remote gateway: %v", msg.Version, err)
+		}
+	default:
+		klog.V(4).Infof("Ignoring the stale key version %d announced by the remote gateway", msg.Version)
+	}
+}
+
+// acceptKey updates the PublicKey resource with the new key of the remote gateway, keeping the previous one
+// until the end of the transition window.
+func (kr *KeyRotator) acceptKey(ctx context.Context, publicKey *networkingv1beta1.PublicKey, msg *keyExchangeMessage) error {
+	publicKey.Spec.PreviousPublicKey = publicKey.Spec.PublicKey
+	publicKey.Spec.PreviousPublicKeyExpiration = &metav1.Time{Time: msg.PreviousExpiration}
+	publicKey.Spec.PublicKey = msg.PublicKey[:]
+	publicKey.Spec.Version = msg.Version
+	if err := kr.client.Update(ctx, publicKey); err != nil {
+		return err
+	}
+
+	klog.Infof("Accepted the key version %d of the remote gateway", msg.Version)
+	return UpdateConnectionKeyRotation(ctx, kr.client, kr.options, func(status *networkingv1beta1.ConnectionKeyRotation) {
+		status.RemoteKeyVersion = msg.Version
+		status.LastRemoteRotation = ptrNow()
+	})
+}
+
+func (kr *KeyRotator) isPeerConfigured(key wgtypes.Key) (bool, error) {
+	device, err := kr.wgcl.Device(tunnel.TunnelInterfaceName)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(device.Peers, func(peer wgtypes.Peer) bool { return peer.PublicKey == key }), nil
+}
+
+// macKeys returns the keys possibly authenticating the messages of the remote gateway, derived from the local private
+// keys (the one in use and the ones of the keys secret) and the public keys of the remote gateway.
+func (kr *KeyRotator) macKeys(publicKey *networkingv1beta1.PublicKey) [][]byte {
+	kr.options.KeysMutex.Lock()
+	privates := []wgtypes.Key{kr.options.PrivateKey}
+	kr.options.KeysMutex.Unlock()
+	if keys, err := ReadKeys(kr.options.KeysDir); err == nil {
+		privates = append(privates, keys.PrivateKey)
+		if keys.PreviousPrivateKey != nil {
+			privates = append(privates, *keys.PreviousPrivateKey)
+		}
+	}
+
+	var peers []wgtypes.Key
+	for _, raw := range [][]byte{publicKey.Spec.PublicKey, publicKey.Spec.PreviousPublicKey} {
+		if key, err := wgtypes.NewKey(raw); err == nil {
+			peers = append(peers, key)
+		}
+	}
+
+	var macKeys [][]byte
+	for _, private := range slices.Compact(privates) {
+		for _, peer := range slices.Compact(peers) {
+			if key, err := keyExchangeMACKey(private, peer); err == nil {
+				macKeys = append(macKeys, key)
+			}
+		}
+	}
+	return macKeys
+}
+
+func ptrNow() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
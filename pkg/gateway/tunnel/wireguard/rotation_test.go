@@ -0,0 +1,197 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Key rotation", func() {
+	var (
+		localKey, remoteKey wgtypes.Key
+	)
+
+	BeforeEach(func() {
+		var err error
+		localKey, err = wgtypes.GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		remoteKey, err = wgtypes.GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("the key exchange messages", func() {
+		var (
+			msg                 *keyExchangeMessage
+			localMAC, remoteMAC []byte
+		)
+
+		BeforeEach(func() {
+			newKey, err := wgtypes.GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			msg = &keyExchangeMessage{
+				Type:               keyAnnouncement,
+				Version:            3,
+				PublicKey:          newKey.PublicKey(),
+				PreviousExpiration: time.Unix(1700000000, 0),
+			}
+
+			localMAC, err = keyExchangeMACKey(localKey, remoteKey.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+			remoteMAC, err = keyExchangeMACKey(remoteKey, localKey.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should derive the same authentication key on both sides", func() {
+			Expect(localMAC).To(Equal(remoteMAC))
+		})
+
+		It("should be decoded by the remote gateway", func() {
+			buf := msg.marshal(localMAC)
+			Expect(buf).To(HaveLen(keyExchangeMessageLength))
+
+			parsed, macKey, err := parseKeyExchangeMessage(buf, [][]byte{{0x01}, remoteMAC})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(macKey).To(Equal(remoteMAC))
+			Expect(parsed.Type).To(Equal(msg.Type))
+			Expect(parsed.Version).To(Equal(msg.Version))
+			Expect(parsed.PublicKey).To(Equal(msg.PublicKey))
+			Expect(parsed.PreviousExpiration.Equal(msg.PreviousExpiration)).To(BeTrue())
+		})
+
+		It("should be rejected if authenticated with a different key", func() {
+			otherKey, err := wgtypes.GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			otherMAC, err := keyExchangeMACKey(otherKey, remoteKey.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = parseKeyExchangeMessage(msg.marshal(otherMAC), [][]byte{remoteMAC})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should be rejected if tampered", func() {
+			buf := msg.marshal(localMAC)
+			buf[10]++
+			_, _, err := parseKeyExchangeMessage(buf, [][]byte{remoteMAC})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should be rejected if truncated", func() {
+			_, _, err := parseKeyExchangeMessage(msg.marshal(localMAC)[:keyExchangeMessageLength-1], [][]byte{remoteMAC})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the peer keys", func() {
+		var (
+			publicKey *networkingv1beta1.PublicKey
+			now       time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			current, previous := localKey.PublicKey(), remoteKey.PublicKey()
+			publicKey = &networkingv1beta1.PublicKey{Spec: networkingv1beta1.PublicKeySpec{
+				PublicKey:                   current[:],
+				PreviousPublicKey:           previous[:],
+				PreviousPublicKeyExpiration: &metav1.Time{Time: now.Add(time.Minute)},
+			}}
+		})
+
+		It("should include the previous key during the transition window", func() {
+			keys, requeue, err := peerKeys(publicKey, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]wgtypes.Key{localKey.PublicKey(), remoteKey.PublicKey()}))
+			Expect(requeue).To(Equal(time.Minute + time.Second))
+		})
+
+		It("should not include the previous key once expired", func() {
+			keys, requeue, err := peerKeys(publicKey, now.Add(time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]wgtypes.Key{localKey.PublicKey()}))
+			Expect(requeue).To(BeZero())
+		})
+	})
+
+	Describe("the keys secret", func() {
+		var (
+			ctx    context.Context
+			cl     client.Client
+			secret *corev1.Secret
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			pub := localKey.PublicKey()
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "default"},
+				Data: map[string][]byte{
+					consts.PrivateKeyField: localKey[:],
+					consts.PublicKeyField:  pub[:],
+				},
+			}
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+		})
+
+		It("should parse the keys generated before the first rotation", func() {
+			keys, err := ParseKeys(secret.Data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys.PrivateKey).To(Equal(localKey))
+			Expect(keys.Version).To(BeZero())
+			Expect(keys.PreviousPrivateKey).To(BeNil())
+		})
+
+		It("should keep the previous keys until the end of the transition window", func() {
+			Expect(RotateKeysSecret(ctx, cl, secret, time.Minute)).To(Succeed())
+
+			keys, err := ParseKeys(secret.Data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys.PrivateKey).ToNot(Equal(localKey))
+			Expect(keys.Version).To(BeEquivalentTo(1))
+			Expect(*keys.PreviousPrivateKey).To(Equal(localKey))
+			Expect(keys.PreviousValid(time.Now())).To(BeTrue())
+			Expect(keys.PreviousValid(time.Now().Add(2 * time.Minute))).To(BeFalse())
+			Expect(secret.Annotations).To(HaveKey(consts.KeyRotationTimestampAnnotation))
+
+			// The previous keys are still valid, hence they are not purged.
+			Expect(PurgePreviousKeys(ctx, cl, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKey(consts.PreviousPrivateKeyField))
+		})
+
+		It("should purge the previous keys at the end of the transition window", func() {
+			Expect(RotateKeysSecret(ctx, cl, secret, 0)).To(Succeed())
+			Expect(PurgePreviousKeys(ctx, cl, secret)).To(Succeed())
+
+			var updated corev1.Secret
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(secret), &updated)).To(Succeed())
+			Expect(updated.Data).ToNot(HaveKey(consts.PreviousPrivateKeyField))
+			Expect(updated.Data).ToNot(HaveKey(consts.PreviousPublicKeyField))
+			Expect(updated.Data).ToNot(HaveKey(consts.PreviousKeyExpirationField))
+			Expect(updated.Data).To(HaveKeyWithValue(consts.KeyVersionField, []byte("1")))
+		})
+	})
+})
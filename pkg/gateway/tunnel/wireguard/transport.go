@@ -0,0 +1,90 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/tunnel/stream"
+)
+
+const (
+	// DefaultTransportLocalPort is the default local port relaying the tunnel to the stream transport.
+	DefaultTransportLocalPort = 51842
+	// DefaultTransportMaxStreams is the default maximum number of concurrent streams accepted by the server.
+	DefaultTransportMaxStreams = 16
+)
+
+// SetupTransport sets up the stream transport carrying the tunnel, if required, returning whether it has been set up.
+// In server mode, the streams are accepted on the listen port, and relayed to the local WireGuard socket.
+// In client mode, the transport is selected based on the protocol advertised by the server endpoint,
+// and the WireGuard peer is pointed to the local end of the stream, which takes care of resolving the endpoint addresses.
+// In both modes, the WebSocket streams are secured with TLS, if configured.
+func SetupTransport(mgr manager.Manager, options *Options) (bool, error) {
+	switch options.GwOptions.Mode {
+	case gateway.ModeServer:
+		if !options.Transport.IsStream() {
+			return false, nil
+		}
+		var tlsConfig *tls.Config
+		if options.TransportTLSCertFile != "" || options.TransportTLSKeyFile != "" {
+			var err error
+			if tlsConfig, err = stream.ServerTLSConfig(options.TransportTLSCertFile, options.TransportTLSKeyFile); err != nil {
+				return false, err
+			}
+		}
+		server := stream.NewServer(options.ListenPort, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: options.ListenPort},
+			tlsConfig, options.TransportMaxStreams)
+		if err := mgr.Add(server); err != nil {
+			return false, fmt.Errorf("unable to add the stream server: %w", err)
+		}
+		return true, nil
+	case gateway.ModeClient:
+		transport := stream.ForEndpointProtocol(options.Transport, options.EndpointProtocol)
+		if !transport.IsStream() {
+			return false, nil
+		}
+		var tlsConfig *tls.Config
+		if options.TransportTLS {
+			if transport != stream.TransportWebSocket {
+				return false, fmt.Errorf("the TLS transport requires the %s transport, rather than %s", stream.TransportWebSocket, transport)
+			}
+			var err error
+			if tlsConfig, err = stream.ClientTLSConfig(options.TransportTLSCAFile, options.TransportTLSServerName); err != nil {
+				return false, err
+			}
+		}
+		client := stream.NewClient(transport, options.EndpointAddresses, options.EndpointPort, options.TransportLocalPort, tlsConfig)
+		if err := client.Listen(); err != nil {
+			return false, fmt.Errorf("unable to set up the stream client: %w", err)
+		}
+		if err := mgr.Add(client); err != nil {
+			return false, fmt.Errorf("unable to add the stream client: %w", err)
+		}
+
+		klog.Infof("Carrying the tunnel to %v (port %d) over %s", options.EndpointAddresses, options.EndpointPort, transport)
+		options.SetEndpointIP(client.LocalAddr().IP)
+		options.EndpointPort = client.LocalAddr().Port
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the trees of the snapshot in the Graphviz DOT format.
+// Acquired networks are highlighted, and labeled with their owner and IPs.
+func (s *Snapshot) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph G {")
+	for i := range s.Roots {
+		s.Roots[i].writeDOT(bw)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func (n *NodeSnapshot) writeDOT(w io.Writer) {
+	if n.Acquired {
+		fmt.Fprintf(w, "  %q [label=%q, style=filled, color=\"#57cc99\"];\n", n.Prefix, strings.Join(n.labelLines(), "\n"))
+	} else {
+		fmt.Fprintf(w, "  %q;\n", n.Prefix)
+	}
+	for _, child := range []*NodeSnapshot{n.Left, n.Right} {
+		if child != nil {
+			fmt.Fprintf(w, "  %q -> %q;\n", n.Prefix, child.Prefix)
+			child.writeDOT(w)
+		}
+	}
+}
+
+// WriteMermaid writes the trees of the snapshot as a Mermaid flowchart.
+// Acquired networks are highlighted, and labeled with their owner and IPs.
+func (s *Snapshot) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart TD")
+	fmt.Fprintln(bw, "  classDef acquired fill:#57cc99")
+	ids := map[*NodeSnapshot]string{}
+	for i := range s.Roots {
+		s.Roots[i].writeMermaid(bw, ids)
+	}
+	return bw.Flush()
+}
+
+func (n *NodeSnapshot) writeMermaid(w io.Writer, ids map[*NodeSnapshot]string) {
+	id := fmt.Sprintf("n%d", len(ids))
+	ids[n] = id
+
+	label := n.Prefix.String()
+	if n.Acquired {
+		label = strings.Join(n.labelLines(), "<br/>")
+	}
+	fmt.Fprintf(w, "  %s[\"%s\"]\n", id, strings.ReplaceAll(label, `"`, "#quot;"))
+	if n.Acquired {
+		fmt.Fprintf(w, "  class %s acquired\n", id)
+	}
+
+	for _, child := range []*NodeSnapshot{n.Left, n.Right} {
+		if child != nil {
+			child.writeMermaid(w, ids)
+			fmt.Fprintf(w, "  %s --> %s\n", id, ids[child])
+		}
+	}
+}
+
+// labelLines returns the lines describing an acquired network: its prefix, owner and IPs.
+func (n *NodeSnapshot) labelLines() []string {
+	lines := []string{n.Prefix.String()}
+	if n.Owner != nil {
+		lines = append(lines, n.Owner.String())
+	}
+	for i := range n.IPs {
+		if n.IPs[i].Owner != nil {
+			lines = append(lines, fmt.Sprintf("%s (%s)", n.IPs[i].Addr, n.IPs[i].Owner))
+		} else {
+			lines = append(lines, n.IPs[i].Addr.String())
+		}
+	}
+	return lines
+}
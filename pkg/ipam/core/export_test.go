@@ -0,0 +1,59 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"net/netip"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam tree export", func() {
+	var snapshot *Snapshot
+
+	BeforeEach(func() {
+		ipam, err := NewIpam([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/30")})
+		Expect(err).NotTo(HaveOccurred())
+		network := netip.MustParsePrefix("10.0.0.0/31")
+		Expect(ipam.NetworkAcquireWithPrefix(network)).ToNot(BeNil())
+		Expect(ipam.NetworkSetOwner(network, Owner{Kind: "Network", Namespace: "default", Name: "foo"})).To(Succeed())
+		_, err = ipam.IPAcquire(network)
+		Expect(err).NotTo(HaveOccurred())
+		snapshot = ipam.Snapshot()
+	})
+
+	It("should export the trees in the DOT format", func() {
+		var sb strings.Builder
+		Expect(snapshot.WriteDOT(&sb)).To(Succeed())
+		Expect(sb.String()).To(HavePrefix("digraph G {\n"))
+		Expect(sb.String()).To(ContainSubstring(`"10.0.0.0/30" -> "10.0.0.0/31";`))
+		Expect(sb.String()).To(ContainSubstring(`"10.0.0.0/30" -> "10.0.0.2/31";`))
+		Expect(sb.String()).To(ContainSubstring(`"10.0.0.0/31" [label="10.0.0.0/31\nNetwork default/foo\n10.0.0.0", style=filled`))
+		Expect(sb.String()).To(HaveSuffix("}\n"))
+	})
+
+	It("should export the trees as a Mermaid flowchart", func() {
+		var sb strings.Builder
+		Expect(snapshot.WriteMermaid(&sb)).To(Succeed())
+		Expect(sb.String()).To(HavePrefix("flowchart TD\n"))
+		Expect(sb.String()).To(ContainSubstring(`n0["10.0.0.0/30"]`))
+		Expect(sb.String()).To(ContainSubstring(`n1["10.0.0.0/31<br/>Network default/foo<br/>10.0.0.0"]`))
+		Expect(sb.String()).To(ContainSubstring("class n1 acquired"))
+		Expect(sb.String()).To(ContainSubstring("n0 --> n1"))
+		Expect(sb.String()).To(ContainSubstring("n0 --> n2"))
+	})
+})
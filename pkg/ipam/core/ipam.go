@@ -23,6 +23,10 @@ import (
 // Ipam represents the IPAM core structure.
 type Ipam struct {
 	roots []node
+	// draining contains the pools no network is allocated from by NetworkAcquire.
+	draining map[netip.Prefix]struct{}
+	// reservations contains the ranges of the pools reserved to specific owners.
+	reservations []Reservation
 }
 
 // NewIpam creates a new IPAM instance.
@@ -43,10 +47,16 @@ func NewIpam(pools []netip.Prefix) (*Ipam, error) {
 	return ipam, nil
 }
 
-// NetworkAcquire allocates a network of the given size.
+// NetworkAcquire allocates a network of the given size from the pools of the given address family, skipping the draining ones.
 // It returns the allocated network or nil if no network is available.
-func (ipam *Ipam) NetworkAcquire(size int) *netip.Prefix {
+func (ipam *Ipam) NetworkAcquire(size int, family Family) *netip.Prefix {
 	for i := range ipam.roots {
+		if FamilyOf(ipam.roots[i].prefix) != family || size > ipam.roots[i].prefix.Addr().BitLen() {
+			continue
+		}
+		if ipam.IsPoolDraining(ipam.roots[i].prefix) {
+			continue
+		}
 		if result := allocateNetwork(size, &ipam.roots[i]); result != nil {
 			return result
 		}
@@ -186,7 +196,6 @@ func (ipam *Ipam) IsPrefixInRoots(prefix netip.Prefix) bool {
 // ToGraphviz generates the Graphviz representation of the IPAM structure.
 func (ipam *Ipam) ToGraphviz() error {
 	for i := range ipam.roots {
-		_ = i
 		if err := ipam.roots[i].toGraphviz(); err != nil {
 			return fmt.Errorf("failed to generate Graphviz representation: %w", err)
 		}
@@ -209,6 +218,9 @@ func (ipam *Ipam) search(prefix netip.Prefix) (*node, error) {
 
 func checkRoots(roots []netip.Prefix) error {
 	for i := range roots {
+		if !roots[i].IsValid() {
+			return fmt.Errorf("%s: invalid prefix", roots[i])
+		}
 		if err := checkHostBitsZero(roots[i]); err != nil {
 			return err
 		}
@@ -217,7 +229,7 @@ func checkRoots(roots []netip.Prefix) error {
 }
 
 // NetworkSetLastUpdateTimestamp sets the last update time of the network with the given prefix.
-// This function is for testing purposes only.
+// It is used to replicate the state of the leader, and for testing purposes.
 func (ipam *Ipam) NetworkSetLastUpdateTimestamp(prefix netip.Prefix, lastUpdateTimestamp time.Time) error {
 	node, err := ipam.search(prefix)
 	if err != nil {
@@ -231,7 +243,7 @@ func (ipam *Ipam) NetworkSetLastUpdateTimestamp(prefix netip.Prefix, lastUpdateT
 }
 
 // IPSetCreationTimestamp sets the creation timestamp of the IP address with the given address.
-// This function is for testing purposes only.
+// It is used to replicate the state of the leader, and for testing purposes.
 func (ipam *Ipam) IPSetCreationTimestamp(addr netip.Addr, prefix netip.Prefix, creationTimestamp time.Time) error {
 	node, err := ipam.search(prefix)
 	if err != nil {
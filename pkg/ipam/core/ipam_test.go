@@ -119,7 +119,7 @@ var _ = Describe("Ipam", func() {
 			)
 
 			BeforeEach(func() {
-				acquiredPrefix = ipam.NetworkAcquire(32)
+				acquiredPrefix = ipam.NetworkAcquire(32, FamilyIPv4)
 				Expect(acquiredPrefix).NotTo(BeNil())
 				Expect(ipam.NetworkIsAvailable(*acquiredPrefix)).To(BeFalse())
 			})
@@ -147,7 +147,7 @@ var _ = Describe("Ipam", func() {
 			)
 
 			BeforeEach(func() {
-				acquiredPrefix = ipam.NetworkAcquire(32)
+				acquiredPrefix = ipam.NetworkAcquire(32, FamilyIPv4)
 				Expect(acquiredPrefix).NotTo(BeNil())
 				Expect(ipam.NetworkIsAvailable(*acquiredPrefix)).To(BeFalse())
 
@@ -188,7 +188,7 @@ var _ = Describe("Ipam", func() {
 				sizes := []int{21, 26, 27, 22, 30, 25, 28, 24, 16, 10, 29}
 				for _, size := range sizes {
 					for i := 0; i < 3; i++ {
-						network := ipam.NetworkAcquire(size)
+						network := ipam.NetworkAcquire(size, FamilyIPv4)
 						Expect(network).ShouldNot(BeNil())
 						for j := 0; j < 3; j++ {
 							addr, err := ipam.IPAcquire(*network)
@@ -230,11 +230,11 @@ var _ = Describe("Ipam", func() {
 				Expect(networks).Should(HaveLen(0))
 
 				acquiredNetworks := []netip.Prefix{}
-				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(24))
-				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(25))
-				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(26))
-				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(27))
-				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(28))
+				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(24, FamilyIPv4))
+				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(25, FamilyIPv4))
+				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(26, FamilyIPv4))
+				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(27, FamilyIPv4))
+				acquiredNetworks = append(acquiredNetworks, *ipam.NetworkAcquire(28, FamilyIPv4))
 
 				networks = ipam.ListNetworks()
 				Expect(networks).Should(HaveLen(5))
@@ -246,20 +246,20 @@ var _ = Describe("Ipam", func() {
 
 		When("acquiring networks", func() {
 			It("should succeed", func() {
-				network := ipam.NetworkAcquire(24)
+				network := ipam.NetworkAcquire(24, FamilyIPv4)
 				Expect(network).ShouldNot(BeNil())
 				Expect(ipam.NetworkIsAvailable(*network)).To(BeFalse())
 			})
 
 			It("should not succeed", func() {
-				network := ipam.NetworkAcquire(4)
+				network := ipam.NetworkAcquire(4, FamilyIPv4)
 				Expect(network).Should(BeNil())
 			})
 		})
 
 		When("releasing networks", func() {
 			It("should succeed", func() {
-				network := ipam.NetworkAcquire(16)
+				network := ipam.NetworkAcquire(16, FamilyIPv4)
 				Expect(network).ShouldNot(BeNil())
 				Expect(ipam.NetworkIsAvailable(*network)).To(BeFalse())
 				Expect(ipam.NetworkRelease(*network, 0).String()).To(Equal(network.String()))
@@ -277,7 +277,7 @@ var _ = Describe("Ipam", func() {
 
 			It("should succeed (with grace period expired)", func() {
 				gracePeriod := time.Second * 5
-				network := ipam.NetworkAcquire(16)
+				network := ipam.NetworkAcquire(16, FamilyIPv4)
 				Expect(ipam.NetworkSetLastUpdateTimestamp(*network, time.Now().Add(-gracePeriod))).Should(Succeed())
 				Expect(network).ShouldNot(BeNil())
 				Expect(ipam.NetworkIsAvailable(*network)).To(BeFalse())
@@ -715,4 +715,124 @@ var _ = Describe("Ipam", func() {
 			})
 		})
 	})
+	Context("Ipam IPv6 and dual-stack pools", func() {
+		var (
+			dualStackPools = []netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("fd00::/8"),
+			}
+		)
+
+		BeforeEach(func() {
+			var err error
+			ipam, err = NewIpam(dualStackPools)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("using invalid IPv6 pools", func() {
+			It("should return an error", func() {
+				_, err := NewIpam([]netip.Prefix{netip.MustParsePrefix("fd00::1/8")})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("acquiring networks", func() {
+			It("should allocate from the pools of the requested family", func() {
+				network := ipam.NetworkAcquire(24, FamilyIPv4)
+				Expect(network).NotTo(BeNil())
+				Expect(network.Addr().Is4()).To(BeTrue())
+				Expect(network.Bits()).To(Equal(24))
+
+				network = ipam.NetworkAcquire(64, FamilyIPv6)
+				Expect(network).NotTo(BeNil())
+				Expect(network.Addr().Is6()).To(BeTrue())
+				Expect(network.Bits()).To(Equal(64))
+				Expect(dualStackPools[1].Contains(network.Addr())).To(BeTrue())
+
+				Expect(ipam.ListNetworks()).To(HaveLen(2))
+			})
+
+			It("should not allocate networks larger than the address length", func() {
+				Expect(ipam.NetworkAcquire(33, FamilyIPv4)).To(BeNil())
+				Expect(ipam.NetworkAcquire(129, FamilyIPv6)).To(BeNil())
+			})
+
+			It("should acquire and release a specific IPv6 network", func() {
+				network := netip.MustParsePrefix("fd00:0:0:42::/64")
+				Expect(ipam.IsPrefixInRoots(network)).To(BeTrue())
+				Expect(ipam.NetworkIsAvailable(network)).To(BeTrue())
+				Expect(ipam.NetworkAcquireWithPrefix(network).String()).To(Equal(network.String()))
+				Expect(ipam.NetworkIsAvailable(network)).To(BeFalse())
+				Expect(ipam.NetworkIsAvailable(netip.MustParsePrefix("fd00::/48"))).To(BeFalse())
+
+				Expect(ipam.NetworkRelease(network, 0).String()).To(Equal(network.String()))
+				Expect(ipam.NetworkIsAvailable(network)).To(BeTrue())
+				Expect(ipam.NetworkIsAvailable(dualStackPools[1])).To(BeTrue())
+			})
+
+			It("should respect the grace period when releasing IPv6 networks", func() {
+				gracePeriod := time.Second * 5
+				network := ipam.NetworkAcquire(120, FamilyIPv6)
+				Expect(network).NotTo(BeNil())
+				Expect(ipam.NetworkRelease(*network, gracePeriod)).To(BeNil())
+
+				Expect(ipam.NetworkSetLastUpdateTimestamp(*network, time.Now().Add(-gracePeriod))).Should(Succeed())
+				Expect(ipam.NetworkRelease(*network, gracePeriod).String()).To(Equal(network.String()))
+			})
+		})
+
+		When("acquiring IPs from an IPv6 network", func() {
+			var network netip.Prefix
+
+			BeforeEach(func() {
+				network = netip.MustParsePrefix("fd00::/126")
+				Expect(ipam.NetworkAcquireWithPrefix(network)).NotTo(BeNil())
+			})
+
+			It("should not overflow available IPs", func() {
+				for i := 0; i < 4; i++ {
+					addr, err := ipam.IPAcquire(network)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(addr).NotTo(BeNil())
+					Expect(network.Contains(*addr)).To(BeTrue())
+				}
+				addr, err := ipam.IPAcquire(network)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(addr).To(BeNil())
+			})
+
+			It("should acquire IPs from a large network", func() {
+				large := netip.MustParsePrefix("fd00:0:0:1::/64")
+				Expect(ipam.NetworkAcquireWithPrefix(large)).NotTo(BeNil())
+
+				specific := netip.MustParseAddr("fd00:0:0:1:ffff:ffff:ffff:ffff")
+				addr, err := ipam.IPAcquireWithAddr(large, specific)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(addr.String()).To(Equal(specific.String()))
+
+				addr, err = ipam.IPAcquire(large)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(addr.String()).To(Equal(large.Addr().String()))
+
+				ips, err := ipam.ListIPs(large)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ips).To(ConsistOf(specific, large.Addr()))
+
+				released, err := ipam.IPRelease(large, specific, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(released.String()).To(Equal(specific.String()))
+			})
+		})
+
+		When("generating graphviz", func() {
+			It("it should succeed", func() {
+				Expect(ipam.NetworkAcquire(64, FamilyIPv6)).NotTo(BeNil())
+				Expect(ipam.ToGraphviz()).Should(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(graphvizFolder)).ShouldNot(HaveOccurred())
+			})
+		})
+	})
 })
@@ -17,20 +17,10 @@ package ipamcore
 import (
 	"fmt"
 	"net/netip"
-	"strings"
 
 	"k8s.io/apimachinery/pkg/util/runtime"
 )
 
-// convertByteSliceToString converts a slice of bytes to a comma-separated string.
-func convertByteSliceToString(byteSlice []byte) string {
-	strSlice := make([]string, len(byteSlice))
-	for i, b := range byteSlice {
-		strSlice[i] = fmt.Sprintf("%d", b)
-	}
-	return strings.Join(strSlice, ".")
-}
-
 // setBit sets the bit at the given position to 1.
 func setBit(b byte, position int) (byte, error) {
 	if position > 7 || position < 0 {
@@ -49,36 +39,39 @@ func checkHostBitsZero(prefix netip.Prefix) error {
 // splitNetworkPrefix splits a network prefix into two subnets.
 // It increases the prefix length by one and sets the bit at
 // the new position to 0 or 1 to retrieve the two subnets.
+// It works for both IPv4 and IPv6 prefixes.
 func splitNetworkPrefix(prefix netip.Prefix) (left, right netip.Prefix) {
 	// We neer to check that the host bits are zero.
 	runtime.Must(checkHostBitsZero(prefix))
 
-	// We need to convert the prefix to a byte slice to manipulate it.
-	bin, err := prefix.MarshalBinary()
-	runtime.Must(err)
-
 	// We need to get the mask length to know where to split the prefix.
-	maskLen := bin[len(bin)-1]
+	maskLen := prefix.Bits()
+	if maskLen >= prefix.Addr().BitLen() {
+		runtime.Must(fmt.Errorf("%s: prefix cannot be splitted", prefix))
+	}
 
-	// Since the prefix host bits are zero, we just need to shift
+	// Since the prefix host bits are zero, we just need to increase
 	// the mask length by one to get the first splitted prefix.
-	left = netip.MustParsePrefix(
-		fmt.Sprintf("%s/%d", convertByteSliceToString(bin[:4]), maskLen+1),
-	)
+	left = netip.PrefixFrom(prefix.Addr(), maskLen+1)
 
 	// We need to set the bit at the mask length position to 1 to get the second splitted prefix.
-	// Since the IP is expressed like a slice of bytes, we need to get the byte index and the bit index to set the bit.
+	// Since the IP is expressed like a slice of bytes (4 for IPv4, 16 for IPv6),
+	// we need to get the byte index and the bit index to set the bit.
+	bin := prefix.Addr().AsSlice()
 	byteIndex := maskLen / 8
 	bitIndex := maskLen % 8
 
 	// We set the bit at the mask length position to 1.
-	bin[byteIndex], err = setBit(bin[byteIndex], int(bitIndex))
+	var err error
+	bin[byteIndex], err = setBit(bin[byteIndex], bitIndex)
 	runtime.Must(err)
 
 	// We forge and return the second splitted prefix.
-	right = netip.MustParsePrefix(
-		fmt.Sprintf("%s/%d", convertByteSliceToString(bin[:4]), maskLen+1),
-	)
+	addr, ok := netip.AddrFromSlice(bin)
+	if !ok {
+		runtime.Must(fmt.Errorf("%s: invalid address after split", prefix))
+	}
+	right = netip.PrefixFrom(addr, maskLen+1)
 
 	return left, right
 }
@@ -90,3 +83,33 @@ func isPrefixChildOf(parent, child netip.Prefix) bool {
 	}
 	return false
 }
+
+// Family identifies the address family of a network.
+type Family int
+
+const (
+	// FamilyIPv4 identifies IPv4 networks.
+	FamilyIPv4 Family = 4
+	// FamilyIPv6 identifies IPv6 networks.
+	FamilyIPv6 Family = 6
+)
+
+// String returns the string representation of the family.
+func (f Family) String() string {
+	switch f {
+	case FamilyIPv4:
+		return "IPv4"
+	case FamilyIPv6:
+		return "IPv6"
+	default:
+		return fmt.Sprintf("Family(%d)", int(f))
+	}
+}
+
+// FamilyOf returns the address family of the given prefix.
+func FamilyOf(prefix netip.Prefix) Family {
+	if prefix.Addr().Is4() {
+		return FamilyIPv4
+	}
+	return FamilyIPv6
+}
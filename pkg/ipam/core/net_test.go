@@ -15,6 +15,8 @@
 package ipamcore
 
 import (
+	"net/netip"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -82,4 +84,43 @@ var _ = Describe("Ipam low level utilities ", func() {
 			})
 		})
 	})
+	Context("prefix operations", func() {
+		When("splitting a network prefix", func() {
+			It("should split IPv4 prefixes", func() {
+				left, right := splitNetworkPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+				Expect(left).To(Equal(netip.MustParsePrefix("10.0.0.0/9")))
+				Expect(right).To(Equal(netip.MustParsePrefix("10.128.0.0/9")))
+
+				left, right = splitNetworkPrefix(netip.MustParsePrefix("192.168.1.0/31"))
+				Expect(left).To(Equal(netip.MustParsePrefix("192.168.1.0/32")))
+				Expect(right).To(Equal(netip.MustParsePrefix("192.168.1.1/32")))
+			})
+
+			It("should split IPv6 prefixes", func() {
+				left, right := splitNetworkPrefix(netip.MustParsePrefix("fd00::/8"))
+				Expect(left).To(Equal(netip.MustParsePrefix("fd00::/9")))
+				Expect(right).To(Equal(netip.MustParsePrefix("fd80::/9")))
+
+				left, right = splitNetworkPrefix(netip.MustParsePrefix("fd00:0:0:1::/64"))
+				Expect(left).To(Equal(netip.MustParsePrefix("fd00:0:0:1::/65")))
+				Expect(right).To(Equal(netip.MustParsePrefix("fd00:0:0:1:8000::/65")))
+
+				left, right = splitNetworkPrefix(netip.MustParsePrefix("fd00::/127"))
+				Expect(left).To(Equal(netip.MustParsePrefix("fd00::/128")))
+				Expect(right).To(Equal(netip.MustParsePrefix("fd00::1/128")))
+			})
+
+			It("should panic with host prefixes", func() {
+				Expect(func() { splitNetworkPrefix(netip.MustParsePrefix("10.0.0.1/32")) }).To(Panic())
+				Expect(func() { splitNetworkPrefix(netip.MustParsePrefix("fd00::1/128")) }).To(Panic())
+			})
+		})
+
+		When("retrieving the family of a prefix", func() {
+			It("should return the correct family", func() {
+				Expect(FamilyOf(netip.MustParsePrefix("10.0.0.0/8"))).To(Equal(FamilyIPv4))
+				Expect(FamilyOf(netip.MustParsePrefix("fd00::/8"))).To(Equal(FamilyIPv6))
+			})
+		})
+	})
 })
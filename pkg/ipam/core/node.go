@@ -16,7 +16,6 @@ package ipamcore
 
 import (
 	"fmt"
-	"math"
 	"net/netip"
 	"os"
 	"path/filepath"
@@ -28,6 +27,7 @@ import (
 type nodeIP struct {
 	addr              netip.Addr
 	creationTimestamp time.Time
+	owner             *Owner
 }
 
 // node represents a node in the binary tree.
@@ -41,6 +41,7 @@ type node struct {
 
 	ips    []nodeIP
 	lastip netip.Addr
+	owner  *Owner
 }
 
 type nodeDirection string
@@ -116,6 +117,7 @@ func networkRelease(prefix netip.Prefix, node *node, gracePeriod time.Duration)
 		node.lastUpdateTimestamp.Add(gracePeriod).Before(time.Now()) {
 		if node.acquired {
 			node.acquired = false
+			node.owner = nil
 			node.lastUpdateTimestamp = time.Now()
 			return &node.prefix
 		}
@@ -189,8 +191,6 @@ func (n *node) ipAcquire() *netip.Addr {
 		return nil
 	}
 
-	size := int(math.Pow(2, float64(n.prefix.Addr().BitLen()-n.prefix.Bits())))
-
 	// If the lastip is not initialized, set it to the first address of the prefix.
 	if !n.lastip.IsValid() {
 		n.lastip = n.prefix.Addr()
@@ -202,21 +202,31 @@ func (n *node) ipAcquire() *netip.Addr {
 		addr = addr.Next()
 	}
 
-	for i := 0; i < size; i++ {
-		// we need to check if the address is contained in the prefix.
-		// If it is not, we need to set it to the first address of the prefix to prevent overflow.
-		if !n.prefix.Contains(addr) {
-			addr = n.prefix.Addr()
-		}
+	// We iterate over the addresses of the prefix until we find a free one or we get back to the starting point.
+	// The size of the prefix is not computed in advance, since IPv6 prefixes may contain more than 2^64 addresses.
+	start := n.nextInPrefix(addr)
+	addr = start
+	for {
 		if !n.isAllocatedIP(addr) {
 			n.ips = append(n.ips, nodeIP{addr: addr, creationTimestamp: time.Now()})
 			n.lastip = addr
 			n.lastUpdateTimestamp = time.Now()
 			return &addr
 		}
-		addr = addr.Next()
+		addr = n.nextInPrefix(addr.Next())
+		if addr.Compare(start) == 0 {
+			return nil
+		}
 	}
-	return nil
+}
+
+// nextInPrefix returns the given address if it is contained in the node prefix.
+// Otherwise, it returns the first address of the prefix to prevent overflow.
+func (n *node) nextInPrefix(addr netip.Addr) netip.Addr {
+	if !n.prefix.Contains(addr) {
+		return n.prefix.Addr()
+	}
+	return addr
 }
 
 func (n *node) allocateIPWithAddr(addr netip.Addr) *netip.Addr {
@@ -359,7 +369,7 @@ func (n *node) toGraphviz() error {
 		}
 	}
 
-	filePath := filepath.Clean(graphvizFolder + "/" + strings.NewReplacer("/", "_", ".", "_").Replace(n.prefix.String()) + ".dot")
+	filePath := filepath.Clean(graphvizFolder + "/" + strings.NewReplacer("/", "_", ".", "_", ":", "_").Replace(n.prefix.String()) + ".dot")
 	file, err := os.Create(filePath)
 	if err != nil {
 		return err
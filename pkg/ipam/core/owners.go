@@ -0,0 +1,164 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// Owner identifies the object an allocation belongs to (e.g., the Network or IP resource it has been requested for).
+type Owner struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+}
+
+// Allocation is a network or IP allocated on behalf of an owner.
+type Allocation struct {
+	// Network is the allocated network, or the network the IP belongs to.
+	Network netip.Prefix
+	// Addr is the allocated IP. It is the zero value for network allocations.
+	Addr netip.Addr
+	// Owner is the owner of the allocation.
+	Owner Owner
+	// Timestamp is the time the allocation has been performed (or last updated, for networks).
+	Timestamp time.Time
+}
+
+// Matches checks whether the given owner identifies the same object. If both UIDs are set, they are compared,
+// so that an object recreated with the same name is considered a different owner.
+func (o *Owner) Matches(other *Owner) bool {
+	if o == nil || other == nil {
+		return false
+	}
+	if o.UID != "" && other.UID != "" {
+		return o.UID == other.UID
+	}
+	return o.APIVersion == other.APIVersion && o.Kind == other.Kind && o.Namespace == other.Namespace && o.Name == other.Name
+}
+
+// String returns a human-readable representation of the owner.
+func (o *Owner) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s %s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+func (o *Owner) clone() *Owner {
+	if o == nil {
+		return nil
+	}
+	owner := *o
+	return &owner
+}
+
+// NetworkSetOwner sets the owner of the given allocated network.
+func (ipam *Ipam) NetworkSetOwner(prefix netip.Prefix, owner Owner) error {
+	node, err := ipam.search(prefix)
+	if err != nil {
+		return err
+	}
+	if node == nil || !node.acquired {
+		return fmt.Errorf("network %s is not allocated", prefix)
+	}
+
+	node.owner = &owner
+	return nil
+}
+
+// IPSetOwner sets the owner of the given allocated IP.
+func (ipam *Ipam) IPSetOwner(prefix netip.Prefix, addr netip.Addr, owner Owner) error {
+	node, err := ipam.search(prefix)
+	if err != nil {
+		return err
+	}
+	if node == nil || !node.acquired {
+		return fmt.Errorf("network %s is not allocated", prefix)
+	}
+
+	for i := range node.ips {
+		if node.ips[i].addr == addr {
+			node.ips[i].owner = &owner
+			return nil
+		}
+	}
+	return fmt.Errorf("IP %s is not allocated in network %s", addr, prefix)
+}
+
+// NetworkOwnedBy returns the network allocated on behalf of the given owner, or nil if none is found.
+func (ipam *Ipam) NetworkOwnedBy(owner Owner) *netip.Prefix {
+	for _, allocation := range ipam.ListOwned() {
+		if !allocation.Addr.IsValid() && allocation.Owner.Matches(&owner) {
+			return &allocation.Network
+		}
+	}
+	return nil
+}
+
+// IPOwnedBy returns the IP of the given network allocated on behalf of the given owner, or nil if none is found.
+func (ipam *Ipam) IPOwnedBy(prefix netip.Prefix, owner Owner) *netip.Addr {
+	node, err := ipam.search(prefix)
+	if err != nil || node == nil || !node.acquired {
+		return nil
+	}
+
+	for i := range node.ips {
+		if node.ips[i].owner.Matches(&owner) {
+			return &node.ips[i].addr
+		}
+	}
+	return nil
+}
+
+// ListOwned returns the networks and IPs allocated on behalf of an owner.
+// Allocations performed without specifying the owner are not included.
+func (ipam *Ipam) ListOwned() []Allocation {
+	var allocations []Allocation
+	for i := range ipam.roots {
+		allocations = append(allocations, listOwned(&ipam.roots[i])...)
+	}
+	return allocations
+}
+
+func listOwned(node *node) []Allocation {
+	if node.acquired {
+		var allocations []Allocation
+		if node.owner != nil {
+			allocations = append(allocations, Allocation{Network: node.prefix, Owner: *node.owner, Timestamp: node.lastUpdateTimestamp})
+		}
+		for i := range node.ips {
+			if node.ips[i].owner != nil {
+				allocations = append(allocations, Allocation{
+					Network: node.prefix, Addr: node.ips[i].addr, Owner: *node.ips[i].owner, Timestamp: node.ips[i].creationTimestamp,
+				})
+			}
+		}
+		return allocations
+	}
+
+	var allocations []Allocation
+	if node.left != nil {
+		allocations = append(allocations, listOwned(node.left)...)
+	}
+	if node.right != nil {
+		allocations = append(allocations, listOwned(node.right)...)
+	}
+	return allocations
+}
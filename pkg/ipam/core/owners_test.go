@@ -0,0 +1,89 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam allocation owners", func() {
+	var (
+		ipam    *Ipam
+		pool    = netip.MustParsePrefix("10.0.0.0/16")
+		network = netip.MustParsePrefix("10.0.1.0/24")
+		owner   = Owner{APIVersion: "ipam.liqo.io/v1alpha1", Kind: "Network", Namespace: "default", Name: "foo", UID: "uid-foo"}
+		ipOwner = Owner{APIVersion: "ipam.liqo.io/v1alpha1", Kind: "IP", Namespace: "default", Name: "bar", UID: "uid-bar"}
+	)
+
+	BeforeEach(func() {
+		var err error
+		ipam, err = NewIpam([]netip.Prefix{pool})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ipam.NetworkAcquireWithPrefix(network)).ToNot(BeNil())
+	})
+
+	Context("Matching owners", func() {
+		It("should compare the UIDs when both are set", func() {
+			other := owner
+			other.UID = "uid-other"
+			Expect(owner.Matches(&other)).To(BeFalse())
+			other.UID = ""
+			Expect(owner.Matches(&other)).To(BeTrue())
+			other.Name = "baz"
+			Expect(owner.Matches(&other)).To(BeFalse())
+		})
+	})
+
+	Context("Tagging allocations", func() {
+		var addr netip.Addr
+
+		BeforeEach(func() {
+			Expect(ipam.NetworkSetOwner(network, owner)).To(Succeed())
+			acquired, err := ipam.IPAcquire(network)
+			Expect(err).ToNot(HaveOccurred())
+			addr = *acquired
+			Expect(ipam.IPSetOwner(network, addr, ipOwner)).To(Succeed())
+		})
+
+		It("should look up the allocations by owner", func() {
+			Expect(ipam.NetworkOwnedBy(owner)).To(HaveValue(Equal(network)))
+			Expect(ipam.IPOwnedBy(network, ipOwner)).To(HaveValue(Equal(addr)))
+			Expect(ipam.NetworkOwnedBy(ipOwner)).To(BeNil())
+			Expect(ipam.ListOwned()).To(HaveLen(2))
+		})
+
+		It("should reject unallocated networks and IPs", func() {
+			Expect(ipam.NetworkSetOwner(netip.MustParsePrefix("10.0.2.0/24"), owner)).ToNot(Succeed())
+			Expect(ipam.IPSetOwner(network, netip.MustParseAddr("10.0.1.200"), ipOwner)).ToNot(Succeed())
+		})
+
+		It("should preserve the owners across snapshots", func() {
+			restored, err := NewIpam([]netip.Prefix{pool})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Restore(ipam.Snapshot())).To(Succeed())
+			Expect(restored.ListOwned()).To(ConsistOf(ipam.ListOwned()))
+		})
+
+		It("should forget the owner when the network is released", func() {
+			Expect(ipam.NetworkRelease(network, 0)).ToNot(BeNil())
+			Expect(ipam.ListOwned()).To(BeEmpty())
+			Expect(ipam.NetworkAcquireWithPrefix(network)).ToNot(BeNil())
+			Expect(ipam.NetworkOwnedBy(owner)).To(BeNil())
+		})
+	})
+})
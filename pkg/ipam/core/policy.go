@@ -0,0 +1,240 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"net/netip"
+	"slices"
+)
+
+// AllocationPolicy is the strategy used to choose the network to allocate among the free ones.
+type AllocationPolicy string
+
+const (
+	// AllocationPolicyFirstFit allocates the first free network found while visiting the tree of each pool.
+	AllocationPolicyFirstFit AllocationPolicy = "FirstFit"
+	// AllocationPolicyBestFit allocates the network from the smallest free block it fits in, to limit fragmentation.
+	AllocationPolicyBestFit AllocationPolicy = "BestFit"
+	// AllocationPolicyDeterministic allocates the network at a position derived from the allocation key,
+	// so that the same key is always assigned the same network, as long as it is available.
+	AllocationPolicyDeterministic AllocationPolicy = "Deterministic"
+)
+
+// AllocationPolicies contains the supported allocation policies.
+var AllocationPolicies = []AllocationPolicy{AllocationPolicyFirstFit, AllocationPolicyBestFit, AllocationPolicyDeterministic}
+
+const (
+	// deterministicMaxProbes is the maximum number of positions probed by the deterministic policy,
+	// starting from the one derived from the key, before giving up.
+	deterministicMaxProbes = 4096
+	// maxSlotsBits bounds the number of candidate positions considered by the deterministic policy, to avoid overflows.
+	maxSlotsBits = 62
+)
+
+// ParseAllocationPolicy parses the given allocation policy. The empty string corresponds to the first-fit policy.
+func ParseAllocationPolicy(policy string) (AllocationPolicy, error) {
+	if policy == "" {
+		return AllocationPolicyFirstFit, nil
+	}
+	if !slices.Contains(AllocationPolicies, AllocationPolicy(policy)) {
+		return "", fmt.Errorf("unknown allocation policy %q (supported: %v)", policy, AllocationPolicies)
+	}
+	return AllocationPolicy(policy), nil
+}
+
+// AcquireOptions configures how NetworkAcquireWithOptions chooses the network to allocate.
+type AcquireOptions struct {
+	// Policy is the allocation policy. The zero value corresponds to the first-fit policy.
+	Policy AllocationPolicy
+	// Key is the key the position of the network is derived from, when using the deterministic policy.
+	Key string
+	// Owner is the requester of the network. If it owns some reservations, the network is allocated within them,
+	// otherwise outside of the reservations of the other owners.
+	Owner string
+}
+
+// NetworkAcquireWithOptions allocates a network of the given size from the pools of the given address family,
+// skipping the draining ones, according to the given options.
+// It returns the allocated network or nil if no network is available.
+func (ipam *Ipam) NetworkAcquireWithOptions(size int, family Family, opts AcquireOptions) *netip.Prefix {
+	ranges, excluded, restricted := ipam.allocationRanges(size, family, opts.Owner)
+
+	var candidates []netip.Prefix
+	switch opts.Policy {
+	case AllocationPolicyDeterministic:
+		return ipam.networkAcquireDeterministic(size, ranges, excluded, opts.Key)
+	case AllocationPolicyBestFit:
+		candidates = ipam.freeRanges(size, ranges, excluded)
+		// Prefer the smallest blocks, preserving the address order otherwise.
+		slices.SortStableFunc(candidates, func(a, b netip.Prefix) int { return b.Bits() - a.Bits() })
+	default:
+		if !restricted {
+			return ipam.NetworkAcquire(size, family)
+		}
+		candidates = ipam.freeRanges(size, ranges, excluded)
+	}
+
+	for _, candidate := range candidates {
+		if result := ipam.NetworkAcquireWithPrefix(netip.PrefixFrom(candidate.Addr(), size)); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// allocationRanges returns the ranges a network of the given size can be allocated from by the given owner,
+// along with the ranges to be excluded. Restricted is true if the ranges are not the plain pools.
+func (ipam *Ipam) allocationRanges(size int, family Family, owner string) (ranges, excluded []netip.Prefix, restricted bool) {
+	var pools, owned []netip.Prefix
+	for i := range ipam.roots {
+		pool := ipam.roots[i].prefix
+		if FamilyOf(pool) != family || size > pool.Addr().BitLen() || size < pool.Bits() || ipam.IsPoolDraining(pool) {
+			continue
+		}
+		pools = append(pools, pool)
+
+		for _, reservation := range ipam.reservationsIn(pool) {
+			if owner != "" && reservation.Owner == owner {
+				if size >= reservation.Prefix.Bits() {
+					owned = append(owned, reservation.Prefix)
+				}
+				continue
+			}
+			excluded = append(excluded, reservation.Prefix)
+		}
+	}
+
+	if slices.ContainsFunc(ipam.reservations, func(r Reservation) bool { return owner != "" && r.Owner == owner }) {
+		// Reservations of different owners never overlap, hence there is nothing to exclude.
+		return owned, nil, true
+	}
+	return pools, excluded, len(excluded) > 0
+}
+
+// freeRanges returns the free blocks within the given ranges (and outside of the excluded ones) able to
+// contain a network of the given size, sorted by range and address.
+func (ipam *Ipam) freeRanges(size int, ranges, excluded []netip.Prefix) []netip.Prefix {
+	var free []netip.Prefix
+	for _, r := range ranges {
+		root := ipam.rootOf(r)
+		if root == nil {
+			continue
+		}
+		for _, block := range freeBlocks(root) {
+			block, ok := intersectPrefixes(block, r)
+			if !ok {
+				continue
+			}
+			for _, p := range subtractPrefixes(block, excluded) {
+				if p.Bits() <= size {
+					free = append(free, p)
+				}
+			}
+		}
+	}
+	return free
+}
+
+// networkAcquireDeterministic allocates the network at the position derived from the given key among all the
+// networks of the given size within the ranges, probing the following positions if it is not available.
+func (ipam *Ipam) networkAcquireDeterministic(size int, ranges, excluded []netip.Prefix, key string) *netip.Prefix {
+	counts := make([]uint64, len(ranges))
+	var total uint64
+	for i := range ranges {
+		counts[i] = uint64(1) << min(size-ranges[i].Bits(), maxSlotsBits)
+		total = min(total+counts[i], uint64(1)<<maxSlotsBits)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	start := hash.Sum64() % total
+
+	for probe := range min(total, deterministicMaxProbes) {
+		candidate := nthSlot(ranges, counts, (start+probe)%total, size)
+		if slices.ContainsFunc(excluded, candidate.Overlaps) {
+			continue
+		}
+		if result := ipam.NetworkAcquireWithPrefix(candidate); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// rootOf returns the root containing the given prefix, or nil if the prefix is not contained in any root.
+func (ipam *Ipam) rootOf(prefix netip.Prefix) *node {
+	for i := range ipam.roots {
+		if isPrefixChildOf(ipam.roots[i].prefix, prefix) {
+			return &ipam.roots[i]
+		}
+	}
+	return nil
+}
+
+// nthSlot returns the n-th network of the given size, enumerating the ranges in order.
+func nthSlot(ranges []netip.Prefix, counts []uint64, n uint64, size int) netip.Prefix {
+	for i := range ranges {
+		if n < counts[i] {
+			return nthSubnet(ranges[i], size, n)
+		}
+		n -= counts[i]
+	}
+	return netip.Prefix{}
+}
+
+// nthSubnet returns the n-th subnet of the given size of the prefix.
+func nthSubnet(prefix netip.Prefix, size int, n uint64) netip.Prefix {
+	bitLen := prefix.Addr().BitLen()
+	offset := new(big.Int).Lsh(new(big.Int).SetUint64(n), uint(bitLen-size))
+	sum := new(big.Int).Add(new(big.Int).SetBytes(prefix.Addr().AsSlice()), offset)
+
+	bin := make([]byte, bitLen/8)
+	sum.FillBytes(bin)
+	addr, _ := netip.AddrFromSlice(bin)
+	return netip.PrefixFrom(addr, size)
+}
+
+// intersectPrefixes returns the intersection of the given prefixes, which is either one of them or empty.
+func intersectPrefixes(a, b netip.Prefix) (netip.Prefix, bool) {
+	switch {
+	case isPrefixChildOf(b, a):
+		return a, true
+	case isPrefixChildOf(a, b):
+		return b, true
+	default:
+		return netip.Prefix{}, false
+	}
+}
+
+// subtractPrefixes returns the maximal subnets of the given prefix not overlapping with any of the excluded ones.
+func subtractPrefixes(prefix netip.Prefix, excluded []netip.Prefix) []netip.Prefix {
+	for _, e := range excluded {
+		if !e.Overlaps(prefix) {
+			continue
+		}
+		if isPrefixChildOf(e, prefix) {
+			return nil
+		}
+		left, right := splitNetworkPrefix(prefix)
+		return append(subtractPrefixes(left, excluded), subtractPrefixes(right, excluded)...)
+	}
+	return []netip.Prefix{prefix}
+}
@@ -0,0 +1,165 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam allocation policies and reservations", func() {
+	var (
+		ipam *Ipam
+		pool = netip.MustParsePrefix("10.0.0.0/16")
+	)
+
+	BeforeEach(func() {
+		var err error
+		ipam, err = NewIpam([]netip.Prefix{pool})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("Parsing policies", func() {
+		It("should default to first-fit", func() {
+			Expect(ParseAllocationPolicy("")).To(Equal(AllocationPolicyFirstFit))
+			Expect(ParseAllocationPolicy("BestFit")).To(Equal(AllocationPolicyBestFit))
+		})
+
+		It("should reject unknown policies", func() {
+			_, err := ParseAllocationPolicy("WorstFit")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("First-fit policy", func() {
+		It("should allocate the same network as the plain allocation", func() {
+			Expect(ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{})).
+				To(HaveValue(Equal(netip.MustParsePrefix("10.0.0.0/24"))))
+		})
+	})
+
+	Context("Best-fit policy", func() {
+		BeforeEach(func() {
+			// Leave a free /24 hole at the end of the first /17, and the whole second /17 free.
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.0.0/18"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.64.0/19"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.96.0/20"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.112.0/21"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.120.0/22"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.124.0/23"))).ToNot(BeNil())
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.126.0/24"))).ToNot(BeNil())
+		})
+
+		It("should allocate the network from the smallest free block", func() {
+			Expect(ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Policy: AllocationPolicyBestFit})).
+				To(HaveValue(Equal(netip.MustParsePrefix("10.0.127.0/24"))))
+			Expect(ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Policy: AllocationPolicyBestFit})).
+				To(HaveValue(Equal(netip.MustParsePrefix("10.0.128.0/24"))))
+		})
+
+		It("should return nil if no block is large enough", func() {
+			Expect(ipam.NetworkAcquireWithOptions(16, FamilyIPv4, AcquireOptions{Policy: AllocationPolicyBestFit})).To(BeNil())
+		})
+	})
+
+	Context("Deterministic policy", func() {
+		opts := AcquireOptions{Policy: AllocationPolicyDeterministic, Key: "cluster-1"}
+
+		It("should allocate the same network for the same key across instances", func() {
+			first := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, opts)
+			Expect(first).ToNot(BeNil())
+
+			other, err := NewIpam([]netip.Prefix{pool})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(other.NetworkAcquire(24, FamilyIPv4)).ToNot(BeNil())
+			Expect(other.NetworkAcquireWithOptions(24, FamilyIPv4, opts)).To(HaveValue(Equal(*first)))
+		})
+
+		It("should allocate different networks for different keys", func() {
+			first := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, opts)
+			second := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Policy: AllocationPolicyDeterministic, Key: "cluster-2"})
+			Expect(first).ToNot(BeNil())
+			Expect(second).ToNot(BeNil())
+			Expect(*second).ToNot(Equal(*first))
+		})
+
+		It("should probe the following positions if the derived one is not available", func() {
+			first := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, opts)
+			Expect(first).ToNot(BeNil())
+			second := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, opts)
+			Expect(second).ToNot(BeNil())
+			Expect(*second).ToNot(Equal(*first))
+		})
+	})
+
+	Context("Reservations", func() {
+		var reserved = netip.MustParsePrefix("10.0.0.0/20")
+
+		BeforeEach(func() {
+			Expect(ipam.Reserve(reserved, "cluster-1")).To(Succeed())
+		})
+
+		It("should validate the reservations", func() {
+			Expect(ipam.Reserve(reserved, "cluster-1")).To(Succeed())
+			Expect(ipam.Reserve(reserved, "cluster-2")).ToNot(Succeed())
+			Expect(ipam.Reserve(netip.MustParsePrefix("10.0.1.0/24"), "cluster-2")).ToNot(Succeed())
+			Expect(ipam.Reserve(netip.MustParsePrefix("10.1.0.0/24"), "cluster-2")).ToNot(Succeed())
+			Expect(ipam.Reserve(netip.MustParsePrefix("10.0.16.0/24"), "")).ToNot(Succeed())
+			Expect(ipam.ListReservations()).To(ConsistOf(Reservation{Prefix: reserved, Owner: "cluster-1"}))
+		})
+
+		It("should allocate the networks of the owner within its reservations", func() {
+			for _, policy := range AllocationPolicies {
+				result := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Policy: policy, Key: "key", Owner: "cluster-1"})
+				Expect(result).ToNot(BeNil())
+				Expect(reserved.Contains(result.Addr())).To(BeTrue(), "policy %s", policy)
+			}
+		})
+
+		It("should allocate the networks of the others outside of the reservations", func() {
+			for _, policy := range AllocationPolicies {
+				for _, owner := range []string{"", "cluster-2"} {
+					result := ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Policy: policy, Key: "key", Owner: owner})
+					Expect(result).ToNot(BeNil())
+					Expect(reserved.Overlaps(*result)).To(BeFalse(), "policy %s", policy)
+				}
+			}
+			Expect(ipam.IsReservedToOthers(netip.MustParsePrefix("10.0.1.0/24"), "cluster-2")).To(BeTrue())
+			Expect(ipam.IsReservedToOthers(netip.MustParsePrefix("10.0.1.0/24"), "cluster-1")).To(BeFalse())
+		})
+
+		It("should return nil once the reservations of the owner are exhausted", func() {
+			Expect(ipam.NetworkAcquireWithOptions(20, FamilyIPv4, AcquireOptions{Owner: "cluster-1"})).To(HaveValue(Equal(reserved)))
+			Expect(ipam.NetworkAcquireWithOptions(24, FamilyIPv4, AcquireOptions{Owner: "cluster-1"})).To(BeNil())
+		})
+
+		It("should prevent the removal of the pool until unreserved", func() {
+			Expect(ipam.RemovePool(pool)).ToNot(Succeed())
+			Expect(ipam.Unreserve(reserved)).To(HaveValue(Equal(Reservation{Prefix: reserved, Owner: "cluster-1"})))
+			Expect(ipam.Unreserve(reserved)).To(BeNil())
+			Expect(ipam.RemovePool(pool)).To(Succeed())
+		})
+
+		It("should persist the reservations in the snapshot", func() {
+			snapshot := ipam.Snapshot()
+			other, err := NewIpam([]netip.Prefix{pool})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(other.Restore(snapshot)).To(Succeed())
+			Expect(other.ListReservations()).To(Equal(ipam.ListReservations()))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// AddPool adds a new pool to the IPAM.
+// It returns an error if the prefix is invalid or overlaps with one of the existing pools.
+func (ipam *Ipam) AddPool(pool netip.Prefix) error {
+	if err := checkRoots([]netip.Prefix{pool}); err != nil {
+		return err
+	}
+	for i := range ipam.roots {
+		if ipam.roots[i].prefix.Overlaps(pool) {
+			return fmt.Errorf("pool %s overlaps with pool %s", pool, ipam.roots[i].prefix)
+		}
+	}
+
+	ipam.roots = append(ipam.roots, newNode(pool))
+	return nil
+}
+
+// RemovePool removes the given pool from the IPAM.
+// It returns an error if the pool does not exist, or some networks or reservations still belong to it.
+func (ipam *Ipam) RemovePool(pool netip.Prefix) error {
+	i := ipam.rootIndex(pool)
+	if i < 0 {
+		return fmt.Errorf("pool %s not found", pool)
+	}
+	if networks := listNetworks(&ipam.roots[i]); len(networks) > 0 {
+		return fmt.Errorf("pool %s still contains %d allocated networks", pool, len(networks))
+	}
+	if reservations := ipam.reservationsIn(pool); len(reservations) > 0 {
+		return fmt.Errorf("pool %s still contains %d reservations", pool, len(reservations))
+	}
+
+	ipam.roots = slices.Delete(ipam.roots, i, i+1)
+	delete(ipam.draining, pool)
+	return nil
+}
+
+// SetPoolDraining configures whether the given pool is draining. Draining pools are skipped when
+// allocating networks of a given size, while networks with a specific prefix can still be allocated
+// (e.g., to restore existing allocations). The draining state is not part of the IPAM snapshot.
+func (ipam *Ipam) SetPoolDraining(pool netip.Prefix, draining bool) error {
+	if ipam.rootIndex(pool) < 0 {
+		return fmt.Errorf("pool %s not found", pool)
+	}
+
+	if !draining {
+		delete(ipam.draining, pool)
+		return nil
+	}
+	if ipam.draining == nil {
+		ipam.draining = make(map[netip.Prefix]struct{})
+	}
+	ipam.draining[pool] = struct{}{}
+	return nil
+}
+
+// IsPoolDraining checks if the given pool is draining.
+func (ipam *Ipam) IsPoolDraining(pool netip.Prefix) bool {
+	_, draining := ipam.draining[pool]
+	return draining
+}
+
+func (ipam *Ipam) rootIndex(pool netip.Prefix) int {
+	for i := range ipam.roots {
+		if ipam.roots[i].prefix == pool {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,115 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam runtime pools", func() {
+	var (
+		ipam     *Ipam
+		static   = netip.MustParsePrefix("10.0.0.0/16")
+		dynamic  = netip.MustParsePrefix("192.168.0.0/16")
+		network  = netip.MustParsePrefix("192.168.1.0/24")
+		overlaps = netip.MustParsePrefix("10.0.0.0/8")
+	)
+
+	BeforeEach(func() {
+		var err error
+		ipam, err = NewIpam([]netip.Prefix{static})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("Adding pools", func() {
+		It("should allocate networks from the added pool", func() {
+			Expect(ipam.AddPool(dynamic)).To(Succeed())
+			Expect(ipam.Pools()).To(Equal([]netip.Prefix{static, dynamic}))
+			Expect(ipam.NetworkAcquireWithPrefix(network)).To(HaveValue(Equal(network)))
+		})
+
+		It("should reject invalid pools", func() {
+			Expect(ipam.AddPool(netip.MustParsePrefix("192.168.1.0/16"))).ToNot(Succeed())
+			Expect(ipam.AddPool(netip.Prefix{})).ToNot(Succeed())
+		})
+
+		It("should reject pools overlapping with the existing ones", func() {
+			Expect(ipam.AddPool(overlaps)).ToNot(Succeed())
+			Expect(ipam.AddPool(static)).ToNot(Succeed())
+			Expect(ipam.Pools()).To(Equal([]netip.Prefix{static}))
+		})
+	})
+
+	Context("Removing pools", func() {
+		BeforeEach(func() {
+			Expect(ipam.AddPool(dynamic)).To(Succeed())
+		})
+
+		It("should remove empty pools", func() {
+			Expect(ipam.RemovePool(dynamic)).To(Succeed())
+			Expect(ipam.Pools()).To(Equal([]netip.Prefix{static}))
+			Expect(ipam.IsPrefixInRoots(network)).To(BeFalse())
+		})
+
+		It("should not remove pools with allocated networks", func() {
+			Expect(ipam.NetworkAcquireWithPrefix(network)).ToNot(BeNil())
+			Expect(ipam.RemovePool(dynamic)).ToNot(Succeed())
+
+			Expect(ipam.NetworkRelease(network, 0)).ToNot(BeNil())
+			Expect(ipam.RemovePool(dynamic)).To(Succeed())
+		})
+
+		It("should return an error if the pool does not exist", func() {
+			Expect(ipam.RemovePool(overlaps)).ToNot(Succeed())
+		})
+	})
+
+	Context("Draining pools", func() {
+		BeforeEach(func() {
+			Expect(ipam.AddPool(dynamic)).To(Succeed())
+			Expect(ipam.SetPoolDraining(static, true)).To(Succeed())
+		})
+
+		It("should skip draining pools when allocating networks by size", func() {
+			Expect(ipam.IsPoolDraining(static)).To(BeTrue())
+			result := ipam.NetworkAcquire(24, FamilyIPv4)
+			Expect(result).ToNot(BeNil())
+			Expect(dynamic.Contains(result.Addr())).To(BeTrue())
+		})
+
+		It("should still allocate specific networks from draining pools", func() {
+			Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.1.0/24"))).ToNot(BeNil())
+		})
+
+		It("should allocate networks again once the pool is not draining anymore", func() {
+			Expect(ipam.SetPoolDraining(dynamic, true)).To(Succeed())
+			Expect(ipam.NetworkAcquire(24, FamilyIPv4)).To(BeNil())
+
+			Expect(ipam.SetPoolDraining(static, false)).To(Succeed())
+			Expect(ipam.IsPoolDraining(static)).To(BeFalse())
+			Expect(ipam.NetworkAcquire(24, FamilyIPv4)).ToNot(BeNil())
+		})
+
+		It("should forget the draining state of removed pools", func() {
+			Expect(ipam.SetPoolDraining(dynamic, true)).To(Succeed())
+			Expect(ipam.RemovePool(dynamic)).To(Succeed())
+			Expect(ipam.IsPoolDraining(dynamic)).To(BeFalse())
+			Expect(ipam.SetPoolDraining(dynamic, true)).ToNot(Succeed())
+		})
+	})
+})
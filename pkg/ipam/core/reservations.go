@@ -0,0 +1,97 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// Reservation is a range of a pool reserved to a given owner (e.g., a remote cluster).
+// Networks requested by the owner are allocated within its reservations, while the
+// ones requested by the others are allocated outside of them.
+type Reservation struct {
+	// Prefix is the reserved range.
+	Prefix netip.Prefix `json:"prefix"`
+	// Owner is the owner of the reservation.
+	Owner string `json:"owner"`
+}
+
+// Reserve reserves the given range to the given owner. Networks already allocated within the range are preserved.
+// It returns an error if the range is not contained in any pool or overlaps with another reservation.
+func (ipam *Ipam) Reserve(prefix netip.Prefix, owner string) error {
+	if err := checkRoots([]netip.Prefix{prefix}); err != nil {
+		return err
+	}
+	if owner == "" {
+		return fmt.Errorf("%s: reservation owner cannot be empty", prefix)
+	}
+	if !ipam.IsPrefixInRoots(prefix) {
+		return fmt.Errorf("%s: reservation not contained in roots", prefix)
+	}
+
+	for i := range ipam.reservations {
+		reservation := &ipam.reservations[i]
+		if reservation.Prefix == prefix && reservation.Owner == owner {
+			return nil
+		}
+		if reservation.Prefix.Overlaps(prefix) {
+			return fmt.Errorf("%s: reservation overlaps with %s (owner %s)", prefix, reservation.Prefix, reservation.Owner)
+		}
+	}
+
+	ipam.reservations = append(ipam.reservations, Reservation{Prefix: prefix, Owner: owner})
+	return nil
+}
+
+// Unreserve removes the reservation of the given range.
+// It returns the removed reservation or nil if the range is not reserved.
+func (ipam *Ipam) Unreserve(prefix netip.Prefix) *Reservation {
+	for i := range ipam.reservations {
+		if ipam.reservations[i].Prefix == prefix {
+			reservation := ipam.reservations[i]
+			ipam.reservations = slices.Delete(ipam.reservations, i, i+1)
+			return &reservation
+		}
+	}
+	return nil
+}
+
+// ListReservations returns the list of reservations.
+func (ipam *Ipam) ListReservations() []Reservation {
+	return slices.Clone(ipam.reservations)
+}
+
+// IsReservedToOthers checks if the given prefix overlaps with a range reserved to an owner other than the given one.
+func (ipam *Ipam) IsReservedToOthers(prefix netip.Prefix, owner string) bool {
+	for i := range ipam.reservations {
+		if ipam.reservations[i].Owner != owner && ipam.reservations[i].Prefix.Overlaps(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// reservationsIn returns the reservations contained in the given pool.
+func (ipam *Ipam) reservationsIn(pool netip.Prefix) []Reservation {
+	var reservations []Reservation
+	for i := range ipam.reservations {
+		if isPrefixChildOf(pool, ipam.reservations[i].Prefix) {
+			reservations = append(reservations, ipam.reservations[i])
+		}
+	}
+	return reservations
+}
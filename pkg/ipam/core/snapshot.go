@@ -0,0 +1,194 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// Snapshot is the serializable representation of the IPAM state.
+type Snapshot struct {
+	// Roots contains the trees of the IPAM pools.
+	Roots []NodeSnapshot `json:"roots"`
+	// Reservations contains the ranges of the pools reserved to specific owners.
+	Reservations []Reservation `json:"reservations,omitempty"`
+}
+
+// NodeSnapshot is the serializable representation of a node of the IPAM tree.
+type NodeSnapshot struct {
+	Prefix              netip.Prefix  `json:"prefix"`
+	Acquired            bool          `json:"acquired,omitempty"`
+	LastUpdateTimestamp time.Time     `json:"lastUpdateTimestamp"`
+	IPs                 []IPSnapshot  `json:"ips,omitempty"`
+	LastIP              netip.Addr    `json:"lastIP,omitzero"`
+	Owner               *Owner        `json:"owner,omitempty"`
+	Left                *NodeSnapshot `json:"left,omitempty"`
+	Right               *NodeSnapshot `json:"right,omitempty"`
+}
+
+// IPSnapshot is the serializable representation of an IP acquired by a node.
+type IPSnapshot struct {
+	Addr              netip.Addr `json:"addr"`
+	CreationTimestamp time.Time  `json:"creationTimestamp"`
+	Owner             *Owner     `json:"owner,omitempty"`
+}
+
+// Snapshot returns a deep copy of the IPAM state, including the grace period timestamps.
+func (ipam *Ipam) Snapshot() *Snapshot {
+	snapshot := &Snapshot{Roots: make([]NodeSnapshot, len(ipam.roots)), Reservations: ipam.ListReservations()}
+	for i := range ipam.roots {
+		snapshot.Roots[i] = *ipam.roots[i].toSnapshot()
+	}
+	return snapshot
+}
+
+// Restore replaces the IPAM state with the one contained in the given snapshot.
+// Roots of the snapshot which do not match any of the IPAM pools are ignored, while pools
+// not present in the snapshot are left untouched. Reservations are replaced with the ones
+// of the snapshot contained in the IPAM pools. The snapshot is validated before being
+// applied: if an error is returned, the IPAM state is not modified.
+func (ipam *Ipam) Restore(snapshot *Snapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("snapshot is nil")
+	}
+
+	restored := make(map[int]*node, len(snapshot.Roots))
+	for i := range snapshot.Roots {
+		root := &snapshot.Roots[i]
+		for j := range ipam.roots {
+			if ipam.roots[j].prefix != root.Prefix {
+				continue
+			}
+			if _, ok := restored[j]; ok {
+				return fmt.Errorf("snapshot contains pool %s multiple times", root.Prefix)
+			}
+			n, err := nodeFromSnapshot(root)
+			if err != nil {
+				return fmt.Errorf("invalid snapshot for pool %s: %w", root.Prefix, err)
+			}
+			restored[j] = n
+		}
+	}
+
+	var reservations []Reservation
+	for _, reservation := range snapshot.Reservations {
+		if !ipam.IsPrefixInRoots(reservation.Prefix) {
+			continue
+		}
+		if reservation.Owner == "" || slices.ContainsFunc(reservations, func(r Reservation) bool {
+			return r.Prefix.Overlaps(reservation.Prefix)
+		}) {
+			return fmt.Errorf("snapshot contains invalid reservation %s (owner %q)", reservation.Prefix, reservation.Owner)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	for i, n := range restored {
+		ipam.roots[i] = *n
+	}
+	ipam.reservations = reservations
+	return nil
+}
+
+func (n *node) toSnapshot() *NodeSnapshot {
+	snapshot := &NodeSnapshot{
+		Prefix:              n.prefix,
+		Acquired:            n.acquired,
+		LastUpdateTimestamp: n.lastUpdateTimestamp,
+		LastIP:              n.lastip,
+		Owner:               n.owner.clone(),
+	}
+	if len(n.ips) > 0 {
+		snapshot.IPs = make([]IPSnapshot, len(n.ips))
+		for i := range n.ips {
+			snapshot.IPs[i] = IPSnapshot{Addr: n.ips[i].addr, CreationTimestamp: n.ips[i].creationTimestamp, Owner: n.ips[i].owner.clone()}
+		}
+	}
+	if n.left != nil {
+		snapshot.Left = n.left.toSnapshot()
+	}
+	if n.right != nil {
+		snapshot.Right = n.right.toSnapshot()
+	}
+	return snapshot
+}
+
+func nodeFromSnapshot(snapshot *NodeSnapshot) (*node, error) {
+	if !snapshot.Prefix.IsValid() {
+		return nil, fmt.Errorf("invalid prefix")
+	}
+	if err := checkHostBitsZero(snapshot.Prefix); err != nil {
+		return nil, err
+	}
+	if (snapshot.Left == nil) != (snapshot.Right == nil) {
+		return nil, fmt.Errorf("%s: node must have either zero or two children", snapshot.Prefix)
+	}
+	if snapshot.Acquired && snapshot.Left != nil {
+		return nil, fmt.Errorf("%s: acquired node cannot be splitted", snapshot.Prefix)
+	}
+	if !snapshot.Acquired && len(snapshot.IPs) > 0 {
+		return nil, fmt.Errorf("%s: IPs can be acquired only from acquired networks", snapshot.Prefix)
+	}
+	if !snapshot.Acquired && snapshot.Owner != nil {
+		return nil, fmt.Errorf("%s: only acquired networks can have an owner", snapshot.Prefix)
+	}
+	if snapshot.LastIP.IsValid() && !snapshot.Prefix.Contains(snapshot.LastIP) {
+		return nil, fmt.Errorf("%s: last IP %s is not contained in the network", snapshot.Prefix, snapshot.LastIP)
+	}
+
+	n := &node{
+		prefix:              snapshot.Prefix,
+		acquired:            snapshot.Acquired,
+		lastUpdateTimestamp: snapshot.LastUpdateTimestamp,
+		lastip:              snapshot.LastIP,
+		owner:               snapshot.Owner.clone(),
+	}
+
+	for i := range snapshot.IPs {
+		addr := snapshot.IPs[i].Addr
+		if !snapshot.Prefix.Contains(addr) {
+			return nil, fmt.Errorf("%s: IP %s is not contained in the network", snapshot.Prefix, addr)
+		}
+		if n.isAllocatedIP(addr) {
+			return nil, fmt.Errorf("%s: IP %s is acquired multiple times", snapshot.Prefix, addr)
+		}
+		n.ips = append(n.ips, nodeIP{addr: addr, creationTimestamp: snapshot.IPs[i].CreationTimestamp, owner: snapshot.IPs[i].Owner.clone()})
+	}
+
+	if snapshot.Left == nil {
+		return n, nil
+	}
+
+	if snapshot.Prefix.Bits() >= snapshot.Prefix.Addr().BitLen() {
+		return nil, fmt.Errorf("%s: node cannot be splitted", snapshot.Prefix)
+	}
+	left, right := splitNetworkPrefix(snapshot.Prefix)
+	if snapshot.Left.Prefix != left || snapshot.Right.Prefix != right {
+		return nil, fmt.Errorf("%s: children %s and %s do not match the expected ones (%s and %s)",
+			snapshot.Prefix, snapshot.Left.Prefix, snapshot.Right.Prefix, left, right)
+	}
+
+	var err error
+	if n.left, err = nodeFromSnapshot(snapshot.Left); err != nil {
+		return nil, err
+	}
+	if n.right, err = nodeFromSnapshot(snapshot.Right); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"encoding/json"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam snapshots", func() {
+	var (
+		ipam  *Ipam
+		pools = []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("fd00::/8"),
+		}
+		network   = netip.MustParsePrefix("10.1.0.0/16")
+		network6  = netip.MustParsePrefix("fd00:1::/64")
+		timestamp = time.Now().Add(-time.Hour).Round(0)
+	)
+
+	BeforeEach(func() {
+		var err error
+		ipam, err = NewIpam(pools)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ipam.NetworkAcquireWithPrefix(network)).NotTo(BeNil())
+		Expect(ipam.NetworkAcquireWithPrefix(network6)).NotTo(BeNil())
+		Expect(ipam.NetworkAcquire(24, FamilyIPv4)).NotTo(BeNil())
+
+		addr, err := ipam.IPAcquire(network)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(addr).NotTo(BeNil())
+		Expect(ipam.IPSetCreationTimestamp(*addr, network, timestamp)).To(Succeed())
+		_, err = ipam.IPAcquireWithAddr(network6, netip.MustParseAddr("fd00:1::42"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ipam.NetworkSetLastUpdateTimestamp(network6, timestamp)).To(Succeed())
+	})
+
+	When("restoring a snapshot", func() {
+		It("should rebuild the same state", func() {
+			data, err := json.Marshal(ipam.Snapshot())
+			Expect(err).NotTo(HaveOccurred())
+
+			var snapshot Snapshot
+			Expect(json.Unmarshal(data, &snapshot)).To(Succeed())
+
+			restored, err := NewIpam(pools)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Restore(&snapshot)).To(Succeed())
+
+			Expect(restored.ListNetworks()).To(ConsistOf(ipam.ListNetworks()))
+			for _, prefix := range ipam.ListNetworks() {
+				expected, err := ipam.ListIPs(prefix)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(restored.ListIPs(prefix)).To(ConsistOf(expected))
+			}
+			Expect(json.Marshal(restored.Snapshot())).To(MatchJSON(data))
+		})
+
+		It("should preserve the grace period timestamps", func() {
+			restored, err := NewIpam(pools)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Restore(ipam.Snapshot())).To(Succeed())
+
+			node := search(network6, &restored.roots[1])
+			Expect(node).NotTo(BeNil())
+			Expect(node.lastUpdateTimestamp).To(BeTemporally("==", timestamp))
+
+			released, err := restored.IPRelease(network, network.Addr(), 30*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(released).NotTo(BeNil())
+		})
+
+		It("should ignore pools which are not configured", func() {
+			restored, err := NewIpam(pools[:1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Restore(ipam.Snapshot())).To(Succeed())
+			Expect(restored.ListNetworks()).To(HaveLen(2))
+			Expect(restored.IsPrefixInRoots(network6)).To(BeFalse())
+		})
+
+		It("should reject inconsistent snapshots without modifying the state", func() {
+			snapshot := ipam.Snapshot()
+			snapshot.Roots[0].Left.Prefix = netip.MustParsePrefix("10.0.0.0/10")
+
+			restored, err := NewIpam(pools)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.NetworkAcquireWithPrefix(network)).NotTo(BeNil())
+			Expect(restored.Restore(snapshot)).NotTo(Succeed())
+			Expect(restored.ListNetworks()).To(ConsistOf(network))
+
+			snapshot = ipam.Snapshot()
+			snapshot.Roots[1].IPs = []IPSnapshot{{Addr: netip.MustParseAddr("fd00::1")}}
+			Expect(restored.Restore(snapshot)).NotTo(Succeed())
+
+			Expect(restored.Restore(nil)).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"math"
+	"net/netip"
+)
+
+// PoolStats contains the usage statistics of an IPAM pool.
+type PoolStats struct {
+	// Pool is the prefix of the pool.
+	Pool netip.Prefix
+	// Networks is the number of networks allocated from the pool.
+	Networks int
+	// IPs is the number of IPs allocated from the networks of the pool.
+	IPs int
+	// Utilization is the fraction of the pool address space covered by allocated networks.
+	Utilization float64
+	// Fragmentation is one minus the ratio between the largest free block and the whole free address space.
+	// It is zero if the free address space is contiguous (or empty), and approaches one as it gets scattered.
+	Fragmentation float64
+	// FreeBlocks is the number of maximal free blocks the free address space is split into.
+	FreeBlocks int
+	// LargestFreeBlock is the largest free block, or the zero value if the pool is full.
+	LargestFreeBlock netip.Prefix
+}
+
+// Pools returns the prefixes of the IPAM pools.
+func (ipam *Ipam) Pools() []netip.Prefix {
+	pools := make([]netip.Prefix, len(ipam.roots))
+	for i := range ipam.roots {
+		pools[i] = ipam.roots[i].prefix
+	}
+	return pools
+}
+
+// PoolOf returns the pool containing the given prefix.
+// It returns false if the prefix is not contained in any pool.
+func (ipam *Ipam) PoolOf(prefix netip.Prefix) (netip.Prefix, bool) {
+	for i := range ipam.roots {
+		if isPrefixChildOf(ipam.roots[i].prefix, prefix) {
+			return ipam.roots[i].prefix, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// PoolStats returns the usage statistics of each IPAM pool.
+func (ipam *Ipam) PoolStats() []PoolStats {
+	stats := make([]PoolStats, len(ipam.roots))
+	for i := range ipam.roots {
+		stats[i] = ipam.roots[i].stats()
+	}
+	return stats
+}
+
+func (n *node) stats() PoolStats {
+	stats := PoolStats{Pool: n.prefix}
+
+	var allocated, free, largest float64
+	for _, network := range listNetworks(n) {
+		allocated += prefixShare(n.prefix, network)
+		stats.Networks++
+		if nd := search(network, n); nd != nil {
+			stats.IPs += len(nd.ips)
+		}
+	}
+
+	for _, block := range freeBlocks(n) {
+		share := prefixShare(n.prefix, block)
+		free += share
+		if share > largest {
+			largest = share
+			stats.LargestFreeBlock = block
+		}
+		stats.FreeBlocks++
+	}
+
+	stats.Utilization = allocated
+	if free > 0 {
+		stats.Fragmentation = 1 - largest/free
+	}
+	return stats
+}
+
+// freeBlocks returns the maximal subtrees of the given node which do not contain any acquired network.
+// Free siblings not yet merged (e.g., because of the grace period) are reported as a single block.
+func freeBlocks(node *node) []netip.Prefix {
+	if node.acquired {
+		return nil
+	}
+	if node.isLeaf() {
+		return []netip.Prefix{node.prefix}
+	}
+
+	left, right := freeBlocks(node.left), freeBlocks(node.right)
+	if len(left) == 1 && left[0] == node.left.prefix && len(right) == 1 && right[0] == node.right.prefix {
+		return []netip.Prefix{node.prefix}
+	}
+	return append(left, right...)
+}
+
+// prefixShare returns the fraction of the address space of the pool covered by the given prefix.
+func prefixShare(pool, prefix netip.Prefix) float64 {
+	return math.Ldexp(1, pool.Bits()-prefix.Bits())
+}
@@ -0,0 +1,115 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamcore
+
+import (
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ipam pool statistics", func() {
+	var (
+		ipam  *Ipam
+		pools = []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("fd00::/8"),
+		}
+	)
+
+	BeforeEach(func() {
+		var err error
+		ipam, err = NewIpam(pools)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return the pools", func() {
+		Expect(ipam.Pools()).To(Equal(pools))
+	})
+
+	It("should return the pool containing a prefix", func() {
+		pool, ok := ipam.PoolOf(netip.MustParsePrefix("10.1.0.0/16"))
+		Expect(ok).To(BeTrue())
+		Expect(pool).To(Equal(pools[0]))
+		pool, ok = ipam.PoolOf(netip.MustParsePrefix("fd00:1::/64"))
+		Expect(ok).To(BeTrue())
+		Expect(pool).To(Equal(pools[1]))
+		_, ok = ipam.PoolOf(netip.MustParsePrefix("192.168.0.0/16"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should report empty pools as fully free", func() {
+		stats := ipam.PoolStats()
+		Expect(stats).To(HaveLen(2))
+		Expect(stats[0]).To(Equal(PoolStats{Pool: pools[0], FreeBlocks: 1, LargestFreeBlock: pools[0]}))
+		Expect(stats[1]).To(Equal(PoolStats{Pool: pools[1], FreeBlocks: 1, LargestFreeBlock: pools[1]}))
+	})
+
+	It("should compute utilization and fragmentation", func() {
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.0.0/9"))).NotTo(BeNil())
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.128.0.0/10"))).NotTo(BeNil())
+		_, err := ipam.IPAcquire(netip.MustParsePrefix("10.0.0.0/9"))
+		Expect(err).NotTo(HaveOccurred())
+
+		stats := ipam.PoolStats()[0]
+		Expect(stats.Networks).To(Equal(2))
+		Expect(stats.IPs).To(Equal(1))
+		Expect(stats.Utilization).To(BeNumerically("~", 0.75))
+		Expect(stats.FreeBlocks).To(Equal(1))
+		Expect(stats.LargestFreeBlock).To(Equal(netip.MustParsePrefix("10.192.0.0/10")))
+		Expect(stats.Fragmentation).To(BeZero())
+
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.192.0.0/11"))).NotTo(BeNil())
+		stats = ipam.PoolStats()[0]
+		Expect(stats.Utilization).To(BeNumerically("~", 0.875))
+		Expect(stats.FreeBlocks).To(Equal(1))
+		Expect(stats.LargestFreeBlock).To(Equal(netip.MustParsePrefix("10.224.0.0/11")))
+		Expect(stats.Fragmentation).To(BeZero())
+
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.224.0.0/13"))).NotTo(BeNil())
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.240.0.0/13"))).NotTo(BeNil())
+		stats = ipam.PoolStats()[0]
+		Expect(stats.FreeBlocks).To(Equal(2))
+		Expect(stats.Fragmentation).To(BeNumerically("~", 0.5))
+
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.248.0.0/14"))).NotTo(BeNil())
+		stats = ipam.PoolStats()[0]
+		// Free space: 10.232.0.0/13 (1/32 of the pool) and 10.252.0.0/14 (1/64 of the pool).
+		Expect(stats.FreeBlocks).To(Equal(2))
+		Expect(stats.LargestFreeBlock).To(Equal(netip.MustParsePrefix("10.232.0.0/13")))
+		Expect(stats.Fragmentation).To(BeNumerically("~", 1.0/3))
+	})
+
+	It("should report contiguous free space as a single block", func() {
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.0.0.0/16"))).NotTo(BeNil())
+		Expect(ipam.NetworkRelease(netip.MustParsePrefix("10.0.0.0/16"), 0)).NotTo(BeNil())
+
+		stats := ipam.PoolStats()[0]
+		Expect(stats.Networks).To(BeZero())
+		Expect(stats.FreeBlocks).To(Equal(1))
+		Expect(stats.LargestFreeBlock).To(Equal(pools[0]))
+	})
+
+	It("should handle IPv6 pools", func() {
+		Expect(ipam.NetworkAcquireWithPrefix(netip.MustParsePrefix("fd00::/64"))).NotTo(BeNil())
+
+		stats := ipam.PoolStats()[1]
+		Expect(stats.Networks).To(Equal(1))
+		Expect(stats.Utilization).To(BeNumerically(">", 0))
+		Expect(stats.FreeBlocks).To(Equal(56))
+		Expect(stats.LargestFreeBlock).To(Equal(netip.MustParsePrefix("fd80::/9")))
+	})
+})
@@ -25,21 +25,65 @@ import (
 // +kubebuilder:rbac:groups=ipam.liqo.io,resources=networks,verbs=get;list;watch
 
 func (lipam *LiqoIPAM) initialize(ctx context.Context) error {
-	lipam.mutex.Lock()
-	defer lipam.mutex.Unlock()
 	klog.Info("Initializing IPAM")
+	if err := lipam.initializeState(ctx); err != nil {
+		return err
+	}
 
-	klog.Infof("IPAM pools: %v", lipam.opts.Pools)
+	// The initialized state is checkpointed synchronously, as the IPAM is not serving yet.
+	if err := lipam.checkpoint(ctx); err != nil {
+		klog.Errorf("Failed to checkpoint IPAM state: %v", err)
+	}
+
+	klog.Info("IPAM initialized")
+	return nil
+}
 
-	if err := lipam.initializeNetworks(ctx); err != nil {
+func (lipam *LiqoIPAM) initializeState(ctx context.Context) error {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	// Add the pools managed at runtime before restoring the state, so that their allocations are restored as well.
+	if err := lipam.initializePools(ctx); err != nil {
 		return err
 	}
 
-	if err := lipam.initializeIPs(ctx); err != nil {
-		return err
+	klog.Infof("IPAM pools: %v", lipam.IpamCore.Pools())
+
+	if lipam.replicated || lipam.restoreSnapshot(ctx) {
+		// The state has been replicated from the previous leader or restored from the snapshot: we only
+		// need to reconcile it with the resources which have been modified since the last update.
+		if lipam.replicated {
+			klog.Info("Reconciling the IPAM state replicated from the previous leader")
+			// The replicated state has never been checkpointed by this replica.
+			lipam.snapshotDirty = true
+		}
+
+		if err := lipam.syncReservations(ctx); err != nil {
+			return err
+		}
+
+		if err := lipam.syncNetworks(ctx); err != nil {
+			return err
+		}
+
+		if err := lipam.syncIPs(ctx); err != nil {
+			return err
+		}
+	} else {
+		if err := lipam.syncReservations(ctx); err != nil {
+			return err
+		}
+
+		if err := lipam.initializeNetworks(ctx); err != nil {
+			return err
+		}
+
+		if err := lipam.initializeIPs(ctx); err != nil {
+			return err
+		}
 	}
 
-	klog.Info("IPAM initialized")
 	return nil
 }
 
@@ -52,7 +96,7 @@ func (lipam *LiqoIPAM) initializeNetworks(ctx context.Context) error {
 
 	// Initialize the networks.
 	for net, netdetails := range nets {
-		if _, err := lipam.networkAcquireSpecific(net); err != nil {
+		if _, err := lipam.networkAcquireSpecific(net, netdetails.owner); err != nil {
 			return err
 		}
 
@@ -72,10 +116,10 @@ func (lipam *LiqoIPAM) initializeIPs(ctx context.Context) error {
 	}
 
 	// Initialize the IPs.
-	for ip, net := range ips {
-		if err := lipam.ipAcquireWithAddr(ip, net); err != nil {
+	for ip, details := range ips {
+		if err := lipam.ipAcquireWithAddr(ip, details.prefix, details.owner); err != nil {
 			klog.Errorf("Failed to reserve IP %q (network %q): %v",
-				ip.String(), net.String(), err)
+				ip.String(), details.prefix.String(), err)
 			klog.Errorf("The IP resource %q might be part of an old installation. "+
 				"Please delete all the IP resources and remove their finalizers if necessary.",
 				ip.String())
@@ -16,15 +16,19 @@ package ipam
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/netip"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	klog "k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
@@ -36,6 +40,17 @@ type LiqoIPAM struct {
 
 	IpamCore *ipamcore.Ipam
 	mutex    sync.Mutex
+	// staticPools are the pools configured at startup, which cannot be removed at runtime.
+	staticPools []netip.Prefix
+
+	snapshotStore SnapshotStore
+	snapshotDirty bool
+	// checkpointRequests notifies the checkpointer that the state has been modified.
+	checkpointRequests chan struct{}
+
+	watchers map[*watcher]struct{}
+	// replicated is true if the state has been replicated from the leader.
+	replicated bool
 
 	HealthServer *health.Server
 	client.Client
@@ -49,10 +64,36 @@ type ServerOptions struct {
 	SyncInterval    time.Duration
 	SyncGracePeriod time.Duration
 	GraphvizEnabled bool
+
+	// AllocationPolicy is the default policy used to remap the networks which cannot be allocated as requested.
+	AllocationPolicy string
+
+	// SnapshotConfigMapName is the name of the ConfigMap used to checkpoint the IPAM state.
+	SnapshotConfigMapName string
+	// SnapshotConfigMapNamespace is the namespace of the ConfigMap used to checkpoint the IPAM state.
+	SnapshotConfigMapNamespace string
+	// SnapshotFilePath is the path of the local file used to checkpoint the IPAM state.
+	// It takes precedence over the ConfigMap, if both are set.
+	SnapshotFilePath string
 }
 
-// New creates a new instance of the LiqoIPAM.
+// New creates a new instance of the LiqoIPAM, and starts it.
 func New(ctx context.Context, cl client.Client, opts *ServerOptions) (*LiqoIPAM, error) {
+	lipam, err := NewReplica(cl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lipam.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	return lipam, nil
+}
+
+// NewReplica creates a new instance of the LiqoIPAM, without starting it.
+// The replica can follow the state of the leader (see FollowUntilElected) until it is started.
+func NewReplica(cl client.Client, opts *ServerOptions) (*LiqoIPAM, error) {
 	hs := health.NewServer()
 	hs.SetServingStatus(IPAM_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
 
@@ -70,31 +111,47 @@ func New(ctx context.Context, cl client.Client, opts *ServerOptions) (*LiqoIPAM,
 		return nil, err
 	}
 
-	lipam := &LiqoIPAM{
-		IpamCore: ipam,
+	if _, err := ipamcore.ParseAllocationPolicy(opts.AllocationPolicy); err != nil {
+		return nil, err
+	}
+
+	return &LiqoIPAM{
+		IpamCore:    ipam,
+		staticPools: prefixRoots,
+
+		snapshotStore:      NewSnapshotStore(cl, opts),
+		checkpointRequests: make(chan struct{}, 1),
 
 		HealthServer: hs,
 		Client:       cl,
 		opts:         opts,
-	}
+	}, nil
+}
 
+// Start initializes the IPAM state, launches the sync and checkpoint routines and marks the IPAM as serving.
+func (lipam *LiqoIPAM) Start(ctx context.Context) error {
 	// Initialize the IPAM instance
 	if err := lipam.initialize(ctx); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Launch sync routine
-	go lipam.sync(ctx, opts.SyncInterval)
+	go lipam.sync(ctx, lipam.opts.SyncInterval)
 
-	hs.SetServingStatus(IPAM_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	// Launch checkpoint routine
+	go lipam.runCheckpointer(ctx)
 
-	return lipam, nil
+	lipam.HealthServer.SetServingStatus(IPAM_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	return nil
 }
 
 // IPAcquire acquires a free IP from a given CIDR.
+// If the request specifies an owner, and an IP of the CIDR has already been acquired for it, the same IP is returned.
 func (lipam *LiqoIPAM) IPAcquire(_ context.Context, req *IPAcquireRequest) (*IPAcquireResponse, error) {
 	lipam.mutex.Lock()
 	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
 
 	prefix, err := netip.ParsePrefix(req.GetCidr())
 	if err != nil {
@@ -102,10 +159,18 @@ func (lipam *LiqoIPAM) IPAcquire(_ context.Context, req *IPAcquireRequest) (*IPA
 	}
 
 	if !lipam.isInPool(prefix) {
-		return nil, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), strings.Join(lipam.opts.Pools, ","))
+		return nil, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), lipam.describePools(netip.Prefix{}))
+	}
+
+	owner := ownerFromMessage(req.GetOwner())
+	if owner != nil {
+		if addr := lipam.IpamCore.IPOwnedBy(prefix, *owner); addr != nil {
+			klog.Infof("IP %q (network %q) already acquired for %s", addr.String(), prefix.String(), owner)
+			return &IPAcquireResponse{Ip: addr.String()}, nil
+		}
 	}
 
-	remappedIP, err := lipam.ipAcquire(prefix)
+	remappedIP, err := lipam.ipAcquire(prefix, owner)
 	if err != nil {
 		return &IPAcquireResponse{}, err
 	}
@@ -117,6 +182,7 @@ func (lipam *LiqoIPAM) IPAcquire(_ context.Context, req *IPAcquireRequest) (*IPA
 func (lipam *LiqoIPAM) IPRelease(_ context.Context, req *IPReleaseRequest) (*IPReleaseResponse, error) {
 	lipam.mutex.Lock()
 	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
 
 	addr, err := netip.ParseAddr(req.GetIp())
 	if err != nil {
@@ -136,9 +202,11 @@ func (lipam *LiqoIPAM) IPRelease(_ context.Context, req *IPReleaseRequest) (*IPR
 }
 
 // NetworkAcquire acquires a network. If it is already reserved, it allocates and reserves a new free one with the same prefix length.
+// If the request specifies an owner, and a network has already been acquired for it, the same network is returned.
 func (lipam *LiqoIPAM) NetworkAcquire(_ context.Context, req *NetworkAcquireRequest) (*NetworkAcquireResponse, error) {
 	lipam.mutex.Lock()
 	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
 
 	var remappedCidr *netip.Prefix
 	var err error
@@ -149,16 +217,34 @@ func (lipam *LiqoIPAM) NetworkAcquire(_ context.Context, req *NetworkAcquireRequ
 	}
 
 	if !lipam.isInPool(prefix) {
-		return &NetworkAcquireResponse{}, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), strings.Join(lipam.opts.Pools, ","))
+		return &NetworkAcquireResponse{}, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), lipam.describePools(netip.Prefix{}))
+	}
+
+	owner := ownerFromMessage(req.GetOwner())
+	if owner != nil {
+		if network := lipam.IpamCore.NetworkOwnedBy(*owner); network != nil {
+			klog.Infof("Network %q already acquired for %s", network.String(), owner)
+			if err := lipam.acquirePreallocatedIPs(*network, req.GetPreAllocated()); err != nil {
+				return &NetworkAcquireResponse{}, err
+			}
+			return &NetworkAcquireResponse{Cidr: network.String()}, nil
+		}
 	}
 
 	if req.GetImmutable() {
-		remappedCidr, err = lipam.networkAcquireSpecific(prefix)
+		if pool, _ := lipam.IpamCore.PoolOf(prefix); lipam.IpamCore.IsPoolDraining(pool) {
+			return &NetworkAcquireResponse{}, fmt.Errorf("prefix %q belongs to the draining pool %q", req.GetCidr(), pool.String())
+		}
+		remappedCidr, err = lipam.networkAcquireSpecific(prefix, owner)
 		if err != nil {
 			return &NetworkAcquireResponse{}, err
 		}
 	} else {
-		remappedCidr, err = lipam.networkAcquire(prefix)
+		policy, err := lipam.allocationPolicy(req.GetAllocationPolicy())
+		if err != nil {
+			return &NetworkAcquireResponse{}, err
+		}
+		remappedCidr, err = lipam.networkAcquire(prefix, req.GetClusterID(), policy, owner)
 		if err != nil {
 			return &NetworkAcquireResponse{}, err
 		}
@@ -175,6 +261,7 @@ func (lipam *LiqoIPAM) NetworkAcquire(_ context.Context, req *NetworkAcquireRequ
 func (lipam *LiqoIPAM) NetworkRelease(_ context.Context, req *NetworkReleaseRequest) (*NetworkReleaseResponse, error) {
 	lipam.mutex.Lock()
 	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
 
 	prefix, err := netip.ParsePrefix(req.GetCidr())
 	if err != nil {
@@ -188,6 +275,40 @@ func (lipam *LiqoIPAM) NetworkRelease(_ context.Context, req *NetworkReleaseRequ
 	return &NetworkReleaseResponse{}, nil
 }
 
+// NetworkReserve reserves a range of the pools to a remote cluster: its remapped networks are allocated within the range,
+// while the ones of the other clusters are allocated outside of it.
+func (lipam *LiqoIPAM) NetworkReserve(_ context.Context, req *NetworkReserveRequest) (*NetworkReserveResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
+
+	prefix, err := netip.ParsePrefix(req.GetCidr())
+	if err != nil {
+		return &NetworkReserveResponse{}, fmt.Errorf("failed to parse prefix %q: %w", req.GetCidr(), err)
+	}
+
+	if err := lipam.networkReserve(prefix, req.GetClusterID()); err != nil {
+		return &NetworkReserveResponse{}, err
+	}
+
+	return &NetworkReserveResponse{}, nil
+}
+
+// NetworkUnreserve removes the reservation of a range of the pools.
+func (lipam *LiqoIPAM) NetworkUnreserve(_ context.Context, req *NetworkUnreserveRequest) (*NetworkUnreserveResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
+
+	prefix, err := netip.ParsePrefix(req.GetCidr())
+	if err != nil {
+		return &NetworkUnreserveResponse{}, fmt.Errorf("failed to parse prefix %q: %w", req.GetCidr(), err)
+	}
+
+	lipam.networkUnreserve(prefix)
+	return &NetworkUnreserveResponse{}, nil
+}
+
 // NetworkIsAvailable checks if a network is available.
 func (lipam *LiqoIPAM) NetworkIsAvailable(_ context.Context, req *NetworkAvailableRequest) (*NetworkAvailableResponse, error) {
 	lipam.mutex.Lock()
@@ -199,10 +320,243 @@ func (lipam *LiqoIPAM) NetworkIsAvailable(_ context.Context, req *NetworkAvailab
 	}
 
 	if !lipam.isInPool(prefix) {
-		return &NetworkAvailableResponse{}, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), strings.Join(lipam.opts.Pools, ","))
+		return &NetworkAvailableResponse{}, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), lipam.describePools(netip.Prefix{}))
 	}
 
 	available := lipam.networkIsAvailable(prefix)
 
 	return &NetworkAvailableResponse{Available: available}, nil
 }
+
+// ListNetworks lists the allocated networks, grouped by pool.
+func (lipam *LiqoIPAM) ListNetworks(_ context.Context, req *ListNetworksRequest) (*ListNetworksResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	filter, err := lipam.parsePoolFilter(req.GetPool())
+	if err != nil {
+		return &ListNetworksResponse{}, err
+	}
+
+	var pools []*PoolNetworks
+	byPool := make(map[netip.Prefix]*PoolNetworks)
+	for _, pool := range lipam.IpamCore.Pools() {
+		if !matchesPool(filter, pool) {
+			continue
+		}
+		pn := &PoolNetworks{Pool: pool.String()}
+		pools = append(pools, pn)
+		byPool[pool] = pn
+	}
+
+	for _, network := range lipam.IpamCore.ListNetworks() {
+		pool, _ := lipam.IpamCore.PoolOf(network)
+		if pn, ok := byPool[pool]; ok {
+			pn.Networks = append(pn.Networks, network.String())
+		}
+	}
+
+	return &ListNetworksResponse{Pools: pools}, nil
+}
+
+// ListIPs lists the allocated IPs, grouped by network.
+// If no network is specified, only the networks with at least one allocated IP are returned.
+func (lipam *LiqoIPAM) ListIPs(_ context.Context, req *ListIPsRequest) (*ListIPsResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	filter, err := lipam.parsePoolFilter(req.GetPool())
+	if err != nil {
+		return &ListIPsResponse{}, err
+	}
+
+	if req.GetCidr() != "" {
+		prefix, err := netip.ParsePrefix(req.GetCidr())
+		if err != nil {
+			return &ListIPsResponse{}, fmt.Errorf("failed to parse prefix %q: %w", req.GetCidr(), err)
+		}
+		pool, ok := lipam.IpamCore.PoolOf(prefix)
+		if !ok || !matchesPool(filter, pool) {
+			return &ListIPsResponse{}, fmt.Errorf("prefix %q is not in the pool %q", req.GetCidr(), lipam.describePools(filter))
+		}
+		nips, err := lipam.networkIPs(pool, prefix)
+		if err != nil {
+			return &ListIPsResponse{}, err
+		}
+		return &ListIPsResponse{Networks: []*NetworkIPs{nips}}, nil
+	}
+
+	var networks []*NetworkIPs
+	for _, network := range lipam.IpamCore.ListNetworks() {
+		pool, _ := lipam.IpamCore.PoolOf(network)
+		if !matchesPool(filter, pool) {
+			continue
+		}
+		nips, err := lipam.networkIPs(pool, network)
+		if err != nil {
+			return &ListIPsResponse{}, err
+		}
+		if len(nips.Ips) > 0 {
+			networks = append(networks, nips)
+		}
+	}
+
+	return &ListIPsResponse{Networks: networks}, nil
+}
+
+// PoolUsage returns the utilization and fragmentation statistics of the pools.
+func (lipam *LiqoIPAM) PoolUsage(_ context.Context, req *PoolUsageRequest) (*PoolUsageResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	filter, err := lipam.parsePoolFilter(req.GetPool())
+	if err != nil {
+		return &PoolUsageResponse{}, err
+	}
+
+	var pools []*PoolUsage
+	for _, stats := range lipam.IpamCore.PoolStats() {
+		if !matchesPool(filter, stats.Pool) {
+			continue
+		}
+		usage := &PoolUsage{
+			Pool:          stats.Pool.String(),
+			Networks:      uint32(stats.Networks),
+			Ips:           uint32(stats.IPs),
+			Utilization:   stats.Utilization,
+			Fragmentation: stats.Fragmentation,
+			FreeBlocks:    uint32(stats.FreeBlocks),
+		}
+		if stats.LargestFreeBlock.IsValid() {
+			usage.LargestFreeBlock = stats.LargestFreeBlock.String()
+		}
+		pools = append(pools, usage)
+	}
+
+	return &PoolUsageResponse{Pools: pools}, nil
+}
+
+// GetState returns the snapshot of the IPAM state, optionally restricted to a single pool.
+func (lipam *LiqoIPAM) GetState(_ context.Context, req *GetStateRequest) (*GetStateResponse, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	filter, err := lipam.parsePoolFilter(req.GetPool())
+	if err != nil {
+		return &GetStateResponse{}, err
+	}
+
+	snapshot := lipam.IpamCore.Snapshot()
+	if filter.IsValid() {
+		snapshot.Roots = slices.DeleteFunc(snapshot.Roots, func(root ipamcore.NodeSnapshot) bool {
+			return root.Prefix != filter
+		})
+		snapshot.Reservations = slices.DeleteFunc(snapshot.Reservations, func(reservation ipamcore.Reservation) bool {
+			return !filter.Overlaps(reservation.Prefix)
+		})
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return &GetStateResponse{}, fmt.Errorf("failed to encode the IPAM state: %w", err)
+	}
+	return &GetStateResponse{Snapshot: data, SyncGracePeriod: int64(lipam.opts.SyncGracePeriod)}, nil
+}
+
+// Watch streams the allocation events, optionally preceded by the current allocations.
+// The stream is closed with an error if the client does not keep up with the events.
+func (lipam *LiqoIPAM) Watch(req *WatchRequest, stream grpc.ServerStreamingServer[AllocationEvent]) error {
+	lipam.mutex.Lock()
+	filter, err := lipam.parsePoolFilter(req.GetPool())
+	if err != nil {
+		lipam.mutex.Unlock()
+		return err
+	}
+	var initial []*AllocationEvent
+	if req.GetSendInitialState() {
+		if initial, err = lipam.currentStateEvents(filter); err != nil {
+			lipam.mutex.Unlock()
+			return err
+		}
+	}
+	w := lipam.subscribe(filter)
+	lipam.mutex.Unlock()
+
+	defer lipam.unsubscribe(w)
+
+	for _, event := range initial {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-w.events:
+			if !ok {
+				return fmt.Errorf("watcher too slow, more than %d events pending", watchEventsBuffer)
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parsePoolFilter parses the optional pool a request is restricted to.
+// It returns the zero prefix if no pool is specified, and an error if the prefix is not one of the pools.
+func (lipam *LiqoIPAM) parsePoolFilter(pool string) (netip.Prefix, error) {
+	if pool == "" {
+		return netip.Prefix{}, nil
+	}
+
+	prefix, err := netip.ParsePrefix(pool)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("failed to parse pool %q: %w", pool, err)
+	}
+	if !slices.Contains(lipam.IpamCore.Pools(), prefix) {
+		return netip.Prefix{}, fmt.Errorf("prefix %q is not one of the pools %q", pool, lipam.describePools(netip.Prefix{}))
+	}
+	return prefix, nil
+}
+
+// describePools returns the pools a request is restricted to, for logging purposes.
+func (lipam *LiqoIPAM) describePools(filter netip.Prefix) string {
+	if filter.IsValid() {
+		return filter.String()
+	}
+	pools := lipam.IpamCore.Pools()
+	descriptions := make([]string, len(pools))
+	for i := range pools {
+		descriptions[i] = pools[i].String()
+	}
+	return strings.Join(descriptions, ",")
+}
+
+// allocationPolicy returns the requested allocation policy, or the default one if not specified.
+func (lipam *LiqoIPAM) allocationPolicy(policy string) (ipamcore.AllocationPolicy, error) {
+	if policy == "" {
+		policy = lipam.opts.AllocationPolicy
+	}
+	return ipamcore.ParseAllocationPolicy(policy)
+}
+
+// matchesPool checks whether the given pool satisfies the filter. The zero filter matches every pool.
+func matchesPool(filter, pool netip.Prefix) bool {
+	return !filter.IsValid() || filter == pool
+}
+
+func (lipam *LiqoIPAM) networkIPs(pool, network netip.Prefix) (*NetworkIPs, error) {
+	addrs, err := lipam.IpamCore.ListIPs(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IPs of network %q: %w", network.String(), err)
+	}
+
+	nips := &NetworkIPs{Pool: pool.String(), Cidr: network.String()}
+	for i := range addrs {
+		nips.Ips = append(nips.Ips, addrs[i].String())
+	}
+	return nips, nil
+}
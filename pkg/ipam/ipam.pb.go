@@ -21,6 +21,73 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AllocationEventType int32
+
+const (
+	AllocationEventType_ALLOCATION_EVENT_TYPE_UNSPECIFIED AllocationEventType = 0
+	AllocationEventType_NETWORK_ACQUIRED                  AllocationEventType = 1
+	AllocationEventType_NETWORK_RELEASED                  AllocationEventType = 2
+	AllocationEventType_IP_ACQUIRED                       AllocationEventType = 3
+	AllocationEventType_IP_RELEASED                       AllocationEventType = 4
+	AllocationEventType_POOL_ADDED                        AllocationEventType = 5 // The cidr is the added pool.
+	AllocationEventType_POOL_REMOVED                      AllocationEventType = 6 // The cidr is the removed pool.
+	AllocationEventType_RESERVATION_ADDED                 AllocationEventType = 7 // The cidr is the reserved range.
+	AllocationEventType_RESERVATION_REMOVED               AllocationEventType = 8 // The cidr is the range no longer reserved.
+)
+
+// Enum value maps for AllocationEventType.
+var (
+	AllocationEventType_name = map[int32]string{
+		0: "ALLOCATION_EVENT_TYPE_UNSPECIFIED",
+		1: "NETWORK_ACQUIRED",
+		2: "NETWORK_RELEASED",
+		3: "IP_ACQUIRED",
+		4: "IP_RELEASED",
+		5: "POOL_ADDED",
+		6: "POOL_REMOVED",
+		7: "RESERVATION_ADDED",
+		8: "RESERVATION_REMOVED",
+	}
+	AllocationEventType_value = map[string]int32{
+		"ALLOCATION_EVENT_TYPE_UNSPECIFIED": 0,
+		"NETWORK_ACQUIRED":                  1,
+		"NETWORK_RELEASED":                  2,
+		"IP_ACQUIRED":                       3,
+		"IP_RELEASED":                       4,
+		"POOL_ADDED":                        5,
+		"POOL_REMOVED":                      6,
+		"RESERVATION_ADDED":                 7,
+		"RESERVATION_REMOVED":               8,
+	}
+)
+
+func (x AllocationEventType) Enum() *AllocationEventType {
+	p := new(AllocationEventType)
+	*p = x
+	return p
+}
+
+func (x AllocationEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AllocationEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_ipam_ipam_proto_enumTypes[0].Descriptor()
+}
+
+func (AllocationEventType) Type() protoreflect.EnumType {
+	return &file_pkg_ipam_ipam_proto_enumTypes[0]
+}
+
+func (x AllocationEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AllocationEventType.Descriptor instead.
+func (AllocationEventType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{0}
+}
+
 type ResponseResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -57,17 +124,95 @@ func (*ResponseResult) Descriptor() ([]byte, []int) {
 	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{0}
 }
 
+type Owner struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiVersion string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	Kind       string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace  string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name       string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Uid        string `protobuf:"bytes,5,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *Owner) Reset() {
+	*x = Owner{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Owner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Owner) ProtoMessage() {}
+
+func (x *Owner) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Owner.ProtoReflect.Descriptor instead.
+func (*Owner) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Owner) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *Owner) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Owner) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Owner) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Owner) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
 type IPAcquireRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cidr string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Cidr  string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Owner *Owner `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"` // The object the IP is acquired for. If set, repeating the request returns the IP already acquired for the same owner.
 }
 
 func (x *IPAcquireRequest) Reset() {
 	*x = IPAcquireRequest{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[1]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -79,7 +224,7 @@ func (x *IPAcquireRequest) String() string {
 func (*IPAcquireRequest) ProtoMessage() {}
 
 func (x *IPAcquireRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[1]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -92,7 +237,7 @@ func (x *IPAcquireRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IPAcquireRequest.ProtoReflect.Descriptor instead.
 func (*IPAcquireRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{1}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *IPAcquireRequest) GetCidr() string {
@@ -102,6 +247,13 @@ func (x *IPAcquireRequest) GetCidr() string {
 	return ""
 }
 
+func (x *IPAcquireRequest) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
 type IPAcquireResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -113,7 +265,7 @@ type IPAcquireResponse struct {
 
 func (x *IPAcquireResponse) Reset() {
 	*x = IPAcquireResponse{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[2]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -125,7 +277,7 @@ func (x *IPAcquireResponse) String() string {
 func (*IPAcquireResponse) ProtoMessage() {}
 
 func (x *IPAcquireResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[2]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -138,7 +290,7 @@ func (x *IPAcquireResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IPAcquireResponse.ProtoReflect.Descriptor instead.
 func (*IPAcquireResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{2}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *IPAcquireResponse) GetIp() string {
@@ -166,7 +318,7 @@ type IPReleaseRequest struct {
 
 func (x *IPReleaseRequest) Reset() {
 	*x = IPReleaseRequest{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[3]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +330,7 @@ func (x *IPReleaseRequest) String() string {
 func (*IPReleaseRequest) ProtoMessage() {}
 
 func (x *IPReleaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[3]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +343,7 @@ func (x *IPReleaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IPReleaseRequest.ProtoReflect.Descriptor instead.
 func (*IPReleaseRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{3}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *IPReleaseRequest) GetIp() string {
@@ -218,7 +370,7 @@ type IPReleaseResponse struct {
 
 func (x *IPReleaseResponse) Reset() {
 	*x = IPReleaseResponse{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[4]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -230,7 +382,7 @@ func (x *IPReleaseResponse) String() string {
 func (*IPReleaseResponse) ProtoMessage() {}
 
 func (x *IPReleaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[4]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -243,7 +395,7 @@ func (x *IPReleaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IPReleaseResponse.ProtoReflect.Descriptor instead.
 func (*IPReleaseResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{4}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *IPReleaseResponse) GetResult() *ResponseResult {
@@ -258,14 +410,17 @@ type NetworkAcquireRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cidr         string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
-	Immutable    bool   `protobuf:"varint,2,opt,name=immutable,proto3" json:"immutable,omitempty"`       // If true, the network cannot be remapped. It will be allocated if available, or an error will be returned.
-	PreAllocated uint32 `protobuf:"varint,3,opt,name=preAllocated,proto3" json:"preAllocated,omitempty"` // The number of IPs to pre-allocate (reserve) in the CIDR, starting from the first IP of the CIDR.
+	Cidr             string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Immutable        bool   `protobuf:"varint,2,opt,name=immutable,proto3" json:"immutable,omitempty"`              // If true, the network cannot be remapped. It will be allocated if available, or an error will be returned.
+	PreAllocated     uint32 `protobuf:"varint,3,opt,name=preAllocated,proto3" json:"preAllocated,omitempty"`        // The number of IPs to pre-allocate (reserve) in the CIDR, starting from the first IP of the CIDR.
+	ClusterID        string `protobuf:"bytes,4,opt,name=clusterID,proto3" json:"clusterID,omitempty"`               // The remote cluster the network belongs to. Remapped networks are allocated within its reservations, if any.
+	AllocationPolicy string `protobuf:"bytes,5,opt,name=allocationPolicy,proto3" json:"allocationPolicy,omitempty"` // The policy used to remap the network (FirstFit, BestFit or Deterministic). If empty, the default one is used.
+	Owner            *Owner `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`                       // The object the network is acquired for. If set, repeating the request returns the network already acquired for the same owner.
 }
 
 func (x *NetworkAcquireRequest) Reset() {
 	*x = NetworkAcquireRequest{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[5]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -277,7 +432,7 @@ func (x *NetworkAcquireRequest) String() string {
 func (*NetworkAcquireRequest) ProtoMessage() {}
 
 func (x *NetworkAcquireRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[5]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,7 +445,7 @@ func (x *NetworkAcquireRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkAcquireRequest.ProtoReflect.Descriptor instead.
 func (*NetworkAcquireRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{5}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *NetworkAcquireRequest) GetCidr() string {
@@ -314,6 +469,27 @@ func (x *NetworkAcquireRequest) GetPreAllocated() uint32 {
 	return 0
 }
 
+func (x *NetworkAcquireRequest) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+func (x *NetworkAcquireRequest) GetAllocationPolicy() string {
+	if x != nil {
+		return x.AllocationPolicy
+	}
+	return ""
+}
+
+func (x *NetworkAcquireRequest) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
 type NetworkAcquireResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -325,7 +501,7 @@ type NetworkAcquireResponse struct {
 
 func (x *NetworkAcquireResponse) Reset() {
 	*x = NetworkAcquireResponse{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[6]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -337,7 +513,7 @@ func (x *NetworkAcquireResponse) String() string {
 func (*NetworkAcquireResponse) ProtoMessage() {}
 
 func (x *NetworkAcquireResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[6]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -350,7 +526,7 @@ func (x *NetworkAcquireResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkAcquireResponse.ProtoReflect.Descriptor instead.
 func (*NetworkAcquireResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{6}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *NetworkAcquireResponse) GetCidr() string {
@@ -377,7 +553,7 @@ type NetworkReleaseRequest struct {
 
 func (x *NetworkReleaseRequest) Reset() {
 	*x = NetworkReleaseRequest{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[7]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -389,7 +565,7 @@ func (x *NetworkReleaseRequest) String() string {
 func (*NetworkReleaseRequest) ProtoMessage() {}
 
 func (x *NetworkReleaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[7]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -402,7 +578,7 @@ func (x *NetworkReleaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkReleaseRequest.ProtoReflect.Descriptor instead.
 func (*NetworkReleaseRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{7}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *NetworkReleaseRequest) GetCidr() string {
@@ -422,7 +598,7 @@ type NetworkReleaseResponse struct {
 
 func (x *NetworkReleaseResponse) Reset() {
 	*x = NetworkReleaseResponse{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[8]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -434,7 +610,7 @@ func (x *NetworkReleaseResponse) String() string {
 func (*NetworkReleaseResponse) ProtoMessage() {}
 
 func (x *NetworkReleaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[8]
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -447,7 +623,7 @@ func (x *NetworkReleaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkReleaseResponse.ProtoReflect.Descriptor instead.
 func (*NetworkReleaseResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{8}
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *NetworkReleaseResponse) GetResult() *ResponseResult {
@@ -457,29 +633,30 @@ func (x *NetworkReleaseResponse) GetResult() *ResponseResult {
 	return nil
 }
 
-type NetworkAvailableRequest struct {
+type NetworkReserveRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cidr string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Cidr      string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	ClusterID string `protobuf:"bytes,2,opt,name=clusterID,proto3" json:"clusterID,omitempty"` // The remote cluster the CIDR is reserved to.
 }
 
-func (x *NetworkAvailableRequest) Reset() {
-	*x = NetworkAvailableRequest{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[9]
+func (x *NetworkReserveRequest) Reset() {
+	*x = NetworkReserveRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkAvailableRequest) String() string {
+func (x *NetworkReserveRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkAvailableRequest) ProtoMessage() {}
+func (*NetworkReserveRequest) ProtoMessage() {}
 
-func (x *NetworkAvailableRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[9]
+func (x *NetworkReserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -490,42 +667,48 @@ func (x *NetworkAvailableRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkAvailableRequest.ProtoReflect.Descriptor instead.
-func (*NetworkAvailableRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use NetworkReserveRequest.ProtoReflect.Descriptor instead.
+func (*NetworkReserveRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *NetworkAvailableRequest) GetCidr() string {
+func (x *NetworkReserveRequest) GetCidr() string {
 	if x != nil {
 		return x.Cidr
 	}
 	return ""
 }
 
-type NetworkAvailableResponse struct {
+func (x *NetworkReserveRequest) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+type NetworkReserveResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Available bool            `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
-	Result    *ResponseResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	Result *ResponseResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 }
 
-func (x *NetworkAvailableResponse) Reset() {
-	*x = NetworkAvailableResponse{}
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[10]
+func (x *NetworkReserveResponse) Reset() {
+	*x = NetworkReserveResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkAvailableResponse) String() string {
+func (x *NetworkReserveResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkAvailableResponse) ProtoMessage() {}
+func (*NetworkReserveResponse) ProtoMessage() {}
 
-func (x *NetworkAvailableResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_ipam_ipam_proto_msgTypes[10]
+func (x *NetworkReserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -536,74 +719,1248 @@ func (x *NetworkAvailableResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkAvailableResponse.ProtoReflect.Descriptor instead.
-func (*NetworkAvailableResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use NetworkReserveResponse.ProtoReflect.Descriptor instead.
+func (*NetworkReserveResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NetworkAvailableResponse) GetAvailable() bool {
+func (x *NetworkReserveResponse) GetResult() *ResponseResult {
 	if x != nil {
-		return x.Available
+		return x.Result
 	}
-	return false
+	return nil
 }
 
-func (x *NetworkAvailableResponse) GetResult() *ResponseResult {
+type NetworkUnreserveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cidr string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+}
+
+func (x *NetworkUnreserveRequest) Reset() {
+	*x = NetworkUnreserveRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkUnreserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkUnreserveRequest) ProtoMessage() {}
+
+func (x *NetworkUnreserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkUnreserveRequest.ProtoReflect.Descriptor instead.
+func (*NetworkUnreserveRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NetworkUnreserveRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+type NetworkUnreserveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *ResponseResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *NetworkUnreserveResponse) Reset() {
+	*x = NetworkUnreserveResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkUnreserveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkUnreserveResponse) ProtoMessage() {}
+
+func (x *NetworkUnreserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkUnreserveResponse.ProtoReflect.Descriptor instead.
+func (*NetworkUnreserveResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NetworkUnreserveResponse) GetResult() *ResponseResult {
 	if x != nil {
 		return x.Result
 	}
 	return nil
 }
 
-var File_pkg_ipam_ipam_proto protoreflect.FileDescriptor
+type NetworkAvailableRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_pkg_ipam_ipam_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x70, 0x6b, 0x67, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x26, 0x0a, 0x10, 0x49, 0x50, 0x41, 0x63, 0x71,
-	0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63,
-	0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x22,
-	0x4c, 0x0a, 0x11, 0x49, 0x50, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x70, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x36, 0x0a,
-	0x10, 0x49, 0x50, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x70, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x63, 0x69, 0x64, 0x72, 0x22, 0x3c, 0x0a, 0x11, 0x49, 0x50, 0x52, 0x65, 0x6c, 0x65, 0x61,
-	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x22, 0x6d, 0x0a, 0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x63,
+	Cidr string `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+}
+
+func (x *NetworkAvailableRequest) Reset() {
+	*x = NetworkAvailableRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAvailableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAvailableRequest) ProtoMessage() {}
+
+func (x *NetworkAvailableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAvailableRequest.ProtoReflect.Descriptor instead.
+func (*NetworkAvailableRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *NetworkAvailableRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+type NetworkAvailableResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Available bool            `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	Result    *ResponseResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *NetworkAvailableResponse) Reset() {
+	*x = NetworkAvailableResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAvailableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAvailableResponse) ProtoMessage() {}
+
+func (x *NetworkAvailableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAvailableResponse.ProtoReflect.Descriptor instead.
+func (*NetworkAvailableResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NetworkAvailableResponse) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *NetworkAvailableResponse) GetResult() *ResponseResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ListNetworksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool string `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"` // If set, only the networks allocated from the given pool are returned.
+}
+
+func (x *ListNetworksRequest) Reset() {
+	*x = ListNetworksRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksRequest) ProtoMessage() {}
+
+func (x *ListNetworksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksRequest.ProtoReflect.Descriptor instead.
+func (*ListNetworksRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListNetworksRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type ListNetworksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pools  []*PoolNetworks `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
+	Result *ResponseResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *ListNetworksResponse) Reset() {
+	*x = ListNetworksResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksResponse) ProtoMessage() {}
+
+func (x *ListNetworksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksResponse.ProtoReflect.Descriptor instead.
+func (*ListNetworksResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListNetworksResponse) GetPools() []*PoolNetworks {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+func (x *ListNetworksResponse) GetResult() *ResponseResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type PoolNetworks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool     string   `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Networks []string `protobuf:"bytes,2,rep,name=networks,proto3" json:"networks,omitempty"`
+}
+
+func (x *PoolNetworks) Reset() {
+	*x = PoolNetworks{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolNetworks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolNetworks) ProtoMessage() {}
+
+func (x *PoolNetworks) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolNetworks.ProtoReflect.Descriptor instead.
+func (*PoolNetworks) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PoolNetworks) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *PoolNetworks) GetNetworks() []string {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+type ListIPsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool string `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"` // If set, only the IPs allocated from the networks of the given pool are returned.
+	Cidr string `protobuf:"bytes,2,opt,name=cidr,proto3" json:"cidr,omitempty"` // If set, only the IPs allocated from the given network are returned.
+}
+
+func (x *ListIPsRequest) Reset() {
+	*x = ListIPsRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIPsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIPsRequest) ProtoMessage() {}
+
+func (x *ListIPsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIPsRequest.ProtoReflect.Descriptor instead.
+func (*ListIPsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListIPsRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ListIPsRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+type ListIPsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Networks []*NetworkIPs   `protobuf:"bytes,1,rep,name=networks,proto3" json:"networks,omitempty"`
+	Result   *ResponseResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *ListIPsResponse) Reset() {
+	*x = ListIPsResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIPsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIPsResponse) ProtoMessage() {}
+
+func (x *ListIPsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIPsResponse.ProtoReflect.Descriptor instead.
+func (*ListIPsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListIPsResponse) GetNetworks() []*NetworkIPs {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *ListIPsResponse) GetResult() *ResponseResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type NetworkIPs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool string   `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Cidr string   `protobuf:"bytes,2,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Ips  []string `protobuf:"bytes,3,rep,name=ips,proto3" json:"ips,omitempty"`
+}
+
+func (x *NetworkIPs) Reset() {
+	*x = NetworkIPs{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkIPs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkIPs) ProtoMessage() {}
+
+func (x *NetworkIPs) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkIPs.ProtoReflect.Descriptor instead.
+func (*NetworkIPs) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NetworkIPs) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *NetworkIPs) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *NetworkIPs) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+type PoolUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool string `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"` // If set, only the usage of the given pool is returned.
+}
+
+func (x *PoolUsageRequest) Reset() {
+	*x = PoolUsageRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolUsageRequest) ProtoMessage() {}
+
+func (x *PoolUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolUsageRequest.ProtoReflect.Descriptor instead.
+func (*PoolUsageRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PoolUsageRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type PoolUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pools  []*PoolUsage    `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
+	Result *ResponseResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *PoolUsageResponse) Reset() {
+	*x = PoolUsageResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolUsageResponse) ProtoMessage() {}
+
+func (x *PoolUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolUsageResponse.ProtoReflect.Descriptor instead.
+func (*PoolUsageResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PoolUsageResponse) GetPools() []*PoolUsage {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+func (x *PoolUsageResponse) GetResult() *ResponseResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type PoolUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool             string  `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Networks         uint32  `protobuf:"varint,2,opt,name=networks,proto3" json:"networks,omitempty"`                // The number of allocated networks.
+	Ips              uint32  `protobuf:"varint,3,opt,name=ips,proto3" json:"ips,omitempty"`                          // The number of IPs allocated from the networks of the pool.
+	Utilization      float64 `protobuf:"fixed64,4,opt,name=utilization,proto3" json:"utilization,omitempty"`         // The fraction of the pool address space covered by allocated networks, between 0 and 1.
+	Fragmentation    float64 `protobuf:"fixed64,5,opt,name=fragmentation,proto3" json:"fragmentation,omitempty"`     // One minus the ratio between the largest free block and the free address space, between 0 and 1.
+	FreeBlocks       uint32  `protobuf:"varint,6,opt,name=freeBlocks,proto3" json:"freeBlocks,omitempty"`            // The number of free blocks the free address space is split into.
+	LargestFreeBlock string  `protobuf:"bytes,7,opt,name=largestFreeBlock,proto3" json:"largestFreeBlock,omitempty"` // The largest free block, empty if the pool is full.
+}
+
+func (x *PoolUsage) Reset() {
+	*x = PoolUsage{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolUsage) ProtoMessage() {}
+
+func (x *PoolUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolUsage.ProtoReflect.Descriptor instead.
+func (*PoolUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PoolUsage) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *PoolUsage) GetNetworks() uint32 {
+	if x != nil {
+		return x.Networks
+	}
+	return 0
+}
+
+func (x *PoolUsage) GetIps() uint32 {
+	if x != nil {
+		return x.Ips
+	}
+	return 0
+}
+
+func (x *PoolUsage) GetUtilization() float64 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *PoolUsage) GetFragmentation() float64 {
+	if x != nil {
+		return x.Fragmentation
+	}
+	return 0
+}
+
+func (x *PoolUsage) GetFreeBlocks() uint32 {
+	if x != nil {
+		return x.FreeBlocks
+	}
+	return 0
+}
+
+func (x *PoolUsage) GetLargestFreeBlock() string {
+	if x != nil {
+		return x.LargestFreeBlock
+	}
+	return ""
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool string `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"` // If set, only the state of the given pool is returned.
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetStateRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type GetStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshot        []byte          `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                // The JSON-encoded snapshot of the IPAM state, including owners and timestamps.
+	SyncGracePeriod int64           `protobuf:"varint,2,opt,name=syncGracePeriod,proto3" json:"syncGracePeriod,omitempty"` // The grace period before an allocation can be released by the sync routine, in nanoseconds.
+	Result          *ResponseResult `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *GetStateResponse) Reset() {
+	*x = GetStateResponse{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateResponse) ProtoMessage() {}
+
+func (x *GetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetStateResponse) GetSnapshot() []byte {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *GetStateResponse) GetSyncGracePeriod() int64 {
+	if x != nil {
+		return x.SyncGracePeriod
+	}
+	return 0
+}
+
+func (x *GetStateResponse) GetResult() *ResponseResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool             string `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`                          // If set, only the events concerning the given pool are sent.
+	SendInitialState bool   `protobuf:"varint,2,opt,name=sendInitialState,proto3" json:"sendInitialState,omitempty"` // If true, the currently allocated networks and IPs are sent as acquisition events before any other event.
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetSendInitialState() bool {
+	if x != nil {
+		return x.SendInitialState
+	}
+	return false
+}
+
+type AllocationEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      AllocationEventType `protobuf:"varint,1,opt,name=type,proto3,enum=AllocationEventType" json:"type,omitempty"`
+	Pool      string              `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
+	Cidr      string              `protobuf:"bytes,3,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Ip        string              `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`                // Set only for IP events.
+	Timestamp int64               `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix time of the event, in nanoseconds.
+	ClusterID string              `protobuf:"bytes,6,opt,name=clusterID,proto3" json:"clusterID,omitempty"`  // Set only for reservation events.
+	Owner     *Owner              `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`          // Set only for acquisition events performed on behalf of an owner.
+}
+
+func (x *AllocationEvent) Reset() {
+	*x = AllocationEvent{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationEvent) ProtoMessage() {}
+
+func (x *AllocationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationEvent.ProtoReflect.Descriptor instead.
+func (*AllocationEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AllocationEvent) GetType() AllocationEventType {
+	if x != nil {
+		return x.Type
+	}
+	return AllocationEventType_ALLOCATION_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *AllocationEvent) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *AllocationEvent) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *AllocationEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AllocationEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AllocationEvent) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+func (x *AllocationEvent) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type ReplicateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replica string `protobuf:"bytes,1,opt,name=replica,proto3" json:"replica,omitempty"` // The name of the follower replica, for logging purposes.
+}
+
+func (x *ReplicateRequest) Reset() {
+	*x = ReplicateRequest{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicateRequest) ProtoMessage() {}
+
+func (x *ReplicateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicateRequest.ProtoReflect.Descriptor instead.
+func (*ReplicateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReplicateRequest) GetReplica() string {
+	if x != nil {
+		return x.Replica
+	}
+	return ""
+}
+
+type ReplicationMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshot []byte           `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // The JSON-encoded snapshot of the IPAM state. Set only in the first message of the stream.
+	Event    *AllocationEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`       // The mutation to apply to the replicated state. Set in all the following messages.
+}
+
+func (x *ReplicationMessage) Reset() {
+	*x = ReplicationMessage{}
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicationMessage) ProtoMessage() {}
+
+func (x *ReplicationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ipam_ipam_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicationMessage.ProtoReflect.Descriptor instead.
+func (*ReplicationMessage) Descriptor() ([]byte, []int) {
+	return file_pkg_ipam_ipam_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReplicationMessage) GetSnapshot() []byte {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *ReplicationMessage) GetEvent() *AllocationEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+var File_pkg_ipam_ipam_proto protoreflect.FileDescriptor
+
+var file_pkg_ipam_ipam_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x6b, 0x67, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x7f, 0x0a, 0x05, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x44, 0x0a, 0x10, 0x49, 0x50, 0x41, 0x63,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72,
+	0x12, 0x1c, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x06, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x22, 0x4c,
+	0x0a, 0x11, 0x49, 0x50, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x70, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x36, 0x0a, 0x10,
+	0x49, 0x50, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x69, 0x64, 0x72, 0x22, 0x3c, 0x0a, 0x11, 0x49, 0x50, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x22, 0xd5, 0x01, 0x0a, 0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x63,
 	0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
 	0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72,
 	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6d, 0x6d, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x6d, 0x6d, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x22,
 	0x0a, 0x0c, 0x70, 0x72, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03,
 	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
-	0x65, 0x64, 0x22, 0x55, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x63, 0x71,
-	0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72,
-	0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x2b, 0x0a, 0x15, 0x4e, 0x65, 0x74,
-	0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x22, 0x41, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x2d, 0x0a, 0x17, 0x4e, 0x65, 0x74,
-	0x77, 0x6f, 0x72, 0x6b, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x22, 0x61, 0x0a, 0x18, 0x4e, 0x65, 0x74, 0x77,
-	0x6f, 0x72, 0x6b, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62,
-	0x6c, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x32, 0xbf, 0x02, 0x0a, 0x04,
+	0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x2a, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x4f, 0x77,
+	0x6e, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x22, 0x55, 0x0a, 0x16, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x22, 0x2b, 0x0a, 0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69,
+	0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x22, 0x41,
+	0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x22, 0x49, 0x0a, 0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69,
+	0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x22, 0x41, 0x0a, 0x16,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
+	0x2d, 0x0a, 0x17, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x55, 0x6e, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69,
+	0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x22, 0x43,
+	0x0a, 0x18, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x55, 0x6e, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0x2d, 0x0a, 0x17, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69,
+	0x64, 0x72, 0x22, 0x61, 0x0a, 0x18, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x29, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c,
+	0x22, 0x64, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x70, 0x6f, 0x6f, 0x6c,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x27, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x3e, 0x0a, 0x0c, 0x50, 0x6f, 0x6f, 0x6c, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x22, 0x38, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x50,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72,
+	0x22, 0x63, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x50, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49,
+	0x50, 0x73, 0x52, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x46, 0x0a, 0x0a, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x49, 0x50, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x69,
+	0x70, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x70, 0x73, 0x22, 0x26, 0x0a,
+	0x10, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x22, 0x5e, 0x0a, 0x11, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x05, 0x70, 0x6f,
+	0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x50, 0x6f, 0x6f, 0x6c,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x27, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xe1, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x69, 0x70, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x75, 0x74, 0x69, 0x6c,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x66, 0x72, 0x61, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d,
+	0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x66, 0x72, 0x65, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x2a, 0x0a,
+	0x10, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x73, 0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x73, 0x74,
+	0x46, 0x72, 0x65, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x25, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c,
+	0x22, 0x81, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x79, 0x6e, 0x63, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x79, 0x6e, 0x63,
+	0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x27, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x22, 0x4e, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x73, 0x65, 0x6e, 0x64,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x10, 0x73, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x05, 0x6f,
+	0x77, 0x6e, 0x65, 0x72, 0x22, 0x2c, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c,
+	0x69, 0x63, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x22, 0x58, 0x0a, 0x12, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2a, 0xdc, 0x01, 0x0a,
+	0x13, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x25, 0x0a, 0x21, 0x41, 0x4c, 0x4c, 0x4f, 0x43, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4e,
+	0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x41, 0x43, 0x51, 0x55, 0x49, 0x52, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x14, 0x0a, 0x10, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x52, 0x45, 0x4c,
+	0x45, 0x41, 0x53, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x50, 0x5f, 0x41, 0x43,
+	0x51, 0x55, 0x49, 0x52, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x50, 0x5f, 0x52,
+	0x45, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x44, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x4f, 0x4f,
+	0x4c, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4f, 0x4f,
+	0x4c, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x06, 0x12, 0x15, 0x0a, 0x11, 0x52,
+	0x45, 0x53, 0x45, 0x52, 0x56, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44,
+	0x10, 0x07, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x08, 0x32, 0xfe, 0x05, 0x0a, 0x04,
 	0x49, 0x50, 0x41, 0x4d, 0x12, 0x32, 0x0a, 0x09, 0x49, 0x50, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72,
 	0x65, 0x12, 0x11, 0x2e, 0x49, 0x50, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x49, 0x50, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65,
@@ -623,8 +1980,36 @@ var file_pkg_ipam_ipam_proto_rawDesc = []byte{
 	0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x18, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
 	0x72, 0x6b, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x76, 0x61, 0x69,
-	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x08, 0x5a,
-	0x06, 0x2e, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a,
+	0x0e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x12,
+	0x16, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x47, 0x0a, 0x10, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x55, 0x6e, 0x72, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x12, 0x18, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x55, 0x6e,
+	0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x55, 0x6e, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
+	0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x14, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x15, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x50,
+	0x73, 0x12, 0x0f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x50, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x50, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x11, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x0d, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x30, 0x01, 0x12, 0x2f, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x10, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x12, 0x11, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x42, 0x08, 0x5a, 0x06,
+	0x2e, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -639,41 +2024,93 @@ func file_pkg_ipam_ipam_proto_rawDescGZIP() []byte {
 	return file_pkg_ipam_ipam_proto_rawDescData
 }
 
-var file_pkg_ipam_ipam_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_pkg_ipam_ipam_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_ipam_ipam_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_pkg_ipam_ipam_proto_goTypes = []any{
-	(*ResponseResult)(nil),           // 0: ResponseResult
-	(*IPAcquireRequest)(nil),         // 1: IPAcquireRequest
-	(*IPAcquireResponse)(nil),        // 2: IPAcquireResponse
-	(*IPReleaseRequest)(nil),         // 3: IPReleaseRequest
-	(*IPReleaseResponse)(nil),        // 4: IPReleaseResponse
-	(*NetworkAcquireRequest)(nil),    // 5: NetworkAcquireRequest
-	(*NetworkAcquireResponse)(nil),   // 6: NetworkAcquireResponse
-	(*NetworkReleaseRequest)(nil),    // 7: NetworkReleaseRequest
-	(*NetworkReleaseResponse)(nil),   // 8: NetworkReleaseResponse
-	(*NetworkAvailableRequest)(nil),  // 9: NetworkAvailableRequest
-	(*NetworkAvailableResponse)(nil), // 10: NetworkAvailableResponse
+	(AllocationEventType)(0),         // 0: AllocationEventType
+	(*ResponseResult)(nil),           // 1: ResponseResult
+	(*Owner)(nil),                    // 2: Owner
+	(*IPAcquireRequest)(nil),         // 3: IPAcquireRequest
+	(*IPAcquireResponse)(nil),        // 4: IPAcquireResponse
+	(*IPReleaseRequest)(nil),         // 5: IPReleaseRequest
+	(*IPReleaseResponse)(nil),        // 6: IPReleaseResponse
+	(*NetworkAcquireRequest)(nil),    // 7: NetworkAcquireRequest
+	(*NetworkAcquireResponse)(nil),   // 8: NetworkAcquireResponse
+	(*NetworkReleaseRequest)(nil),    // 9: NetworkReleaseRequest
+	(*NetworkReleaseResponse)(nil),   // 10: NetworkReleaseResponse
+	(*NetworkReserveRequest)(nil),    // 11: NetworkReserveRequest
+	(*NetworkReserveResponse)(nil),   // 12: NetworkReserveResponse
+	(*NetworkUnreserveRequest)(nil),  // 13: NetworkUnreserveRequest
+	(*NetworkUnreserveResponse)(nil), // 14: NetworkUnreserveResponse
+	(*NetworkAvailableRequest)(nil),  // 15: NetworkAvailableRequest
+	(*NetworkAvailableResponse)(nil), // 16: NetworkAvailableResponse
+	(*ListNetworksRequest)(nil),      // 17: ListNetworksRequest
+	(*ListNetworksResponse)(nil),     // 18: ListNetworksResponse
+	(*PoolNetworks)(nil),             // 19: PoolNetworks
+	(*ListIPsRequest)(nil),           // 20: ListIPsRequest
+	(*ListIPsResponse)(nil),          // 21: ListIPsResponse
+	(*NetworkIPs)(nil),               // 22: NetworkIPs
+	(*PoolUsageRequest)(nil),         // 23: PoolUsageRequest
+	(*PoolUsageResponse)(nil),        // 24: PoolUsageResponse
+	(*PoolUsage)(nil),                // 25: PoolUsage
+	(*GetStateRequest)(nil),          // 26: GetStateRequest
+	(*GetStateResponse)(nil),         // 27: GetStateResponse
+	(*WatchRequest)(nil),             // 28: WatchRequest
+	(*AllocationEvent)(nil),          // 29: AllocationEvent
+	(*ReplicateRequest)(nil),         // 30: ReplicateRequest
+	(*ReplicationMessage)(nil),       // 31: ReplicationMessage
 }
 var file_pkg_ipam_ipam_proto_depIdxs = []int32{
-	0,  // 0: IPAcquireResponse.result:type_name -> ResponseResult
-	0,  // 1: IPReleaseResponse.result:type_name -> ResponseResult
-	0,  // 2: NetworkAcquireResponse.result:type_name -> ResponseResult
-	0,  // 3: NetworkReleaseResponse.result:type_name -> ResponseResult
-	0,  // 4: NetworkAvailableResponse.result:type_name -> ResponseResult
-	1,  // 5: IPAM.IPAcquire:input_type -> IPAcquireRequest
-	3,  // 6: IPAM.IPRelease:input_type -> IPReleaseRequest
-	5,  // 7: IPAM.NetworkAcquire:input_type -> NetworkAcquireRequest
-	7,  // 8: IPAM.NetworkRelease:input_type -> NetworkReleaseRequest
-	9,  // 9: IPAM.NetworkIsAvailable:input_type -> NetworkAvailableRequest
-	2,  // 10: IPAM.IPAcquire:output_type -> IPAcquireResponse
-	4,  // 11: IPAM.IPRelease:output_type -> IPReleaseResponse
-	6,  // 12: IPAM.NetworkAcquire:output_type -> NetworkAcquireResponse
-	8,  // 13: IPAM.NetworkRelease:output_type -> NetworkReleaseResponse
-	10, // 14: IPAM.NetworkIsAvailable:output_type -> NetworkAvailableResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	2,  // 0: IPAcquireRequest.owner:type_name -> Owner
+	1,  // 1: IPAcquireResponse.result:type_name -> ResponseResult
+	1,  // 2: IPReleaseResponse.result:type_name -> ResponseResult
+	2,  // 3: NetworkAcquireRequest.owner:type_name -> Owner
+	1,  // 4: NetworkAcquireResponse.result:type_name -> ResponseResult
+	1,  // 5: NetworkReleaseResponse.result:type_name -> ResponseResult
+	1,  // 6: NetworkReserveResponse.result:type_name -> ResponseResult
+	1,  // 7: NetworkUnreserveResponse.result:type_name -> ResponseResult
+	1,  // 8: NetworkAvailableResponse.result:type_name -> ResponseResult
+	19, // 9: ListNetworksResponse.pools:type_name -> PoolNetworks
+	1,  // 10: ListNetworksResponse.result:type_name -> ResponseResult
+	22, // 11: ListIPsResponse.networks:type_name -> NetworkIPs
+	1,  // 12: ListIPsResponse.result:type_name -> ResponseResult
+	25, // 13: PoolUsageResponse.pools:type_name -> PoolUsage
+	1,  // 14: PoolUsageResponse.result:type_name -> ResponseResult
+	1,  // 15: GetStateResponse.result:type_name -> ResponseResult
+	0,  // 16: AllocationEvent.type:type_name -> AllocationEventType
+	2,  // 17: AllocationEvent.owner:type_name -> Owner
+	29, // 18: ReplicationMessage.event:type_name -> AllocationEvent
+	3,  // 19: IPAM.IPAcquire:input_type -> IPAcquireRequest
+	5,  // 20: IPAM.IPRelease:input_type -> IPReleaseRequest
+	7,  // 21: IPAM.NetworkAcquire:input_type -> NetworkAcquireRequest
+	9,  // 22: IPAM.NetworkRelease:input_type -> NetworkReleaseRequest
+	15, // 23: IPAM.NetworkIsAvailable:input_type -> NetworkAvailableRequest
+	11, // 24: IPAM.NetworkReserve:input_type -> NetworkReserveRequest
+	13, // 25: IPAM.NetworkUnreserve:input_type -> NetworkUnreserveRequest
+	17, // 26: IPAM.ListNetworks:input_type -> ListNetworksRequest
+	20, // 27: IPAM.ListIPs:input_type -> ListIPsRequest
+	23, // 28: IPAM.PoolUsage:input_type -> PoolUsageRequest
+	28, // 29: IPAM.Watch:input_type -> WatchRequest
+	26, // 30: IPAM.GetState:input_type -> GetStateRequest
+	30, // 31: IPAM.Replicate:input_type -> ReplicateRequest
+	4,  // 32: IPAM.IPAcquire:output_type -> IPAcquireResponse
+	6,  // 33: IPAM.IPRelease:output_type -> IPReleaseResponse
+	8,  // 34: IPAM.NetworkAcquire:output_type -> NetworkAcquireResponse
+	10, // 35: IPAM.NetworkRelease:output_type -> NetworkReleaseResponse
+	16, // 36: IPAM.NetworkIsAvailable:output_type -> NetworkAvailableResponse
+	12, // 37: IPAM.NetworkReserve:output_type -> NetworkReserveResponse
+	14, // 38: IPAM.NetworkUnreserve:output_type -> NetworkUnreserveResponse
+	18, // 39: IPAM.ListNetworks:output_type -> ListNetworksResponse
+	21, // 40: IPAM.ListIPs:output_type -> ListIPsResponse
+	24, // 41: IPAM.PoolUsage:output_type -> PoolUsageResponse
+	29, // 42: IPAM.Watch:output_type -> AllocationEvent
+	27, // 43: IPAM.GetState:output_type -> GetStateResponse
+	31, // 44: IPAM.Replicate:output_type -> ReplicationMessage
+	32, // [32:45] is the sub-list for method output_type
+	19, // [19:32] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_pkg_ipam_ipam_proto_init() }
@@ -686,13 +2123,14 @@ func file_pkg_ipam_ipam_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_ipam_ipam_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_ipam_ipam_proto_goTypes,
 		DependencyIndexes: file_pkg_ipam_ipam_proto_depIdxs,
+		EnumInfos:         file_pkg_ipam_ipam_proto_enumTypes,
 		MessageInfos:      file_pkg_ipam_ipam_proto_msgTypes,
 	}.Build()
 	File_pkg_ipam_ipam_proto = out.File
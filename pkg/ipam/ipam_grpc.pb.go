@@ -25,6 +25,14 @@ const (
 	IPAM_NetworkAcquire_FullMethodName     = "/IPAM/NetworkAcquire"
 	IPAM_NetworkRelease_FullMethodName     = "/IPAM/NetworkRelease"
 	IPAM_NetworkIsAvailable_FullMethodName = "/IPAM/NetworkIsAvailable"
+	IPAM_NetworkReserve_FullMethodName     = "/IPAM/NetworkReserve"
+	IPAM_NetworkUnreserve_FullMethodName   = "/IPAM/NetworkUnreserve"
+	IPAM_ListNetworks_FullMethodName       = "/IPAM/ListNetworks"
+	IPAM_ListIPs_FullMethodName            = "/IPAM/ListIPs"
+	IPAM_PoolUsage_FullMethodName          = "/IPAM/PoolUsage"
+	IPAM_Watch_FullMethodName              = "/IPAM/Watch"
+	IPAM_GetState_FullMethodName           = "/IPAM/GetState"
+	IPAM_Replicate_FullMethodName          = "/IPAM/Replicate"
 )
 
 // IPAMClient is the client API for IPAM service.
@@ -36,6 +44,14 @@ type IPAMClient interface {
 	NetworkAcquire(ctx context.Context, in *NetworkAcquireRequest, opts ...grpc.CallOption) (*NetworkAcquireResponse, error)
 	NetworkRelease(ctx context.Context, in *NetworkReleaseRequest, opts ...grpc.CallOption) (*NetworkReleaseResponse, error)
 	NetworkIsAvailable(ctx context.Context, in *NetworkAvailableRequest, opts ...grpc.CallOption) (*NetworkAvailableResponse, error)
+	NetworkReserve(ctx context.Context, in *NetworkReserveRequest, opts ...grpc.CallOption) (*NetworkReserveResponse, error)
+	NetworkUnreserve(ctx context.Context, in *NetworkUnreserveRequest, opts ...grpc.CallOption) (*NetworkUnreserveResponse, error)
+	ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error)
+	ListIPs(ctx context.Context, in *ListIPsRequest, opts ...grpc.CallOption) (*ListIPsResponse, error)
+	PoolUsage(ctx context.Context, in *PoolUsageRequest, opts ...grpc.CallOption) (*PoolUsageResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AllocationEvent], error)
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error)
+	Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplicationMessage], error)
 }
 
 type iPAMClient struct {
@@ -96,6 +112,104 @@ func (c *iPAMClient) NetworkIsAvailable(ctx context.Context, in *NetworkAvailabl
 	return out, nil
 }
 
+func (c *iPAMClient) NetworkReserve(ctx context.Context, in *NetworkReserveRequest, opts ...grpc.CallOption) (*NetworkReserveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkReserveResponse)
+	err := c.cc.Invoke(ctx, IPAM_NetworkReserve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) NetworkUnreserve(ctx context.Context, in *NetworkUnreserveRequest, opts ...grpc.CallOption) (*NetworkUnreserveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkUnreserveResponse)
+	err := c.cc.Invoke(ctx, IPAM_NetworkUnreserve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNetworksResponse)
+	err := c.cc.Invoke(ctx, IPAM_ListNetworks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) ListIPs(ctx context.Context, in *ListIPsRequest, opts ...grpc.CallOption) (*ListIPsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIPsResponse)
+	err := c.cc.Invoke(ctx, IPAM_ListIPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) PoolUsage(ctx context.Context, in *PoolUsageRequest, opts ...grpc.CallOption) (*PoolUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PoolUsageResponse)
+	err := c.cc.Invoke(ctx, IPAM_PoolUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AllocationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IPAM_ServiceDesc.Streams[0], IPAM_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, AllocationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IPAM_WatchClient = grpc.ServerStreamingClient[AllocationEvent]
+
+func (c *iPAMClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStateResponse)
+	err := c.cc.Invoke(ctx, IPAM_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMClient) Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplicationMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IPAM_ServiceDesc.Streams[1], IPAM_Replicate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReplicateRequest, ReplicationMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IPAM_ReplicateClient = grpc.ServerStreamingClient[ReplicationMessage]
+
 // IPAMServer is the server API for IPAM service.
 // All implementations must embed UnimplementedIPAMServer
 // for forward compatibility.
@@ -105,6 +219,14 @@ type IPAMServer interface {
 	NetworkAcquire(context.Context, *NetworkAcquireRequest) (*NetworkAcquireResponse, error)
 	NetworkRelease(context.Context, *NetworkReleaseRequest) (*NetworkReleaseResponse, error)
 	NetworkIsAvailable(context.Context, *NetworkAvailableRequest) (*NetworkAvailableResponse, error)
+	NetworkReserve(context.Context, *NetworkReserveRequest) (*NetworkReserveResponse, error)
+	NetworkUnreserve(context.Context, *NetworkUnreserveRequest) (*NetworkUnreserveResponse, error)
+	ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error)
+	ListIPs(context.Context, *ListIPsRequest) (*ListIPsResponse, error)
+	PoolUsage(context.Context, *PoolUsageRequest) (*PoolUsageResponse, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[AllocationEvent]) error
+	GetState(context.Context, *GetStateRequest) (*GetStateResponse, error)
+	Replicate(*ReplicateRequest, grpc.ServerStreamingServer[ReplicationMessage]) error
 	mustEmbedUnimplementedIPAMServer()
 }
 
@@ -130,6 +252,30 @@ func (UnimplementedIPAMServer) NetworkRelease(context.Context, *NetworkReleaseRe
 func (UnimplementedIPAMServer) NetworkIsAvailable(context.Context, *NetworkAvailableRequest) (*NetworkAvailableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NetworkIsAvailable not implemented")
 }
+func (UnimplementedIPAMServer) NetworkReserve(context.Context, *NetworkReserveRequest) (*NetworkReserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkReserve not implemented")
+}
+func (UnimplementedIPAMServer) NetworkUnreserve(context.Context, *NetworkUnreserveRequest) (*NetworkUnreserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkUnreserve not implemented")
+}
+func (UnimplementedIPAMServer) ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNetworks not implemented")
+}
+func (UnimplementedIPAMServer) ListIPs(context.Context, *ListIPsRequest) (*ListIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIPs not implemented")
+}
+func (UnimplementedIPAMServer) PoolUsage(context.Context, *PoolUsageRequest) (*PoolUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PoolUsage not implemented")
+}
+func (UnimplementedIPAMServer) Watch(*WatchRequest, grpc.ServerStreamingServer[AllocationEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedIPAMServer) GetState(context.Context, *GetStateRequest) (*GetStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedIPAMServer) Replicate(*ReplicateRequest, grpc.ServerStreamingServer[ReplicationMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Replicate not implemented")
+}
 func (UnimplementedIPAMServer) mustEmbedUnimplementedIPAMServer() {}
 func (UnimplementedIPAMServer) testEmbeddedByValue()              {}
 
@@ -241,6 +387,136 @@ func _IPAM_NetworkIsAvailable_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IPAM_NetworkReserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkReserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).NetworkReserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_NetworkReserve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).NetworkReserve(ctx, req.(*NetworkReserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_NetworkUnreserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkUnreserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).NetworkUnreserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_NetworkUnreserve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).NetworkUnreserve(ctx, req.(*NetworkUnreserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNetworksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).ListNetworks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_ListNetworks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_ListIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).ListIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_ListIPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).ListIPs(ctx, req.(*ListIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_PoolUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).PoolUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_PoolUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).PoolUsage(ctx, req.(*PoolUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPAMServer).Watch(m, &grpc.GenericServerStream[WatchRequest, AllocationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IPAM_WatchServer = grpc.ServerStreamingServer[AllocationEvent]
+
+func _IPAM_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IPAM_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAM_Replicate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReplicateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPAMServer).Replicate(m, &grpc.GenericServerStream[ReplicateRequest, ReplicationMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IPAM_ReplicateServer = grpc.ServerStreamingServer[ReplicationMessage]
+
 // IPAM_ServiceDesc is the grpc.ServiceDesc for IPAM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,7 +544,42 @@ var IPAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NetworkIsAvailable",
 			Handler:    _IPAM_NetworkIsAvailable_Handler,
 		},
+		{
+			MethodName: "NetworkReserve",
+			Handler:    _IPAM_NetworkReserve_Handler,
+		},
+		{
+			MethodName: "NetworkUnreserve",
+			Handler:    _IPAM_NetworkUnreserve_Handler,
+		},
+		{
+			MethodName: "ListNetworks",
+			Handler:    _IPAM_ListNetworks_Handler,
+		},
+		{
+			MethodName: "ListIPs",
+			Handler:    _IPAM_ListIPs_Handler,
+		},
+		{
+			MethodName: "PoolUsage",
+			Handler:    _IPAM_PoolUsage_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _IPAM_GetState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _IPAM_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Replicate",
+			Handler:       _IPAM_Replicate_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/ipam/ipam.proto",
 }
@@ -16,6 +16,7 @@ package ipam
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/netip"
@@ -31,6 +32,7 @@ import (
 
 	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
 	grpcutils "github.com/liqotech/liqo/pkg/utils/grpc"
 	"github.com/liqotech/liqo/pkg/utils/testutil"
 )
@@ -698,4 +700,197 @@ var _ = Describe("IPAM integration tests", func() {
 			})
 		})
 	})
+
+	Describe("Listing networks and IPs", func() {
+		BeforeEach(func() {
+			_, err := ipamClient.NetworkAcquire(ctx, &NetworkAcquireRequest{
+				Cidr:         "10.20.0.0/16",
+				Immutable:    true,
+				PreAllocated: 2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = ipamClient.NetworkAcquire(ctx, &NetworkAcquireRequest{
+				Cidr:      "192.168.1.0/24",
+				Immutable: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should list the networks of every pool", func() {
+			res, err := ipamClient.ListNetworks(ctx, &ListNetworksRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pools).To(HaveLen(3))
+			Expect(res.Pools[0].Pool).To(Equal("10.0.0.0/8"))
+			Expect(res.Pools[0].Networks).To(ConsistOf(
+				testutil.PodCIDR, testutil.ServiceCIDR, testutil.ExternalCIDR, testutil.InternalCIDR, "10.20.0.0/16"))
+			Expect(res.Pools[1].Pool).To(Equal("192.168.0.0/16"))
+			Expect(res.Pools[1].Networks).To(ConsistOf("192.168.1.0/24"))
+			Expect(res.Pools[2].Pool).To(Equal("172.16.0.0/12"))
+			Expect(res.Pools[2].Networks).To(BeEmpty())
+		})
+
+		It("should list the networks of a given pool", func() {
+			res, err := ipamClient.ListNetworks(ctx, &ListNetworksRequest{Pool: "192.168.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pools).To(HaveLen(1))
+			Expect(res.Pools[0].Networks).To(ConsistOf("192.168.1.0/24"))
+		})
+
+		It("should fail listing the networks of an unknown pool", func() {
+			_, err := ipamClient.ListNetworks(ctx, &ListNetworksRequest{Pool: "192.168.0.0/24"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should list the IPs of every network", func() {
+			res, err := ipamClient.ListIPs(ctx, &ListIPsRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Networks).To(HaveLen(1))
+			Expect(res.Networks[0].Pool).To(Equal("10.0.0.0/8"))
+			Expect(res.Networks[0].Cidr).To(Equal("10.20.0.0/16"))
+			Expect(res.Networks[0].Ips).To(ConsistOf("10.20.0.0", "10.20.0.1"))
+
+			res, err = ipamClient.ListIPs(ctx, &ListIPsRequest{Pool: "192.168.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Networks).To(BeEmpty())
+		})
+
+		It("should list the IPs of a given network", func() {
+			res, err := ipamClient.ListIPs(ctx, &ListIPsRequest{Cidr: "192.168.1.0/24"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Networks).To(HaveLen(1))
+			Expect(res.Networks[0].Cidr).To(Equal("192.168.1.0/24"))
+			Expect(res.Networks[0].Ips).To(BeEmpty())
+
+			_, err = ipamClient.ListIPs(ctx, &ListIPsRequest{Pool: "10.0.0.0/8", Cidr: "192.168.1.0/24"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return the usage of the pools", func() {
+			res, err := ipamClient.PoolUsage(ctx, &PoolUsageRequest{Pool: "192.168.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pools).To(HaveLen(1))
+			Expect(res.Pools[0].Pool).To(Equal("192.168.0.0/16"))
+			Expect(res.Pools[0].Networks).To(BeEquivalentTo(1))
+			Expect(res.Pools[0].Ips).To(BeZero())
+			Expect(res.Pools[0].Utilization).To(BeNumerically("~", 1.0/256))
+			Expect(res.Pools[0].FreeBlocks).To(BeEquivalentTo(8))
+			Expect(res.Pools[0].LargestFreeBlock).To(Equal("192.168.128.0/17"))
+			Expect(res.Pools[0].Fragmentation).To(BeNumerically("~", 1-128.0/255))
+
+			res, err = ipamClient.PoolUsage(ctx, &PoolUsageRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pools).To(HaveLen(3))
+			Expect(res.Pools[0].Ips).To(BeEquivalentTo(2))
+			Expect(res.Pools[2].Utilization).To(BeZero())
+		})
+
+		It("should return the state of a given pool", func() {
+			res, err := ipamClient.GetState(ctx, &GetStateRequest{Pool: "192.168.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.SyncGracePeriod).To(BeEquivalentTo(serverOpts.SyncGracePeriod))
+
+			var snapshot ipamcore.Snapshot
+			Expect(json.Unmarshal(res.Snapshot, &snapshot)).To(Succeed())
+			Expect(snapshot.Roots).To(HaveLen(1))
+			Expect(snapshot.Roots[0].Prefix).To(Equal(netip.MustParsePrefix("192.168.0.0/16")))
+
+			res, err = ipamClient.GetState(ctx, &GetStateRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal(res.Snapshot, &snapshot)).To(Succeed())
+			Expect(snapshot.Roots).To(HaveLen(3))
+		})
+	})
+
+	Describe("Watching allocation events", func() {
+		var (
+			watchCtx    context.Context
+			watchCancel context.CancelFunc
+		)
+
+		BeforeEach(func() {
+			watchCtx, watchCancel = context.WithCancel(ctx)
+		})
+
+		AfterEach(func() {
+			watchCancel()
+		})
+
+		It("should stream the allocation events", func() {
+			stream, err := ipamClient.Watch(watchCtx, &WatchRequest{Pool: "10.0.0.0/8"})
+			Expect(err).ToNot(HaveOccurred())
+			// Wait for the watcher to be registered, as the stream is established asynchronously.
+			Eventually(func() int {
+				ipamServer.mutex.Lock()
+				defer ipamServer.mutex.Unlock()
+				return len(ipamServer.watchers)
+			}).Should(Equal(1))
+
+			_, err = ipamClient.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.1.0/24", Immutable: true})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = ipamClient.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "10.20.0.0/16", Immutable: true})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = ipamClient.IPAcquire(ctx, &IPAcquireRequest{Cidr: "10.20.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = ipamClient.IPRelease(ctx, &IPReleaseRequest{Cidr: "10.20.0.0/16", Ip: "10.20.0.0"})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = ipamClient.NetworkRelease(ctx, &NetworkReleaseRequest{Cidr: "10.20.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+
+			expected := []struct {
+				eventType AllocationEventType
+				ip        string
+			}{
+				{AllocationEventType_NETWORK_ACQUIRED, ""},
+				{AllocationEventType_IP_ACQUIRED, "10.20.0.0"},
+				{AllocationEventType_IP_RELEASED, "10.20.0.0"},
+				{AllocationEventType_NETWORK_RELEASED, ""},
+			}
+			for i := range expected {
+				event, err := stream.Recv()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Type).To(Equal(expected[i].eventType))
+				Expect(event.Pool).To(Equal("10.0.0.0/8"))
+				Expect(event.Cidr).To(Equal("10.20.0.0/16"))
+				Expect(event.Ip).To(Equal(expected[i].ip))
+				Expect(event.Timestamp).ToNot(BeZero())
+			}
+		})
+
+		It("should send the initial state", func() {
+			_, err := ipamClient.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.1.0/24", Immutable: true, PreAllocated: 1})
+			Expect(err).ToNot(HaveOccurred())
+
+			stream, err := ipamClient.Watch(watchCtx, &WatchRequest{Pool: "192.168.0.0/16", SendInitialState: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			event, err := stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Type).To(Equal(AllocationEventType_NETWORK_ACQUIRED))
+			Expect(event.Cidr).To(Equal("192.168.1.0/24"))
+
+			event, err = stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Type).To(Equal(AllocationEventType_IP_ACQUIRED))
+			Expect(event.Ip).To(Equal("192.168.1.0"))
+		})
+
+		It("should fail watching an unknown pool", func() {
+			stream, err := ipamClient.Watch(watchCtx, &WatchRequest{Pool: "50.0.0.0/8"})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should disconnect watchers not keeping up with the events", func() {
+			ipamServer.mutex.Lock()
+			w := ipamServer.subscribe(netip.Prefix{})
+			for range watchEventsBuffer + 1 {
+				ipamServer.notify(AllocationEventType_NETWORK_ACQUIRED, netip.MustParsePrefix("10.20.0.0/16"), netip.Addr{})
+			}
+			ipamServer.mutex.Unlock()
+
+			Expect(w.events).To(HaveLen(watchEventsBuffer))
+			Expect(ipamServer.watchers).ToNot(HaveKey(w))
+		})
+	})
 })
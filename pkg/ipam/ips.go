@@ -23,10 +23,11 @@ import (
 	klog "k8s.io/klog/v2"
 
 	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
 )
 
-// ipAcquire acquires an IP, eventually remapped if conflicts are found.
-func (lipam *LiqoIPAM) ipAcquire(prefix netip.Prefix) (*netip.Addr, error) {
+// ipAcquire acquires an IP, eventually remapped if conflicts are found. The IP is tagged with the given owner, if any.
+func (lipam *LiqoIPAM) ipAcquire(prefix netip.Prefix, owner *ipamcore.Owner) (*netip.Addr, error) {
 	result, err := lipam.IpamCore.IPAcquire(prefix)
 	if err != nil {
 		return nil, fmt.Errorf("error reserving IP in network %q: %w", prefix.String(), err)
@@ -35,7 +36,13 @@ func (lipam *LiqoIPAM) ipAcquire(prefix netip.Prefix) (*netip.Addr, error) {
 		return nil, fmt.Errorf("failed to reserve IP in network %q", prefix.String())
 	}
 
+	if err := lipam.ipSetOwner(prefix, *result, owner); err != nil {
+		return nil, err
+	}
+
 	klog.Infof("Acquired IP %q (network %q)", result.String(), prefix.String())
+	lipam.snapshotDirty = true
+	lipam.notifyOwned(AllocationEventType_IP_ACQUIRED, prefix, *result, owner)
 
 	if lipam.opts.GraphvizEnabled {
 		return result, lipam.IpamCore.ToGraphviz()
@@ -43,8 +50,8 @@ func (lipam *LiqoIPAM) ipAcquire(prefix netip.Prefix) (*netip.Addr, error) {
 	return result, nil
 }
 
-// acquireIpWithAddress acquires an IP with a specific address.
-func (lipam *LiqoIPAM) ipAcquireWithAddr(addr netip.Addr, prefix netip.Prefix) error {
+// ipAcquireWithAddr acquires an IP with a specific address. The IP is tagged with the given owner, if any.
+func (lipam *LiqoIPAM) ipAcquireWithAddr(addr netip.Addr, prefix netip.Prefix, owner *ipamcore.Owner) error {
 	result, err := lipam.IpamCore.IPAcquireWithAddr(prefix, addr)
 	if err != nil {
 		return fmt.Errorf("error reserving IP %q in network %q: %w", addr.String(), prefix.String(), err)
@@ -53,7 +60,13 @@ func (lipam *LiqoIPAM) ipAcquireWithAddr(addr netip.Addr, prefix netip.Prefix) e
 		return fmt.Errorf("failed to reserve IP %q in network %q", addr.String(), prefix.Addr())
 	}
 
+	if err := lipam.ipSetOwner(prefix, *result, owner); err != nil {
+		return err
+	}
+
 	klog.Infof("Acquired specific IP %q (%q)", result.String(), prefix.String())
+	lipam.snapshotDirty = true
+	lipam.notifyOwned(AllocationEventType_IP_ACQUIRED, prefix, *result, owner)
 	if lipam.opts.GraphvizEnabled {
 		return lipam.IpamCore.ToGraphviz()
 	}
@@ -71,6 +84,8 @@ func (lipam *LiqoIPAM) ipRelease(addr netip.Addr, prefix netip.Prefix, gracePeri
 		return nil
 	}
 	klog.Infof("Freed IP %q (network %q)", addr.String(), prefix.String())
+	lipam.snapshotDirty = true
+	lipam.notify(AllocationEventType_IP_RELEASED, prefix, addr)
 
 	if lipam.opts.GraphvizEnabled {
 		return lipam.IpamCore.ToGraphviz()
@@ -84,8 +99,13 @@ func (lipam *LiqoIPAM) ipIsAvailable(addr netip.Addr, prefix netip.Prefix) (bool
 	return !allocated, err
 }
 
-func (lipam *LiqoIPAM) listIPsOnCluster(ctx context.Context) (map[netip.Addr]netip.Prefix, error) {
-	result := make(map[netip.Addr]netip.Prefix)
+type ipDetails struct {
+	prefix netip.Prefix
+	owner  *ipamcore.Owner
+}
+
+func (lipam *LiqoIPAM) listIPsOnCluster(ctx context.Context) (map[netip.Addr]ipDetails, error) {
+	result := make(map[netip.Addr]ipDetails)
 	var ipList ipamv1alpha1.IPList
 	if err := lipam.Client.List(ctx, &ipList); err != nil {
 		return nil, err
@@ -121,7 +141,7 @@ func (lipam *LiqoIPAM) listIPsOnCluster(ctx context.Context) (map[netip.Addr]net
 			return nil, fmt.Errorf("failed to parse CIDR %q: %w", cidr, err)
 		}
 
-		result[addr] = prefix
+		result[addr] = ipDetails{prefix: prefix, owner: ownerFromRestoredObject(ip, ipamv1alpha1.IPKind)}
 	}
 
 	return result, nil
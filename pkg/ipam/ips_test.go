@@ -92,12 +92,23 @@ var _ = Describe("IPs tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ips).To(HaveLen(3))
 
-			Expect(ips).To(HaveKeyWithValue(netip.MustParseAddr("10.1.0.0"), netip.MustParsePrefix("10.1.0.0/16")))
-			Expect(ips).To(HaveKeyWithValue(netip.MustParseAddr("10.1.0.1"), netip.MustParsePrefix("10.1.0.0/16")))
-			Expect(ips).To(HaveKeyWithValue(netip.MustParseAddr("10.2.0.0"), netip.MustParsePrefix("10.2.0.0/16")))
+			Expect(ips[netip.MustParseAddr("10.1.0.0")].prefix).To(Equal(netip.MustParsePrefix("10.1.0.0/16")))
+			Expect(ips[netip.MustParseAddr("10.1.0.1")].prefix).To(Equal(netip.MustParsePrefix("10.1.0.0/16")))
+			Expect(ips[netip.MustParseAddr("10.2.0.0")].prefix).To(Equal(netip.MustParsePrefix("10.2.0.0/16")))
 			Expect(ips).ToNot(HaveKey(netip.MustParseAddr("10.3.0.0")))
 			Expect(ips).ToNot(HaveKey(netip.MustParseAddr("10.3.0.1")))
 		})
+
+		It("should derive the owner of the ips from the corresponding resources", func() {
+			ips, err := ipamServer.listIPsOnCluster(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ips[netip.MustParseAddr("10.1.0.0")].owner).To(Equal(&ipamcore.Owner{
+				APIVersion: ipamv1alpha1.SchemeGroupVersion.String(),
+				Kind:       ipamv1alpha1.IPKind,
+				Namespace:  testNamespace,
+				Name:       "ip1",
+			}))
+		})
 	})
 
 })
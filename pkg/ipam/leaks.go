@@ -0,0 +1,265 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=ips,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=networks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+const (
+	// LeakedAllocationReason is the reason of the events reporting a leaked allocation.
+	LeakedAllocationReason = "LeakedAllocation"
+	// LeakedAllocationFreedReason is the reason of the events reporting that a leaked allocation has been freed.
+	LeakedAllocationFreedReason = "LeakedAllocationFreed"
+
+	leakTypeLabel   = "type"
+	leakTypeNetwork = "network"
+	leakTypeIP      = "ip"
+)
+
+var (
+	// MetricsLeakedAllocations is the metric reporting the allocations whose owner no longer exists.
+	MetricsLeakedAllocations = prometheus.NewDesc(
+		"liqo_ipam_leaked_allocations",
+		"Number of IPAM allocations whose owner no longer exists, as of the last collection",
+		[]string{leakTypeLabel},
+		nil,
+	)
+	// MetricsFreedLeakedAllocations is the metric reporting the leaked allocations freed by the collector.
+	MetricsFreedLeakedAllocations = prometheus.NewDesc(
+		"liqo_ipam_leaked_allocations_freed_total",
+		"Number of leaked IPAM allocations freed by the collector",
+		[]string{leakTypeLabel},
+		nil,
+	)
+)
+
+var (
+	_ prometheus.Collector = &LeakCollector{}
+	_ manager.Runnable     = &LeakCollector{}
+)
+
+// LeakCollectorOptions contains the options of the LeakCollector.
+type LeakCollectorOptions struct {
+	// Interval is the interval between two collections. If zero, the collector is disabled.
+	Interval time.Duration
+	// GracePeriod is the minimum age of the allocations to be considered leaked.
+	GracePeriod time.Duration
+	// Free configures whether leaked allocations are freed, or only reported.
+	Free bool
+}
+
+// LeakCollector periodically looks for the networks and IPs acquired on behalf of an owner which no longer exists,
+// reporting them through events and metrics, and optionally freeing them.
+type LeakCollector struct {
+	client   client.Client
+	ipam     *LiqoIPAM
+	recorder record.EventRecorder
+	// target is the object the events are attached to, since the owners of the leaked allocations no longer exist.
+	target *corev1.ObjectReference
+	opts   LeakCollectorOptions
+
+	mutex  sync.Mutex
+	leaked map[string]int
+	freed  map[string]int
+}
+
+// NewLeakCollector returns a new LeakCollector for the given IPAM.
+// Events are attached to the given target (typically the IPAM pod), if not nil.
+func NewLeakCollector(cl client.Client, lipam *LiqoIPAM, recorder record.EventRecorder,
+	target *corev1.ObjectReference, opts LeakCollectorOptions) *LeakCollector {
+	return &LeakCollector{
+		client:   cl,
+		ipam:     lipam,
+		recorder: recorder,
+		target:   target,
+		opts:     opts,
+
+		leaked: map[string]int{},
+		freed:  map[string]int{},
+	}
+}
+
+// Start runs the collector until the given context is canceled.
+func (lc *LeakCollector) Start(ctx context.Context) error {
+	if lc.opts.Interval == 0 {
+		klog.Info("IPAM leak collector disabled")
+		return nil
+	}
+
+	return wait.PollUntilContextCancel(ctx, lc.opts.Interval, false, func(ctx context.Context) (bool, error) {
+		if _, err := lc.Scan(ctx); err != nil {
+			klog.Errorf("IPAM leak collection failed: %v", err)
+		}
+		return false, nil
+	})
+}
+
+// Scan looks for the leaked allocations, freeing them if configured to do so, and returns them.
+func (lc *LeakCollector) Scan(ctx context.Context) ([]ipamcore.Allocation, error) {
+	// The owners are checked without holding the IPAM mutex, to avoid blocking the allocations while querying the API server.
+	lc.ipam.mutex.Lock()
+	allocations := lc.ipam.IpamCore.ListOwned()
+	lc.ipam.mutex.Unlock()
+
+	var leaked []ipamcore.Allocation
+	for i := range allocations {
+		allocation := &allocations[i]
+		if time.Since(allocation.Timestamp) < lc.opts.GracePeriod {
+			continue
+		}
+
+		exists, err := lc.ownerExists(ctx, &allocation.Owner)
+		if err != nil {
+			klog.Warningf("Unable to check the owner (%s) of %s: %v", &allocation.Owner, describeAllocation(allocation), err)
+			continue
+		}
+		if !exists {
+			leaked = append(leaked, *allocation)
+		}
+	}
+
+	counts := map[string]int{leakTypeNetwork: 0, leakTypeIP: 0}
+	for i := range leaked {
+		allocation := &leaked[i]
+		counts[allocationType(allocation)]++
+
+		message := fmt.Sprintf("%s is owned by %s, which no longer exists", describeAllocation(allocation), &allocation.Owner)
+		klog.Warning(message)
+		lc.event(corev1.EventTypeWarning, LeakedAllocationReason, message)
+
+		if lc.opts.Free {
+			if err := lc.free(ctx, allocation); err != nil {
+				return leaked, err
+			}
+		}
+	}
+
+	lc.mutex.Lock()
+	lc.leaked = counts
+	lc.mutex.Unlock()
+
+	return leaked, nil
+}
+
+// Describe implements prometheus.Collector.
+func (lc *LeakCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- MetricsLeakedAllocations
+	ch <- MetricsFreedLeakedAllocations
+}
+
+// Collect implements prometheus.Collector.
+func (lc *LeakCollector) Collect(ch chan<- prometheus.Metric) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	for _, t := range []string{leakTypeNetwork, leakTypeIP} {
+		ch <- prometheus.MustNewConstMetric(MetricsLeakedAllocations, prometheus.GaugeValue, float64(lc.leaked[t]), t)
+		ch <- prometheus.MustNewConstMetric(MetricsFreedLeakedAllocations, prometheus.CounterValue, float64(lc.freed[t]), t)
+	}
+}
+
+// ownerExists checks whether the given owner still exists. Owners recreated with a different UID are considered not existing.
+func (lc *LeakCollector) ownerExists(ctx context.Context, owner *ipamcore.Owner) (bool, error) {
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil || owner.APIVersion == "" {
+		return false, fmt.Errorf("invalid API version %q", owner.APIVersion)
+	}
+
+	var obj metav1.PartialObjectMetadata
+	obj.SetGroupVersionKind(gv.WithKind(owner.Kind))
+	if err := lc.client.Get(ctx, client.ObjectKey{Namespace: owner.Namespace, Name: owner.Name}, &obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return owner.UID == "" || string(obj.GetUID()) == owner.UID, nil
+}
+
+// free releases the given leaked allocation, provided that it still belongs to the same owner.
+func (lc *LeakCollector) free(ctx context.Context, allocation *ipamcore.Allocation) error {
+	lc.ipam.mutex.Lock()
+	defer lc.ipam.mutex.Unlock()
+	defer lc.ipam.requestCheckpoint()
+
+	if !slices.ContainsFunc(lc.ipam.IpamCore.ListOwned(), func(a ipamcore.Allocation) bool {
+		return a.Network == allocation.Network && a.Addr == allocation.Addr && a.Owner.Matches(&allocation.Owner)
+	}) {
+		// The allocation has been released (or reacquired) in the meanwhile.
+		return nil
+	}
+
+	var err error
+	if allocation.Addr.IsValid() {
+		err = lc.ipam.ipRelease(allocation.Addr, allocation.Network, 0)
+	} else {
+		err = lc.ipam.networkRelease(allocation.Network, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to free %s: %w", describeAllocation(allocation), err)
+	}
+
+	lc.mutex.Lock()
+	lc.freed[allocationType(allocation)]++
+	lc.mutex.Unlock()
+
+	lc.event(corev1.EventTypeNormal, LeakedAllocationFreedReason,
+		fmt.Sprintf("%s owned by %s has been freed", describeAllocation(allocation), &allocation.Owner))
+	return nil
+}
+
+func (lc *LeakCollector) event(eventType, reason, message string) {
+	if lc.recorder == nil || lc.target == nil {
+		return
+	}
+	lc.recorder.Event(lc.target, eventType, reason, message)
+}
+
+func allocationType(allocation *ipamcore.Allocation) string {
+	if allocation.Addr.IsValid() {
+		return leakTypeIP
+	}
+	return leakTypeNetwork
+}
+
+func describeAllocation(allocation *ipamcore.Allocation) string {
+	if allocation.Addr.IsValid() {
+		return fmt.Sprintf("IP %q (network %q)", allocation.Addr.String(), allocation.Network.String())
+	}
+	return fmt.Sprintf("network %q", allocation.Network.String())
+}
@@ -0,0 +1,127 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("Owned allocations and leaks tests", func() {
+	const (
+		testNamespace = "test"
+	)
+
+	var (
+		ctx        context.Context
+		ipamServer *LiqoIPAM
+
+		existing *ipamv1alpha1.Network
+		owner    = func(name, uid string) *Owner {
+			return &Owner{ApiVersion: ipamv1alpha1.SchemeGroupVersion.String(), Kind: ipamv1alpha1.NetworkKind,
+				Namespace: testNamespace, Name: name, Uid: uid}
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		existing = testutil.FakeNetwork("existing", testNamespace, "10.1.0.0/16", nil)
+		existing.SetUID(types.UID("uid-existing"))
+
+		ipamCore, err := ipamcore.NewIpam([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+		Expect(err).ToNot(HaveOccurred())
+		ipamServer = &LiqoIPAM{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(existing).Build(),
+			IpamCore: ipamCore,
+			opts:     &ServerOptions{},
+		}
+	})
+
+	Context("Acquiring on behalf of an owner", func() {
+		It("should return the same network to repeated requests", func() {
+			req := &NetworkAcquireRequest{Cidr: "10.1.0.0/16", Owner: owner("existing", "uid-existing")}
+			first, err := ipamServer.NetworkAcquire(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			second, err := ipamServer.NetworkAcquire(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Cidr).To(Equal(first.Cidr))
+			Expect(ipamServer.IpamCore.ListOwned()).To(HaveLen(1))
+		})
+
+		It("should return the same IP to repeated requests", func() {
+			_, err := ipamServer.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "10.1.0.0/16", Immutable: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			req := &IPAcquireRequest{Cidr: "10.1.0.0/16", Owner: &Owner{Kind: "IP", Namespace: testNamespace, Name: "ip"}}
+			first, err := ipamServer.IPAcquire(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			second, err := ipamServer.IPAcquire(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Ip).To(Equal(first.Ip))
+		})
+	})
+
+	Context("Collecting leaked allocations", func() {
+		var collector *LeakCollector
+
+		BeforeEach(func() {
+			for cidr, o := range map[string]*Owner{
+				"10.1.0.0/16": owner("existing", "uid-existing"),
+				"10.2.0.0/16": owner("missing", ""),
+				"10.3.0.0/16": owner("existing", "uid-recreated"),
+			} {
+				_, err := ipamServer.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: cidr, Immutable: true, Owner: o})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(ipamServer.IpamCore.ListOwned()).To(HaveLen(3))
+		})
+
+		It("should report the allocations whose owner no longer exists", func() {
+			collector = NewLeakCollector(ipamServer.Client, ipamServer, nil, nil, LeakCollectorOptions{})
+			leaked, err := collector.Scan(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(leaked).To(HaveLen(2))
+			Expect(leaked).To(ContainElements(
+				HaveField("Owner.Name", "missing"),
+				HaveField("Owner.UID", "uid-recreated"),
+			))
+			Expect(ipamServer.IpamCore.ListOwned()).To(HaveLen(3))
+		})
+
+		It("should free the leaked allocations if configured to", func() {
+			collector = NewLeakCollector(ipamServer.Client, ipamServer, nil, nil, LeakCollectorOptions{Free: true})
+			_, err := collector.Scan(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ipamServer.IpamCore.ListOwned()).To(ConsistOf(HaveField("Owner.UID", "uid-existing")))
+		})
+
+		It("should not consider the allocations within the grace period", func() {
+			collector = NewLeakCollector(ipamServer.Client, ipamServer, nil, nil, LeakCollectorOptions{GracePeriod: time.Hour})
+			Expect(collector.Scan(ctx)).To(BeEmpty())
+		})
+	})
+})
@@ -16,6 +16,7 @@ package ipam
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/netip"
 	"time"
@@ -23,20 +24,40 @@ import (
 	klog "k8s.io/klog/v2"
 
 	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
 	ipamutils "github.com/liqotech/liqo/pkg/utils/ipam"
 )
 
-// networkAcquire acquires a network, eventually remapped if conflicts are found.
-func (lipam *LiqoIPAM) networkAcquire(prefix netip.Prefix) (*netip.Prefix, error) {
-	result := lipam.IpamCore.NetworkAcquireWithPrefix(prefix)
+// networkAcquire acquires a network, eventually remapped if conflicts are found, the pool it belongs to is draining,
+// or it is reserved to another cluster. Remapped networks are chosen according to the given policy, and allocated
+// within the reservations of the given cluster, if any.
+// The network is tagged with the given owner, if any.
+func (lipam *LiqoIPAM) networkAcquire(prefix netip.Prefix, clusterID string, policy ipamcore.AllocationPolicy,
+	owner *ipamcore.Owner) (*netip.Prefix, error) {
+	var result *netip.Prefix
+	pool, _ := lipam.IpamCore.PoolOf(prefix)
+	if !lipam.IpamCore.IsPoolDraining(pool) && !lipam.IpamCore.IsReservedToOthers(prefix, clusterID) {
+		result = lipam.IpamCore.NetworkAcquireWithPrefix(prefix)
+	}
 	if result == nil {
-		result = lipam.IpamCore.NetworkAcquire(prefix.Bits())
+		result = lipam.IpamCore.NetworkAcquireWithOptions(prefix.Bits(), ipamcore.FamilyOf(prefix), ipamcore.AcquireOptions{
+			Policy: policy,
+			// The key includes the desired prefix, so that the networks of the same cluster are remapped to different positions.
+			Key:   clusterID + "/" + prefix.String(),
+			Owner: clusterID,
+		})
 		if result == nil {
 			return nil, fmt.Errorf("failed to reserve network %q", prefix.String())
 		}
 	}
 
+	if err := lipam.networkSetOwner(*result, owner); err != nil {
+		return nil, lipam.networkDiscard(*result, err)
+	}
+
 	klog.Infof("Acquired network %q -> %q", prefix.String(), result.String())
+	lipam.snapshotDirty = true
+	lipam.notifyOwned(AllocationEventType_NETWORK_ACQUIRED, *result, netip.Addr{}, owner)
 
 	if lipam.opts.GraphvizEnabled {
 		return result, lipam.IpamCore.ToGraphviz()
@@ -45,14 +66,20 @@ func (lipam *LiqoIPAM) networkAcquire(prefix netip.Prefix) (*netip.Prefix, error
 }
 
 // networkAcquireSpecific acquires a network with a specific prefix.
-// If the network is already allocated, it returns an error.
-func (lipam *LiqoIPAM) networkAcquireSpecific(prefix netip.Prefix) (*netip.Prefix, error) {
+// If the network is already allocated, it returns an error. The network is tagged with the given owner, if any.
+func (lipam *LiqoIPAM) networkAcquireSpecific(prefix netip.Prefix, owner *ipamcore.Owner) (*netip.Prefix, error) {
 	result := lipam.IpamCore.NetworkAcquireWithPrefix(prefix)
 	if result == nil {
 		return nil, fmt.Errorf("failed to reserve specific network %q", prefix.String())
 	}
 
+	if err := lipam.networkSetOwner(*result, owner); err != nil {
+		return nil, lipam.networkDiscard(*result, err)
+	}
+
 	klog.Infof("Acquired specific network %q -> %q", prefix.String(), result.String())
+	lipam.snapshotDirty = true
+	lipam.notifyOwned(AllocationEventType_NETWORK_ACQUIRED, *result, netip.Addr{}, owner)
 
 	if lipam.opts.GraphvizEnabled {
 		return result, lipam.IpamCore.ToGraphviz()
@@ -60,6 +87,15 @@ func (lipam *LiqoIPAM) networkAcquireSpecific(prefix netip.Prefix) (*netip.Prefi
 	return result, nil
 }
 
+// networkDiscard releases the given network, just acquired and not yet notified, as its acquisition failed
+// because of the given error, which is returned together with the release failure, if any.
+func (lipam *LiqoIPAM) networkDiscard(prefix netip.Prefix, cause error) error {
+	if lipam.IpamCore.NetworkRelease(prefix, 0) == nil {
+		return errors.Join(cause, fmt.Errorf("failed to release network %q", prefix.String()))
+	}
+	return cause
+}
+
 func (lipam *LiqoIPAM) acquirePreallocatedIPs(prefix netip.Prefix, preallocated uint32) error {
 	// Check if the network can allocate all preallocated IPs.
 	if prefix.Bits() < int(preallocated) {
@@ -73,7 +109,7 @@ func (lipam *LiqoIPAM) acquirePreallocatedIPs(prefix netip.Prefix, preallocated
 			return err
 		}
 		if available {
-			if err := lipam.ipAcquireWithAddr(addr, prefix); err != nil {
+			if err := lipam.ipAcquireWithAddr(addr, prefix, nil); err != nil {
 				return err
 			}
 		}
@@ -91,6 +127,8 @@ func (lipam *LiqoIPAM) networkRelease(prefix netip.Prefix, gracePeriod time.Dura
 		return nil
 	}
 	klog.Infof("Freed network %q", prefix.String())
+	lipam.snapshotDirty = true
+	lipam.notify(AllocationEventType_NETWORK_RELEASED, prefix, netip.Addr{})
 
 	if lipam.opts.GraphvizEnabled {
 		return lipam.IpamCore.ToGraphviz()
@@ -105,6 +143,7 @@ func (lipam *LiqoIPAM) networkIsAvailable(prefix netip.Prefix) bool {
 
 type prefixDetails struct {
 	preallocated uint32
+	owner        *ipamcore.Owner
 }
 
 func (lipam *LiqoIPAM) listNetworksOnCluster(ctx context.Context) (map[netip.Prefix]prefixDetails, error) {
@@ -122,6 +161,11 @@ func (lipam *LiqoIPAM) listNetworksOnCluster(ctx context.Context) (map[netip.Pre
 			continue
 		}
 
+		// Reservations do not correspond to allocated networks.
+		if net.Spec.ReservedFor != "" {
+			continue
+		}
+
 		cidr := net.Status.CIDR.String()
 		if cidr == "" {
 			continue
@@ -132,7 +176,7 @@ func (lipam *LiqoIPAM) listNetworksOnCluster(ctx context.Context) (map[netip.Pre
 			return nil, fmt.Errorf("failed to parse CIDR %q: %w", cidr, err)
 		}
 
-		result[prefix] = prefixDetails{preallocated: net.Spec.PreAllocated}
+		result[prefix] = prefixDetails{preallocated: net.Spec.PreAllocated, owner: ownerFromRestoredObject(net, ipamv1alpha1.NetworkKind)}
 	}
 
 	return result, nil
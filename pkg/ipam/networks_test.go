@@ -16,6 +16,7 @@ package ipam
 
 import (
 	"context"
+	"errors"
 	"net/netip"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
 	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
 	"github.com/liqotech/liqo/pkg/utils/testutil"
@@ -54,6 +56,11 @@ var _ = Describe("Networks tests", func() {
 			return nw
 		}
 
+		addReservedFor = func(nw *ipamv1alpha1.Network, clusterID liqov1beta1.ClusterID) *ipamv1alpha1.Network {
+			nw.Spec.ReservedFor = clusterID
+			return nw
+		}
+
 		addDeletionTimestamp = func(nw *ipamv1alpha1.Network) *ipamv1alpha1.Network {
 			nw.SetDeletionTimestamp(ptr.To(metav1.NewTime(time.Now())))
 			nw.SetFinalizers([]string{"test-finalizer"}) // fake client requires at least one finalizer if deletion timestamp is set
@@ -84,6 +91,9 @@ var _ = Describe("Networks tests", func() {
 
 				// Network in deletion
 				addDeletionTimestamp(testutil.FakeNetwork("net6", testNamespace, "10.6.0.0/16", nil)),
+
+				// Network reserved to a remote cluster
+				addReservedFor(testutil.FakeNetwork("net7", testNamespace, "10.7.0.0/16", nil), "cluster-a"),
 			).Build()
 
 			ipamServer = &LiqoIPAM{
@@ -103,10 +113,148 @@ var _ = Describe("Networks tests", func() {
 			Expect(nets).To(HaveKey(netip.MustParsePrefix("10.1.0.0/16")))
 			Expect(nets).To(HaveKey(netip.MustParsePrefix("10.2.0.0/16")))
 			Expect(nets).To(HaveKey(netip.MustParsePrefix("10.3.0.0/16")))
-			Expect(nets).To(HaveKeyWithValue(netip.MustParsePrefix("10.4.0.0/16"), prefixDetails{10})) // network with preAllocated field
-			Expect(nets).ToNot(HaveKey(netip.MustParsePrefix(("10.5.0.0/16"))))                        // network with no status
-			Expect(nets).ToNot(HaveKey(netip.MustParsePrefix(("10.6.0.0/16"))))                        // network in deletion
+			Expect(nets[netip.MustParsePrefix("10.4.0.0/16")].preallocated).To(BeNumerically("==", 10)) // network with preAllocated field
+			Expect(nets).ToNot(HaveKey(netip.MustParsePrefix(("10.5.0.0/16"))))                         // network with no status
+			Expect(nets).ToNot(HaveKey(netip.MustParsePrefix(("10.6.0.0/16"))))                         // network in deletion
+			Expect(nets).ToNot(HaveKey(netip.MustParsePrefix(("10.7.0.0/16"))))                         // reserved network
+		})
+
+		It("should derive the owner of the networks from the corresponding resources", func() {
+			nets, err := ipamServer.listNetworksOnCluster(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nets[netip.MustParsePrefix("10.1.0.0/16")].owner).To(Equal(&ipamcore.Owner{
+				APIVersion: ipamv1alpha1.SchemeGroupVersion.String(),
+				Kind:       ipamv1alpha1.NetworkKind,
+				Namespace:  testNamespace,
+				Name:       "net1",
+			}))
+		})
+
+		It("should correctly list reservations on cluster", func() {
+			reservations, err := ipamServer.listReservationsOnCluster(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reservations).To(Equal(map[netip.Prefix]string{netip.MustParsePrefix("10.7.0.0/16"): "cluster-a"}))
+		})
+	})
+
+	Context("Acquire networks with reservations", func() {
+		var reserved = netip.MustParsePrefix("10.1.0.0/16")
+
+		BeforeEach(func() {
+			ipamServer = &LiqoIPAM{
+				Client:   fakeClientBuilder.Build(),
+				IpamCore: ipamCore,
+				opts:     &ServerOptions{},
+			}
+			Expect(ipamServer.networkReserve(reserved, "cluster-a")).To(Succeed())
+		})
+
+		It("should remap the networks of the other clusters outside of the reservation", func() {
+			acquired, err := ipamServer.networkAcquire(netip.MustParsePrefix("10.1.0.0/24"), "cluster-b", ipamcore.AllocationPolicyFirstFit, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved.Overlaps(*acquired)).To(BeFalse())
+		})
+
+		It("should remap the networks of the owner within the reservation", func() {
+			prefix := netip.MustParsePrefix("10.2.0.0/24")
+			acquired, err := ipamServer.networkAcquire(prefix, "cluster-a", ipamcore.AllocationPolicyBestFit, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*acquired).To(Equal(prefix))
+
+			remapped, err := ipamServer.networkAcquire(prefix, "cluster-a", ipamcore.AllocationPolicyBestFit, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved.Contains(remapped.Addr())).To(BeTrue())
+		})
+
+		It("should remap the networks of the same cluster to the same CIDR with the deterministic policy", func() {
+			prefix := netip.MustParsePrefix("10.2.0.0/24")
+			Expect(ipamServer.networkAcquireSpecific(prefix, nil)).ToNot(BeNil())
+			remapped, err := ipamServer.networkAcquire(prefix, "cluster-c", ipamcore.AllocationPolicyDeterministic, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ipamServer.networkRelease(*remapped, 0)).To(Succeed())
+
+			again, err := ipamServer.networkAcquire(prefix, "cluster-c", ipamcore.AllocationPolicyDeterministic, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*again).To(Equal(*remapped))
+		})
+
+		It("should align the reservations with the cluster", func() {
+			ipamServer.Client = fakeClientBuilder.WithObjects(
+				addReservedFor(testutil.FakeNetwork("net1", testNamespace, "10.3.0.0/16", nil), "cluster-b"),
+			).Build()
+			Expect(ipamServer.syncReservations(ctx)).To(Succeed())
+			Expect(ipamCore.ListReservations()).To(ConsistOf(
+				ipamcore.Reservation{Prefix: netip.MustParsePrefix("10.3.0.0/16"), Owner: "cluster-b"}))
 		})
 	})
 
+	Context("Acquire networks in dual-stack pools", func() {
+		BeforeEach(func() {
+			ipamCore, err = ipamcore.NewIpam([]netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("fd00::/8"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			ipamServer = &LiqoIPAM{
+				Client:   fakeClientBuilder.Build(),
+				IpamCore: ipamCore,
+				opts: &ServerOptions{
+					GraphvizEnabled: false,
+				},
+			}
+		})
+
+		It("should remap IPv6 networks within the IPv6 pool", func() {
+			prefix := netip.MustParsePrefix("fd00:1::/64")
+			acquired, err := ipamServer.networkAcquire(prefix, "", ipamcore.AllocationPolicyFirstFit, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired.String()).To(Equal(prefix.String()))
+
+			remapped, err := ipamServer.networkAcquire(prefix, "", ipamcore.AllocationPolicyFirstFit, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(remapped.Addr().Is6()).To(BeTrue())
+			Expect(remapped.Bits()).To(Equal(64))
+			Expect(remapped.String()).ToNot(Equal(prefix.String()))
+		})
+
+		It("should preallocate IPv6 addresses", func() {
+			prefix := netip.MustParsePrefix("fd00:2::/120")
+			_, err := ipamServer.networkAcquireSpecific(prefix, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ipamServer.acquirePreallocatedIPs(prefix, 2)).To(Succeed())
+
+			ips, err := ipamCore.ListIPs(prefix)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ips).To(ConsistOf(netip.MustParseAddr("fd00:2::"), netip.MustParseAddr("fd00:2::1")))
+		})
+	})
+
+	Context("Acquire networks failing", func() {
+		BeforeEach(func() {
+			ipamServer = &LiqoIPAM{
+				Client:   fakeClientBuilder.Build(),
+				IpamCore: ipamCore,
+				opts: &ServerOptions{
+					GraphvizEnabled: false,
+				},
+			}
+		})
+
+		It("should release the networks whose acquisition failed", func() {
+			prefix := netip.MustParsePrefix("10.1.0.0/16")
+			_, err := ipamServer.networkAcquireSpecific(prefix, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ipamServer.networkIsAvailable(prefix)).To(BeFalse())
+
+			cause := errors.New("owner not set")
+			Expect(ipamServer.networkDiscard(prefix, cause)).To(MatchError(cause))
+			Expect(ipamServer.networkIsAvailable(prefix)).To(BeTrue())
+
+			// The release of a network not allocated is reported as well.
+			err = ipamServer.networkDiscard(prefix, cause)
+			Expect(err).To(MatchError(cause))
+			Expect(err).To(MatchError(ContainSubstring("failed to release network")))
+		})
+	})
 })
@@ -27,7 +27,11 @@ type Options struct {
 	RenewDeadline           time.Duration
 	RetryPeriod             time.Duration
 	PodName                 string
+	PodNamespace            string
 	DeploymentName          string
+	LeaderAddress           string
+	MetricsAddress          string
 
-	ServerOpts ServerOptions
+	ServerOpts        ServerOptions
+	LeakCollectorOpts LeakCollectorOptions
 }
@@ -0,0 +1,103 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"errors"
+	"net/netip"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// OwnerFromObject returns the owner identifying the given object, to be included in the acquisition requests.
+func OwnerFromObject(obj client.Object, scheme *runtime.Scheme) (*Owner, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &Owner{
+		ApiVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Uid:        string(obj.GetUID()),
+	}, nil
+}
+
+// ownerFromRestoredObject returns the owner of the allocation restored from the given object of the given kind,
+// matching the one included by the controllers in the acquisition requests performed for it (see OwnerFromObject).
+func ownerFromRestoredObject(obj client.Object, kind string) *ipamcore.Owner {
+	return &ipamcore.Owner{
+		APIVersion: ipamv1alpha1.SchemeGroupVersion.String(),
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        string(obj.GetUID()),
+	}
+}
+
+// ownerFromMessage converts the owner included in a request. It returns nil if the owner is not set.
+func ownerFromMessage(owner *Owner) *ipamcore.Owner {
+	if owner.GetKind() == "" || owner.GetName() == "" {
+		return nil
+	}
+	return &ipamcore.Owner{
+		APIVersion: owner.GetApiVersion(),
+		Kind:       owner.GetKind(),
+		Namespace:  owner.GetNamespace(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUid(),
+	}
+}
+
+// ownerToMessage converts the given owner to be included in a response or event. It returns nil if the owner is nil.
+func ownerToMessage(owner *ipamcore.Owner) *Owner {
+	if owner == nil {
+		return nil
+	}
+	return &Owner{
+		ApiVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Namespace:  owner.Namespace,
+		Name:       owner.Name,
+		Uid:        owner.UID,
+	}
+}
+
+// networkSetOwner tags the given network, just acquired and not yet notified, with the given owner (if any).
+func (lipam *LiqoIPAM) networkSetOwner(prefix netip.Prefix, owner *ipamcore.Owner) error {
+	if owner == nil {
+		return nil
+	}
+	return lipam.IpamCore.NetworkSetOwner(prefix, *owner)
+}
+
+// ipSetOwner tags the given IP, just acquired and not yet notified, with the given owner (if any).
+// If the owner cannot be set, the IP is released.
+func (lipam *LiqoIPAM) ipSetOwner(prefix netip.Prefix, addr netip.Addr, owner *ipamcore.Owner) error {
+	if owner == nil {
+		return nil
+	}
+	if err := lipam.IpamCore.IPSetOwner(prefix, addr, *owner); err != nil {
+		_, releaseErr := lipam.IpamCore.IPRelease(prefix, addr, 0)
+		return errors.Join(err, releaseErr)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+const (
+	poolFinalizer = "pool.ipam.liqo.io/finalizer"
+
+	// poolResyncPeriod is the period the status of the pools is refreshed with, as allocations do not trigger reconciliations.
+	poolResyncPeriod = 30 * time.Second
+)
+
+// PoolReconciler reconciles the Pool objects with the pools of the IPAM.
+type PoolReconciler struct {
+	client.Client
+
+	ipam *LiqoIPAM
+}
+
+// NewPoolReconciler returns a new PoolReconciler, managing the pools of the given IPAM.
+// It must be started only once the IPAM has been started.
+func NewPoolReconciler(cl client.Client, lipam *LiqoIPAM) *PoolReconciler {
+	return &PoolReconciler{
+		Client: cl,
+		ipam:   lipam,
+	}
+}
+
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=pools,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=pools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=pools/finalizers,verbs=update
+
+// Reconcile Pool objects.
+func (r *PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pool ipamv1alpha1.Pool
+	if err := r.Get(ctx, req.NamespacedName, &pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("Pool %q not found", req.Name)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("an error occurred while getting Pool %q: %v", req.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	prefix, err := netip.ParsePrefix(pool.Spec.CIDR.String())
+	if err != nil {
+		return ctrl.Result{}, r.handleInvalidPool(ctx, &pool, fmt.Errorf("failed to parse CIDR %q: %w", pool.Spec.CIDR, err))
+	}
+
+	duplicate, err := r.duplicateOf(ctx, &pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !pool.GetDeletionTimestamp().IsZero() {
+		return r.handlePoolDeletion(ctx, &pool, prefix, duplicate != "")
+	}
+
+	if !controllerutil.ContainsFinalizer(&pool, poolFinalizer) {
+		// Add finalizer to prevent deletion while networks are still allocated from the pool.
+		controllerutil.AddFinalizer(&pool, poolFinalizer)
+		if err := r.Update(ctx, &pool); err != nil {
+			klog.Errorf("error while adding finalizer to Pool %q: %v", pool.Name, err)
+			return ctrl.Result{}, err
+		}
+		klog.Infof("finalizer %q correctly added to Pool %q", poolFinalizer, pool.Name)
+		// We return immediately and wait for the next reconcile to eventually update the status.
+		return ctrl.Result{}, nil
+	}
+
+	if duplicate != "" {
+		err := r.handleInvalidPool(ctx, &pool, fmt.Errorf("the CIDR is already managed by Pool %q", duplicate))
+		return ctrl.Result{RequeueAfter: poolResyncPeriod}, err
+	}
+
+	stats, err := r.ipam.ensurePool(prefix, pool.Spec.Draining)
+	if err != nil {
+		// The pool might become valid once the conflicting one is removed.
+		return ctrl.Result{RequeueAfter: poolResyncPeriod}, r.handleInvalidPool(ctx, &pool, err)
+	}
+
+	phase := ipamv1alpha1.PoolPhaseActive
+	if pool.Spec.Draining {
+		phase = ipamv1alpha1.PoolPhaseDraining
+	}
+	if err := r.updatePoolStatus(ctx, &pool, phase, "", stats); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: poolResyncPeriod}, nil
+}
+
+// SetupWithManager monitors Pool resources.
+func (r *PoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPool).
+		For(&ipamv1alpha1.Pool{}).
+		Complete(r)
+}
+
+// handlePoolDeletion drains the pool being deleted, and removes the finalizer once the pool is not part of the IPAM anymore.
+// Pools whose CIDR is still managed by another Pool object are left untouched.
+func (r *PoolReconciler) handlePoolDeletion(ctx context.Context, pool *ipamv1alpha1.Pool,
+	prefix netip.Prefix, duplicated bool) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(pool, poolFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if !duplicated {
+		removed, stats, err := r.ipam.drainPool(prefix)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !removed {
+			message := fmt.Sprintf("waiting for %d networks and the reservations to be released", stats.Networks)
+			if err := r.updatePoolStatus(ctx, pool, ipamv1alpha1.PoolPhaseTerminating, message, stats); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: poolResyncPeriod}, nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(pool, poolFinalizer)
+	if err := r.Update(ctx, pool); err != nil {
+		klog.Errorf("error while removing finalizer from Pool %q: %v", pool.Name, err)
+		return ctrl.Result{}, err
+	}
+	klog.Infof("finalizer correctly removed from Pool %q", pool.Name)
+	return ctrl.Result{}, nil
+}
+
+// handleInvalidPool reports that the pool cannot be used by the IPAM.
+func (r *PoolReconciler) handleInvalidPool(ctx context.Context, pool *ipamv1alpha1.Pool, reason error) error {
+	klog.Warningf("Pool %q is invalid: %v", pool.Name, reason)
+	return r.updatePoolStatus(ctx, pool, ipamv1alpha1.PoolPhaseInvalid, reason.Error(), nil)
+}
+
+// duplicateOf returns the name of the Pool object managing the same CIDR of the given one, if any.
+// The oldest object wins, so that the one created afterwards is reported as invalid.
+func (r *PoolReconciler) duplicateOf(ctx context.Context, pool *ipamv1alpha1.Pool) (string, error) {
+	var pools ipamv1alpha1.PoolList
+	if err := r.List(ctx, &pools); err != nil {
+		return "", fmt.Errorf("failed to list pools: %w", err)
+	}
+
+	for i := range pools.Items {
+		other := &pools.Items[i]
+		if other.Name == pool.Name || other.Spec.CIDR != pool.Spec.CIDR || !other.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if other.CreationTimestamp.Before(&pool.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&pool.CreationTimestamp) && other.Name < pool.Name) ||
+			!pool.GetDeletionTimestamp().IsZero() {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// updatePoolStatus updates the status of the given Pool, including the pod and service CIDRs conflicting with it.
+func (r *PoolReconciler) updatePoolStatus(ctx context.Context, pool *ipamv1alpha1.Pool,
+	phase ipamv1alpha1.PoolPhase, message string, stats *ipamcore.PoolStats) error {
+	status := ipamv1alpha1.PoolStatus{Phase: phase, Message: message}
+
+	if stats != nil {
+		status.Networks = int32(stats.Networks)
+		status.IPs = int32(stats.IPs)
+		status.Utilization = fmt.Sprintf("%.2f%%", stats.Utilization*100)
+
+		conflicts, err := r.conflicts(ctx, stats.Pool)
+		if err != nil {
+			return err
+		}
+		status.Conflicts = conflicts
+	}
+
+	pool.Status = status
+	if err := r.Status().Update(ctx, pool); err != nil {
+		klog.Errorf("error while updating Pool %q status: %v", pool.Name, err)
+		return err
+	}
+	return nil
+}
+
+// conflicts returns the pod and service CIDRs partially overlapping with the given pool.
+// CIDRs entirely contained in the pool are reserved by the IPAM, hence they are not conflicting.
+func (r *PoolReconciler) conflicts(ctx context.Context, pool netip.Prefix) ([]ipamv1alpha1.PoolConflict, error) {
+	var networks ipamv1alpha1.NetworkList
+	if err := r.List(ctx, &networks, client.HasLabels{consts.NetworkTypeLabelKey}); err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	var conflicts []ipamv1alpha1.PoolConflict
+	for i := range networks.Items {
+		nw := &networks.Items[i]
+
+		networkType := nw.Labels[consts.NetworkTypeLabelKey]
+		if networkType != string(consts.NetworkTypePodCIDR) && networkType != string(consts.NetworkTypeServiceCIDR) {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(nw.Spec.CIDR.String())
+		if err != nil || !prefix.Overlaps(pool) || (pool.Bits() <= prefix.Bits() && pool.Contains(prefix.Addr())) {
+			continue
+		}
+
+		conflicts = append(conflicts, ipamv1alpha1.PoolConflict{
+			Name:      nw.Name,
+			Namespace: nw.Namespace,
+			Type:      networkType,
+			CIDR:      networkingv1beta1.CIDR(prefix.String()),
+		})
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("Pool reconciler", func() {
+	var (
+		ctx        context.Context
+		cancel     context.CancelFunc
+		cl         client.Client
+		lipam      *LiqoIPAM
+		reconciler *PoolReconciler
+
+		pool    = netip.MustParsePrefix("192.168.0.0/16")
+		network = netip.MustParsePrefix("192.168.1.0/24")
+
+		reconcile = func(name string) ctrl.Result {
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+			Expect(err).ToNot(HaveOccurred())
+			return res
+		}
+
+		getPool = func(name string) *ipamv1alpha1.Pool {
+			var p ipamv1alpha1.Pool
+			Expect(cl.Get(ctx, types.NamespacedName{Name: name}, &p)).To(Succeed())
+			return &p
+		}
+
+		setup = func(objs ...client.Object) {
+			var err error
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+				WithStatusSubresource(&ipamv1alpha1.Pool{}).WithObjects(objs...).Build()
+			lipam, err = New(ctx, cl, &ServerOptions{
+				Pools:           []string{"10.0.0.0/8"},
+				SyncInterval:    time.Duration(0),
+				SyncGracePeriod: time.Duration(0),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			reconciler = NewPoolReconciler(cl, lipam)
+		}
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("a pool is created", func() {
+		BeforeEach(func() {
+			setup(testutil.FakePool("pool", pool.String(), false))
+			reconcile("pool")
+			Expect(reconcile("pool").RequeueAfter).To(Equal(poolResyncPeriod))
+		})
+
+		It("should add the pool to the IPAM", func() {
+			Expect(getPool("pool").Finalizers).To(ContainElement(poolFinalizer))
+			Expect(lipam.IpamCore.Pools()).To(ContainElement(pool))
+
+			res, err := lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: network.String()})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.GetCidr()).To(Equal(network.String()))
+		})
+
+		It("should report the usage of the pool", func() {
+			_, err := lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.0.0/17", Immutable: true, PreAllocated: 2})
+			Expect(err).ToNot(HaveOccurred())
+			reconcile("pool")
+
+			status := getPool("pool").Status
+			Expect(status.Phase).To(Equal(ipamv1alpha1.PoolPhaseActive))
+			Expect(status.Networks).To(BeEquivalentTo(1))
+			Expect(status.IPs).To(BeEquivalentTo(2))
+			Expect(status.Utilization).To(Equal("50.00%"))
+		})
+
+		It("should stop allocating new networks from the pool when draining", func() {
+			_, err := lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: network.String(), Immutable: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			p := getPool("pool")
+			p.Spec.Draining = true
+			Expect(cl.Update(ctx, p)).To(Succeed())
+			reconcile("pool")
+			Expect(getPool("pool").Status.Phase).To(Equal(ipamv1alpha1.PoolPhaseDraining))
+
+			// Networks belonging to the draining pool are remapped elsewhere, or refused if immutable.
+			res, err := lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.2.0/24"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pool.Contains(netip.MustParsePrefix(res.GetCidr()).Addr())).To(BeFalse())
+			_, err = lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.3.0/24", Immutable: true})
+			Expect(err).To(HaveOccurred())
+
+			// Existing networks are preserved.
+			Expect(lipam.networkIsAvailable(network)).To(BeFalse())
+		})
+
+		It("should remove the pool once empty, when deleted", func() {
+			_, err := lipam.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: network.String(), Immutable: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cl.Delete(ctx, getPool("pool"))).To(Succeed())
+			Expect(reconcile("pool").RequeueAfter).To(Equal(poolResyncPeriod))
+			Expect(getPool("pool").Status.Phase).To(Equal(ipamv1alpha1.PoolPhaseTerminating))
+			Expect(lipam.IpamCore.Pools()).To(ContainElement(pool))
+
+			_, err = lipam.NetworkRelease(ctx, &NetworkReleaseRequest{Cidr: network.String()})
+			Expect(err).ToNot(HaveOccurred())
+			reconcile("pool")
+
+			Expect(lipam.IpamCore.Pools()).ToNot(ContainElement(pool))
+			err = cl.Get(ctx, types.NamespacedName{Name: "pool"}, &ipamv1alpha1.Pool{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	When("a pool is invalid", func() {
+		It("should report the pools overlapping with the existing ones", func() {
+			setup(testutil.FakePool("pool", "10.1.0.0/16", false))
+			reconcile("pool")
+			Expect(reconcile("pool").RequeueAfter).To(Equal(poolResyncPeriod))
+
+			Expect(getPool("pool").Status.Phase).To(Equal(ipamv1alpha1.PoolPhaseInvalid))
+			Expect(lipam.IpamCore.Pools()).ToNot(ContainElement(netip.MustParsePrefix("10.1.0.0/16")))
+		})
+
+		It("should report the pools managing the same CIDR of an older one", func() {
+			older, newer := testutil.FakePool("older", pool.String(), false), testutil.FakePool("newer", pool.String(), false)
+			older.CreationTimestamp.Time = time.Now().Add(-time.Hour)
+			newer.CreationTimestamp.Time = time.Now()
+			setup(older, newer)
+			reconcile("newer")
+			reconcile("newer")
+			Expect(getPool("newer").Status.Phase).To(Equal(ipamv1alpha1.PoolPhaseInvalid))
+
+			// Deleting the duplicated pool does not affect the IPAM.
+			reconcile("older")
+			reconcile("older")
+			Expect(cl.Delete(ctx, getPool("newer"))).To(Succeed())
+			reconcile("newer")
+			Expect(lipam.IpamCore.Pools()).To(ContainElement(pool))
+		})
+	})
+
+	It("should adopt the pools configured at startup, without removing them", func() {
+		setup(testutil.FakePool("static", "10.0.0.0/8", true))
+		reconcile("static")
+		reconcile("static")
+		Expect(lipam.IpamCore.IsPoolDraining(netip.MustParsePrefix("10.0.0.0/8"))).To(BeTrue())
+
+		Expect(cl.Delete(ctx, getPool("static"))).To(Succeed())
+		reconcile("static")
+		Expect(lipam.IpamCore.Pools()).To(ContainElement(netip.MustParsePrefix("10.0.0.0/8")))
+		Expect(lipam.IpamCore.IsPoolDraining(netip.MustParsePrefix("10.0.0.0/8"))).To(BeFalse())
+	})
+
+	It("should report the pod and service CIDRs partially overlapping with the pool", func() {
+		// The networks are not yet reserved by the IPAM (i.e., they have no status), as they do not belong to any pool.
+		unreserved := func(name, cidr string, networkType consts.NetworkType) *ipamv1alpha1.Network {
+			nw := testutil.FakeNetwork(name, "default", cidr, map[string]string{consts.NetworkTypeLabelKey: string(networkType)})
+			nw.Status.CIDR = ""
+			return nw
+		}
+		setup(
+			testutil.FakePool("pool", "192.168.0.0/17", false),
+			unreserved("pod-cidr", "192.168.0.0/16", consts.NetworkTypePodCIDR),
+			unreserved("service-cidr", "192.168.0.0/24", consts.NetworkTypeServiceCIDR),
+			unreserved("external-cidr", "192.168.0.0/8", consts.NetworkTypeExternalCIDR),
+		)
+		reconcile("pool")
+		reconcile("pool")
+
+		Expect(getPool("pool").Status.Conflicts).To(ConsistOf(ipamv1alpha1.PoolConflict{
+			Name: "pod-cidr", Namespace: "default", Type: string(consts.NetworkTypePodCIDR), CIDR: "192.168.0.0/16",
+		}))
+	})
+
+	It("should restore the allocations of the pools at startup", func() {
+		setup(
+			testutil.FakePool("pool", pool.String(), false),
+			testutil.FakeNetwork("net", "default", network.String(), nil),
+		)
+		Expect(lipam.IpamCore.Pools()).To(ContainElement(pool))
+		Expect(lipam.networkIsAvailable(network)).To(BeFalse())
+	})
+})
@@ -0,0 +1,174 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	klog "k8s.io/klog/v2"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// initializePools adds the pools defined through Pool resources to the IPAM, so that the allocations
+// belonging to them can be restored. Pools being deleted are added as well, and drained by the pool reconciler.
+// Pools which cannot be added (e.g., overlapping with other pools) are skipped, and reported by the pool reconciler.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) initializePools(ctx context.Context) error {
+	// The static pools might be missing from a state replicated from a leader configured differently.
+	for _, pool := range lipam.staticPools {
+		if err := lipam.poolAdd(pool); err != nil {
+			return err
+		}
+	}
+
+	var pools ipamv1alpha1.PoolList
+	if err := lipam.Client.List(ctx, &pools); err != nil {
+		return fmt.Errorf("failed to list pools: %w", err)
+	}
+
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+
+		prefix, err := netip.ParsePrefix(pool.Spec.CIDR.String())
+		if err != nil {
+			klog.Warningf("Skipping pool %q: failed to parse CIDR %q: %v", pool.Name, pool.Spec.CIDR, err)
+			continue
+		}
+
+		if err := lipam.poolAdd(prefix); err != nil {
+			klog.Warningf("Skipping pool %q: %v", pool.Name, err)
+			continue
+		}
+
+		draining := pool.Spec.Draining || !pool.GetDeletionTimestamp().IsZero()
+		if err := lipam.IpamCore.SetPoolDraining(prefix, draining); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensurePool adds the given pool to the IPAM (if not already present) and configures whether it is draining.
+// It returns the usage statistics of the pool.
+func (lipam *LiqoIPAM) ensurePool(pool netip.Prefix, draining bool) (*ipamcore.PoolStats, error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
+
+	if err := lipam.poolAdd(pool); err != nil {
+		return nil, err
+	}
+
+	if draining != lipam.IpamCore.IsPoolDraining(pool) {
+		if err := lipam.IpamCore.SetPoolDraining(pool, draining); err != nil {
+			return nil, err
+		}
+		klog.Infof("Pool %q draining: %t", pool.String(), draining)
+	}
+
+	return lipam.poolStats(pool), nil
+}
+
+// drainPool prevents new allocations from the given pool, and removes it as soon as no network is allocated from it.
+// Pools configured at startup are never removed, and they are restored as non-draining instead.
+// It returns whether the pool is not part of the IPAM anymore, along with its usage statistics otherwise.
+func (lipam *LiqoIPAM) drainPool(pool netip.Prefix) (removed bool, stats *ipamcore.PoolStats, err error) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+	defer lipam.requestCheckpoint()
+
+	if !slices.Contains(lipam.IpamCore.Pools(), pool) {
+		return true, nil, nil
+	}
+
+	if lipam.isStaticPool(pool) {
+		klog.Infof("Pool %q is configured at startup, hence it is not removed", pool.String())
+		return true, nil, lipam.IpamCore.SetPoolDraining(pool, false)
+	}
+
+	if err := lipam.IpamCore.SetPoolDraining(pool, true); err != nil {
+		return false, nil, err
+	}
+
+	reservations := slices.ContainsFunc(lipam.IpamCore.ListReservations(), func(r ipamcore.Reservation) bool {
+		return pool.Overlaps(r.Prefix)
+	})
+	if stats = lipam.poolStats(pool); stats.Networks > 0 || reservations {
+		klog.V(4).Infof("Pool %q still contains networks or reservations, waiting for them to be released", pool.String())
+		return false, stats, nil
+	}
+
+	return true, nil, lipam.poolRemove(pool)
+}
+
+// poolAdd adds a pool to the IPAM, if not already present.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) poolAdd(pool netip.Prefix) error {
+	if slices.Contains(lipam.IpamCore.Pools(), pool) {
+		return nil
+	}
+
+	if err := lipam.IpamCore.AddPool(pool); err != nil {
+		return fmt.Errorf("failed to add pool %q: %w", pool.String(), err)
+	}
+
+	klog.Infof("Added pool %q", pool.String())
+	lipam.snapshotDirty = true
+	lipam.notify(AllocationEventType_POOL_ADDED, pool, netip.Addr{})
+	return nil
+}
+
+// poolRemove removes an empty pool from the IPAM, if present.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) poolRemove(pool netip.Prefix) error {
+	if !slices.Contains(lipam.IpamCore.Pools(), pool) {
+		return nil
+	}
+
+	if lipam.isStaticPool(pool) {
+		return fmt.Errorf("pool %q is configured at startup and cannot be removed", pool.String())
+	}
+
+	if err := lipam.IpamCore.RemovePool(pool); err != nil {
+		return fmt.Errorf("failed to remove pool %q: %w", pool.String(), err)
+	}
+
+	klog.Infof("Removed pool %q", pool.String())
+	lipam.snapshotDirty = true
+	lipam.notify(AllocationEventType_POOL_REMOVED, pool, netip.Addr{})
+	return nil
+}
+
+// poolStats returns the usage statistics of the given pool, or nil if the pool does not exist.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) poolStats(pool netip.Prefix) *ipamcore.PoolStats {
+	for _, stats := range lipam.IpamCore.PoolStats() {
+		if stats.Pool == pool {
+			return &stats
+		}
+	}
+	return nil
+}
+
+// isStaticPool checks whether the given pool is configured at startup through the server options.
+func (lipam *LiqoIPAM) isStaticPool(pool netip.Prefix) bool {
+	return slices.Contains(lipam.staticPools, pool)
+}
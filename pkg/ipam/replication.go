@@ -0,0 +1,248 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// replicationRetryPeriod is the period the followers wait before re-establishing a broken replication stream.
+const replicationRetryPeriod = time.Second
+
+// Replicate streams the IPAM state to a follower replica: a snapshot of the current state,
+// followed by the mutations applied afterwards. The stream is closed with an error if the
+// follower does not keep up with the mutations, so that it can resync from a new snapshot.
+func (lipam *LiqoIPAM) Replicate(req *ReplicateRequest, stream grpc.ServerStreamingServer[ReplicationMessage]) error {
+	lipam.mutex.Lock()
+	data, err := json.Marshal(lipam.IpamCore.Snapshot())
+	if err != nil {
+		lipam.mutex.Unlock()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	w := lipam.subscribe(netip.Prefix{})
+	lipam.mutex.Unlock()
+
+	defer lipam.unsubscribe(w)
+
+	klog.Infof("Replica %q started following the IPAM state", req.GetReplica())
+	if err := stream.Send(&ReplicationMessage{Snapshot: data}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			klog.Infof("Replica %q stopped following the IPAM state", req.GetReplica())
+			return nil
+		case event, ok := <-w.events:
+			if !ok {
+				return fmt.Errorf("replica %q too slow, more than %d events pending", req.GetReplica(), watchEventsBuffer)
+			}
+			if err := stream.Send(&ReplicationMessage{Event: event}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// FollowUntilElected keeps the state of the replica in sync with the one of the leader, until the elected channel is closed
+// or the context is canceled. The replication stream is re-established (starting from a fresh snapshot) in case of errors.
+// It returns only once the replica stopped applying mutations, hence it is safe to start it afterwards.
+func (lipam *LiqoIPAM) FollowUntilElected(ctx context.Context, leader IPAMClient, replica string, elected <-chan struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-elected:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := lipam.follow(ctx, leader, replica); err != nil && ctx.Err() == nil {
+			klog.Warningf("Failed to follow the IPAM leader, retrying: %v", err)
+		}
+	}, replicationRetryPeriod)
+}
+
+// follow applies the state streamed by the leader, until the stream is broken or the context is canceled.
+func (lipam *LiqoIPAM) follow(ctx context.Context, leader IPAMClient, replica string) error {
+	stream, err := leader.Replicate(ctx, &ReplicateRequest{Replica: replica})
+	if err != nil {
+		return fmt.Errorf("failed to start replication: %w", err)
+	}
+
+	msg, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive snapshot: %w", err)
+	}
+	if err := lipam.applyReplicatedSnapshot(msg.GetSnapshot()); err != nil {
+		return err
+	}
+	klog.Info("IPAM state replicated from the leader")
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("failed to receive mutation: %w", err)
+		}
+		if err := lipam.applyReplicatedEvent(msg.GetEvent()); err != nil {
+			return err
+		}
+	}
+}
+
+// applyReplicatedSnapshot replaces the state of the replica with the given JSON-encoded snapshot.
+func (lipam *LiqoIPAM) applyReplicatedSnapshot(data []byte) error {
+	var snapshot ipamcore.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	// Restore the snapshot on a fresh instance, to discard the state of previous replication streams.
+	// The pools are the ones of the leader, as they may have been added or removed at runtime.
+	pools := make([]netip.Prefix, len(snapshot.Roots))
+	for i := range snapshot.Roots {
+		pools[i] = snapshot.Roots[i].Prefix
+	}
+	core, err := ipamcore.NewIpam(pools)
+	if err != nil {
+		return err
+	}
+	if err := core.Restore(&snapshot); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	lipam.IpamCore = core
+	lipam.replicated = true
+	return nil
+}
+
+// applyReplicatedEvent applies a mutation performed by the leader to the state of the replica.
+// Mutations are applied directly to the IPAM core, as followers neither checkpoint their state nor notify watchers.
+// The timestamps of the leader are replicated as well, so that the grace periods are preserved in case of failover.
+func (lipam *LiqoIPAM) applyReplicatedEvent(event *AllocationEvent) error {
+	if event == nil {
+		return errors.New("received empty replication message")
+	}
+
+	network, err := netip.ParsePrefix(event.GetCidr())
+	if err != nil {
+		return fmt.Errorf("failed to parse replicated prefix %q: %w", event.GetCidr(), err)
+	}
+
+	var addr netip.Addr
+	if event.GetIp() != "" {
+		if addr, err = netip.ParseAddr(event.GetIp()); err != nil {
+			return fmt.Errorf("failed to parse replicated address %q: %w", event.GetIp(), err)
+		}
+	}
+
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+
+	switch event.GetType() {
+	case AllocationEventType_NETWORK_ACQUIRED:
+		if lipam.IpamCore.NetworkAcquireWithPrefix(network) == nil {
+			return fmt.Errorf("failed to replicate acquisition of network %q", network)
+		}
+		if owner := ownerFromMessage(event.GetOwner()); owner != nil {
+			if err := lipam.IpamCore.NetworkSetOwner(network, *owner); err != nil {
+				return fmt.Errorf("failed to replicate owner of network %q: %w", network, err)
+			}
+		}
+		if err := lipam.replicateNetworkTimestamp(network, event.GetTimestamp()); err != nil {
+			return err
+		}
+	case AllocationEventType_NETWORK_RELEASED:
+		if lipam.IpamCore.NetworkRelease(network, 0) == nil {
+			return fmt.Errorf("failed to replicate release of network %q", network)
+		}
+	case AllocationEventType_IP_ACQUIRED:
+		if result, err := lipam.IpamCore.IPAcquireWithAddr(network, addr); err != nil || result == nil {
+			return errors.Join(fmt.Errorf("failed to replicate acquisition of IP %q (network %q)", addr, network), err)
+		}
+		if owner := ownerFromMessage(event.GetOwner()); owner != nil {
+			if err := lipam.IpamCore.IPSetOwner(network, addr, *owner); err != nil {
+				return fmt.Errorf("failed to replicate owner of IP %q (network %q): %w", addr, network, err)
+			}
+		}
+		if event.GetTimestamp() != 0 {
+			if err := lipam.IpamCore.IPSetCreationTimestamp(addr, network, time.Unix(0, event.GetTimestamp())); err != nil {
+				return fmt.Errorf("failed to replicate timestamp of IP %q (network %q): %w", addr, network, err)
+			}
+		}
+		if err := lipam.replicateNetworkTimestamp(network, event.GetTimestamp()); err != nil {
+			return err
+		}
+	case AllocationEventType_IP_RELEASED:
+		if result, err := lipam.IpamCore.IPRelease(network, addr, 0); err != nil || result == nil {
+			return errors.Join(fmt.Errorf("failed to replicate release of IP %q (network %q)", addr, network), err)
+		}
+		if err := lipam.replicateNetworkTimestamp(network, event.GetTimestamp()); err != nil {
+			return err
+		}
+	case AllocationEventType_POOL_ADDED:
+		if err := lipam.IpamCore.AddPool(network); err != nil {
+			return fmt.Errorf("failed to replicate addition of pool %q: %w", network, err)
+		}
+	case AllocationEventType_POOL_REMOVED:
+		if err := lipam.IpamCore.RemovePool(network); err != nil {
+			return fmt.Errorf("failed to replicate removal of pool %q: %w", network, err)
+		}
+	case AllocationEventType_RESERVATION_ADDED:
+		if err := lipam.IpamCore.Reserve(network, event.GetClusterID()); err != nil {
+			return fmt.Errorf("failed to replicate reservation of network %q: %w", network, err)
+		}
+	case AllocationEventType_RESERVATION_REMOVED:
+		if lipam.IpamCore.Unreserve(network) == nil {
+			return fmt.Errorf("failed to replicate removal of reservation of network %q", network)
+		}
+	default:
+		return fmt.Errorf("unknown replicated event type %q", event.GetType())
+	}
+
+	klog.V(4).Infof("Replicated %s of %q %s", event.GetType(), network, event.GetIp())
+	return nil
+}
+
+// replicateNetworkTimestamp sets the last update time of the given network to the one of the leader, as the
+// acquisitions of networks and IPs, as well as the releases of IPs, refresh it. Events without a timestamp are ignored.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) replicateNetworkTimestamp(network netip.Prefix, timestamp int64) error {
+	if timestamp == 0 {
+		return nil
+	}
+	if err := lipam.IpamCore.NetworkSetLastUpdateTimestamp(network, time.Unix(0, timestamp)); err != nil {
+		return fmt.Errorf("failed to replicate timestamp of network %q: %w", network, err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+	grpcutils "github.com/liqotech/liqo/pkg/utils/grpc"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("IPAM replication", func() {
+	const testNamespace = "test"
+
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		cl     client.Client
+
+		leader  *LiqoIPAM
+		replica *LiqoIPAM
+		server  *grpc.Server
+		conn    *grpc.ClientConn
+		client  IPAMClient
+
+		elected  chan struct{}
+		followed chan struct{}
+
+		serverOpts = func() *ServerOptions {
+			return &ServerOptions{
+				Pools:           []string{"10.0.0.0/8"},
+				SyncInterval:    time.Duration(0),
+				SyncGracePeriod: time.Duration(0),
+			}
+		}
+
+		replicaIsAvailable = func(prefix string) bool {
+			replica.mutex.Lock()
+			defer replica.mutex.Unlock()
+			return replica.networkIsAvailable(netip.MustParsePrefix(prefix))
+		}
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx, cancel = context.WithCancel(context.Background())
+
+		cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			testutil.FakeNetwork("net1", testNamespace, "10.1.0.0/16", nil),
+		).Build()
+
+		leader, err = New(ctx, cl, serverOpts())
+		Expect(err).ToNot(HaveOccurred())
+
+		server = grpc.NewServer(grpcutils.LeaderFollowingServerOptions()...)
+		RegisterIPAMServer(server, leader)
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		go func() { _ = server.Serve(lis) }()
+
+		conn, err = grpc.NewClient(lis.Addr().String(), append(grpcutils.LeaderFollowingDialOptions(IsRetryable),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+		Expect(err).ToNot(HaveOccurred())
+		client = NewIPAMClient(conn)
+
+		replica, err = NewReplica(cl, serverOpts())
+		Expect(err).ToNot(HaveOccurred())
+
+		elected, followed = make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(followed)
+			replica.FollowUntilElected(ctx, client, "replica", elected)
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(followed).Should(BeClosed())
+		Expect(conn.Close()).To(Succeed())
+		server.Stop()
+	})
+
+	It("should replicate the initial state of the leader", func() {
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+		Expect(replicaIsAvailable("10.2.0.0/16")).To(BeTrue())
+	})
+
+	It("should replicate the mutations performed by the leader", func() {
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+
+		_, err := client.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "10.2.0.0/16", Immutable: true, PreAllocated: 1})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() bool { return replicaIsAvailable("10.2.0.0/16") }).Should(BeFalse())
+		Eventually(func() (bool, error) {
+			replica.mutex.Lock()
+			defer replica.mutex.Unlock()
+			return replica.ipIsAvailable(netip.MustParseAddr("10.2.0.0"), netip.MustParsePrefix("10.2.0.0/16"))
+		}).Should(BeFalse())
+
+		_, err = client.NetworkRelease(ctx, &NetworkReleaseRequest{Cidr: "10.1.0.0/16"})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeTrue())
+	})
+
+	It("should replicate the pools managed at runtime", func() {
+		pool := netip.MustParsePrefix("192.168.0.0/16")
+		replicaPools := func() []netip.Prefix {
+			replica.mutex.Lock()
+			defer replica.mutex.Unlock()
+			return replica.IpamCore.Pools()
+		}
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+
+		_, err := leader.ensurePool(pool, false)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(replicaPools).Should(ContainElement(pool))
+
+		_, err = client.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "192.168.1.0/24", Immutable: true})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() bool { return replicaIsAvailable("192.168.1.0/24") }).Should(BeFalse())
+
+		_, err = client.NetworkRelease(ctx, &NetworkReleaseRequest{Cidr: "192.168.1.0/24"})
+		Expect(err).ToNot(HaveOccurred())
+		removed, _, err := leader.drainPool(pool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeTrue())
+		Eventually(replicaPools).ShouldNot(ContainElement(pool))
+	})
+
+	It("should replicate the reservations", func() {
+		replicaReservations := func() []ipamcore.Reservation {
+			replica.mutex.Lock()
+			defer replica.mutex.Unlock()
+			return replica.IpamCore.ListReservations()
+		}
+		reservation := ipamcore.Reservation{Prefix: netip.MustParsePrefix("10.4.0.0/16"), Owner: "cluster-a"}
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+
+		_, err := client.NetworkReserve(ctx, &NetworkReserveRequest{Cidr: "10.4.0.0/16", ClusterID: "cluster-a"})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(replicaReservations).Should(ConsistOf(reservation))
+
+		_, err = client.NetworkUnreserve(ctx, &NetworkUnreserveRequest{Cidr: "10.4.0.0/16"})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(replicaReservations).Should(BeEmpty())
+	})
+
+	It("should take over from the replicated state once elected", func() {
+		_, err := client.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "10.2.0.0/16", Immutable: true})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() bool { return replicaIsAvailable("10.2.0.0/16") }).Should(BeFalse())
+
+		close(elected)
+		Eventually(followed).Should(BeClosed())
+		Expect(replica.replicated).To(BeTrue())
+
+		// The replicated state is reconciled with the cluster: the network acquired through the leader is kept
+		// since the grace period is not over (it is not yet reflected by any resource).
+		replica.opts.SyncGracePeriod = time.Hour
+		Expect(replica.Start(ctx)).To(Succeed())
+		Expect(replicaIsAvailable("10.1.0.0/16")).To(BeFalse())
+		Expect(replicaIsAvailable("10.2.0.0/16")).To(BeFalse())
+		Expect(replicaIsAvailable("10.3.0.0/16")).To(BeTrue())
+	})
+
+	It("should preserve the timestamps of the acquisitions performed by the leader", func() {
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+
+		timestamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+		owner := &Owner{ApiVersion: "v1", Kind: "Pod", Namespace: testNamespace, Name: "pod", Uid: "uid"}
+		Expect(replica.applyReplicatedEvent(&AllocationEvent{Type: AllocationEventType_NETWORK_ACQUIRED,
+			Cidr: "10.2.0.0/16", Timestamp: timestamp.UnixNano(), Owner: owner})).To(Succeed())
+		Expect(replica.applyReplicatedEvent(&AllocationEvent{Type: AllocationEventType_IP_ACQUIRED,
+			Cidr: "10.2.0.0/16", Ip: "10.2.0.1", Timestamp: timestamp.UnixNano(), Owner: owner})).To(Succeed())
+
+		replica.mutex.Lock()
+		defer replica.mutex.Unlock()
+		Expect(replica.IpamCore.ListOwned()).To(ContainElements(
+			SatisfyAll(HaveField("Addr", netip.Addr{}), HaveField("Timestamp", BeTemporally("==", timestamp))),
+			SatisfyAll(HaveField("Addr", netip.MustParseAddr("10.2.0.1")), HaveField("Timestamp", BeTemporally("==", timestamp))),
+		))
+	})
+
+	It("should resync from a new snapshot if a mutation cannot be applied", func() {
+		Eventually(func() bool { return replicaIsAvailable("10.1.0.0/16") }).Should(BeFalse())
+
+		// Diverge the replica from the leader.
+		replica.mutex.Lock()
+		Expect(replica.IpamCore.NetworkAcquireWithPrefix(netip.MustParsePrefix("10.2.0.0/16"))).ToNot(BeNil())
+		replica.mutex.Unlock()
+
+		_, err := client.NetworkAcquire(ctx, &NetworkAcquireRequest{Cidr: "10.2.0.0/24", Immutable: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() []netip.Prefix {
+			replica.mutex.Lock()
+			defer replica.mutex.Unlock()
+			return replica.IpamCore.ListNetworks()
+		}).WithTimeout(5 * time.Second).Should(ConsistOf(netip.MustParsePrefix("10.1.0.0/16"), netip.MustParsePrefix("10.2.0.0/24")))
+	})
+})
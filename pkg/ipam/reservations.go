@@ -0,0 +1,105 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	klog "k8s.io/klog/v2"
+
+	ipamv1alpha1 "github.com/liqotech/liqo/apis/ipam/v1alpha1"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// networkReserve reserves a range of the pools to the given cluster.
+func (lipam *LiqoIPAM) networkReserve(prefix netip.Prefix, clusterID string) error {
+	reservation := ipamcore.Reservation{Prefix: prefix, Owner: clusterID}
+	if slices.Contains(lipam.IpamCore.ListReservations(), reservation) {
+		return nil
+	}
+
+	if err := lipam.IpamCore.Reserve(prefix, clusterID); err != nil {
+		return fmt.Errorf("failed to reserve network %q to cluster %q: %w", prefix.String(), clusterID, err)
+	}
+
+	klog.Infof("Reserved network %q to cluster %q", prefix.String(), clusterID)
+	lipam.snapshotDirty = true
+	lipam.notifyReservation(AllocationEventType_RESERVATION_ADDED, reservation)
+	return nil
+}
+
+// networkUnreserve removes the reservation of the given range, if any.
+func (lipam *LiqoIPAM) networkUnreserve(prefix netip.Prefix) {
+	reservation := lipam.IpamCore.Unreserve(prefix)
+	if reservation == nil {
+		klog.Infof("Network %q already unreserved", prefix.String())
+		return
+	}
+
+	klog.Infof("Removed reservation of network %q to cluster %q", prefix.String(), reservation.Owner)
+	lipam.snapshotDirty = true
+	lipam.notifyReservation(AllocationEventType_RESERVATION_REMOVED, *reservation)
+}
+
+// listReservationsOnCluster returns the ranges reserved through the Network resources, along with the owning clusters.
+func (lipam *LiqoIPAM) listReservationsOnCluster(ctx context.Context) (map[netip.Prefix]string, error) {
+	result := make(map[netip.Prefix]string)
+	var networks ipamv1alpha1.NetworkList
+	if err := lipam.Client.List(ctx, &networks); err != nil {
+		return nil, err
+	}
+
+	for i := range networks.Items {
+		net := &networks.Items[i]
+		if net.Spec.ReservedFor == "" || !net.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(net.Spec.CIDR.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIDR %q: %w", net.Spec.CIDR, err)
+		}
+		result[prefix] = string(net.Spec.ReservedFor)
+	}
+
+	return result, nil
+}
+
+// syncReservations aligns the reservations of the IPAM with the ones configured in the cluster.
+// Reservations which cannot be applied (e.g., because overlapping) are reported and skipped,
+// as they are rejected as well when requested by the network controller.
+func (lipam *LiqoIPAM) syncReservations(ctx context.Context) error {
+	clusterReservations, err := lipam.listReservationsOnCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range lipam.IpamCore.ListReservations() {
+		if owner, ok := clusterReservations[reservation.Prefix]; !ok || owner != reservation.Owner {
+			lipam.networkUnreserve(reservation.Prefix)
+		}
+	}
+
+	for prefix, owner := range clusterReservations {
+		if err := lipam.networkReserve(prefix, owner); err != nil {
+			klog.Warning(err)
+		}
+	}
+
+	return nil
+}
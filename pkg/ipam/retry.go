@@ -0,0 +1,37 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+// IsRetryable tells whether a call to the given IPAM method, with the given request, can be safely retried if the
+// leader becomes unavailable in the meanwhile. Read-only methods, releases and reservations are idempotent, hence always
+// retryable, while acquisitions are retryable only if performed on behalf of an owner, as repeating them would otherwise
+// allocate a second resource.
+func IsRetryable(method string, req any) bool {
+	switch method {
+	case IPAM_NetworkIsAvailable_FullMethodName, IPAM_ListNetworks_FullMethodName, IPAM_ListIPs_FullMethodName,
+		IPAM_PoolUsage_FullMethodName, IPAM_GetState_FullMethodName,
+		IPAM_IPRelease_FullMethodName, IPAM_NetworkRelease_FullMethodName,
+		IPAM_NetworkReserve_FullMethodName, IPAM_NetworkUnreserve_FullMethodName:
+		return true
+	case IPAM_IPAcquire_FullMethodName:
+		r, ok := req.(*IPAcquireRequest)
+		return ok && ownerFromMessage(r.GetOwner()) != nil
+	case IPAM_NetworkAcquire_FullMethodName:
+		r, ok := req.(*NetworkAcquireRequest)
+		return ok && ownerFromMessage(r.GetOwner()) != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retryable methods", func() {
+	owner := &Owner{ApiVersion: "ipam.liqo.io/v1alpha1", Kind: "Network", Namespace: "default", Name: "foo", Uid: "uid"}
+
+	DescribeTable("IsRetryable",
+		func(method string, req any, expected bool) {
+			Expect(IsRetryable(method, req)).To(Equal(expected))
+		},
+		Entry("read-only method", IPAM_ListNetworks_FullMethodName, &ListNetworksRequest{}, true),
+		Entry("release", IPAM_NetworkRelease_FullMethodName, &NetworkReleaseRequest{Cidr: "10.0.0.0/24"}, true),
+		Entry("network acquire without owner", IPAM_NetworkAcquire_FullMethodName, &NetworkAcquireRequest{Cidr: "10.0.0.0/24"}, false),
+		Entry("network acquire with owner", IPAM_NetworkAcquire_FullMethodName,
+			&NetworkAcquireRequest{Cidr: "10.0.0.0/24", Owner: owner}, true),
+		Entry("IP acquire without owner", IPAM_IPAcquire_FullMethodName, &IPAcquireRequest{Cidr: "10.0.0.0/24"}, false),
+		Entry("IP acquire with owner", IPAM_IPAcquire_FullMethodName, &IPAcquireRequest{Cidr: "10.0.0.0/24", Owner: owner}, true),
+		Entry("unknown method", "/IPAM/Unknown", nil, false),
+	)
+})
@@ -0,0 +1,333 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+
+const (
+	// SnapshotConfigMapKey is the key of the ConfigMap binary data containing the IPAM snapshot.
+	SnapshotConfigMapKey = "snapshot.json.gz"
+	// MaxConfigMapSnapshotSize is the maximum size of the (compressed) snapshot stored in a ConfigMap,
+	// leaving room for the object metadata below the 1 MiB limit enforced by the API server.
+	MaxConfigMapSnapshotSize = 900 * 1024
+
+	// checkpointDebounce is the time waited after a modification before checkpointing the state,
+	// so that the modifications performed in the meanwhile are persisted at once.
+	checkpointDebounce = time.Second
+	// checkpointFlushTimeout is the maximum time waited to checkpoint the last modifications upon termination.
+	checkpointFlushTimeout = 5 * time.Second
+)
+
+// ErrSnapshotTooLarge is returned when the snapshot exceeds the maximum size supported by the store.
+var ErrSnapshotTooLarge = errors.New("snapshot too large")
+
+var (
+	// MetricsSnapshotFailures is the metric that counts the failed checkpoints of the IPAM state.
+	MetricsSnapshotFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "liqo_ipam_snapshot_failures_total",
+		Help: "Number of failed checkpoints of the IPAM state.",
+	})
+
+	// MetricsSnapshotLastSuccess is the metric that exposes the time of the last successful checkpoint of the IPAM state.
+	MetricsSnapshotLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "liqo_ipam_snapshot_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful checkpoint of the IPAM state.",
+	})
+
+	// MetricsSnapshotSize is the metric that exposes the size of the last encoded snapshot of the IPAM state.
+	MetricsSnapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "liqo_ipam_snapshot_size_bytes",
+		Help: "Size in bytes of the last (compressed) snapshot of the IPAM state.",
+	})
+)
+
+// RegisterSnapshotMetrics registers the snapshot metrics to the given registerer.
+func RegisterSnapshotMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{MetricsSnapshotFailures, MetricsSnapshotLastSuccess, MetricsSnapshotSize} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SnapshotStore persists and retrieves the snapshots of the IPAM state.
+type SnapshotStore interface {
+	// Load returns the last saved snapshot, or nil if no snapshot has been saved yet.
+	Load(ctx context.Context) (*ipamcore.Snapshot, error)
+	// Save persists the given snapshot, replacing the previous one.
+	Save(ctx context.Context, snapshot *ipamcore.Snapshot) error
+}
+
+// NewSnapshotStore returns the SnapshotStore configured by the given options, or nil if snapshots are disabled.
+func NewSnapshotStore(cl client.Client, opts *ServerOptions) SnapshotStore {
+	switch {
+	case opts.SnapshotFilePath != "":
+		return NewFileSnapshotStore(opts.SnapshotFilePath)
+	case opts.SnapshotConfigMapName != "":
+		return NewConfigMapSnapshotStore(cl, opts.SnapshotConfigMapNamespace, opts.SnapshotConfigMapName)
+	default:
+		return nil
+	}
+}
+
+// configMapSnapshotStore stores the IPAM snapshot in a ConfigMap.
+type configMapSnapshotStore struct {
+	cl        client.Client
+	namespace string
+	name      string
+}
+
+// NewConfigMapSnapshotStore returns a SnapshotStore saving the snapshots in the given ConfigMap.
+func NewConfigMapSnapshotStore(cl client.Client, namespace, name string) SnapshotStore {
+	return &configMapSnapshotStore{cl: cl, namespace: namespace, name: name}
+}
+
+// Load returns the snapshot stored in the ConfigMap.
+func (s *configMapSnapshotStore) Load(ctx context.Context) (*ipamcore.Snapshot, error) {
+	var cm corev1.ConfigMap
+	if err := s.cl.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.name}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get snapshot configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	data, ok := cm.BinaryData[SnapshotConfigMapKey]
+	if !ok {
+		return nil, nil
+	}
+	return decodeSnapshot(data)
+}
+
+// Save stores the snapshot in the ConfigMap, creating it if necessary.
+// It fails without contacting the API server if the snapshot would exceed the maximum size of a ConfigMap.
+func (s *configMapSnapshotStore) Save(ctx context.Context, snapshot *ipamcore.Snapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxConfigMapSnapshotSize {
+		return fmt.Errorf("%w: %d bytes exceed the maximum of %d bytes for the configmap %s/%s, consider storing it in a file",
+			ErrSnapshotTooLarge, len(data), MaxConfigMapSnapshotSize, s.namespace, s.name)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace}}
+	if _, err := resource.CreateOrUpdate(ctx, s.cl, cm, func() error {
+		cm.BinaryData = map[string][]byte{SnapshotConfigMapKey: data}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to save snapshot configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+	return nil
+}
+
+// fileSnapshotStore stores the IPAM snapshot in a local file.
+type fileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore returns a SnapshotStore saving the snapshots in the given file.
+func NewFileSnapshotStore(path string) SnapshotStore {
+	return &fileSnapshotStore{path: filepath.Clean(path)}
+}
+
+// Load returns the snapshot stored in the file.
+func (s *fileSnapshotStore) Load(_ context.Context) (*ipamcore.Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot file %q: %w", s.path, err)
+	}
+	return decodeSnapshot(data)
+}
+
+// Save atomically replaces the snapshot file.
+func (s *fileSnapshotStore) Save(_ context.Context, snapshot *ipamcore.Snapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace snapshot file %q: %w", s.path, err)
+	}
+	return nil
+}
+
+func encodeSnapshot(snapshot *ipamcore.Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	MetricsSnapshotSize.Set(float64(buf.Len()))
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(data []byte) (*ipamcore.Snapshot, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+
+	var snapshot ipamcore.Snapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// restoreSnapshot restores the IPAM state from the configured snapshot store.
+// It returns true if the state has been restored, false if no usable snapshot is available.
+func (lipam *LiqoIPAM) restoreSnapshot(ctx context.Context) bool {
+	if lipam.snapshotStore == nil {
+		return false
+	}
+
+	snapshot, err := lipam.snapshotStore.Load(ctx)
+	switch {
+	case err != nil:
+		klog.Warningf("Failed to load IPAM snapshot, falling back to full initialization: %v", err)
+		return false
+	case snapshot == nil:
+		klog.Info("No IPAM snapshot found, performing full initialization")
+		return false
+	}
+
+	if err := lipam.IpamCore.Restore(snapshot); err != nil {
+		klog.Warningf("Failed to restore IPAM snapshot, falling back to full initialization: %v", err)
+		return false
+	}
+
+	klog.Info("IPAM state restored from snapshot")
+	return true
+}
+
+// requestCheckpoint asynchronously checkpoints the current IPAM state, if modified since the last checkpoint.
+// It must be called with the mutex held, and it never blocks.
+func (lipam *LiqoIPAM) requestCheckpoint() {
+	if lipam.snapshotStore == nil || !lipam.snapshotDirty {
+		return
+	}
+
+	select {
+	case lipam.checkpointRequests <- struct{}{}:
+	default:
+		// A checkpoint is already pending, and it will include the current modifications.
+	}
+}
+
+// runCheckpointer checkpoints the IPAM state upon request, until the context is canceled. Requests are debounced,
+// so that bursts of modifications are persisted at once. Errors are not fatal, since the state can always be rebuilt
+// from the cluster: the checkpoint is retried at the next modification or sync.
+func (lipam *LiqoIPAM) runCheckpointer(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			// Persist the last modifications, if any, before terminating.
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkpointFlushTimeout)
+			defer cancel()
+			if err := lipam.checkpoint(flushCtx); err != nil {
+				klog.Errorf("Failed to checkpoint IPAM state: %v", err)
+			}
+			return
+		case <-lipam.checkpointRequests:
+		}
+
+		select {
+		case <-ctx.Done():
+			continue
+		case <-time.After(checkpointDebounce):
+		}
+
+		if err := lipam.checkpoint(ctx); err != nil {
+			klog.Errorf("Failed to checkpoint IPAM state: %v", err)
+		}
+	}
+}
+
+// checkpoint saves the current IPAM state to the configured snapshot store, if modified since the last checkpoint.
+// The mutex is held only to take the snapshot, while the snapshot is saved without blocking the other operations.
+func (lipam *LiqoIPAM) checkpoint(ctx context.Context) error {
+	lipam.mutex.Lock()
+	if lipam.snapshotStore == nil || !lipam.snapshotDirty {
+		lipam.mutex.Unlock()
+		return nil
+	}
+	snapshot := lipam.IpamCore.Snapshot()
+	lipam.snapshotDirty = false
+	lipam.mutex.Unlock()
+
+	if err := lipam.snapshotStore.Save(ctx, snapshot); err != nil {
+		lipam.mutex.Lock()
+		lipam.snapshotDirty = true
+		lipam.mutex.Unlock()
+		MetricsSnapshotFailures.Inc()
+		return err
+	}
+
+	MetricsSnapshotLastSuccess.SetToCurrentTime()
+	klog.V(4).Info("IPAM state checkpointed")
+	return nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("Snapshot tests", func() {
+	const (
+		testNamespace = "test"
+	)
+
+	var (
+		ctx               context.Context
+		fakeClientBuilder *fake.ClientBuilder
+
+		pools = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+		newSnapshot = func(networks ...netip.Prefix) *ipamcore.Snapshot {
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			for i := range networks {
+				Expect(core.NetworkAcquireWithPrefix(networks[i])).ToNot(BeNil())
+			}
+			return core.Snapshot()
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClientBuilder = fake.NewClientBuilder().WithScheme(scheme.Scheme)
+	})
+
+	Context("Snapshot stores", func() {
+		It("should save and load snapshots in a file", func() {
+			store := NewFileSnapshotStore(filepath.Join(GinkgoT().TempDir(), "snapshot"))
+
+			snapshot, err := store.Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(BeNil())
+
+			Expect(store.Save(ctx, newSnapshot(netip.MustParsePrefix("10.1.0.0/16")))).To(Succeed())
+			Expect(store.Save(ctx, newSnapshot(netip.MustParsePrefix("10.2.0.0/16")))).To(Succeed())
+
+			snapshot, err = store.Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot.Roots).To(HaveLen(1))
+			Expect(snapshot.Roots[0].Prefix).To(Equal(pools[0]))
+		})
+
+		It("should fail loading a corrupted file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "snapshot")
+			Expect(os.WriteFile(path, []byte("corrupted"), 0o600)).To(Succeed())
+
+			_, err := NewFileSnapshotStore(path).Load(ctx)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should save and load snapshots in a configmap", func() {
+			store := NewConfigMapSnapshotStore(fakeClientBuilder.Build(), testNamespace, "ipam-snapshot")
+
+			snapshot, err := store.Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(BeNil())
+
+			Expect(store.Save(ctx, newSnapshot(netip.MustParsePrefix("10.1.0.0/16")))).To(Succeed())
+			Expect(store.Save(ctx, newSnapshot(netip.MustParsePrefix("10.2.0.0/16")))).To(Succeed())
+
+			snapshot, err = store.Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot.Roots).To(HaveLen(1))
+
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(core.Restore(snapshot)).To(Succeed())
+			Expect(core.ListNetworks()).To(ConsistOf(netip.MustParsePrefix("10.2.0.0/16")))
+		})
+	})
+
+	Context("Initialization from snapshot", func() {
+		var (
+			store      SnapshotStore
+			ipamServer *LiqoIPAM
+			serverOpts *ServerOptions
+		)
+
+		BeforeEach(func() {
+			store = NewFileSnapshotStore(filepath.Join(GinkgoT().TempDir(), "snapshot"))
+
+			// The snapshot contains a network which is no more in the cluster, and lacks a network created after the checkpoint.
+			Expect(store.Save(ctx, newSnapshot(
+				netip.MustParsePrefix("10.1.0.0/16"),
+				netip.MustParsePrefix("10.2.0.0/16"),
+			))).To(Succeed())
+
+			serverOpts = &ServerOptions{
+				Pools:           []string{pools[0].String()},
+				SyncGracePeriod: time.Hour,
+			}
+		})
+
+		It("should reconcile the restored state with the cluster", func() {
+			cl := fakeClientBuilder.WithObjects(
+				testutil.FakeNetwork("net1", testNamespace, "10.1.0.0/16", nil),
+				testutil.FakeNetwork("net3", testNamespace, "10.3.0.0/16", nil),
+			).Build()
+
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			ipamServer = &LiqoIPAM{
+				Client:        cl,
+				IpamCore:      core,
+				snapshotStore: store,
+				opts:          serverOpts,
+			}
+			Expect(ipamServer.initialize(ctx)).To(Succeed())
+
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.1.0.0/16"))).To(BeFalse())
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.3.0.0/16"))).To(BeFalse())
+			// The stale network is kept until the grace period expires.
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.2.0.0/16"))).To(BeFalse())
+
+			// The reconciled state has been checkpointed.
+			snapshot, err := store.Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			restored, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(restored.Restore(snapshot)).To(Succeed())
+			Expect(restored.ListNetworks()).To(ConsistOf(
+				netip.MustParsePrefix("10.1.0.0/16"),
+				netip.MustParsePrefix("10.2.0.0/16"),
+				netip.MustParsePrefix("10.3.0.0/16"),
+			))
+		})
+
+		It("should release stale networks once the grace period is over", func() {
+			serverOpts.SyncGracePeriod = 0
+			cl := fakeClientBuilder.WithObjects(
+				testutil.FakeNetwork("net1", testNamespace, "10.1.0.0/16", nil),
+			).Build()
+
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			ipamServer = &LiqoIPAM{
+				Client:        cl,
+				IpamCore:      core,
+				snapshotStore: store,
+				opts:          serverOpts,
+			}
+			Expect(ipamServer.initialize(ctx)).To(Succeed())
+
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.1.0.0/16"))).To(BeFalse())
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.2.0.0/16"))).To(BeTrue())
+		})
+
+		It("should fall back to the full initialization with a corrupted snapshot", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "corrupted")
+			Expect(os.WriteFile(path, []byte("corrupted"), 0o600)).To(Succeed())
+			cl := fakeClientBuilder.WithObjects(
+				testutil.FakeNetwork("net1", testNamespace, "10.1.0.0/16", nil),
+			).Build()
+
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			ipamServer = &LiqoIPAM{
+				Client:        cl,
+				IpamCore:      core,
+				snapshotStore: NewFileSnapshotStore(path),
+				opts:          serverOpts,
+			}
+			Expect(ipamServer.initialize(ctx)).To(Succeed())
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.1.0.0/16"))).To(BeFalse())
+			Expect(ipamServer.networkIsAvailable(netip.MustParsePrefix("10.2.0.0/16"))).To(BeTrue())
+
+			// The corrupted snapshot has been replaced.
+			snapshot, err := NewFileSnapshotStore(path).Load(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).ToNot(BeNil())
+		})
+	})
+
+	Context("Asynchronous checkpoints", func() {
+		var (
+			ipamServer *LiqoIPAM
+			cancel     context.CancelFunc
+		)
+
+		newServer := func(store SnapshotStore) {
+			core, err := ipamcore.NewIpam(pools)
+			Expect(err).ToNot(HaveOccurred())
+			ipamServer = &LiqoIPAM{
+				Client:             fakeClientBuilder.Build(),
+				IpamCore:           core,
+				snapshotStore:      store,
+				checkpointRequests: make(chan struct{}, 1),
+				opts:               &ServerOptions{},
+			}
+
+			var runCtx context.Context
+			runCtx, cancel = context.WithCancel(ctx)
+			go ipamServer.runCheckpointer(runCtx)
+		}
+
+		acquire := func(prefix netip.Prefix) {
+			ipamServer.mutex.Lock()
+			defer ipamServer.mutex.Unlock()
+			defer ipamServer.requestCheckpoint()
+			_, err := ipamServer.networkAcquireSpecific(prefix, nil)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		AfterEach(func() { cancel() })
+
+		It("should checkpoint the modifications in background", func() {
+			store := NewFileSnapshotStore(filepath.Join(GinkgoT().TempDir(), "snapshot"))
+			newServer(store)
+
+			acquire(netip.MustParsePrefix("10.1.0.0/16"))
+			acquire(netip.MustParsePrefix("10.2.0.0/16"))
+
+			Eventually(func() []netip.Prefix {
+				snapshot, err := store.Load(ctx)
+				if err != nil || snapshot == nil {
+					return nil
+				}
+				restored, err := ipamcore.NewIpam(pools)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(restored.Restore(snapshot)).To(Succeed())
+				return restored.ListNetworks()
+			}, 5*checkpointDebounce).Should(ConsistOf(netip.MustParsePrefix("10.1.0.0/16"), netip.MustParsePrefix("10.2.0.0/16")))
+		})
+
+		It("should keep the state as modified if the checkpoint fails", func() {
+			// The file cannot be created, as its parent directory does not exist.
+			newServer(NewFileSnapshotStore(filepath.Join(GinkgoT().TempDir(), "missing", "snapshot")))
+
+			acquire(netip.MustParsePrefix("10.1.0.0/16"))
+
+			Consistently(func() bool {
+				ipamServer.mutex.Lock()
+				defer ipamServer.mutex.Unlock()
+				return ipamServer.snapshotDirty
+			}, 2*checkpointDebounce).Should(BeTrue())
+		})
+	})
+})
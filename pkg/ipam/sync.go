@@ -43,6 +43,11 @@ func (lipam *LiqoIPAM) sync(ctx context.Context, syncFrequency time.Duration) {
 			defer lipam.mutex.Unlock()
 			klog.V(3).Infof("Started IPAM cache sync routine (grace period: %s)", lipam.opts.SyncGracePeriod)
 
+			// Sync reservations.
+			if err := lipam.syncReservations(ctx); err != nil {
+				return false, err
+			}
+
 			// Sync networks.
 			if err := lipam.syncNetworks(ctx); err != nil {
 				return false, err
@@ -53,6 +58,8 @@ func (lipam *LiqoIPAM) sync(ctx context.Context, syncFrequency time.Duration) {
 				return false, err
 			}
 
+			lipam.requestCheckpoint()
+
 			klog.V(3).Info("Completed IPAM cache sync routine")
 			return false, nil
 		})
@@ -66,7 +73,7 @@ func syncNetworkAcquire(lipam *LiqoIPAM, clusterNetworks map[netip.Prefix]prefix
 	// Add networks that are present in the cluster but not in the cache.
 	for clusterNetwork, clusterNetworkDetails := range clusterNetworks {
 		if _, ok := cachedNetworks[clusterNetwork]; !ok {
-			if _, err := lipam.networkAcquireSpecific(clusterNetwork); err != nil {
+			if _, err := lipam.networkAcquireSpecific(clusterNetwork, clusterNetworkDetails.owner); err != nil {
 				return fmt.Errorf("failed to acquire network %q: %w", clusterNetwork, err)
 			}
 		}
@@ -116,11 +123,11 @@ func (lipam *LiqoIPAM) syncNetworks(ctx context.Context) error {
 	return nil
 }
 
-func syncIPsAcquire(lipam *LiqoIPAM, clusterIPs, cachedIPs map[netip.Addr]netip.Prefix) error {
-	for clusterIP, clusterNetwork := range clusterIPs {
+func syncIPsAcquire(lipam *LiqoIPAM, clusterIPs map[netip.Addr]ipDetails, cachedIPs map[netip.Addr]netip.Prefix) error {
+	for clusterIP, clusterIPDetails := range clusterIPs {
 		if _, ok := cachedIPs[clusterIP]; !ok {
-			if err := lipam.ipAcquireWithAddr(clusterIP, clusterNetwork); err != nil {
-				return fmt.Errorf("failed to acquire IP %q in network %q: %w", clusterIP.String(), clusterNetwork.String(), err)
+			if err := lipam.ipAcquireWithAddr(clusterIP, clusterIPDetails.prefix, clusterIPDetails.owner); err != nil {
+				return fmt.Errorf("failed to acquire IP %q in network %q: %w", clusterIP.String(), clusterIPDetails.prefix.String(), err)
 			}
 		}
 	}
@@ -142,7 +149,8 @@ func iscachedIPPreallocated(cachedIP netip.Addr, cachedNetwork netip.Prefix, clu
 	return false
 }
 
-func syncIPsFree(lipam *LiqoIPAM, clusterIPs, cachedIPs map[netip.Addr]netip.Prefix, clusterNetworks map[netip.Prefix]prefixDetails) error {
+func syncIPsFree(lipam *LiqoIPAM, clusterIPs map[netip.Addr]ipDetails, cachedIPs map[netip.Addr]netip.Prefix,
+	clusterNetworks map[netip.Prefix]prefixDetails) error {
 	for cachedIP, cachedNetwork := range cachedIPs {
 		if _, ok := clusterIPs[cachedIP]; !ok && !iscachedIPPreallocated(cachedIP, cachedNetwork, clusterNetworks) {
 			if err := lipam.ipRelease(cachedIP, cachedNetwork, lipam.opts.SyncGracePeriod); err != nil {
@@ -44,7 +44,7 @@ var _ = Describe("Sync routine tests", func() {
 		addNetwork = func(server *LiqoIPAM, cidr string) {
 			prefix, err := netip.ParsePrefix(cidr)
 			Expect(err).ShouldNot(HaveOccurred())
-			_, err = server.networkAcquireSpecific(prefix)
+			_, err = server.networkAcquireSpecific(prefix, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 		}
 
@@ -53,7 +53,7 @@ var _ = Describe("Sync routine tests", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 			prefix, err := netip.ParsePrefix(cidr)
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(server.ipAcquireWithAddr(addr, prefix)).Should(Succeed())
+			Expect(server.ipAcquireWithAddr(addr, prefix, nil)).Should(Succeed())
 		}
 
 		addPreAllocated = func(nw *ipamv1alpha1.Network, preAllocated uint32) *ipamv1alpha1.Network {
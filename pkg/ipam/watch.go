@@ -0,0 +1,151 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net/netip"
+	"time"
+
+	klog "k8s.io/klog/v2"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// watchEventsBuffer is the number of events buffered for each watcher.
+// Watchers falling further behind are disconnected, rather than silently missing events.
+const watchEventsBuffer = 1024
+
+// watcher is a subscriber to the allocation events.
+type watcher struct {
+	// pool is the pool the watcher is interested in. The zero value matches every pool.
+	pool   netip.Prefix
+	events chan *AllocationEvent
+}
+
+// subscribe registers a new watcher for the allocation events of the given pool.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) subscribe(pool netip.Prefix) *watcher {
+	if lipam.watchers == nil {
+		lipam.watchers = make(map[*watcher]struct{})
+	}
+
+	w := &watcher{pool: pool, events: make(chan *AllocationEvent, watchEventsBuffer)}
+	lipam.watchers[w] = struct{}{}
+	return w
+}
+
+// unsubscribe removes the given watcher, if still registered.
+func (lipam *LiqoIPAM) unsubscribe(w *watcher) {
+	lipam.mutex.Lock()
+	defer lipam.mutex.Unlock()
+	delete(lipam.watchers, w)
+}
+
+// notify sends an allocation event to the interested watchers.
+// It must be called with the mutex held, so that events are delivered in the same order the operations are performed.
+func (lipam *LiqoIPAM) notify(eventType AllocationEventType, network netip.Prefix, addr netip.Addr) {
+	lipam.notifyOwned(eventType, network, addr, nil)
+}
+
+// notifyOwned sends an allocation event performed on behalf of the given owner (if any) to the interested watchers.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) notifyOwned(eventType AllocationEventType, network netip.Prefix, addr netip.Addr, owner *ipamcore.Owner) {
+	if len(lipam.watchers) == 0 {
+		return
+	}
+
+	pool := lipam.eventPool(network)
+	event := newAllocationEvent(eventType, pool, network, addr, time.Now())
+	event.Owner = ownerToMessage(owner)
+	lipam.broadcast(pool, event)
+}
+
+// notifyReservation sends a reservation event to the interested watchers.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) notifyReservation(eventType AllocationEventType, reservation ipamcore.Reservation) {
+	if len(lipam.watchers) == 0 {
+		return
+	}
+
+	pool := lipam.eventPool(reservation.Prefix)
+	event := newAllocationEvent(eventType, pool, reservation.Prefix, netip.Addr{}, time.Now())
+	event.ClusterID = reservation.Owner
+	lipam.broadcast(pool, event)
+}
+
+// eventPool returns the pool the given network belongs to, to be included in the events.
+func (lipam *LiqoIPAM) eventPool(network netip.Prefix) netip.Prefix {
+	pool, ok := lipam.IpamCore.PoolOf(network)
+	if !ok {
+		// The event refers to a pool which has just been removed.
+		pool = network
+	}
+	return pool
+}
+
+// broadcast delivers the given event to the watchers interested in its pool, disconnecting the ones too slow.
+func (lipam *LiqoIPAM) broadcast(pool netip.Prefix, event *AllocationEvent) {
+	for w := range lipam.watchers {
+		if !matchesPool(w.pool, pool) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			klog.Warningf("IPAM watcher too slow, disconnecting it")
+			delete(lipam.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+// currentStateEvents returns the acquisition events describing the current allocations of the given pool.
+// It must be called with the mutex held.
+func (lipam *LiqoIPAM) currentStateEvents(pool netip.Prefix) ([]*AllocationEvent, error) {
+	var events []*AllocationEvent
+	now := time.Now()
+
+	for _, network := range lipam.IpamCore.ListNetworks() {
+		networkPool, _ := lipam.IpamCore.PoolOf(network)
+		if !matchesPool(pool, networkPool) {
+			continue
+		}
+		events = append(events, newAllocationEvent(AllocationEventType_NETWORK_ACQUIRED, networkPool, network, netip.Addr{}, now))
+
+		addrs, err := lipam.IpamCore.ListIPs(network)
+		if err != nil {
+			return nil, err
+		}
+		for i := range addrs {
+			events = append(events, newAllocationEvent(AllocationEventType_IP_ACQUIRED, networkPool, network, addrs[i], now))
+		}
+	}
+
+	return events, nil
+}
+
+func newAllocationEvent(eventType AllocationEventType, pool, network netip.Prefix,
+	addr netip.Addr, timestamp time.Time) *AllocationEvent {
+	event := &AllocationEvent{
+		Type:      eventType,
+		Pool:      pool.String(),
+		Cidr:      network.String(),
+		Timestamp: timestamp.UnixNano(),
+	}
+	if addr.IsValid() {
+		event.Ip = addr.String()
+	}
+	return event
+}
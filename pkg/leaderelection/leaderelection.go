@@ -151,6 +151,12 @@ func IsLeader() bool {
 	return leading
 }
 
+// IsLeaderPod checks whether the given pod is labeled as the leader of its deployment.
+func IsLeaderPod(pod *corev1.Pod) bool {
+	value, ok := pod.Labels[leaderLabel]
+	return ok && !strings.EqualFold(value, "false")
+}
+
 // handleLeaderLabel labels the current pod as leader and unlabels eventual old leader.
 func handleLeaderLabel(ctx context.Context, rc *rest.Config, scheme *runtime.Scheme, opts *PodInfo) error {
 	klog.Infof("Leader election: labeling this pod as leader and unlabeling eventual old leader")
@@ -68,6 +68,14 @@ func InitFlags(flagset *pflag.FlagSet, opts *Options) {
 		"The name of the cluster role used by the wireguard gateway servers")
 	flagset.StringVar(&opts.WgGatewayClientClusterRoleName, "wg-gateway-client-cluster-role-name", "liqo-gateway",
 		"The name of the cluster role used by the wireguard gateway clients")
+	flagset.StringVar(&opts.IPsecGatewayServerClusterRoleName, "ipsec-gateway-server-cluster-role-name", "liqo-gateway",
+		"The name of the cluster role used by the IPsec gateway servers")
+	flagset.StringVar(&opts.IPsecGatewayClientClusterRoleName, "ipsec-gateway-client-cluster-role-name", "liqo-gateway",
+		"The name of the cluster role used by the IPsec gateway clients")
+	flagset.StringVar(&opts.PlainGatewayServerClusterRoleName, "plain-gateway-server-cluster-role-name", "liqo-gateway",
+		"The name of the cluster role used by the plain gateway servers")
+	flagset.StringVar(&opts.PlainGatewayClientClusterRoleName, "plain-gateway-client-cluster-role-name", "liqo-gateway",
+		"The name of the cluster role used by the plain gateway clients")
 	flagset.BoolVar(&opts.FabricFullMasqueradeEnabled, "fabric-full-masquerade-enabled", false,
 		"Enable the full masquerade on the fabric network")
 	flagset.BoolVar(&opts.GwmasqbypassEnabled, "gateway-masquerade-bypass-enabled", false,
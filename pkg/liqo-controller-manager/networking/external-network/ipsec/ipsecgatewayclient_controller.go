@@ -0,0 +1,297 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/forge"
+	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
+	mapsutil "github.com/liqotech/liqo/pkg/utils/maps"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// IPsecGatewayClientReconciler manage IPsecGatewayClient lifecycle.
+type IPsecGatewayClientReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	clusterRoleName string
+
+	eventRecorder record.EventRecorder
+}
+
+// NewIPsecGatewayClientReconciler returns a new IPsecGatewayClientReconciler.
+func NewIPsecGatewayClientReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder,
+	clusterRoleName string) *IPsecGatewayClientReconciler {
+	return &IPsecGatewayClientReconciler{
+		Client:          cl,
+		Scheme:          s,
+		clusterRoleName: clusterRoleName,
+
+		eventRecorder: recorder,
+	}
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=ipsecgatewayclients,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=ipsecgatewayclients/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=ipsecgatewayclients/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;create;delete;update
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;delete;create;update;patch
+// +kubectl:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;delete;create;update;patch
+
+// Reconcile manage IPsecGatewayClient lifecycle.
+func (r *IPsecGatewayClientReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	ipsecClient := &networkingv1beta1.IPsecGatewayClient{}
+	if err = r.Get(ctx, req.NamespacedName, ipsecClient); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("IPsec gateway client %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get the IPsec gateway client %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !ipsecClient.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(ipsecClient, consts.ClusterRoleBindingFinalizer) {
+			if err = enutils.DeleteClusterRoleBinding(ctx, r.Client, ipsecClient); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(ipsecClient, consts.ClusterRoleBindingFinalizer)
+			if err = r.Update(ctx, ipsecClient); err != nil {
+				klog.Errorf("Unable to remove finalizer %q from IPsec gateway client %q: %v",
+					consts.ClusterRoleBindingFinalizer, req.NamespacedName, err)
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Resource is deleting and child resources are deleted as well by garbage collector. Nothing to do.
+		return ctrl.Result{}, nil
+	}
+
+	originalIPsecClient := ipsecClient.DeepCopy()
+
+	// Ensure ServiceAccount and ClusterRoleBinding (create or update)
+	if err = enutils.EnsureServiceAccountAndClusterRoleBinding(ctx, r.Client, r.Scheme, &ipsecClient.Spec.Deployment, ipsecClient,
+		r.clusterRoleName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// update if the ipsecClient has been updated
+	if !equality.Semantic.DeepEqual(originalIPsecClient, ipsecClient) {
+		if err := r.Update(ctx, ipsecClient); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// we return here to avoid conflicts
+		return ctrl.Result{}, nil
+	}
+
+	deployNsName := types.NamespacedName{Namespace: ipsecClient.Namespace, Name: forge.GatewayResourceName(ipsecClient.Name)}
+
+	var deploy *appsv1.Deployment
+	var d appsv1.Deployment
+	err = r.Get(ctx, deployNsName, &d)
+	switch {
+	case apierrors.IsNotFound(err):
+		deploy = nil
+	case err != nil:
+		klog.Errorf("error while getting deployment %q: %v", deployNsName, err)
+		return ctrl.Result{}, err
+	default:
+		deploy = &d
+	}
+
+	// Handle status
+	defer func() {
+		newErr := r.Status().Update(ctx, ipsecClient)
+		if newErr != nil {
+			if err != nil {
+				klog.Errorf("Error reconciling the IPsec gateway client %q: %s", req.NamespacedName, err)
+			}
+			klog.Errorf("Unable to update the IPsec gateway client status %q: %s", req.NamespacedName, newErr)
+			err = newErr
+			return
+		}
+
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeNormal, "Reconciled", "IPsec gateway client reconciled")
+	}()
+
+	if err := r.handleInternalEndpointStatus(ctx, ipsecClient, deploy); err != nil {
+		klog.Errorf("Error while handling internal endpoint status: %v", err)
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeWarning, "InternalEndpointStatusFailed",
+			fmt.Sprintf("Failed to handle internal endpoint status: %s", err))
+		return ctrl.Result{}, err
+	}
+
+	// If a secret has not been provided in the gateway specification, the controller is in charge of generating a secret with the IPsec keys.
+	if ipsecClient.Spec.SecretRef.Name == "" {
+		// Ensure IPsec keys secret (create or update)
+		if err = ensureKeysSecret(ctx, r.Client, ipsecClient, gateway.ModeClient); err != nil {
+			r.eventRecorder.Event(ipsecClient, corev1.EventTypeWarning, "KeysSecretEnforcedFailed", "Failed to enforce keys secret")
+			return ctrl.Result{}, err
+		}
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeNormal, "KeysSecretEnforced", "Enforced keys secret")
+	} else {
+		// Check that the secret exists and ensure is correctly labeled
+		if err = enutils.CheckExistingKeysSecret(ctx, r.Client, ipsecClient.Spec.SecretRef.Name, ipsecClient.Namespace, ipsecClient.GetObjectMeta()); err != nil {
+			r.eventRecorder.Event(ipsecClient, corev1.EventTypeWarning, "KeysSecretCheckFailed", fmt.Sprintf("Failed to check keys secret: %s", err))
+			return ctrl.Result{}, err
+		}
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeNormal, "KeysSecretChecked", "Checked keys secret")
+	}
+
+	if err := r.handleSecretRefStatus(ctx, ipsecClient); err != nil {
+		klog.Errorf("Error while handling secret ref status: %v", err)
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeWarning, "SecretRefStatusFailed",
+			fmt.Sprintf("Failed to handle secret ref status: %s", err))
+		return ctrl.Result{}, err
+	}
+
+	// Ensure deployment (create or update)
+	_, err = r.ensureDeployment(ctx, ipsecClient, deployNsName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(ipsecClient, corev1.EventTypeNormal, "DeploymentEnforced", "Enforced deployment")
+
+	// Ensure Metrics (if set)
+	err = enutils.EnsureMetrics(ctx,
+		r.Client, r.Scheme,
+		ipsecClient.Spec.Metrics, ipsecClient)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(ipsecClient, corev1.EventTypeNormal, "MetricsEnforced", "Enforced metrics")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager register the IPsecGatewayClientReconciler to the manager.
+func (r *IPsecGatewayClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlIPsecGatewayClient).
+		For(&networkingv1beta1.IPsecGatewayClient{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ServiceAccount{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enutils.PodEnquerer)).
+		Watches(&rbacv1.ClusterRoleBinding{},
+			handler.EnqueueRequestsFromMapFunc(enutils.ClusterRoleBindingEnquerer)).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(enutils.GatewaySecretEnquerer),
+			builder.WithPredicates(enutils.FilterGatewaySecretsPredicate())).
+		Complete(r)
+}
+
+func (r *IPsecGatewayClientReconciler) ensureDeployment(ctx context.Context, ipsecClient *networkingv1beta1.IPsecGatewayClient,
+	depNsName types.NamespacedName) (*appsv1.Deployment, error) {
+	dep := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      depNsName.Name,
+		Namespace: depNsName.Namespace,
+	}}
+
+	op, err := resource.CreateOrUpdate(ctx, r.Client, &dep, func() error {
+		return r.mutateFnIPsecClientDeployment(&dep, ipsecClient)
+	})
+	if err != nil {
+		klog.Errorf("error while creating/updating deployment %q (operation: %s): %v", depNsName, op, err)
+		return nil, err
+	}
+
+	klog.Infof("Deployment %q correctly enforced (operation: %s)", depNsName, op)
+	return &dep, nil
+}
+
+func (r *IPsecGatewayClientReconciler) mutateFnIPsecClientDeployment(deployment *appsv1.Deployment, ipsecClient *networkingv1beta1.IPsecGatewayClient) error {
+	// Forge metadata
+	mapsutil.SmartMergeLabels(deployment, ipsecClient.Spec.Deployment.Metadata.GetLabels())
+	mapsutil.SmartMergeAnnotations(deployment, ipsecClient.Spec.Deployment.Metadata.GetAnnotations())
+
+	// Forge spec
+	deployment.Spec = ipsecClient.Spec.Deployment.Spec
+
+	if ipsecClient.Status.SecretRef != nil {
+		// When no secret reference is provided, we will need to replace the secret name in the deployment manifest with the auto-generated one.
+		for i := range deployment.Spec.Template.Spec.Volumes {
+			if deployment.Spec.Template.Spec.Volumes[i].Name == ipsecVolumeName {
+				deployment.Spec.Template.Spec.Volumes[i].Secret = &corev1.SecretVolumeSource{
+					SecretName: ipsecClient.Status.SecretRef.Name,
+				}
+				break
+			}
+		}
+	} else {
+		r.eventRecorder.Event(ipsecClient, corev1.EventTypeWarning, "MissingSecretRef", "IPsec keys secret not found")
+	}
+
+	// Set IPsec client as owner of the deployment
+	return controllerutil.SetControllerReference(ipsecClient, deployment, r.Scheme)
+}
+
+func (r *IPsecGatewayClientReconciler) handleSecretRefStatus(ctx context.Context, ipsecClient *networkingv1beta1.IPsecGatewayClient) error {
+	secret, err := enutils.GetGatewaySecret(ctx, r.Client, ipsecClient)
+	switch {
+	case apierrors.IsNotFound(err):
+		ipsecClient.Status.SecretRef = nil
+		return nil
+	case err != nil:
+		return err
+	default:
+		ipsecClient.Status.SecretRef = &corev1.ObjectReference{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		}
+		return nil
+	}
+}
+
+func (r *IPsecGatewayClientReconciler) handleInternalEndpointStatus(ctx context.Context,
+	ipsecClient *networkingv1beta1.IPsecGatewayClient, dep *appsv1.Deployment) error {
+	if dep == nil {
+		ipsecClient.Status.InternalEndpoint = nil
+		return nil
+	}
+
+	ige, err := enutils.ForgeInternalEndpoint(ctx, r.Client, dep)
+	if err != nil {
+		return err
+	}
+
+	ipsecClient.Status.InternalEndpoint = ige
+	return nil
+}
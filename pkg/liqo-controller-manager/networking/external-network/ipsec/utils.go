@@ -0,0 +1,80 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+	ipsectunnel "github.com/liqotech/liqo/pkg/gateway/tunnel/ipsec"
+	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
+)
+
+const (
+	ipsecVolumeName = "ipsec-config"
+)
+
+// ensureKeysSecret ensures the presence of the private and public keys used by the IPsec gateway to derive the
+// security associations, saving them inside a Secret resource.
+func ensureKeysSecret(ctx context.Context, cl client.Client, ipsecObj metav1.Object, mode gateway.Mode) error {
+	var controllerRef metav1.OwnerReference
+	for _, ref := range ipsecObj.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			switch ref.Kind {
+			case networkingv1beta1.GatewayClientKind:
+				controllerRef = ref
+			case networkingv1beta1.GatewayServerKind:
+				controllerRef = ref
+			}
+			break
+		}
+	}
+
+	opts := &gateway.Options{
+		Name:            controllerRef.Name,
+		Namespace:       ipsecObj.GetNamespace(),
+		RemoteClusterID: ipsecObj.GetLabels()[consts.RemoteClusterID],
+		GatewayUID:      string(controllerRef.UID),
+		Mode:            mode,
+	}
+
+	_, err := enutils.GetGatewaySecret(ctx, cl, ipsecObj)
+	switch {
+	case kerrors.IsNotFound(err):
+		pri, err := ipsectunnel.GeneratePrivateKey()
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if err := ipsectunnel.CreateKeysSecret(ctx, cl, opts, pri); err != nil {
+			klog.Error(err)
+			return err
+		}
+		klog.Infof("Keys secret for IPsec gateway %q correctly enforced", ipsecObj.GetName())
+		return nil
+	case err != nil:
+		klog.Error(err)
+		return err
+	default:
+		return nil
+	}
+}
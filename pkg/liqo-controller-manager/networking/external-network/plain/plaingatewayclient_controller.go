@@ -0,0 +1,236 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway/forge"
+	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
+	mapsutil "github.com/liqotech/liqo/pkg/utils/maps"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// PlainGatewayClientReconciler manage PlainGatewayClient lifecycle.
+type PlainGatewayClientReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	clusterRoleName string
+
+	eventRecorder record.EventRecorder
+}
+
+// NewPlainGatewayClientReconciler returns a new PlainGatewayClientReconciler.
+func NewPlainGatewayClientReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder,
+	clusterRoleName string) *PlainGatewayClientReconciler {
+	return &PlainGatewayClientReconciler{
+		Client:          cl,
+		Scheme:          s,
+		clusterRoleName: clusterRoleName,
+
+		eventRecorder: recorder,
+	}
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayclients,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayclients/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayclients/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;delete;create;update;patch
+// +kubectl:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;delete;create;update;patch
+
+// Reconcile manage PlainGatewayClient lifecycle.
+func (r *PlainGatewayClientReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	plainClient := &networkingv1beta1.PlainGatewayClient{}
+	if err = r.Get(ctx, req.NamespacedName, plainClient); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("plain gateway client %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get the plain gateway client %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !plainClient.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(plainClient, consts.ClusterRoleBindingFinalizer) {
+			if err = enutils.DeleteClusterRoleBinding(ctx, r.Client, plainClient); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(plainClient, consts.ClusterRoleBindingFinalizer)
+			if err = r.Update(ctx, plainClient); err != nil {
+				klog.Errorf("Unable to remove finalizer %q from plain gateway client %q: %v",
+					consts.ClusterRoleBindingFinalizer, req.NamespacedName, err)
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Resource is deleting and child resources are deleted as well by garbage collector. Nothing to do.
+		return ctrl.Result{}, nil
+	}
+
+	originalPlainClient := plainClient.DeepCopy()
+
+	// Ensure ServiceAccount and ClusterRoleBinding (create or update)
+	if err = enutils.EnsureServiceAccountAndClusterRoleBinding(ctx, r.Client, r.Scheme, &plainClient.Spec.Deployment, plainClient,
+		r.clusterRoleName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// update if the plainClient has been updated
+	if !equality.Semantic.DeepEqual(originalPlainClient, plainClient) {
+		if err := r.Update(ctx, plainClient); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// we return here to avoid conflicts
+		return ctrl.Result{}, nil
+	}
+
+	deployNsName := types.NamespacedName{Namespace: plainClient.Namespace, Name: forge.GatewayResourceName(plainClient.Name)}
+
+	var deploy *appsv1.Deployment
+	var d appsv1.Deployment
+	err = r.Get(ctx, deployNsName, &d)
+	switch {
+	case apierrors.IsNotFound(err):
+		deploy = nil
+	case err != nil:
+		klog.Errorf("error while getting deployment %q: %v", deployNsName, err)
+		return ctrl.Result{}, err
+	default:
+		deploy = &d
+	}
+
+	// Handle status
+	defer func() {
+		newErr := r.Status().Update(ctx, plainClient)
+		if newErr != nil {
+			if err != nil {
+				klog.Errorf("Error reconciling the plain gateway client %q: %s", req.NamespacedName, err)
+			}
+			klog.Errorf("Unable to update the plain gateway client status %q: %s", req.NamespacedName, newErr)
+			err = newErr
+			return
+		}
+
+		r.eventRecorder.Event(plainClient, corev1.EventTypeNormal, "Reconciled", "plain gateway client reconciled")
+	}()
+
+	if err := r.handleInternalEndpointStatus(ctx, plainClient, deploy); err != nil {
+		klog.Errorf("Error while handling internal endpoint status: %v", err)
+		r.eventRecorder.Event(plainClient, corev1.EventTypeWarning, "InternalEndpointStatusFailed",
+			fmt.Sprintf("Failed to handle internal endpoint status: %s", err))
+		return ctrl.Result{}, err
+	}
+
+	// Ensure deployment (create or update)
+	_, err = r.ensureDeployment(ctx, plainClient, deployNsName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(plainClient, corev1.EventTypeNormal, "DeploymentEnforced", "Enforced deployment")
+
+	// Ensure Metrics (if set)
+	err = enutils.EnsureMetrics(ctx,
+		r.Client, r.Scheme,
+		plainClient.Spec.Metrics, plainClient)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(plainClient, corev1.EventTypeNormal, "MetricsEnforced", "Enforced metrics")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager register the PlainGatewayClientReconciler to the manager.
+func (r *PlainGatewayClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPlainGatewayClient).
+		For(&networkingv1beta1.PlainGatewayClient{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ServiceAccount{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enutils.PodEnquerer)).
+		Watches(&rbacv1.ClusterRoleBinding{},
+			handler.EnqueueRequestsFromMapFunc(enutils.ClusterRoleBindingEnquerer)).
+		Complete(r)
+}
+
+func (r *PlainGatewayClientReconciler) ensureDeployment(ctx context.Context, plainClient *networkingv1beta1.PlainGatewayClient,
+	depNsName types.NamespacedName) (*appsv1.Deployment, error) {
+	dep := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      depNsName.Name,
+		Namespace: depNsName.Namespace,
+	}}
+
+	op, err := resource.CreateOrUpdate(ctx, r.Client, &dep, func() error {
+		return r.mutateFnPlainClientDeployment(&dep, plainClient)
+	})
+	if err != nil {
+		klog.Errorf("error while creating/updating deployment %q (operation: %s): %v", depNsName, op, err)
+		return nil, err
+	}
+
+	klog.Infof("Deployment %q correctly enforced (operation: %s)", depNsName, op)
+	return &dep, nil
+}
+
+func (r *PlainGatewayClientReconciler) mutateFnPlainClientDeployment(deployment *appsv1.Deployment, plainClient *networkingv1beta1.PlainGatewayClient) error {
+	// Forge metadata
+	mapsutil.SmartMergeLabels(deployment, plainClient.Spec.Deployment.Metadata.GetLabels())
+	mapsutil.SmartMergeAnnotations(deployment, plainClient.Spec.Deployment.Metadata.GetAnnotations())
+
+	// Forge spec
+	deployment.Spec = plainClient.Spec.Deployment.Spec
+
+	// Set plain client as owner of the deployment
+	return controllerutil.SetControllerReference(plainClient, deployment, r.Scheme)
+}
+
+func (r *PlainGatewayClientReconciler) handleInternalEndpointStatus(ctx context.Context,
+	plainClient *networkingv1beta1.PlainGatewayClient, dep *appsv1.Deployment) error {
+	if dep == nil {
+		plainClient.Status.InternalEndpoint = nil
+		return nil
+	}
+
+	ige, err := enutils.ForgeInternalEndpoint(ctx, r.Client, dep)
+	if err != nil {
+		return err
+	}
+
+	plainClient.Status.InternalEndpoint = ige
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway/forge"
+	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
+	mapsutil "github.com/liqotech/liqo/pkg/utils/maps"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// PlainGatewayServerReconciler manage PlainGatewayServer lifecycle.
+type PlainGatewayServerReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	clusterRoleName string
+
+	eventRecorder record.EventRecorder
+}
+
+// NewPlainGatewayServerReconciler returns a new PlainGatewayServerReconciler.
+func NewPlainGatewayServerReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder,
+	clusterRoleName string) *PlainGatewayServerReconciler {
+	return &PlainGatewayServerReconciler{
+		Client:          cl,
+		Scheme:          s,
+		clusterRoleName: clusterRoleName,
+
+		eventRecorder: recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayservers,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayservers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=plaingatewayservers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;delete;create;update;patch
+// +kubectl:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;delete;create;update;patch
+
+// Reconcile manage PlainGatewayServer lifecycle.
+func (r *PlainGatewayServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	plainServer := &networkingv1beta1.PlainGatewayServer{}
+	if err = r.Get(ctx, req.NamespacedName, plainServer); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("plain gateway server %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get the plain gateway server %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !plainServer.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(plainServer, consts.ClusterRoleBindingFinalizer) {
+			if err = enutils.DeleteClusterRoleBinding(ctx, r.Client, plainServer); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			controllerutil.RemoveFinalizer(plainServer, consts.ClusterRoleBindingFinalizer)
+			if err = r.Update(ctx, plainServer); err != nil {
+				klog.Errorf("Unable to remove finalizer %q from plain gateway server %q: %v",
+					consts.ClusterRoleBindingFinalizer, req.NamespacedName, err)
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Resource is deleting and child resources are deleted as well by garbage collector. Nothing to do.
+		return ctrl.Result{}, nil
+	}
+
+	originalPlainServer := plainServer.DeepCopy()
+
+	// Ensure ServiceAccount and ClusterRoleBinding (create or update)
+	if err = enutils.EnsureServiceAccountAndClusterRoleBinding(ctx, r.Client, r.Scheme, &plainServer.Spec.Deployment, plainServer,
+		r.clusterRoleName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// update if the plainServer has been updated
+	if !equality.Semantic.DeepEqual(originalPlainServer, plainServer) {
+		if err := r.Update(ctx, plainServer); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// we return here to avoid conflicts
+		return ctrl.Result{}, nil
+	}
+
+	deployNsName := types.NamespacedName{Namespace: plainServer.Namespace, Name: forge.GatewayResourceName(plainServer.Name)}
+	svcNsName := types.NamespacedName{Namespace: plainServer.Namespace, Name: forge.GatewayResourceName(plainServer.Name)}
+
+	var deploy *appsv1.Deployment
+	var d appsv1.Deployment
+	err = r.Get(ctx, deployNsName, &d)
+	switch {
+	case apierrors.IsNotFound(err):
+		deploy = nil
+	case err != nil:
+		klog.Errorf("Unable to get the deployment %q: %v", deployNsName, err)
+		return ctrl.Result{}, err
+	default:
+		deploy = &d
+	}
+
+	// Handle status
+	defer func() {
+		newErr := r.Status().Update(ctx, plainServer)
+		if newErr != nil {
+			if err != nil {
+				klog.Errorf("Error reconciling the plain gateway server %q: %s", req.NamespacedName, err)
+			}
+			klog.Errorf("Unable to update the plain gateway server status %q: %s", req.NamespacedName, newErr)
+			err = newErr
+			return
+		}
+
+		r.eventRecorder.Event(plainServer, corev1.EventTypeNormal, "Reconciled", "plain gateway server reconciled")
+	}()
+
+	if err := r.handleEndpointStatus(ctx, plainServer, svcNsName, deploy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.handleInternalEndpointStatus(ctx, plainServer, svcNsName, deploy); err != nil {
+		klog.Errorf("Error while handling internal endpoint status: %v", err)
+		r.eventRecorder.Event(plainServer, corev1.EventTypeWarning, "InternalEndpointStatusFailed",
+			fmt.Sprintf("Failed to handle internal endpoint status: %s", err))
+		return ctrl.Result{}, err
+	}
+
+	// Ensure deployment (create or update)
+	_, err = r.ensureDeployment(ctx, plainServer, deployNsName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(plainServer, corev1.EventTypeNormal, "DeploymentEnforced", "Enforced deployment")
+
+	// Ensure service (create or update)
+	_, err = r.ensureService(ctx, plainServer, svcNsName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(plainServer, corev1.EventTypeNormal, "ServiceEnforced", "Enforced service")
+
+	// Ensure Metrics (if set)
+	err = enutils.EnsureMetrics(ctx,
+		r.Client, r.Scheme,
+		plainServer.Spec.Metrics, plainServer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.eventRecorder.Event(plainServer, corev1.EventTypeNormal, "MetricsEnforced", "Enforced metrics")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager register the PlainGatewayServerReconciler to the manager.
+func (r *PlainGatewayServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPlainGatewayServer).
+		For(&networkingv1beta1.PlainGatewayServer{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ServiceAccount{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enutils.PodEnquerer)).
+		Watches(&rbacv1.ClusterRoleBinding{},
+			handler.EnqueueRequestsFromMapFunc(enutils.ClusterRoleBindingEnquerer)).
+		Complete(r)
+}
+
+func (r *PlainGatewayServerReconciler) ensureDeployment(ctx context.Context, plainServer *networkingv1beta1.PlainGatewayServer,
+	depNsName types.NamespacedName) (*appsv1.Deployment, error) {
+	dep := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      depNsName.Name,
+		Namespace: depNsName.Namespace,
+	}}
+
+	op, err := resource.CreateOrUpdate(ctx, r.Client, &dep, func() error {
+		return r.mutateFnPlainServerDeployment(&dep, plainServer)
+	})
+	if err != nil {
+		klog.Errorf("error while creating/updating deployment %q (operation: %s): %v", depNsName, op, err)
+		return nil, err
+	}
+
+	klog.Infof("Deployment %q correctly enforced (operation: %s)", depNsName, op)
+	return &dep, nil
+}
+
+func (r *PlainGatewayServerReconciler) ensureService(ctx context.Context, plainServer *networkingv1beta1.PlainGatewayServer,
+	svcNsName types.NamespacedName) (*corev1.Service, error) {
+	svc := corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      svcNsName.Name,
+		Namespace: svcNsName.Namespace,
+	}}
+
+	op, err := resource.CreateOrUpdate(ctx, r.Client, &svc, func() error {
+		return r.mutateFnPlainServerService(&svc, plainServer)
+	})
+	if err != nil {
+		klog.Errorf("error while creating/updating service %q (operation: %s): %v", svcNsName, op, err)
+		return nil, err
+	}
+
+	klog.Infof("Service %q correctly enforced (operation: %s)", svcNsName, op)
+	return &svc, nil
+}
+
+func (r *PlainGatewayServerReconciler) mutateFnPlainServerDeployment(deployment *appsv1.Deployment, plainServer *networkingv1beta1.PlainGatewayServer) error {
+	// Forge metadata
+	mapsutil.SmartMergeLabels(deployment, plainServer.Spec.Deployment.Metadata.GetLabels())
+	mapsutil.SmartMergeAnnotations(deployment, plainServer.Spec.Deployment.Metadata.GetAnnotations())
+
+	// Forge spec
+	deployment.Spec = plainServer.Spec.Deployment.Spec
+
+	// Set plain server as owner of the deployment
+	return controllerutil.SetControllerReference(plainServer, deployment, r.Scheme)
+}
+
+func (r *PlainGatewayServerReconciler) mutateFnPlainServerService(service *corev1.Service, plainServer *networkingv1beta1.PlainGatewayServer) error {
+	// Forge metadata
+	mapsutil.SmartMergeLabels(service, plainServer.Spec.Service.Metadata.GetLabels())
+	mapsutil.SmartMergeAnnotations(service, plainServer.Spec.Service.Metadata.GetAnnotations())
+
+	// Forge spec
+	serviceClassName := service.Spec.LoadBalancerClass
+	service.Spec = plainServer.Spec.Service.Spec
+	if plainServer.Spec.Service.Spec.LoadBalancerClass == nil {
+		service.Spec.LoadBalancerClass = serviceClassName
+	}
+
+	// Set plain server as owner of the service
+	return controllerutil.SetControllerReference(plainServer, service, r.Scheme)
+}
+
+func (r *PlainGatewayServerReconciler) handleEndpointStatus(ctx context.Context, plainServer *networkingv1beta1.PlainGatewayServer,
+	svcNsName types.NamespacedName, dep *appsv1.Deployment) error {
+	if dep == nil {
+		plainServer.Status.Endpoint = nil
+		return nil
+	}
+
+	// Handle plain server Service
+	var service corev1.Service
+	err := r.Get(ctx, svcNsName, &service)
+	if err != nil {
+		klog.Error(err) // raise an error also if service NotFound
+		return err
+	}
+
+	// Put service endpoint in plain server status
+	endpointStatus, err := enutils.ForgeEndpointStatus(ctx, r.Client, &service, dep)
+	if err != nil {
+		plainServer.Status.Endpoint = nil // we empty the endpoint status to avoid misaligned spec and status
+		return err
+	}
+
+	plainServer.Status.Endpoint = endpointStatus
+
+	return nil
+}
+
+func (r *PlainGatewayServerReconciler) handleInternalEndpointStatus(ctx context.Context, plainServer *networkingv1beta1.PlainGatewayServer,
+	svcNsName types.NamespacedName, dep *appsv1.Deployment) error {
+	if dep == nil {
+		plainServer.Status.InternalEndpoint = nil
+		return nil
+	}
+
+	var service corev1.Service
+	err := r.Get(ctx, svcNsName, &service)
+	if err != nil {
+		klog.Error(err) // raise an error also if service NotFound
+		return err
+	}
+
+	_, ige, err := enutils.ForgeEndpointStatusNodePort(ctx, r.Client, &service, dep)
+	if err != nil {
+		return err
+	}
+
+	plainServer.Status.InternalEndpoint = ige
+	return nil
+}
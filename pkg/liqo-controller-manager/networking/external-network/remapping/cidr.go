@@ -27,8 +27,8 @@ import (
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
-	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
 	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
@@ -46,16 +46,9 @@ const (
 // CreateOrUpdateNatMappingCIDR creates or updates the NAT mapping for a CIDR type.
 func CreateOrUpdateNatMappingCIDR(ctx context.Context, cl client.Client, opts *Options,
 	cfg *networkingv1beta1.Configuration, scheme *runtime.Scheme, cidrtype CIDRType) error {
-	var tableCIDRName string
-	switch cidrtype {
-	case PodCIDR:
-		tableCIDRName = TablePodCIDRName
-	case ExternalCIDR:
-		tableCIDRName = TableExternalCIDRName
-	}
 	fwcfg := &networkingv1beta1.FirewallConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s", cfg.Name, tableCIDRName),
+			Name:      fmt.Sprintf("%s-%s", cfg.Name, forgeTableCIDRBaseName(cidrtype)),
 			Namespace: cfg.Namespace,
 		},
 	}
@@ -80,8 +73,12 @@ func mutateCIDRFirewallConfiguration(fwcfg *networkingv1beta1.FirewallConfigurat
 		if cfg.Labels == nil {
 			return fmt.Errorf("configuration %q has no labels", cfg.Name)
 		}
-		remoteClusterID := cfg.Labels[string(consts.RemoteClusterID)]
-		fwcfg.SetLabels(ForgeFirewallTargetLabels(remoteClusterID))
+		// The traffic towards clusters reached through a transit cluster is handled by the gateway towards the latter.
+		gatewayClusterID, err := netutils.GetGatewayClusterID(cfg)
+		if err != nil {
+			return err
+		}
+		fwcfg.SetLabels(ForgeFirewallTargetLabels(string(gatewayClusterID)))
 		fwcfg.Spec = forgeCIDRFirewallConfigurationSpec(cfg, opts, cidrtype)
 		return controllerutil.SetOwnerReference(cfg, fwcfg, scheme)
 	}
@@ -89,13 +86,7 @@ func mutateCIDRFirewallConfiguration(fwcfg *networkingv1beta1.FirewallConfigurat
 
 func forgeCIDRFirewallConfigurationSpec(cfg *networkingv1beta1.Configuration, opts *Options,
 	cidrtype CIDRType) networkingv1beta1.FirewallConfigurationSpec {
-	var tableCIDRName string
-	switch cidrtype {
-	case PodCIDR:
-		tableCIDRName = TablePodCIDRName
-	case ExternalCIDR:
-		tableCIDRName = TableExternalCIDRName
-	}
+	tableCIDRName := forgeTableCIDRName(cfg, cidrtype)
 
 	return networkingv1beta1.FirewallConfigurationSpec{
 		Table: firewall.Table{
@@ -109,6 +100,27 @@ func forgeCIDRFirewallConfigurationSpec(cfg *networkingv1beta1.Configuration, op
 	}
 }
 
+// forgeTableCIDRBaseName returns the base name of the table remapping the given CIDR type.
+func forgeTableCIDRBaseName(cidrtype CIDRType) string {
+	switch cidrtype {
+	case PodCIDR:
+		return TablePodCIDRName
+	case ExternalCIDR:
+		return TableExternalCIDRName
+	}
+	return ""
+}
+
+// forgeTableCIDRName returns the name of the table remapping the given CIDR type. The tables of the clusters
+// reached through a transit cluster are configured in the same gateway of the latter, hence they are suffixed
+// with the name of the Configuration.
+func forgeTableCIDRName(cfg *networkingv1beta1.Configuration, cidrtype CIDRType) string {
+	if netutils.IsTransitConfiguration(cfg) {
+		return fmt.Sprintf("%s-%s", forgeTableCIDRBaseName(cidrtype), cfg.Name)
+	}
+	return forgeTableCIDRBaseName(cidrtype)
+}
+
 func forgeCIDRFirewallConfigurationDNATChain(cfg *networkingv1beta1.Configuration, opts *Options, cidrtype CIDRType) firewall.Chain {
 	return firewall.Chain{
 		Name:     &DNATChainName,
@@ -26,11 +26,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	configuration "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/configuration"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
 	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
 )
 
@@ -76,6 +78,11 @@ func (r *RemappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 	klog.V(4).Infof("Reconciling configuration %q", req.NamespacedName)
 
+	// The configurations enqueued because of the forwarding ones may not be configured yet.
+	if !netutils.IsConfigurationStatusSet(conf.Status) {
+		return ctrl.Result{}, nil
+	}
+
 	if cidrutils.GetPrimary(conf.Spec.Remote.CIDR.Pod) != cidrutils.GetPrimary(conf.Status.Remote.CIDR.Pod) {
 		if err := CreateOrUpdateNatMappingCIDR(ctx, r.Client, r.Options, conf,
 			r.Scheme, PodCIDR); err != nil {
@@ -90,6 +97,10 @@ func (r *RemappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	if err := EnforceTransitNat(ctx, r.Client, conf, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -105,5 +116,6 @@ func (r *RemappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlConfigurationRemapping).
 		For(&networkingv1beta1.Configuration{}, builder.WithPredicates(filterByLabelsPredicate)).
+		Watches(&networkingv1beta1.Configuration{}, handler.EnqueueRequestsFromMapFunc(netutils.ForwardingConfigurationEnqueuer(r.Client))).
 		Complete(r)
 }
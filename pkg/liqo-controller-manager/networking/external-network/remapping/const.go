@@ -23,6 +23,8 @@ var (
 	TableIPMappingGwName = "remap-ipmapping-gw"
 	// TableIPMappingFabricName is the name of the table for the IP mapping.
 	TableIPMappingFabricName = "remap-ipmapping-fabric"
+	// TableTransitName is the name of the table for the transit traffic.
+	TableTransitName = "remap-transit"
 
 	// DNATChainName is the name of the chain for the output traffic.
 	DNATChainName = "outgoing"
@@ -0,0 +1,150 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remapping
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	"github.com/liqotech/liqo/pkg/fabric"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
+	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// EnforceTransitNat creates or updates the NAT configuration of the traffic forwarded between the remote cluster
+// and the other remote clusters with the forwarding enabled, or deletes it if the forwarding is disabled.
+// The transit traffic traverses the nodes, moving from the gateway towards the source cluster to the one towards
+// the destination cluster: its source is preserved, rather than being masqueraded as the traffic originated by the nodes,
+// so that it can be routed back (and it is possibly remapped) by the destination cluster.
+func EnforceTransitNat(ctx context.Context, cl client.Client, cfg *networkingv1beta1.Configuration, scheme *runtime.Scheme) error {
+	fwcfg := &networkingv1beta1.FirewallConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      forgeTransitFirewallConfigurationName(cfg),
+			Namespace: cfg.Namespace,
+		},
+	}
+
+	if !netutils.IsForwardingConfiguration(cfg) || !netutils.IsConfigurationStatusSet(cfg.Status) {
+		if err := cl.Delete(ctx, fwcfg); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("unable to delete the transit firewall configuration %q: %w", fwcfg.Name, err)
+		}
+		return nil
+	}
+
+	forwarding, err := netutils.ListForwardingConfigurations(ctx, cl, client.ObjectKeyFromObject(cfg))
+	if err != nil {
+		return err
+	}
+
+	if _, err := resource.CreateOrUpdate(ctx, cl, fwcfg, func() error {
+		fwcfg.SetLabels(fabric.ForgeFirewallTargetLabels())
+		fwcfg.Spec = forgeTransitFirewallConfigurationSpec(cfg, forwarding)
+		return controllerutil.SetOwnerReference(cfg, fwcfg, scheme)
+	}); err != nil {
+		return fmt.Errorf("unable to enforce the transit firewall configuration %q: %w", fwcfg.Name, err)
+	}
+
+	klog.V(4).Infof("Transit firewall configuration %q enforced", fwcfg.Name)
+	return nil
+}
+
+func forgeTransitFirewallConfigurationName(cfg *networkingv1beta1.Configuration) string {
+	return fmt.Sprintf("%s-transit", cfg.Name)
+}
+
+func forgeTransitFirewallConfigurationSpec(cfg *networkingv1beta1.Configuration,
+	forwarding []networkingv1beta1.Configuration) networkingv1beta1.FirewallConfigurationSpec {
+	return networkingv1beta1.FirewallConfigurationSpec{
+		Table: firewall.Table{
+			Name:   ptr.To(fmt.Sprintf("%s-%s", TableTransitName, cfg.Name)),
+			Family: ptr.To(firewall.TableFamilyIPv4),
+			Chains: []firewall.Chain{
+				{
+					Name:   ptr.To(PostroutingChainName),
+					Policy: ptr.To(firewall.ChainPolicyAccept),
+					Type:   firewall.ChainTypeNAT,
+					Hook:   &firewall.ChainHookPostrouting,
+					// The chain precedes the one masquerading the traffic towards the remote clusters.
+					Priority: ptr.To(firewall.ChainPriorityNATSource - 2),
+					Rules: firewall.RulesSet{
+						NatRules: forgeTransitNatRules(cfg, forwarding),
+					},
+				},
+			},
+		},
+	}
+}
+
+// forgeTransitNatRules forges the rules preserving the source of the traffic from the remote cluster
+// towards the other remote clusters with the forwarding enabled.
+func forgeTransitNatRules(cfg *networkingv1beta1.Configuration, forwarding []networkingv1beta1.Configuration) []firewall.NatRule {
+	// Sort the configurations to prevent useless updates.
+	forwarding = slices.Clone(forwarding)
+	slices.SortFunc(forwarding, func(a, b networkingv1beta1.Configuration) int { return strings.Compare(a.Name, b.Name) })
+
+	sources := map[string]*networkingv1beta1.CIDR{
+		"pod": cidrutils.GetPrimary(cfg.Status.Remote.CIDR.Pod),
+		"ext": cidrutils.GetPrimary(cfg.Status.Remote.CIDR.External),
+	}
+
+	natrules := []firewall.NatRule{}
+	for i := range forwarding {
+		if !netutils.IsConfigurationStatusSet(forwarding[i].Status) {
+			continue
+		}
+		destinations := map[string]*networkingv1beta1.CIDR{
+			"pod": cidrutils.GetPrimary(forwarding[i].Status.Remote.CIDR.Pod),
+			"ext": cidrutils.GetPrimary(forwarding[i].Status.Remote.CIDR.External),
+		}
+		for _, srcType := range []string{"pod", "ext"} {
+			for _, dstType := range []string{"pod", "ext"} {
+				natrules = append(natrules, firewall.NatRule{
+					Name:    ptr.To(fmt.Sprintf("transit-%s-%s-%s", forwarding[i].Name, srcType, dstType)),
+					NatType: firewall.NatTypeSource,
+					To:      ptr.To(sources[srcType].String()),
+					Match: []firewall.Match{
+						{
+							Op: firewall.MatchOperationEq,
+							IP: &firewall.MatchIP{
+								Value:    sources[srcType].String(),
+								Position: firewall.MatchPositionSrc,
+							},
+						},
+						{
+							Op: firewall.MatchOperationEq,
+							IP: &firewall.MatchIP{
+								Value:    destinations[dstType].String(),
+								Position: firewall.MatchPositionDst,
+							},
+						},
+					},
+				})
+			}
+		}
+	}
+	return natrules
+}
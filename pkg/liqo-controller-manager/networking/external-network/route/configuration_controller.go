@@ -34,6 +34,7 @@ import (
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	configuration "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/configuration"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
 	"github.com/liqotech/liqo/pkg/utils"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 )
@@ -102,6 +103,10 @@ func (r *ConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&networkingv1beta1.GatewayClient{},
 			handler.EnqueueRequestsFromMapFunc(r.configurationEnqueuerByRemoteID()),
 		).
+		Watches(
+			&networkingv1beta1.Configuration{},
+			handler.EnqueueRequestsFromMapFunc(netutils.ForwardingConfigurationEnqueuer(r.Client)),
+		).
 		Complete(r)
 }
 
@@ -122,6 +127,17 @@ func (r *ConfigurationReconciler) configurationEnqueuerByRemoteID() handler.MapF
 			klog.Errorf("unable to get the configuration for cluster %s: %s", remoteID, err)
 			return nil
 		}
-		return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(cfg)}}
+		requests := []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(cfg)}}
+
+		// The configurations of the clusters reached through the remote one depend on its gateway as well.
+		transit, err := netutils.ListTransitConfigurations(ctx, r.Client, remoteID)
+		if err != nil {
+			klog.Errorf("unable to list the transit configurations for cluster %s: %s", remoteID, err)
+			return requests
+		}
+		for i := range transit {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&transit[i])})
+		}
+		return requests
 	}
 }
@@ -17,6 +17,8 @@ package route
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -30,6 +32,7 @@ import (
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/gateway"
 	"github.com/liqotech/liqo/pkg/gateway/tunnel"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
 	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	"github.com/liqotech/liqo/pkg/utils/resource"
@@ -55,12 +58,13 @@ func GetRemoteClusterID(cfg *networkingv1beta1.Configuration) (liqov1beta1.Clust
 // enforceRouteConfigurationPresence creates or updates a RouteConfiguration object.
 func enforceRouteConfigurationPresence(ctx context.Context, cl client.Client, scheme *runtime.Scheme,
 	cfg *networkingv1beta1.Configuration) error {
-	remoteClusterID, err := GetRemoteClusterID(cfg)
+	// The traffic towards clusters reached through a transit cluster is handled by the gateway towards the latter.
+	gatewayClusterID, err := netutils.GetGatewayClusterID(cfg)
 	if err != nil {
 		return err
 	}
 
-	mode, err := GetGatewayMode(ctx, cl, remoteClusterID)
+	mode, err := GetGatewayMode(ctx, cl, gatewayClusterID)
 	if err != nil {
 		return err
 	}
@@ -86,16 +90,23 @@ func enforceRouteConfigurationPresence(ctx context.Context, cl client.Client, sc
 		return err
 	}
 
+	var forwarding []networkingv1beta1.Configuration
+	if netutils.IsForwardingConfiguration(cfg) {
+		if forwarding, err = netutils.ListForwardingConfigurations(ctx, cl, client.ObjectKeyFromObject(cfg)); err != nil {
+			return err
+		}
+	}
+
 	_, err = resource.CreateOrUpdate(ctx, cl, routecfg,
-		forgeMutateRouteConfiguration(cfg, routecfg, scheme, remoteClusterID, remoteInterfaceIP, internalNodes))
+		forgeMutateRouteConfiguration(cfg, routecfg, scheme, gatewayClusterID, remoteInterfaceIP, internalNodes, forwarding))
 	return err
 }
 
 // forgeMutateRouteConfiguration mutates a RouteConfiguration object.
 func forgeMutateRouteConfiguration(cfg *networkingv1beta1.Configuration,
 	routecfg *networkingv1beta1.RouteConfiguration, scheme *runtime.Scheme,
-	remoteClusterID liqov1beta1.ClusterID,
-	remoteInterfaceIP string, internalNodes *networkingv1beta1.InternalNodeList) func() error {
+	gatewayClusterID liqov1beta1.ClusterID, remoteInterfaceIP string, internalNodes *networkingv1beta1.InternalNodeList,
+	forwarding []networkingv1beta1.Configuration) func() error {
 	return func() error {
 		var err error
 
@@ -103,7 +114,7 @@ func forgeMutateRouteConfiguration(cfg *networkingv1beta1.Configuration,
 			return err
 		}
 
-		routecfg.ObjectMeta.Labels = gateway.ForgeRouteExternalTargetLabels(string(remoteClusterID))
+		routecfg.ObjectMeta.Labels = gateway.ForgeRouteExternalTargetLabels(string(gatewayClusterID))
 
 		routecfg.Spec = networkingv1beta1.RouteConfigurationSpec{
 			Table: networkingv1beta1.Table{
@@ -136,10 +147,59 @@ func forgeMutateRouteConfiguration(cfg *networkingv1beta1.Configuration,
 					},
 				}...)
 		}
+
+		routecfg.Spec.Table.Rules = append(routecfg.Spec.Table.Rules, forgeTransitRules(forwarding, internalNodes)...)
 		return nil
 	}
 }
 
+// forgeTransitRules forges the rules forwarding the traffic received through the tunnel towards the (remapped) CIDRs
+// of the given remote clusters to the nodes, which route it to the corresponding gateways.
+func forgeTransitRules(forwarding []networkingv1beta1.Configuration,
+	internalNodes *networkingv1beta1.InternalNodeList) []networkingv1beta1.Rule {
+	if len(internalNodes.Items) == 0 {
+		return nil
+	}
+
+	// Sort the nodes and the configurations to prevent useless updates.
+	nodes := slices.Clone(internalNodes.Items)
+	slices.SortFunc(nodes, func(a, b networkingv1beta1.InternalNode) int { return strings.Compare(a.Name, b.Name) })
+	forwarding = slices.Clone(forwarding)
+	slices.SortFunc(forwarding, func(a, b networkingv1beta1.Configuration) int { return strings.Compare(a.Name, b.Name) })
+
+	var nextHops []networkingv1beta1.NextHop
+	for i := range nodes {
+		nextHops = append(nextHops, networkingv1beta1.NextHop{
+			Gw:     ptr.To(nodes[i].Spec.Interface.Node.IP),
+			Dev:    ptr.To(nodes[i].Spec.Interface.Gateway.Name),
+			Onlink: ptr.To(true),
+		})
+	}
+
+	var rules []networkingv1beta1.Rule
+	for i := range forwarding {
+		if !netutils.IsConfigurationStatusSet(forwarding[i].Status) {
+			continue
+		}
+		for _, dst := range []*networkingv1beta1.CIDR{
+			cidrutils.GetPrimary(forwarding[i].Status.Remote.CIDR.Pod),
+			cidrutils.GetPrimary(forwarding[i].Status.Remote.CIDR.External),
+		} {
+			route := networkingv1beta1.Route{Dst: dst, Gw: nextHops[0].Gw, Dev: nextHops[0].Dev, Onlink: ptr.To(true)}
+			if len(nextHops) > 1 {
+				// Balance the transit traffic across the nodes.
+				route = networkingv1beta1.Route{Dst: dst, NextHops: nextHops}
+			}
+			rules = append(rules, networkingv1beta1.Rule{
+				Iif:    ptr.To(tunnel.TunnelInterfaceName),
+				Dst:    dst,
+				Routes: []networkingv1beta1.Route{route},
+			})
+		}
+	}
+	return rules
+}
+
 // GetGatewayMode returns the mode of the Gateway related to the Configuration.
 func GetGatewayMode(ctx context.Context, cl client.Client, remoteClusterID liqov1beta1.ClusterID) (gateway.Mode, error) {
 	gwserver, gwclient, err := getters.GetGatewaysByClusterID(ctx, cl, remoteClusterID)
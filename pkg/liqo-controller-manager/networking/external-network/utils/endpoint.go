@@ -0,0 +1,217 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/concurrent"
+	"github.com/liqotech/liqo/pkg/utils"
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
+)
+
+// ForgeEndpointStatus forges the endpoint status of a gateway server, depending on the type of the service exposing it.
+func ForgeEndpointStatus(ctx context.Context, cl client.Client, service *corev1.Service,
+	dep *appsv1.Deployment) (*networkingv1beta1.EndpointStatus, error) {
+	switch service.Spec.Type {
+	case corev1.ServiceTypeClusterIP:
+		return forgeEndpointStatusClusterIP(service)
+	case corev1.ServiceTypeNodePort:
+		endpointStatus, _, err := ForgeEndpointStatusNodePort(ctx, cl, service, dep)
+		return endpointStatus, err
+	case corev1.ServiceTypeLoadBalancer:
+		return forgeEndpointStatusLoadBalancer(service)
+	default:
+		err := fmt.Errorf("service type %q not supported for gateway server Service %s/%s", service.Spec.Type, service.Namespace, service.Name)
+		klog.Error(err)
+		return nil, err
+	}
+}
+
+func forgeEndpointStatusClusterIP(service *corev1.Service) (*networkingv1beta1.EndpointStatus, error) {
+	if len(service.Spec.Ports) == 0 {
+		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
+		klog.Error(err)
+		return nil, err
+	}
+
+	port := service.Spec.Ports[0].Port
+	protocol := &service.Spec.Ports[0].Protocol
+	addresses := service.Spec.ClusterIPs
+
+	return &networkingv1beta1.EndpointStatus{
+		Protocol:  protocol,
+		Port:      port,
+		Addresses: addresses,
+	}, nil
+}
+
+// ForgeEndpointStatusNodePort forges the endpoint status of a gateway server exposed through a NodePort service,
+// together with the internal endpoint of the active gateway pod.
+func ForgeEndpointStatusNodePort(ctx context.Context, cl client.Client, service *corev1.Service,
+	dep *appsv1.Deployment) (*networkingv1beta1.EndpointStatus, *networkingv1beta1.InternalGatewayEndpoint, error) {
+	if len(service.Spec.Ports) == 0 {
+		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
+		klog.Error(err)
+		return nil, nil, err
+	}
+
+	port := service.Spec.Ports[0].NodePort
+	protocol := &service.Spec.Ports[0].Protocol
+
+	ige, err := ForgeInternalEndpoint(ctx, cl, dep)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node := &corev1.Node{}
+	err = cl.Get(ctx, types.NamespacedName{Name: *ige.Node}, node)
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Unable to get node %q: %v", *ige.Node, err)
+		return nil, nil, err
+	}
+
+	addresses := make([]string, 1)
+	if utils.IsNodeReady(node) {
+		if addresses[0], err = utils.GetAddress(node); err != nil {
+			klog.Errorf("Unable to get address of node %q: %v", *ige.Node, err)
+			return nil, nil, err
+		}
+	}
+
+	return &networkingv1beta1.EndpointStatus{
+		Protocol:  protocol,
+		Port:      port,
+		Addresses: addresses,
+	}, ige, nil
+}
+
+func forgeEndpointStatusLoadBalancer(service *corev1.Service) (*networkingv1beta1.EndpointStatus, error) {
+	if len(service.Spec.Ports) == 0 {
+		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
+		klog.Error(err)
+		return nil, err
+	}
+
+	port := service.Spec.Ports[0].Port
+	protocol := &service.Spec.Ports[0].Protocol
+
+	var addresses []string
+	for i := range service.Status.LoadBalancer.Ingress {
+		if hostName := service.Status.LoadBalancer.Ingress[i].Hostname; hostName != "" {
+			addresses = append(addresses, hostName)
+		}
+		if ip := service.Status.LoadBalancer.Ingress[i].IP; ip != "" {
+			addresses = append(addresses, ip)
+		}
+	}
+
+	return &networkingv1beta1.EndpointStatus{
+		Protocol:  protocol,
+		Port:      port,
+		Addresses: addresses,
+	}, nil
+}
+
+// ForgeInternalEndpoint forges the internal endpoint of a gateway, pointing to its active pod.
+// In case of active-active gateways, it points to the ready replica with the lowest index, and lists all the ready replicas.
+func ForgeInternalEndpoint(ctx context.Context, cl client.Client, dep *appsv1.Deployment) (*networkingv1beta1.InternalGatewayEndpoint, error) {
+	podsSelector := client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(gateway.ForgeActiveGatewayPodLabels())}
+	var podList corev1.PodList
+	if err := cl.List(ctx, &podList, client.InNamespace(dep.Namespace), podsSelector); err != nil {
+		klog.Errorf("Unable to list pods of deployment %s/%s: %v", dep.Namespace, dep.Name, err)
+		return nil, err
+	}
+
+	if len(podList.Items) > 0 && isActiveActive(podList.Items) {
+		return forgeInternalEndpointActiveActive(podList.Items, dep)
+	}
+
+	if len(podList.Items) != 1 {
+		err := fmt.Errorf("wrong number of pods for deployment %s/%s: %d (must be 1)", dep.Namespace, dep.Name, len(podList.Items))
+		klog.Error(err)
+		return nil, err
+	}
+
+	if podList.Items[0].Status.PodIP == "" {
+		err := fmt.Errorf("pod %s/%s has no IP", podList.Items[0].Namespace, podList.Items[0].Name)
+		klog.Error(err)
+		return nil, err
+	}
+
+	return &networkingv1beta1.InternalGatewayEndpoint{
+		IP:   ptr.To(networkingv1beta1.IP(podList.Items[0].Status.PodIP)),
+		Node: &podList.Items[0].Spec.NodeName,
+	}, nil
+}
+
+// isActiveActive returns whether the given active pods belong to an active-active gateway, i.e., they are labeled with their replica index.
+func isActiveActive(pods []corev1.Pod) bool {
+	for i := range pods {
+		if _, ok := pods[i].GetLabels()[concurrent.ReplicaIndexKey]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func forgeInternalEndpointActiveActive(pods []corev1.Pod, dep *appsv1.Deployment) (*networkingv1beta1.InternalGatewayEndpoint, error) {
+	var replicas []networkingv1beta1.InternalGatewayReplicaEndpoint
+	for i := range pods {
+		pod := &pods[i]
+		if ready, _ := podutils.IsPodReady(pod); !ready || pod.Status.PodIP == "" {
+			continue
+		}
+		index, err := concurrent.ReplicaIndexFromPod(pod)
+		if err != nil {
+			klog.Warning(err)
+			continue
+		}
+		replicas = append(replicas, networkingv1beta1.InternalGatewayReplicaEndpoint{
+			Index: index,
+			IP:    networkingv1beta1.IP(pod.Status.PodIP),
+			Node:  pod.Spec.NodeName,
+		})
+	}
+
+	if len(replicas) == 0 {
+		err := fmt.Errorf("no ready replicas for deployment %s/%s", dep.Namespace, dep.Name)
+		klog.Error(err)
+		return nil, err
+	}
+
+	slices.SortFunc(replicas, func(a, b networkingv1beta1.InternalGatewayReplicaEndpoint) int {
+		return a.Index - b.Index
+	})
+
+	return &networkingv1beta1.InternalGatewayEndpoint{
+		IP:       ptr.To(replicas[0].IP),
+		Node:     ptr.To(replicas[0].Node),
+		Replicas: replicas,
+	}, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway/forge"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// FilterGatewaySecretsPredicate selects the secrets containing the keys of the gateways towards remote clusters.
+func FilterGatewaySecretsPredicate() predicate.Predicate {
+	filterGatewayResources, err := predicate.LabelSelectorPredicate(liqolabels.GatewayResourceLabelSelector)
+	utilruntime.Must(err)
+
+	filterResourcesForRemote, err := predicate.LabelSelectorPredicate(liqolabels.ResourceForRemoteClusterLabelSelector)
+	utilruntime.Must(err)
+
+	return predicate.And(filterGatewayResources, filterResourcesForRemote)
+}
+
+// GatewaySecretEnquerer enqueues the gateway the given keys secret belongs to.
+func GatewaySecretEnquerer(_ context.Context, obj client.Object) []ctrl.Request {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+
+	return []ctrl.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: secret.Namespace,
+				Name:      forge.GatewayResourceName(secret.Name),
+			},
+		},
+	}
+}
+
+// ClusterRoleBindingEnquerer enqueues the gateway the given cluster role binding belongs to.
+func ClusterRoleBindingEnquerer(_ context.Context, obj client.Object) []ctrl.Request {
+	crb, ok := obj.(*rbacv1.ClusterRoleBinding)
+	if !ok {
+		return nil
+	}
+
+	if crb.Labels == nil {
+		return nil
+	}
+	gwName, ok := crb.Labels[consts.GatewayNameLabel]
+	if !ok {
+		return nil
+	}
+	gwNs, ok := crb.Labels[consts.GatewayNamespaceLabel]
+	if !ok {
+		return nil
+	}
+
+	return []ctrl.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: gwNs,
+				Name:      gwName,
+			},
+		},
+	}
+}
+
+// PodEnquerer enqueues the gateway the given pod belongs to.
+func PodEnquerer(_ context.Context, obj client.Object) []ctrl.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+
+	if pod.Labels == nil {
+		return nil
+	}
+	gwName, ok := pod.Labels[consts.GatewayNameLabel]
+	if !ok {
+		return nil
+	}
+	gwNs, ok := pod.Labels[consts.GatewayNamespaceLabel]
+	if !ok {
+		return nil
+	}
+
+	return []ctrl.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: gwNs,
+				Name:      gwName,
+			},
+		},
+	}
+}
+
+// CheckExistingKeysSecret checks that the keys secret provided by the user for the given gateway
+// contains the private and public keys, and enforces the labels required to associate it to the gateway.
+func CheckExistingKeysSecret(ctx context.Context, cl client.Client, secretName, namespace string, gwObj metav1.Object) error {
+	var s corev1.Secret
+	if err := cl.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, &s); err != nil {
+		return err
+	}
+
+	// Check needed data fields are present
+	if s.Data == nil {
+		return fmt.Errorf("mandatory data %q and %q are missing in secret %q", consts.PrivateKeyField, consts.PublicKeyField, secretName)
+	}
+	if _, ok := s.Data[consts.PrivateKeyField]; !ok {
+		return fmt.Errorf("missing %q data in secret %q", consts.PrivateKeyField, secretName)
+	}
+	if _, ok := s.Data[consts.PublicKeyField]; !ok {
+		return fmt.Errorf("missing %q data in secret %q", consts.PublicKeyField, secretName)
+	}
+
+	// Check remote cluster ID label match the parent gateway object
+	remoteClusterID, exists := gwObj.GetLabels()[consts.RemoteClusterID]
+	if !exists || remoteClusterID == "" {
+		return fmt.Errorf("missing %q label in gateway %q", consts.RemoteClusterID, gwObj.GetName())
+	}
+	if s.Labels != nil {
+		if v, ok := s.Labels[consts.RemoteClusterID]; ok && v != remoteClusterID {
+			return fmt.Errorf("label %q in secret %q does not match the one in gateway %q", consts.RemoteClusterID, secretName, gwObj.GetName())
+		}
+	}
+
+	// Enforce correct labels on the secret if not present
+	if s.Labels == nil || s.Labels[consts.RemoteClusterID] == "" || s.Labels[consts.GatewayResourceLabel] != consts.GatewayResourceLabelValue {
+		s.SetLabels(labels.Merge(s.GetLabels(), map[string]string{
+			consts.RemoteClusterID:      remoteClusterID,
+			consts.GatewayResourceLabel: consts.GatewayResourceLabelValue,
+		}))
+		if err := cl.Update(ctx, &s); err != nil {
+			return fmt.Errorf("unable to update labels in secret %q: %w", secretName, err)
+		}
+		klog.Infof("Enforced correct gateway labels in secret %q", secretName)
+	}
+
+	return nil
+}
+
+// GetGatewaySecret returns the keys secret associated to the given gateway.
+func GetGatewaySecret(ctx context.Context, cl client.Client, gwObj metav1.Object) (*corev1.Secret, error) {
+	gwObjNsName := types.NamespacedName{Name: gwObj.GetName(), Namespace: gwObj.GetNamespace()}
+
+	remoteClusterID, exists := gwObj.GetLabels()[consts.RemoteClusterID]
+	if !exists {
+		err := fmt.Errorf("missing %q label in gateway %q", consts.RemoteClusterID, gwObjNsName)
+		klog.Error(err)
+		return nil, err
+	}
+	gwSecretSelector := client.MatchingLabels{
+		consts.GatewayResourceLabel: consts.GatewayResourceLabelValue,
+		consts.RemoteClusterID:      remoteClusterID,
+	}
+
+	var secrets corev1.SecretList
+	err := cl.List(ctx, &secrets, client.InNamespace(gwObj.GetNamespace()), gwSecretSelector)
+	if err != nil {
+		klog.Errorf("Unable to list secrets associated to gateway %q: %v", gwObjNsName, err)
+		return nil, err
+	}
+
+	switch len(secrets.Items) {
+	case 0:
+		err = kerrors.NewNotFound(corev1.Resource("Secret"), gwObjNsName.Name)
+		return nil, err
+	case 1:
+		return &secrets.Items[0], nil
+	default:
+		return nil, fmt.Errorf("found multiple secrets associated to gateway %q", gwObjNsName)
+	}
+}
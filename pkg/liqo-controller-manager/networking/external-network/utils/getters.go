@@ -53,6 +53,16 @@ func ParseInternalEndpoint(internalEndpoint map[string]interface{}) *networkingv
 	if value, ok := internalEndpoint["node"]; ok {
 		res.Node = ptr.To(value.(string))
 	}
+	if value, ok := internalEndpoint["replicas"]; ok {
+		for _, item := range value.([]interface{}) {
+			replica := item.(map[string]interface{})
+			res.Replicas = append(res.Replicas, networkingv1beta1.InternalGatewayReplicaEndpoint{
+				Index: int(replica["index"].(int64)),
+				IP:    networkingv1beta1.IP(replica["ip"].(string)),
+				Node:  replica["node"].(string),
+			})
+		}
+	}
 	return res
 }
 
@@ -0,0 +1,207 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/concurrent"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// endpointSliceManagedBy is the value of the managed-by label of the EndpointSlices targeting the replicas of active-active gateways.
+const endpointSliceManagedBy = "networking.liqo.io"
+
+// IsActiveActiveService returns whether the given service exposes an active-active gateway.
+func IsActiveActiveService(service *corev1.Service) bool {
+	return service.GetAnnotations()[gateway.ConcurrencyModeAnnotationKey] == gateway.ConcurrencyModeActiveActive.String()
+}
+
+// ReplicaPortName returns the name of the service port targeting the gateway replica with the given index.
+func ReplicaPortName(index int) string {
+	return fmt.Sprintf("replica-%d", index)
+}
+
+// ReplicaEndpointSliceName returns the name of the EndpointSlice targeting the gateway replica with the given index.
+func ReplicaEndpointSliceName(serviceName string, index int) string {
+	return fmt.Sprintf("%s-replica-%d", serviceName, index)
+}
+
+// MutateActiveActiveService configures the service exposing an active-active gateway, starting from the one forged from the template.
+// The service gets one port for each replica, consecutive to the one of the template, which is targeted to the replica with the
+// same index by a dedicated EndpointSlice. Hence, the selector is removed, as the EndpointSlices are managed by the controller.
+func MutateActiveActiveService(service *corev1.Service, replicas int) error {
+	if len(service.Spec.Ports) != 1 {
+		return fmt.Errorf("service %s/%s of an active-active gateway must have exactly one port", service.Namespace, service.Name)
+	}
+	if service.Spec.Type == corev1.ServiceTypeNodePort && service.Spec.Ports[0].NodePort == 0 {
+		return fmt.Errorf("service %s/%s of an active-active gateway must specify the node port", service.Namespace, service.Name)
+	}
+
+	base := service.Spec.Ports[0]
+	service.Spec.Selector = nil
+	service.Spec.Ports = make([]corev1.ServicePort, replicas)
+	for i := range replicas {
+		port := base.DeepCopy()
+		port.Name = ReplicaPortName(i)
+		port.Port = base.Port + int32(i)
+		if base.NodePort != 0 {
+			port.NodePort = base.NodePort + int32(i)
+		}
+		service.Spec.Ports[i] = *port
+	}
+	return nil
+}
+
+// EnsureReplicaEndpointSlices enforces the EndpointSlices targeting each port of the service exposing an active-active gateway
+// to the replica with the corresponding index. Endpoints are always considered ready, since the readiness of the replicas
+// depends on the establishment of the tunnel, which requires the endpoints to be reachable.
+func EnsureReplicaEndpointSlices(ctx context.Context, cl client.Client, scheme *runtime.Scheme, service *corev1.Service,
+	dep *appsv1.Deployment, owner client.Object) error {
+	pods, err := listReplicaPods(ctx, cl, dep)
+	if err != nil {
+		return err
+	}
+
+	for i := range service.Spec.Ports {
+		port := &service.Spec.Ports[i]
+		eps := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+			Name:      ReplicaEndpointSliceName(service.Name, i),
+			Namespace: service.Namespace,
+		}}
+
+		op, err := resource.CreateOrUpdate(ctx, cl, eps, func() error {
+			mutateReplicaEndpointSlice(eps, service, port, pods[i])
+			return controllerutil.SetControllerReference(owner, eps, scheme)
+		})
+		if err != nil {
+			klog.Errorf("error while creating/updating endpointslice %s/%s (operation: %s): %v", eps.Namespace, eps.Name, op, err)
+			return err
+		}
+		klog.V(4).Infof("EndpointSlice %s/%s correctly enforced (operation: %s)", eps.Namespace, eps.Name, op)
+	}
+
+	return deleteStaleReplicaEndpointSlices(ctx, cl, service, len(service.Spec.Ports))
+}
+
+// DeleteReplicaEndpointSlices deletes the EndpointSlices targeting the replicas of the given service, if any
+// (e.g., because the gateway is no longer active-active).
+func DeleteReplicaEndpointSlices(ctx context.Context, cl client.Client, service *corev1.Service) error {
+	return deleteStaleReplicaEndpointSlices(ctx, cl, service, 0)
+}
+
+func mutateReplicaEndpointSlice(eps *discoveryv1.EndpointSlice, service *corev1.Service, port *corev1.ServicePort, pod *corev1.Pod) {
+	eps.SetLabels(labels.Merge(eps.GetLabels(), map[string]string{
+		discoveryv1.LabelServiceName: service.Name,
+		discoveryv1.LabelManagedBy:   endpointSliceManagedBy,
+	}))
+
+	targetPort := port.TargetPort.IntVal
+	if targetPort == 0 {
+		targetPort = port.Port
+	}
+
+	eps.AddressType = discoveryv1.AddressTypeIPv4
+	eps.Ports = []discoveryv1.EndpointPort{{
+		Name:     ptr.To(port.Name),
+		Port:     ptr.To(targetPort),
+		Protocol: ptr.To(port.Protocol),
+	}}
+
+	eps.Endpoints = nil
+	if pod == nil || pod.Status.PodIP == "" {
+		return
+	}
+	if ip := net.ParseIP(pod.Status.PodIP); ip.To4() == nil {
+		eps.AddressType = discoveryv1.AddressTypeIPv6
+	}
+	eps.Endpoints = []discoveryv1.Endpoint{{
+		Addresses:  []string{pod.Status.PodIP},
+		Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		NodeName:   ptr.To(pod.Spec.NodeName),
+		TargetRef: &corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+		},
+	}}
+}
+
+// listReplicaPods returns the pods of the given deployment, indexed by their replica index.
+func listReplicaPods(ctx context.Context, cl client.Client, dep *appsv1.Deployment) (map[int]*corev1.Pod, error) {
+	var podList corev1.PodList
+	if err := cl.List(ctx, &podList, client.InNamespace(dep.Namespace),
+		client.MatchingLabels(dep.Spec.Selector.MatchLabels), client.HasLabels{concurrent.ReplicaIndexKey}); err != nil {
+		klog.Errorf("Unable to list pods of deployment %s/%s: %v", dep.Namespace, dep.Name, err)
+		return nil, err
+	}
+
+	pods := make(map[int]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		index, err := concurrent.ReplicaIndexFromPod(pod)
+		if err != nil {
+			klog.Warning(err)
+			continue
+		}
+		pods[index] = pod
+	}
+	return pods, nil
+}
+
+// deleteStaleReplicaEndpointSlices deletes the EndpointSlices targeting replicas with an index greater or equal than the given one.
+func deleteStaleReplicaEndpointSlices(ctx context.Context, cl client.Client, service *corev1.Service, replicas int) error {
+	var slices discoveryv1.EndpointSliceList
+	if err := cl.List(ctx, &slices, client.InNamespace(service.Namespace), client.MatchingLabels{
+		discoveryv1.LabelServiceName: service.Name,
+		discoveryv1.LabelManagedBy:   endpointSliceManagedBy,
+	}); err != nil {
+		return err
+	}
+
+	expected := make(map[string]struct{}, replicas)
+	for i := range replicas {
+		expected[ReplicaEndpointSliceName(service.Name, i)] = struct{}{}
+	}
+
+	for i := range slices.Items {
+		if _, ok := expected[slices.Items[i].Name]; ok {
+			continue
+		}
+		if err := client.IgnoreNotFound(cl.Delete(ctx, &slices.Items[i])); err != nil {
+			return err
+		}
+		klog.Infof("Deleted stale endpointslice %s/%s", slices.Items[i].Namespace, slices.Items[i].Name)
+	}
+	return nil
+}
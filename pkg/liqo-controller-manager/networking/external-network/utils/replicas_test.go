@@ -0,0 +1,201 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"context"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/gateway"
+	"github.com/liqotech/liqo/pkg/gateway/concurrent"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
+)
+
+var _ = Describe("Active-active gateway replicas", func() {
+	const (
+		ns      = "default"
+		svcName = "gw-server"
+	)
+
+	var (
+		ctx context.Context
+		cl  client.Client
+		dep *appsv1.Deployment
+
+		newService = func(svcType corev1.ServiceType, nodePort int32) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svcName,
+					Namespace:   ns,
+					Annotations: map[string]string{gateway.ConcurrencyModeAnnotationKey: gateway.ConcurrencyModeActiveActive.String()},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:     svcType,
+					Selector: map[string]string{"app": "gateway"},
+					Ports: []corev1.ServicePort{{
+						Name:       "wireguard",
+						Port:       51840,
+						NodePort:   nodePort,
+						TargetPort: intstr.FromInt32(51840),
+						Protocol:   corev1.ProtocolUDP,
+					}},
+				},
+			}
+		}
+
+		newPod = func(name string, index int, ip string, ready bool) *corev1.Pod {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns,
+					Labels: labels.Merge(gateway.ForgeActiveGatewayPodLabels(), map[string]string{
+						"app":                      "gateway",
+						concurrent.ReplicaIndexKey: strconv.Itoa(index),
+					}),
+				},
+				Spec: corev1.PodSpec{NodeName: "node-" + name},
+				Status: corev1.PodStatus{
+					PodIP:      ip,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				},
+			}
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dep = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: ns, UID: "dep-uid"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](2),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gateway"}},
+			},
+		}
+	})
+
+	Describe("The MutateActiveActiveService function", func() {
+		It("should forge one port for each replica", func() {
+			svc := newService(corev1.ServiceTypeNodePort, 30000)
+			Expect(utils.MutateActiveActiveService(svc, 3)).To(Succeed())
+
+			Expect(svc.Spec.Selector).To(BeNil())
+			Expect(svc.Spec.Ports).To(HaveLen(3))
+			for i := range svc.Spec.Ports {
+				Expect(svc.Spec.Ports[i].Name).To(Equal(utils.ReplicaPortName(i)))
+				Expect(svc.Spec.Ports[i].Port).To(BeEquivalentTo(51840 + i))
+				Expect(svc.Spec.Ports[i].NodePort).To(BeEquivalentTo(30000 + i))
+				Expect(svc.Spec.Ports[i].Protocol).To(Equal(corev1.ProtocolUDP))
+			}
+		})
+
+		It("should fail if the node port is not specified for NodePort services", func() {
+			Expect(utils.MutateActiveActiveService(newService(corev1.ServiceTypeNodePort, 0), 2)).ToNot(Succeed())
+		})
+
+		It("should not require the node port for LoadBalancer services", func() {
+			svc := newService(corev1.ServiceTypeLoadBalancer, 0)
+			Expect(utils.MutateActiveActiveService(svc, 2)).To(Succeed())
+			Expect(svc.Spec.Ports).To(HaveLen(2))
+			Expect(svc.Spec.Ports[1].NodePort).To(BeZero())
+		})
+	})
+
+	Describe("The ForgeInternalEndpoint function", func() {
+		It("should list the ready replicas, sorted by index", func() {
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				newPod("pod-2", 2, "10.0.0.3", true),
+				newPod("pod-0", 0, "10.0.0.1", false),
+				newPod("pod-1", 1, "10.0.0.2", true),
+			).Build()
+
+			ige, err := utils.ForgeInternalEndpoint(ctx, cl, dep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ige.IP).To(PointTo(BeEquivalentTo("10.0.0.2")))
+			Expect(ige.Node).To(PointTo(Equal("node-pod-1")))
+			Expect(ige.Replicas).To(Equal([]networkingv1beta1.InternalGatewayReplicaEndpoint{
+				{Index: 1, IP: "10.0.0.2", Node: "node-pod-1"},
+				{Index: 2, IP: "10.0.0.3", Node: "node-pod-2"},
+			}))
+		})
+
+		It("should fail if no replica is ready", func() {
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newPod("pod-0", 0, "10.0.0.1", false)).Build()
+			_, err := utils.ForgeInternalEndpoint(ctx, cl, dep)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The EnsureReplicaEndpointSlices function", func() {
+		var svc *corev1.Service
+
+		BeforeEach(func() {
+			svc = newService(corev1.ServiceTypeNodePort, 30000)
+			Expect(utils.MutateActiveActiveService(svc, 2)).To(Succeed())
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				dep, newPod("pod-0", 0, "10.0.0.1", true), newPod("pod-1", 1, "10.0.0.2", false),
+			).Build()
+		})
+
+		It("should target each port to the replica with the same index", func() {
+			Expect(utils.EnsureReplicaEndpointSlices(ctx, cl, scheme.Scheme, svc, dep, dep)).To(Succeed())
+
+			for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+				var eps discoveryv1.EndpointSlice
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: ns, Name: utils.ReplicaEndpointSliceName(svcName, i)}, &eps)).To(Succeed())
+				Expect(eps.Labels).To(HaveKeyWithValue(discoveryv1.LabelServiceName, svcName))
+				Expect(eps.Ports).To(HaveLen(1))
+				Expect(eps.Ports[0].Name).To(PointTo(Equal(utils.ReplicaPortName(i))))
+				Expect(eps.Ports[0].Port).To(PointTo(BeEquivalentTo(51840)))
+				Expect(eps.Endpoints).To(HaveLen(1))
+				Expect(eps.Endpoints[0].Addresses).To(ConsistOf(ip))
+				Expect(eps.Endpoints[0].Conditions.Ready).To(PointTo(BeTrue()))
+				Expect(eps.OwnerReferences).To(HaveLen(1))
+			}
+		})
+
+		It("should delete the stale EndpointSlices", func() {
+			Expect(utils.EnsureReplicaEndpointSlices(ctx, cl, scheme.Scheme, svc, dep, dep)).To(Succeed())
+			svc.Spec.Ports = svc.Spec.Ports[:1]
+			Expect(utils.EnsureReplicaEndpointSlices(ctx, cl, scheme.Scheme, svc, dep, dep)).To(Succeed())
+
+			var list discoveryv1.EndpointSliceList
+			Expect(cl.List(ctx, &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+
+			Expect(utils.DeleteReplicaEndpointSlices(ctx, cl, svc)).To(Succeed())
+			Expect(cl.List(ctx, &list)).To(Succeed())
+			Expect(list.Items).To(BeEmpty())
+		})
+	})
+})
@@ -17,6 +17,7 @@ package wireguard
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -24,12 +25,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -163,16 +162,17 @@ func (r *WgGatewayClientReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// If a secret has not been provided in the gateway specification, the controller is in charge of generating a secret with the Wireguard keys.
+	var requeue time.Duration
 	if wgClient.Spec.SecretRef.Name == "" {
 		// Ensure WireGuard keys secret (create or update)
-		if err = ensureKeysSecret(ctx, r.Client, wgClient, gateway.ModeClient); err != nil {
+		if requeue, err = ensureKeysSecret(ctx, r.Client, wgClient, gateway.ModeClient, wgClient.Spec.KeyRotation); err != nil {
 			r.eventRecorder.Event(wgClient, corev1.EventTypeWarning, "KeysSecretEnforcedFailed", "Failed to enforce keys secret")
 			return ctrl.Result{}, err
 		}
 		r.eventRecorder.Event(wgClient, corev1.EventTypeNormal, "KeysSecretEnforced", "Enforced keys secret")
 	} else {
 		// Check that the secret exists and ensure is correctly labeled
-		if err = checkExistingKeysSecret(ctx, r.Client, wgClient.Spec.SecretRef.Name, wgClient.Namespace, wgClient.GetObjectMeta()); err != nil {
+		if err = enutils.CheckExistingKeysSecret(ctx, r.Client, wgClient.Spec.SecretRef.Name, wgClient.Namespace, wgClient.GetObjectMeta()); err != nil {
 			r.eventRecorder.Event(wgClient, corev1.EventTypeWarning, "KeysSecretCheckFailed", fmt.Sprintf("Failed to check keys secret: %s", err))
 			return ctrl.Result{}, err
 		}
@@ -202,7 +202,7 @@ func (r *WgGatewayClientReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	r.eventRecorder.Event(wgClient, corev1.EventTypeNormal, "MetricsEnforced", "Enforced metrics")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeue}, nil
 }
 
 // SetupWithManager register the WgGatewayClientReconciler to the manager.
@@ -211,12 +211,12 @@ func (r *WgGatewayClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&networkingv1beta1.WgGatewayClient{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ServiceAccount{}).
-		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(podEnquerer)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enutils.PodEnquerer)).
 		Watches(&rbacv1.ClusterRoleBinding{},
-			handler.EnqueueRequestsFromMapFunc(clusterRoleBindingEnquerer)).
+			handler.EnqueueRequestsFromMapFunc(enutils.ClusterRoleBindingEnquerer)).
 		Watches(&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(wireGuardSecretEnquerer),
-			builder.WithPredicates(filterWireGuardSecretsPredicate())).
+			handler.EnqueueRequestsFromMapFunc(enutils.GatewaySecretEnquerer),
+			builder.WithPredicates(enutils.FilterGatewaySecretsPredicate())).
 		Complete(r)
 }
 
@@ -266,7 +266,7 @@ func (r *WgGatewayClientReconciler) mutateFnWgClientDeployment(deployment *appsv
 }
 
 func (r *WgGatewayClientReconciler) handleSecretRefStatus(ctx context.Context, wgClient *networkingv1beta1.WgGatewayClient) error {
-	secret, err := getWireGuardSecret(ctx, r.Client, wgClient)
+	secret, err := enutils.GetGatewaySecret(ctx, r.Client, wgClient)
 	switch {
 	case apierrors.IsNotFound(err):
 		wgClient.Status.SecretRef = nil
@@ -289,28 +289,11 @@ func (r *WgGatewayClientReconciler) handleInternalEndpointStatus(ctx context.Con
 		return nil
 	}
 
-	podsSelector := client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(gateway.ForgeActiveGatewayPodLabels())}
-	var podList corev1.PodList
-	if err := r.List(ctx, &podList, client.InNamespace(dep.Namespace), podsSelector); err != nil {
-		klog.Errorf("Unable to list pods of deployment %s/%s: %v", dep.Namespace, dep.Name, err)
-		return err
-	}
-
-	if len(podList.Items) != 1 {
-		err := fmt.Errorf("wrong number of pods for deployment %s/%s: %d (must be 1)", dep.Namespace, dep.Name, len(podList.Items))
-		klog.Error(err)
-		return err
-	}
-
-	if podList.Items[0].Status.PodIP == "" {
-		err := fmt.Errorf("pod %s/%s has no IP", podList.Items[0].Namespace, podList.Items[0].Name)
-		klog.Error(err)
+	ige, err := enutils.ForgeInternalEndpoint(ctx, r.Client, dep)
+	if err != nil {
 		return err
 	}
 
-	wgClient.Status.InternalEndpoint = &networkingv1beta1.InternalGatewayEndpoint{
-		IP:   ptr.To(networkingv1beta1.IP(podList.Items[0].Status.PodIP)),
-		Node: &podList.Items[0].Spec.NodeName,
-	}
+	wgClient.Status.InternalEndpoint = ige
 	return nil
 }
@@ -17,14 +17,15 @@ package wireguard
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -41,7 +42,6 @@ import (
 	"github.com/liqotech/liqo/pkg/gateway"
 	"github.com/liqotech/liqo/pkg/gateway/forge"
 	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
-	"github.com/liqotech/liqo/pkg/utils"
 	mapsutil "github.com/liqotech/liqo/pkg/utils/maps"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
@@ -76,6 +76,7 @@ func NewWgGatewayServerReconciler(cl client.Client, s *runtime.Scheme,
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;delete;create;update;patch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;delete;create;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;create;delete;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;delete;create;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;delete;create;update;patch
@@ -172,16 +173,17 @@ func (r *WgGatewayServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// If a secret has not been provided in the gateway specification, the controller is in charge of generating a secret with the Wireguard keys.
+	var requeue time.Duration
 	if wgServer.Spec.SecretRef.Name == "" {
 		// Ensure WireGuard keys secret (create or update)
-		if err = ensureKeysSecret(ctx, r.Client, wgServer, gateway.ModeServer); err != nil {
+		if requeue, err = ensureKeysSecret(ctx, r.Client, wgServer, gateway.ModeServer, wgServer.Spec.KeyRotation); err != nil {
 			r.eventRecorder.Event(wgServer, corev1.EventTypeWarning, "KeysSecretEnforcedFailed", "Failed to enforce keys secret")
 			return ctrl.Result{}, err
 		}
 		r.eventRecorder.Event(wgServer, corev1.EventTypeNormal, "KeysSecretEnforced", "Enforced keys secret")
 	} else {
 		// Check that the secret exists and ensure is correctly labeled
-		if err = checkExistingKeysSecret(ctx, r.Client, wgServer.Spec.SecretRef.Name, wgServer.Namespace, wgServer.GetObjectMeta()); err != nil {
+		if err = enutils.CheckExistingKeysSecret(ctx, r.Client, wgServer.Spec.SecretRef.Name, wgServer.Namespace, wgServer.GetObjectMeta()); err != nil {
 			r.eventRecorder.Event(wgServer, corev1.EventTypeWarning, "KeysSecretCheckFailed", fmt.Sprintf("Failed to check keys secret: %s", err))
 			return ctrl.Result{}, err
 		}
@@ -203,12 +205,19 @@ func (r *WgGatewayServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	r.eventRecorder.Event(wgServer, corev1.EventTypeNormal, "DeploymentEnforced", "Enforced deployment")
 
 	// Ensure service (create or update)
-	_, err = r.ensureService(ctx, wgServer, svcNsName)
+	svc, err := r.ensureService(ctx, wgServer, svcNsName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	r.eventRecorder.Event(wgServer, corev1.EventTypeNormal, "ServiceEnforced", "Enforced service")
 
+	// Ensure the EndpointSlices pairing each port of the service with a replica, in case of active-active gateways.
+	if err := r.ensureReplicaEndpointSlices(ctx, wgServer, svc, deploy); err != nil {
+		r.eventRecorder.Event(wgServer, corev1.EventTypeWarning, "EndpointSlicesFailed",
+			fmt.Sprintf("Failed to enforce replica endpointslices: %s", err))
+		return ctrl.Result{}, err
+	}
+
 	// Ensure Metrics (if set)
 	err = enutils.EnsureMetrics(ctx,
 		r.Client, r.Scheme,
@@ -218,7 +227,7 @@ func (r *WgGatewayServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	r.eventRecorder.Event(wgServer, corev1.EventTypeNormal, "MetricsEnforced", "Enforced metrics")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeue}, nil
 }
 
 // SetupWithManager register the WgGatewayServerReconciler to the manager.
@@ -227,13 +236,14 @@ func (r *WgGatewayServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&networkingv1beta1.WgGatewayServer{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&discoveryv1.EndpointSlice{}).
 		Owns(&corev1.ServiceAccount{}).
-		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(podEnquerer)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enutils.PodEnquerer)).
 		Watches(&rbacv1.ClusterRoleBinding{},
-			handler.EnqueueRequestsFromMapFunc(clusterRoleBindingEnquerer)).
+			handler.EnqueueRequestsFromMapFunc(enutils.ClusterRoleBindingEnquerer)).
 		Watches(&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(wireGuardSecretEnquerer),
-			builder.WithPredicates(filterWireGuardSecretsPredicate())).
+			handler.EnqueueRequestsFromMapFunc(enutils.GatewaySecretEnquerer),
+			builder.WithPredicates(enutils.FilterGatewaySecretsPredicate())).
 		Complete(r)
 }
 
@@ -312,10 +322,24 @@ func (r *WgGatewayServerReconciler) mutateFnWgServerService(service *corev1.Serv
 		service.Spec.LoadBalancerClass = serviceClassName
 	}
 
+	if enutils.IsActiveActiveService(service) {
+		if err := enutils.MutateActiveActiveService(service, int(ptr.Deref(wgServer.Spec.Deployment.Spec.Replicas, 1))); err != nil {
+			return err
+		}
+	}
+
 	// Set WireGuard server as owner of the service
 	return controllerutil.SetControllerReference(wgServer, service, r.Scheme)
 }
 
+func (r *WgGatewayServerReconciler) ensureReplicaEndpointSlices(ctx context.Context, wgServer *networkingv1beta1.WgGatewayServer,
+	svc *corev1.Service, dep *appsv1.Deployment) error {
+	if !enutils.IsActiveActiveService(svc) || dep == nil {
+		return enutils.DeleteReplicaEndpointSlices(ctx, r.Client, svc)
+	}
+	return enutils.EnsureReplicaEndpointSlices(ctx, r.Client, r.Scheme, svc, dep, wgServer)
+}
+
 func (r *WgGatewayServerReconciler) handleEndpointStatus(ctx context.Context, wgServer *networkingv1beta1.WgGatewayServer,
 	svcNsName types.NamespacedName, dep *appsv1.Deployment) error {
 	if dep == nil {
@@ -332,21 +356,9 @@ func (r *WgGatewayServerReconciler) handleEndpointStatus(ctx context.Context, wg
 	}
 
 	// Put service endpoint in WireGuard server status
-	var endpointStatus *networkingv1beta1.EndpointStatus
-	switch service.Spec.Type {
-	case corev1.ServiceTypeClusterIP:
-		endpointStatus, err = r.forgeEndpointStatusClusterIP(&service)
-	case corev1.ServiceTypeNodePort:
-		endpointStatus, _, err = r.forgeEndpointStatusNodePort(ctx, &service, dep)
-	case corev1.ServiceTypeLoadBalancer:
-		endpointStatus, err = r.forgeEndpointStatusLoadBalancer(&service)
-	default:
-		err = fmt.Errorf("service type %q not supported for WireGuard server Service %q", service.Spec.Type, svcNsName)
-		klog.Error(err)
-		wgServer.Status.Endpoint = nil // we empty the endpoint status to avoid misaligned spec and status
-	}
-
+	endpointStatus, err := enutils.ForgeEndpointStatus(ctx, r.Client, &service, dep)
 	if err != nil {
+		wgServer.Status.Endpoint = nil // we empty the endpoint status to avoid misaligned spec and status
 		return err
 	}
 
@@ -355,111 +367,8 @@ func (r *WgGatewayServerReconciler) handleEndpointStatus(ctx context.Context, wg
 	return nil
 }
 
-func (r *WgGatewayServerReconciler) forgeEndpointStatusClusterIP(service *corev1.Service) (*networkingv1beta1.EndpointStatus, error) {
-	if len(service.Spec.Ports) == 0 {
-		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
-		klog.Error(err)
-		return nil, err
-	}
-
-	port := service.Spec.Ports[0].Port
-	protocol := &service.Spec.Ports[0].Protocol
-	addresses := service.Spec.ClusterIPs
-
-	return &networkingv1beta1.EndpointStatus{
-		Protocol:  protocol,
-		Port:      port,
-		Addresses: addresses,
-	}, nil
-}
-
-func (r *WgGatewayServerReconciler) forgeEndpointStatusNodePort(ctx context.Context, service *corev1.Service,
-	dep *appsv1.Deployment) (*networkingv1beta1.EndpointStatus, *networkingv1beta1.InternalGatewayEndpoint, error) {
-	if len(service.Spec.Ports) == 0 {
-		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
-		klog.Error(err)
-		return nil, nil, err
-	}
-
-	port := service.Spec.Ports[0].NodePort
-	protocol := &service.Spec.Ports[0].Protocol
-
-	podsSelector := client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(gateway.ForgeActiveGatewayPodLabels())}
-	var podList corev1.PodList
-	if err := r.List(ctx, &podList, client.InNamespace(dep.Namespace), podsSelector); err != nil {
-		klog.Errorf("Unable to list pods of deployment %s/%s: %v", dep.Namespace, dep.Name, err)
-		return nil, nil, err
-	}
-
-	if len(podList.Items) != 1 {
-		err := fmt.Errorf("wrong number of pods for deployment %s/%s: %d (must be 1)", dep.Namespace, dep.Name, len(podList.Items))
-		klog.Error(err)
-		return nil, nil, err
-	}
-
-	pod := &podList.Items[0]
-
-	node := &corev1.Node{}
-	err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node)
-	if err != nil && !apierrors.IsNotFound(err) {
-		klog.Errorf("Unable to get node %q: %v", pod.Spec.NodeName, err)
-		return nil, nil, err
-	}
-
-	addresses := make([]string, 1)
-	if utils.IsNodeReady(node) {
-		if addresses[0], err = utils.GetAddress(node); err != nil {
-			klog.Errorf("Unable to get address of node %q: %v", pod.Spec.NodeName, err)
-			return nil, nil, err
-		}
-	}
-
-	internalAddress := pod.Status.PodIP
-	if internalAddress == "" {
-		err := fmt.Errorf("pod %s/%s has no IP", pod.Namespace, pod.Name)
-		klog.Error(err)
-		return nil, nil, err
-	}
-
-	return &networkingv1beta1.EndpointStatus{
-			Protocol:  protocol,
-			Port:      port,
-			Addresses: addresses,
-		}, &networkingv1beta1.InternalGatewayEndpoint{
-			IP:   ptr.To(networkingv1beta1.IP(internalAddress)),
-			Node: &pod.Spec.NodeName,
-		}, nil
-}
-
-func (r *WgGatewayServerReconciler) forgeEndpointStatusLoadBalancer(service *corev1.Service) (*networkingv1beta1.EndpointStatus, error) {
-	if len(service.Spec.Ports) == 0 {
-		err := fmt.Errorf("service %s/%s has no ports", service.Namespace, service.Name)
-		klog.Error(err)
-		return nil, err
-	}
-
-	port := service.Spec.Ports[0].Port
-	protocol := &service.Spec.Ports[0].Protocol
-
-	var addresses []string
-	for i := range service.Status.LoadBalancer.Ingress {
-		if hostName := service.Status.LoadBalancer.Ingress[i].Hostname; hostName != "" {
-			addresses = append(addresses, hostName)
-		}
-		if ip := service.Status.LoadBalancer.Ingress[i].IP; ip != "" {
-			addresses = append(addresses, ip)
-		}
-	}
-
-	return &networkingv1beta1.EndpointStatus{
-		Protocol:  protocol,
-		Port:      port,
-		Addresses: addresses,
-	}, nil
-}
-
 func (r *WgGatewayServerReconciler) handleSecretRefStatus(ctx context.Context, wgServer *networkingv1beta1.WgGatewayServer) error {
-	secret, err := getWireGuardSecret(ctx, r.Client, wgServer)
+	secret, err := enutils.GetGatewaySecret(ctx, r.Client, wgServer)
 	switch {
 	case apierrors.IsNotFound(err):
 		wgServer.Status.SecretRef = nil
@@ -489,7 +398,7 @@ func (r *WgGatewayServerReconciler) handleInternalEndpointStatus(ctx context.Con
 		return err
 	}
 
-	_, ige, err := r.forgeEndpointStatusNodePort(ctx, &service, dep)
+	_, ige, err := enutils.ForgeEndpointStatusNodePort(ctx, r.Client, &service, dep)
 	if err != nil {
 		return err
 	}
@@ -43,16 +43,17 @@ func defaultGatewayServerName(remoteClusterID liqov1beta1.ClusterID) string {
 
 // GwServerOptions encapsulate the options to forge a GatewayServer.
 type GwServerOptions struct {
-	KubeClient        kubernetes.Interface
-	RemoteClusterID   liqov1beta1.ClusterID
-	GatewayType       string
-	TemplateName      string
-	TemplateNamespace string
-	ServiceType       corev1.ServiceType
-	MTU               int
-	Port              int32
-	NodePort          *int32
-	LoadBalancerIP    *string
+	KubeClient         kubernetes.Interface
+	RemoteClusterID    liqov1beta1.ClusterID
+	GatewayType        string
+	TemplateName       string
+	TemplateNamespace  string
+	ServiceType        corev1.ServiceType
+	MTU                int
+	Port               int32
+	NodePort           *int32
+	LoadBalancerIP     *string
+	AllowedClientCIDRs []string
 }
 
 // GatewayServer forges a GatewayServer.
@@ -102,6 +103,9 @@ func MutateGatewayServer(gwServer *networkingv1beta1.GatewayServer, o *GwServerO
 	if o.LoadBalancerIP != nil && *o.LoadBalancerIP != "" {
 		gwServer.Spec.Endpoint.LoadBalancerIP = o.LoadBalancerIP
 	}
+	for _, cidr := range o.AllowedClientCIDRs {
+		gwServer.Spec.Endpoint.AllowedClientCIDRs = append(gwServer.Spec.Endpoint.AllowedClientCIDRs, networkingv1beta1.CIDR(cidr))
+	}
 
 	// Server Template Reference
 	gvr, err := enutils.ParseGroupVersionResource(o.GatewayType)
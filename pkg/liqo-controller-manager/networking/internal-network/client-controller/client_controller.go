@@ -26,6 +26,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
@@ -34,7 +35,6 @@ import (
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/internal-network/fabricipam"
 	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
 	"github.com/liqotech/liqo/pkg/utils"
-	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
@@ -57,6 +57,7 @@ func NewClientReconciler(cl client.Client, s *runtime.Scheme) *ClientReconciler
 // +kubebuilder:rbac:groups=networking.liqo.io,resources=gatewayclients,verbs=get;list;watch;delete;create;update;patch
 // +kubebuilder:rbac:groups=networking.liqo.io,resources=gatewayclients/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=networking.liqo.io,resources=configurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=connections,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.liqo.io,resources=internalfabrics,verbs=get;list;watch;delete;create;update;patch
 
 // Reconcile manage GatewayClient lifecycle.
@@ -125,7 +126,9 @@ func (r *ClientReconciler) ensureInternalFabric(ctx context.Context, gwClient *n
 		}
 		internalFabric.Labels[consts.RemoteClusterID] = string(remoteClusterID)
 
-		internalFabric.Spec.MTU = gwClient.Spec.MTU
+		if internalFabric.Spec.MTU, err = internalnetwork.ForgeMTU(ctx, r.Client, gwClient.Namespace, remoteClusterID, gwClient.Spec.MTU); err != nil {
+			return err
+		}
 
 		internalFabric.Spec.GatewayIP = *gwClient.Status.InternalEndpoint.IP
 
@@ -136,15 +139,19 @@ func (r *ClientReconciler) ensureInternalFabric(ctx context.Context, gwClient *n
 		); err != nil {
 			return err
 		}
+
+		if err := internalnetwork.EnforceInternalFabricReplicas(ctx, r.Client, internalFabric, gwClient.Status.InternalEndpoint); err != nil {
+			return err
+		}
+
 		ip, err := ipam.Allocate(internalFabric.GetName())
 		if err != nil {
 			return err
 		}
 		internalFabric.Spec.Interface.Gateway.IP = networkingv1beta1.IP(ip.String())
 
-		internalFabric.Spec.RemoteCIDRs = []networkingv1beta1.CIDR{
-			*cidrutils.GetPrimary(configuration.Status.Remote.CIDR.Pod),
-			*cidrutils.GetPrimary(configuration.Status.Remote.CIDR.External),
+		if internalFabric.Spec.RemoteCIDRs, err = internalnetwork.ForgeRemoteCIDRs(ctx, r.Client, configuration); err != nil {
+			return err
 		}
 
 		return controllerutil.SetControllerReference(gwClient, internalFabric, r.Scheme)
@@ -160,5 +167,9 @@ func (r *ClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlGatewayClientInternal).
 		Owns(&networkingv1beta1.InternalFabric{}).
 		For(&networkingv1beta1.GatewayClient{}).
+		Watches(&networkingv1beta1.Connection{},
+			handler.EnqueueRequestsFromMapFunc(internalnetwork.ConnectionEnqueuer(networkingv1beta1.GatewayClientKind))).
+		Watches(&networkingv1beta1.Configuration{},
+			handler.EnqueueRequestsFromMapFunc(internalnetwork.TransitConfigurationEnqueuer(r.Client, networkingv1beta1.GatewayClientKind))).
 		Complete(r)
 }
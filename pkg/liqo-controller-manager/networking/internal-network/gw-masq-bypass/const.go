@@ -17,4 +17,8 @@ package gwmasqbypass
 const (
 	// PrePostroutingChainName is the name of the chain that manage gw-masquerade-bypass.
 	PrePostroutingChainName = "pre-postrouting"
+	// GatewayMasqueradeBypassRuleName is the name of the rule that manage gw-masquerade-bypass.
+	GatewayMasqueradeBypassRuleName = "gw-masquerade-bypass"
+	// GatewaysMapName is the name of the map containing the IPs of the gateway pods running on the node.
+	GatewaysMapName = "gateways"
 )
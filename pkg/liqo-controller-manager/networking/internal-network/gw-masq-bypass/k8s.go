@@ -18,11 +18,12 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -30,6 +31,7 @@ import (
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
 	"github.com/liqotech/liqo/pkg/fabric"
+	"github.com/liqotech/liqo/pkg/gateway"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
@@ -42,60 +44,54 @@ func getNodeFromFirewallConfigurationName(name string) string {
 	return name[:len(name)-len("-gw-masquerade-bypass")]
 }
 
-func enforceFirewallPodPresence(ctx context.Context, cl client.Client, scheme *runtime.Scheme,
-	opts *Options, pod *corev1.Pod) (controllerutil.OperationResult, error) {
-	if pod.Status.PodIP == "" {
-		return "", nil
-	}
-
-	internalnode := &networkingv1beta1.InternalNode{}
-	if err := cl.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, internalnode); err != nil {
-		return "", err
-	}
-
+// enforceFirewallNode enforces the masquerade bypass for the active gateway pods running on the given node,
+// deleting the firewall configuration when none is left.
+func enforceFirewallNode(ctx context.Context, cl client.Client, scheme *runtime.Scheme,
+	opts *Options, nodeName string) (controllerutil.OperationResult, error) {
 	fwcfg := &networkingv1beta1.FirewallConfiguration{
-		ObjectMeta: metav1.ObjectMeta{Name: generateFirewallConfigurationName(pod.Spec.NodeName), Namespace: opts.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: generateFirewallConfigurationName(nodeName), Namespace: opts.Namespace},
 	}
 
-	op, err := resource.CreateOrUpdate(ctx, cl, fwcfg, forgeFirewallPodUpdateFunction(internalnode, fwcfg, pod, scheme, opts.GenevePort))
-
-	return op, err
-}
-
-func enforceFirewallPodAbsence(ctx context.Context, cl client.Client, opts *Options, pod *corev1.Pod) error {
-	nodeName, err := GetPodNodeFromMap(client.ObjectKeyFromObject(pod))
+	podIPs, err := listGatewayPodIPs(ctx, cl, nodeName)
 	if err != nil {
-		return err
-	}
-	if nodeName == "" {
-		return fmt.Errorf("unable to get node name from pod %s/%s", pod.GetNamespace(), pod.GetName())
+		return "", err
 	}
-	fwcfg := networkingv1beta1.FirewallConfiguration{ObjectMeta: metav1.ObjectMeta{
-		Name: generateFirewallConfigurationName(nodeName), Namespace: opts.Namespace,
-	}}
-	if err := cl.Get(ctx, client.ObjectKeyFromObject(&fwcfg), &fwcfg); err != nil {
-		return fmt.Errorf("unable to get firewall configuration %s: %w", fwcfg.GetName(), err)
+	if len(podIPs) == 0 {
+		if err := cl.Delete(ctx, fwcfg); err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		return controllerutil.OperationResultUpdated, nil
 	}
 
-	if _, err := resource.CreateOrUpdate(ctx, cl, &fwcfg, forgeFirewallPodDeleteFunction(pod, &fwcfg)); err != nil {
-		return fmt.Errorf("unable to update firewall configuration %s: %w", fwcfg.GetName(), err)
+	internalnode := &networkingv1beta1.InternalNode{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: nodeName}, internalnode); err != nil {
+		return "", err
 	}
 
-	klog.Infof("Removed gw-masquerade-bypass for pod %s/%s from firewallconfiguration %s", pod.GetNamespace(), pod.GetName(), fwcfg.GetName())
+	return resource.CreateOrUpdate(ctx, cl, fwcfg, forgeFirewallNodeUpdateFunction(internalnode, fwcfg, podIPs, scheme, opts.GenevePort))
+}
 
-	DeletePodKeyFromMap(client.ObjectKeyFromObject(pod))
+// listGatewayPodIPs returns the sorted IPs of the active gateway pods running on the given node.
+func listGatewayPodIPs(ctx context.Context, cl client.Client, nodeName string) ([]string, error) {
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods, client.MatchingLabelsSelector{
+		Selector: labels.SelectorFromSet(gateway.ForgeActiveGatewayPodLabels()),
+	}); err != nil {
+		return nil, fmt.Errorf("unable to list the gateway pods: %w", err)
+	}
 
-	if len(fwcfg.Spec.Table.Chains[0].Rules.NatRules) == 0 {
-		if err := cl.Delete(ctx, &fwcfg); err != nil {
-			return err
+	var podIPs []string
+	for i := range pods.Items {
+		if pods.Items[i].Spec.NodeName == nodeName && pods.Items[i].Status.PodIP != "" && pods.Items[i].DeletionTimestamp.IsZero() {
+			podIPs = append(podIPs, pods.Items[i].Status.PodIP)
 		}
 	}
-
-	return nil
+	slices.Sort(podIPs)
+	return slices.Compact(podIPs), nil
 }
 
-func forgeFirewallPodUpdateFunction(internalnode *networkingv1beta1.InternalNode,
-	fwcfg *networkingv1beta1.FirewallConfiguration, pod *corev1.Pod, scheme *runtime.Scheme, genevePort uint16) controllerutil.MutateFn {
+func forgeFirewallNodeUpdateFunction(internalnode *networkingv1beta1.InternalNode,
+	fwcfg *networkingv1beta1.FirewallConfiguration, podIPs []string, scheme *runtime.Scheme, genevePort uint16) controllerutil.MutateFn {
 	return func() error {
 		if err := controllerutil.SetOwnerReference(internalnode, fwcfg, scheme); err != nil {
 			return err
@@ -106,108 +102,49 @@ func forgeFirewallPodUpdateFunction(internalnode *networkingv1beta1.InternalNode
 
 		fwcfg.Spec.Table.Name = ptr.To(generateFirewallConfigurationName(internalnode.Name))
 		fwcfg.Spec.Table.Family = ptr.To(firewall.TableFamilyIPv4)
-
-		if fwcfg.Spec.Table.Chains == nil || len(fwcfg.Spec.Table.Chains) == 0 {
-			fwcfg.Spec.Table.Chains = []firewall.Chain{{
-				Rules: firewall.RulesSet{
-					NatRules: []firewall.NatRule{},
-				},
-			}}
-		}
-		setFirewallPodChain(&fwcfg.Spec.Table.Chains[0])
-
-		rules := &fwcfg.Spec.Table.Chains[0].Rules.NatRules
-
-		if rule, exists := rulesContainsPod(pod, *rules); exists {
-			updatePodToFw(pod, rule, genevePort)
-		} else {
-			addPodToFw(pod, rules, genevePort)
-		}
+		fwcfg.Spec.Table.Sets = []firewall.Set{forgeGatewaysMap(podIPs)}
+		fwcfg.Spec.Table.Chains = []firewall.Chain{forgeFirewallChain(genevePort)}
 		return nil
 	}
 }
 
-func setFirewallPodChain(chain *firewall.Chain) {
-	chain.Name = ptr.To(PrePostroutingChainName)
-	chain.Type = firewall.ChainTypeNAT
-	chain.Hook = ptr.To(firewall.ChainHookPostrouting)
-	chain.Policy = ptr.To(firewall.ChainPolicyAccept)
-	chain.Priority = ptr.To(firewall.ChainPriorityNATSource - 1)
-}
-
-func forgeFirewallPodDeleteFunction(pod *corev1.Pod, fwcfg *networkingv1beta1.FirewallConfiguration) controllerutil.MutateFn {
-	return func() error {
-		if fwcfg.Spec.Table.Chains == nil || len(fwcfg.Spec.Table.Chains) != 1 {
-			return fmt.Errorf("firewall configuration table should contain only one chain, it contains %d", len(fwcfg.Spec.Table.Chains))
-		}
-		if _, exists := rulesContainsPod(pod, fwcfg.Spec.Table.Chains[0].Rules.NatRules); exists {
-			fwcfg.Spec.Table.Chains[0].Rules.NatRules = slices.DeleteFunc(fwcfg.Spec.Table.Chains[0].Rules.NatRules, func(r firewall.NatRule) bool {
-				return ruleMatchPod(pod, &r)
-			})
-		}
-		return nil
+// forgeGatewaysMap forges the map translating the IP of each gateway pod to itself.
+func forgeGatewaysMap(podIPs []string) firewall.Set {
+	elements := make([]firewall.SetElement, len(podIPs))
+	for i := range podIPs {
+		elements[i] = firewall.SetElement{Key: podIPs[i], Value: ptr.To(podIPs[i])}
 	}
-}
-
-func ruleMatchPod(pod *corev1.Pod, rule *firewall.NatRule) bool {
-	return rule.TargetRef.Name == pod.Name &&
-		rule.TargetRef.Namespace == pod.Namespace &&
-		rule.TargetRef.Kind == "Pod"
-}
-
-func rulesContainsPod(pod *corev1.Pod, rules []firewall.NatRule) (*firewall.NatRule, bool) {
-	for i := range rules {
-		if ruleMatchPod(pod, &rules[i]) {
-			return &rules[i], true
-		}
+	return firewall.Set{
+		Name:     GatewaysMapName,
+		KeyType:  firewall.SetDataTypeIPAddr,
+		DataType: ptr.To(firewall.SetDataTypeIPAddr),
+		Elements: elements,
 	}
-	return nil, false
-}
-
-func addPodToFw(pod *corev1.Pod, rules *[]firewall.NatRule, genevePort uint16) {
-	*rules = append(*rules, firewall.NatRule{
-		Name:    &pod.Name,
-		NatType: firewall.NatTypeSource,
-		To:      ptr.To(pod.Status.PodIP),
-		Match: []firewall.Match{
-			{
-				Op: firewall.MatchOperationEq,
-				IP: &firewall.MatchIP{
-					Value:    pod.Status.PodIP,
-					Position: firewall.MatchPositionSrc,
-				},
-				Port: &firewall.MatchPort{
-					Position: firewall.MatchPositionDst,
-					Value:    fmt.Sprintf("%d", genevePort),
-				},
-				Proto: &firewall.MatchProto{
-					Value: firewall.L4ProtoUDP,
-				},
-			},
-		},
-		TargetRef: &corev1.ObjectReference{Name: pod.Name, Namespace: pod.Namespace, Kind: pod.Kind},
-	})
 }
 
-func updatePodToFw(pod *corev1.Pod, rule *firewall.NatRule, genevePort uint16) {
-	rule.Name = ptr.To(pod.Name)
-	rule.NatType = firewall.NatTypeSource
-	rule.To = ptr.To(pod.Status.PodIP)
-	rule.Match = []firewall.Match{
-		{
-			Op: firewall.MatchOperationEq,
-			IP: &firewall.MatchIP{
-				Value:    pod.Status.PodIP,
-				Position: firewall.MatchPositionSrc,
-			},
-			Port: &firewall.MatchPort{
-				Position: firewall.MatchPositionDst,
-				Value:    fmt.Sprintf("%d", genevePort),
-			},
-			Proto: &firewall.MatchProto{
-				Value: firewall.L4ProtoUDP,
-			},
+// forgeFirewallChain forges the chain translating the source of the geneve traffic of the gateway pods to their own IP,
+// which prevents the following masquerade. The traffic of the other pods is not translated, as the lookup in the map fails.
+func forgeFirewallChain(genevePort uint16) firewall.Chain {
+	return firewall.Chain{
+		Name:     ptr.To(PrePostroutingChainName),
+		Type:     firewall.ChainTypeNAT,
+		Hook:     ptr.To(firewall.ChainHookPostrouting),
+		Policy:   ptr.To(firewall.ChainPolicyAccept),
+		Priority: ptr.To(firewall.ChainPriorityNATSource - 1),
+		Rules: firewall.RulesSet{
+			NatRules: []firewall.NatRule{{
+				Name:    ptr.To(GatewayMasqueradeBypassRuleName),
+				NatType: firewall.NatTypeSource,
+				To:      ptr.To(firewall.SetReferencePrefix + GatewaysMapName),
+				Match: []firewall.Match{{
+					Op:    firewall.MatchOperationEq,
+					Proto: &firewall.MatchProto{Value: firewall.L4ProtoUDP},
+					Port: &firewall.MatchPort{
+						Position: firewall.MatchPositionDst,
+						Value:    strconv.Itoa(int(genevePort)),
+					},
+				}},
+			}},
 		},
 	}
-	rule.TargetRef = &corev1.ObjectReference{Name: pod.Name, Namespace: pod.Namespace, Kind: pod.Kind}
 }
@@ -0,0 +1,105 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gwmasqbypass
+
+import (
+	"context"
+
+	"github.com/google/nftables"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+	"github.com/liqotech/liqo/pkg/gateway"
+)
+
+var _ = Describe("Gateway masquerade bypass", func() {
+	const namespace = "liqo"
+
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		cl     client.Client
+		opts   = &Options{Namespace: namespace, GenevePort: 6091}
+	)
+
+	gatewayPod := func(name, nodeName, podIP string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "liqo-tenant-remote", Labels: gateway.ForgeActiveGatewayPodLabels()},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{PodIP: podIP},
+		}
+	}
+
+	getFirewallConfiguration := func(nodeName string) (*networkingv1beta1.FirewallConfiguration, error) {
+		fwcfg := &networkingv1beta1.FirewallConfiguration{}
+		err := cl.Get(ctx, client.ObjectKey{Name: generateFirewallConfigurationName(nodeName), Namespace: namespace}, fwcfg)
+		return fwcfg, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(networkingv1beta1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&networkingv1beta1.InternalNode{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+			&networkingv1beta1.InternalNode{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+			gatewayPod("gw-1", "node-a", "10.0.0.2"),
+			gatewayPod("gw-2", "node-a", "10.0.0.1"),
+			gatewayPod("gw-3", "node-b", "10.0.1.1"),
+			gatewayPod("gw-4", "node-a", ""),
+		).Build()
+	})
+
+	It("should map the IPs of the gateway pods running on the node", func() {
+		Expect(enforceFirewallNode(ctx, cl, scheme, opts, "node-a")).ToNot(BeEmpty())
+
+		fwcfg, err := getFirewallConfiguration("node-a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fwcfg.Spec.Table.Sets).To(ConsistOf(firewall.Set{
+			Name: GatewaysMapName, KeyType: firewall.SetDataTypeIPAddr, DataType: ptr.To(firewall.SetDataTypeIPAddr),
+			Elements: []firewall.SetElement{{Key: "10.0.0.1", Value: ptr.To("10.0.0.1")}, {Key: "10.0.0.2", Value: ptr.To("10.0.0.2")}},
+		}))
+		Expect(fwcfg.Spec.Table.Chains).To(HaveLen(1))
+		Expect(fwcfg.Spec.Table.Chains[0].Rules.NatRules).To(HaveLen(1))
+
+		// The rule translates the source through the map, which must be valid.
+		wrapper := &firewallutils.NatRuleWrapper{NatRule: &fwcfg.Spec.Table.Chains[0].Rules.NatRules[0], Sets: fwcfg.Spec.Table.Sets}
+		Expect(firewallutils.CheckSetReferences(wrapper)).To(Succeed())
+		_, err = wrapper.Forge(&nftables.Chain{Table: &nftables.Table{Family: nftables.TableFamilyIPv4}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should remove the IPs of the deleted gateway pods, and the configuration when none is left", func() {
+		Expect(enforceFirewallNode(ctx, cl, scheme, opts, "node-b")).ToNot(BeEmpty())
+		_, err := getFirewallConfiguration("node-b")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cl.Delete(ctx, gatewayPod("gw-3", "node-b", "10.0.1.1"))).To(Succeed())
+		Expect(enforceFirewallNode(ctx, cl, scheme, opts, "node-b")).ToNot(BeEmpty())
+		_, err = getFirewallConfiguration("node-b")
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -30,7 +30,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/liqotech/liqo/pkg/consts"
@@ -46,7 +45,6 @@ type PodReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	EventsRecorder record.EventRecorder
-	GenericEvents  chan event.GenericEvent
 	Options        *Options
 }
 
@@ -58,7 +56,6 @@ func NewPodReconciler(cl client.Client, s *runtime.Scheme,
 		Scheme:         s,
 		EventsRecorder: er,
 		Options:        options,
-		GenericEvents:  make(chan event.GenericEvent),
 	}
 }
 
@@ -80,29 +77,38 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			Namespace: req.Namespace,
 		},
 	}
+	deleted := false
 	if err = r.Get(ctx, req.NamespacedName, pod); err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.Infof("There is no pod %s", req.String())
-			return ctrl.Result{}, enforceFirewallPodAbsence(ctx, r.Client, r.Options, pod)
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("unable to get the pod %q: %w", req.NamespacedName, err)
+		}
+		klog.Infof("There is no pod %s", req.String())
+		deleted = true
+		if pod.Spec.NodeName, err = GetPodNodeFromMap(req.NamespacedName); err != nil {
+			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, fmt.Errorf("unable to get the pod %q: %w", req.NamespacedName, err)
 	}
 
 	klog.V(4).Infof("Reconciling pod %s", req.String())
 
-	if pod.Spec.NodeName != "" {
-		PopulatePodKeyToNodeMap(pod)
-	} else {
+	if pod.Spec.NodeName == "" {
 		return ctrl.Result{}, nil
 	}
 
-	op, err := enforceFirewallPodPresence(ctx, r.Client, r.Scheme, r.Options, pod)
+	// The masquerade bypass is enforced for all the gateway pods running on the node at once, as they share the same map.
+	op, err := enforceFirewallNode(ctx, r.Client, r.Scheme, r.Options, pod.Spec.NodeName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	if deleted {
+		DeletePodKeyFromMap(req.NamespacedName)
+	} else {
+		PopulatePodKeyToNodeMap(pod)
+	}
+
 	if op != controllerutil.OperationResultNone {
-		klog.Infof("Updated gw-masquerade-bypass for pod %s", req.String())
+		klog.Infof("Updated gw-masquerade-bypass for node %s, reconciling pod %s", pod.Spec.NodeName, req.String())
 	}
 
 	return ctrl.Result{}, nil
@@ -118,6 +124,5 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPodGwMasq).
 		For(&corev1.Pod{}, builder.WithPredicates(p)).
-		WatchesRawSource(NewLeftoverPodsSource(r.GenericEvents, NewLeftoverPodsEventHandler())).
 		Complete(r)
 }
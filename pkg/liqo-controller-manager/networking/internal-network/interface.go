@@ -17,6 +17,7 @@ package internalnetwork
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -55,24 +56,32 @@ func FindFreeInterfaceName(ctx context.Context, cl client.Client, i interface{})
 	}
 }
 
-func findFreeInterfaceNameForInternalFabric(ctx context.Context, cl client.Client) (string, error) {
+// FindFreeReplicaInterfaceName returns a free interface name for an additional replica of the given InternalFabric.
+// If it cannot find a free name, it returns an error.
+func FindFreeReplicaInterfaceName(ctx context.Context, cl client.Client, internalFabric *networkingv1beta1.InternalFabric) (string, error) {
+	// The names of the given InternalFabric are considered as well, since they might not have been persisted yet.
+	return findFreeInterfaceNameForInternalFabric(ctx, cl, internalFabric)
+}
+
+func findFreeInterfaceNameForInternalFabric(ctx context.Context, cl client.Client, inUse ...*networkingv1beta1.InternalFabric) (string, error) {
 	list, err := getters.ListInternalFabricsByLabels(ctx, cl, labels.Everything())
 	if err != nil {
 		return "", fmt.Errorf("cannot list internal nodes: %w", err)
 	}
 
+	internalFabrics := inUse
+	for i := range list.Items {
+		internalFabrics = append(internalFabrics, &list.Items[i])
+	}
+
 	ok := false
 	retry := 0
 	var name string
 	for !ok && retry < maxretries {
 		name = forgeInterfaceName()
-		ok = true
-		for i := range list.Items {
-			if list.Items[i].Spec.Interface.Node.Name == name {
-				ok = false
-				break
-			}
-		}
+		ok = !slices.ContainsFunc(internalFabrics, func(internalFabric *networkingv1beta1.InternalFabric) bool {
+			return isInterfaceNameInUse(internalFabric, name)
+		})
 		retry++
 	}
 	if !ok {
@@ -106,3 +115,46 @@ func findFreeInterfaceNameForInternalNode(ctx context.Context, cl client.Client)
 	}
 	return name, nil
 }
+
+func isInterfaceNameInUse(internalFabric *networkingv1beta1.InternalFabric, name string) bool {
+	if internalFabric.Spec.Interface.Node.Name == name {
+		return true
+	}
+	return slices.ContainsFunc(internalFabric.Spec.Replicas, func(replica networkingv1beta1.InternalFabricSpecReplica) bool {
+		return replica.InterfaceName == name
+	})
+}
+
+// EnforceInternalFabricReplicas updates the additional replicas of the given InternalFabric according to the ready
+// replicas of an active-active gateway, except for the first one, which is already identified by the gateway IP.
+// The entries of the replicas which are no longer ready are preserved, without gateway IP, to keep the interface names stable.
+func EnforceInternalFabricReplicas(ctx context.Context, cl client.Client, internalFabric *networkingv1beta1.InternalFabric,
+	endpoint *networkingv1beta1.InternalGatewayEndpoint) error {
+	ready := make(map[int]networkingv1beta1.IP)
+	for i := 1; i < len(endpoint.Replicas); i++ {
+		ready[endpoint.Replicas[i].Index] = endpoint.Replicas[i].IP
+	}
+
+	for i := range internalFabric.Spec.Replicas {
+		replica := &internalFabric.Spec.Replicas[i]
+		replica.GatewayIP = ready[replica.Index]
+		delete(ready, replica.Index)
+	}
+
+	for index, ip := range ready {
+		name, err := FindFreeReplicaInterfaceName(ctx, cl, internalFabric)
+		if err != nil {
+			return err
+		}
+		internalFabric.Spec.Replicas = append(internalFabric.Spec.Replicas, networkingv1beta1.InternalFabricSpecReplica{
+			Index:         index,
+			InterfaceName: name,
+			GatewayIP:     ip,
+		})
+	}
+
+	slices.SortFunc(internalFabric.Spec.Replicas, func(a, b networkingv1beta1.InternalFabricSpecReplica) int {
+		return a.Index - b.Index
+	})
+	return nil
+}
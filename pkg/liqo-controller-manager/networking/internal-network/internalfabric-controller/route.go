@@ -68,15 +68,22 @@ func (r *InternalFabricReconciler) ensureRouteConfiguration(ctx context.Context,
 		sort.Slice(remoteCIDRs, func(i, j int) bool {
 			return remoteCIDRs[i] < remoteCIDRs[j]
 		})
+		nextHops := forgeReplicaNextHops(internalFabric)
 		for _, remoteCIDR := range remoteCIDRs {
-			rule := networkingv1beta1.Rule{
-				Routes: []networkingv1beta1.Route{
-					{
-						Dst: ptr.To(remoteCIDR),
-						Gw:  ptr.To(internalFabric.Spec.Interface.Gateway.IP),
-					},
-				},
+			remoteRoute := networkingv1beta1.Route{
 				Dst: ptr.To(remoteCIDR),
+				Gw:  ptr.To(internalFabric.Spec.Interface.Gateway.IP),
+			}
+			if nextHops != nil {
+				// Balance the traffic across the replicas of active-active gateways, which share the same gateway IP.
+				remoteRoute = networkingv1beta1.Route{
+					Dst:      ptr.To(remoteCIDR),
+					NextHops: nextHops,
+				}
+			}
+			rule := networkingv1beta1.Rule{
+				Routes: []networkingv1beta1.Route{remoteRoute},
+				Dst:    ptr.To(remoteCIDR),
 			}
 			rules = append(rules, rule)
 		}
@@ -98,6 +105,31 @@ func (r *InternalFabricReconciler) ensureRouteConfiguration(ctx context.Context,
 	return nil
 }
 
+// forgeReplicaNextHops returns the next hops towards the ready replicas of an active-active gateway,
+// through the corresponding interfaces. It returns nil if there are no additional ready replicas.
+func forgeReplicaNextHops(internalFabric *networkingv1beta1.InternalFabric) []networkingv1beta1.NextHop {
+	var nextHops []networkingv1beta1.NextHop
+	for i := range internalFabric.Spec.Replicas {
+		if internalFabric.Spec.Replicas[i].GatewayIP == "" {
+			continue
+		}
+		nextHops = append(nextHops, networkingv1beta1.NextHop{
+			Gw:     ptr.To(internalFabric.Spec.Interface.Gateway.IP),
+			Dev:    ptr.To(internalFabric.Spec.Replicas[i].InterfaceName),
+			Onlink: ptr.To(true),
+		})
+	}
+	if nextHops == nil {
+		return nil
+	}
+
+	return append([]networkingv1beta1.NextHop{{
+		Gw:     ptr.To(internalFabric.Spec.Interface.Gateway.IP),
+		Dev:    ptr.To(internalFabric.Spec.Interface.Node.Name),
+		Onlink: ptr.To(true),
+	}}, nextHops...)
+}
+
 // GenerateRouteConfigurationName returns the name of the RouteConfiguration associated to the InternalFabric.
 func GenerateRouteConfigurationName(internalFabric *networkingv1beta1.InternalFabric) string {
 	return fmt.Sprintf("%s-node-gw", internalFabric.Name)
@@ -0,0 +1,58 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internalnetwork
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// ForgeMTU returns the MTU of the internal fabric towards the given remote cluster, given the MTU of the gateway.
+// The MTU is lowered to the one the tunnel has been tuned to according to the discovered path MTU, if any.
+func ForgeMTU(ctx context.Context, cl client.Client, namespace string, remoteClusterID liqov1beta1.ClusterID, gatewayMTU int) (int, error) {
+	connection, err := getters.GetConnectionByClusterIDInNamespace(ctx, cl, string(remoteClusterID), namespace)
+	switch {
+	case apierrors.IsNotFound(err):
+		return gatewayMTU, nil
+	case err != nil:
+		return 0, err
+	}
+
+	if connection.Status.MTU > 0 && connection.Status.MTU < gatewayMTU {
+		return connection.Status.MTU, nil
+	}
+	return gatewayMTU, nil
+}
+
+// ConnectionEnqueuer returns a function mapping a Connection to the gateway of the given kind it refers to, if any.
+func ConnectionEnqueuer(gatewayKind string) func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		connection, ok := obj.(*networkingv1beta1.Connection)
+		if !ok || connection.Spec.GatewayRef.Kind != gatewayKind {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{
+			Name:      connection.Spec.GatewayRef.Name,
+			Namespace: connection.Spec.GatewayRef.Namespace,
+		}}}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internalnetwork
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("MTU", func() {
+	const (
+		namespace = "liqo-tenant-remote"
+		clusterID = "remote"
+	)
+
+	var (
+		cl         client.Client
+		connection *networkingv1beta1.Connection
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(networkingv1beta1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		connection = &networkingv1beta1.Connection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gw", Namespace: namespace,
+				Labels: map[string]string{consts.RemoteClusterID: clusterID},
+			},
+			Spec: networkingv1beta1.ConnectionSpec{
+				GatewayRef: corev1.ObjectReference{Kind: networkingv1beta1.GatewayServerKind, Name: "gw", Namespace: namespace},
+			},
+		}
+	})
+
+	It("should return the gateway MTU if the connection does not exist", func() {
+		Expect(ForgeMTU(ctx, cl, namespace, clusterID, 1340)).To(Equal(1340))
+	})
+
+	It("should return the gateway MTU if the tunnel has not been tuned", func() {
+		Expect(cl.Create(ctx, connection)).To(Succeed())
+		Expect(ForgeMTU(ctx, cl, namespace, clusterID, 1340)).To(Equal(1340))
+	})
+
+	It("should return the tuned MTU if lower than the gateway one", func() {
+		connection.Status.MTU = 1280
+		Expect(cl.Create(ctx, connection)).To(Succeed())
+		Expect(ForgeMTU(ctx, cl, namespace, clusterID, 1340)).To(Equal(1280))
+		Expect(ForgeMTU(ctx, cl, namespace, clusterID, 1200)).To(Equal(1200))
+	})
+
+	It("should enqueue only the gateways of the given kind", func() {
+		enqueue := ConnectionEnqueuer(networkingv1beta1.GatewayServerKind)
+		Expect(enqueue(ctx, connection)).To(ConsistOf(HaveField("NamespacedName", client.ObjectKey{Name: "gw", Namespace: namespace})))
+		Expect(ConnectionEnqueuer(networkingv1beta1.GatewayClientKind)(ctx, connection)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,80 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internalnetwork
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	netutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/utils"
+	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// ForgeRemoteCIDRs returns the (remapped) remote CIDRs routed through the gateway associated with the given Configuration,
+// including the ones of the clusters reached through the remote cluster acting as transit cluster.
+func ForgeRemoteCIDRs(ctx context.Context, cl client.Client, configuration *networkingv1beta1.Configuration) ([]networkingv1beta1.CIDR, error) {
+	remoteCIDRs := []networkingv1beta1.CIDR{
+		*cidrutils.GetPrimary(configuration.Status.Remote.CIDR.Pod),
+		*cidrutils.GetPrimary(configuration.Status.Remote.CIDR.External),
+	}
+
+	remoteClusterID, err := netutils.GetGatewayClusterID(configuration)
+	if err != nil {
+		return nil, err
+	}
+	transit, err := netutils.ListTransitConfigurations(ctx, cl, remoteClusterID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transit {
+		if !netutils.IsConfigurationStatusSet(transit[i].Status) {
+			continue
+		}
+		remoteCIDRs = append(remoteCIDRs,
+			*cidrutils.GetPrimary(transit[i].Status.Remote.CIDR.Pod),
+			*cidrutils.GetPrimary(transit[i].Status.Remote.CIDR.External),
+		)
+	}
+	return remoteCIDRs, nil
+}
+
+// TransitConfigurationEnqueuer returns a function mapping a Configuration of a cluster reached through a transit cluster
+// to the gateway of the given kind towards the latter, if any.
+func TransitConfigurationEnqueuer(cl client.Client, gatewayKind string) func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		configuration, ok := obj.(*networkingv1beta1.Configuration)
+		if !ok || !netutils.IsTransitConfiguration(configuration) {
+			return nil
+		}
+
+		gwServer, gwClient, err := getters.GetGatewaysByClusterID(ctx, cl, configuration.Spec.Transit.Via)
+		if err != nil {
+			klog.Errorf("Unable to get the gateways towards the transit cluster %q: %s", configuration.Spec.Transit.Via, err)
+			return nil
+		}
+		switch {
+		case gatewayKind == networkingv1beta1.GatewayServerKind && gwServer != nil:
+			return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(gwServer)}}
+		case gatewayKind == networkingv1beta1.GatewayClientKind && gwClient != nil:
+			return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(gwClient)}}
+		}
+		return nil
+	}
+}
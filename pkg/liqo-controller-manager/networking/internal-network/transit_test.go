@@ -0,0 +1,100 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internalnetwork
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
+)
+
+var _ = Describe("Transit", func() {
+	const (
+		hubID   = "hub"
+		spokeID = "spoke"
+	)
+
+	var (
+		cl       client.Client
+		hub      *networkingv1beta1.Configuration
+		spoke    *networkingv1beta1.Configuration
+		gwServer *networkingv1beta1.GatewayServer
+	)
+
+	forgeConfiguration := func(clusterID, pod, ext string) *networkingv1beta1.Configuration {
+		return &networkingv1beta1.Configuration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: clusterID, Namespace: "liqo-tenant-" + clusterID,
+				Labels: map[string]string{consts.RemoteClusterID: clusterID},
+			},
+			Status: networkingv1beta1.ConfigurationStatus{
+				Remote: &networkingv1beta1.ClusterConfig{CIDR: networkingv1beta1.ClusterConfigCIDR{
+					Pod:      cidrutils.SetPrimary(networkingv1beta1.CIDR(pod)),
+					External: cidrutils.SetPrimary(networkingv1beta1.CIDR(ext)),
+				}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(networkingv1beta1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		hub = forgeConfiguration(hubID, "10.71.0.0/16", "10.72.0.0/16")
+		spoke = forgeConfiguration(spokeID, "10.81.0.0/16", "10.82.0.0/16")
+		spoke.Spec.Transit = &networkingv1beta1.TransitConfig{Via: hubID}
+		gwServer = &networkingv1beta1.GatewayServer{ObjectMeta: metav1.ObjectMeta{
+			Name: hubID, Namespace: hub.Namespace,
+			Labels: map[string]string{consts.RemoteClusterID: hubID},
+		}}
+	})
+
+	It("should route only the remote CIDRs if no cluster is reached through the remote one", func() {
+		Expect(cl.Create(ctx, hub)).To(Succeed())
+		Expect(ForgeRemoteCIDRs(ctx, cl, hub)).To(ConsistOf(
+			networkingv1beta1.CIDR("10.71.0.0/16"), networkingv1beta1.CIDR("10.72.0.0/16")))
+	})
+
+	It("should route also the remote CIDRs of the clusters reached through the remote one", func() {
+		Expect(cl.Create(ctx, hub)).To(Succeed())
+		Expect(cl.Create(ctx, spoke)).To(Succeed())
+		Expect(ForgeRemoteCIDRs(ctx, cl, hub)).To(ConsistOf(
+			networkingv1beta1.CIDR("10.71.0.0/16"), networkingv1beta1.CIDR("10.72.0.0/16"),
+			networkingv1beta1.CIDR("10.81.0.0/16"), networkingv1beta1.CIDR("10.82.0.0/16")))
+	})
+
+	It("should skip the clusters reached through the remote one not configured yet", func() {
+		spoke.Status.Remote = nil
+		Expect(cl.Create(ctx, hub)).To(Succeed())
+		Expect(cl.Create(ctx, spoke)).To(Succeed())
+		Expect(ForgeRemoteCIDRs(ctx, cl, hub)).To(HaveLen(2))
+	})
+
+	It("should enqueue the gateway towards the transit cluster", func() {
+		Expect(cl.Create(ctx, gwServer)).To(Succeed())
+		Expect(TransitConfigurationEnqueuer(cl, networkingv1beta1.GatewayServerKind)(ctx, spoke)).To(ConsistOf(
+			HaveField("NamespacedName", client.ObjectKeyFromObject(gwServer))))
+		Expect(TransitConfigurationEnqueuer(cl, networkingv1beta1.GatewayClientKind)(ctx, spoke)).To(BeEmpty())
+		Expect(TransitConfigurationEnqueuer(cl, networkingv1beta1.GatewayServerKind)(ctx, hub)).To(BeEmpty())
+	})
+})
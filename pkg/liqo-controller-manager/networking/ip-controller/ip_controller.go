@@ -248,10 +248,14 @@ func (r *IPReconciler) handleNetworkRef(ctx context.Context, ip *ipamv1alpha1.IP
 // forgeIPStatus forge the IP status.
 func (r *IPReconciler) forgeIPStatus(ctx context.Context, ip *ipamv1alpha1.IP, cidr networkingv1beta1.CIDR) error {
 	// Update IP status if it is not set yet.
-	// The IPAM function that maps IPs is not idempotent, so we avoid to call it
-	// multiple times by checking if the IP is already set.
+	// The IPAM returns the same IP when the request is repeated by the same owner, but we
+	// still avoid to call it multiple times by checking if the IP is already set.
 	if ip.Status.IP == "" {
-		acquiredIP, err := acquireIP(ctx, r.ipamClient, cidr)
+		owner, err := ipam.OwnerFromObject(ip, r.Scheme)
+		if err != nil {
+			return err
+		}
+		acquiredIP, err := acquireIP(ctx, r.ipamClient, cidr, owner)
 		if err != nil {
 			return err
 		}
@@ -285,7 +289,9 @@ func (r *IPReconciler) handleIPStatusDeletion(ctx context.Context, ip *ipamv1alp
 }
 
 // acquireIP acquire a free IP of a given CIDR from the IPAM.
-func acquireIP(ctx context.Context, ipamClient ipam.IPAMClient, cidr networkingv1beta1.CIDR) (networkingv1beta1.IP, error) {
+// The owner makes the request idempotent, so that retrying it after a failure does not leak IPs.
+func acquireIP(ctx context.Context, ipamClient ipam.IPAMClient, cidr networkingv1beta1.CIDR,
+	owner *ipam.Owner) (networkingv1beta1.IP, error) {
 	switch ipamClient.(type) {
 	case nil:
 		// IPAM is not enabled, return an error.
@@ -293,7 +299,8 @@ func acquireIP(ctx context.Context, ipamClient ipam.IPAMClient, cidr networkingv
 	default:
 		// interact with the IPAM to retrieve the correct mapping.
 		response, err := ipamClient.IPAcquire(ctx, &ipam.IPAcquireRequest{
-			Cidr: string(cidr),
+			Cidr:  string(cidr),
+			Owner: owner,
 		})
 		if err != nil {
 			klog.Errorf("IPAM: error while acquiring IP from CIDR %q: %v", cidr, err)
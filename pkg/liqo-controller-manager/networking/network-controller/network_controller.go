@@ -120,14 +120,32 @@ func (r *NetworkReconciler) handleNetworkStatus(ctx context.Context, nw *ipamv1a
 		}
 
 		// Update Network status if it is not set yet
-		// The IPAM NetworkAcquire() function is not idempotent, so we avoid to call it
-		// multiple times by checking if the status is already set.
-		if nw.Status.CIDR == "" {
+		// The IPAM NetworkAcquire() function returns the same network when the request is repeated by the
+		// same owner, but we still avoid to call it multiple times by checking if the status is already set.
+		if nw.Status.CIDR == "" && nw.Spec.ReservedFor != "" {
+			// The Network reserves a range of the pools to a remote cluster, rather than allocating it.
+			if err := reserveCIDR(ctx, r.ipamClient, nw.Spec.CIDR, string(nw.Spec.ReservedFor)); err != nil {
+				return err
+			}
+
+			nw.Status.CIDR = nw.Spec.CIDR
+			if err := r.updateNetworkStatus(ctx, nw, true); err != nil {
+				return err
+			}
+		} else if nw.Status.CIDR == "" {
 			desiredCIDR := nw.Spec.CIDR
 			// if the Network must not be remapped, we acquire the network specifying to the IPAM that the cidr is immutable.
 			immutable := ipamutils.NetworkNotRemapped(nw)
 			preallocated := nw.Spec.PreAllocated
-			remappedCIDR, err := getRemappedCIDR(ctx, r.ipamClient, desiredCIDR, immutable, preallocated)
+			// The remote cluster the Network belongs to, if any, is used to honour its reservations.
+			clusterID := nw.Labels[consts.RemoteClusterID]
+			// The owner makes the request idempotent, so that retrying it after a failure does not leak networks.
+			owner, err := ipam.OwnerFromObject(nw, r.Scheme)
+			if err != nil {
+				return err
+			}
+			remappedCIDR, err := getRemappedCIDR(ctx, r.ipamClient, desiredCIDR, immutable, preallocated,
+				clusterID, nw.Spec.AllocationPolicy, owner)
 			if err != nil {
 				return err
 			}
@@ -148,7 +166,11 @@ func (r *NetworkReconciler) handleNetworkStatus(ctx context.Context, nw *ipamv1a
 				return err
 			}
 
-			if err := deleteRemappedCIDR(ctx, r.ipamClient, remappedCIDR); err != nil {
+			if nw.Spec.ReservedFor != "" {
+				if err := unreserveCIDR(ctx, r.ipamClient, remappedCIDR); err != nil {
+					return err
+				}
+			} else if err := deleteRemappedCIDR(ctx, r.ipamClient, remappedCIDR); err != nil {
 				return err
 			}
 		}
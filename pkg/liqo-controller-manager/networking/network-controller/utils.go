@@ -24,8 +24,8 @@ import (
 )
 
 // getRemappedCIDR returns the remapped CIDR for the given CIDR.
-func getRemappedCIDR(ctx context.Context, ipamClient ipam.IPAMClient,
-	desiredCIDR networkingv1beta1.CIDR, immutable bool, preallocated uint32) (networkingv1beta1.CIDR, error) {
+func getRemappedCIDR(ctx context.Context, ipamClient ipam.IPAMClient, desiredCIDR networkingv1beta1.CIDR,
+	immutable bool, preallocated uint32, clusterID, allocationPolicy string, owner *ipam.Owner) (networkingv1beta1.CIDR, error) {
 	switch ipamClient.(type) {
 	case nil:
 		// IPAM is not enabled, use original CIDR from spec
@@ -33,9 +33,12 @@ func getRemappedCIDR(ctx context.Context, ipamClient ipam.IPAMClient,
 	default:
 		// interact with the IPAM to retrieve the correct mapping.
 		response, err := ipamClient.NetworkAcquire(ctx, &ipam.NetworkAcquireRequest{
-			Cidr:         desiredCIDR.String(),
-			Immutable:    immutable,
-			PreAllocated: preallocated,
+			Cidr:             desiredCIDR.String(),
+			Immutable:        immutable,
+			PreAllocated:     preallocated,
+			ClusterID:        clusterID,
+			AllocationPolicy: allocationPolicy,
+			Owner:            owner,
 		})
 		if err != nil {
 			klog.Errorf("IPAM: error while mapping network CIDR %s: %v", desiredCIDR, err)
@@ -66,3 +69,42 @@ func deleteRemappedCIDR(ctx context.Context, ipamClient ipam.IPAMClient,
 		return nil
 	}
 }
+
+// reserveCIDR reserves the given CIDR to the given remote cluster.
+func reserveCIDR(ctx context.Context, ipamClient ipam.IPAMClient, cidr networkingv1beta1.CIDR, clusterID string) error {
+	switch ipamClient.(type) {
+	case nil:
+		// If the IPAM is not enabled there is nothing to reserve.
+		return nil
+	default:
+		_, err := ipamClient.NetworkReserve(ctx, &ipam.NetworkReserveRequest{
+			Cidr:      cidr.String(),
+			ClusterID: clusterID,
+		})
+		if err != nil {
+			klog.Errorf("IPAM: error while reserving CIDR %s to cluster %s: %v", cidr, clusterID, err)
+			return err
+		}
+		klog.Infof("IPAM: reserved CIDR %s to cluster %s", cidr, clusterID)
+		return nil
+	}
+}
+
+// unreserveCIDR removes the reservation of the given CIDR.
+func unreserveCIDR(ctx context.Context, ipamClient ipam.IPAMClient, cidr networkingv1beta1.CIDR) error {
+	switch ipamClient.(type) {
+	case nil:
+		// If the IPAM is not enabled there is nothing to unreserve.
+		return nil
+	default:
+		_, err := ipamClient.NetworkUnreserve(ctx, &ipam.NetworkUnreserveRequest{
+			Cidr: cidr.String(),
+		})
+		if err != nil {
+			klog.Errorf("IPAM: error while unreserving CIDR %s: %v", cidr, err)
+			return err
+		}
+		klog.Infof("IPAM: unreserved CIDR %s", cidr)
+		return nil
+	}
+}
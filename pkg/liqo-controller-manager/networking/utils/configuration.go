@@ -15,7 +15,17 @@
 package utils
 
 import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
 	cidrutils "github.com/liqotech/liqo/pkg/utils/cidr"
 )
 
@@ -25,3 +35,82 @@ func IsConfigurationStatusSet(confStatus networkingv1beta1.ConfigurationStatus)
 		!cidrutils.IsVoid(cidrutils.GetPrimary(confStatus.Remote.CIDR.Pod)) &&
 		!cidrutils.IsVoid(cidrutils.GetPrimary(confStatus.Remote.CIDR.External))
 }
+
+// IsTransitConfiguration checks if the remote cluster of a Configuration is reached through a transit cluster.
+func IsTransitConfiguration(cfg *networkingv1beta1.Configuration) bool {
+	return cfg.Spec.Transit != nil && cfg.Spec.Transit.Via != ""
+}
+
+// IsForwardingConfiguration checks if the traffic of the remote cluster of a Configuration
+// is forwarded to the other remote clusters with the forwarding enabled.
+func IsForwardingConfiguration(cfg *networkingv1beta1.Configuration) bool {
+	return cfg.Spec.Transit != nil && cfg.Spec.Transit.Forward && !IsTransitConfiguration(cfg)
+}
+
+// GetGatewayClusterID returns the ID of the remote cluster whose gateway handles the traffic of a Configuration,
+// i.e., the transit cluster, if set, or the remote cluster itself.
+func GetGatewayClusterID(cfg *networkingv1beta1.Configuration) (liqov1beta1.ClusterID, error) {
+	if IsTransitConfiguration(cfg) {
+		return cfg.Spec.Transit.Via, nil
+	}
+	remoteID, ok := cfg.GetLabels()[consts.RemoteClusterID]
+	if !ok {
+		return "", fmt.Errorf("configuration %s/%s has no remote cluster ID label", cfg.Namespace, cfg.Name)
+	}
+	return liqov1beta1.ClusterID(remoteID), nil
+}
+
+// ListTransitConfigurations returns the Configurations whose remote clusters are reached through the given transit cluster.
+func ListTransitConfigurations(ctx context.Context, cl client.Client, via liqov1beta1.ClusterID) ([]networkingv1beta1.Configuration, error) {
+	var list networkingv1beta1.ConfigurationList
+	if err := cl.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("unable to list configurations: %w", err)
+	}
+
+	var configurations []networkingv1beta1.Configuration
+	for i := range list.Items {
+		if IsTransitConfiguration(&list.Items[i]) && list.Items[i].Spec.Transit.Via == via {
+			configurations = append(configurations, list.Items[i])
+		}
+	}
+	return configurations, nil
+}
+
+// ListForwardingConfigurations returns the Configurations whose traffic is forwarded between each other,
+// excluding the one with the given name (if any).
+func ListForwardingConfigurations(ctx context.Context, cl client.Client, exclude client.ObjectKey) ([]networkingv1beta1.Configuration, error) {
+	var list networkingv1beta1.ConfigurationList
+	if err := cl.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("unable to list configurations: %w", err)
+	}
+
+	var configurations []networkingv1beta1.Configuration
+	for i := range list.Items {
+		if client.ObjectKeyFromObject(&list.Items[i]) == exclude || !IsForwardingConfiguration(&list.Items[i]) {
+			continue
+		}
+		configurations = append(configurations, list.Items[i])
+	}
+	return configurations, nil
+}
+
+// ForwardingConfigurationEnqueuer enqueues the forwarding Configurations when another forwarding one changes,
+// as the transit configuration depends on the remapped CIDRs of the others.
+func ForwardingConfigurationEnqueuer(cl client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		cfg, ok := obj.(*networkingv1beta1.Configuration)
+		if !ok || !IsForwardingConfiguration(cfg) {
+			return nil
+		}
+		forwarding, err := ListForwardingConfigurations(ctx, cl, client.ObjectKeyFromObject(cfg))
+		if err != nil {
+			klog.Errorf("unable to list the forwarding configurations: %s", err)
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(forwarding))
+		for i := range forwarding {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&forwarding[i])})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	liqodataplane "github.com/liqotech/liqo/pkg/dataplane"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+)
+
+// Options encapsulates the arguments of the dataplane command.
+type Options struct {
+	*factory.Factory
+
+	HostType        liqodataplane.HostType
+	NodeName        string
+	RemoteClusterID string
+
+	// The files containing the state captured from the host. When none is set, the desired state is rendered.
+	NftRulesetFile string
+	IPRulesFile    string
+	IPRoutesFile   string
+}
+
+// Run implements the dataplane command.
+func (o *Options) Run(ctx context.Context) error {
+	firewallSelectors, routeSelectors, err := liqodataplane.Selectors(o.HostType, o.NodeName, o.RemoteClusterID)
+	if err != nil {
+		return err
+	}
+
+	s := o.Printer.StartSpinner("Retrieving the FirewallConfigurations and RouteConfigurations")
+	fwcfgs, err := o.getFirewallConfigurations(ctx, firewallSelectors)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed retrieving the FirewallConfigurations: %v", err))
+		return err
+	}
+	rcfgs, err := o.getRouteConfigurations(ctx, routeSelectors)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed retrieving the RouteConfigurations: %v", err))
+		return err
+	}
+	s.Success(fmt.Sprintf("Retrieved %d FirewallConfigurations and %d RouteConfigurations", len(fwcfgs), len(rcfgs)))
+
+	if o.NftRulesetFile == "" && o.IPRulesFile == "" && o.IPRoutesFile == "" {
+		return o.render(fwcfgs, rcfgs)
+	}
+	return o.diff(fwcfgs, rcfgs)
+}
+
+// render prints the nftables ruleset and the ip commands corresponding to the given configurations.
+func (o *Options) render(fwcfgs []networkingv1beta1.FirewallConfiguration, rcfgs []networkingv1beta1.RouteConfiguration) error {
+	ruleset, err := liqodataplane.RenderFirewall(fwcfgs)
+	if err != nil {
+		return fmt.Errorf("failed to render the nftables ruleset: %w", err)
+	}
+	routes, err := liqodataplane.RenderRoutes(rcfgs)
+	if err != nil {
+		return fmt.Errorf("failed to render the routes: %w", err)
+	}
+
+	fmt.Print(ruleset)
+	if ruleset != "" && routes != "" {
+		fmt.Println()
+	}
+	fmt.Print(routes)
+	return nil
+}
+
+// diff compares the given configurations with the captured state, printing the divergences.
+// An error is returned if any divergence is detected, so that the command can be used in scripts.
+func (o *Options) diff(fwcfgs []networkingv1beta1.FirewallConfiguration, rcfgs []networkingv1beta1.RouteConfiguration) error {
+	diverged := false
+
+	if o.NftRulesetFile != "" {
+		captured, err := readFile(o.NftRulesetFile)
+		if err != nil {
+			return err
+		}
+		diff, err := liqodataplane.DiffFirewall(fwcfgs, captured)
+		if err != nil {
+			return fmt.Errorf("failed to compare the nftables ruleset: %w", err)
+		}
+		if diff != "" {
+			diverged = true
+			fmt.Print(diff)
+		} else {
+			o.Printer.Success.Println("The nftables ruleset matches the FirewallConfigurations")
+		}
+	}
+
+	if o.IPRulesFile != "" || o.IPRoutesFile != "" {
+		if o.IPRulesFile == "" || o.IPRoutesFile == "" {
+			return fmt.Errorf("both the captured ip rules and routes are required")
+		}
+		rules, err := readFile(o.IPRulesFile)
+		if err != nil {
+			return err
+		}
+		routes, err := readFile(o.IPRoutesFile)
+		if err != nil {
+			return err
+		}
+		diff, err := liqodataplane.DiffRoutes(rcfgs, rules, routes)
+		if err != nil {
+			return fmt.Errorf("failed to compare the routes: %w", err)
+		}
+		if !diff.Empty() {
+			diverged = true
+			fmt.Print(diff.String())
+		} else {
+			o.Printer.Success.Println("The ip rules and routes match the RouteConfigurations")
+		}
+	}
+
+	if diverged {
+		return fmt.Errorf("the captured state diverges from the desired one")
+	}
+	return nil
+}
+
+// getFirewallConfigurations returns the FirewallConfigurations matching any of the given selectors.
+func (o *Options) getFirewallConfigurations(ctx context.Context, selectors []labels.Set) ([]networkingv1beta1.FirewallConfiguration, error) {
+	var fwcfgs []networkingv1beta1.FirewallConfiguration
+	seen := map[client.ObjectKey]bool{}
+	for _, selector := range selectors {
+		var list networkingv1beta1.FirewallConfigurationList
+		if err := o.CRClient.List(ctx, &list, client.MatchingLabels(selector)); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if key := client.ObjectKeyFromObject(&list.Items[i]); !seen[key] {
+				seen[key] = true
+				fwcfgs = append(fwcfgs, list.Items[i])
+			}
+		}
+	}
+	return fwcfgs, nil
+}
+
+// getRouteConfigurations returns the RouteConfigurations matching any of the given selectors.
+func (o *Options) getRouteConfigurations(ctx context.Context, selectors []labels.Set) ([]networkingv1beta1.RouteConfiguration, error) {
+	var rcfgs []networkingv1beta1.RouteConfiguration
+	seen := map[client.ObjectKey]bool{}
+	for _, selector := range selectors {
+		var list networkingv1beta1.RouteConfigurationList
+		if err := o.CRClient.List(ctx, &list, client.MatchingLabels(selector)); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if key := client.ObjectKeyFromObject(&list.Items[i]); !seen[key] {
+				seen[key] = true
+				rcfgs = append(rcfgs, list.Items[i])
+			}
+		}
+	}
+	return rcfgs, nil
+}
+
+func readFile(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/ipam"
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+	"github.com/liqotech/liqo/pkg/leaderelection"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/utils/pod"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// OutputFormat is the format of the output of the ipam command.
+type OutputFormat string
+
+const (
+	// OutputTable prints the allocations as human-readable tables.
+	OutputTable OutputFormat = "table"
+	// OutputJSON prints the IPAM trees in JSON format.
+	OutputJSON OutputFormat = "json"
+	// OutputDOT prints the IPAM trees in the Graphviz DOT format.
+	OutputDOT OutputFormat = "dot"
+	// OutputMermaid prints the IPAM trees as a Mermaid flowchart.
+	OutputMermaid OutputFormat = "mermaid"
+)
+
+// Options encapsulates the arguments of the ipam command.
+type Options struct {
+	*factory.Factory
+
+	Pool    string
+	Format  OutputFormat
+	Timeout time.Duration
+}
+
+// State is the state of the IPAM, as returned by the IPAM server.
+type State struct {
+	// Snapshot contains the IPAM trees and reservations.
+	Snapshot *ipamcore.Snapshot
+	// SyncGracePeriod is the grace period before an allocation can be released by the IPAM sync routine.
+	SyncGracePeriod time.Duration
+}
+
+// Run implements the ipam command.
+func (o *Options) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	s := o.Printer.StartSpinner("Retrieving the IPAM state")
+	state, err := o.getState(ctx)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed retrieving the IPAM state: %v", err))
+		return err
+	}
+	s.Success("IPAM state correctly retrieved")
+
+	switch o.Format {
+	case OutputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(state.Snapshot)
+	case OutputDOT:
+		return state.Snapshot.WriteDOT(os.Stdout)
+	case OutputMermaid:
+		return state.Snapshot.WriteMermaid(os.Stdout)
+	default:
+		return o.printTables(state)
+	}
+}
+
+// getState retrieves the IPAM state from the IPAM server, through a port-forward to the leader IPAM pod.
+func (o *Options) getState(ctx context.Context) (*State, error) {
+	ipamPod, err := o.getIPAMPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := pod.PortForward(ctx, o.KubeClient, o.RESTConfig, ipamPod, consts.IpamPort)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the IPAM server: %w", err)
+	}
+	defer conn.Close()
+
+	res, err := ipam.NewIPAMClient(conn).GetState(ctx, &ipam.GetStateRequest{Pool: o.Pool})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the IPAM state: %w", err)
+	}
+
+	var snapshot ipamcore.Snapshot
+	if err := json.Unmarshal(res.GetSnapshot(), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode the IPAM state: %w", err)
+	}
+	return &State{Snapshot: &snapshot, SyncGracePeriod: time.Duration(res.GetSyncGracePeriod())}, nil
+}
+
+// getIPAMPod returns the running IPAM pod, preferring the leader in case of multiple replicas.
+func (o *Options) getIPAMPod(ctx context.Context) (*corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := o.CRClient.List(ctx, &pods, client.InNamespace(o.LiqoNamespace),
+		client.MatchingLabelsSelector{Selector: liqolabels.IPAMLabelSelector()}); err != nil {
+		return nil, fmt.Errorf("failed to list the IPAM pods: %w", err)
+	}
+
+	pods.Items = slices.DeleteFunc(pods.Items, func(p corev1.Pod) bool {
+		return p.Status.Phase != corev1.PodRunning || p.DeletionTimestamp != nil
+	})
+	switch len(pods.Items) {
+	case 0:
+		return nil, fmt.Errorf("no running IPAM pod found in namespace %q (is the internal IPAM enabled?)", o.LiqoNamespace)
+	case 1:
+		return &pods.Items[0], nil
+	}
+
+	for i := range pods.Items {
+		if leaderelection.IsLeaderPod(&pods.Items[i]) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no IPAM leader found among the %d running pods in namespace %q", len(pods.Items), o.LiqoNamespace)
+}
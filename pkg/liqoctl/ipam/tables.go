@@ -0,0 +1,159 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+// Tables contains the tables describing the IPAM state.
+type Tables struct {
+	Pools        pterm.TableData
+	Networks     pterm.TableData
+	IPs          pterm.TableData
+	Reservations pterm.TableData
+}
+
+// ForgeTables builds the tables describing the pools, the allocated networks and IPs, and the reservations of the given state.
+// The grace periods are computed with respect to the given time.
+func ForgeTables(state *State, now time.Time) (*Tables, error) {
+	pools := make([]netip.Prefix, len(state.Snapshot.Roots))
+	for i := range state.Snapshot.Roots {
+		pools[i] = state.Snapshot.Roots[i].Prefix
+	}
+	core, err := ipamcore.NewIpam(pools)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IPAM state: %w", err)
+	}
+	if err := core.Restore(state.Snapshot); err != nil {
+		return nil, fmt.Errorf("invalid IPAM state: %w", err)
+	}
+
+	tables := &Tables{
+		Pools:        pterm.TableData{{"Pool", "Networks", "IPs", "Utilization", "Fragmentation", "Largest free block"}},
+		Networks:     pterm.TableData{{"Network", "Pool", "Owner", "IPs", "Age", "Grace period"}},
+		IPs:          pterm.TableData{{"IP", "Network", "Owner", "Age", "Grace period"}},
+		Reservations: pterm.TableData{{"Range", "Cluster"}},
+	}
+
+	for _, stats := range core.PoolStats() {
+		largest := "-"
+		if stats.LargestFreeBlock.IsValid() {
+			largest = stats.LargestFreeBlock.String()
+		}
+		tables.Pools = append(tables.Pools, []string{
+			stats.Pool.String(), strconv.Itoa(stats.Networks), strconv.Itoa(stats.IPs),
+			formatPercentage(stats.Utilization), formatPercentage(stats.Fragmentation), largest,
+		})
+	}
+
+	for i := range state.Snapshot.Roots {
+		root := &state.Snapshot.Roots[i]
+		for _, network := range acquiredNodes(root) {
+			tables.Networks = append(tables.Networks, []string{
+				network.Prefix.String(), root.Prefix.String(), formatOwner(network.Owner), strconv.Itoa(len(network.IPs)),
+				formatAge(network.LastUpdateTimestamp, now), formatGracePeriod(network.LastUpdateTimestamp, state.SyncGracePeriod, now),
+			})
+			for j := range network.IPs {
+				ip := &network.IPs[j]
+				tables.IPs = append(tables.IPs, []string{
+					ip.Addr.String(), network.Prefix.String(), formatOwner(ip.Owner),
+					formatAge(ip.CreationTimestamp, now), formatGracePeriod(ip.CreationTimestamp, state.SyncGracePeriod, now),
+				})
+			}
+		}
+	}
+
+	for _, reservation := range state.Snapshot.Reservations {
+		tables.Reservations = append(tables.Reservations, []string{reservation.Prefix.String(), reservation.Owner})
+	}
+
+	return tables, nil
+}
+
+// printTables prints the tables describing the given state, omitting the empty ones.
+func (o *Options) printTables(state *State) error {
+	tables, err := ForgeTables(state, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		title string
+		data  pterm.TableData
+	}{
+		{"Pools", tables.Pools},
+		{"Networks", tables.Networks},
+		{"IPs", tables.IPs},
+		{"Reservations", tables.Reservations},
+	} {
+		if len(section.data) <= 1 {
+			continue
+		}
+		o.Printer.Section.Println(section.title)
+		if err := o.Printer.Table.WithData(section.data).Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acquiredNodes returns the acquired networks of the given tree, in address order.
+func acquiredNodes(node *ipamcore.NodeSnapshot) []*ipamcore.NodeSnapshot {
+	if node.Acquired {
+		return []*ipamcore.NodeSnapshot{node}
+	}
+	var nodes []*ipamcore.NodeSnapshot
+	for _, child := range []*ipamcore.NodeSnapshot{node.Left, node.Right} {
+		if child != nil {
+			nodes = append(nodes, acquiredNodes(child)...)
+		}
+	}
+	return nodes
+}
+
+func formatOwner(owner *ipamcore.Owner) string {
+	if owner == nil {
+		return "-"
+	}
+	return owner.String()
+}
+
+func formatPercentage(value float64) string {
+	return fmt.Sprintf("%.1f%%", value*100)
+}
+
+func formatAge(timestamp, now time.Time) string {
+	if timestamp.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(now.Sub(timestamp))
+}
+
+// formatGracePeriod describes whether the allocation is still protected from being released by the IPAM sync routine.
+func formatGracePeriod(timestamp time.Time, gracePeriod time.Duration, now time.Time) string {
+	if remaining := timestamp.Add(gracePeriod).Sub(now); !timestamp.IsZero() && remaining > 0 {
+		return fmt.Sprintf("%s left", duration.HumanDuration(remaining))
+	}
+	return "expired"
+}
@@ -0,0 +1,85 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ipamcore "github.com/liqotech/liqo/pkg/ipam/core"
+)
+
+var _ = Describe("Forging the IPAM tables", func() {
+	var (
+		state  *State
+		now    time.Time
+		tables *Tables
+	)
+
+	BeforeEach(func() {
+		pool := netip.MustParsePrefix("10.0.0.0/16")
+		network := netip.MustParsePrefix("10.0.1.0/24")
+
+		ipam, err := ipamcore.NewIpam([]netip.Prefix{pool})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipam.NetworkAcquireWithPrefix(network)).ToNot(BeNil())
+		Expect(ipam.NetworkSetOwner(network, ipamcore.Owner{Kind: "Network", Namespace: "default", Name: "foo"})).To(Succeed())
+		_, err = ipam.IPAcquire(network)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipam.Reserve(netip.MustParsePrefix("10.0.128.0/17"), "cluster-a")).To(Succeed())
+
+		now = time.Now()
+		state = &State{Snapshot: ipam.Snapshot(), SyncGracePeriod: time.Hour}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		tables, err = ForgeTables(state, now)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should describe the pools", func() {
+		Expect(tables.Pools).To(HaveLen(2))
+		Expect(tables.Pools[1]).To(HaveExactElements("10.0.0.0/16", "1", "1", "0.4%", HaveSuffix("%"), "10.0.128.0/17"))
+	})
+
+	It("should describe the networks and the IPs with their owners", func() {
+		Expect(tables.Networks).To(HaveLen(2))
+		Expect(tables.Networks[1][:4]).To(HaveExactElements("10.0.1.0/24", "10.0.0.0/16", "Network default/foo", "1"))
+		Expect(tables.Networks[1][5]).To(HaveSuffix("left"))
+
+		Expect(tables.IPs).To(HaveLen(2))
+		Expect(tables.IPs[1][:3]).To(HaveExactElements("10.0.1.0", "10.0.1.0/24", "-"))
+	})
+
+	It("should describe the reservations", func() {
+		Expect(tables.Reservations).To(HaveLen(2))
+		Expect(tables.Reservations[1]).To(HaveExactElements("10.0.128.0/17", "cluster-a"))
+	})
+
+	When("the grace period is expired", func() {
+		BeforeEach(func() {
+			now = now.Add(2 * time.Hour)
+		})
+
+		It("should report it", func() {
+			Expect(tables.Networks[1][5]).To(Equal("expired"))
+			Expect(tables.IPs[1][4]).To(Equal("expired"))
+		})
+	})
+})
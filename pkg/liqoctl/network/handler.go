@@ -24,6 +24,7 @@ import (
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	enutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/external-network/utils"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/forge"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/networking/getters"
 	"github.com/liqotech/liqo/pkg/liqoctl/factory"
@@ -46,6 +47,7 @@ type Options struct {
 	ServerServicePort           int32
 	ServerServiceNodePort       int32
 	ServerServiceLoadBalancerIP string
+	ServerAllowedClientCIDRs    argsutils.CIDRList
 
 	ClientGatewayType       string
 	ClientTemplateName      string
@@ -209,8 +211,8 @@ func (o *Options) RunConnect(ctx context.Context) error {
 		return err
 	}
 
-	// If sharing keys is disabled, return immediately
-	if o.DisableSharingKeys {
+	// If sharing keys is disabled, or the gateways do not rely on keys, return immediately
+	if o.DisableSharingKeys || o.plainGateways() {
 		return nil
 	}
 
@@ -344,18 +346,33 @@ func (o *Options) initNetworkConfigs(ctx context.Context, cluster1, cluster2 *Cl
 	return nil
 }
 
+// plainGateways returns whether both gateways are of the plain (unencrypted) type, hence not relying on the PublicKey resources.
+func (o *Options) plainGateways() bool {
+	serverGVR, err := enutils.ParseGroupVersionResource(o.ServerGatewayType)
+	if err != nil {
+		return false
+	}
+	clientGVR, err := enutils.ParseGroupVersionResource(o.ClientGatewayType)
+	if err != nil {
+		return false
+	}
+	return serverGVR == networkingv1beta1.PlainGatewayServerTemplateGroupVersionResource &&
+		clientGVR == networkingv1beta1.PlainGatewayClientTemplateGroupVersionResource
+}
+
 func (o *Options) newGatewayServerForgeOptions(kubeClient kubernetes.Interface, remoteClusterID liqov1beta1.ClusterID) *forge.GwServerOptions {
 	return &forge.GwServerOptions{
-		KubeClient:        kubeClient,
-		RemoteClusterID:   remoteClusterID,
-		GatewayType:       o.ServerGatewayType,
-		TemplateName:      o.ServerTemplateName,
-		TemplateNamespace: o.ServerTemplateNamespace,
-		ServiceType:       corev1.ServiceType(o.ServerServiceType.Value),
-		MTU:               o.MTU,
-		Port:              o.ServerServicePort,
-		NodePort:          ptr.To(o.ServerServiceNodePort),
-		LoadBalancerIP:    ptr.To(o.ServerServiceLoadBalancerIP),
+		KubeClient:         kubeClient,
+		RemoteClusterID:    remoteClusterID,
+		GatewayType:        o.ServerGatewayType,
+		TemplateName:       o.ServerTemplateName,
+		TemplateNamespace:  o.ServerTemplateNamespace,
+		ServiceType:        corev1.ServiceType(o.ServerServiceType.Value),
+		MTU:                o.MTU,
+		Port:               o.ServerServicePort,
+		NodePort:           ptr.To(o.ServerServiceNodePort),
+		LoadBalancerIP:     ptr.To(o.ServerServiceLoadBalancerIP),
+		AllowedClientCIDRs: o.ServerAllowedClientCIDRs.StringList.StringList,
 	}
 }
 
@@ -83,6 +83,8 @@ func (o *Options) Create(ctx context.Context, options *rest.CreateOptions) *cobr
 		"Force the NodePort of the Gateway Server. Leave empty to let Kubernetes allocate a random NodePort")
 	cmd.Flags().StringVar(&o.LoadBalancerIP, "load-balancer-ip", "",
 		"Force LoadBalancer IP of the Gateway Server. Leave empty to use the one provided by the LoadBalancer provider")
+	cmd.Flags().Var(&o.AllowedClientCIDRs, "allowed-client-cidrs",
+		"CIDRs the Gateway Client is allowed to connect from. Required by the plain Gateway Server, which otherwise refuses all the clients")
 	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the Gateway Server to be ready")
 
 	runtime.Must(cmd.MarkFlagRequired("remote-cluster-id"))
@@ -28,17 +28,18 @@ type Options struct {
 	createOptions *rest.CreateOptions
 	deleteOptions *rest.DeleteOptions
 
-	RemoteClusterID   argsutils.ClusterIDFlags
-	GatewayType       string
-	TemplateName      string
-	TemplateNamespace string
-	ServiceType       *argsutils.StringEnum
-	MTU               int
-	Port              int32
-	NodePort          int32
-	LoadBalancerIP    string
-	Proxy             bool
-	Wait              bool
+	RemoteClusterID    argsutils.ClusterIDFlags
+	GatewayType        string
+	TemplateName       string
+	TemplateNamespace  string
+	ServiceType        *argsutils.StringEnum
+	MTU                int
+	Port               int32
+	NodePort           int32
+	LoadBalancerIP     string
+	AllowedClientCIDRs argsutils.CIDRList
+	Proxy              bool
+	Wait               bool
 }
 
 var _ rest.API = &Options{}
@@ -65,15 +66,16 @@ func (o *Options) getForgeOptions() *forge.GwServerOptions {
 	}
 
 	return &forge.GwServerOptions{
-		KubeClient:        o.createOptions.KubeClient,
-		RemoteClusterID:   o.RemoteClusterID.GetClusterID(),
-		GatewayType:       o.GatewayType,
-		TemplateName:      o.TemplateName,
-		TemplateNamespace: o.TemplateNamespace,
-		ServiceType:       corev1.ServiceType(o.ServiceType.Value),
-		MTU:               o.MTU,
-		Port:              o.Port,
-		NodePort:          ptr.To(o.NodePort),
-		LoadBalancerIP:    ptr.To(o.LoadBalancerIP),
+		KubeClient:         o.createOptions.KubeClient,
+		RemoteClusterID:    o.RemoteClusterID.GetClusterID(),
+		GatewayType:        o.GatewayType,
+		TemplateName:       o.TemplateName,
+		TemplateNamespace:  o.TemplateNamespace,
+		ServiceType:        corev1.ServiceType(o.ServiceType.Value),
+		MTU:                o.MTU,
+		Port:               o.Port,
+		NodePort:           ptr.To(o.NodePort),
+		LoadBalancerIP:     ptr.To(o.LoadBalancerIP),
+		AllowedClientCIDRs: o.AllowedClientCIDRs.StringList.StringList,
 	}
 }
@@ -0,0 +1,405 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/gateway"
+	gwbenchmark "github.com/liqotech/liqo/pkg/gateway/benchmark"
+	"github.com/liqotech/liqo/pkg/liqoctl/test/network/check"
+	"github.com/liqotech/liqo/pkg/liqoctl/test/network/client"
+	"github.com/liqotech/liqo/pkg/liqoctl/test/network/flags"
+	"github.com/liqotech/liqo/pkg/liqoctl/test/network/setup"
+	podutils "github.com/liqotech/liqo/pkg/liqoctl/utils/pod"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	timeutils "github.com/liqotech/liqo/pkg/utils/time"
+)
+
+const (
+	// maxRetries is the maximum number of attempts to connect to the benchmark server, which may be not yet listening.
+	maxRetries = 5
+	// retryInterval is the interval between two attempts to connect to the benchmark server.
+	retryInterval = time.Second
+	// gatewayContainerName is the name of the container of the gateway pods, whose image embeds the traffic generator.
+	gatewayContainerName = "gateway"
+	// generatorCommand is the command running the traffic generator embedded in the gateway image.
+	generatorCommand = "liqo-component benchmark"
+	// podPrefix is the prefix of the name of the pods running the pod-to-pod benchmark.
+	podPrefix = "liqo-benchmark"
+
+	// KindGateway identifies the benchmarks between gateways.
+	KindGateway = "gateway"
+	// KindPod identifies the benchmarks between pods.
+	KindPod = "pod"
+)
+
+// Result contains the results of a benchmark between two endpoints.
+type Result struct {
+	// Kind is the kind of the endpoints (i.e., gateway or pod).
+	Kind string
+	// Source is the endpoint generating the traffic.
+	Source string
+	// Target is the endpoint receiving the traffic.
+	Target     string
+	Throughput *gwbenchmark.Throughput
+	Latency    *gwbenchmark.Latency
+	Err        error
+}
+
+// endpoint is a container the traffic generator is executed in.
+type endpoint struct {
+	pod       *corev1.Pod
+	container string
+	clset     *kubernetes.Clientset
+	cfg       *rest.Config
+}
+
+func (e *endpoint) exec(ctx context.Context, cmd string) (stdout, stderr string, err error) {
+	return podutils.ExecInContainer(ctx, e.clset, e.cfg, e.pod, e.container, cmd)
+}
+
+// RunBenchmarks measures the throughput and the latency between the gateways of the consumer and of each provider,
+// and, if enabled, between pods running in the consumer and in each provider.
+func RunBenchmarks(ctx context.Context, cl *client.Client, cfg client.Configs, opts *flags.Options) error {
+	logger := opts.Topts.LocalFactory.Printer.Logger
+	providers := slices.Sorted(maps.Keys(cl.Providers))
+
+	var results []Result
+	for _, provider := range providers {
+		logger.Info("Running benchmark between gateways", logger.Args("consumer", cl.ConsumerName, "provider", provider))
+		results = append(results, benchmarkGateways(ctx, cl, cfg, opts, provider)...)
+	}
+
+	if opts.BenchmarkPods {
+		for _, provider := range providers {
+			logger.Info("Running benchmark between pods", logger.Args("consumer", cl.ConsumerName, "provider", provider))
+			results = append(results, benchmarkPods(ctx, cl, cfg, opts, provider))
+		}
+	}
+
+	var failed int
+	for i := range results {
+		if results[i].Err != nil {
+			failed++
+			logger.Error("Benchmark failed", logger.Args(
+				"source", results[i].Source, "target", results[i].Target, "error", results[i].Err,
+			))
+		}
+	}
+
+	pterm.Println("")
+	if err := opts.Topts.LocalFactory.Printer.Table.WithData(ForgeTableData(results)).Render(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d benchmarks failed", failed)
+	}
+	return nil
+}
+
+// ForgeTableData creates the table data summarizing the results of the benchmarks.
+func ForgeTableData(results []Result) pterm.TableData {
+	td := pterm.TableData{
+		{"Kind", "Source", "Target", "Throughput (sent/received)", "Retransmits", "Latency (min/p50/p90/p99/max)", "Loss"},
+	}
+	for i := range results {
+		row := []string{results[i].Kind, results[i].Source, results[i].Target, "N/A", "N/A", "N/A", "N/A"}
+		if t := results[i].Throughput; t != nil {
+			row[3] = fmt.Sprintf("%s / %s", formatThroughput(t.Sent), formatThroughput(t.Received))
+			row[4] = strconv.Itoa(t.Retransmits)
+		}
+		if l := results[i].Latency; l != nil {
+			if l.Samples > 0 {
+				row[5] = fmt.Sprintf("%s / %s / %s / %s / %s",
+					timeutils.FormatLatency(l.Min), timeutils.FormatLatency(l.P50), timeutils.FormatLatency(l.P90),
+					timeutils.FormatLatency(l.P99), timeutils.FormatLatency(l.Max))
+			}
+			row[6] = fmt.Sprintf("%.1f%%", l.PacketLoss)
+		}
+		td = append(td, row)
+	}
+	return td
+}
+
+// benchmarkGateways runs the benchmark between each replica of the consumer gateway towards the given provider,
+// and the replica of the provider gateway the tunnel is established with.
+func benchmarkGateways(ctx context.Context, cl *client.Client, cfg client.Configs, opts *flags.Options, provider string) []Result {
+	fail := func(err error) []Result {
+		return []Result{{Kind: KindGateway, Source: cl.ConsumerName, Target: provider, Err: err}}
+	}
+
+	localPods, mode, err := activeGatewayPods(ctx, cl.Consumer, provider)
+	if err != nil {
+		return fail(fmt.Errorf("consumer: %w", err))
+	}
+	remotePods, _, err := activeGatewayPods(ctx, cl.Providers[provider], cl.ConsumerName)
+	if err != nil {
+		return fail(fmt.Errorf("provider: %w", err))
+	}
+
+	localClset, err := check.InitClientSet(cfg[cl.ConsumerName])
+	if err != nil {
+		return fail(err)
+	}
+	remoteClset, err := check.InitClientSet(cfg[provider])
+	if err != nil {
+		return fail(err)
+	}
+
+	var results []Result
+	for _, index := range slices.Sorted(maps.Keys(localPods)) {
+		local := localPods[index]
+		result := Result{Kind: KindGateway, Source: cl.ConsumerName + "/" + local.Name, Target: provider}
+
+		// In active-active mode, the tunnel of each replica is established with the remote replica with the same index.
+		remote, ok := remotePods[index]
+		if !ok {
+			result.Err = fmt.Errorf("no active replica of the provider gateway with index %q", index)
+			results = append(results, result)
+			continue
+		}
+		result.Target = provider + "/" + remote.Name
+
+		// The traffic generator is embedded in the gateway image, and runs in the gateway container itself.
+		// The client targets the tunnel address of the peer gateway, which is inferred from the mode of the local one.
+		sender := &endpoint{pod: local, container: gatewayContainerName, clset: localClset, cfg: cfg[cl.ConsumerName]}
+		receiver := &endpoint{pod: remote, container: gatewayContainerName, clset: remoteClset, cfg: cfg[provider]}
+		result.Throughput, result.Latency, result.Err = measure(ctx, opts, sender, receiver, "--mode "+mode.String())
+		results = append(results, result)
+	}
+	return results
+}
+
+// benchmarkPods runs the benchmark between a pod running in the consumer and one offloaded to the given provider.
+// The pods run the gateway image, to embed the traffic generator, and are deleted once the benchmark completes.
+func benchmarkPods(ctx context.Context, cl *client.Client, cfg client.Configs, opts *flags.Options, provider string) Result {
+	result := Result{Kind: KindPod, Source: cl.ConsumerName, Target: provider}
+
+	image, err := gatewayImage(ctx, cl.Consumer, provider)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Both pods are created and accessed through the consumer cluster, where the IP of the remote pod is the remapped one.
+	clset, err := check.InitClientSet(cfg[cl.ConsumerName])
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	local, err := runBenchmarkPod(ctx, cl.Consumer, image, cl.ConsumerName, opts.Topts.Timeout)
+	if local != nil {
+		defer deleteBenchmarkPod(cl.Consumer, local)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("consumer: %w", err)
+		return result
+	}
+	remote, err := runBenchmarkPod(ctx, cl.Consumer, image, provider, opts.Topts.Timeout)
+	if remote != nil {
+		defer deleteBenchmarkPod(cl.Consumer, remote)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("provider: %w", err)
+		return result
+	}
+	result.Source = cl.ConsumerName + "/" + local.Name
+	result.Target = provider + "/" + remote.Name
+
+	sender := &endpoint{pod: local, container: gatewayContainerName, clset: clset, cfg: cfg[cl.ConsumerName]}
+	receiver := &endpoint{pod: remote, container: gatewayContainerName, clset: clset, cfg: cfg[cl.ConsumerName]}
+	result.Throughput, result.Latency, result.Err = measure(ctx, opts, sender, receiver, "--address "+remote.Status.PodIP)
+	return result
+}
+
+// measure runs the traffic generator server in the receiver, and the client in the sender, targeting the server
+// according to the given arguments. It returns the throughput and the latency measured by the client.
+func measure(ctx context.Context, opts *flags.Options, sender, receiver *endpoint,
+	target string) (*gwbenchmark.Throughput, *gwbenchmark.Latency, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The server terminates once the client completes, or at the latest when the command times out.
+	serverErr := make(chan error, 1)
+	go func() {
+		_, stderr, err := receiver.exec(ctx, fmt.Sprintf("%s server --port %d --timeout %s",
+			generatorCommand, gwbenchmark.DefaultPort, opts.Topts.Timeout))
+		if err != nil {
+			err = fmt.Errorf("failed to run the benchmark server in pod %q: %w (%s)", receiver.pod.Name, err, stderr)
+		}
+		serverErr <- err
+	}()
+
+	cmd := fmt.Sprintf("%s client %s --port %d --duration %s --streams %d --pings %d",
+		generatorCommand, target, gwbenchmark.DefaultPort, opts.BenchmarkDuration, max(opts.BenchmarkStreams, 1), max(opts.BenchmarkPings, 1))
+
+	var result *gwbenchmark.Result
+	if _, err := podutils.TryFor(ctx, maxRetries, func() (bool, error) {
+		stdout, stderr, err := sender.exec(ctx, cmd)
+		if err == nil {
+			result, err = parseResult(stdout)
+			return err == nil, err
+		}
+		err = fmt.Errorf("failed to run the benchmark client in pod %q: %w (%s)", sender.pod.Name, err, stderr)
+
+		// The server may be not yet listening, or it may have failed.
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case serr := <-serverErr:
+			if serr == nil {
+				serr = fmt.Errorf("the benchmark server in pod %q terminated unexpectedly", receiver.pod.Name)
+			}
+			return false, serr
+		case <-time.After(retryInterval):
+			return false, err
+		}
+	}); err != nil {
+		return nil, nil, err
+	}
+	return result.Throughput, result.Latency, nil
+}
+
+// activeGatewayPods returns the active pods of the gateway towards the given remote cluster, indexed by replica index,
+// together with the mode of the gateway. In active-passive mode, the only active pod has no index.
+func activeGatewayPods(ctx context.Context, cl ctrlclient.Client, remoteClusterID string) (map[string]*corev1.Pod, gateway.Mode, error) {
+	gw, mode, err := getGateway(ctx, cl, remoteClusterID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pods := corev1.PodList{}
+	if err := cl.List(ctx, &pods, ctrlclient.InNamespace(gw.GetNamespace()), ctrlclient.MatchingLabels{
+		consts.GatewayNameLabel:      gw.GetName(),
+		consts.GatewayNamespaceLabel: gw.GetNamespace(),
+		consts.GatewayActiveLabel:    consts.GatewayActiveLabelValue,
+	}); err != nil {
+		return nil, "", fmt.Errorf("failed to list the gateway pods: %w", err)
+	}
+
+	active := make(map[string]*corev1.Pod)
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			active[pods.Items[i].Labels[consts.GatewayReplicaIndexLabel]] = &pods.Items[i]
+		}
+	}
+	if len(active) == 0 {
+		return nil, "", fmt.Errorf("no active pod of gateway %q", gw.GetName())
+	}
+	return active, mode, nil
+}
+
+// getGateway returns the gateway towards the given remote cluster, together with its mode.
+func getGateway(ctx context.Context, cl ctrlclient.Client, remoteClusterID string) (ctrlclient.Object, gateway.Mode, error) {
+	gwServer, gwClient, err := getters.GetGatewaysByClusterID(ctx, cl, liqov1beta1.ClusterID(remoteClusterID))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve the gateway towards cluster %q: %w", remoteClusterID, err)
+	}
+
+	switch {
+	case gwServer != nil:
+		return gwServer, gateway.ModeServer, nil
+	case gwClient != nil:
+		return gwClient, gateway.ModeClient, nil
+	default:
+		return nil, "", fmt.Errorf("no gateway towards cluster %q", remoteClusterID)
+	}
+}
+
+// gatewayImage returns the image of the gateway towards the given remote cluster, which embeds the traffic generator.
+func gatewayImage(ctx context.Context, cl ctrlclient.Client, remoteClusterID string) (string, error) {
+	pods, _, err := activeGatewayPods(ctx, cl, remoteClusterID)
+	if err != nil {
+		return "", fmt.Errorf("consumer: %w", err)
+	}
+	for _, pod := range pods {
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == gatewayContainerName {
+				return pod.Spec.Containers[i].Image, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("consumer: no %q container in the gateway pods", gatewayContainerName)
+}
+
+// runBenchmarkPod creates a pod running the given gateway image in the given cluster, and waits for it to be running.
+// The pod terminates on its own after the given lifetime, in case it is not deleted.
+func runBenchmarkPod(ctx context.Context, cl ctrlclient.Client, image, owner string, lifetime time.Duration) (*corev1.Pod, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: podPrefix + "-" + owner + "-",
+			Namespace:    setup.NamespaceName,
+			Labels:       map[string]string{setup.PodLabelApp: podPrefix},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    gatewayContainerName,
+				Image:   image,
+				Command: []string{"sleep", strconv.Itoa(max(int(lifetime.Seconds()), 1))},
+			}},
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         ptr.To(max(int64(lifetime.Seconds()), 1)),
+			TerminationGracePeriodSeconds: ptr.To[int64](0),
+			NodeSelector:                  map[string]string{consts.RemoteClusterID: owner},
+		},
+	}
+	if err := cl.Create(ctx, pod); err != nil {
+		return nil, fmt.Errorf("failed to create the benchmark pod: %w", err)
+	}
+
+	if err := wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (done bool, err error) {
+		if err := cl.Get(ctx, ctrlclient.ObjectKeyFromObject(pod), pod); err != nil {
+			return false, err
+		}
+		switch pod.Status.Phase {
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return false, fmt.Errorf("pod %q terminated", pod.Name)
+		case corev1.PodRunning:
+			return pod.Status.PodIP != "", nil
+		default:
+			return false, nil
+		}
+	}); err != nil {
+		return pod, fmt.Errorf("failed waiting for the benchmark pod %q to be running: %w", pod.Name, err)
+	}
+	return pod, nil
+}
+
+// deleteBenchmarkPod deletes the given benchmark pod. It uses a new context, as the benchmark one may be already expired.
+func deleteBenchmarkPod(cl ctrlclient.Client, pod *corev1.Pod) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	// Errors are ignored, as the pod terminates anyway once its deadline expires.
+	_ = ctrlclient.IgnoreNotFound(cl.Delete(ctx, pod))
+}
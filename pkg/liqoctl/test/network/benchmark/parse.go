@@ -0,0 +1,46 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gwbenchmark "github.com/liqotech/liqo/pkg/gateway/benchmark"
+)
+
+// parseResult parses the JSON output of the traffic generator client.
+func parseResult(output string) (*gwbenchmark.Result, error) {
+	var result gwbenchmark.Result
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse the benchmark output: %w", err)
+	}
+	if result.Throughput == nil || result.Latency == nil {
+		return nil, fmt.Errorf("incomplete benchmark output: %q", output)
+	}
+	return &result, nil
+}
+
+// formatThroughput formats a throughput in bits per second.
+func formatThroughput(bps float64) string {
+	switch {
+	case bps >= 1e9:
+		return fmt.Sprintf("%.2f Gbit/s", bps/1e9)
+	case bps >= 1e6:
+		return fmt.Sprintf("%.2f Mbit/s", bps/1e6)
+	default:
+		return fmt.Sprintf("%.2f kbit/s", bps/1e3)
+	}
+}
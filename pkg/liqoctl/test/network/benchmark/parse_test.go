@@ -0,0 +1,60 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	gwbenchmark "github.com/liqotech/liqo/pkg/gateway/benchmark"
+)
+
+var _ = Describe("Parsing the benchmark outputs", func() {
+	It("should parse the output of the traffic generator", func() {
+		result, err := parseResult(`{"throughput":{"sent":1e9,"received":9.9e8,"retransmits":12},
+			"latency":{"samples":4,"packetLoss":20,"min":1000000,"p50":2000000,"p90":4000000,"p99":4000000,"max":4000000}}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Throughput).To(Equal(&gwbenchmark.Throughput{Sent: 1e9, Received: 9.9e8, Retransmits: 12}))
+		Expect(result.Latency.Samples).To(Equal(4))
+		Expect(result.Latency.PacketLoss).To(BeNumerically("~", 20))
+		Expect(result.Latency.Min).To(Equal(time.Millisecond))
+		Expect(result.Latency.P50).To(Equal(2 * time.Millisecond))
+		Expect(result.Latency.Max).To(Equal(4 * time.Millisecond))
+	})
+
+	It("should fail if the output is not JSON", func() {
+		_, err := parseResult("Error: unknown command")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if a measurement is missing", func() {
+		_, err := parseResult(`{"throughput":{"sent":1e9,"received":9.9e8}}`)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("summarizing the results", func() {
+		It("should report the unavailable measurements", func() {
+			td := ForgeTableData([]Result{
+				{Kind: KindGateway, Source: "a", Target: "b", Throughput: &gwbenchmark.Throughput{Sent: 2.5e8, Received: 2.4e8, Retransmits: 3}},
+				{Kind: KindPod, Source: "a", Target: "c"},
+			})
+			Expect(td).To(HaveLen(3))
+			Expect(td[1]).To(Equal([]string{KindGateway, "a", "b", "250.00 Mbit/s / 240.00 Mbit/s", "3", "N/A", "N/A"}))
+			Expect(td[2]).To(Equal([]string{KindPod, "a", "c", "N/A", "N/A", "N/A", "N/A"}))
+		})
+	})
+})
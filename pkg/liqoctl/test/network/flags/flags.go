@@ -15,6 +15,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 )
 
@@ -40,6 +42,16 @@ const (
 	FlagNamesPodNodeport FlagNames = "pod-np"
 	// FlagNamesIP is the flag that enables IP remapping for the tests.
 	FlagNamesIP FlagNames = "ip"
+	// FlagNamesBenchmark is the flag that runs the throughput and latency benchmark instead of the checks.
+	FlagNamesBenchmark FlagNames = "benchmark"
+	// FlagNamesBenchmarkPods is the flag that enables the pod-to-pod benchmark.
+	FlagNamesBenchmarkPods FlagNames = "benchmark-pods"
+	// FlagNamesBenchmarkDuration is the flag that sets the duration of each throughput measurement.
+	FlagNamesBenchmarkDuration FlagNames = "benchmark-duration"
+	// FlagNamesBenchmarkStreams is the flag that sets the number of parallel streams of each throughput measurement.
+	FlagNamesBenchmarkStreams FlagNames = "benchmark-streams"
+	// FlagNamesBenchmarkPings is the flag that sets the number of pings of each latency measurement.
+	FlagNamesBenchmarkPings FlagNames = "benchmark-pings"
 )
 
 // AddFlags adds the flags used by the network tests to the given flag set.
@@ -54,4 +66,14 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.Basic, string(FlagNamesBasic), false, "Run only pod-to-pod checks")
 	fs.BoolVar(&o.PodToNodePort, string(FlagNamesPodNodeport), false, "Enable curl from pod to nodeport service")
 	fs.BoolVar(&o.IPRemapping, string(FlagNamesIP), false, "Enable IP remapping for the tests")
+	fs.BoolVar(&o.Benchmark, string(FlagNamesBenchmark), false,
+		"Measure the throughput and the latency between the gateways of the clusters, instead of running the checks")
+	fs.BoolVar(&o.BenchmarkPods, string(FlagNamesBenchmarkPods), false,
+		"Measure also the throughput and the latency between pods running in different clusters")
+	fs.DurationVar(&o.BenchmarkDuration, string(FlagNamesBenchmarkDuration), 10*time.Second,
+		"The duration of each throughput measurement")
+	fs.IntVar(&o.BenchmarkStreams, string(FlagNamesBenchmarkStreams), 1,
+		"The number of parallel TCP streams of each throughput measurement")
+	fs.IntVar(&o.BenchmarkPings, string(FlagNamesBenchmarkPings), 50,
+		"The number of pings of each latency measurement")
 }
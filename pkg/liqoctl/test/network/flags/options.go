@@ -17,6 +17,7 @@ package flags
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/liqotech/liqo/pkg/liqoctl/test"
 )
@@ -94,4 +95,15 @@ type Options struct {
 	PodToNodePort bool
 	// IpRemapping
 	IPRemapping bool
+
+	// Benchmark measures the throughput and the latency instead of running the checks.
+	Benchmark bool
+	// BenchmarkPods measures also the throughput and the latency between pods.
+	BenchmarkPods bool
+	// BenchmarkDuration is the duration of each throughput measurement.
+	BenchmarkDuration time.Duration
+	// BenchmarkStreams is the number of parallel streams of each throughput measurement.
+	BenchmarkStreams int
+	// BenchmarkPings is the number of pings of each latency measurement.
+	BenchmarkPings int
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/liqotech/liqo/pkg/liqoctl/test/network/benchmark"
 	"github.com/liqotech/liqo/pkg/liqoctl/test/network/check"
 	"github.com/liqotech/liqo/pkg/liqoctl/test/network/client"
 	"github.com/liqotech/liqo/pkg/liqoctl/test/network/flags"
@@ -54,6 +55,10 @@ func (o *Options) RunNetworkTest(ctx context.Context) error {
 		}
 	}
 
+	if o.Nopts.Benchmark {
+		return o.runBenchmark(ctx, cl, cfg)
+	}
+
 	printer.Logger.Info("Setting up infrastructure")
 	totreplicas, err := setup.MakeInfrastructure(ctx, cl, o.Nopts)
 	if err != nil {
@@ -75,3 +80,30 @@ func (o *Options) RunNetworkTest(ctx context.Context) error {
 
 	return nil
 }
+
+// runBenchmark runs the benchmark, setting up the infrastructure only if the pod-to-pod benchmark is enabled.
+func (o *Options) runBenchmark(ctx context.Context, cl *client.Client, cfg client.Configs) error {
+	printer := o.Nopts.Topts.LocalFactory.Printer
+
+	if o.Nopts.BenchmarkPods {
+		printer.Logger.Info("Setting up infrastructure")
+		if _, err := setup.MakeInfrastructure(ctx, cl, o.Nopts); err != nil {
+			return fmt.Errorf("error setting up infrastructure: %w", err)
+		}
+		printer.Logger.Info("Infrastructure set up")
+	}
+
+	if err := benchmark.RunBenchmarks(ctx, cl, cfg, o.Nopts); err != nil {
+		return fmt.Errorf("error running benchmarks: %w", err)
+	}
+
+	if o.Nopts.BenchmarkPods && o.Nopts.RemoveNamespace {
+		printer.Logger.Info("Removing namespace")
+		if err := setup.RemoveNamespace(ctx, cl); err != nil {
+			return fmt.Errorf("error removing namespace: %w", err)
+		}
+		printer.Logger.Info("Namespace removed")
+	}
+
+	return nil
+}
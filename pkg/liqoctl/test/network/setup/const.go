@@ -19,6 +19,8 @@ const (
 	NamespaceName = "liqo-test-network"
 	// DeploymentName is the name of the deployment used for the tests.
 	DeploymentName = "netshoot"
+	// NetshootImage is the image of the containers used for the tests.
+	NetshootImage = "ghcr.io/nicolaka/netshoot"
 	// ControlPlaneTaintKey is the key of the taint applied to the control plane nodes.
 	ControlPlaneTaintKey = "node-role.kubernetes.io/control-plane"
 
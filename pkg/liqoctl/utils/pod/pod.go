@@ -30,6 +30,12 @@ import (
 // ExecInPod executes a command in a pod.
 func ExecInPod(ctx context.Context, clset *kubernetes.Clientset, cfg *rest.Config,
 	pod *corev1.Pod, cmd string) (stdout, stderr string, err error) {
+	return ExecInContainer(ctx, clset, cfg, pod, pod.Spec.Containers[0].Name, cmd)
+}
+
+// ExecInContainer executes a command in the given container of a pod, which may also be an ephemeral container.
+func ExecInContainer(ctx context.Context, clset *kubernetes.Clientset, cfg *rest.Config,
+	pod *corev1.Pod, container, cmd string) (stdout, stderr string, err error) {
 	// Prepare the API URL used to execute the command
 	url := clset.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -38,7 +44,7 @@ func ExecInPod(ctx context.Context, clset *kubernetes.Clientset, cfg *rest.Confi
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Command:   strings.Split(cmd, " "),
-			Container: pod.Spec.Containers[0].Name,
+			Container: container,
 			Stdin:     false,
 			Stdout:    true,
 			Stderr:    true,
@@ -0,0 +1,72 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward forwards a random local port to the given port of a pod, until the context is canceled.
+// It returns the local port, once the forwarding is ready.
+func PortForward(ctx context.Context, clset kubernetes.Interface, cfg *rest.Config,
+	pod *corev1.Pod, port uint16) (uint16, error) {
+	url := clset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("portforward").URL()
+
+	transport, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize port forwarder: %w", err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	ready := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", port)},
+		ctx.Done(), ready, io.Discard, io.Discard)
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize port forwarder: %w", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- fw.ForwardPorts() }()
+
+	select {
+	case <-ready:
+	case err := <-errCh:
+		return 0, fmt.Errorf("failed to forward port %d of pod %s/%s: %w", port, pod.Namespace, pod.Name, err)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve the forwarded port: %w", err)
+	}
+	if len(ports) == 0 {
+		return 0, fmt.Errorf("no port has been forwarded")
+	}
+	return ports[0].Local, nil
+}
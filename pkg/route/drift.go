@@ -0,0 +1,139 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vishvananda/netlink"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/drift"
+)
+
+var (
+	// MetricsDrifts is the metric that counts the divergences of the enforced rules and routes from the desired ones.
+	MetricsDrifts = drift.NewMetricsDrifts("route",
+		"Number of divergences of the rules and routes enforced by a RouteConfiguration from the desired ones.")
+	// MetricsLastDriftCheck is the metric that reports the last time the enforced rules and routes have been checked.
+	MetricsLastDriftCheck = drift.NewMetricsLastCheck("route",
+		"Last time the rules and routes enforced by a RouteConfiguration have been compared with the desired ones.")
+)
+
+// RegisterMetrics registers the route metrics to the given registerer.
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	if err := registerer.Register(MetricsDrifts); err != nil {
+		return err
+	}
+	return registerer.Register(MetricsLastDriftCheck)
+}
+
+// detectDrifts compares the rules and routes currently enforced with the ones of the desired table.
+func detectDrifts(table *networkingv1beta1.Table, tableID uint32) ([]drift.Drift, error) {
+	var drifts []drift.Drift
+	exists, err := ExistsTableID(tableID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		drifts = append(drifts, drift.NewDrift(drift.KindTable, "table %s is not registered", table.Name))
+	}
+
+	existingRules, err := GetRulesByTableID(tableID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range table.Rules {
+		if _, exists, _ := ExistsRule(&table.Rules[i], existingRules); !exists {
+			drifts = append(drifts, drift.NewDrift(drift.KindRule, "rule %s is missing", describeRule(&table.Rules[i], table.Name)))
+		}
+	}
+	for i := range existingRules {
+		if !IsContainedRule(&existingRules[i], table.Rules) {
+			drifts = append(drifts, drift.NewDrift(drift.KindRule, "%s is unexpected", existingRules[i].String()))
+		}
+	}
+
+	allRoutes := []networkingv1beta1.Route{}
+	for i := range table.Rules {
+		allRoutes = append(allRoutes, table.Rules[i].Routes...)
+	}
+	for i := range allRoutes {
+		desired, err := forgeNetlinkRoute(&allRoutes[i], tableID)
+		if err != nil {
+			return nil, err
+		}
+		existing, exists, err := ExistsRoute(&allRoutes[i], tableID)
+		switch {
+		case err != nil:
+			drifts = append(drifts, drift.NewDrift(drift.KindRoute, "route to %s is ambiguous: %v", allRoutes[i].Dst, err))
+		case !exists:
+			drifts = append(drifts, drift.NewDrift(drift.KindRoute, "route to %s is missing", allRoutes[i].Dst))
+		case !IsEqualRoute(desired, existing):
+			drifts = append(drifts, drift.NewDrift(drift.KindRoute, "route to %s is modified", allRoutes[i].Dst))
+		}
+	}
+
+	existingRoutes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{Table: int(tableID)}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, err
+	}
+	for i := range existingRoutes {
+		if !IsContainedRoute(&existingRoutes[i], allRoutes) {
+			drifts = append(drifts, drift.NewDrift(drift.KindRoute, "%s is unexpected", existingRoutes[i].String()))
+		}
+	}
+	return drifts, nil
+}
+
+// describeRule returns a human-readable description of the given rule.
+func describeRule(rule *networkingv1beta1.Rule, table string) string {
+	var sb strings.Builder
+	sb.WriteString("from ")
+	if rule.Src != nil {
+		sb.WriteString(rule.Src.String())
+	} else {
+		sb.WriteString("all")
+	}
+	if rule.Dst != nil {
+		fmt.Fprintf(&sb, " to %s", rule.Dst)
+	}
+	if rule.Iif != nil {
+		fmt.Fprintf(&sb, " iif %s", *rule.Iif)
+	}
+	if rule.Oif != nil {
+		fmt.Fprintf(&sb, " oif %s", *rule.Oif)
+	}
+	if rule.FwMark != nil {
+		fmt.Fprintf(&sb, " fwmark %#x", *rule.FwMark)
+	}
+	fmt.Fprintf(&sb, " lookup %s", table)
+	return sb.String()
+}
+
+// checkDrifts compares the enforced configuration with the desired one, recording the outcome in the condition of the host.
+func (r *RouteConfigurationReconciler) checkDrifts(rcfg *networkingv1beta1.RouteConfiguration, tableID uint32) error {
+	checked, drifted, err := r.driftChecker.Check(rcfg, func() ([]drift.Drift, error) {
+		return detectDrifts(&rcfg.Spec.Table, tableID)
+	})
+	if err != nil || !checked {
+		return err
+	}
+	conditionRef := getConditionRef(rcfg, r.PodName)
+	drift.SetStatusTimes(&conditionRef.LastCheckTime, &conditionRef.LastDriftTime, drifted)
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net"
+	"os"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"k8s.io/utils/ptr"
+
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/drift"
+)
+
+var _ = Describe("Drifts", func() {
+	const (
+		tableName = "liqo-test"
+		dev       = "liqo-test"
+	)
+
+	var (
+		ns      netns.NsHandle
+		tableID uint32
+
+		dst  = ptr.To(networkingv1beta1.CIDR("10.2.0.0/16"))
+		gw   = ptr.To(networkingv1beta1.IP("10.1.0.254"))
+		peer = ptr.To(networkingv1beta1.IP("10.1.0.253"))
+	)
+
+	// forgeTable returns a table with a single rule containing the given route.
+	forgeTable := func(rule networkingv1beta1.Rule, route networkingv1beta1.Route) *networkingv1beta1.Table {
+		rule.Routes = []networkingv1beta1.Route{route}
+		return &networkingv1beta1.Table{Name: tableName, Rules: []networkingv1beta1.Rule{rule}}
+	}
+
+	// enforce applies the given table, as the reconciler does.
+	enforce := func(table *networkingv1beta1.Table) {
+		Expect(EnsureTablePresence(&networkingv1beta1.RouteConfiguration{
+			Spec: networkingv1beta1.RouteConfigurationSpec{Table: *table}}, tableID)).To(Succeed())
+		for i := range table.Rules {
+			Expect(EnsureRulePresence(&table.Rules[i], tableID)).To(Succeed())
+			Expect(EnsureRoutesPresence(table.Rules[i].Routes, tableID)).To(Succeed())
+		}
+	}
+
+	// inNamespace runs the given function in the test namespace. The rules and routes are managed
+	// through the global netlink handle, hence the thread is switched to the namespace meanwhile.
+	inNamespace := func(f func()) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		origin, err := netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+		defer origin.Close()
+		Expect(netns.Set(ns)).To(Succeed())
+		defer func() { Expect(netns.Set(origin)).To(Succeed()) }()
+		f()
+	}
+
+	BeforeEach(func() {
+		if os.Geteuid() != 0 {
+			Skip("the creation of the network namespaces requires root privileges")
+		}
+
+		var err error
+		tableID, err = GetTableID(tableName)
+		Expect(err).ToNot(HaveOccurred())
+		registered, err := ExistsTableID(tableID)
+		Expect(err).ToNot(HaveOccurred())
+		if !registered {
+			DeferCleanup(func() { Expect(EnsureTableAbsence(tableID)).To(Succeed()) })
+		}
+
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		origin, err := netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+		defer origin.Close()
+		ns, err = netns.New()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(ns.Close)
+		defer func() { Expect(netns.Set(origin)).To(Succeed()) }()
+
+		link := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: dev}, PeerName: dev + "-peer"}
+		Expect(netlink.LinkAdd(link)).To(Succeed())
+		addr, err := netlink.ParseAddr("10.1.0.1/24")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(netlink.AddrAdd(link, addr)).To(Succeed())
+		Expect(netlink.LinkSetUp(link)).To(Succeed())
+		// The peer is brought up as well, as the kernel flags the routes through a link without carrier.
+		peerLink, err := netlink.LinkByName(link.PeerName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(netlink.LinkSetUp(peerLink)).To(Succeed())
+	})
+
+	DescribeTable("should not detect drifts in the enforced configuration",
+		func(rule networkingv1beta1.Rule, route networkingv1beta1.Route) {
+			inNamespace(func() {
+				table := forgeTable(rule, route)
+				enforce(table)
+
+				Expect(detectDrifts(table, tableID)).To(BeEmpty())
+			})
+		},
+		Entry("rule src", networkingv1beta1.Rule{Src: ptr.To(networkingv1beta1.CIDR("10.3.0.0/16"))},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev)}),
+		Entry("rule dst", networkingv1beta1.Rule{Dst: ptr.To(networkingv1beta1.CIDR("10.2.0.0/16"))},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev)}),
+		Entry("rule iif", networkingv1beta1.Rule{Iif: ptr.To(dev)},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev)}),
+		Entry("rule oif", networkingv1beta1.Rule{Oif: ptr.To(dev)},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev)}),
+		Entry("rule fwmark", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev)}),
+		Entry("route gw", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, Gw: gw}),
+		Entry("route gw onlink", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, Gw: ptr.To(networkingv1beta1.IP("10.4.0.1")), Dev: ptr.To(dev), Onlink: ptr.To(true)}),
+		Entry("route src", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, Src: ptr.To(networkingv1beta1.IP("10.1.0.1")), Dev: ptr.To(dev)}),
+		Entry("route scope", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, Dev: ptr.To(dev), Scope: ptr.To(networkingv1beta1.LinkScope)}),
+		Entry("route next hops", networkingv1beta1.Rule{FwMark: ptr.To(42)},
+			networkingv1beta1.Route{Dst: dst, NextHops: []networkingv1beta1.NextHop{
+				{Gw: gw, Dev: ptr.To(dev)}, {Gw: peer, Dev: ptr.To(dev), Weight: ptr.To(2)}}}),
+	)
+
+	It("should detect the drift of a removed route", func() {
+		inNamespace(func() {
+			table := forgeTable(networkingv1beta1.Rule{FwMark: ptr.To(42)}, networkingv1beta1.Route{Dst: dst, Gw: gw})
+			enforce(table)
+
+			_, network, err := net.ParseCIDR(string(*dst))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(netlink.RouteDel(&netlink.Route{Dst: network, Table: int(tableID)})).To(Succeed())
+
+			Expect(detectDrifts(table, tableID)).To(ConsistOf(HaveField("Kind", drift.KindRoute)))
+		})
+	})
+})
@@ -17,6 +17,7 @@ package route
 import (
 	"fmt"
 	"net"
+	"slices"
 
 	"github.com/vishvananda/netlink"
 
@@ -119,6 +120,21 @@ func IsEqualRoute(route1, route2 *netlink.Route) bool {
 	if route1.Flags != route2.Flags {
 		return false
 	}
+	return isEqualMultiPath(route1.MultiPath, route2.MultiPath)
+}
+
+// isEqualMultiPath checks if the two lists of next hops are equal, regardless of their order.
+func isEqualMultiPath(nh1, nh2 []*netlink.NexthopInfo) bool {
+	if len(nh1) != len(nh2) {
+		return false
+	}
+	for _, h1 := range nh1 {
+		if !slices.ContainsFunc(nh2, func(h2 *netlink.NexthopInfo) bool {
+			return h1.LinkIndex == h2.LinkIndex && h1.Hops == h2.Hops && h1.Gw.Equal(h2.Gw) && h1.Flags == h2.Flags
+		}) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -203,13 +219,49 @@ func forgeNetlinkRoute(route *networkingv1beta1.Route, tableID uint32) (*netlink
 		}
 	}
 
+	multipath, err := forgeNetlinkNextHops(route.NextHops)
+	if err != nil {
+		return nil, err
+	}
+
 	return &netlink.Route{
 		Dst:       dst,
 		Gw:        gw,
 		Src:       src,
 		LinkIndex: linkIndex,
+		MultiPath: multipath,
 		Table:     int(tableID),
 		Flags:     flags,
 		Scope:     scope,
 	}, nil
 }
+
+func forgeNetlinkNextHops(nextHops []networkingv1beta1.NextHop) ([]*netlink.NexthopInfo, error) {
+	if len(nextHops) == 0 {
+		return nil, nil
+	}
+
+	multipath := make([]*netlink.NexthopInfo, len(nextHops))
+	for i := range nextHops {
+		nh := &netlink.NexthopInfo{}
+		if nextHops[i].Gw != nil {
+			nh.Gw = net.ParseIP(nextHops[i].Gw.String())
+		}
+		if nextHops[i].Dev != nil {
+			link, err := netlink.LinkByName(*nextHops[i].Dev)
+			if err != nil {
+				return nil, err
+			}
+			nh.LinkIndex = link.Attrs().Index
+		}
+		if nextHops[i].Onlink != nil && *nextHops[i].Onlink {
+			nh.Flags |= int(netlink.FLAG_ONLINK)
+		}
+		// The kernel weight is the number of hops plus one.
+		if nextHops[i].Weight != nil {
+			nh.Hops = *nextHops[i].Weight - 1
+		}
+		multipath[i] = nh
+	}
+	return multipath, nil
+}
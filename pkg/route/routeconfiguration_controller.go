@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -35,6 +36,8 @@ import (
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/drift"
+	"github.com/liqotech/liqo/pkg/utils/kernel"
 	"github.com/liqotech/liqo/pkg/utils/network/netmonitor"
 )
 
@@ -50,6 +53,10 @@ type RouteConfigurationReconciler struct {
 	LabelsSets []labels.Set
 	// EnableFinalizer is used to enable the finalizer on the reconciled resources.
 	EnableFinalizer bool
+
+	driftChecker *drift.Checker
+	// multipathHashing tracks whether the layer 4 hashing for multipath routes has already been enabled.
+	multipathHashing bool
 }
 
 // newRouteConfigurationReconciler returns a new RouteConfigurationReconciler.
@@ -62,6 +69,7 @@ func newRouteConfigurationReconciler(cl client.Client, s *runtime.Scheme, podnam
 		EventsRecorder:  er,
 		LabelsSets:      labelsSets,
 		EnableFinalizer: enableFinalizer,
+		driftChecker:    drift.NewChecker("RouteConfiguration", podname, er, MetricsDrifts, MetricsLastDriftCheck),
 	}, nil
 }
 
@@ -90,6 +98,7 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err = r.Get(ctx, req.NamespacedName, routeconfiguration); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.Infof("There is no routeconfiguration %s", req.String())
+			r.driftChecker.Forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("unable to get the routeconfiguration %q: %w", req.NamespacedName, err)
@@ -97,8 +106,9 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	klog.V(4).Infof("Reconciling routeconfiguration %s", req.String())
 
+	originalStatus := routeconfiguration.Status.DeepCopy()
 	defer func() {
-		err = r.UpdateStatus(ctx, r.EventsRecorder, routeconfiguration, r.PodName, err)
+		err = r.UpdateStatus(ctx, r.EventsRecorder, routeconfiguration, originalStatus, r.PodName, err)
 	}()
 
 	var tableID uint32
@@ -112,13 +122,13 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	containsFinalizer := ctrlutil.ContainsFinalizer(routeconfiguration, routeconfigurationControllerFinalizer)
 	switch {
 	case !deleting && !containsFinalizer && r.EnableFinalizer:
+		// The update of the finalizer does not trigger a new reconciliation, hence the configuration is applied straight away.
 		if err = r.ensureRouteConfigurationFinalizerPresence(ctx, routeconfiguration); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		return ctrl.Result{}, nil
-
 	case deleting && containsFinalizer && r.EnableFinalizer:
+		r.driftChecker.Forget(req.NamespacedName)
 		for i := range routeconfiguration.Spec.Table.Rules {
 			if err = EnsureRuleAbsence(&routeconfiguration.Spec.Table.Rules[i], tableID); err != nil {
 				return ctrl.Result{}, err
@@ -144,6 +154,11 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, nil
 	}
 
+	// Detect whether the enforced configuration has been modified by other actors, before restoring it.
+	if err = r.checkDrifts(routeconfiguration, tableID); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if err = CleanRules(routeconfiguration.Spec.Table.Rules, tableID); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -160,6 +175,15 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	klog.Infof("Applying routeconfiguration %s", req.String())
 
+	if !r.multipathHashing && hasMultipathRoutes(allRoutes) {
+		// Balance the traffic across the next hops (e.g., active-active gateways) on a per-flow basis.
+		if err := kernel.EnableMultipathL4Hashing(); err != nil {
+			klog.Warningf("Unable to enable layer 4 hashing for multipath routes: %v", err)
+		} else {
+			r.multipathHashing = true
+		}
+	}
+
 	if err = EnsureTablePresence(routeconfiguration, tableID); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -174,8 +198,20 @@ func (r *RouteConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	klog.Infof("Applied routeconfiguration %s", req.String())
+	r.driftChecker.Applied(routeconfiguration)
 
-	return ctrl.Result{}, nil
+	// The resource is periodically reconciled, to detect and restore the divergences of the enforced configuration.
+	return ctrl.Result{RequeueAfter: drift.CheckPeriod}, nil
+}
+
+// hasMultipathRoutes returns whether any of the given routes has multiple next hops.
+func hasMultipathRoutes(routes []networkingv1beta1.Route) bool {
+	for i := range routes {
+		if len(routes[i].NextHops) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // SetupWithManager register the RouteConfigurationReconciler to the manager.
@@ -193,7 +229,7 @@ func (r *RouteConfigurationReconciler) SetupWithManager(ctx context.Context, mgr
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlRouteConfiguration).
-		For(&networkingv1beta1.RouteConfiguration{}, builder.WithPredicates(filterByLabelsPredicate)).
+		For(&networkingv1beta1.RouteConfiguration{}, builder.WithPredicates(filterByLabelsPredicate, ignoreStatusUpdatesPredicate())).
 		WatchesRawSource(NewRouteWatchSource(src, NewRouteWatchEventHandler(r.Client, r.LabelsSets))).
 		Complete(r)
 }
@@ -210,6 +246,12 @@ func forgeLabelsPredicate(labelsSets []labels.Set) (predicate.Predicate, error)
 	return predicate.Or(labelPredicates...), nil
 }
 
+// ignoreStatusUpdatesPredicate returns a predicate that filters out the updates involving only the status,
+// which are performed by all the hosts applying the resource, and would otherwise trigger a reconciliation on each of them.
+func ignoreStatusUpdatesPredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
+
 func getConditionRef(rcfg *networkingv1beta1.RouteConfiguration, podname string) *networkingv1beta1.RouteConfigurationStatusCondition {
 	var conditionRef *networkingv1beta1.RouteConfigurationStatusCondition
 	for i := range rcfg.Status.Conditions {
@@ -223,13 +265,16 @@ func getConditionRef(rcfg *networkingv1beta1.RouteConfiguration, podname string)
 			Host: podname,
 		}
 		rcfg.Status.Conditions = append(rcfg.Status.Conditions, *conditionRef)
+		// The condition is appended by value, hence the reference must point to the element of the slice.
+		conditionRef = &rcfg.Status.Conditions[len(rcfg.Status.Conditions)-1]
 	}
 	return conditionRef
 }
 
-// UpdateStatus updates the status of the given RouteConfiguration.
+// UpdateStatus updates the status of the given RouteConfiguration, patching it only if it differs from the original one.
 func (r *RouteConfigurationReconciler) UpdateStatus(ctx context.Context, er record.EventRecorder,
-	routeconfiguration *networkingv1beta1.RouteConfiguration, podname string, err error) error {
+	routeconfiguration *networkingv1beta1.RouteConfiguration, originalStatus *networkingv1beta1.RouteConfigurationStatus,
+	podname string, err error) error {
 	conditionRef := getConditionRef(routeconfiguration, podname)
 	conditionRef.Host = podname
 	conditionRef.Type = networkingv1beta1.RouteConfigurationStatusConditionTypeApplied
@@ -242,10 +287,16 @@ func (r *RouteConfigurationReconciler) UpdateStatus(ctx context.Context, er reco
 	}
 	if oldStatus != conditionRef.Status {
 		conditionRef.LastTransitionTime = metav1.Now()
+		er.Eventf(routeconfiguration, "Normal", "RouteConfigurationUpdate", "RouteConfiguration %s: %s", conditionRef.Type, conditionRef.Status)
 	}
 
-	er.Eventf(routeconfiguration, "Normal", "RouteConfigurationUpdate", "RouteConfiguration %s: %s", conditionRef.Type, conditionRef.Status)
-	if clerr := r.Client.Status().Update(ctx, routeconfiguration); clerr != nil {
+	if equality.Semantic.DeepEqual(originalStatus, &routeconfiguration.Status) {
+		return err
+	}
+	original := routeconfiguration.DeepCopy()
+	original.Status = *originalStatus
+	if clerr := r.Client.Status().Patch(ctx, routeconfiguration,
+		client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); clerr != nil {
 		err = errors.Join(err, clerr)
 	}
 	return err
@@ -16,7 +16,6 @@ package route
 
 import (
 	"bufio"
-	"crypto/sha256"
 	"errors"
 	"fmt"
 	"os"
@@ -26,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
+	routeutils "github.com/liqotech/liqo/pkg/utils/route"
 )
 
 const (
@@ -68,7 +68,7 @@ func GetTableID(tableName string) (uint32, error) {
 	if tableName == "" {
 		return 0, fmt.Errorf("table name is empty")
 	}
-	return generateTableID(tableName), nil
+	return routeutils.TableID(tableName), nil
 }
 
 // ExistsTableID checks if the given table ID is already present in the rt_tables file.
@@ -167,17 +167,6 @@ func filterDeletedLines(tableID uint32) ([]string, error) {
 	return lines, nil
 }
 
-func generateTableID(name string) uint32 {
-	hash := sha256.Sum256([]byte(name))
-	id := hash[0:4]
-	// the first bit of the most significant byte must be 0. https://serverfault.com/questions/315705/how-many-custom-route-tables-can-i-have-on-linux
-	id[3] >>= 1
-	// IDs in the range 0 <= ID <= 255 are used by the operating system
-	// make sure we won't use this range
-	id[1] |= 1
-	return uint32(hash[3])<<24 | uint32(hash[2])<<16 | uint32(hash[1])<<8 | uint32(hash[0])
-}
-
 func forgeTableEntry(tableID uint32, tableName string) string {
 	return fmt.Sprintf("%s\t%s", strconv.FormatUint(uint64(tableID), 10), tableName)
 }
@@ -0,0 +1,156 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CheckPeriod is the period after which the enforced configuration is compared again with the desired one.
+	CheckPeriod = time.Minute
+	// StatusRefreshPeriod is the period after which the time of the last check is refreshed in the status,
+	// which is shared by all the hosts. The time of each check is exposed through the metrics.
+	StatusRefreshPeriod = 10 * time.Minute
+)
+
+// Kind is the kind of object which diverges from the desired configuration.
+type Kind string
+
+const (
+	// KindTable is the kind of the divergences of a table.
+	KindTable Kind = "table"
+	// KindSet is the kind of the divergences of a set.
+	KindSet Kind = "set"
+	// KindChain is the kind of the divergences of a chain.
+	KindChain Kind = "chain"
+	// KindRule is the kind of the divergences of a rule.
+	KindRule Kind = "rule"
+	// KindRoute is the kind of the divergences of a route.
+	KindRoute Kind = "route"
+)
+
+// Drift describes a divergence of the enforced configuration from the desired one.
+type Drift struct {
+	Kind    Kind
+	Message string
+}
+
+// NewDrift returns a new Drift of the given kind, with a formatted message.
+func NewDrift(kind Kind, format string, args ...any) Drift {
+	return Drift{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewMetricsDrifts returns the metric that counts the divergences detected for the resources of a given subsystem.
+func NewMetricsDrifts(subsystem, help string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "liqo",
+		Subsystem: subsystem,
+		Name:      "drifts_total",
+		Help:      help,
+	}, []string{"namespace", "name", "kind"})
+}
+
+// NewMetricsLastCheck returns the metric that reports the time of the last check of the resources of a given subsystem.
+func NewMetricsLastCheck(subsystem, help string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "liqo",
+		Subsystem: subsystem,
+		Name:      "drift_last_check_timestamp_seconds",
+		Help:      help,
+	}, []string{"namespace", "name"})
+}
+
+// Checker compares the configurations enforced on the host with the desired ones,
+// and reports the divergences through logs, events and metrics.
+type Checker struct {
+	kind           string
+	host           string
+	eventsRecorder record.EventRecorder
+	metricsDrifts  *prometheus.CounterVec
+	metricsCheck   *prometheus.GaugeVec
+
+	// appliedGenerations tracks the generation of the resources last applied on the host.
+	appliedGenerations sync.Map
+}
+
+// NewChecker returns a new Checker for the resources of the given kind (e.g., FirewallConfiguration), applied by the given host.
+func NewChecker(kind, host string, er record.EventRecorder, metricsDrifts *prometheus.CounterVec, metricsCheck *prometheus.GaugeVec) *Checker {
+	return &Checker{
+		kind:           kind,
+		host:           host,
+		eventsRecorder: er,
+		metricsDrifts:  metricsDrifts,
+		metricsCheck:   metricsCheck,
+	}
+}
+
+// Applied records that the current generation of the given resource has been applied on the host.
+func (c *Checker) Applied(obj client.Object) {
+	c.appliedGenerations.Store(client.ObjectKeyFromObject(obj), obj.GetGeneration())
+}
+
+// Forget stops tracking the given resource, which has been deleted or removed from the host.
+func (c *Checker) Forget(nsName types.NamespacedName) {
+	c.appliedGenerations.Delete(nsName)
+	c.metricsCheck.DeleteLabelValues(nsName.Namespace, nsName.Name)
+}
+
+// Check compares the enforced configuration with the desired one, through the given detection function,
+// reporting the divergences through logs, events and metrics. The check is performed only if the current generation
+// of the resource has already been applied by this host, since otherwise the divergences are caused by the updates
+// of the resource itself. It returns whether the check has been performed, and whether divergences have been found.
+func (c *Checker) Check(obj client.Object, detect func() ([]Drift, error)) (checked, drifted bool, err error) {
+	if generation, ok := c.appliedGenerations.Load(client.ObjectKeyFromObject(obj)); !ok || generation != obj.GetGeneration() {
+		return false, false, nil
+	}
+
+	drifts, err := detect()
+	if err != nil {
+		return false, false, fmt.Errorf("unable to compare the enforced configuration with the desired one: %w", err)
+	}
+	for i := range drifts {
+		klog.Warningf("%s %s/%s drifted: %s", c.kind, obj.GetNamespace(), obj.GetName(), drifts[i].Message)
+		c.eventsRecorder.Eventf(obj, corev1.EventTypeWarning, c.kind+"Drift", "Host %s: %s", c.host, drifts[i].Message)
+		c.metricsDrifts.WithLabelValues(obj.GetNamespace(), obj.GetName(), string(drifts[i].Kind)).Inc()
+	}
+	c.metricsCheck.WithLabelValues(obj.GetNamespace(), obj.GetName()).SetToCurrentTime()
+	return true, len(drifts) > 0, nil
+}
+
+// SetStatusTimes records the outcome of a check in the given fields of the condition of the host.
+// The time of the last drift is always updated, while the time of the last check is refreshed
+// at most once per StatusRefreshPeriod, to limit the updates of the status shared by all the hosts.
+func SetStatusTimes(lastCheckTime, lastDriftTime **metav1.Time, drifted bool) {
+	now := metav1.Now()
+	if drifted {
+		*lastDriftTime = &now
+		*lastCheckTime = &now
+		return
+	}
+	if *lastCheckTime == nil || time.Since((*lastCheckTime).Time) >= StatusRefreshPeriod {
+		*lastCheckTime = &now
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/utils/drift"
+)
+
+var _ = Describe("Drift detection", func() {
+	Describe("The Checker", func() {
+		var (
+			checker  *drift.Checker
+			recorder *record.FakeRecorder
+			obj      *corev1.ConfigMap
+			detected int
+			drifts   []drift.Drift
+		)
+
+		detect := func() ([]drift.Drift, error) {
+			detected++
+			return drifts, nil
+		}
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			checker = drift.NewChecker("Configuration", "host", recorder,
+				drift.NewMetricsDrifts("test", "drifts"), drift.NewMetricsLastCheck("test", "last check"))
+			obj = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name", Generation: 1}}
+			detected, drifts = 0, nil
+		})
+
+		It("should not check the resources not yet applied", func() {
+			checked, drifted, err := checker.Check(obj, detect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(BeFalse())
+			Expect(drifted).To(BeFalse())
+			Expect(detected).To(BeZero())
+		})
+
+		It("should not check the resources whose current generation has not been applied", func() {
+			checker.Applied(obj)
+			obj.Generation++
+			checked, _, err := checker.Check(obj, detect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(BeFalse())
+		})
+
+		It("should not check the forgotten resources", func() {
+			checker.Applied(obj)
+			checker.Forget(client.ObjectKeyFromObject(obj))
+			checked, _, err := checker.Check(obj, detect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(BeFalse())
+		})
+
+		It("should report no divergence if the configuration matches", func() {
+			checker.Applied(obj)
+			checked, drifted, err := checker.Check(obj, detect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(BeTrue())
+			Expect(drifted).To(BeFalse())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should report each divergence through events and metrics", func() {
+			metricsDrifts := drift.NewMetricsDrifts("test", "drifts")
+			checker = drift.NewChecker("Configuration", "host", recorder, metricsDrifts, drift.NewMetricsLastCheck("test", "last check"))
+			drifts = []drift.Drift{
+				drift.NewDrift(drift.KindRule, "rule %s is missing", "foo"),
+				drift.NewDrift(drift.KindRule, "rule %s is unexpected", "bar"),
+				drift.NewDrift(drift.KindTable, "table %s is missing", "baz"),
+			}
+			checker.Applied(obj)
+			checked, drifted, err := checker.Check(obj, detect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(BeTrue())
+			Expect(drifted).To(BeTrue())
+			Expect(recorder.Events).To(HaveLen(3))
+			Expect(<-recorder.Events).To(Equal("Warning ConfigurationDrift Host host: rule foo is missing"))
+			Expect(testutil.ToFloat64(metricsDrifts.WithLabelValues("ns", "name", string(drift.KindRule)))).To(BeNumerically("==", 2))
+			Expect(testutil.ToFloat64(metricsDrifts.WithLabelValues("ns", "name", string(drift.KindTable)))).To(BeNumerically("==", 1))
+		})
+
+		It("should return the detection errors", func() {
+			checker.Applied(obj)
+			_, _, err := checker.Check(obj, func() ([]drift.Drift, error) { return nil, errors.New("failure") })
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The SetStatusTimes function", func() {
+		var lastCheckTime, lastDriftTime *metav1.Time
+
+		BeforeEach(func() { lastCheckTime, lastDriftTime = nil, nil })
+
+		It("should set the time of the first check", func() {
+			drift.SetStatusTimes(&lastCheckTime, &lastDriftTime, false)
+			Expect(lastCheckTime).ToNot(BeNil())
+			Expect(lastDriftTime).To(BeNil())
+		})
+
+		It("should not refresh the time of a recent check", func() {
+			recent := metav1.NewTime(time.Now().Add(-time.Minute))
+			lastCheckTime = &recent
+			drift.SetStatusTimes(&lastCheckTime, &lastDriftTime, false)
+			Expect(lastCheckTime).To(Equal(&recent))
+		})
+
+		It("should refresh the time of an old check", func() {
+			old := metav1.NewTime(time.Now().Add(-drift.StatusRefreshPeriod))
+			lastCheckTime = &old
+			drift.SetStatusTimes(&lastCheckTime, &lastDriftTime, false)
+			Expect(lastCheckTime.After(old.Time)).To(BeTrue())
+		})
+
+		It("should always record the time of a drift", func() {
+			recent := metav1.NewTime(time.Now().Add(-time.Minute))
+			lastCheckTime = &recent
+			drift.SetStatusTimes(&lastCheckTime, &lastDriftTime, true)
+			Expect(lastDriftTime).ToNot(BeNil())
+			Expect(lastCheckTime).To(Equal(lastDriftTime))
+		})
+	})
+})
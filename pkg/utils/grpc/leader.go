@@ -0,0 +1,93 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// keepaliveTime is the interval between the keepalive pings, used to detect connections to dead replicas.
+	keepaliveTime = 10 * time.Second
+	// keepaliveTimeout is the time waited for a keepalive ping acknowledgment before closing the connection.
+	keepaliveTimeout = 5 * time.Second
+
+	// retryMaxAttempts is the maximum number of attempts of a call failed because the connection with the leader
+	// has been lost, waiting for the new leader to be elected and to become reachable.
+	retryMaxAttempts = 5
+	// retryInitialBackoff is the backoff before the first retry, doubled at each attempt up to retryMaxBackoff.
+	retryInitialBackoff = 500 * time.Millisecond
+	// retryMaxBackoff is the maximum backoff between two retries.
+	retryMaxBackoff = 5 * time.Second
+)
+
+// RetryableFunc tells whether a unary call to the given method (i.e., "/service/method"), with the given request,
+// can be safely retried, that is, whether repeating it after it possibly reached the server has no further effects.
+type RetryableFunc func(method string, req any) bool
+
+// LeaderFollowingDialOptions returns the dial options to transparently follow the leader of a replicated service,
+// assuming the target address always resolves to the current leader (e.g., a Service selecting the leader pod only).
+// Connections to a replica which is gone are promptly detected through keepalives, and the unary calls failed in the
+// meanwhile are retried with backoff, so that they eventually reach the new leader. Only the calls considered retryable
+// by the given function are retried, as the failed attempts might have been served anyway.
+func LeaderFollowingDialOptions(retryable RetryableFunc) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(retryUnaryInterceptor(retryable)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                keepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// retryUnaryInterceptor retries with backoff the retryable unary calls failed as the server is unavailable.
+func retryUnaryInterceptor(retryable RetryableFunc) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		backoff := retryInitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt == retryMaxAttempts || status.Code(err) != codes.Unavailable ||
+				retryable == nil || !retryable(method, req) {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, retryMaxBackoff)
+		}
+	}
+}
+
+// LeaderFollowingServerOptions returns the server options accepting the keepalives sent by the clients configured
+// through LeaderFollowingDialOptions, which would otherwise be considered abusive.
+func LeaderFollowingServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveTime / 2,
+			PermitWithoutStream: true,
+		}),
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernel
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+const (
+	multipathHashPolicyFileV4 = "/proc/sys/net/ipv4/fib_multipath_hash_policy"
+	multipathHashPolicyFileV6 = "/proc/sys/net/ipv6/fib_multipath_hash_policy"
+)
+
+// EnableMultipathL4Hashing configures the host to balance the traffic across the next hops of multipath routes
+// depending on the layer 4 five-tuple, rather than on the source and destination addresses only.
+// Packets of the same flow are still always forwarded through the same next hop.
+// The setting applies to the whole network namespace (i.e., to the entire host, for host network pods), affecting
+// also the multipath routes not managed by Liqo, and it is not restored on termination.
+// The IPv6 setting is skipped if IPv6 is disabled.
+func EnableMultipathL4Hashing() error {
+	if err := os.WriteFile(multipathHashPolicyFileV4, []byte("1\n"), 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(multipathHashPolicyFileV6, []byte("1\n"), 0o600); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -126,6 +126,11 @@ func ComponentLabelSelector(name, component string) labels.Selector {
 	return labels.NewSelector().Add(*req1, *req2)
 }
 
+// IPAMLabelSelector returns the label selector associated with the IPAM components.
+func IPAMLabelSelector() labels.Selector {
+	return ComponentLabelSelector("ipam", "ipam")
+}
+
 // ControllerManagerLabelSelector returns the label selector associated with the controller-manager components.
 func ControllerManagerLabelSelector() labels.Selector {
 	return ComponentLabelSelector("controller-manager", "controller-manager")
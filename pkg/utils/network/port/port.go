@@ -20,8 +20,8 @@ import (
 
 // ParsePortRange parses the port range and returns the start and end of the range.
 func ParsePortRange(value string) (start, end uint16, err error) {
-	_, err = fmt.Sscanf(value, "%d-%d", start, end)
-	if err != nil {
+	_, err = fmt.Sscanf(value, "%d-%d", &start, &end)
+	if err != nil || start > end {
 		return 0, 0, fmt.Errorf("invalid port range %s", value)
 	}
 
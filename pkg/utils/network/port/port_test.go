@@ -0,0 +1,47 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package port_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/liqotech/liqo/pkg/utils/network/port"
+)
+
+var _ = Describe("Port range parsing", func() {
+	DescribeTable("should return the bounds of a valid range",
+		func(value string, start, end uint16) {
+			s, e, err := port.ParsePortRange(value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s).To(Equal(start))
+			Expect(e).To(Equal(end))
+		},
+		Entry("a range", "1000-2000", uint16(1000), uint16(2000)),
+		Entry("a single port range", "80-80", uint16(80), uint16(80)),
+		Entry("the whole port space", "0-65535", uint16(0), uint16(65535)),
+	)
+
+	DescribeTable("should fail if the range is not valid",
+		func(value string) {
+			_, _, err := port.ParsePortRange(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("a single port", "80"),
+		Entry("a reversed range", "2000-1000"),
+		Entry("a port out of range", "1000-70000"),
+		Entry("a non numeric range", "http-https"),
+	)
+})
@@ -232,6 +232,19 @@ func FakeNetworkReservedSubnet(i int) *ipamv1alpha1.Network {
 	})
 }
 
+// FakePool returns a fake Pool.
+func FakePool(name, cidr string, draining bool) *ipamv1alpha1.Pool {
+	return &ipamv1alpha1.Pool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: ipamv1alpha1.PoolSpec{
+			CIDR:     networkingv1beta1.CIDR(cidr),
+			Draining: draining,
+		},
+	}
+}
+
 // FakeIP returns a fake IP.
 func FakeIP(name, namespace, ip, cidr string, labels map[string]string, networkRef *corev1.ObjectReference, masquerade bool) *ipamv1alpha1.IP {
 	return &ipamv1alpha1.IP{
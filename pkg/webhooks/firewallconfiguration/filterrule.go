@@ -0,0 +1,67 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewallconfiguration
+
+import (
+	"fmt"
+	"strconv"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+)
+
+func checkFilterRulesInChain(chain *firewallapi.Chain) error {
+	filterrules := chain.Rules.FilterRules
+	for i := range filterrules {
+		if err := checkFilterRuleValue(&filterrules[i]); err != nil {
+			return forgeChainError(chain, err)
+		}
+		if err := checkFilterRuleAction(chain, &filterrules[i]); err != nil {
+			return forgeChainError(chain, err)
+		}
+	}
+	return nil
+}
+
+// checkFilterRuleAction checks that the action is allowed in the hook of the chain,
+// as the reject statement is valid only in the input, forward and output hooks.
+func checkFilterRuleAction(chain *firewallapi.Chain, filterrule *firewallapi.FilterRule) error {
+	if filterrule.Action != firewallapi.ActionReject || chain.Hook == nil {
+		return nil
+	}
+	switch *chain.Hook {
+	case firewallapi.ChainHookInput, firewallapi.ChainHookForward, firewallapi.ChainHookOutput:
+		return nil
+	default:
+		return fmt.Errorf("filterrule %s is %s, which is allowed only in the input, forward and output hooks, not in the %s one",
+			ptrToString(filterrule.Name), filterrule.Action, *chain.Hook)
+	}
+}
+
+func checkFilterRuleValue(filterrule *firewallapi.FilterRule) error {
+	switch filterrule.Action {
+	case firewallapi.ActionCtMark:
+		if filterrule.Value == nil {
+			return fmt.Errorf("filterrule %s is %s but has no Value field", ptrToString(filterrule.Name), filterrule.Action)
+		}
+		if _, err := strconv.Atoi(*filterrule.Value); err != nil {
+			return fmt.Errorf("filterrule %s has an invalid mark %s: %w", ptrToString(filterrule.Name), *filterrule.Value, err)
+		}
+	case firewallapi.ActionSetMetaMarkFromCtMark, firewallapi.ActionAccept, firewallapi.ActionDrop, firewallapi.ActionReject:
+		if filterrule.Value != nil {
+			return fmt.Errorf("filterrule %s is %s but has a Value field", ptrToString(filterrule.Name), filterrule.Action)
+		}
+	}
+	return nil
+}
@@ -109,6 +109,7 @@ func (w *webhookValidate) Handle(ctx context.Context, req admission.Request) adm
 
 	family := firewallConfiguration.Spec.Table.Family
 	chains := firewallConfiguration.Spec.Table.Chains
+	sets := firewallConfiguration.Spec.Table.Sets
 
 	if req.Operation == v1.Update {
 		oldFirewallConfiguration, err = w.DecodeFirewallConfiguration(req.OldObject)
@@ -129,6 +130,10 @@ func (w *webhookValidate) Handle(ctx context.Context, req admission.Request) adm
 		return admission.Denied(err.Error())
 	}
 
+	if err := checkSets(*family, sets); err != nil {
+		return admission.Denied(err.Error())
+	}
+
 	for i := range chains {
 		chain := chains[i]
 
@@ -136,11 +141,19 @@ func (w *webhookValidate) Handle(ctx context.Context, req admission.Request) adm
 			return admission.Denied(err.Error())
 		}
 
-		if err := checkRulesInChain(&chain); err != nil {
+		if err := checkRulesInChain(&chain, sets); err != nil {
+			return admission.Denied(err.Error())
+		}
+
+		if err := checkMatchesInChain(*family, &chain); err != nil {
 			return admission.Denied(err.Error())
 		}
 
 		switch chain.Type {
+		case firewallapi.ChainTypeFilter:
+			if err := checkFilterRulesInChain(&chain); err != nil {
+				return admission.Denied(err.Error())
+			}
 		case firewallapi.ChainTypeNAT:
 			if err := checkNatRulesInChain(&chain); err != nil {
 				return admission.Denied(err.Error())
@@ -0,0 +1,101 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewallconfiguration
+
+import (
+	"fmt"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+func checkMatchesInChain(tableFamily firewallapi.TableFamily, chain *firewallapi.Chain) error {
+	for i := range chain.Rules.FilterRules {
+		if err := checkMatches(tableFamily, chain.Rules.FilterRules[i].Match); err != nil {
+			return forgeChainError(chain, fmt.Errorf("filterrule %v: %w", ptrToString(chain.Rules.FilterRules[i].Name), err))
+		}
+	}
+	for i := range chain.Rules.NatRules {
+		if err := checkMatches(tableFamily, chain.Rules.NatRules[i].Match); err != nil {
+			return forgeChainError(chain, fmt.Errorf("natrule %v: %w", ptrToString(chain.Rules.NatRules[i].Name), err))
+		}
+	}
+	return nil
+}
+
+func checkMatches(tableFamily firewallapi.TableFamily, matches []firewallapi.Match) error {
+	for i := range matches {
+		m := &matches[i]
+		if m.CtState != nil {
+			if err := checkMatchCtState(m.CtState); err != nil {
+				return err
+			}
+		}
+		if m.Mark != nil {
+			if _, _, err := firewallutils.ParseMark(m.Mark.Value); err != nil {
+				return err
+			}
+		}
+		if m.ICMP != nil {
+			if err := checkMatchICMP(tableFamily, m.ICMP); err != nil {
+				return err
+			}
+		}
+		if m.Limit != nil {
+			if m.Limit.Rate <= 0 {
+				return fmt.Errorf("limit rate must be positive")
+			}
+			if m.Limit.Burst != nil && *m.Limit.Burst < 0 {
+				return fmt.Errorf("limit burst cannot be negative")
+			}
+		}
+	}
+	return nil
+}
+
+// checkMatchICMP checks that the ICMP protocol is carried by the IP version of the table,
+// as the icmp messages never match in ip6 tables, and the icmpv6 ones in ip tables.
+func checkMatchICMP(tableFamily firewallapi.TableFamily, icmp *firewallapi.MatchICMP) error {
+	switch {
+	case firewallutils.IsICMPv6(icmp) && tableFamily == firewallapi.TableFamilyIPv4:
+		return fmt.Errorf("icmpv6 match is not supported in %s tables", tableFamily)
+	case !firewallutils.IsICMPv6(icmp) && tableFamily == firewallapi.TableFamilyIPv6:
+		return fmt.Errorf("icmp match is not supported in %s tables, use the icmpv6 protocol", tableFamily)
+	case !firewallutils.IsICMPv6(icmp) && icmp.Type == firewallapi.ICMPTypePacketTooBig:
+		return fmt.Errorf("icmp type %s exists only in the icmpv6 protocol", icmp.Type)
+	}
+	return nil
+}
+
+func checkMatchCtState(ctState *firewallapi.MatchCtState) error {
+	if len(ctState.Value) == 0 {
+		return fmt.Errorf("ct state match has no states")
+	}
+	states := map[firewallapi.CtState]interface{}{}
+	for _, state := range ctState.Value {
+		if _, ok := states[state]; ok {
+			return fmt.Errorf("ct state %v is duplicated", state)
+		}
+		states[state] = nil
+	}
+	return nil
+}
+
+func ptrToString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
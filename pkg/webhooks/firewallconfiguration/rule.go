@@ -24,14 +24,17 @@ import (
 	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
 )
 
-func checkRulesInChain(chain *firewallapi.Chain) error {
-	rules := firewall.FromChainToRulesArray(chain)
+func checkRulesInChain(chain *firewallapi.Chain, sets []firewallapi.Set) error {
+	rules := firewall.FromChainToRulesArray(chain, sets)
 	if err := checkVoidRuleName(rules); err != nil {
 		return forgeChainError(chain, err)
 	}
 	if err := checkUniqueRuleNames(rules); err != nil {
 		return forgeChainError(chain, err)
 	}
+	if err := checkRulesSetReferences(rules); err != nil {
+		return forgeChainError(chain, err)
+	}
 	return nil
 }
 
@@ -60,9 +63,18 @@ func checkUniqueRuleNames(rules []firewallutils.Rule) error {
 	return nil
 }
 
+func checkRulesSetReferences(rules []firewallutils.Rule) error {
+	for i := range rules {
+		if err := firewallutils.CheckSetReferences(rules[i]); err != nil {
+			return fmt.Errorf("rule %v: %w", *rules[i].GetName(), err)
+		}
+	}
+	return nil
+}
+
 func generateRuleNames(chains []firewallapi.Chain) {
 	for i := range chains {
-		rules := firewall.FromChainToRulesArray(&chains[i])
+		rules := firewall.FromChainToRulesArray(&chains[i], nil)
 		for j := range rules {
 			if rules[j].GetName() == nil || *rules[j].GetName() == "" {
 				rules[j].SetName(uuid.NewString())
@@ -0,0 +1,41 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewallconfiguration
+
+import (
+	"fmt"
+
+	"github.com/google/nftables"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+	firewallutils "github.com/liqotech/liqo/pkg/firewall/utils"
+)
+
+func checkSets(tableFamily firewallapi.TableFamily, sets []firewallapi.Set) error {
+	table := &nftables.Table{Family: firewallutils.GetTableFamily(tableFamily)}
+	names := map[string]interface{}{}
+	for i := range sets {
+		if _, ok := names[sets[i].Name]; ok {
+			return fmt.Errorf("set name %v is duplicated", sets[i].Name)
+		}
+		names[sets[i].Name] = nil
+
+		// Forging the set checks the validity of its type, against the table family too, and of its elements.
+		if _, _, err := firewallutils.ForgeSet(&sets[i], table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
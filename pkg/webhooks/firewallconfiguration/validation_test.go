@@ -0,0 +1,89 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewallconfiguration
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	firewallapi "github.com/liqotech/liqo/apis/networking/v1beta1/firewall"
+)
+
+var _ = Describe("Validation", func() {
+	DescribeTable("the ICMP matches",
+		func(family firewallapi.TableFamily, icmp firewallapi.MatchICMP, expectErr bool) {
+			err := checkMatches(family, []firewallapi.Match{{Op: firewallapi.MatchOperationEq, ICMP: &icmp}})
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("icmp in an ip table", firewallapi.TableFamilyIPv4,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest}, false),
+		Entry("icmp in an inet table", firewallapi.TableFamilyINet,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest, Protocol: firewallapi.ICMPProtocolICMP}, false),
+		Entry("icmp in an ip6 table", firewallapi.TableFamilyIPv6,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest}, true),
+		Entry("icmpv6 in an ip6 table", firewallapi.TableFamilyIPv6,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest, Protocol: firewallapi.ICMPProtocolICMPv6}, false),
+		Entry("icmpv6 in an inet table", firewallapi.TableFamilyINet,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypePacketTooBig, Protocol: firewallapi.ICMPProtocolICMPv6}, false),
+		Entry("icmpv6 in an ip table", firewallapi.TableFamilyIPv4,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypeEchoRequest, Protocol: firewallapi.ICMPProtocolICMPv6}, true),
+		Entry("an icmpv6 type in an icmp match", firewallapi.TableFamilyINet,
+			firewallapi.MatchICMP{Type: firewallapi.ICMPTypePacketTooBig}, true),
+	)
+
+	DescribeTable("the filter rule actions",
+		func(hook firewallapi.ChainHook, action firewallapi.FilterAction, expectErr bool) {
+			chain := &firewallapi.Chain{
+				Name: ptr.To("test"), Type: firewallapi.ChainTypeFilter, Hook: &hook,
+				Rules: firewallapi.RulesSet{FilterRules: []firewallapi.FilterRule{{Name: ptr.To("test"), Action: action}}},
+			}
+			err := checkFilterRulesInChain(chain)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("reject in the input hook", firewallapi.ChainHookInput, firewallapi.ActionReject, false),
+		Entry("reject in the forward hook", firewallapi.ChainHookForward, firewallapi.ActionReject, false),
+		Entry("reject in the output hook", firewallapi.ChainHookOutput, firewallapi.ActionReject, false),
+		Entry("reject in the prerouting hook", firewallapi.ChainHookPrerouting, firewallapi.ActionReject, true),
+		Entry("reject in the postrouting hook", firewallapi.ChainHookPostrouting, firewallapi.ActionReject, true),
+		Entry("reject in the ingress hook", firewallapi.ChainHookIngress, firewallapi.ActionReject, true),
+		Entry("drop in the prerouting hook", firewallapi.ChainHookPrerouting, firewallapi.ActionDrop, false),
+	)
+
+	DescribeTable("the sets",
+		func(family firewallapi.TableFamily, keyType firewallapi.SetDataType, expectErr bool) {
+			err := checkSets(family, []firewallapi.Set{{Name: "addrs", KeyType: keyType}})
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("ipv4_addr in an ip table", firewallapi.TableFamilyIPv4, firewallapi.SetDataTypeIPAddr, false),
+		Entry("ipv4_addr in an inet table", firewallapi.TableFamilyINet, firewallapi.SetDataTypeIPAddr, false),
+		Entry("ipv4_addr in an ip6 table", firewallapi.TableFamilyIPv6, firewallapi.SetDataTypeIPAddr, true),
+		Entry("ipv6_addr in an ip6 table", firewallapi.TableFamilyIPv6, firewallapi.SetDataTypeIP6Addr, false),
+		Entry("ipv6_addr in an inet table", firewallapi.TableFamilyINet, firewallapi.SetDataTypeIP6Addr, false),
+		Entry("ipv6_addr in an ip table", firewallapi.TableFamilyIPv4, firewallapi.SetDataTypeIP6Addr, true),
+	)
+})
@@ -30,3 +30,20 @@ func checkUniqueRoutes(routes []networkingv1beta1.Route) error {
 	}
 	return nil
 }
+
+func checkNextHops(routes []networkingv1beta1.Route) error {
+	for i := range routes {
+		if len(routes[i].NextHops) == 0 {
+			continue
+		}
+		if routes[i].Gw != nil || routes[i].Dev != nil {
+			return fmt.Errorf("route to %s cannot specify both gw/dev and nextHops", routes[i].Dst.String())
+		}
+		for j := range routes[i].NextHops {
+			if routes[i].NextHops[j].Gw == nil && routes[i].NextHops[j].Dev == nil {
+				return fmt.Errorf("next hop %d of route to %s must specify at least one of gw and dev", j, routes[i].Dst.String())
+			}
+		}
+	}
+	return nil
+}
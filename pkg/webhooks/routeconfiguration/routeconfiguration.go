@@ -95,6 +95,9 @@ func (w *webhook) Handle(ctx context.Context, req admission.Request) admission.R
 		if err := checkUniqueRoutes(routeconfiguration.Spec.Table.Rules[i].Routes); err != nil {
 			return admission.Denied(err.Error())
 		}
+		if err := checkNextHops(routeconfiguration.Spec.Table.Rules[i].Routes); err != nil {
+			return admission.Denied(err.Error())
+		}
 	}
 
 	return admission.Allowed("")